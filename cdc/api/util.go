@@ -38,6 +38,9 @@ var httpBadRequestError = []*errors.Error{
 	cerror.ErrChangeFeedNotExists, cerror.ErrTargetTsBeforeStartTs, cerror.ErrTableIneligible,
 	cerror.ErrFilterRuleInvalid, cerror.ErrChangefeedUpdateRefused, cerror.ErrMySQLConnectionError,
 	cerror.ErrMySQLInvalidConfig, cerror.ErrCaptureNotExist, cerror.ErrSchedulerRequestFailed,
+	cerror.ErrNamespaceNotExists, cerror.ErrNamespaceAlreadyExists, cerror.ErrNamespaceNotEmpty,
+	cerror.ErrNamespaceQuotaExceeded, cerror.ErrChangefeedRevisionNotFound,
+	cerror.ErrSnapshotTableNameNotFound, cerror.ErrUpstreamAlreadyExists, cerror.ErrUpstreamInUse,
 }
 
 const (
@@ -150,6 +153,27 @@ func HandleOwnerScheduleTable(
 	}
 }
 
+// HandleOwnerResyncTable re-syncs the physical tables of a table
+func HandleOwnerResyncTable(
+	ctx context.Context, capture capture.Capture,
+	changefeedID model.ChangeFeedID, tableIDs []model.TableID,
+	tableName model.TableName, policy model.ResyncPolicy,
+) error {
+	// Use buffered channel to prevent blocking owner.
+	done := make(chan error, 1)
+	o, err := capture.GetOwner()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	o.ResyncTable(changefeedID, tableIDs, tableName, policy, done)
+	select {
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	case err := <-done:
+		return errors.Trace(err)
+	}
+}
+
 // ForwardToOwner forwards an request to the owner
 func ForwardToOwner(c *gin.Context, p capture.Capture) {
 	ctx := c.Request.Context()
@@ -43,6 +43,8 @@ const (
 	apiOpVarChangefeedID = "changefeed_id"
 	// apiOpVarCaptureID is the key of capture ID in HTTP API
 	apiOpVarCaptureID = "capture_id"
+	// apiOpVarNamespace is the key of changefeed namespace in HTTP API
+	apiOpVarNamespace = "namespace"
 )
 
 // OpenAPI provides capture APIs.
@@ -120,12 +122,14 @@ func RegisterOpenAPIRoutes(router *gin.Engine, api OpenAPI) {
 // @Accept json
 // @Produce json
 // @Param state query string false "state"
+// @Param namespace query string false "namespace"
 // @Success 200 {array} model.ChangefeedCommonInfo
 // @Failure 500 {object} model.HTTPError
 // @Router /api/v1/changefeeds [get]
 func (h *OpenAPI) ListChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 	state := c.Query(apiOpVarChangefeedState)
+	namespace := c.Query(apiOpVarNamespace)
 	// get all changefeed status
 	statuses, err := h.statusProvider().GetAllChangeFeedStatuses(ctx)
 	if err != nil {
@@ -145,6 +149,9 @@ func (h *OpenAPI) ListChangefeed(c *gin.Context) {
 	changefeeds := make([]model.ChangeFeedID, 0)
 
 	for cfID := range statuses {
+		if namespace != "" && cfID.Namespace != namespace {
+			continue
+		}
 		changefeeds = append(changefeeds, cfID)
 	}
 	sort.Slice(changefeeds, func(i, j int) bool {
@@ -196,12 +203,13 @@ func (h *OpenAPI) ListChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Success 200 {object} model.ChangefeedDetail
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id} [get]
 func (h *OpenAPI) GetChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -258,6 +266,9 @@ func (h *OpenAPI) GetChangefeed(c *gin.Context) {
 		Engine:         info.Engine,
 		FeedState:      info.State,
 		TaskStatus:     taskStatus,
+
+		LastRewrittenDDL: status.LastRewrittenDDL,
+		LastErrors:       status.LastErrors,
 	}
 
 	c.IndentedJSON(http.StatusOK, changefeedDetail)
@@ -333,13 +344,14 @@ func (h *OpenAPI) CreateChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id}/pause [post]
 func (h *OpenAPI) PauseChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -371,12 +383,13 @@ func (h *OpenAPI) PauseChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed-id path string true "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/changefeeds/{changefeed_id}/resume [post]
 func (h *OpenAPI) ResumeChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -408,6 +421,7 @@ func (h *OpenAPI) ResumeChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id  path  string  true  "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Param target_ts body integer false "changefeed target ts"
 // @Param sink_uri body string false "sink uri"
 // @Param filter_rules body []string false "filter rules"
@@ -419,7 +433,7 @@ func (h *OpenAPI) ResumeChangefeed(c *gin.Context) {
 // @Router /api/v1/changefeeds/{changefeed_id} [put]
 func (h *OpenAPI) UpdateChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
@@ -450,7 +464,7 @@ func (h *OpenAPI) UpdateChangefeed(c *gin.Context) {
 		return
 	}
 
-	err = h.capture.GetEtcdClient().SaveChangeFeedInfo(ctx, newInfo, changefeedID)
+	err = h.capture.GetEtcdClient().UpdateChangefeedInfo(ctx, newInfo, changefeedID, "")
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -466,12 +480,13 @@ func (h *OpenAPI) UpdateChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router	/api/v1/changefeeds/{changefeed_id} [delete]
 func (h *OpenAPI) RemoveChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -525,12 +540,13 @@ func (h *OpenAPI) RemoveChangefeed(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Success 202
 // @Failure 500,400 {object} model.HTTPError
 // @Router /api/v1/changefeeds/{changefeed_id}/tables/rebalance_table [post]
 func (h *OpenAPI) RebalanceTables(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
@@ -558,6 +574,7 @@ func (h *OpenAPI) RebalanceTables(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param changefeed_id path string true "changefeed_id"
+// @Param namespace query string false "namespace"
 // @Param table_id body integer true "table_id"
 // @Param capture_id body string true "capture_id"
 // @Success 202
@@ -565,7 +582,7 @@ func (h *OpenAPI) RebalanceTables(c *gin.Context) {
 // @Router /api/v1/changefeeds/{changefeed_id}/tables/move_table [post]
 func (h *OpenAPI) MoveTable(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -632,7 +649,7 @@ func (h *OpenAPI) ResignOwner(c *gin.Context) {
 func (h *OpenAPI) GetProcessor(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := getChangefeedID(c)
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -858,6 +875,11 @@ func (h *OpenAPI) ServerStatus(c *gin.Context) {
 		IsOwner:  h.capture.IsOwner(),
 		Liveness: h.capture.Liveness(),
 	}
+	// The upstream manager is not ready until the capture is initialized,
+	// report the status without upstreams in this case.
+	if upManager, err := h.capture.GetUpstreamManager(); err == nil {
+		status.Upstreams = upManager.Statuses()
+	}
 	c.IndentedJSON(http.StatusOK, status)
 }
 
@@ -909,3 +931,16 @@ func SetLogLevel(c *gin.Context) {
 	log.Warn("log level changed", zap.String("level", data.Level))
 	c.Status(http.StatusOK)
 }
+
+// getChangefeedID returns the changefeed ID in the path of a request, its
+// namespace is specified by the query, or the default namespace if omitted.
+func getChangefeedID(c *gin.Context) model.ChangeFeedID {
+	namespace := c.Query(apiOpVarNamespace)
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	return model.ChangeFeedID{
+		Namespace: namespace,
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+}
@@ -25,12 +25,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/log"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/capture"
 	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
 	"github.com/pingcap/tiflow/cdc/model"
 	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -92,6 +95,28 @@ func (p *mockStatusProvider) GetCaptures(ctx context.Context) ([]*model.CaptureI
 	return args.Get(0).([]*model.CaptureInfo), args.Error(1)
 }
 
+func (p *mockStatusProvider) GetTableReplicationStates(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableReplicationState, error) {
+	args := p.Called(ctx, changefeedID)
+	return args.Get(0).([]*model.TableReplicationState), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetTableInfo(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string, ts uint64,
+) (*model.TableInfo, error) {
+	args := p.Called(ctx, changefeedID, schemaName, tableName, ts)
+	return args.Get(0).(*model.TableInfo), args.Error(1)
+}
+
+func (p *mockStatusProvider) GetTableDDLJobs(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string,
+	startTs, endTs uint64,
+) ([]*timodel.Job, error) {
+	args := p.Called(ctx, changefeedID, schemaName, tableName, startTs, endTs)
+	return args.Get(0).([]*timodel.Job), args.Error(1)
+}
+
 func newRouter(c capture.Capture, p *mockStatusProvider) *gin.Engine {
 	router := gin.New()
 	RegisterOpenAPIRoutes(router, NewOpenAPI4Test(c, p))
@@ -187,6 +212,19 @@ func TestListChangefeed(t *testing.T) {
 	require.Equal(t, model.StateStopped, resp[1].FeedState)
 	require.Equal(t, uint64(0x2), resp[0].CheckpointTSO)
 	require.Equal(t, uint64(0x2), resp[1].CheckpointTSO)
+
+	// test list changefeed in a specific namespace
+	api = testCase{url: "/api/v1/changefeeds?namespace=ab", method: "GET"}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	resp = []model.ChangefeedCommonInfo{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(resp))
+	require.Equal(t, "ab", resp[0].Namespace)
+	require.Equal(t, "ab", resp[1].Namespace)
 }
 
 func TestGetChangefeed(t *testing.T) {
@@ -810,6 +848,8 @@ func TestServerStatusLiveness(t *testing.T) {
 	cp.EXPECT().IsOwner().DoAndReturn(func() bool {
 		return true
 	}).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(nil, cerror.ErrUpstreamManagerNotReady).AnyTimes()
 
 	// Alive.
 	alive := cp.EXPECT().Liveness().DoAndReturn(func() model.Liveness {
@@ -837,6 +877,31 @@ func TestServerStatusLiveness(t *testing.T) {
 	require.EqualValues(t, model.LivenessCaptureStopping, resp.Liveness)
 }
 
+func TestServerStatusUpstreams(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	cp := mock_capture.NewMockCapture(ctrl)
+	router := newRouter(cp, newStatusProvider())
+	api := testCase{url: "/api/v1/status", method: "GET"}
+
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: captureID}, nil).AnyTimes()
+	cp.EXPECT().IsOwner().Return(false).AnyTimes()
+	cp.EXPECT().Liveness().Return(model.LivenessCaptureAlive).AnyTimes()
+	cp.EXPECT().GetUpstreamManager().
+		Return(upstream.NewManager4Test(&gc.MockPDClient{}), nil).AnyTimes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), api.method, api.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	var resp model.ServerStatus
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Upstreams, 1)
+	require.True(t, resp.Upstreams[0].IsDefault)
+	require.Equal(t, model.UpstreamStateNormal, resp.Upstreams[0].State)
+}
+
 func TestSetLogLevel(t *testing.T) {
 	t.Parallel()
 
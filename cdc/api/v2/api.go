@@ -50,6 +50,36 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	changefeedGroup.PUT("/:changefeed_id", api.updateChangefeed)
 	changefeedGroup.GET("/:changefeed_id/meta_info", api.getChangeFeedMetaInfo)
 	changefeedGroup.POST("/:changefeed_id/resume", api.resumeChangefeed)
+	changefeedGroup.GET("/:changefeed_id/history", api.getChangefeedHistory)
+	changefeedGroup.GET("/:changefeed_id/history/diff", api.diffChangefeedHistory)
+	changefeedGroup.POST("/:changefeed_id/rollback", api.rollbackChangefeed)
+	changefeedGroup.GET("/:changefeed_id/schema", api.getTableSchema)
+	changefeedGroup.GET("/:changefeed_id/schema/ddl", api.getTableDDLEvents)
+	changefeedGroup.GET("/:changefeed_id/snapshot", api.getChangefeedSnapshotProgress)
+	changefeedGroup.POST("/:changefeed_id/resync", api.resyncTable)
+	changefeedGroup.GET("/:changefeed_id/latency", api.getChangefeedLatency)
+	changefeedGroup.GET("/:changefeed_id/diagnose", api.diagnoseChangefeed)
+
+	// processor apis, they are served by the capture itself
+	processorGroup := v2.Group("/processors")
+	processorGroup.GET("/:changefeed_id/latency", api.getProcessorLatency)
+
+	// namespace apis
+	namespaceGroup := v2.Group("/namespaces")
+	namespaceGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	namespaceGroup.GET("", api.listNamespaces)
+	namespaceGroup.POST("", api.createNamespace)
+	namespaceGroup.GET("/:namespace", api.getNamespace)
+	namespaceGroup.PUT("/:namespace", api.updateNamespace)
+	namespaceGroup.DELETE("/:namespace", api.deleteNamespace)
+
+	// upstream apis
+	upstreamGroup := v2.Group("/upstreams")
+	upstreamGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
+	upstreamGroup.GET("", api.listUpstreams)
+	upstreamGroup.POST("", api.createUpstream)
+	upstreamGroup.GET("/:upstream_id", api.getUpstream)
+	upstreamGroup.DELETE("/:upstream_id", api.deleteUpstream)
 
 	verifyTableGroup := v2.Group("/verify_table")
 	verifyTableGroup.Use(middleware.ForwardToOwnerMiddleware(api.capture))
@@ -62,6 +92,9 @@ func RegisterOpenAPIV2Routes(router *gin.Engine, api OpenAPIV2) {
 	unsafeGroup.POST("/resolve_lock", api.ResolveLock)
 	unsafeGroup.DELETE("/service_gc_safepoint", api.DeleteServiceGcSafePoint)
 
+	// server config apis, they are served by the capture itself
+	v2.POST("/config/reload", api.reloadServerConfig)
+
 	// common APIs
 	v2.POST("/tso", api.QueryTso)
 }
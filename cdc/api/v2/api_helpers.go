@@ -15,6 +15,7 @@ package v2
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
+	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -74,6 +76,17 @@ type APIV2Helpers interface {
 		cfInfo *model.ChangeFeedInfo,
 	) error
 
+	// dryRunCreateChangefeed verifies the changefeedConfig without
+	// persisting anything, and yields a compatibility report
+	dryRunCreateChangefeed(
+		ctx context.Context,
+		cfg *ChangefeedConfig,
+		pdClient pd.Client,
+		statusProvider owner.StatusProvider,
+		gcServiceID string,
+		kvStorage tidbkv.Storage,
+	) *DryRunReport
+
 	verifyResumeChangefeedConfig(
 		ctx context.Context,
 		pdClient pd.Client,
@@ -139,7 +152,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	}
 
 	cfStatus, err := statusProvider.GetChangeFeedStatus(ctx,
-		model.DefaultChangeFeedID(cfg.ID))
+		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID})
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
 		return nil, err
 	}
@@ -163,7 +176,7 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 		ctx,
 		pdClient,
 		ensureGCServiceID,
-		model.DefaultChangeFeedID(cfg.ID),
+		model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
 		ensureTTL, cfg.StartTs); err != nil {
 		if !cerror.ErrStartTsBeforeGC.Equal(err) {
 			return nil, cerror.ErrPDEtcdAPIError.Wrap(err)
@@ -257,6 +270,219 @@ func (APIV2HelpersImpl) verifyCreateChangefeedConfig(
 	}, nil
 }
 
+// names of the check items of a changefeed dry run
+const (
+	dryRunCheckChangefeedConfig = "changefeed-config"
+	dryRunCheckOldValue         = "old-value"
+	dryRunCheckDDLAllowlist     = "ddl-allow-list"
+	dryRunCheckTables           = "tables"
+	dryRunCheckGCSafepoint      = "gc-safepoint"
+	dryRunCheckSink             = "sink"
+	dryRunCheckRedoStorage      = "redo-storage"
+)
+
+// dryRunCreateChangefeed checks ChangefeedConfig like verifyCreateChangefeedConfig,
+// but it does not stop at the first error and nothing is persisted,
+// neither the changefeed nor its service GC safepoint.
+func (APIV2HelpersImpl) dryRunCreateChangefeed(
+	ctx context.Context,
+	cfg *ChangefeedConfig,
+	pdClient pd.Client,
+	statusProvider owner.StatusProvider,
+	gcServiceID string,
+	kvStorage tidbkv.Storage,
+) *DryRunReport {
+	if cfg.ID == "" {
+		cfg.ID = uuid.New().String()
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = model.DefaultNamespace
+	}
+	report := &DryRunReport{Namespace: cfg.Namespace, ID: cfg.ID}
+	changefeedID := model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID}
+
+	// verify the changefeed config, the checks below depend on it
+	if cfg.StartTs == 0 {
+		ts, logical, err := pdClient.GetTS(ctx)
+		if err != nil {
+			report.AddCheck(dryRunCheckChangefeedConfig, DryRunCheckFail,
+				"fail to get ts from pd client: "+err.Error())
+			return report
+		}
+		cfg.StartTs = oracle.ComposeTS(ts, logical)
+	}
+	report.StartTs = cfg.StartTs
+	sinkURIParsed, err := url.Parse(cfg.SinkURI)
+	if err != nil {
+		report.AddCheck(dryRunCheckChangefeedConfig, DryRunCheckFail,
+			cerror.WrapError(cerror.ErrSinkURIInvalid, err).Error())
+		return report
+	}
+	replicaCfg := cfg.ReplicaConfig.ToInternalReplicaConfig()
+	protocol := sinkURIParsed.Query().Get(config.ProtocolKey)
+	if protocol == "" {
+		protocol = replicaCfg.Sink.Protocol
+	}
+
+	// verify protocol and old value compatibility
+	oldValueIncompatible := false
+	switch {
+	case !replicaCfg.EnableOldValue && replicaCfg.ForceReplicate:
+		oldValueIncompatible = true
+		report.AddCheck(dryRunCheckOldValue, DryRunCheckFail,
+			"if use force replicate, old value feature must be enabled")
+	case !replicaCfg.EnableOldValue && isForceEnableOldValueProtocol(protocol):
+		oldValueIncompatible = true
+		report.AddCheck(dryRunCheckOldValue, DryRunCheckFail,
+			fmt.Sprintf("%s protocol requires old value to be enabled", protocol))
+	case replicaCfg.EnableOldValue && hasIndexValueDispatchRule(replicaCfg):
+		report.AddCheck(dryRunCheckOldValue, DryRunCheckWarn,
+			"index-value distribution mode does not guarantee row-level "+
+				"orderliness when old value is enabled")
+	default:
+		report.AddCheck(dryRunCheckOldValue, DryRunCheckPass, "")
+	}
+
+	var configErr error
+	switch {
+	case cfg.SinkURI == "":
+		configErr = cerror.ErrSinkURIInvalid.GenWithStackByArgs(
+			"sink_uri is empty, cannot create a changefeed without sink_uri")
+	case model.ValidateChangefeedID(cfg.ID) != nil:
+		configErr = cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid changefeed_id: %s", cfg.ID)
+	case model.ValidateNamespace(cfg.Namespace) != nil:
+		configErr = cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid namespace: %s", cfg.Namespace)
+	case cfg.TargetTs > 0 && cfg.TargetTs <= cfg.StartTs:
+		configErr = cerror.ErrTargetTsBeforeStartTs.GenWithStackByArgs(
+			cfg.TargetTs, cfg.StartTs)
+	default:
+		cfStatus, err := statusProvider.GetChangeFeedStatus(ctx, changefeedID)
+		if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
+			configErr = err
+		} else if cfStatus != nil {
+			configErr = cerror.ErrChangeFeedAlreadyExists.GenWithStackByArgs(cfg.ID)
+		}
+	}
+	if configErr == nil {
+		// the old value incompatibility has been reported above, validate
+		// the other parts of the config as if old value is enabled.
+		validateCfg := replicaCfg
+		if oldValueIncompatible {
+			validateCfg = replicaCfg.Clone()
+			validateCfg.EnableOldValue = true
+		}
+		configErr = validateCfg.ValidateAndAdjust(sinkURIParsed)
+		replicaCfg.Sink = validateCfg.Sink
+	}
+	if configErr != nil {
+		report.AddCheck(dryRunCheckChangefeedConfig, DryRunCheckFail, configErr.Error())
+	} else {
+		report.AddCheck(dryRunCheckChangefeedConfig, DryRunCheckPass, "")
+	}
+
+	// verify DDL types in the allowlist
+	unsupportedDDLs := filter.UnsupportedDDLTypes(replicaCfg.Filter.DDLAllowlist)
+	for _, ddlType := range unsupportedDDLs {
+		report.UnsupportedDDLs = append(report.UnsupportedDDLs, ddlType.String())
+	}
+	if len(unsupportedDDLs) != 0 {
+		report.AddCheck(dryRunCheckDDLAllowlist, DryRunCheckWarn,
+			"replicating DDLs in unsupported_ddls may break the downstream")
+	} else {
+		report.AddCheck(dryRunCheckDDLAllowlist, DryRunCheckPass, "")
+	}
+
+	// verify tables in the schema snapshot at start ts
+	report.addTablesCheck(replicaCfg, kvStorage, cfg.StartTs,
+		cfg.ReplicaConfig.IgnoreIneligibleTable)
+
+	// verify GC safepoint
+	if err := gc.CheckChangefeedStartTsSafety(
+		ctx, pdClient, gcServiceID, changefeedID, cfg.StartTs); err != nil {
+		report.AddCheck(dryRunCheckGCSafepoint, DryRunCheckFail, err.Error())
+	} else {
+		report.AddCheck(dryRunCheckGCSafepoint, DryRunCheckPass, "")
+	}
+
+	// verify sink and redo storage
+	if configErr != nil {
+		report.AddCheck(dryRunCheckSink, DryRunCheckSkip, "changefeed config is invalid")
+	} else if err := sink.CheckReachable(ctx, cfg.SinkURI, replicaCfg); err != nil {
+		report.AddCheck(dryRunCheckSink, DryRunCheckFail, err.Error())
+	} else {
+		report.AddCheck(dryRunCheckSink, DryRunCheckPass, "")
+	}
+	if !redo.IsConsistentEnabled(replicaCfg.Consistent.Level) {
+		report.AddCheck(dryRunCheckRedoStorage, DryRunCheckSkip, "redo log is disabled")
+	} else if err := redo.ValidateStorage(ctx, replicaCfg.Consistent); err != nil {
+		report.AddCheck(dryRunCheckRedoStorage, DryRunCheckFail, err.Error())
+	} else {
+		report.AddCheck(dryRunCheckRedoStorage, DryRunCheckPass, "")
+	}
+	return report
+}
+
+// addTablesCheck lists the eligible and ineligible tables in the report.
+func (r *DryRunReport) addTablesCheck(
+	replicaCfg *config.ReplicaConfig, kvStorage tidbkv.Storage,
+	startTs uint64, ignoreIneligibleTable bool,
+) {
+	f, err := filter.NewFilter(replicaCfg, "")
+	if err != nil {
+		r.AddCheck(dryRunCheckTables, DryRunCheckFail, errors.Cause(err).Error())
+		return
+	}
+	tableInfos, ineligibleTables, eligibleTables, err := entry.VerifyTables(f, kvStorage, startTs)
+	if err != nil {
+		r.AddCheck(dryRunCheckTables, DryRunCheckFail, errors.Cause(err).Error())
+		return
+	}
+	r.EligibleTables = toAPITableNames(eligibleTables)
+	r.IneligibleTables = toAPITableNames(ineligibleTables)
+	if err := f.Verify(tableInfos); err != nil {
+		r.AddCheck(dryRunCheckTables, DryRunCheckFail, errors.Cause(err).Error())
+		return
+	}
+	switch {
+	case len(ineligibleTables) == 0:
+		r.AddCheck(dryRunCheckTables, DryRunCheckPass, "")
+	case replicaCfg.ForceReplicate:
+		r.AddCheck(dryRunCheckTables, DryRunCheckWarn,
+			"ineligible tables are forced to replicate")
+	case ignoreIneligibleTable:
+		r.AddCheck(dryRunCheckTables, DryRunCheckWarn,
+			"ineligible tables are ignored")
+	default:
+		r.AddCheck(dryRunCheckTables, DryRunCheckFail,
+			cerror.ErrTableIneligible.GenWithStackByArgs(ineligibleTables).Error())
+	}
+}
+
+func isForceEnableOldValueProtocol(protocol string) bool {
+	for _, fp := range config.ForceEnableOldValueProtocols {
+		if protocol == fp {
+			return true
+		}
+	}
+	return false
+}
+
+func hasIndexValueDispatchRule(replicaCfg *config.ReplicaConfig) bool {
+	for _, rule := range replicaCfg.Sink.DispatchRules {
+		switch strings.ToLower(rule.PartitionRule) {
+		case "rowid", "index-value":
+			return true
+		}
+		switch strings.ToLower(rule.DispatcherRule) {
+		case "rowid", "index-value":
+			return true
+		}
+	}
+	return false
+}
+
 // verifyUpstream verifies the upstream config before updating a changefeed
 func (h APIV2HelpersImpl) verifyUpstream(ctx context.Context,
 	changefeedConfig *ChangefeedConfig,
@@ -392,7 +618,7 @@ func (APIV2HelpersImpl) verifyResumeChangefeedConfig(ctx context.Context,
 		ctx,
 		pdClient,
 		gcServiceID,
-		model.DefaultChangeFeedID(changefeedID.ID),
+		changefeedID,
 		gcTTL, checkpointTs)
 	if err != nil {
 		if !cerror.ErrStartTsBeforeGC.Equal(err) {
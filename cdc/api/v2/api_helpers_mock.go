@@ -55,6 +55,20 @@ func (mr *MockAPIV2HelpersMockRecorder) createTiStore(pdAddrs, credential interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createTiStore", reflect.TypeOf((*MockAPIV2Helpers)(nil).createTiStore), pdAddrs, credential)
 }
 
+// dryRunCreateChangefeed mocks base method.
+func (m *MockAPIV2Helpers) dryRunCreateChangefeed(ctx context.Context, cfg *ChangefeedConfig, pdClient client.Client, statusProvider owner.StatusProvider, gcServiceID string, kvStorage kv.Storage) *DryRunReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "dryRunCreateChangefeed", ctx, cfg, pdClient, statusProvider, gcServiceID, kvStorage)
+	ret0, _ := ret[0].(*DryRunReport)
+	return ret0
+}
+
+// dryRunCreateChangefeed indicates an expected call of dryRunCreateChangefeed.
+func (mr *MockAPIV2HelpersMockRecorder) dryRunCreateChangefeed(ctx, cfg, pdClient, statusProvider, gcServiceID, kvStorage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "dryRunCreateChangefeed", reflect.TypeOf((*MockAPIV2Helpers)(nil).dryRunCreateChangefeed), ctx, cfg, pdClient, statusProvider, gcServiceID, kvStorage)
+}
+
 // getPDClient mocks base method.
 func (m *MockAPIV2Helpers) getPDClient(ctx context.Context, pdAddrs []string, credential *security.Credential) (client.Client, error) {
 	m.ctrl.T.Helper()
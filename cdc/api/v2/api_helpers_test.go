@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
 )
 
 func TestVerifyCreateChangefeedConfig(t *testing.T) {
@@ -145,3 +146,77 @@ func TestVerifyUpdateChangefeedConfig(t *testing.T) {
 	newCfInfo, newUpInfo, err = h.verifyUpdateChangefeedConfig(ctx, cfg, oldInfo, oldUpInfo, storage, 0)
 	require.NotNil(t, err)
 }
+
+func TestDryRunCreateChangefeed(t *testing.T) {
+	ctx := context.Background()
+	pdClient := &mockPDClient{}
+	helper := entry.NewSchemaTestHelper(t)
+	helper.Tk().MustExec("use test;")
+	helper.Tk().MustExec("create table t1(id int primary key);")
+	helper.Tk().MustExec("create table t2(id int);")
+	storage := helper.Storage()
+	ver, err := storage.CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	provider := &mockStatusProvider{}
+	h := &APIV2HelpersImpl{}
+
+	getCheck := func(report *DryRunReport, name string) DryRunCheck {
+		for _, check := range report.Checks {
+			if check.Name == name {
+				return check
+			}
+		}
+		require.FailNow(t, "check not found", name)
+		return DryRunCheck{}
+	}
+
+	cfg := &ChangefeedConfig{
+		ID:            "dry-run",
+		StartTs:       ver.Ver,
+		SinkURI:       "blackhole://",
+		ReplicaConfig: GetDefaultReplicaConfig(),
+	}
+	// ineligible table t2 fails the check
+	report := h.dryRunCreateChangefeed(ctx, cfg, pdClient, provider, "en", storage)
+	require.False(t, report.Passed)
+	require.Equal(t, model.DefaultNamespace, report.Namespace)
+	require.Equal(t, ver.Ver, report.StartTs)
+	require.Equal(t, DryRunCheckFail, getCheck(report, dryRunCheckTables).Status)
+	require.Equal(t, []TableName{{Schema: "test", Table: "t2", TableID: report.IneligibleTables[0].TableID}},
+		report.IneligibleTables)
+	require.Equal(t, DryRunCheckPass, getCheck(report, dryRunCheckSink).Status)
+	require.Equal(t, DryRunCheckPass, getCheck(report, dryRunCheckGCSafepoint).Status)
+	require.Equal(t, DryRunCheckSkip, getCheck(report, dryRunCheckRedoStorage).Status)
+
+	// ignore ineligible tables, only warnings are left
+	cfg.ReplicaConfig.IgnoreIneligibleTable = true
+	report = h.dryRunCreateChangefeed(ctx, cfg, pdClient, provider, "en", storage)
+	require.True(t, report.Passed)
+	require.Equal(t, DryRunCheckWarn, getCheck(report, dryRunCheckTables).Status)
+	require.Equal(t, DryRunCheckPass, getCheck(report, dryRunCheckChangefeedConfig).Status)
+
+	// disable old value but force replicate, the other checks still run
+	cfg.ReplicaConfig.ForceReplicate = true
+	cfg.ReplicaConfig.EnableOldValue = false
+	report = h.dryRunCreateChangefeed(ctx, cfg, pdClient, provider, "en", storage)
+	require.False(t, report.Passed)
+	require.Equal(t, DryRunCheckFail, getCheck(report, dryRunCheckOldValue).Status)
+	require.Equal(t, DryRunCheckPass, getCheck(report, dryRunCheckChangefeedConfig).Status)
+	require.Equal(t, DryRunCheckPass, getCheck(report, dryRunCheckSink).Status)
+	cfg.ReplicaConfig.ForceReplicate = false
+	cfg.ReplicaConfig.EnableOldValue = true
+
+	// changefeed already exists, sink check is skipped
+	provider.changefeedStatus = &model.ChangeFeedStatus{}
+	report = h.dryRunCreateChangefeed(ctx, cfg, pdClient, provider, "en", storage)
+	require.False(t, report.Passed)
+	require.Equal(t, DryRunCheckFail, getCheck(report, dryRunCheckChangefeedConfig).Status)
+	require.Equal(t, DryRunCheckSkip, getCheck(report, dryRunCheckSink).Status)
+	provider.changefeedStatus = nil
+
+	// invalid sink uri
+	cfg.SinkURI = string([]byte{0x7f, ' '})
+	report = h.dryRunCreateChangefeed(ctx, cfg, pdClient, provider, "en", storage)
+	require.False(t, report.Passed)
+	require.Len(t, report.Checks, 1)
+}
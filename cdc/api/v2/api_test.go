@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/gin-gonic/gin"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/owner"
 	pd "github.com/tikv/pd/client"
@@ -64,7 +65,13 @@ type mockStatusProvider struct {
 	owner.StatusProvider
 	changefeedStatus *model.ChangeFeedStatus
 	changefeedInfo   *model.ChangeFeedInfo
+	changefeedInfos  map[model.ChangeFeedID]*model.ChangeFeedInfo
+	captures         []*model.CaptureInfo
+	tableStates      []*model.TableReplicationState
 	err              error
+
+	getTableInfo    func(schemaName, tableName string, ts uint64) (*model.TableInfo, error)
+	getTableDDLJobs func(schemaName, tableName string, startTs, endTs uint64) ([]*timodel.Job, error)
 }
 
 // GetChangeFeedStatus returns a changefeeds' runtime status.
@@ -80,3 +87,39 @@ func (m *mockStatusProvider) GetChangeFeedInfo(ctx context.Context,
 ) (*model.ChangeFeedInfo, error) {
 	return m.changefeedInfo, m.err
 }
+
+// GetAllChangeFeedInfo returns mock changefeeds' info.
+func (m *mockStatusProvider) GetAllChangeFeedInfo(ctx context.Context) (
+	map[model.ChangeFeedID]*model.ChangeFeedInfo, error,
+) {
+	return m.changefeedInfos, m.err
+}
+
+// GetCaptures returns mock captures' info.
+func (m *mockStatusProvider) GetCaptures(ctx context.Context) (
+	[]*model.CaptureInfo, error,
+) {
+	return m.captures, m.err
+}
+
+// GetTableReplicationStates returns mock table replication states.
+func (m *mockStatusProvider) GetTableReplicationStates(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableReplicationState, error) {
+	return m.tableStates, m.err
+}
+
+// GetTableInfo returns a mock table info.
+func (m *mockStatusProvider) GetTableInfo(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string, ts uint64,
+) (*model.TableInfo, error) {
+	return m.getTableInfo(schemaName, tableName, ts)
+}
+
+// GetTableDDLJobs returns mock DDL jobs of a table.
+func (m *mockStatusProvider) GetTableDDLJobs(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string,
+	startTs, endTs uint64,
+) ([]*timodel.Job, error) {
+	return m.getTableDDLJobs(schemaName, tableName, startTs, endTs)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/api"
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
@@ -33,10 +34,17 @@ import (
 	"go.uber.org/zap"
 )
 
-const apiOpVarChangefeedID = "changefeed_id"
+const (
+	apiOpVarChangefeedID = "changefeed_id"
+	apiOpVarNamespace    = "namespace"
+	// apiOpVarDryRun is the key of the dry run switch of creating a changefeed
+	apiOpVarDryRun = "dry_run"
+)
 
 // createChangefeed handles create changefeed request,
-// it returns the changefeed's changefeedInfo that it just created
+// it returns the changefeed's changefeedInfo that it just created.
+// If dry_run=true is specified, nothing is created and
+// a compatibility report is returned instead.
 func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 	cfg := &ChangefeedConfig{ReplicaConfig: GetDefaultReplicaConfig()}
@@ -45,7 +53,19 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
 		return
 	}
-	if len(cfg.PDAddrs) == 0 {
+	if len(cfg.PDAddrs) == 0 && cfg.UpstreamID != 0 {
+		namespace := cfg.Namespace
+		if namespace == "" {
+			namespace = model.DefaultNamespace
+		}
+		upstreamInfo, err := h.capture.GetEtcdClient().GetUpstreamInfo(ctx,
+			cfg.UpstreamID, namespace)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		cfg.PDConfig = ToAPIUpstreamConfig(upstreamInfo).PDConfig
+	} else if len(cfg.PDAddrs) == 0 {
 		up, err := getCaptureDefaultUpstream(h.capture)
 		if err != nil {
 			_ = c.Error(err)
@@ -73,6 +93,17 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	// We should not close kvStorage since all kvStorage in cdc is the same one.
 	// defer kvStorage.Close()
 	// TODO: We should get a kvStorage from upstream instead of creating a new one
+	if c.Query(apiOpVarDryRun) == "true" {
+		report := h.helpers.dryRunCreateChangefeed(
+			ctx,
+			cfg,
+			pdClient,
+			h.capture.StatusProvider(),
+			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceDryRun),
+			kvStorage)
+		c.JSON(http.StatusOK, report)
+		return
+	}
 	info, err := h.helpers.verifyCreateChangefeedConfig(
 		ctx,
 		cfg,
@@ -93,13 +124,26 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 			ctx,
 			pdClient,
 			h.capture.GetEtcdClient().GetEnsureGCServiceID(gc.EnsureGCServiceCreating),
-			model.DefaultChangeFeedID(cfg.ID),
+			model.ChangeFeedID{Namespace: cfg.Namespace, ID: cfg.ID},
 		)
 		if err != nil {
 			_ = c.Error(err)
 			return
 		}
 	}()
+	if cfg.UpstreamID != 0 && cfg.UpstreamID != info.UpstreamID {
+		needRemoveGCSafePoint = true
+		_ = c.Error(cerror.ErrUpstreamMissMatch.GenWithStackByArgs(
+			cfg.UpstreamID, info.UpstreamID))
+		return
+	}
+	changefeedID := model.ChangeFeedID{Namespace: info.Namespace, ID: info.ID}
+	if err := checkNamespaceQuota(ctx, h.capture, changefeedID,
+		info.Config); err != nil {
+		needRemoveGCSafePoint = true
+		_ = c.Error(err)
+		return
+	}
 	upstreamInfo := &model.UpstreamInfo{
 		ID:            info.UpstreamID,
 		PDEndpoints:   strings.Join(cfg.PDAddrs, ","),
@@ -118,7 +162,7 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	err = h.capture.GetEtcdClient().CreateChangefeedInfo(ctx,
 		upstreamInfo,
 		info,
-		model.DefaultChangeFeedID(info.ID))
+		changefeedID)
 	if err != nil {
 		needRemoveGCSafePoint = true
 		_ = c.Error(err)
@@ -126,6 +170,7 @@ func (h *OpenAPIV2) createChangefeed(c *gin.Context) {
 	}
 
 	log.Info("Create changefeed successfully!",
+		zap.String("namespace", info.Namespace),
 		zap.String("id", info.ID),
 		zap.String("changefeed", infoStr))
 	c.JSON(http.StatusCreated, toAPIModel(info, true))
@@ -160,21 +205,9 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 		_ = c.Error(err)
 		return
 	}
-	toAPIModelFunc := func(tbls []model.TableName) []TableName {
-		var apiModles []TableName
-		for _, tbl := range tbls {
-			apiModles = append(apiModles, TableName{
-				Schema:      tbl.Schema,
-				Table:       tbl.Table,
-				TableID:     tbl.TableID,
-				IsPartition: tbl.IsPartition,
-			})
-		}
-		return apiModles
-	}
 	tables := &Tables{
-		IneligibleTables: toAPIModelFunc(ineligibleTables),
-		EligibleTables:   toAPIModelFunc(eligibleTables),
+		IneligibleTables: toAPITableNames(ineligibleTables),
+		EligibleTables:   toAPITableNames(eligibleTables),
 	}
 	c.JSON(http.StatusOK, tables)
 }
@@ -184,10 +217,15 @@ func (h *OpenAPIV2) verifyTable(c *gin.Context) {
 // Can only update a changefeed's: TargetTs, SinkURI,
 // ReplicaConfig, PDAddrs, CAPath, CertPath, KeyPath,
 // SyncPointEnabled, SyncPointInterval
+// The updated changefeedInfo is recorded in the changefeed's history
+// with the reason specified by the query parameter reason.
 func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -265,8 +303,8 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 		zap.String("changefeedInfo", newCfInfo.String()),
 		zap.Any("upstreamInfo", newUpInfo))
 
-	err = h.capture.GetEtcdClient().
-		UpdateChangefeedAndUpstream(ctx, newUpInfo, newCfInfo, changefeedID)
+	err = h.capture.GetEtcdClient().UpdateChangefeedAndUpstream(
+		ctx, newUpInfo, newCfInfo, changefeedID, c.Query(apiOpVarReason))
 	if err != nil {
 		_ = c.Error(errors.Trace(err))
 		return
@@ -278,7 +316,10 @@ func (h *OpenAPIV2) updateChangefeed(c *gin.Context) {
 func (h *OpenAPIV2) getChangeFeedMetaInfo(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
 	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
 			changefeedID.ID))
@@ -295,7 +336,10 @@ func (h *OpenAPIV2) getChangeFeedMetaInfo(c *gin.Context) {
 // resumeChangefeed handles update changefeed request.
 func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 	ctx := c.Request.Context()
-	changefeedID := model.DefaultChangeFeedID(c.Param(apiOpVarChangefeedID))
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
 	err := model.ValidateChangefeedID(changefeedID.ID)
 	if err != nil {
 		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
@@ -303,11 +347,16 @@ func (h *OpenAPIV2) resumeChangefeed(c *gin.Context) {
 		return
 	}
 
-	_, err = h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
+	if err := checkNamespaceQuota(ctx, h.capture, changefeedID,
+		cfInfo.Config); err != nil {
+		_ = c.Error(err)
+		return
+	}
 
 	cfg := new(ResumeChangefeedConfig)
 	if err := c.BindJSON(&cfg); err != nil {
@@ -435,3 +484,65 @@ func getUpstreamPDConfig(up *upstream.Upstream) PDConfig {
 		CertPath: up.SecurityConfig.CertPath,
 	}
 }
+
+func toAPITableNames(tbls []model.TableName) []TableName {
+	var apiModles []TableName
+	for _, tbl := range tbls {
+		apiModles = append(apiModles, TableName{
+			Schema:      tbl.Schema,
+			Table:       tbl.Table,
+			TableID:     tbl.TableID,
+			IsPartition: tbl.IsPartition,
+		})
+	}
+	return apiModles
+}
+
+// getNamespaceValueWithDefault returns the namespace in the query of a request,
+// or the default namespace if it is not specified.
+func getNamespaceValueWithDefault(c *gin.Context) string {
+	namespace := c.Query(apiOpVarNamespace)
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	return namespace
+}
+
+// checkNamespaceQuota returns an error if the namespace of the changefeed does
+// not exist, or it can not hold one more running changefeed.
+func checkNamespaceQuota(
+	ctx context.Context,
+	cp capture.Capture,
+	changefeedID model.ChangeFeedID,
+	replicaConfig *config.ReplicaConfig,
+) error {
+	nsInfo, err := cp.GetEtcdClient().GetNamespaceInfo(ctx, changefeedID.Namespace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if nsInfo.ChangefeedQuota == 0 && nsInfo.MemoryQuota == 0 {
+		return nil
+	}
+	infos, err := cp.StatusProvider().GetAllChangeFeedInfo(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	count, memoryQuota := 0, uint64(0)
+	for id, info := range infos {
+		if id.Namespace != changefeedID.Namespace || id == changefeedID {
+			continue
+		}
+		if info.State != model.StateNormal && info.State != model.StateError {
+			continue
+		}
+		count++
+		if info.Config != nil {
+			memoryQuota += info.Config.MemoryQuota
+		}
+	}
+	var newMemoryQuota uint64
+	if replicaConfig != nil {
+		newMemoryQuota = replicaConfig.MemoryQuota
+	}
+	return nsInfo.CheckQuota(count, memoryQuota, newMemoryQuota)
+}
@@ -0,0 +1,399 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/tracing"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/pdutil"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+const (
+	// diagnoseLagThreshold is the checkpoint lag over which a table is
+	// considered stuck.
+	diagnoseLagThreshold = time.Minute
+	// captureDebugInfoTimeout is the timeout of collecting the debug info
+	// from a capture.
+	captureDebugInfoTimeout = 10 * time.Second
+)
+
+// sinkErrorKeywords are the keywords of the error codes raised by sinks.
+var sinkErrorKeywords = []string{
+	"Sink", "MySQL", "Kafka", "Pulsar", "MQ", "S3", "Storage",
+}
+
+// gcErrorCodes are the error codes raised if the data required by
+// the changefeed is garbage collected.
+var gcErrorCodes = []string{
+	string(cerror.ErrSnapshotLostByGC.RFCCode()),
+	string(cerror.ErrGCTTLExceeded.RFCCode()),
+	string(cerror.ErrStartTsBeforeGC.RFCCode()),
+}
+
+// diagnoseChangefeed collects the health information of a changefeed from
+// the cluster and finds out the likely cause if the changefeed is stuck.
+// Information which fails to be collected is reported in CollectErrors
+// instead of failing the request, since the cluster may be unhealthy.
+func (h *OpenAPIV2) diagnoseChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	statusProvider := h.capture.StatusProvider()
+	info, err := statusProvider.GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	d := &ChangefeedDiagnosis{
+		Namespace:           changefeedID.Namespace,
+		ID:                  changefeedID.ID,
+		Info:                info,
+		Tables:              make([]*model.TableReplicationState, 0),
+		Latency:             make([]TableLatency, 0),
+		ServiceGCSafePoints: make([]ServiceSafePoint, 0),
+		Captures:            make([]CaptureDiagnosis, 0),
+		Etcd:                make([]EtcdData, 0),
+		Findings:            make([]DiagnosisFinding, 0),
+		CollectErrors:       make([]string, 0),
+	}
+	collectErr := func(what string, err error) {
+		d.CollectErrors = append(d.CollectErrors, fmt.Sprintf("%s: %s", what, err))
+	}
+
+	if d.Status, err = statusProvider.GetChangeFeedStatus(ctx, changefeedID); err != nil {
+		collectErr("changefeed status", err)
+	}
+	if tables, err := statusProvider.
+		GetTableReplicationStates(ctx, changefeedID); err != nil {
+		collectErr("table replication states", err)
+	} else {
+		d.Tables = tables
+	}
+	h.collectUpstreamDiagnosis(ctx, d, collectErr)
+	h.collectEtcdDiagnosis(ctx, d, collectErr)
+	h.collectCaptureDiagnosis(ctx, d, collectErr)
+
+	d.Findings = diagnose(d)
+	d.MaskSinkURI()
+	c.JSON(http.StatusOK, d)
+}
+
+// collectUpstreamDiagnosis collects the current ts and the GC safe points
+// from the upstream of the changefeed.
+func (h *OpenAPIV2) collectUpstreamDiagnosis(
+	ctx context.Context, d *ChangefeedDiagnosis, collectErr func(string, error),
+) {
+	upManager, err := h.capture.GetUpstreamManager()
+	if err != nil {
+		collectErr("upstream", err)
+		return
+	}
+	up, ok := upManager.Get(d.Info.UpstreamID)
+	if !ok {
+		collectErr("upstream",
+			cerror.ErrUpstreamNotFound.GenWithStackByArgs(d.Info.UpstreamID))
+		return
+	}
+	now, err := up.PDClock.CurrentTime()
+	if err != nil {
+		collectErr("current ts", err)
+	} else {
+		d.CurrentTs = oracle.GoTimeToTS(now)
+	}
+	safePoints, err := pdutil.ListGcServiceSafePoint(ctx, up.PDClient, up.SecurityConfig)
+	if err != nil {
+		collectErr("gc safe points", err)
+		return
+	}
+	d.GCSafePoint = safePoints.GCSafePoint
+	for _, sp := range safePoints.ServiceGCSafepoints {
+		d.ServiceGCSafePoints = append(d.ServiceGCSafePoints, ServiceSafePoint{
+			ServiceID: sp.ServiceID,
+			ExpiredAt: sp.ExpiredAt,
+			SafePoint: sp.SafePoint,
+		})
+	}
+}
+
+// collectEtcdDiagnosis collects the etcd keys related to the changefeed.
+func (h *OpenAPIV2) collectEtcdDiagnosis(
+	ctx context.Context, d *ChangefeedDiagnosis, collectErr func(string, error),
+) {
+	etcdClient := h.capture.GetEtcdClient()
+	d.GCServiceID = etcdClient.GetGCServiceID()
+	kvs, err := etcdClient.GetAllCDCInfo(ctx)
+	if err != nil {
+		collectErr("etcd", err)
+		return
+	}
+	nsPrefix := "/" + d.Namespace + "/"
+	for _, kv := range kvs {
+		key := string(kv.Key)
+		if !strings.Contains(key, "/capture/") && !strings.Contains(key, "/owner/") &&
+			!(strings.Contains(key, nsPrefix) && (strings.HasSuffix(key, "/"+d.ID) ||
+				strings.Contains(key, "/"+d.ID+"/"))) {
+			continue
+		}
+		d.Etcd = append(d.Etcd, EtcdData{Key: key, Value: string(kv.Value)})
+	}
+}
+
+// maskedEtcdValue replaces an etcd value which contains a sink URI
+// but can not be decoded, so that the password in it is not leaked.
+const maskedEtcdValue = "<masked: the value contains a sink uri>"
+
+// MaskSinkURI masks the passwords of the sink URIs in the diagnosis,
+// including the ones in the changefeed info and its history dumped from etcd.
+func (d *ChangefeedDiagnosis) MaskSinkURI() {
+	if d.Info != nil {
+		info := *d.Info
+		info.SinkURI = maskSinkURIValue(info.SinkURI).(string)
+		d.Info = &info
+	}
+	for i := range d.Etcd {
+		d.Etcd[i].Value = maskEtcdValue(d.Etcd[i].Value)
+	}
+}
+
+// maskEtcdValue masks the sink URIs at any depth of a JSON etcd value.
+func maskEtcdValue(value string) string {
+	if !strings.Contains(value, `"sink-uri"`) {
+		return value
+	}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	// Keep the precision of timestamps.
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return maskedEtcdValue
+	}
+	var mask func(v interface{})
+	mask = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if key == "sink-uri" {
+					v[key] = maskSinkURIValue(value)
+					continue
+				}
+				mask(value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				mask(value)
+			}
+		}
+	}
+	mask(v)
+	masked, err := json.Marshal(v)
+	if err != nil {
+		return maskedEtcdValue
+	}
+	return string(masked)
+}
+
+// collectCaptureDiagnosis collects the debug info and the table latency
+// from all captures.
+func (h *OpenAPIV2) collectCaptureDiagnosis(
+	ctx context.Context, d *ChangefeedDiagnosis, collectErr func(string, error),
+) {
+	self, err := h.capture.Info()
+	if err != nil {
+		collectErr("capture", err)
+		return
+	}
+	captures, err := h.capture.StatusProvider().GetCaptures(ctx)
+	if err != nil {
+		collectErr("captures", err)
+		return
+	}
+	sort.Slice(captures, func(i, j int) bool {
+		return captures[i].ID < captures[j].ID
+	})
+	changefeedID := model.ChangeFeedID{Namespace: d.Namespace, ID: d.ID}
+	for _, capture := range captures {
+		cd := CaptureDiagnosis{
+			ID:            capture.ID,
+			AdvertiseAddr: capture.AdvertiseAddr,
+			IsOwner:       capture.ID == self.ID,
+		}
+		cctx, cancel := context.WithTimeout(ctx, captureDebugInfoTimeout)
+		if cd.IsOwner {
+			var buf bytes.Buffer
+			h.capture.WriteDebugInfo(cctx, &buf)
+			cd.DebugInfo = buf.String()
+			d.Latency = append(d.Latency,
+				toTableLatencies(self.ID, tracing.GetChangefeedLatency(changefeedID))...)
+		} else {
+			debugInfo, err := requestCapture(cctx, capture, "/debug/info", nil)
+			if err != nil {
+				cd.Error = err.Error()
+			} else {
+				cd.DebugInfo = string(debugInfo)
+				latency, err := getCaptureLatency(cctx, capture, changefeedID)
+				if err != nil {
+					collectErr("latency", err)
+				}
+				d.Latency = append(d.Latency, latency...)
+			}
+		}
+		cancel()
+		d.Captures = append(d.Captures, cd)
+	}
+	sort.Slice(d.Latency, func(i, j int) bool {
+		return d.Latency[i].TableID < d.Latency[j].TableID
+	})
+}
+
+// diagnose runs heuristics on the collected information and returns the
+// problems found, the most severe ones are listed first.
+func diagnose(d *ChangefeedDiagnosis) []DiagnosisFinding {
+	findings := make([]DiagnosisFinding, 0)
+	add := func(severity, cause string, tableID model.TableID, format string, args ...interface{}) {
+		findings = append(findings, DiagnosisFinding{
+			Severity: severity,
+			Cause:    cause,
+			TableID:  tableID,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// errors reported by the changefeed
+	if d.Info != nil && d.Info.Error != nil {
+		cause := DiagnosisCauseChangefeedError
+		for _, code := range gcErrorCodes {
+			if d.Info.Error.Code == code {
+				cause = DiagnosisCauseGC
+			}
+		}
+		for _, keyword := range sinkErrorKeywords {
+			if cause == DiagnosisCauseChangefeedError &&
+				strings.Contains(d.Info.Error.Code, keyword) {
+				cause = DiagnosisCauseSinkError
+			}
+		}
+		severity := DiagnosisSeverityWarning
+		if d.Info.State == model.StateError || d.Info.State == model.StateFailed {
+			severity = DiagnosisSeverityCritical
+		}
+		add(severity, cause, 0, "changefeed is %s, error reported by %s: [%s] %s",
+			d.Info.State, d.Info.Error.Addr, d.Info.Error.Code, d.Info.Error.Message)
+	}
+
+	// gc safe points
+	if d.Status != nil && d.GCSafePoint != 0 {
+		if d.Status.CheckpointTs < d.GCSafePoint {
+			add(DiagnosisSeverityCritical, DiagnosisCauseGC, 0,
+				"checkpoint ts %d is less than the GC safe point %d, "+
+					"the data required by the changefeed has been garbage collected",
+				d.Status.CheckpointTs, d.GCSafePoint)
+		}
+		for _, sp := range d.ServiceGCSafePoints {
+			if sp.ServiceID == d.GCServiceID && sp.SafePoint > d.Status.CheckpointTs {
+				add(DiagnosisSeverityWarning, DiagnosisCauseGC, 0,
+					"the service GC safe point %d of TiCDC is greater than "+
+						"checkpoint ts %d, the changefeed may not be able to resume",
+					sp.SafePoint, d.Status.CheckpointTs)
+			}
+		}
+	}
+
+	// unreachable captures
+	for _, capture := range d.Captures {
+		if capture.Error != "" {
+			add(DiagnosisSeverityWarning, DiagnosisCauseCapture, 0,
+				"capture %s (%s) is unreachable: %s",
+				capture.ID, capture.AdvertiseAddr, capture.Error)
+		}
+	}
+
+	// the slowest table blocks the checkpoint of the changefeed
+	if d.CurrentTs == 0 || len(d.Tables) == 0 {
+		return findings
+	}
+	current := oracle.GetTimeFromTS(d.CurrentTs)
+	lag := func(ts model.Ts) time.Duration {
+		return current.Sub(oracle.GetTimeFromTS(ts))
+	}
+	var stuck *model.TableReplicationState
+	stuckCount := 0
+	for _, table := range d.Tables {
+		if table.CheckpointTs == 0 || lag(table.CheckpointTs) < diagnoseLagThreshold {
+			continue
+		}
+		stuckCount++
+		if stuck == nil || table.CheckpointTs < stuck.CheckpointTs {
+			stuck = table
+		}
+	}
+	if stuck == nil {
+		return findings
+	}
+	name := tableName(d, stuck.TableID)
+	capture := stuck.Primary
+	if capture == "" {
+		capture = stuck.Secondary
+	}
+	checkpointLag := lag(stuck.CheckpointTs).Truncate(time.Second)
+	switch {
+	case stuck.State != "Replicating":
+		add(DiagnosisSeverityCritical, DiagnosisCauseScheduling, stuck.TableID,
+			"table %s is stuck in %s state on capture %s, checkpoint lag is %s, "+
+				"%d tables are lagging",
+			name, stuck.State, capture, checkpointLag, stuckCount)
+	case lag(stuck.ResolvedTs) >= diagnoseLagThreshold:
+		add(DiagnosisSeverityCritical, DiagnosisCauseRegionLock, stuck.TableID,
+			"resolved ts of table %s on capture %s is stuck, resolved ts lag is %s, "+
+				"it is likely blocked by an unresolved lock or an unavailable region, "+
+				"try resolving the locks by `cdc cli unsafe resolve-lock`, "+
+				"%d tables are lagging",
+			name, capture, lag(stuck.ResolvedTs).Truncate(time.Second), stuckCount)
+	default:
+		add(DiagnosisSeverityCritical, DiagnosisCauseSink, stuck.TableID,
+			"checkpoint ts of table %s on capture %s is stuck while resolved ts "+
+				"advances, checkpoint lag is %s, the sink is likely slow or blocked, "+
+				"%d tables are lagging",
+			name, capture, checkpointLag, stuckCount)
+	}
+	return findings
+}
+
+// tableName returns the name of a table from the latency info if possible.
+func tableName(d *ChangefeedDiagnosis, tableID model.TableID) string {
+	for _, table := range d.Latency {
+		if table.TableID == tableID && table.TableName != "" {
+			return fmt.Sprintf("%s(%d)", table.TableName, tableID)
+		}
+	}
+	return fmt.Sprintf("%d", tableID)
+}
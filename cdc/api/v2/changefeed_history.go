@@ -0,0 +1,266 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	apiOpVarReason       = "reason"
+	apiOpVarFromRevision = "from"
+	apiOpVarToRevision   = "to"
+
+	changefeedSinkURIPath = "sink-uri"
+)
+
+// getChangefeedHistory returns the latest revisions of a changefeed's info.
+func (h *OpenAPIV2) getChangefeedHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	history, _, err := h.getChangefeedHistoryWithInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := &ChangefeedHistory{
+		Namespace: changefeedID.Namespace,
+		ID:        changefeedID.ID,
+		Revisions: make([]ChangefeedRevision, 0, len(history.Revisions)),
+	}
+	for _, revision := range history.Revisions {
+		resp.Revisions = append(resp.Revisions, ChangefeedRevision{
+			Revision:   revision.Revision,
+			UpdateTime: revision.UpdateTime,
+			Reason:     revision.Reason,
+			Info:       toAPIModel(revision.Info, true),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// diffChangefeedHistory returns the config changes between two revisions
+// of a changefeed. The current revision and its previous revision are
+// compared if they are not specified.
+func (h *OpenAPIV2) diffChangefeedHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	history, _, err := h.getChangefeedHistoryWithInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	toRevision := history.Current()
+	if to := c.Query(apiOpVarToRevision); to != "" {
+		revision, err := strconv.ParseUint(to, 10, 64)
+		if err != nil {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid revision: %s", to))
+			return
+		}
+		if toRevision, err = history.GetRevision(changefeedID, revision); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	fromRevision := toRevision
+	if from := c.Query(apiOpVarFromRevision); from != "" {
+		revision, err := strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid revision: %s", from))
+			return
+		}
+		if fromRevision, err = history.GetRevision(changefeedID, revision); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	} else {
+		for i := 1; i < len(history.Revisions); i++ {
+			if history.Revisions[i] == toRevision {
+				fromRevision = history.Revisions[i-1]
+				break
+			}
+		}
+	}
+
+	changes, err := model.DiffChangeFeedInfo(fromRevision.Info, toRevision.Info)
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	resp := &ChangefeedHistoryDiff{
+		Namespace: changefeedID.Namespace,
+		ID:        changefeedID.ID,
+		From:      fromRevision.Revision,
+		To:        toRevision.Revision,
+		Changes:   make([]ChangefeedConfigChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		if change.Path == changefeedSinkURIPath {
+			change.Old = maskSinkURIValue(change.Old)
+			change.New = maskSinkURIValue(change.New)
+		}
+		resp.Changes = append(resp.Changes, ChangefeedConfigChange{
+			Path: change.Path,
+			Old:  change.Old,
+			New:  change.New,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// rollbackChangefeed rolls back the config of a stopped changefeed
+// to a previous revision, which is recorded as a new revision.
+func (h *OpenAPIV2) rollbackChangefeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := &RollbackChangefeedConfig{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+
+	history, cfInfo, err := h.getChangefeedHistoryWithInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if cfInfo.State != model.StateStopped {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.
+			GenWithStackByArgs("can only roll back changefeed config when it is stopped"))
+		return
+	}
+	revision, err := history.GetRevision(changefeedID, cfg.Revision)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if revision == history.Current() {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			fmt.Sprintf("revision %d is the current revision", cfg.Revision)))
+		return
+	}
+	newCfInfo, err := cfInfo.RollbackTo(revision.Info)
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+
+	cfStatus, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if newCfInfo.TargetTs != 0 && newCfInfo.TargetTs <= cfStatus.CheckpointTs {
+		_ = c.Error(cerror.ErrChangefeedUpdateRefused.GenWithStackByArgs(
+			fmt.Sprintf("target ts %d of revision %d is not greater than "+
+				"checkpoint ts %d", newCfInfo.TargetTs, cfg.Revision,
+				cfStatus.CheckpointTs)))
+		return
+	}
+	upInfo, err := h.capture.GetEtcdClient().
+		GetUpstreamInfo(ctx, cfInfo.UpstreamID, cfInfo.Namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	reason := fmt.Sprintf("rollback to revision %d", cfg.Revision)
+	if cfg.Reason != "" {
+		reason += ": " + cfg.Reason
+	}
+	err = h.capture.GetEtcdClient().
+		UpdateChangefeedAndUpstream(ctx, upInfo, newCfInfo, changefeedID, reason)
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Rollback changefeed successfully!",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Uint64("revision", cfg.Revision),
+		zap.String("changefeedInfo", newCfInfo.String()))
+	c.JSON(http.StatusOK, toAPIModel(newCfInfo, true))
+}
+
+// getChangefeedHistoryWithInfo returns the history and the current info
+// of a changefeed. The current info is returned as the only revision
+// if the changefeed has no history.
+func (h *OpenAPIV2) getChangefeedHistoryWithInfo(
+	ctx context.Context, changefeedID model.ChangeFeedID,
+) (*model.ChangeFeedHistory, *model.ChangeFeedInfo, error) {
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := h.capture.GetEtcdClient().GetChangeFeedHistory(ctx, changefeedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if history.Current() == nil {
+		if err := history.AddRevision(cfInfo, "", cfInfo.CreateTime); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return history, cfInfo, nil
+}
+
+func maskSinkURIValue(value interface{}) interface{} {
+	sinkURI, ok := value.(string)
+	if !ok {
+		return value
+	}
+	masked, err := util.MaskSinkURI(sinkURI)
+	if err != nil {
+		log.Error("failed to mask sink URI", zap.Error(err))
+	}
+	return masked
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/tracing"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/httputil"
+)
+
+// getChangefeedLatency collects the replication latency of the tables in
+// a changefeed from all captures.
+func (h *OpenAPIV2) getChangefeedLatency(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	if _, err := h.capture.StatusProvider().
+		GetChangeFeedInfo(ctx, changefeedID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	self, err := h.capture.Info()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	captures, err := h.capture.StatusProvider().GetCaptures(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resp := &ChangefeedLatency{
+		Namespace: changefeedID.Namespace,
+		ID:        changefeedID.ID,
+		Tables:    make([]TableLatency, 0),
+	}
+	for _, capture := range captures {
+		var tables []TableLatency
+		if capture.ID == self.ID {
+			tables = toTableLatencies(self.ID, tracing.GetChangefeedLatency(changefeedID))
+		} else {
+			tables, err = getCaptureLatency(ctx, capture, changefeedID)
+			if err != nil {
+				_ = c.Error(err)
+				return
+			}
+		}
+		resp.Tables = append(resp.Tables, tables...)
+	}
+	sort.Slice(resp.Tables, func(i, j int) bool {
+		return resp.Tables[i].TableID < resp.Tables[j].TableID
+	})
+	c.JSON(http.StatusOK, resp)
+}
+
+// getProcessorLatency returns the replication latency of the tables in
+// a changefeed which are replicated by this capture.
+func (h *OpenAPIV2) getProcessorLatency(c *gin.Context) {
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	self, err := h.capture.Info()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, &ChangefeedLatency{
+		Namespace: changefeedID.Namespace,
+		ID:        changefeedID.ID,
+		Tables:    toTableLatencies(self.ID, tracing.GetChangefeedLatency(changefeedID)),
+	})
+}
+
+// getCaptureLatency gets the replication latency of the tables in
+// a changefeed from the processor api of a capture.
+func getCaptureLatency(
+	ctx context.Context, capture *model.CaptureInfo, changefeedID model.ChangeFeedID,
+) ([]TableLatency, error) {
+	body, err := requestCapture(ctx, capture,
+		fmt.Sprintf("/api/v2/processors/%s/latency", changefeedID.ID),
+		url.Values{apiOpVarNamespace: {changefeedID.Namespace}})
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrGetCaptureLatencyFailed, err,
+			capture.ID, err.Error())
+	}
+	var latency ChangefeedLatency
+	if err := json.Unmarshal(body, &latency); err != nil {
+		return nil, cerror.WrapError(cerror.ErrGetCaptureLatencyFailed, err,
+			capture.ID, err.Error())
+	}
+	return latency.Tables, nil
+}
+
+// requestCapture sends a GET request to the http server of a capture and
+// returns the response body.
+func requestCapture(
+	ctx context.Context, capture *model.CaptureInfo, path string, query url.Values,
+) ([]byte, error) {
+	security := config.GetGlobalServerConfig().Security
+	scheme := "http"
+	// we should check tls config instead of security here because
+	// security will never be nil
+	if tls, _ := security.ToTLSConfigWithVerify(); tls != nil {
+		scheme = "https"
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     capture.AdvertiseAddr,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	cli, err := httputil.NewClient(security)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp, err := cli.Get(ctx, u.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("[%d] %s", resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+func toTableLatencies(
+	captureID model.CaptureID, tables []tracing.TableLatency,
+) []TableLatency {
+	res := make([]TableLatency, 0, len(tables))
+	for _, table := range tables {
+		stages := make([]StageLatency, 0, len(table.Stages))
+		for _, stage := range table.Stages {
+			stages = append(stages, StageLatency{
+				Stage:     stage.Stage.String(),
+				Count:     stage.Count,
+				AvgLagMs:  stage.Avg.Milliseconds(),
+				MaxLagMs:  stage.Max.Milliseconds(),
+				LastLagMs: stage.Last.Milliseconds(),
+			})
+		}
+		res = append(res, TableLatency{
+			TableID:   table.TableID,
+			TableName: table.TableName,
+			CaptureID: captureID,
+			Stages:    stages,
+		})
+	}
+	return res
+}
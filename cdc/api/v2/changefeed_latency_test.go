@@ -0,0 +1,123 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/tracing"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestGetChangefeedLatency(t *testing.T) {
+	t.Parallel()
+	getLatency := testCase{url: "/api/v2/changefeeds/%s/latency", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	// the remote capture replicates table 1
+	remoteFailed := false
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/processors/test-latency/latency" ||
+			r.URL.Query().Get(apiOpVarNamespace) != "ns" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if remoteFailed {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("processor is not ready"))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&ChangefeedLatency{
+			Tables: []TableLatency{{
+				TableID:   1,
+				TableName: "test.t1",
+				CaptureID: "capture-2",
+				Stages:    []StageLatency{{Stage: "sink", Count: 1, MaxLagMs: 10}},
+			}},
+		})
+	}))
+	defer remote.Close()
+
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{ID: "test-latency", Namespace: "ns"},
+		captures: []*model.CaptureInfo{
+			{ID: "capture-1", AdvertiseAddr: "127.0.0.1:8300"},
+			{ID: "capture-2", AdvertiseAddr: strings.TrimPrefix(remote.URL, "http://")},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().Info().Return(model.CaptureInfo{ID: "capture-1"}, nil).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+
+	// this capture replicates table 2
+	changefeedID := model.ChangeFeedID{Namespace: "ns", ID: "test-latency"}
+	tracker := tracing.NewTableTracker(changefeedID, 2, "test.t2")
+	defer tracker.Close()
+	tracker.Observe(tracing.StagePuller, 1,
+		oracle.GoTimeToTS(time.Now().Add(-time.Second)))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), getLatency.method,
+		fmt.Sprintf(getLatency.url, "test-latency")+"?namespace=ns", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ChangefeedLatency{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "ns", resp.Namespace)
+	require.Len(t, resp.Tables, 2)
+	require.Equal(t, "capture-2", resp.Tables[0].CaptureID)
+	require.Equal(t, int64(10), resp.Tables[0].Stages[0].MaxLagMs)
+	require.Equal(t, int64(2), resp.Tables[1].TableID)
+	require.Equal(t, "capture-1", resp.Tables[1].CaptureID)
+	require.Len(t, resp.Tables[1].Stages, 5)
+	require.Equal(t, "puller", resp.Tables[1].Stages[1].Stage)
+	require.Equal(t, uint64(1), resp.Tables[1].Stages[1].Count)
+	require.Greater(t, resp.Tables[1].Stages[1].MaxLagMs, int64(0))
+
+	// the error of a remote capture is returned
+	remoteFailed = true
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), getLatency.method,
+		fmt.Sprintf(getLatency.url, "test-latency")+"?namespace=ns", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrGetCaptureLatencyFailed")
+	require.Contains(t, respErr.Error, "processor is not ready")
+
+	// the processor api only returns the tables of this capture
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), "GET",
+		"/api/v2/processors/test-latency/latency?namespace=ns", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = ChangefeedLatency{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Tables, 1)
+	require.Equal(t, int64(2), resp.Tables[0].TableID)
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/api"
+	"github.com/pingcap/tiflow/cdc/model"
+	ddlfactory "github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/factory"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/factory"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// resyncTable re-syncs a table of a running changefeed. The table is removed
+// from the changefeed, and added back at the latest checkpoint with a new
+// snapshot of its rows, the other tables are not affected.
+func (h *OpenAPIV2) resyncTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfg := &ResyncTableConfig{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if cfg.SchemaName == "" || cfg.TableName == "" {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"both schema_name and table_name must be specified"))
+		return
+	}
+	policy := model.ResyncPolicyTruncate
+	if cfg.Policy != "" {
+		policy = model.ResyncPolicy(cfg.Policy)
+	}
+	if err := policy.Validate(); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if cfInfo.State != model.StateNormal {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"can only resync a table of a running changefeed, state: %s", cfInfo.State))
+		return
+	}
+	// The new sink truncates the table by its DDL sink, which is not
+	// implemented for all the sinks.
+	if policy == model.ResyncPolicyTruncate &&
+		config.GetGlobalServerConfig().Debug.EnableNewSink &&
+		factory.IsSupported(cfInfo.SinkURI) && !ddlfactory.IsSupported(cfInfo.SinkURI) {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"the truncate policy is not supported by the new sink of changefeed %s, "+
+				"use the overwrite policy instead", changefeedID.ID))
+		return
+	}
+	cfStatus, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	tableInfo, err := h.capture.StatusProvider().GetTableInfo(ctx, changefeedID,
+		cfg.SchemaName, cfg.TableName, cfStatus.CheckpointTs)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	tableIDs := []model.TableID{tableInfo.ID}
+	if pi := tableInfo.GetPartitionInfo(); pi != nil {
+		// Every partition truncates the whole table in the downstream,
+		// so the rows re-synced by other partitions would be lost.
+		if policy == model.ResyncPolicyTruncate {
+			_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+				"the truncate policy is not supported by partitioned table %s, "+
+					"use the overwrite policy instead", tableInfo.TableName.QuoteString()))
+			return
+		}
+		tableIDs = tableIDs[:0]
+		for _, partition := range pi.Definitions {
+			tableIDs = append(tableIDs, partition.ID)
+		}
+	}
+	if err := api.HandleOwnerResyncTable(ctx, h.capture, changefeedID,
+		tableIDs, tableInfo.TableName, policy); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,171 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	mock_owner "github.com/pingcap/tiflow/cdc/owner/mock"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResyncTable(t *testing.T) {
+	t.Parallel()
+	resync := testCase{url: "/api/v2/changefeeds/%s/resync", method: "POST"}
+	cp, helpers := newTestSchemaCapture(t)
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+	statusProvider := cp.StatusProvider().(*mockStatusProvider)
+
+	doRequest := func(cfg *ResyncTableConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), resync.method,
+			fmt.Sprintf(resync.url, changeFeedID.ID), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// table is not specified
+	w := doRequest(&ResyncTableConfig{SchemaName: "test"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "ErrAPIInvalidParam")
+
+	// invalid policy
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t", Policy: "drop"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "invalid resync policy")
+
+	// changefeed is not running
+	statusProvider.changefeedInfo.State = model.StateStopped
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "running changefeed")
+	statusProvider.changefeedInfo.State = model.StateNormal
+
+	// the table is resolved at the checkpoint ts, and truncated by default
+	tableInfo := model.WrapTableInfo(1, "test", 10, &timodel.TableInfo{
+		ID: 2, Name: timodel.NewCIStr("t"),
+	})
+	statusProvider.getTableInfo = func(schemaName, tableName string, ts uint64) (*model.TableInfo, error) {
+		require.Equal(t, "t", tableName)
+		require.Equal(t, uint64(100), ts)
+		return tableInfo, nil
+	}
+	owner.EXPECT().ResyncTable(gomock.Any(), []model.TableID{2},
+		tableInfo.TableName, model.ResyncPolicyTruncate, gomock.Any()).
+		Do(func(_ model.ChangeFeedID, _ []model.TableID, _ model.TableName,
+			_ model.ResyncPolicy, done chan<- error,
+		) {
+			done <- nil
+			close(done)
+		}).Times(1)
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// all the partitions of a partitioned table are re-synced
+	partitioned := model.WrapTableInfo(1, "test", 10, &timodel.TableInfo{
+		ID: 3, Name: timodel.NewCIStr("p"),
+		Partition: &timodel.PartitionInfo{
+			Enable:      true,
+			Definitions: []timodel.PartitionDefinition{{ID: 4}, {ID: 5}},
+		},
+	})
+	statusProvider.getTableInfo = func(schemaName, tableName string, ts uint64) (*model.TableInfo, error) {
+		require.Equal(t, "p", tableName)
+		require.Equal(t, uint64(100), ts)
+		return partitioned, nil
+	}
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "p"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "overwrite policy")
+
+	owner.EXPECT().ResyncTable(gomock.Any(), []model.TableID{4, 5},
+		partitioned.TableName, model.ResyncPolicyOverwrite, gomock.Any()).
+		Do(func(_ model.ChangeFeedID, _ []model.TableID, _ model.TableName,
+			_ model.ResyncPolicy, done chan<- error,
+		) {
+			done <- nil
+			close(done)
+		}).Times(1)
+	w = doRequest(&ResyncTableConfig{
+		SchemaName: "test", TableName: "p", Policy: "overwrite",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestResyncTableWithNewSink(t *testing.T) {
+	oldCfg := config.GetGlobalServerConfig()
+	defer config.StoreGlobalServerConfig(oldCfg)
+	cfg := config.GetDefaultServerConfig()
+	cfg.Debug.EnableNewSink = true
+	config.StoreGlobalServerConfig(cfg)
+
+	resync := testCase{url: "/api/v2/changefeeds/%s/resync", method: "POST"}
+	cp, helpers := newTestSchemaCapture(t)
+	owner := mock_owner.NewMockOwner(gomock.NewController(t))
+	cp.EXPECT().GetOwner().Return(owner, nil).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+	statusProvider := cp.StatusProvider().(*mockStatusProvider)
+	tableInfo := model.WrapTableInfo(1, "test", 10, &timodel.TableInfo{
+		ID: 2, Name: timodel.NewCIStr("t"),
+	})
+	statusProvider.getTableInfo = func(schemaName, tableName string, ts uint64) (*model.TableInfo, error) {
+		return tableInfo, nil
+	}
+	doRequest := func(cfg *ResyncTableConfig) *httptest.ResponseRecorder {
+		body, err := json.Marshal(cfg)
+		require.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), resync.method,
+			fmt.Sprintf(resync.url, changeFeedID.ID), bytes.NewReader(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The new kafka sink can not truncate the table.
+	statusProvider.changefeedInfo.State = model.StateNormal
+	statusProvider.changefeedInfo.SinkURI = "kafka://127.0.0.1:9092/topic"
+	w := doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "overwrite policy")
+
+	owner.EXPECT().ResyncTable(gomock.Any(), []model.TableID{2},
+		tableInfo.TableName, gomock.Any(), gomock.Any()).
+		Do(func(_ model.ChangeFeedID, _ []model.TableID, _ model.TableName,
+			_ model.ResyncPolicy, done chan<- error,
+		) {
+			done <- nil
+			close(done)
+		}).Times(2)
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t", Policy: "overwrite"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The new mysql sink truncates the table by its DDL sink.
+	statusProvider.changefeedInfo.SinkURI = "mysql://127.0.0.1:3306/"
+	w = doRequest(&ResyncTableConfig{SchemaName: "test", TableName: "t"})
+	require.Equal(t, http.StatusOK, w.Code)
+}
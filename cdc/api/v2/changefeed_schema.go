@@ -0,0 +1,215 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
+)
+
+const (
+	apiOpVarSchema  = "schema"
+	apiOpVarTable   = "table"
+	apiOpVarTs      = "ts"
+	apiOpVarStartTs = "start_ts"
+	apiOpVarEndTs   = "end_ts"
+)
+
+// getTableSchema returns the schema of a table at a ts in the schema
+// storage of a changefeed, which is maintained by the owner. The checkpoint
+// ts of the changefeed is used if ts is not specified.
+func (h *OpenAPIV2) getTableSchema(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID, schemaName, tableName, err := parseTableQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	ts, err := h.parseTsWithDefault(ctx, c, apiOpVarTs, changefeedID, 0)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	tableInfo, err := h.capture.StatusProvider().GetTableInfo(ctx, changefeedID,
+		schemaName, tableName, ts)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := &TableSchema{
+		Namespace:        changefeedID.Namespace,
+		ID:               changefeedID.ID,
+		Ts:               ts,
+		SchemaName:       tableInfo.TableName.Schema,
+		TableName:        tableInfo.TableName.Table,
+		TableID:          tableInfo.ID,
+		Columns:          make([]ColumnSchema, 0, len(tableInfo.Columns)),
+		Indexes:          make([]IndexSchema, 0, len(tableInfo.Indices)),
+		HandleKeyColumns: make([]string, 0),
+	}
+	for _, col := range tableInfo.Columns {
+		column := ColumnSchema{
+			Name:         col.Name.O,
+			Type:         col.GetTypeDesc(),
+			Nullable:     !mysql.HasNotNullFlag(col.GetFlag()),
+			IsPrimaryKey: mysql.HasPriKeyFlag(col.GetFlag()),
+		}
+		if def := col.GetDefaultValue(); def != nil {
+			value := fmt.Sprintf("%v", def)
+			column.Default = &value
+		}
+		resp.Columns = append(resp.Columns, column)
+		if flag, ok := tableInfo.ColumnsFlag[col.ID]; ok && flag.IsHandleKey() {
+			resp.HandleKeyColumns = append(resp.HandleKeyColumns, col.Name.O)
+		}
+	}
+	for _, idx := range tableInfo.Indices {
+		index := IndexSchema{
+			Name:    idx.Name.O,
+			Columns: make([]string, 0, len(idx.Columns)),
+			Unique:  idx.Unique,
+			Primary: idx.Primary,
+		}
+		for _, col := range idx.Columns {
+			index.Columns = append(index.Columns, col.Name.O)
+		}
+		resp.Indexes = append(resp.Indexes, index)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getTableDDLEvents returns the DDLs applied to a table between start_ts
+// and end_ts by a changefeed. The earliest ts of the schema history kept
+// by the owner and the checkpoint ts of the changefeed are used if they
+// are not specified.
+func (h *OpenAPIV2) getTableDDLEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID, schemaName, tableName, err := parseTableQuery(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var startTs uint64
+	if c.Query(apiOpVarStartTs) != "" {
+		startTs, err = h.parseTsWithDefault(ctx, c, apiOpVarStartTs, changefeedID, 0)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	endTs, err := h.parseTsWithDefault(ctx, c, apiOpVarEndTs, changefeedID, 0)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if startTs > endTs {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"start_ts %d is greater than end_ts %d", startTs, endTs))
+		return
+	}
+
+	f, err := filter.NewFilter(cfInfo.Config, "")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	jobs, err := h.capture.StatusProvider().GetTableDDLJobs(ctx, changefeedID,
+		schemaName, tableName, startTs, endTs)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := &TableDDLEvents{
+		Namespace:  changefeedID.Namespace,
+		ID:         changefeedID.ID,
+		SchemaName: schemaName,
+		TableName:  tableName,
+		StartTs:    startTs,
+		EndTs:      endTs,
+		Events:     make([]TableDDLEvent, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		resp.Events = append(resp.Events, TableDDLEvent{
+			JobID:      job.ID,
+			CommitTs:   job.BinlogInfo.FinishedTS,
+			Type:       job.Type.String(),
+			Query:      job.Query,
+			SchemaName: job.SchemaName,
+			TableName:  job.TableName,
+			Ignored: f.ShouldDiscardDDL(job.Type) ||
+				f.ShouldIgnoreTable(job.SchemaName, job.TableName),
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseTableQuery(c *gin.Context) (
+	changefeedID model.ChangeFeedID, schemaName, tableName string, err error,
+) {
+	changefeedID = model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err = model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		err = cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID)
+		return
+	}
+	schemaName, tableName = c.Query(apiOpVarSchema), c.Query(apiOpVarTable)
+	if schemaName == "" || tableName == "" {
+		err = cerror.ErrAPIInvalidParam.GenWithStack(
+			"both schema and table must be specified")
+	}
+	return
+}
+
+// parseTsWithDefault parses a ts from the query. If the ts is not specified,
+// defaultTs is used, and the checkpoint ts of the changefeed is used if
+// defaultTs is zero.
+func (h *OpenAPIV2) parseTsWithDefault(
+	ctx context.Context, c *gin.Context, key string,
+	changefeedID model.ChangeFeedID, defaultTs uint64,
+) (uint64, error) {
+	if value := c.Query(key); value != "" {
+		ts, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || ts == 0 {
+			return 0, cerror.ErrAPIInvalidParam.GenWithStack(
+				"invalid %s: %s", key, value)
+		}
+		return ts, nil
+	}
+	if defaultTs != 0 {
+		return defaultTs, nil
+	}
+	cfStatus, err := h.capture.StatusProvider().GetChangeFeedStatus(ctx, changefeedID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return cfStatus.CheckpointTs, nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchemaCapture(t *testing.T) (*mock_capture.MockCapture, *MockAPIV2Helpers) {
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test.*"}
+	statusProvider := &mockStatusProvider{
+		changefeedInfo: &model.ChangeFeedInfo{
+			ID:      changeFeedID.ID,
+			StartTs: 5,
+			Config:  cfg,
+		},
+		changefeedStatus: &model.ChangeFeedStatus{CheckpointTs: 100},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	return cp, helpers
+}
+
+func TestGetTableSchema(t *testing.T) {
+	t.Parallel()
+	getSchema := testCase{url: "/api/v2/changefeeds/%s/schema?%s", method: "GET"}
+	cp, helpers := newTestSchemaCapture(t)
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+	statusProvider := cp.StatusProvider().(*mockStatusProvider)
+
+	idType := types.NewFieldType(mysql.TypeLong)
+	idType.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	nameType := types.NewFieldType(mysql.TypeVarchar)
+	nameType.SetFlen(10)
+	tableInfo := model.WrapTableInfo(1, "test", 10, &timodel.TableInfo{
+		ID:         2,
+		Name:       timodel.NewCIStr("t"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *idType, State: timodel.StatePublic},
+			{
+				ID: 2, Name: timodel.NewCIStr("name"), FieldType: *nameType,
+				DefaultValue: "abc", State: timodel.StatePublic,
+			},
+		},
+		Indices: []*timodel.IndexInfo{{
+			Name:    timodel.NewCIStr("idx_name"),
+			Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("name"), Offset: 1}},
+			State:   timodel.StatePublic,
+		}},
+	})
+
+	// the checkpoint ts is used by default
+	statusProvider.getTableInfo = func(schemaName, tableName string, ts uint64) (*model.TableInfo, error) {
+		require.Equal(t, "test", schemaName)
+		require.Equal(t, "t", tableName)
+		require.Equal(t, uint64(100), ts)
+		return tableInfo, nil
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), getSchema.method,
+		fmt.Sprintf(getSchema.url, changeFeedID.ID, "schema=test&table=t"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := TableSchema{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, uint64(100), resp.Ts)
+	require.Equal(t, int64(2), resp.TableID)
+	require.Len(t, resp.Columns, 2)
+	require.Equal(t, "int(11)", resp.Columns[0].Type)
+	require.True(t, resp.Columns[0].IsPrimaryKey)
+	require.False(t, resp.Columns[0].Nullable)
+	require.Nil(t, resp.Columns[0].Default)
+	require.Equal(t, "varchar(10)", resp.Columns[1].Type)
+	require.True(t, resp.Columns[1].Nullable)
+	require.Equal(t, "abc", *resp.Columns[1].Default)
+	require.Equal(t, []IndexSchema{{Name: "idx_name", Columns: []string{"name"}}}, resp.Indexes)
+	require.Equal(t, []string{"id"}, resp.HandleKeyColumns)
+
+	// table not found
+	statusProvider.getTableInfo = func(schemaName, tableName string, ts uint64) (*model.TableInfo, error) {
+		require.Equal(t, uint64(50), ts)
+		return nil, cerror.ErrSnapshotTableNameNotFound.GenWithStackByArgs(schemaName, tableName, ts)
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), getSchema.method,
+		fmt.Sprintf(getSchema.url, changeFeedID.ID, "schema=test&table=t1&ts=50"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrSnapshotTableNameNotFound")
+
+	// invalid parameters
+	for _, query := range []string{"schema=test", "table=t", "schema=test&table=t&ts=abc"} {
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequestWithContext(context.Background(), getSchema.method,
+			fmt.Sprintf(getSchema.url, changeFeedID.ID, query), nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetTableDDLEvents(t *testing.T) {
+	t.Parallel()
+	getDDLs := testCase{url: "/api/v2/changefeeds/%s/schema/ddl?%s", method: "GET"}
+	cp, helpers := newTestSchemaCapture(t)
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+	statusProvider := cp.StatusProvider().(*mockStatusProvider)
+
+	jobs := []*timodel.Job{
+		{
+			ID: 10, Type: timodel.ActionCreateTable, SchemaName: "test", TableName: "t",
+			Query:      "create table t (id int primary key)",
+			BinlogInfo: &timodel.HistoryInfo{FinishedTS: 20},
+		},
+		{
+			ID: 11, Type: timodel.ActionRenameTable, SchemaName: "test1", TableName: "t",
+			Query:      "rename table test.t to test1.t",
+			BinlogInfo: &timodel.HistoryInfo{FinishedTS: 30},
+		},
+	}
+	// the earliest ts of the schema history and the checkpoint ts are used
+	// by default
+	statusProvider.getTableDDLJobs = func(
+		schemaName, tableName string, startTs, endTs uint64,
+	) ([]*timodel.Job, error) {
+		require.Equal(t, "test", schemaName)
+		require.Equal(t, "t", tableName)
+		require.Equal(t, uint64(0), startTs)
+		require.Equal(t, uint64(100), endTs)
+		return jobs, nil
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), getDDLs.method,
+		fmt.Sprintf(getDDLs.url, changeFeedID.ID, "schema=test&table=t"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := TableDDLEvents{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, uint64(0), resp.StartTs)
+	require.Equal(t, uint64(100), resp.EndTs)
+	require.Equal(t, []TableDDLEvent{
+		{
+			JobID: 10, CommitTs: 20, Type: "create table", Query: jobs[0].Query,
+			SchemaName: "test", TableName: "t",
+		},
+		{
+			JobID: 11, CommitTs: 30, Type: "rename table", Query: jobs[1].Query,
+			SchemaName: "test1", TableName: "t", Ignored: true,
+		},
+	}, resp.Events)
+
+	statusProvider.getTableDDLJobs = func(
+		schemaName, tableName string, startTs, endTs uint64,
+	) ([]*timodel.Job, error) {
+		require.Equal(t, uint64(20), startTs)
+		require.Equal(t, uint64(25), endTs)
+		return nil, nil
+	}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), getDDLs.method,
+		fmt.Sprintf(getDDLs.url, changeFeedID.ID, "schema=test&table=t&start_ts=20&end_ts=25"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = TableDDLEvents{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Events, 0)
+
+	// start ts is greater than end ts
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), getDDLs.method,
+		fmt.Sprintf(getDDLs.url, changeFeedID.ID, "schema=test&table=t&start_ts=30&end_ts=25"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
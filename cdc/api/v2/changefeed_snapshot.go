@@ -0,0 +1,76 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// getChangefeedSnapshotProgress returns the initial snapshot progress
+// of the tables in a changefeed.
+func (h *OpenAPIV2) getChangefeedSnapshotProgress(c *gin.Context) {
+	ctx := c.Request.Context()
+	changefeedID := model.ChangeFeedID{
+		Namespace: getNamespaceValueWithDefault(c),
+		ID:        c.Param(apiOpVarChangefeedID),
+	}
+	if err := model.ValidateChangefeedID(changefeedID.ID); err != nil {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack("invalid changefeed_id: %s",
+			changefeedID.ID))
+		return
+	}
+	cfInfo, err := h.capture.StatusProvider().GetChangeFeedInfo(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := &ChangefeedSnapshotProgress{
+		Namespace:  changefeedID.Namespace,
+		ID:         changefeedID.ID,
+		Enabled:    cfInfo.Config.InitialSnapshot,
+		SnapshotTs: cfInfo.StartTs,
+		Tables:     make([]TableSnapshotProgress, 0),
+	}
+	if !resp.Enabled {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	progresses, err := h.capture.GetEtcdClient().
+		GetChangeFeedSnapshotProgress(ctx, changefeedID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	for tableID, progress := range progresses {
+		if progress.SnapshotTs != cfInfo.StartTs {
+			continue
+		}
+		resp.Tables = append(resp.Tables, TableSnapshotProgress{
+			TableID:    tableID,
+			TableName:  progress.TableName,
+			Rows:       progress.Rows,
+			Done:       progress.Done,
+			UpdateTime: progress.UpdateTime,
+		})
+	}
+	sort.Slice(resp.Tables, func(i, j int) bool {
+		return resp.Tables[i].TableID < resp.Tables[j].TableID
+	})
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChangefeedSnapshotProgress(t *testing.T) {
+	t.Parallel()
+	getSnapshot := testCase{url: "/api/v2/changefeeds/%s/snapshot", method: "GET"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t)))
+	router := newRouter(apiV2)
+
+	info := &model.ChangeFeedInfo{
+		ID:        changeFeedID.ID,
+		Namespace: model.DefaultNamespace,
+		StartTs:   100,
+		Config:    config.GetDefaultReplicaConfig(),
+	}
+	statusProvider := &mockStatusProvider{changefeedInfo: info}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+
+	// the snapshot progress is not queried if initial snapshot is disabled
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), getSnapshot.method,
+		fmt.Sprintf(getSnapshot.url, changeFeedID.ID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ChangefeedSnapshotProgress{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.False(t, resp.Enabled)
+	require.Len(t, resp.Tables, 0)
+
+	info.Config.InitialSnapshot = true
+	etcdClient.EXPECT().GetChangeFeedSnapshotProgress(gomock.Any(), gomock.Any()).
+		Return(map[model.TableID]*model.TableSnapshotProgress{
+			3: {SnapshotTs: 100, TableName: "`test`.`t3`", Rows: 10},
+			1: {SnapshotTs: 100, TableName: "`test`.`t1`", Rows: 20, Done: true},
+			// left by a removed changefeed
+			2: {SnapshotTs: 99, TableName: "`test`.`t2`", Rows: 30, Done: true},
+		}, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), getSnapshot.method,
+		fmt.Sprintf(getSnapshot.url, changeFeedID.ID), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = ChangefeedSnapshotProgress{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.True(t, resp.Enabled)
+	require.Equal(t, uint64(100), resp.SnapshotTs)
+	require.Len(t, resp.Tables, 2)
+	require.Equal(t, int64(1), resp.Tables[0].TableID)
+	require.True(t, resp.Tables[0].Done)
+	require.Equal(t, "`test`.`t3`", resp.Tables[1].TableName)
+	require.Equal(t, uint64(10), resp.Tables[1].Rows)
+}
@@ -62,6 +62,9 @@ func TestCreateChangefeed(t *testing.T) {
 		Return(etcd.GcServiceIDForTest()).AnyTimes()
 	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
 	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	etcdClient.EXPECT().
+		GetNamespaceInfo(gomock.Any(), gomock.Any()).
+		Return(model.NewDefaultNamespaceInfo(), nil).AnyTimes()
 	cp.EXPECT().GetUpstreamManager().Return(mockUpManager, nil).AnyTimes()
 	cp.EXPECT().IsReady().Return(true).AnyTimes()
 	cp.EXPECT().IsOwner().Return(true).AnyTimes()
@@ -218,6 +221,167 @@ func TestCreateChangefeed(t *testing.T) {
 	require.Equal(t, http.StatusCreated, w.Code)
 }
 
+func TestCreateChangefeedWithUpstreamID(t *testing.T) {
+	t.Parallel()
+	create := testCase{url: "/api/v2/changefeeds", method: "POST"}
+
+	pdClient := &mockPDClient{}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	etcdClient.EXPECT().
+		GetNamespaceInfo(gomock.Any(), gomock.Any()).
+		Return(model.NewDefaultNamespaceInfo(), nil).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(&mockStatusProvider{}).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+
+	upstreamInfo := &model.UpstreamInfo{
+		ID:          123,
+		PDEndpoints: "http://127.0.0.1:2379,http://127.0.0.1:2382",
+		CAPath:      "ca.pem",
+	}
+	etcdClient.EXPECT().
+		GetUpstreamInfo(gomock.Any(), gomock.Any(), model.DefaultNamespace).
+		DoAndReturn(func(_ context.Context, id model.UpstreamID, _ string) (
+			*model.UpstreamInfo, error,
+		) {
+			if id != upstreamInfo.ID {
+				return nil, cerrors.ErrUpstreamNotFound.GenWithStackByArgs(id)
+			}
+			return upstreamInfo, nil
+		}).AnyTimes()
+	helpers.EXPECT().
+		getPDClient(gomock.Any(), []string{"http://127.0.0.1:2379", "http://127.0.0.1:2382"},
+			gomock.Any()).
+		Return(pdClient, nil).AnyTimes()
+	helpers.EXPECT().
+		createTiStore(gomock.Any(), gomock.Any()).
+		Return(nil, nil).AnyTimes()
+	helpers.EXPECT().
+		verifyCreateChangefeedConfig(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context,
+			cfg *ChangefeedConfig,
+			pdClient pd.Client,
+			statusProvider owner.StatusProvider,
+			ensureGCServiceID string,
+			kvStorage tidbkv.Storage,
+		) (*model.ChangeFeedInfo, error) {
+			require.Equal(t, "ca.pem", cfg.CAPath)
+			return &model.ChangeFeedInfo{
+				UpstreamID: pdClient.GetClusterID(ctx),
+				ID:         cfg.ID,
+				SinkURI:    cfg.SinkURI,
+			}, nil
+		}).AnyTimes()
+
+	// case 1: the upstream is not registered
+	body, err := json.Marshal(&ChangefeedConfig{
+		ID: changeFeedID.ID, SinkURI: blackholeSink, UpstreamID: 100,
+	})
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), create.method,
+		create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrUpstreamNotFound")
+
+	// case 2: the registered upstream points to another cluster
+	upstreamInfo.ID = 100
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrUpstreamMissMatch")
+
+	// case 3: success
+	upstreamInfo.ID = 123
+	etcdClient.EXPECT().
+		CreateChangefeedInfo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, up *model.UpstreamInfo,
+			info *model.ChangeFeedInfo, _ model.ChangeFeedID,
+		) error {
+			require.Equal(t, upstreamInfo, up)
+			return nil
+		}).Times(1)
+	body, err = json.Marshal(&ChangefeedConfig{
+		ID: changeFeedID.ID, SinkURI: blackholeSink, UpstreamID: 123,
+	})
+	require.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), create.method,
+		create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	resp := ChangeFeedInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, uint64(123), resp.UpstreamID)
+}
+
+func TestCreateChangefeedDryRun(t *testing.T) {
+	t.Parallel()
+	create := testCase{url: "/api/v2/changefeeds?dry_run=true", method: "POST"}
+
+	pdClient := &mockPDClient{}
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	apiV2 := NewOpenAPIV2ForTest(cp, helpers)
+	router := newRouter(apiV2)
+
+	statusProvider := &mockStatusProvider{}
+	etcdClient.EXPECT().
+		GetEnsureGCServiceID(gomock.Any()).
+		Return(etcd.GcServiceIDForTest()).AnyTimes()
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	helpers.EXPECT().
+		getPDClient(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(pdClient, nil).AnyTimes()
+	helpers.EXPECT().
+		createTiStore(gomock.Any(), gomock.Any()).
+		Return(nil, nil).AnyTimes()
+
+	// the changefeed is neither verified nor created in dry run mode
+	report := &DryRunReport{ID: changeFeedID.ID}
+	report.AddCheck(dryRunCheckSink, DryRunCheckFail, "sink is unreachable")
+	helpers.EXPECT().
+		dryRunCreateChangefeed(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report).Times(1)
+
+	cfConfig := &ChangefeedConfig{
+		ID:       changeFeedID.ID,
+		SinkURI:  mysqlSink,
+		PDConfig: PDConfig{PDAddrs: []string{"http://127.0.0.1:2379"}},
+	}
+	body, err := json.Marshal(cfConfig)
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), create.method,
+		create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := DryRunReport{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, *report, resp)
+	require.False(t, resp.Passed)
+}
+
 func TestUpdateChangefeed(t *testing.T) {
 	t.Parallel()
 	update := testCase{url: "/api/v2/changefeeds/%s", method: "PUT"}
@@ -232,7 +396,7 @@ func TestUpdateChangefeed(t *testing.T) {
 	cp.EXPECT().IsOwner().Return(true).AnyTimes()
 
 	// case 1 invalid id
-	invalidID := "#Invalid_"
+	invalidID := "Invalid_"
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequestWithContext(context.Background(), update.method,
 		fmt.Sprintf(update.url, invalidID), nil)
@@ -361,7 +525,7 @@ func TestUpdateChangefeed(t *testing.T) {
 		Return(&model.ChangeFeedInfo{}, &model.UpstreamInfo{}, nil).
 		Times(1)
 	etcdClient.EXPECT().
-		UpdateChangefeedAndUpstream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		UpdateChangefeedAndUpstream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(cerrors.ErrEtcdAPIError).Times(1)
 
 	w = httptest.NewRecorder()
@@ -380,7 +544,7 @@ func TestUpdateChangefeed(t *testing.T) {
 		Return(oldCfInfo, &model.UpstreamInfo{}, nil).
 		Times(1)
 	etcdClient.EXPECT().
-		UpdateChangefeedAndUpstream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		UpdateChangefeedAndUpstream(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil).Times(1)
 
 	w = httptest.NewRecorder()
@@ -565,6 +729,9 @@ func TestResumeChangefeed(t *testing.T) {
 		Return(etcd.GcServiceIDForTest()).AnyTimes()
 	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
 	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	etcdClient.EXPECT().
+		GetNamespaceInfo(gomock.Any(), gomock.Any()).
+		Return(model.NewDefaultNamespaceInfo(), nil).AnyTimes()
 	cp.EXPECT().GetUpstreamManager().Return(mockUpManager, nil).AnyTimes()
 	cp.EXPECT().IsReady().Return(true).AnyTimes()
 	cp.EXPECT().IsOwner().Return(true).AnyTimes()
@@ -657,3 +824,44 @@ func TestResumeChangefeed(t *testing.T) {
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestCheckNamespaceQuota(t *testing.T) {
+	t.Parallel()
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.MemoryQuota = 1024
+	statusProvider := &mockStatusProvider{
+		changefeedInfos: map[model.ChangeFeedID]*model.ChangeFeedInfo{
+			{Namespace: "tenant", ID: "cf1"}: {State: model.StateNormal, Config: cfg},
+			{Namespace: "tenant", ID: "cf2"}: {State: model.StateStopped, Config: cfg},
+			{Namespace: "other", ID: "cf3"}:  {State: model.StateNormal, Config: cfg},
+		},
+	}
+	cp.EXPECT().StatusProvider().Return(statusProvider).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	ctx := context.Background()
+
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "not-exist").
+		Return(nil, cerrors.ErrNamespaceNotExists.GenWithStackByArgs("not-exist"))
+	err := checkNamespaceQuota(ctx, cp,
+		model.ChangeFeedID{Namespace: "not-exist", ID: "cf"}, cfg)
+	require.True(t, cerrors.ErrNamespaceNotExists.Equal(err))
+
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "tenant").
+		Return(&model.NamespaceInfo{
+			Name: "tenant", ChangefeedQuota: 2, MemoryQuota: 2048,
+		}, nil).AnyTimes()
+	// Only cf1 is running in the namespace.
+	require.Nil(t, checkNamespaceQuota(ctx, cp,
+		model.ChangeFeedID{Namespace: "tenant", ID: "cf4"}, cfg))
+	// A changefeed does not count itself when it is resumed.
+	require.Nil(t, checkNamespaceQuota(ctx, cp,
+		model.ChangeFeedID{Namespace: "tenant", ID: "cf1"}, cfg))
+	largeCfg := cfg.Clone()
+	largeCfg.MemoryQuota = 1025
+	err = checkNamespaceQuota(ctx, cp,
+		model.ChangeFeedID{Namespace: "tenant", ID: "cf4"}, largeCfg)
+	require.True(t, cerrors.ErrNamespaceQuotaExceeded.Equal(err))
+}
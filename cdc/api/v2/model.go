@@ -15,6 +15,7 @@ package v2
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -87,6 +88,9 @@ type ChangefeedConfig struct {
 	ReplicaConfig     *ReplicaConfig `json:"replica_config"`
 	SyncPointEnabled  bool           `json:"sync_point_enabled"`
 	SyncPointInterval time.Duration  `json:"sync_point_interval"`
+	// UpstreamID is the ID of a registered upstream to replicate from,
+	// it is used only when PDAddrs is not specified.
+	UpstreamID uint64 `json:"upstream_id,omitempty"`
 	PDConfig
 }
 
@@ -97,9 +101,15 @@ type ReplicaConfig struct {
 	ForceReplicate        bool              `json:"force_replicate"`
 	IgnoreIneligibleTable bool              `json:"ignore_ineligible_table"`
 	CheckGCSafePoint      bool              `json:"check_gc_safe_point"`
+	MemoryQuota           uint64            `json:"memory_quota"`
+	InitialSnapshot       bool              `json:"initial_snapshot"`
 	Filter                *FilterConfig     `json:"filter"`
 	Sink                  *SinkConfig       `json:"sink"`
 	Consistent            *ConsistentConfig `json:"consistent"`
+	Retry                 *RetryConfig      `json:"retry,omitempty"`
+	RateLimit             *RateLimitConfig  `json:"rate_limit,omitempty"`
+	Priority              string            `json:"priority,omitempty"`
+	EnableRowChecksum     bool              `json:"enable_row_checksum,omitempty"`
 }
 
 // ToInternalReplicaConfig coverts *v2.ReplicaConfig into *config.ReplicaConfig
@@ -109,6 +119,10 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 	res.EnableOldValue = c.EnableOldValue
 	res.ForceReplicate = c.ForceReplicate
 	res.CheckGCSafePoint = c.CheckGCSafePoint
+	res.MemoryQuota = c.MemoryQuota
+	res.InitialSnapshot = c.InitialSnapshot
+	res.Priority = c.Priority
+	res.EnableRowChecksum = c.EnableRowChecksum
 
 	if c.Filter != nil {
 		var mySQLReplicationRules *filter.MySQLReplicationRules
@@ -142,12 +156,17 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 				efs[i] = ef.ToInternalEventFilterRule()
 			}
 		}
+		var drs []*config.DDLRewriteRule
+		for _, dr := range c.Filter.DDLRewriteRules {
+			drs = append(drs, dr.ToInternalDDLRewriteRule())
+		}
 		res.Filter = &config.FilterConfig{
 			Rules:                 c.Filter.Rules,
 			MySQLReplicationRules: mySQLReplicationRules,
 			IgnoreTxnStartTs:      c.Filter.IgnoreTxnStartTs,
 			DDLAllowlist:          c.Filter.DDLAllowlist,
 			EventFilters:          efs,
+			DDLRewriteRules:       drs,
 		}
 	}
 	if c.Consistent != nil {
@@ -158,6 +177,29 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 			Storage:           c.Consistent.Storage,
 		}
 	}
+	if c.Retry != nil {
+		res.Retry = &config.RetryConfig{
+			MaxRetryDuration:    config.TomlDuration(c.Retry.MaxRetryDuration),
+			BackoffInitInterval: config.TomlDuration(c.Retry.BackoffInitInterval),
+			BackoffMaxInterval:  config.TomlDuration(c.Retry.BackoffMaxInterval),
+			AutoSkipDDLErrors:   c.Retry.AutoSkipDDLErrors,
+		}
+	}
+	if c.RateLimit != nil {
+		var tables []*config.TableRateLimit
+		for _, table := range c.RateLimit.Tables {
+			tables = append(tables, &config.TableRateLimit{
+				Matcher:           table.Matcher,
+				MaxRowsPerSecond:  table.MaxRowsPerSecond,
+				MaxBytesPerSecond: table.MaxBytesPerSecond,
+			})
+		}
+		res.RateLimit = &config.RateLimitConfig{
+			MaxRowsPerSecond:  c.RateLimit.MaxRowsPerSecond,
+			MaxBytesPerSecond: c.RateLimit.MaxBytesPerSecond,
+			Tables:            tables,
+		}
+	}
 	if c.Sink != nil {
 		var dispatchRules []*config.DispatchRule
 		for _, rule := range c.Sink.DispatchRules {
@@ -175,12 +217,35 @@ func (c *ReplicaConfig) ToInternalReplicaConfig() *config.ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var topicTemplates []*config.TopicTemplate
+		for _, template := range c.Sink.TopicTemplates {
+			topicTemplates = append(topicTemplates, &config.TopicTemplate{
+				Topic:             template.Topic,
+				PartitionNum:      template.PartitionNum,
+				ReplicationFactor: template.ReplicationFactor,
+				TopicConfigs:      template.TopicConfigs,
+			})
+		}
+		var typeRendering *config.TypeRenderingConfig
+		if c.Sink.TypeRendering != nil {
+			typeRendering = &config.TypeRenderingConfig{
+				Decimal:         c.Sink.TypeRendering.Decimal,
+				TimestampZone:   c.Sink.TypeRendering.TimestampZone,
+				TimestampFormat: c.Sink.TypeRendering.TimestampFormat,
+				Binary:          c.Sink.TypeRendering.Binary,
+				Enum:            c.Sink.TypeRendering.Enum,
+			}
+		}
 		res.Sink = &config.SinkConfig{
 			DispatchRules:   dispatchRules,
 			Protocol:        c.Sink.Protocol,
 			TxnAtomicity:    config.AtomicityLevel(c.Sink.TxnAtomicity),
 			ColumnSelectors: columnSelectors,
 			SchemaRegistry:  c.Sink.SchemaRegistry,
+			TopicTemplates:  topicTemplates,
+			TypeRendering:   typeRendering,
+
+			EnablePartitionChangeMarker: c.Sink.EnablePartitionChangeMarker,
 		}
 	}
 	return res
@@ -195,6 +260,10 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 		ForceReplicate:        cloned.ForceReplicate,
 		IgnoreIneligibleTable: false,
 		CheckGCSafePoint:      cloned.CheckGCSafePoint,
+		MemoryQuota:           cloned.MemoryQuota,
+		InitialSnapshot:       cloned.InitialSnapshot,
+		Priority:              cloned.Priority,
+		EnableRowChecksum:     cloned.EnableRowChecksum,
 	}
 
 	if cloned.Filter != nil {
@@ -231,12 +300,18 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			}
 		}
 
+		var drs []DDLRewriteRule
+		for _, dr := range cloned.Filter.DDLRewriteRules {
+			drs = append(drs, ToAPIDDLRewriteRule(dr))
+		}
+
 		res.Filter = &FilterConfig{
 			MySQLReplicationRules: mySQLReplicationRules,
 			Rules:                 cloned.Filter.Rules,
 			IgnoreTxnStartTs:      cloned.Filter.IgnoreTxnStartTs,
 			DDLAllowlist:          cloned.Filter.DDLAllowlist,
 			EventFilters:          efs,
+			DDLRewriteRules:       drs,
 		}
 	}
 	if cloned.Sink != nil {
@@ -255,12 +330,35 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 				Columns: selector.Columns,
 			})
 		}
+		var topicTemplates []*TopicTemplate
+		for _, template := range cloned.Sink.TopicTemplates {
+			topicTemplates = append(topicTemplates, &TopicTemplate{
+				Topic:             template.Topic,
+				PartitionNum:      template.PartitionNum,
+				ReplicationFactor: template.ReplicationFactor,
+				TopicConfigs:      template.TopicConfigs,
+			})
+		}
+		var typeRendering *TypeRenderingConfig
+		if cloned.Sink.TypeRendering != nil {
+			typeRendering = &TypeRenderingConfig{
+				Decimal:         cloned.Sink.TypeRendering.Decimal,
+				TimestampZone:   cloned.Sink.TypeRendering.TimestampZone,
+				TimestampFormat: cloned.Sink.TypeRendering.TimestampFormat,
+				Binary:          cloned.Sink.TypeRendering.Binary,
+				Enum:            cloned.Sink.TypeRendering.Enum,
+			}
+		}
 		res.Sink = &SinkConfig{
 			Protocol:        cloned.Sink.Protocol,
 			SchemaRegistry:  cloned.Sink.SchemaRegistry,
 			DispatchRules:   dispatchRules,
 			ColumnSelectors: columnSelectors,
 			TxnAtomicity:    string(cloned.Sink.TxnAtomicity),
+			TopicTemplates:  topicTemplates,
+			TypeRendering:   typeRendering,
+
+			EnablePartitionChangeMarker: cloned.Sink.EnablePartitionChangeMarker,
 		}
 	}
 	if cloned.Consistent != nil {
@@ -271,6 +369,29 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 			Storage:           cloned.Consistent.Storage,
 		}
 	}
+	if cloned.Retry != nil {
+		res.Retry = &RetryConfig{
+			MaxRetryDuration:    time.Duration(cloned.Retry.MaxRetryDuration),
+			BackoffInitInterval: time.Duration(cloned.Retry.BackoffInitInterval),
+			BackoffMaxInterval:  time.Duration(cloned.Retry.BackoffMaxInterval),
+			AutoSkipDDLErrors:   cloned.Retry.AutoSkipDDLErrors,
+		}
+	}
+	if cloned.RateLimit != nil {
+		var tables []*TableRateLimit
+		for _, table := range cloned.RateLimit.Tables {
+			tables = append(tables, &TableRateLimit{
+				Matcher:           table.Matcher,
+				MaxRowsPerSecond:  table.MaxRowsPerSecond,
+				MaxBytesPerSecond: table.MaxBytesPerSecond,
+			})
+		}
+		res.RateLimit = &RateLimitConfig{
+			MaxRowsPerSecond:  cloned.RateLimit.MaxRowsPerSecond,
+			MaxBytesPerSecond: cloned.RateLimit.MaxBytesPerSecond,
+			Tables:            tables,
+		}
+	}
 	return res
 }
 
@@ -290,6 +411,10 @@ func GetDefaultReplicaConfig() *ReplicaConfig {
 			FlushIntervalInMs: 1000,
 			Storage:           "",
 		},
+		Retry: &RetryConfig{
+			BackoffInitInterval: 10 * time.Second,
+			BackoffMaxInterval:  30 * time.Minute,
+		},
 	}
 }
 
@@ -301,6 +426,7 @@ type FilterConfig struct {
 	IgnoreTxnStartTs []uint64               `json:"ignore_txn_start_ts,omitempty"`
 	DDLAllowlist     []tidbModel.ActionType `json:"ddl_allow_list,omitempty"`
 	EventFilters     []EventFilterRule      `json:"event_filters"`
+	DDLRewriteRules  []DDLRewriteRule       `json:"ddl_rewrite_rules,omitempty"`
 }
 
 // EventFilterRule is used by sql event filter and expression filter
@@ -360,6 +486,44 @@ func ToAPIEventFilterRule(er *config.EventFilterRule) EventFilterRule {
 	return res
 }
 
+// DDLRewriteRule is used to rewrite the DDLs of the matched tables
+// This is a duplicate of config.DDLRewriteRule
+type DDLRewriteRule struct {
+	Matcher     []string          `json:"matcher"`
+	Action      string            `json:"action"`
+	TypeMapping map[string]string `json:"type_mapping,omitempty"`
+	Charset     string            `json:"charset,omitempty"`
+	Collation   string            `json:"collation,omitempty"`
+	Pattern     string            `json:"pattern,omitempty"`
+	Replacement string            `json:"replacement,omitempty"`
+}
+
+// ToInternalDDLRewriteRule converts DDLRewriteRule to *config.DDLRewriteRule
+func (r DDLRewriteRule) ToInternalDDLRewriteRule() *config.DDLRewriteRule {
+	return &config.DDLRewriteRule{
+		Matcher:     r.Matcher,
+		Action:      config.DDLRewriteAction(r.Action),
+		TypeMapping: r.TypeMapping,
+		Charset:     r.Charset,
+		Collation:   r.Collation,
+		Pattern:     r.Pattern,
+		Replacement: r.Replacement,
+	}
+}
+
+// ToAPIDDLRewriteRule converts *config.DDLRewriteRule to API DDLRewriteRule
+func ToAPIDDLRewriteRule(r *config.DDLRewriteRule) DDLRewriteRule {
+	return DDLRewriteRule{
+		Matcher:     r.Matcher,
+		Action:      string(r.Action),
+		TypeMapping: r.TypeMapping,
+		Charset:     r.Charset,
+		Collation:   r.Collation,
+		Pattern:     r.Pattern,
+		Replacement: r.Replacement,
+	}
+}
+
 // MySQLReplicationRules is a set of rules based on MySQL's replication tableFilter.
 type MySQLReplicationRules struct {
 	// DoTables is an allowlist of tables.
@@ -384,11 +548,15 @@ type Table struct {
 // SinkConfig represents sink config for a changefeed
 // This is a duplicate of config.SinkConfig
 type SinkConfig struct {
-	Protocol        string            `json:"protocol"`
-	SchemaRegistry  string            `json:"schema_registry"`
-	DispatchRules   []*DispatchRule   `json:"dispatchers,omitempty"`
-	ColumnSelectors []*ColumnSelector `json:"column_selectors"`
-	TxnAtomicity    string            `json:"transaction_atomicity"`
+	Protocol        string               `json:"protocol"`
+	SchemaRegistry  string               `json:"schema_registry"`
+	DispatchRules   []*DispatchRule      `json:"dispatchers,omitempty"`
+	ColumnSelectors []*ColumnSelector    `json:"column_selectors"`
+	TxnAtomicity    string               `json:"transaction_atomicity"`
+	TopicTemplates  []*TopicTemplate     `json:"topic_templates,omitempty"`
+	TypeRendering   *TypeRenderingConfig `json:"type_rendering,omitempty"`
+
+	EnablePartitionChangeMarker bool `json:"enable_partition_change_marker,omitempty"`
 }
 
 // DispatchRule represents partition rule for a table
@@ -406,6 +574,25 @@ type ColumnSelector struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
+// TopicTemplate represents a template used to create the topics
+// This is a duplicate of config.TopicTemplate
+type TopicTemplate struct {
+	Topic             string            `json:"topic"`
+	PartitionNum      int32             `json:"partition_num"`
+	ReplicationFactor int16             `json:"replication_factor"`
+	TopicConfigs      map[string]string `json:"topic_configs,omitempty"`
+}
+
+// TypeRenderingConfig represents the policy of rendering column values
+// This is a duplicate of config.TypeRenderingConfig
+type TypeRenderingConfig struct {
+	Decimal         string `json:"decimal"`
+	TimestampZone   string `json:"timestamp_zone"`
+	TimestampFormat string `json:"timestamp_format"`
+	Binary          string `json:"binary"`
+	Enum            string `json:"enum"`
+}
+
 // ConsistentConfig represents replication consistency config for a changefeed
 // This is a duplicate of config.ConsistentConfig
 type ConsistentConfig struct {
@@ -415,6 +602,31 @@ type ConsistentConfig struct {
 	Storage           string `json:"storage"`
 }
 
+// RetryConfig represents the policy of restarting a changefeed on errors
+// This is a duplicate of config.RetryConfig
+type RetryConfig struct {
+	MaxRetryDuration    time.Duration `json:"max_retry_duration"`
+	BackoffInitInterval time.Duration `json:"backoff_init_interval"`
+	BackoffMaxInterval  time.Duration `json:"backoff_max_interval"`
+	AutoSkipDDLErrors   []string      `json:"auto_skip_ddl_errors,omitempty"`
+}
+
+// RateLimitConfig represents the replication rate limits of a changefeed
+// This is a duplicate of config.RateLimitConfig
+type RateLimitConfig struct {
+	MaxRowsPerSecond  uint64            `json:"max_rows_per_second"`
+	MaxBytesPerSecond uint64            `json:"max_bytes_per_second"`
+	Tables            []*TableRateLimit `json:"tables,omitempty"`
+}
+
+// TableRateLimit represents the rate limit of the tables matched by Matcher
+// This is a duplicate of config.TableRateLimit
+type TableRateLimit struct {
+	Matcher           []string `json:"matcher"`
+	MaxRowsPerSecond  uint64   `json:"max_rows_per_second"`
+	MaxBytesPerSecond uint64   `json:"max_bytes_per_second"`
+}
+
 // EtcdData contains key/value pair of etcd data
 type EtcdData struct {
 	Key   string `json:"key,omitempty"`
@@ -501,3 +713,346 @@ type UpstreamConfig struct {
 	ID uint64 `json:"id"`
 	PDConfig
 }
+
+// ToInternalUpstreamInfo converts *v2.UpstreamConfig into *model.UpstreamInfo
+func (cfg *UpstreamConfig) ToInternalUpstreamInfo() *model.UpstreamInfo {
+	return &model.UpstreamInfo{
+		ID:            cfg.ID,
+		PDEndpoints:   strings.Join(cfg.PDAddrs, ","),
+		KeyPath:       cfg.KeyPath,
+		CertPath:      cfg.CertPath,
+		CAPath:        cfg.CAPath,
+		CertAllowedCN: cfg.CertAllowedCN,
+	}
+}
+
+// ToAPIUpstreamConfig converts *model.UpstreamInfo into *v2.UpstreamConfig
+func ToAPIUpstreamConfig(info *model.UpstreamInfo) *UpstreamConfig {
+	return &UpstreamConfig{
+		ID: info.ID,
+		PDConfig: PDConfig{
+			PDAddrs:       strings.Split(info.PDEndpoints, ","),
+			CAPath:        info.CAPath,
+			CertPath:      info.CertPath,
+			KeyPath:       info.KeyPath,
+			CertAllowedCN: info.CertAllowedCN,
+		},
+	}
+}
+
+// NamespaceInfo is the settings of a namespace, it is used both as the request
+// of creating or updating a namespace and as the response of querying one.
+type NamespaceInfo struct {
+	Name            string    `json:"name"`
+	CreateTime      time.Time `json:"create_time"`
+	ChangefeedQuota int       `json:"changefeed_quota"`
+	MemoryQuota     uint64    `json:"memory_quota"`
+}
+
+// ToInternalNamespaceInfo converts *v2.NamespaceInfo into *model.NamespaceInfo
+func (n *NamespaceInfo) ToInternalNamespaceInfo() *model.NamespaceInfo {
+	return &model.NamespaceInfo{
+		Name:            n.Name,
+		CreateTime:      n.CreateTime,
+		ChangefeedQuota: n.ChangefeedQuota,
+		MemoryQuota:     n.MemoryQuota,
+	}
+}
+
+// ToAPINamespaceInfo converts *model.NamespaceInfo into *v2.NamespaceInfo
+func ToAPINamespaceInfo(n *model.NamespaceInfo) *NamespaceInfo {
+	return &NamespaceInfo{
+		Name:            n.Name,
+		CreateTime:      n.CreateTime,
+		ChangefeedQuota: n.ChangefeedQuota,
+		MemoryQuota:     n.MemoryQuota,
+	}
+}
+
+// DryRunCheckStatus is the result of a dry run check item
+type DryRunCheckStatus string
+
+const (
+	// DryRunCheckPass means the check item passes
+	DryRunCheckPass DryRunCheckStatus = "pass"
+	// DryRunCheckWarn means the changefeed can be created,
+	// but the check item should be paid attention to
+	DryRunCheckWarn DryRunCheckStatus = "warn"
+	// DryRunCheckFail means the changefeed can not be created
+	DryRunCheckFail DryRunCheckStatus = "fail"
+	// DryRunCheckSkip means the check item is not applicable
+	DryRunCheckSkip DryRunCheckStatus = "skip"
+)
+
+// DryRunCheck is a check item of a dry run
+type DryRunCheck struct {
+	Name    string            `json:"name"`
+	Status  DryRunCheckStatus `json:"status"`
+	Message string            `json:"message,omitempty"`
+}
+
+// DryRunReport is the compatibility report of creating a changefeed
+// in dry run mode, nothing is persisted by a dry run.
+type DryRunReport struct {
+	Namespace        string        `json:"namespace"`
+	ID               string        `json:"id"`
+	StartTs          uint64        `json:"start_ts"`
+	EligibleTables   []TableName   `json:"eligible_tables"`
+	IneligibleTables []TableName   `json:"ineligible_tables"`
+	UnsupportedDDLs  []string      `json:"unsupported_ddls"`
+	Checks           []DryRunCheck `json:"checks"`
+	Passed           bool          `json:"passed"`
+}
+
+// AddCheck appends a check item to the report
+func (r *DryRunReport) AddCheck(name string, status DryRunCheckStatus, message string) {
+	r.Checks = append(r.Checks, DryRunCheck{
+		Name:    name,
+		Status:  status,
+		Message: message,
+	})
+	r.Passed = !r.HasStatus(DryRunCheckFail)
+}
+
+// HasStatus returns true if any check item of the report is in the status
+func (r *DryRunReport) HasStatus(status DryRunCheckStatus) bool {
+	for _, check := range r.Checks {
+		if check.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangefeedRevision is a revision of the info of a changefeed
+type ChangefeedRevision struct {
+	Revision   uint64          `json:"revision"`
+	UpdateTime time.Time       `json:"update_time"`
+	Reason     string          `json:"reason"`
+	Info       *ChangeFeedInfo `json:"info"`
+}
+
+// ChangefeedHistory is the latest revisions of the info of a changefeed,
+// the last one is the current revision
+type ChangefeedHistory struct {
+	Namespace string               `json:"namespace"`
+	ID        string               `json:"id"`
+	Revisions []ChangefeedRevision `json:"revisions"`
+}
+
+// ChangefeedConfigChange is a config item which is different between
+// two revisions of a changefeed, Path is the keys of the item in the
+// changefeed info joined by "."
+type ChangefeedConfigChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// ChangefeedHistoryDiff is the config changes from revision From to
+// revision To of a changefeed
+type ChangefeedHistoryDiff struct {
+	Namespace string                   `json:"namespace"`
+	ID        string                   `json:"id"`
+	From      uint64                   `json:"from"`
+	To        uint64                   `json:"to"`
+	Changes   []ChangefeedConfigChange `json:"changes"`
+}
+
+// RollbackChangefeedConfig is used by rollback changefeed api
+type RollbackChangefeedConfig struct {
+	Revision uint64 `json:"revision"`
+	Reason   string `json:"reason"`
+}
+
+// ColumnSchema is the schema of a column of a table
+type ColumnSchema struct {
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Nullable     bool    `json:"nullable"`
+	Default      *string `json:"default,omitempty"`
+	IsPrimaryKey bool    `json:"is_primary_key"`
+}
+
+// IndexSchema is the schema of an index of a table
+type IndexSchema struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// TableSchema is the schema of a table at a ts in the schema storage
+// of a changefeed
+type TableSchema struct {
+	Namespace        string         `json:"namespace"`
+	ID               string         `json:"id"`
+	Ts               uint64         `json:"ts"`
+	SchemaName       string         `json:"schema_name"`
+	TableName        string         `json:"table_name"`
+	TableID          int64          `json:"table_id"`
+	Columns          []ColumnSchema `json:"columns"`
+	Indexes          []IndexSchema  `json:"indexes"`
+	HandleKeyColumns []string       `json:"handle_key_columns"`
+}
+
+// TableDDLEvent is a DDL applied to a table, Ignored is true if the DDL
+// is filtered out by the changefeed
+type TableDDLEvent struct {
+	JobID      int64  `json:"job_id"`
+	CommitTs   uint64 `json:"commit_ts"`
+	Type       string `json:"type"`
+	Query      string `json:"query"`
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+	Ignored    bool   `json:"ignored"`
+}
+
+// TableDDLEvents is the DDLs applied to a table in (StartTs, EndTs]
+type TableDDLEvents struct {
+	Namespace  string          `json:"namespace"`
+	ID         string          `json:"id"`
+	SchemaName string          `json:"schema_name"`
+	TableName  string          `json:"table_name"`
+	StartTs    uint64          `json:"start_ts"`
+	EndTs      uint64          `json:"end_ts"`
+	Events     []TableDDLEvent `json:"events"`
+}
+
+// TableSnapshotProgress is the initial snapshot progress of a table
+type TableSnapshotProgress struct {
+	TableID    int64     `json:"table_id"`
+	TableName  string    `json:"table_name"`
+	Rows       uint64    `json:"rows"`
+	Done       bool      `json:"done"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// ChangefeedSnapshotProgress is the initial snapshot progress of the tables
+// in a changefeed, the tables whose snapshot has not been started are not
+// listed.
+type ChangefeedSnapshotProgress struct {
+	Namespace  string                  `json:"namespace"`
+	ID         string                  `json:"id"`
+	Enabled    bool                    `json:"enabled"`
+	SnapshotTs uint64                  `json:"snapshot_ts"`
+	Tables     []TableSnapshotProgress `json:"tables"`
+}
+
+// ResyncTableConfig is used by resync table api, the policy is one of
+// "truncate" and "overwrite", "truncate" is used if it is not specified.
+type ResyncTableConfig struct {
+	SchemaName string `json:"schema_name"`
+	TableName  string `json:"table_name"`
+	Policy     string `json:"policy"`
+}
+
+// StageLatency is the replication lag of events when they pass a stage,
+// the lag is measured from the physical time of the commit ts.
+type StageLatency struct {
+	Stage     string `json:"stage"`
+	Count     uint64 `json:"count"`
+	AvgLagMs  int64  `json:"avg_lag_ms"`
+	MaxLagMs  int64  `json:"max_lag_ms"`
+	LastLagMs int64  `json:"last_lag_ms"`
+}
+
+// TableLatency is the replication lag of a table at each stage.
+type TableLatency struct {
+	TableID   int64          `json:"table_id"`
+	TableName string         `json:"table_name"`
+	CaptureID string         `json:"capture_id"`
+	Stages    []StageLatency `json:"stages"`
+}
+
+// ChangefeedLatency is the replication lag of the tables in a changefeed.
+type ChangefeedLatency struct {
+	Namespace string         `json:"namespace"`
+	ID        string         `json:"id"`
+	Tables    []TableLatency `json:"tables"`
+}
+
+// Diagnosis finding severities
+const (
+	// DiagnosisSeverityCritical means the changefeed can not make progress.
+	DiagnosisSeverityCritical = "critical"
+	// DiagnosisSeverityWarning means the changefeed may be affected.
+	DiagnosisSeverityWarning = "warning"
+)
+
+// Diagnosis finding causes
+const (
+	DiagnosisCauseChangefeedError = "changefeed-error"
+	DiagnosisCauseSinkError       = "sink-error"
+	DiagnosisCauseGC              = "gc"
+	DiagnosisCauseRegionLock      = "region-lock"
+	DiagnosisCauseSink            = "sink"
+	DiagnosisCauseScheduling      = "scheduling"
+	DiagnosisCauseCapture         = "capture"
+)
+
+// DiagnosisFinding is a problem found when diagnosing a changefeed.
+type DiagnosisFinding struct {
+	Severity string `json:"severity"`
+	Cause    string `json:"cause"`
+	// TableID is the table which blocks the changefeed, it is 0 if the
+	// problem is not related to a table.
+	TableID int64  `json:"table_id,omitempty"`
+	Message string `json:"message"`
+}
+
+// ServiceSafePoint is a service GC safe point registered in PD
+type ServiceSafePoint struct {
+	ServiceID string `json:"service_id"`
+	ExpiredAt int64  `json:"expired_at"`
+	SafePoint uint64 `json:"safe_point"`
+}
+
+// CaptureDiagnosis is the information collected from a capture when
+// diagnosing a changefeed.
+type CaptureDiagnosis struct {
+	ID            string `json:"id"`
+	AdvertiseAddr string `json:"address"`
+	IsOwner       bool   `json:"is_owner"`
+	// DebugInfo is the debug info of the owner and processors of the capture
+	DebugInfo string `json:"debug_info"`
+	// Error is the error occurred when collecting information from the
+	// capture, the capture may be unreachable if it is not empty.
+	Error string `json:"error,omitempty"`
+}
+
+// ChangefeedDiagnosis is the health diagnosis of a changefeed, it contains
+// the information collected from the cluster and the problems found.
+type ChangefeedDiagnosis struct {
+	Namespace string `json:"namespace"`
+	ID        string `json:"id"`
+	// CurrentTs is the current ts of the upstream when diagnosing
+	CurrentTs uint64                         `json:"current_ts"`
+	Info      *model.ChangeFeedInfo          `json:"info"`
+	Status    *model.ChangeFeedStatus        `json:"status"`
+	Tables    []*model.TableReplicationState `json:"tables"`
+	Latency   []TableLatency                 `json:"latency"`
+	// GCServiceID is the service ID of TiCDC in PD
+	GCServiceID         string             `json:"gc_service_id"`
+	GCSafePoint         uint64             `json:"gc_safe_point"`
+	ServiceGCSafePoints []ServiceSafePoint `json:"service_gc_safe_points"`
+	Captures            []CaptureDiagnosis `json:"captures"`
+	// Etcd contains the etcd keys related to the changefeed
+	Etcd     []EtcdData         `json:"etcd"`
+	Findings []DiagnosisFinding `json:"findings"`
+	// CollectErrors are the errors occurred when collecting information,
+	// the diagnosis may be incomplete if it is not empty.
+	CollectErrors []string `json:"collect_errors"`
+}
+
+// ServerConfigReloadResult is the result of reloading the server config.
+type ServerConfigReloadResult struct {
+	// Applied are the changed items applied without restarting the server.
+	Applied []string `json:"applied"`
+	// AppliedToNewTables are the changed items applied to the tables added
+	// after the reload only, the running tables keep the old values.
+	AppliedToNewTables []string `json:"applied_to_new_tables"`
+	// RestartRequired are the changed items which take effect only after
+	// the server is restarted.
+	RestartRequired []string `json:"restart_required"`
+}
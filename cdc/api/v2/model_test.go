@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	parserModel "github.com/pingcap/tidb/parser/model"
@@ -47,6 +48,22 @@ func TestToAPIReplicaConfig(t *testing.T) {
 		},
 		SchemaRegistry: "bbb",
 		TxnAtomicity:   "aa",
+		TopicTemplates: []*config.TopicTemplate{
+			{
+				Topic:             "{schema}_{table}",
+				PartitionNum:      6,
+				ReplicationFactor: 3,
+				TopicConfigs:      map[string]string{"retention.ms": "86400000"},
+			},
+		},
+		EnablePartitionChangeMarker: true,
+		TypeRendering: &config.TypeRenderingConfig{
+			Decimal:         config.DecimalRenderingNumber,
+			TimestampZone:   config.TimestampZoneUTC,
+			TimestampFormat: config.TimestampFormatISO,
+			Binary:          config.BinaryRenderingHex,
+			Enum:            config.EnumRenderingValue,
+		},
 	}
 	cfg.Consistent = &config.ConsistentConfig{
 		Level:             "1",
@@ -54,6 +71,21 @@ func TestToAPIReplicaConfig(t *testing.T) {
 		FlushIntervalInMs: 10,
 		Storage:           "s3",
 	}
+	cfg.Retry = &config.RetryConfig{
+		MaxRetryDuration:    config.TomlDuration(time.Hour),
+		BackoffInitInterval: config.TomlDuration(time.Second),
+		BackoffMaxInterval:  config.TomlDuration(time.Minute),
+		AutoSkipDDLErrors:   []string{"Error 1050"},
+	}
+	cfg.RateLimit = &config.RateLimitConfig{
+		MaxRowsPerSecond:  1000,
+		MaxBytesPerSecond: 1024,
+		Tables: []*config.TableRateLimit{
+			{Matcher: []string{"test.*"}, MaxRowsPerSecond: 10},
+		},
+	}
+	cfg.Priority = config.ChangefeedPriorityHigh
+	cfg.EnableRowChecksum = true
 	cfg.Filter = &config.FilterConfig{
 		Rules: []string{"a", "b", "c"},
 		MySQLReplicationRules: &filter.MySQLReplicationRules{
@@ -0,0 +1,139 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// listNamespaces lists all namespaces, including the default namespace.
+func (h *OpenAPIV2) listNamespaces(c *gin.Context) {
+	ctx := c.Request.Context()
+	infos, err := h.capture.GetEtcdClient().GetAllNamespaceInfo(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := make([]*NamespaceInfo, 0, len(infos))
+	for _, info := range infos {
+		resp = append(resp, ToAPINamespaceInfo(info))
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Name < resp[j].Name
+	})
+	c.JSON(http.StatusOK, resp)
+}
+
+// createNamespace creates a namespace with its quotas.
+func (h *OpenAPIV2) createNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	cfg := &NamespaceInfo{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if err := verifyNamespaceInfo(cfg); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if cfg.Name == model.DefaultNamespace {
+		_ = c.Error(cerror.ErrNamespaceAlreadyExists.GenWithStackByArgs(cfg.Name))
+		return
+	}
+	info := cfg.ToInternalNamespaceInfo()
+	info.CreateTime = time.Now()
+	if err := h.capture.GetEtcdClient().CreateNamespace(ctx, info); err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Create namespace successfully!", zap.Any("namespace", info))
+	c.JSON(http.StatusCreated, ToAPINamespaceInfo(info))
+}
+
+// getNamespace returns the info of a namespace.
+func (h *OpenAPIV2) getNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param(apiOpVarNamespace)
+	info, err := h.capture.GetEtcdClient().GetNamespaceInfo(ctx, namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ToAPINamespaceInfo(info))
+}
+
+// updateNamespace updates the quotas of a namespace. Changefeeds exceeding the
+// new quotas are stopped by the owner.
+func (h *OpenAPIV2) updateNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param(apiOpVarNamespace)
+	oldInfo, err := h.capture.GetEtcdClient().GetNamespaceInfo(ctx, namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	cfg := ToAPINamespaceInfo(oldInfo)
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	// The name and the create time of a namespace can not be changed.
+	cfg.Name = oldInfo.Name
+	cfg.CreateTime = oldInfo.CreateTime
+	if err := verifyNamespaceInfo(cfg); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	info := cfg.ToInternalNamespaceInfo()
+	if err := h.capture.GetEtcdClient().UpdateNamespace(ctx, info); err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Update namespace successfully!",
+		zap.Any("oldNamespace", oldInfo), zap.Any("namespace", info))
+	c.JSON(http.StatusOK, ToAPINamespaceInfo(info))
+}
+
+// deleteNamespace deletes an empty namespace.
+func (h *OpenAPIV2) deleteNamespace(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := c.Param(apiOpVarNamespace)
+	if err := h.capture.GetEtcdClient().DeleteNamespace(ctx, namespace); err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Delete namespace successfully!", zap.String("namespace", namespace))
+	c.Status(http.StatusOK)
+}
+
+func verifyNamespaceInfo(info *NamespaceInfo) error {
+	if err := model.ValidateNamespace(info.Name); err != nil {
+		return cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid namespace: %s", info.Name)
+	}
+	if info.ChangefeedQuota < 0 {
+		return cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid changefeed quota: %d", info.ChangefeedQuota)
+	}
+	return nil
+}
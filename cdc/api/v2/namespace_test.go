@@ -0,0 +1,193 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateNamespace(t *testing.T) {
+	t.Parallel()
+	create := testCase{url: "/api/v2/namespaces", method: "POST"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	// case 1: invalid namespace name
+	body, err := json.Marshal(&NamespaceInfo{Name: "#invalid"})
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 2: namespace already exists
+	etcdClient.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).
+		Return(cerrors.ErrNamespaceAlreadyExists.GenWithStackByArgs("tenant")).Times(1)
+	body, err = json.Marshal(&NamespaceInfo{Name: "tenant", ChangefeedQuota: 2})
+	require.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrNamespaceAlreadyExists")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 3: success
+	etcdClient.EXPECT().CreateNamespace(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, info *model.NamespaceInfo) error {
+			require.Equal(t, "tenant", info.Name)
+			require.Equal(t, 2, info.ChangefeedQuota)
+			require.False(t, info.CreateTime.IsZero())
+			return nil
+		}).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	resp := NamespaceInfo{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "tenant", resp.Name)
+}
+
+func TestListAndGetNamespace(t *testing.T) {
+	t.Parallel()
+	list := testCase{url: "/api/v2/namespaces", method: "GET"}
+	get := testCase{url: "/api/v2/namespaces/%s", method: "GET"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	etcdClient.EXPECT().GetAllNamespaceInfo(gomock.Any()).
+		Return(map[string]*model.NamespaceInfo{
+			"tenant":               {Name: "tenant", MemoryQuota: 1024},
+			model.DefaultNamespace: model.NewDefaultNamespaceInfo(),
+		}, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var infos []NamespaceInfo
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&infos))
+	require.Len(t, infos, 2)
+	require.Equal(t, model.DefaultNamespace, infos[0].Name)
+	require.Equal(t, uint64(1024), infos[1].MemoryQuota)
+
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "not-exist").
+		Return(nil, cerrors.ErrNamespaceNotExists.GenWithStackByArgs("not-exist")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		get.method, fmt.Sprintf(get.url, "not-exist"), nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrNamespaceNotExists")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateNamespace(t *testing.T) {
+	t.Parallel()
+	update := testCase{url: "/api/v2/namespaces/%s", method: "PUT"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "tenant").
+		Return(&model.NamespaceInfo{Name: "tenant", ChangefeedQuota: 1}, nil).AnyTimes()
+	etcdClient.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, info *model.NamespaceInfo) error {
+			// The name can not be changed, and unspecified quotas are kept.
+			require.Equal(t, "tenant", info.Name)
+			require.Equal(t, 1, info.ChangefeedQuota)
+			require.Equal(t, uint64(2048), info.MemoryQuota)
+			return nil
+		}).Times(1)
+	body := []byte(`{"name":"other","memory_quota":2048}`)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		update.method, fmt.Sprintf(update.url, "tenant"), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// invalid quota
+	body = []byte(`{"changefeed_quota":-1}`)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		update.method, fmt.Sprintf(update.url, "tenant"), bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	t.Parallel()
+	remove := testCase{url: "/api/v2/namespaces/%s", method: "DELETE"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	etcdClient.EXPECT().DeleteNamespace(gomock.Any(), "tenant").
+		Return(cerrors.ErrNamespaceNotEmpty.GenWithStackByArgs("tenant", 1)).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		remove.method, fmt.Sprintf(remove.url, "tenant"), nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrNamespaceNotEmpty")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	etcdClient.EXPECT().DeleteNamespace(gomock.Any(), "tenant").Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		remove.method, fmt.Sprintf(remove.url, "tenant"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
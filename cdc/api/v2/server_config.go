@@ -0,0 +1,37 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+// reloadServerConfig reloads the config file of the server which receives
+// the request, and reports the changed items which need a restart.
+// The request is not forwarded to the owner, every server has its own config.
+func (h *OpenAPIV2) reloadServerConfig(c *gin.Context) {
+	result, err := config.ReloadGlobalServerConfig()
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, &ServerConfigReloadResult{
+		Applied:            result.Applied,
+		AppliedToNewTables: result.AppliedToNewTables,
+		RestartRequired:    result.RestartRequired,
+	})
+}
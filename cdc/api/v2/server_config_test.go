@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadServerConfig(t *testing.T) {
+	oldCfg := config.GetGlobalServerConfig()
+	defer func() {
+		config.StoreGlobalServerConfig(oldCfg)
+		config.SetServerConfigLoader(nil)
+	}()
+	config.StoreGlobalServerConfig(config.GetDefaultServerConfig())
+
+	reload := testCase{url: "/api/v2/config/reload", method: "POST"}
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	apiV2 := NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t)))
+	router := newRouter(apiV2)
+
+	// case 1: the config can not be reloaded
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		reload.method, reload.url, nil)
+	router.ServeHTTP(w, req)
+	require.NotEqual(t, http.StatusOK, w.Code)
+	respErr := model.HTTPError{}
+	err := json.NewDecoder(w.Body).Decode(&respErr)
+	require.Nil(t, err)
+	require.Contains(t, respErr.Code, "ErrReloadServerConfig")
+
+	// case 2: reload successfully
+	config.SetServerConfigLoader(func() (*config.ServerConfig, error) {
+		cfg := config.GetDefaultServerConfig()
+		cfg.LogLevel = "warn"
+		cfg.GcTTL = 100
+		cfg.KVClient.WorkerConcurrent = 16
+		return cfg, nil
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		reload.method, reload.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp := ServerConfigReloadResult{}
+	err = json.NewDecoder(w.Body).Decode(&resp)
+	require.Nil(t, err)
+	require.Equal(t, []string{"log-level"}, resp.Applied)
+	require.Equal(t, []string{"kv-client.worker-concurrent"}, resp.AppliedToNewTables)
+	require.Equal(t, []string{"gc-ttl"}, resp.RestartRequired)
+	require.Equal(t, "warn", config.GetGlobalServerConfig().LogLevel)
+}
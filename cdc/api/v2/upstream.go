@@ -0,0 +1,141 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const apiOpVarUpstreamID = "upstream_id"
+
+// listUpstreams lists all upstreams registered in a namespace.
+func (h *OpenAPIV2) listUpstreams(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	infos, err := h.capture.GetEtcdClient().GetAllUpstreamInfo(ctx, namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	resp := make([]*UpstreamConfig, 0, len(infos))
+	for _, info := range infos {
+		resp = append(resp, ToAPIUpstreamConfig(info))
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].ID < resp[j].ID
+	})
+	c.JSON(http.StatusOK, resp)
+}
+
+// createUpstream registers an upstream TiDB cluster in a namespace, the ID of
+// the upstream is the cluster ID reported by its PD.
+func (h *OpenAPIV2) createUpstream(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	cfg := &UpstreamConfig{}
+	if err := c.BindJSON(cfg); err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIInvalidParam, err))
+		return
+	}
+	if len(cfg.PDAddrs) == 0 {
+		_ = c.Error(cerror.ErrAPIInvalidParam.GenWithStack(
+			"pd_addrs of the upstream must be specified"))
+		return
+	}
+	if _, err := h.capture.GetEtcdClient().GetNamespaceInfo(ctx, namespace); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	pdClient, err := h.helpers.getPDClient(timeoutCtx, cfg.PDAddrs, cfg.toCredential())
+	if err != nil {
+		_ = c.Error(cerror.WrapError(cerror.ErrAPIGetPDClientFailed, err))
+		return
+	}
+	defer pdClient.Close()
+	clusterID := pdClient.GetClusterID(ctx)
+	if cfg.ID != 0 && cfg.ID != clusterID {
+		_ = c.Error(cerror.ErrUpstreamMissMatch.GenWithStackByArgs(cfg.ID, clusterID))
+		return
+	}
+	cfg.ID = clusterID
+
+	info := cfg.ToInternalUpstreamInfo()
+	if err := h.capture.GetEtcdClient().CreateUpstreamInfo(ctx, info, namespace); err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Create upstream successfully!",
+		zap.String("namespace", namespace), zap.Any("upstream", info))
+	c.JSON(http.StatusCreated, ToAPIUpstreamConfig(info))
+}
+
+// getUpstream returns the info of a registered upstream.
+func (h *OpenAPIV2) getUpstream(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	upstreamID, err := parseUpstreamID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	info, err := h.capture.GetEtcdClient().GetUpstreamInfo(ctx, upstreamID, namespace)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, ToAPIUpstreamConfig(info))
+}
+
+// deleteUpstream removes an upstream that is not used by any changefeed.
+func (h *OpenAPIV2) deleteUpstream(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace := getNamespaceValueWithDefault(c)
+	upstreamID, err := parseUpstreamID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	err = h.capture.GetEtcdClient().DeleteUpstreamInfo(ctx, upstreamID, namespace)
+	if err != nil {
+		_ = c.Error(errors.Trace(err))
+		return
+	}
+	log.Info("Delete upstream successfully!",
+		zap.String("namespace", namespace), zap.Uint64("upstreamID", upstreamID))
+	c.Status(http.StatusOK)
+}
+
+func parseUpstreamID(c *gin.Context) (model.UpstreamID, error) {
+	param := c.Param(apiOpVarUpstreamID)
+	upstreamID, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return 0, cerror.ErrAPIInvalidParam.GenWithStack(
+			"invalid upstream id: %s", param)
+	}
+	return upstreamID, nil
+}
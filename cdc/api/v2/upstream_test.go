@@ -0,0 +1,196 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_capture "github.com/pingcap/tiflow/cdc/capture/mock"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	mock_etcd "github.com/pingcap/tiflow/pkg/etcd/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUpstream(t *testing.T) {
+	t.Parallel()
+	create := testCase{url: "/api/v2/upstreams?namespace=tenant", method: "POST"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	helpers := NewMockAPIV2Helpers(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	etcdClient.EXPECT().GetNamespaceInfo(gomock.Any(), "tenant").
+		Return(&model.NamespaceInfo{Name: "tenant"}, nil).AnyTimes()
+	helpers.EXPECT().getPDClient(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&mockPDClient{}, nil).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, helpers))
+
+	// case 1: pd addresses are not specified
+	body, err := json.Marshal(&UpstreamConfig{})
+	require.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 2: the specified id mismatches the cluster id
+	cfg := &UpstreamConfig{
+		ID: 1,
+		PDConfig: PDConfig{
+			PDAddrs: []string{"http://127.0.0.1:2379"},
+			CAPath:  "ca.pem",
+		},
+	}
+	body, err = json.Marshal(cfg)
+	require.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrUpstreamMissMatch")
+
+	// case 3: the upstream already exists
+	cfg.ID = 0
+	body, err = json.Marshal(cfg)
+	require.Nil(t, err)
+	etcdClient.EXPECT().CreateUpstreamInfo(gomock.Any(), gomock.Any(), "tenant").
+		Return(cerrors.ErrUpstreamAlreadyExists.GenWithStackByArgs(123, "tenant")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	respErr = model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrUpstreamAlreadyExists")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// case 4: success, the id is the cluster id of the upstream
+	etcdClient.EXPECT().CreateUpstreamInfo(gomock.Any(), gomock.Any(), "tenant").
+		DoAndReturn(func(_ context.Context, info *model.UpstreamInfo, _ string) error {
+			require.Equal(t, &model.UpstreamInfo{
+				ID:          123,
+				PDEndpoints: "http://127.0.0.1:2379",
+				CAPath:      "ca.pem",
+			}, info)
+			return nil
+		}).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		create.method, create.url, bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	resp := &UpstreamConfig{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(resp))
+	require.Equal(t, uint64(123), resp.ID)
+	require.Equal(t, cfg.PDAddrs, resp.PDAddrs)
+}
+
+func TestListAndGetUpstream(t *testing.T) {
+	t.Parallel()
+	list := testCase{url: "/api/v2/upstreams", method: "GET"}
+	get := testCase{url: "/api/v2/upstreams/%s", method: "GET"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	etcdClient.EXPECT().GetAllUpstreamInfo(gomock.Any(), model.DefaultNamespace).
+		Return(map[model.UpstreamID]*model.UpstreamInfo{
+			3: {ID: 3, PDEndpoints: "http://127.0.0.3:2379"},
+			1: {ID: 1, PDEndpoints: "http://127.0.0.1:2379,http://127.0.0.2:2379"},
+		}, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), list.method, list.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp []*UpstreamConfig
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	require.Equal(t, uint64(1), resp[0].ID)
+	require.Equal(t, []string{"http://127.0.0.1:2379", "http://127.0.0.2:2379"},
+		resp[0].PDAddrs)
+	require.Equal(t, uint64(3), resp[1].ID)
+
+	// invalid upstream id
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), get.method,
+		fmt.Sprintf(get.url, "abc"), nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrAPIInvalidParam")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	etcdClient.EXPECT().GetUpstreamInfo(gomock.Any(), uint64(3), model.DefaultNamespace).
+		Return(&model.UpstreamInfo{ID: 3, PDEndpoints: "http://127.0.0.3:2379"}, nil).
+		Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(), get.method,
+		fmt.Sprintf(get.url, "3"), nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	info := &UpstreamConfig{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(info))
+	require.Equal(t, uint64(3), info.ID)
+}
+
+func TestDeleteUpstream(t *testing.T) {
+	t.Parallel()
+	remove := testCase{url: "/api/v2/upstreams/3?namespace=tenant", method: "DELETE"}
+
+	cp := mock_capture.NewMockCapture(gomock.NewController(t))
+	etcdClient := mock_etcd.NewMockCDCEtcdClientForAPI(gomock.NewController(t))
+	cp.EXPECT().IsReady().Return(true).AnyTimes()
+	cp.EXPECT().IsOwner().Return(true).AnyTimes()
+	cp.EXPECT().GetEtcdClient().Return(etcdClient).AnyTimes()
+	router := newRouter(NewOpenAPIV2ForTest(cp, NewMockAPIV2Helpers(gomock.NewController(t))))
+
+	etcdClient.EXPECT().DeleteUpstreamInfo(gomock.Any(), uint64(3), "tenant").
+		Return(cerrors.ErrUpstreamInUse.GenWithStackByArgs(3, "test")).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(),
+		remove.method, remove.url, nil)
+	router.ServeHTTP(w, req)
+	respErr := model.HTTPError{}
+	require.Nil(t, json.NewDecoder(w.Body).Decode(&respErr))
+	require.Contains(t, respErr.Code, "ErrUpstreamInUse")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	etcdClient.EXPECT().DeleteUpstreamInfo(gomock.Any(), uint64(3), "tenant").
+		Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequestWithContext(context.Background(),
+		remove.method, remove.url, nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+}
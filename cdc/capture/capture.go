@@ -33,9 +33,11 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
+	lsorter "github.com/pingcap/tiflow/cdc/sorter/leveldb"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
@@ -90,6 +92,9 @@ type captureImpl struct {
 	EtcdClient       *etcd.CDCEtcdClient
 	sorterSystem     *ssystem.System
 	tableActorSystem *system.System
+	// unregisterDiskQuotaHook unregisters the hook applying the reloaded
+	// sorter disk quota, it is nil if the disk quota is disabled.
+	unregisterDiskQuotaHook func()
 
 	// MessageServer is the receiver of the messages from the other nodes.
 	// It should be recreated each time the capture is restarted.
@@ -98,6 +103,11 @@ type captureImpl struct {
 	// MessageRouter manages the clients to send messages to all peers.
 	MessageRouter p2p.MessageRouter
 
+	// notifier posts the events to the webhooks. It lasts for the whole
+	// life of the server, so that the undelivered events are kept when
+	// the capture is restarted.
+	notifier *notification.Notifier
+
 	// grpcService is a wrapper that can hold a MessageServer.
 	// The instance should last for the whole life of the server,
 	// regardless of server restarting.
@@ -130,6 +140,7 @@ func NewCapture(pdEndpoints []string,
 		pdEndpoints:         pdEndpoints,
 		newProcessorManager: processor.NewManager,
 		newOwner:            owner.NewOwner,
+		notifier:            notification.NewNotifier(conf.Notification, etcdClient.ClusterID, etcdClient),
 
 		migrator: migrate.NewMigrator(etcdClient, pdEndpoints, conf),
 	}
@@ -224,11 +235,20 @@ func (c *captureImpl) reset(ctx context.Context) error {
 				log.Warn("stop sorter system failed", zap.Error(err))
 			}
 		}
+		c.stopDiskQuotaHook()
 		// Sorter dir has been set and checked when server starts.
 		// See https://github.com/pingcap/tiflow/blob/9dad09/cdc/server.go#L275
-		sortDir := config.GetGlobalServerConfig().Sorter.SortDir
-		memPercentage := float64(config.GetGlobalServerConfig().Sorter.MaxMemoryPercentage) / 100
-		c.sorterSystem = ssystem.NewSystem(sortDir, memPercentage, conf.Debug.DB)
+		sorterConf := config.GetGlobalServerConfig().Sorter
+		sortDirs := append([]string{sorterConf.SortDir}, sorterConf.ExtraSortDirs...)
+		memPercentage := float64(sorterConf.MaxMemoryPercentage) / 100
+		var diskQuota *lsorter.DiskQuota
+		if sorterConf.DiskQuota > 0 {
+			diskQuota = lsorter.NewDiskQuota(sorterConf.DiskQuota, sorterConf.DiskQuotaPolicy)
+			c.unregisterDiskQuotaHook = config.OnServerConfigReloaded(func(cfg *config.ServerConfig) {
+				diskQuota.SetQuota(cfg.Sorter.DiskQuota, cfg.Sorter.DiskQuotaPolicy)
+			})
+		}
+		c.sorterSystem = ssystem.NewSystem(sortDirs, memPercentage, conf.Debug.DB, diskQuota)
 		err = c.sorterSystem.Start(ctx)
 		if err != nil {
 			return errors.Annotate(
@@ -330,6 +350,7 @@ func (c *captureImpl) run(stdCtx context.Context) error {
 		SorterSystem:     c.sorterSystem,
 		MessageServer:    c.MessageServer,
 		MessageRouter:    c.MessageRouter,
+		Notifier:         c.notifier,
 	})
 
 	g.Go(func() error {
@@ -368,6 +389,10 @@ func (c *captureImpl) run(stdCtx context.Context) error {
 		return c.MessageServer.Run(ctx)
 	})
 
+	g.Go(func() error {
+		return c.notifier.Run(ctx)
+	})
+
 	err = g.Wait()
 	if err != nil {
 		return errors.Annotate(err, "capture exited")
@@ -443,6 +468,12 @@ func (c *captureImpl) campaignOwner(ctx cdcContext.Context) error {
 		log.Info("campaign owner successfully",
 			zap.String("captureID", c.info.ID),
 			zap.Int64("ownerRev", ownerRev))
+		// Deliver the events which the previous owner had not delivered.
+		if err := c.notifier.Load(ctx); err != nil {
+			log.Warn("failed to load pending notification events",
+				zap.String("captureID", c.info.ID), zap.Error(err))
+		}
+		c.notifier.Notify(notification.NewOwnerChangedEvent(c.info))
 
 		owner := c.newOwner(c.upstreamManager)
 		c.setOwner(owner)
@@ -603,6 +634,7 @@ func (c *captureImpl) AsyncClose() {
 		}
 		c.sorterSystem = nil
 	}
+	c.stopDiskQuotaHook()
 	log.Info("sorter actor system closed", zap.String("captureID", c.info.ID))
 
 	c.grpcService.Reset(nil)
@@ -704,6 +736,13 @@ func (c *captureImpl) Liveness() model.Liveness {
 	return c.liveness.Load()
 }
 
+func (c *captureImpl) stopDiskQuotaHook() {
+	if c.unregisterDiskQuotaHook != nil {
+		c.unregisterDiskQuotaHook()
+		c.unregisterDiskQuotaHook = nil
+	}
+}
+
 // WriteDebugInfo writes the debug info into writer.
 func (c *captureImpl) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	wait := func(done <-chan error) {
@@ -740,6 +779,11 @@ func (c *captureImpl) WriteDebugInfo(ctx context.Context, w io.Writer) {
 	c.captureMu.Unlock()
 	// wait the debug info printed
 	wait(doneM)
+
+	if c.notifier != nil {
+		fmt.Fprintf(w, "\n\n*** notification info ***:\n\n")
+		c.notifier.WriteDebugInfo(w)
+	}
 }
 
 // IsOwner returns whether the capture is an owner
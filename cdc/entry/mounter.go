@@ -71,6 +71,7 @@ type mounterImpl struct {
 	schemaStorage                SchemaStorage
 	tz                           *time.Location
 	enableOldValue               bool
+	enableRowChecksum            bool
 	changefeedID                 model.ChangeFeedID
 	filter                       pfilter.Filter
 	metricMountDuration          prometheus.Observer
@@ -84,12 +85,14 @@ func NewMounter(schemaStorage SchemaStorage,
 	tz *time.Location,
 	filter pfilter.Filter,
 	enableOldValue bool,
+	enableRowChecksum bool,
 ) Mounter {
 	return &mounterImpl{
-		schemaStorage:  schemaStorage,
-		changefeedID:   changefeedID,
-		enableOldValue: enableOldValue,
-		filter:         filter,
+		schemaStorage:     schemaStorage,
+		changefeedID:      changefeedID,
+		enableOldValue:    enableOldValue,
+		enableRowChecksum: enableRowChecksum,
+		filter:            filter,
 		metricMountDuration: mountDuration.
 			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricTotalRows: totalRowsCountGauge.
@@ -390,7 +393,7 @@ func (m *mounterImpl) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntr
 
 	rawRow.PreRowDatums = preRawCols
 	rawRow.RowDatums = rawCols
-	return &model.RowChangedEvent{
+	event := &model.RowChangedEvent{
 		StartTs:          row.StartTs,
 		CommitTs:         row.CRTs,
 		RowID:            intRowID,
@@ -406,7 +409,12 @@ func (m *mounterImpl) mountRowKVEntry(tableInfo *model.TableInfo, row *rowKVEntr
 		PreColumns:          preCols,
 		IndexColumns:        tableInfo.IndexColumnsOffset,
 		ApproximateDataSize: dataSize,
-	}, rawRow, nil
+	}
+	if m.enableRowChecksum {
+		checksum := model.CalcRowChecksum(event.ChecksumColumns())
+		event.Checksum = &checksum
+	}
+	return event, rawRow, nil
 }
 
 var emptyBytes = make([]byte, 0)
@@ -296,7 +296,7 @@ func testMounterDisableOldValue(t *testing.T, tc struct {
 	require.Nil(t, err)
 	mounter := NewMounter(scheamStorage,
 		model.DefaultChangeFeedID("c1"),
-		time.UTC, filter, false).(*mounterImpl)
+		time.UTC, filter, false, true).(*mounterImpl)
 	mounter.tz = time.Local
 	ctx := context.Background()
 
@@ -313,6 +313,8 @@ func testMounterDisableOldValue(t *testing.T, tc struct {
 			rows++
 			require.Equal(t, row.Table.Table, tc.tableName)
 			require.Equal(t, row.Table.Schema, "test")
+			require.NotNil(t, row.Checksum)
+			require.Equal(t, model.CalcRowChecksum(row.ChecksumColumns()), *row.Checksum)
 			// [TODO] check size and reopen this check
 			// require.Equal(t, rowBytes[rows-1], row.ApproximateBytes(), row)
 			t.Log("ApproximateBytes", tc.tableName, rows-1, row.ApproximateBytes())
@@ -999,7 +1001,7 @@ func TestDecodeEventIgnoreRow(t *testing.T) {
 
 	ts := schemaStorage.GetLastSnapshot().CurrentTs()
 	schemaStorage.AdvanceResolvedTs(ver.Ver)
-	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, true).(*mounterImpl)
+	mounter := NewMounter(schemaStorage, cfID, time.Local, filter, true, false).(*mounterImpl)
 
 	type testCase struct {
 		schema  string
@@ -276,6 +276,9 @@ func (info *ChangeFeedInfo) VerifyAndComplete() error {
 	if info.Config.Consistent == nil {
 		info.Config.Consistent = defaultConfig.Consistent
 	}
+	if info.Config.Retry == nil {
+		info.Config.Retry = defaultConfig.Retry
+	}
 
 	return nil
 }
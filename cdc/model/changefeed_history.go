@@ -0,0 +1,207 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// MaxChangeFeedRevisions is the max number of revisions kept in
+// the history of a changefeed, the oldest ones are dropped first.
+const MaxChangeFeedRevisions = 16
+
+// changeFeedInfoRuntimeFields are the json keys of the fields in
+// ChangeFeedInfo which are maintained by TiCDC rather than configured by
+// users, they are neither compared nor rolled back between revisions.
+var changeFeedInfoRuntimeFields = []string{
+	"upstream-id", "namespace", "changefeed-id", "create-time", "start-ts",
+	"admin-job-type", "state", "error", "creator-version",
+}
+
+// ChangeFeedRevision is a revision of the info of a changefeed.
+type ChangeFeedRevision struct {
+	Revision   uint64          `json:"revision"`
+	UpdateTime time.Time       `json:"update-time"`
+	Reason     string          `json:"reason"`
+	Info       *ChangeFeedInfo `json:"info"`
+}
+
+// ChangeFeedHistory stores the latest revisions of the info of a
+// changefeed in ascending order, the last one is the current revision.
+type ChangeFeedHistory struct {
+	Revisions []*ChangeFeedRevision `json:"revisions"`
+}
+
+// Current returns the current revision, it returns nil if
+// the history is empty.
+func (h *ChangeFeedHistory) Current() *ChangeFeedRevision {
+	if len(h.Revisions) == 0 {
+		return nil
+	}
+	return h.Revisions[len(h.Revisions)-1]
+}
+
+// AddRevision appends a copy of info as the current revision and drops
+// the oldest revisions if there are more than MaxChangeFeedRevisions.
+func (h *ChangeFeedHistory) AddRevision(
+	info *ChangeFeedInfo, reason string, updateTime time.Time,
+) error {
+	cloned, err := info.Clone()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	revision := uint64(1)
+	if current := h.Current(); current != nil {
+		revision = current.Revision + 1
+	}
+	h.Revisions = append(h.Revisions, &ChangeFeedRevision{
+		Revision:   revision,
+		UpdateTime: updateTime,
+		Reason:     reason,
+		Info:       cloned,
+	})
+	if len(h.Revisions) > MaxChangeFeedRevisions {
+		h.Revisions = h.Revisions[len(h.Revisions)-MaxChangeFeedRevisions:]
+	}
+	return nil
+}
+
+// GetRevision returns the given revision of the history.
+func (h *ChangeFeedHistory) GetRevision(
+	id ChangeFeedID, revision uint64,
+) (*ChangeFeedRevision, error) {
+	for _, r := range h.Revisions {
+		if r.Revision == revision {
+			return r, nil
+		}
+	}
+	return nil, cerror.ErrChangefeedRevisionNotFound.GenWithStackByArgs(id, revision)
+}
+
+// Marshal using json.Marshal.
+func (h *ChangeFeedHistory) Marshal() ([]byte, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// Unmarshal from binary data.
+func (h *ChangeFeedHistory) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, h)
+	return errors.Annotatef(cerror.WrapError(cerror.ErrUnmarshalFailed, err),
+		"unmarshal data: %v", data)
+}
+
+// ChangeFeedInfoChange is a config item of ChangeFeedInfo which is
+// different between two revisions. Path is the json keys of the item
+// joined by ".", e.g. "config.filter.rules".
+type ChangeFeedInfoChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// DiffChangeFeedInfo returns the changed config items from oldInfo to
+// newInfo, ordered by their paths.
+func DiffChangeFeedInfo(oldInfo, newInfo *ChangeFeedInfo) ([]ChangeFeedInfoChange, error) {
+	oldFields, err := changeFeedInfoConfigFields(oldInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	newFields, err := changeFeedInfoConfigFields(newInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes := make([]ChangeFeedInfoChange, 0)
+	diffFields("", oldFields, newFields, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// RollbackTo returns a copy of info whose configs are replaced by the
+// configs of target, the runtime fields are kept unchanged.
+func (info *ChangeFeedInfo) RollbackTo(target *ChangeFeedInfo) (*ChangeFeedInfo, error) {
+	rolledBack, err := target.Clone()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rolledBack.UpstreamID = info.UpstreamID
+	rolledBack.Namespace = info.Namespace
+	rolledBack.ID = info.ID
+	rolledBack.CreateTime = info.CreateTime
+	rolledBack.StartTs = info.StartTs
+	rolledBack.AdminJobType = info.AdminJobType
+	rolledBack.State = info.State
+	rolledBack.Error = info.Error
+	rolledBack.CreatorVersion = info.CreatorVersion
+	return rolledBack, nil
+}
+
+func changeFeedInfoConfigFields(info *ChangeFeedInfo) (map[string]interface{}, error) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+	}
+	for _, key := range changeFeedInfoRuntimeFields {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+func diffFields(
+	prefix string, oldFields, newFields map[string]interface{},
+	changes *[]ChangeFeedInfoChange,
+) {
+	keys := make(map[string]struct{}, len(oldFields)+len(newFields))
+	for key := range oldFields {
+		keys[key] = struct{}{}
+	}
+	for key := range newFields {
+		keys[key] = struct{}{}
+	}
+	for key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		oldValue, newValue := oldFields[key], newFields[key]
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffFields(path, oldMap, newMap, changes)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			*changes = append(*changes, ChangeFeedInfoChange{
+				Path: path,
+				Old:  oldValue,
+				New:  newValue,
+			})
+		}
+	}
+}
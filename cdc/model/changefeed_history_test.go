@@ -0,0 +1,120 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeFeedHistory(t *testing.T) {
+	t.Parallel()
+
+	id := DefaultChangeFeedID("test")
+	history := &ChangeFeedHistory{}
+	require.Nil(t, history.Current())
+
+	info := &ChangeFeedInfo{SinkURI: "blackhole://", Config: config.GetDefaultReplicaConfig()}
+	now := time.Now()
+	for i := 0; i < MaxChangeFeedRevisions+2; i++ {
+		info.TargetTs = uint64(i)
+		require.Nil(t, history.AddRevision(info, "update", now))
+	}
+	require.Len(t, history.Revisions, MaxChangeFeedRevisions)
+	require.Equal(t, uint64(MaxChangeFeedRevisions+2), history.Current().Revision)
+	require.Equal(t, uint64(MaxChangeFeedRevisions+1), history.Current().Info.TargetTs)
+	// revisions are copied
+	info.TargetTs = 100
+	require.Equal(t, uint64(MaxChangeFeedRevisions+1), history.Current().Info.TargetTs)
+
+	_, err := history.GetRevision(id, 1)
+	require.True(t, cerror.ErrChangefeedRevisionNotFound.Equal(err))
+	revision, err := history.GetRevision(id, 3)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), revision.Info.TargetTs)
+
+	data, err := history.Marshal()
+	require.Nil(t, err)
+	unmarshaled := &ChangeFeedHistory{}
+	require.Nil(t, unmarshaled.Unmarshal(data))
+	require.Len(t, unmarshaled.Revisions, MaxChangeFeedRevisions)
+	require.Equal(t, "update", unmarshaled.Current().Reason)
+	require.True(t, now.Equal(unmarshaled.Current().UpdateTime))
+}
+
+func TestDiffChangeFeedInfo(t *testing.T) {
+	t.Parallel()
+
+	oldInfo := &ChangeFeedInfo{
+		ID:      "test",
+		SinkURI: "blackhole://",
+		State:   StateNormal,
+		Config:  config.GetDefaultReplicaConfig(),
+	}
+	newInfo, err := oldInfo.Clone()
+	require.Nil(t, err)
+	changes, err := DiffChangeFeedInfo(oldInfo, newInfo)
+	require.Nil(t, err)
+	require.Empty(t, changes)
+
+	// runtime fields are ignored
+	newInfo.State = StateStopped
+	newInfo.AdminJobType = AdminStop
+	newInfo.SinkURI = "mysql://127.0.0.1:3306/"
+	newInfo.Config.Filter.Rules = []string{"test.*"}
+	newInfo.Config.EnableOldValue = false
+	changes, err = DiffChangeFeedInfo(oldInfo, newInfo)
+	require.Nil(t, err)
+	require.Equal(t, []ChangeFeedInfoChange{
+		{Path: "config.enable-old-value", Old: true, New: false},
+		{
+			Path: "config.filter.rules",
+			Old:  []interface{}{"*.*"},
+			New:  []interface{}{"test.*"},
+		},
+		{Path: "sink-uri", Old: "blackhole://", New: "mysql://127.0.0.1:3306/"},
+	}, changes)
+}
+
+func TestChangeFeedInfoRollbackTo(t *testing.T) {
+	t.Parallel()
+
+	target := &ChangeFeedInfo{
+		ID:       "test",
+		SinkURI:  "blackhole://",
+		TargetTs: 10,
+		State:    StateNormal,
+		Config:   config.GetDefaultReplicaConfig(),
+	}
+	current, err := target.Clone()
+	require.Nil(t, err)
+	current.SinkURI = "mysql://127.0.0.1:3306/"
+	current.TargetTs = 20
+	current.State = StateStopped
+	current.AdminJobType = AdminStop
+
+	rolledBack, err := current.RollbackTo(target)
+	require.Nil(t, err)
+	require.Equal(t, "blackhole://", rolledBack.SinkURI)
+	require.Equal(t, uint64(10), rolledBack.TargetTs)
+	require.Equal(t, StateStopped, rolledBack.State)
+	require.Equal(t, AdminStop, rolledBack.AdminJobType)
+	changes, err := DiffChangeFeedInfo(target, rolledBack)
+	require.Nil(t, err)
+	require.Empty(t, changes)
+}
@@ -14,8 +14,9 @@
 package model
 
 import (
-	"errors"
+	"time"
 
+	"github.com/pingcap/errors"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
@@ -30,3 +31,22 @@ type RunningError struct {
 func (r RunningError) IsChangefeedUnRetryableError() bool {
 	return cerror.IsChangefeedUnRetryableError(errors.New(r.Message + r.Code))
 }
+
+// ErrorClass returns the class of the running error.
+func (r RunningError) ErrorClass() cerror.ErrorClass {
+	if cerror.IsChangefeedFastFailErrorCode(errors.RFCErrorCode(r.Code)) {
+		return cerror.ErrorClassFatal
+	}
+	if r.IsChangefeedUnRetryableError() {
+		return cerror.ErrorClassOperatorAction
+	}
+	return cerror.ErrorClassRetryable
+}
+
+// RecordedError is an error occurred in a changefeed, it is recorded in the
+// changefeed status along with its class and the time it occurred.
+type RecordedError struct {
+	RunningError
+	Class cerror.ErrorClass `json:"class"`
+	Time  time.Time         `json:"time"`
+}
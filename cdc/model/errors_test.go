@@ -55,3 +55,36 @@ func TestIsChangefeedNotRetryError(t *testing.T) {
 		require.Equal(t, c.result, c.err.IsChangefeedUnRetryableError())
 	}
 }
+
+func TestRunningErrorClass(t *testing.T) {
+	cases := []struct {
+		err   RunningError
+		class cerror.ErrorClass
+	}{
+		{
+			RunningError{
+				Code:    string(cerror.ErrMySQLTxnError.RFCCode()),
+				Message: "connection refused",
+			},
+			cerror.ErrorClassRetryable,
+		},
+		{
+			RunningError{
+				Code:    string(cerror.ErrSinkURIInvalid.RFCCode()),
+				Message: cerror.ErrSinkURIInvalid.Error(),
+			},
+			cerror.ErrorClassOperatorAction,
+		},
+		{
+			RunningError{
+				Code:    string(cerror.ErrSnapshotLostByGC.RFCCode()),
+				Message: cerror.ErrSnapshotLostByGC.Error(),
+			},
+			cerror.ErrorClassFatal,
+		},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.class, c.err.ErrorClass())
+	}
+}
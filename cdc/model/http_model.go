@@ -99,6 +99,26 @@ type ServerStatus struct {
 	Pid      int      `json:"pid"`
 	IsOwner  bool     `json:"is_owner"`
 	Liveness Liveness `json:"liveness"`
+	// Upstreams is the health of the upstreams the server is connected to.
+	Upstreams []UpstreamStatus `json:"upstreams,omitempty"`
+}
+
+// States of an upstream reported in UpstreamStatus.
+const (
+	UpstreamStateInitializing = "initializing"
+	UpstreamStateNormal       = "normal"
+	UpstreamStateError        = "error"
+	UpstreamStateClosing      = "closing"
+	UpstreamStateClosed       = "closed"
+)
+
+// UpstreamStatus holds the health of an upstream TiDB cluster seen by a server
+type UpstreamStatus struct {
+	ID          uint64   `json:"id"`
+	PDEndpoints []string `json:"pd_endpoints"`
+	IsDefault   bool     `json:"is_default"`
+	State       string   `json:"state"`
+	Error       string   `json:"error,omitempty"`
 }
 
 // ChangefeedCommonInfo holds some common usage information of a changefeed
@@ -144,6 +164,10 @@ type ChangefeedDetail struct {
 	ErrorHis       []int64             `json:"error_history"`
 	CreatorVersion string              `json:"creator_version"`
 	TaskStatus     []CaptureTaskStatus `json:"task_status,omitempty"`
+	// LastRewrittenDDL is the last DDL rewritten by the DDL rewrite rules.
+	LastRewrittenDDL *RewrittenDDL `json:"last_rewritten_ddl,omitempty"`
+	// LastErrors are the most recent errors occurred in the changefeed.
+	LastErrors []*RecordedError `json:"last_errors,omitempty"`
 }
 
 // MarshalJSON use to marshal ChangefeedDetail
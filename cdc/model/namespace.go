@@ -0,0 +1,86 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NamespaceInfo stores the settings of a namespace in etcd.
+// A namespace isolates the changefeeds of different tenants
+// that share one TiCDC cluster.
+type NamespaceInfo struct {
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create-time"`
+	// ChangefeedQuota is the max number of changefeeds in the namespace,
+	// 0 means unlimited.
+	ChangefeedQuota int `json:"changefeed-quota"`
+	// MemoryQuota is the max sum of sink memory quota of changefeeds in
+	// the namespace, in bytes. 0 means unlimited.
+	MemoryQuota uint64 `json:"memory-quota"`
+}
+
+// NewDefaultNamespaceInfo returns the info of the default namespace,
+// which always exists and has no quota.
+func NewDefaultNamespaceInfo() *NamespaceInfo {
+	return &NamespaceInfo{Name: DefaultNamespace}
+}
+
+// CheckQuota returns an error if a namespace, which already has
+// changefeedCount changefeeds using memoryQuota bytes of memory quota in
+// total, can not hold one more changefeed with the given memory quota.
+func (n *NamespaceInfo) CheckQuota(
+	changefeedCount int, memoryQuota uint64, newMemoryQuota uint64,
+) error {
+	if n.ChangefeedQuota > 0 && changefeedCount+1 > n.ChangefeedQuota {
+		return cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs(
+			n.Name, "changefeed", changefeedCount+1, n.ChangefeedQuota)
+	}
+	if n.MemoryQuota > 0 && memoryQuota+newMemoryQuota > n.MemoryQuota {
+		return cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs(
+			n.Name, "memory", memoryQuota+newMemoryQuota, n.MemoryQuota)
+	}
+	return nil
+}
+
+// Marshal using json.Marshal.
+func (n *NamespaceInfo) Marshal() ([]byte, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// Unmarshal from binary data.
+func (n *NamespaceInfo) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, n)
+	return errors.Annotatef(cerror.WrapError(cerror.ErrUnmarshalFailed, err),
+		"unmarshal data: %v", data)
+}
+
+// Clone returns a cloned NamespaceInfo
+func (n *NamespaceInfo) Clone() (*NamespaceInfo, error) {
+	s, err := n.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	cloned := new(NamespaceInfo)
+	err = cloned.Unmarshal(s)
+	return cloned, err
+}
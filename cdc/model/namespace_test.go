@@ -0,0 +1,43 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceInfoCheckQuota(t *testing.T) {
+	info := NewDefaultNamespaceInfo()
+	require.Nil(t, info.CheckQuota(100, 1<<40, 1<<40))
+
+	info = &NamespaceInfo{Name: "test", ChangefeedQuota: 2, MemoryQuota: 1024}
+	require.Nil(t, info.CheckQuota(1, 512, 512))
+	err := info.CheckQuota(2, 0, 0)
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Contains(t, err.Error(), "changefeed quota")
+	err = info.CheckQuota(1, 512, 513)
+	require.True(t, cerror.ErrNamespaceQuotaExceeded.Equal(err))
+	require.Contains(t, err.Error(), "memory quota")
+}
+
+func TestNamespaceInfoClone(t *testing.T) {
+	info := &NamespaceInfo{Name: "test", ChangefeedQuota: 1}
+	cloned, err := info.Clone()
+	require.Nil(t, err)
+	cloned.ChangefeedQuota = 2
+	require.Equal(t, 1, info.ChangefeedQuota)
+}
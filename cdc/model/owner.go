@@ -261,6 +261,22 @@ func (ts *TaskStatus) Clone() *TaskStatus {
 	return &clone
 }
 
+// TableReplicationState is the replication state of a table maintained by
+// the scheduler of the owner.
+type TableReplicationState struct {
+	TableID TableID `json:"table-id"`
+	// State is the scheduling state of the table, a table is being
+	// replicated normally if the state is "Replicating".
+	State string `json:"state"`
+	// Primary is the capture which is replicating the table.
+	Primary CaptureID `json:"primary"`
+	// Secondary is the capture which is preparing the table, it is not
+	// empty if the table is being moved.
+	Secondary    CaptureID `json:"secondary,omitempty"`
+	CheckpointTs Ts        `json:"checkpoint-ts"`
+	ResolvedTs   Ts        `json:"resolved-ts"`
+}
+
 // TableID is the ID of the table
 type TableID = int64
 
@@ -290,6 +306,24 @@ type ChangeFeedStatus struct {
 	ResolvedTs   uint64       `json:"resolved-ts"`
 	CheckpointTs uint64       `json:"checkpoint-ts"`
 	AdminJobType AdminJobType `json:"admin-job-type"`
+	// LastRewrittenDDL is the last DDL rewritten by the DDL rewrite rules
+	// of the changefeed.
+	LastRewrittenDDL *RewrittenDDL `json:"last-rewritten-ddl,omitempty"`
+	// LastErrors are the most recent errors occurred in the changefeed,
+	// the oldest one first.
+	LastErrors []*RecordedError `json:"last-errors,omitempty"`
+	// PartitionNums are the partition numbers used by the MQ sinks to
+	// dispatch the events of the topics whose partition numbers have been
+	// changed. They are changed by the owner at a resolved ts barrier.
+	PartitionNums map[string]int32 `json:"partition-nums,omitempty"`
+}
+
+// RewrittenDDL is a DDL rewritten by the DDL rewrite rules of a changefeed,
+// an empty RewrittenQuery means the DDL is not sent to the downstream.
+type RewrittenDDL struct {
+	CommitTs       uint64 `json:"commit-ts"`
+	OriginalQuery  string `json:"original-query"`
+	RewrittenQuery string `json:"rewritten-query"`
 }
 
 // Marshal returns json encoded string of ChangeFeedStatus, only contains necessary fields stored in storage
@@ -302,3 +302,91 @@ func (ti *TableInfo) IsIndexUnique(indexInfo *model.IndexInfo) bool {
 func (ti *TableInfo) Clone() *TableInfo {
 	return WrapTableInfo(ti.SchemaID, ti.TableName.Schema, ti.TableInfoVersion, ti.TableInfo.Clone())
 }
+
+// BuildTiDBTableInfo builds a TiDB TableInfo from the given columns and index
+// columns of a row changed event. It is only used to generate SQL for the
+// downstream, so only the names, types, flags and unique indices are filled.
+func BuildTiDBTableInfo(columns []*Column, indexColumns [][]int) *model.TableInfo {
+	ret := &model.TableInfo{}
+	ret.Name = model.NewCIStr("BuildTiDBTableInfo")
+
+	for i, col := range columns {
+		columnInfo := &model.ColumnInfo{
+			Offset: i,
+			State:  model.StatePublic,
+		}
+		if col == nil {
+			// A nil column means it is a virtual generated column, which is
+			// not visible to CDC. Mark it as generated so that it is skipped
+			// when generating SQL.
+			columnInfo.Name = model.NewCIStr(fmt.Sprintf("omitted_%d", i))
+			columnInfo.GeneratedExprString = "pass_generated_check"
+			columnInfo.GeneratedStored = false
+			ret.Columns = append(ret.Columns, columnInfo)
+			continue
+		}
+		columnInfo.Name = model.NewCIStr(col.Name)
+		columnInfo.SetType(col.Type)
+		if col.Charset != "" {
+			columnInfo.SetCharset(col.Charset)
+		}
+		flag := col.Flag
+		if flag.IsBinary() {
+			columnInfo.SetCharset("binary")
+		}
+		if flag.IsGeneratedColumn() {
+			columnInfo.GeneratedExprString = "pass_generated_check"
+			columnInfo.GeneratedStored = true
+		}
+		if flag.IsHandleKey() || flag.IsPrimaryKey() {
+			columnInfo.AddFlag(mysql.PriKeyFlag)
+		}
+		if flag.IsUniqueKey() {
+			columnInfo.AddFlag(mysql.UniqueKeyFlag)
+		}
+		if !flag.IsNullable() {
+			columnInfo.AddFlag(mysql.NotNullFlag)
+		}
+		if flag.IsMultipleKey() {
+			columnInfo.AddFlag(mysql.MultipleKeyFlag)
+		}
+		if flag.IsUnsigned() {
+			columnInfo.AddFlag(mysql.UnsignedFlag)
+		}
+		ret.Columns = append(ret.Columns, columnInfo)
+	}
+
+	for i, offsets := range indexColumns {
+		if len(offsets) == 0 {
+			continue
+		}
+		indexInfo := &model.IndexInfo{
+			Name:   model.NewCIStr(fmt.Sprintf("idx_%d", i)),
+			Unique: true,
+			State:  model.StatePublic,
+		}
+		// The index built from handle key columns is treated as the primary
+		// key, so that it is preferred when generating the WHERE clause.
+		isHandle := true
+		for _, offset := range offsets {
+			if offset >= len(columns) || columns[offset] == nil {
+				isHandle = false
+				continue
+			}
+			if !columns[offset].Flag.IsHandleKey() {
+				isHandle = false
+			}
+			indexInfo.Columns = append(indexInfo.Columns, &model.IndexColumn{
+				Name:   ret.Columns[offset].Name,
+				Offset: offset,
+				Length: types.UnspecifiedLength,
+			})
+		}
+		if len(indexInfo.Columns) != len(offsets) {
+			continue
+		}
+		indexInfo.Primary = isHandle
+		ret.Indices = append(ret.Indices, indexInfo)
+	}
+	return ret
+}
@@ -270,3 +270,56 @@ func TestTableInfoClone(t *testing.T) {
 	cloned.SchemaID = 100
 	require.Equal(t, int64(10), info.SchemaID)
 }
+
+func TestBuildTiDBTableInfo(t *testing.T) {
+	t.Parallel()
+	columns := []*Column{
+		{
+			Name: "a",
+			Type: mysql.TypeLong,
+			Flag: HandleKeyFlag | PrimaryKeyFlag,
+		},
+		nil,
+		{
+			Name:    "b",
+			Type:    mysql.TypeVarchar,
+			Charset: "utf8mb4",
+			Flag:    UniqueKeyFlag | NullableFlag,
+		},
+		{
+			Name: "c",
+			Type: mysql.TypeLonglong,
+			Flag: GeneratedColumnFlag | NullableFlag | UnsignedFlag,
+		},
+	}
+	info := BuildTiDBTableInfo(columns, [][]int{{0}, {2}})
+	require.Len(t, info.Columns, 4)
+
+	require.Equal(t, "a", info.Columns[0].Name.O)
+	require.True(t, mysql.HasPriKeyFlag(info.Columns[0].GetFlag()))
+	require.True(t, mysql.HasNotNullFlag(info.Columns[0].GetFlag()))
+	require.False(t, info.Columns[0].IsGenerated())
+
+	require.True(t, info.Columns[1].IsGenerated())
+	require.False(t, info.Columns[1].GeneratedStored)
+
+	require.Equal(t, "utf8mb4", info.Columns[2].GetCharset())
+	require.True(t, mysql.HasUniKeyFlag(info.Columns[2].GetFlag()))
+	require.False(t, mysql.HasNotNullFlag(info.Columns[2].GetFlag()))
+
+	require.True(t, info.Columns[3].IsGenerated())
+	require.True(t, info.Columns[3].GeneratedStored)
+	require.True(t, mysql.HasUnsignedFlag(info.Columns[3].GetFlag()))
+
+	require.Len(t, info.Indices, 2)
+	require.True(t, info.Indices[0].Primary)
+	require.True(t, info.Indices[0].Unique)
+	require.Equal(t, 0, info.Indices[0].Columns[0].Offset)
+	require.False(t, info.Indices[1].Primary)
+	require.True(t, info.Indices[1].Unique)
+	require.Equal(t, "b", info.Indices[1].Columns[0].Name.O)
+
+	// Index referring to an omitted column is dropped.
+	info = BuildTiDBTableInfo(columns, [][]int{{1}})
+	require.Len(t, info.Indices, 0)
+}
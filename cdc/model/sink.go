@@ -271,6 +271,9 @@ type RowChangedEvent struct {
 	SplitTxn bool `json:"-" msg:"-"`
 	// ReplicatingTs is ts when a table starts replicating events to downstream.
 	ReplicatingTs Ts `json:"-" msg:"-"`
+	// Checksum is the checksum of the row calculated by CalcRowChecksum,
+	// it's nil if the row checksum is not enabled for the changefeed.
+	Checksum *uint32 `json:"checksum,omitempty" msg:"-"`
 }
 
 // GetCommitTs returns the commit timestamp of this event.
@@ -0,0 +1,104 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// ResyncPolicy decides how the rows of a table in the downstream are
+// handled when the table is re-synced.
+type ResyncPolicy string
+
+const (
+	// ResyncPolicyTruncate truncates the table in the downstream before the
+	// rows are replicated again.
+	ResyncPolicyTruncate ResyncPolicy = "truncate"
+	// ResyncPolicyOverwrite overwrites the rows in the downstream, the rows
+	// which do not exist in the upstream are kept.
+	ResyncPolicyOverwrite ResyncPolicy = "overwrite"
+)
+
+// Validate checks whether the resync policy is supported.
+func (p ResyncPolicy) Validate() error {
+	switch p {
+	case ResyncPolicyTruncate, ResyncPolicyOverwrite:
+		return nil
+	}
+	return cerror.ErrAPIInvalidParam.GenWithStack("invalid resync policy: %s", p)
+}
+
+// TableSnapshotProgress is the progress of the initial snapshot of a table,
+// it is saved after every chunk of rows is flushed to the sink, so the
+// snapshot can be resumed from NextKey when the table is moved or the
+// capture is restarted.
+type TableSnapshotProgress struct {
+	// SnapshotTs is the ts at which the table is scanned, a progress with a
+	// different snapshot ts is left by a removed changefeed and is ignored.
+	SnapshotTs Ts `json:"snapshot-ts"`
+	// TableName is the quoted name of the table when the snapshot is taken.
+	TableName string `json:"table-name"`
+	// NextKey is the first row key which has not been flushed to the sink.
+	NextKey []byte `json:"next-key"`
+	// Rows is the number of rows which have been flushed to the sink.
+	Rows       uint64    `json:"rows"`
+	Done       bool      `json:"done"`
+	UpdateTime time.Time `json:"update-time"`
+	// Resync is true if the snapshot is taken by a table resync, the
+	// snapshot ts of a requested resync is 0 before the table is added
+	// back to the changefeed.
+	Resync bool         `json:"resync,omitempty"`
+	Policy ResyncPolicy `json:"policy,omitempty"`
+	// Table is the name of the re-synced table, it is used to truncate
+	// the table in the downstream.
+	Table *TableName `json:"table,omitempty"`
+}
+
+// NewTableResyncRequest returns the progress which requests the table to
+// be snapshotted again when it is added back to the changefeed.
+func NewTableResyncRequest(table TableName, policy ResyncPolicy) *TableSnapshotProgress {
+	return &TableSnapshotProgress{
+		TableName:  table.QuoteString(),
+		Table:      &table,
+		UpdateTime: time.Now(),
+		Resync:     true,
+		Policy:     policy,
+	}
+}
+
+// ResyncPending returns true if the table is requested to be re-synced
+// but it has not been added back to the changefeed.
+func (p *TableSnapshotProgress) ResyncPending() bool {
+	return p.Resync && !p.Done && p.SnapshotTs == 0
+}
+
+// Marshal using json.Marshal.
+func (p *TableSnapshotProgress) Marshal() ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// Unmarshal from binary data.
+func (p *TableSnapshotProgress) Unmarshal(data []byte) error {
+	err := json.Unmarshal(data, p)
+	return errors.Annotatef(cerror.WrapError(cerror.ErrUnmarshalFailed, err),
+		"unmarshal data: %v", data)
+}
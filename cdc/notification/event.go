@@ -0,0 +1,91 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+)
+
+// EventType is the type of an event.
+type EventType string
+
+const (
+	// EventChangefeedStateChanged is sent when the state of a changefeed
+	// is changed, such as from normal to error.
+	EventChangefeedStateChanged EventType = "changefeed-state-changed"
+	// EventOwnerChanged is sent when a capture becomes the owner.
+	EventOwnerChanged EventType = "owner-changed"
+	// EventCaptureJoined is sent when a capture joins the cluster.
+	EventCaptureJoined EventType = "capture-joined"
+	// EventCaptureLeft is sent when a capture leaves the cluster.
+	EventCaptureLeft EventType = "capture-left"
+)
+
+// Event is posted to the webhooks in JSON format. An event may be delivered
+// more than once, the receivers can deduplicate the events by ID.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      EventType `json:"type"`
+	Time      time.Time `json:"time"`
+	ClusterID string    `json:"cluster_id"`
+
+	Namespace  string              `json:"namespace,omitempty"`
+	Changefeed string              `json:"changefeed,omitempty"`
+	OldState   model.FeedState     `json:"old_state,omitempty"`
+	NewState   model.FeedState     `json:"new_state,omitempty"`
+	Error      *model.RunningError `json:"error,omitempty"`
+
+	CaptureID   string `json:"capture_id,omitempty"`
+	CaptureAddr string `json:"capture_address,omitempty"`
+}
+
+// NewChangefeedStateChangedEvent creates an event for the state change
+// of a changefeed.
+func NewChangefeedStateChangedEvent(
+	id model.ChangeFeedID, oldState, newState model.FeedState, err *model.RunningError,
+) *Event {
+	return &Event{
+		Type:       EventChangefeedStateChanged,
+		Namespace:  id.Namespace,
+		Changefeed: id.ID,
+		OldState:   oldState,
+		NewState:   newState,
+		Error:      err,
+	}
+}
+
+// NewOwnerChangedEvent creates an event for the capture becoming the owner.
+func NewOwnerChangedEvent(capture *model.CaptureInfo) *Event {
+	return newCaptureEvent(EventOwnerChanged, capture)
+}
+
+// NewCaptureJoinedEvent creates an event for the capture joining the cluster.
+func NewCaptureJoinedEvent(capture *model.CaptureInfo) *Event {
+	return newCaptureEvent(EventCaptureJoined, capture)
+}
+
+// NewCaptureLeftEvent creates an event for the capture leaving the cluster.
+func NewCaptureLeftEvent(capture *model.CaptureInfo) *Event {
+	return newCaptureEvent(EventCaptureLeft, capture)
+}
+
+func newCaptureEvent(tp EventType, capture *model.CaptureInfo) *Event {
+	return &Event{
+		Type:        tp,
+		CaptureID:   capture.ID,
+		CaptureAddr: capture.AdvertiseAddr,
+	}
+}
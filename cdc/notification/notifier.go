@@ -0,0 +1,508 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// signatureHeader carries the HMAC-SHA256 signature of the request body
+	// if the secret of the webhook is set.
+	signatureHeader = "X-TiCDC-Signature"
+	eventTypeHeader = "X-TiCDC-Event"
+	eventIDHeader   = "X-TiCDC-Event-ID"
+
+	deliveryBackoffBaseDelayInMs = 500
+	deliveryBackoffMaxDelayInMs  = 60 * 1000
+
+	// persistRetryInterval is the interval to retry persisting the events
+	// if the store is unavailable.
+	persistRetryInterval = 5 * time.Second
+)
+
+const (
+	resultDelivered = "delivered"
+	resultDropped   = "dropped"
+	resultFailed    = "failed"
+)
+
+// maxRejectedEvents is the number of the latest rejected events kept by
+// a webhook for debugging.
+const maxRejectedEvents = 16
+
+// EventStore persists the events which are not delivered yet, so they are
+// not lost if the owner crashes and are delivered by the next owner.
+// etcd.CDCEtcdClient implements it.
+type EventStore interface {
+	PutNotificationEvent(ctx context.Context, webhookID, eventID string, data []byte) error
+	DeleteNotificationEvent(ctx context.Context, webhookID, eventID string) error
+	GetNotificationEvents(ctx context.Context, webhookID string) ([][]byte, error)
+}
+
+// Notifier posts events to the configured webhooks. Events are delivered
+// at least once: an event is kept in the queue and in the store until the
+// webhook accepts it or rejects it permanently, and the oldest event is
+// dropped if the queue is full. All methods are safe to call on a nil
+// Notifier.
+type Notifier struct {
+	clusterID string
+	webhooks  []*webhook
+}
+
+// NewNotifier creates a Notifier, it returns nil if no webhook is configured.
+// The store can be nil, then the queued events are kept in memory only.
+func NewNotifier(
+	cfg *config.NotificationConfig, clusterID string, store EventStore,
+) *Notifier {
+	if cfg == nil || len(cfg.Webhooks) == 0 {
+		return nil
+	}
+	client := &http.Client{Timeout: time.Duration(cfg.Timeout)}
+	n := &Notifier{clusterID: clusterID}
+	for _, hook := range cfg.Webhooks {
+		n.webhooks = append(n.webhooks, &webhook{
+			id:        webhookID(hook.URL),
+			url:       hook.URL,
+			secret:    hook.Secret,
+			client:    client,
+			store:     store,
+			queueSize: cfg.QueueSize,
+			saved:     make(map[string]struct{}),
+			notifyCh:  make(chan struct{}, 1),
+			saveCh:    make(chan struct{}, 1),
+		})
+	}
+	return n
+}
+
+// webhookID returns the key of the webhook in the store.
+func webhookID(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Notify enqueues the event to all webhooks, it never blocks.
+func (n *Notifier) Notify(event *Event) {
+	if n == nil {
+		return
+	}
+	event.ID = uuid.New().String()
+	event.Time = time.Now()
+	event.ClusterID = n.clusterID
+	for _, w := range n.webhooks {
+		w.enqueue(event)
+	}
+}
+
+// IsPending returns true if the state change of the changefeed to the state
+// is not delivered to some webhook yet.
+func (n *Notifier) IsPending(id model.ChangeFeedID, state model.FeedState) bool {
+	if n == nil {
+		return false
+	}
+	for _, w := range n.webhooks {
+		w.mu.Lock()
+		for _, event := range w.queue {
+			if event.Type == EventChangefeedStateChanged &&
+				event.Namespace == id.Namespace && event.Changefeed == id.ID &&
+				event.NewState == state {
+				w.mu.Unlock()
+				return true
+			}
+		}
+		w.mu.Unlock()
+	}
+	return false
+}
+
+// Load enqueues the events left in the store, it is called once the capture
+// becomes the owner, so the events which the previous owner had not
+// delivered before it crashed are delivered by the new owner.
+func (n *Notifier) Load(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	for _, w := range n.webhooks {
+		if err := w.load(ctx); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Run delivers the queued events until the context is done. Events which
+// are not delivered yet are kept, so Run can be called again later.
+func (n *Notifier) Run(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	g, ctx := errgroup.WithContext(ctx)
+	for _, w := range n.webhooks {
+		w := w
+		g.Go(func() error {
+			return w.run(ctx)
+		})
+		if w.store != nil {
+			g.Go(func() error {
+				return w.persist(ctx)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// WriteDebugInfo writes the pending and the rejected events of the webhooks.
+func (n *Notifier) WriteDebugInfo(writer io.Writer) {
+	if n == nil {
+		return
+	}
+	for _, w := range n.webhooks {
+		w.mu.Lock()
+		fmt.Fprintf(writer, "webhook: %s, pending events: %d\n", w.url, len(w.queue))
+		for _, r := range w.rejected {
+			fmt.Fprintf(writer, "\trejected event: %s, type: %s, time: %s, error: %s\n",
+				r.event.ID, r.event.Type, r.time.Format(time.RFC3339), r.err)
+		}
+		w.mu.Unlock()
+	}
+}
+
+type rejection struct {
+	event *Event
+	err   error
+	time  time.Time
+}
+
+type webhook struct {
+	id        string
+	url       string
+	secret    string
+	client    *http.Client
+	store     EventStore
+	queueSize int
+
+	mu    sync.Mutex
+	queue []*Event
+	// removed are the events removed from the queue, their keys are deleted
+	// from the store if they are saved.
+	removed []*Event
+	// saved are the IDs of the events saved in the store.
+	saved map[string]struct{}
+	// rejected are the latest events rejected by the webhook.
+	rejected []rejection
+	notifyCh chan struct{}
+	saveCh   chan struct{}
+}
+
+func (w *webhook) enqueue(event *Event) {
+	w.mu.Lock()
+	if len(w.queue) >= w.queueSize {
+		dropped := w.queue[0]
+		w.queue = w.queue[1:]
+		w.removeLocked(dropped)
+		eventCounter.WithLabelValues(string(dropped.Type), resultDropped).Inc()
+		log.Warn("notification queue is full, drop the oldest event",
+			zap.String("url", w.url),
+			zap.String("eventID", dropped.ID),
+			zap.String("eventType", string(dropped.Type)))
+	}
+	w.queue = append(w.queue, event)
+	w.mu.Unlock()
+
+	w.notify()
+}
+
+func (w *webhook) notify() {
+	select {
+	case w.notifyCh <- struct{}{}:
+	default:
+	}
+	select {
+	case w.saveCh <- struct{}{}:
+	default:
+	}
+}
+
+// load enqueues the events in the store which are not queued yet before
+// the queued ones, the newest events are kept if the queue is full.
+func (w *webhook) load(ctx context.Context) error {
+	if w.store == nil {
+		return nil
+	}
+	values, err := w.store.GetNotificationEvents(ctx, w.id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	loaded := make([]*Event, 0, len(values))
+	for _, value := range values {
+		event := &Event{}
+		if err := json.Unmarshal(value, event); err != nil {
+			return cerror.WrapError(cerror.ErrUnmarshalFailed, err)
+		}
+		loaded = append(loaded, event)
+	}
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].Time.Before(loaded[j].Time)
+	})
+
+	w.mu.Lock()
+	queued := make(map[string]struct{}, len(w.queue))
+	for _, event := range w.queue {
+		queued[event.ID] = struct{}{}
+	}
+	events := make([]*Event, 0, len(loaded)+len(w.queue))
+	for _, event := range loaded {
+		if _, ok := queued[event.ID]; !ok {
+			w.saved[event.ID] = struct{}{}
+			events = append(events, event)
+		}
+	}
+	log.Info("load pending notification events",
+		zap.String("url", w.url), zap.Int("count", len(events)))
+	events = append(events, w.queue...)
+	if len(events) > w.queueSize {
+		for _, dropped := range events[:len(events)-w.queueSize] {
+			w.removeLocked(dropped)
+			eventCounter.WithLabelValues(string(dropped.Type), resultDropped).Inc()
+		}
+		events = events[len(events)-w.queueSize:]
+	}
+	w.queue = events
+	w.mu.Unlock()
+
+	w.notify()
+	return nil
+}
+
+func (w *webhook) front() *Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.queue) == 0 {
+		return nil
+	}
+	return w.queue[0]
+}
+
+// pop removes the event from the queue, unless it has been dropped already.
+func (w *webhook) pop(event *Event) {
+	w.mu.Lock()
+	for i, e := range w.queue {
+		if e == event {
+			w.queue = append(w.queue[:i], w.queue[i+1:]...)
+			w.removeLocked(event)
+			break
+		}
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.saveCh <- struct{}{}:
+	default:
+	}
+}
+
+// removeLocked records the event removed from the queue, so it is deleted
+// from the store. The caller must hold the mu.
+func (w *webhook) removeLocked(event *Event) {
+	if w.store != nil {
+		w.removed = append(w.removed, event)
+	}
+}
+
+func (w *webhook) reject(event *Event, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.rejected) >= maxRejectedEvents {
+		w.rejected = w.rejected[1:]
+	}
+	w.rejected = append(w.rejected, rejection{event: event, err: err, time: time.Now()})
+}
+
+func (w *webhook) run(ctx context.Context) error {
+	for {
+		event := w.front()
+		if event == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-w.notifyCh:
+			}
+			continue
+		}
+		if err := w.deliver(ctx, event); err != nil {
+			if ctx.Err() != nil {
+				// Keep the event, it will be delivered in the next run.
+				return nil
+			}
+			// Only the events rejected permanently by the webhook fail,
+			// the others are retried until they are delivered.
+			eventCounter.WithLabelValues(string(event.Type), resultFailed).Inc()
+			w.reject(event, err)
+			log.Error("webhook rejected the event, drop it",
+				zap.String("url", w.url),
+				zap.String("eventID", event.ID),
+				zap.String("eventType", string(event.Type)),
+				zap.Error(err))
+		} else {
+			eventCounter.WithLabelValues(string(event.Type), resultDelivered).Inc()
+		}
+		w.pop(event)
+	}
+}
+
+// persist saves the queued events to the store and deletes the removed
+// ones until the context is done. It is the only goroutine writing the
+// store, so an event is never saved after it is deleted.
+func (w *webhook) persist(ctx context.Context) error {
+	ticker := time.NewTicker(persistRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.saveCh:
+		case <-ticker.C:
+		}
+		if err := w.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Warn("failed to persist the notification events, retry later",
+				zap.String("url", w.url), zap.Error(err))
+		}
+	}
+}
+
+func (w *webhook) sync(ctx context.Context) error {
+	w.mu.Lock()
+	removed := w.removed
+	w.removed = nil
+	var unsaved []*Event
+	for _, event := range w.queue {
+		if _, ok := w.saved[event.ID]; !ok {
+			unsaved = append(unsaved, event)
+		}
+	}
+	w.mu.Unlock()
+
+	for i, event := range removed {
+		w.mu.Lock()
+		_, ok := w.saved[event.ID]
+		w.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if err := w.store.DeleteNotificationEvent(ctx, w.id, event.ID); err != nil {
+			w.mu.Lock()
+			w.removed = append(w.removed, removed[i:]...)
+			w.mu.Unlock()
+			return errors.Trace(err)
+		}
+		w.mu.Lock()
+		delete(w.saved, event.ID)
+		w.mu.Unlock()
+	}
+	for _, event := range unsaved {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.store.PutNotificationEvent(ctx, w.id, event.ID, data); err != nil {
+			return errors.Trace(err)
+		}
+		// The event may be removed while it is being saved, it is deleted
+		// in the next sync then.
+		w.mu.Lock()
+		w.saved[event.ID] = struct{}{}
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// deliver posts the event until the webhook accepts it or rejects it with
+// a client error other than 408 and 429, which can not succeed by retrying.
+func (w *webhook) deliver(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	permanent := false
+	return retry.Do(ctx, func() error {
+		statusCode, err := w.post(ctx, event, body)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Debug("failed to post the event to webhook, retry later",
+					zap.String("url", w.url),
+					zap.String("eventID", event.ID),
+					zap.Error(err))
+			}
+			permanent = statusCode >= 400 && statusCode < 500 &&
+				statusCode != http.StatusRequestTimeout &&
+				statusCode != http.StatusTooManyRequests
+		}
+		return err
+	}, retry.WithBackoffBaseDelay(deliveryBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(deliveryBackoffMaxDelayInMs),
+		retry.WithIsRetryableErr(func(error) bool { return !permanent }))
+}
+
+func (w *webhook) post(ctx context.Context, event *Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventTypeHeader, string(event.Type))
+	req.Header.Set(eventIDHeader, event.ID)
+	if w.secret != "" {
+		req.Header.Set(signatureHeader, "sha256="+Sign(w.secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Errorf("webhook %s responded with status %s", w.url, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body keyed by the secret,
+// receivers can use it to verify the signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,368 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	events   []*Event
+	headers  []http.Header
+	bodies   [][]byte
+	statuses []int
+	// unavailable makes the receiver respond 503 to all requests.
+	unavailable bool
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+	}
+	event := &Event{}
+	if err := json.Unmarshal(body, event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.events = append(r.events, event)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.bodies = append(r.bodies, body)
+}
+
+func (r *receiver) received() []*Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Event(nil), r.events...)
+}
+
+func (r *receiver) setUnavailable(unavailable bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unavailable = unavailable
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	events map[string]map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{events: make(map[string]map[string][]byte)}
+}
+
+func (s *memoryStore) PutNotificationEvent(
+	_ context.Context, webhookID, eventID string, data []byte,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events[webhookID] == nil {
+		s.events[webhookID] = make(map[string][]byte)
+	}
+	s.events[webhookID][eventID] = data
+	return nil
+}
+
+func (s *memoryStore) DeleteNotificationEvent(
+	_ context.Context, webhookID, eventID string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events[webhookID], eventID)
+	return nil
+}
+
+func (s *memoryStore) GetNotificationEvents(
+	_ context.Context, webhookID string,
+) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events [][]byte
+	for _, data := range s.events[webhookID] {
+		events = append(events, data)
+	}
+	return events, nil
+}
+
+func (s *memoryStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, events := range s.events {
+		count += len(events)
+	}
+	return count
+}
+
+func runNotifier(n *Notifier) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = n.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func newTestConfig(queueSize int, hooks ...*config.WebhookConfig) *config.NotificationConfig {
+	return &config.NotificationConfig{
+		Webhooks:  hooks,
+		QueueSize: queueSize,
+		Timeout:   config.TomlDuration(time.Second),
+	}
+}
+
+func TestNewNotifierWithoutWebhooks(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewNotifier(nil, "default", nil))
+	n := NewNotifier(newTestConfig(10), "default", nil)
+	require.Nil(t, n)
+	// All methods are safe to call on a nil notifier.
+	n.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-1"}))
+	require.Nil(t, n.Run(context.Background()))
+}
+
+func TestNotifierDeliverWithSignature(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := NewNotifier(newTestConfig(10, &config.WebhookConfig{
+		URL: srv.URL, Secret: "s3cret",
+	}), "cluster-1", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.Nil(t, n.Run(ctx))
+	}()
+
+	id := model.DefaultChangeFeedID("test")
+	n.Notify(NewChangefeedStateChangedEvent(id, model.StateNormal, model.StateError,
+		&model.RunningError{Code: "CDC:ErrSinkURIInvalid", Message: "invalid"}))
+	n.Notify(NewOwnerChangedEvent(&model.CaptureInfo{ID: "capture-1", AdvertiseAddr: "127.0.0.1:8300"}))
+
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	events := r.received()
+	require.Equal(t, EventChangefeedStateChanged, events[0].Type)
+	require.Equal(t, "cluster-1", events[0].ClusterID)
+	require.Equal(t, "test", events[0].Changefeed)
+	require.Equal(t, model.StateNormal, events[0].OldState)
+	require.Equal(t, model.StateError, events[0].NewState)
+	require.Equal(t, "CDC:ErrSinkURIInvalid", events[0].Error.Code)
+	require.Equal(t, EventOwnerChanged, events[1].Type)
+	require.Equal(t, "capture-1", events[1].CaptureID)
+	require.Equal(t, "127.0.0.1:8300", events[1].CaptureAddr)
+
+	for i, header := range r.headers {
+		require.Equal(t, "application/json", header.Get("Content-Type"))
+		require.Equal(t, string(events[i].Type), header.Get(eventTypeHeader))
+		require.Equal(t, events[i].ID, header.Get(eventIDHeader))
+		require.Equal(t, "sha256="+Sign("s3cret", r.bodies[i]), header.Get(signatureHeader))
+	}
+}
+
+func TestNotifierRetry(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{statuses: []int{
+		http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK,
+		http.StatusBadRequest,
+	}}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := NewNotifier(newTestConfig(10, &config.WebhookConfig{URL: srv.URL}), "default", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.Nil(t, n.Run(ctx))
+	}()
+
+	// The first event is retried until the webhook accepts it, the second
+	// one is rejected by the webhook and dropped without retrying.
+	n.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-1"}))
+	n.Notify(NewCaptureLeftEvent(&model.CaptureInfo{ID: "capture-1"}))
+	n.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-2"}))
+
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	events := r.received()
+	require.Equal(t, EventCaptureJoined, events[0].Type)
+	require.Equal(t, "capture-1", events[0].CaptureID)
+	require.Equal(t, EventCaptureJoined, events[1].Type)
+	require.Equal(t, "capture-2", events[1].CaptureID)
+}
+
+func TestNotifierQueueFull(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	n := NewNotifier(newTestConfig(2, &config.WebhookConfig{URL: srv.URL}), "default", nil)
+	// Events are queued before the notifier runs, the oldest one is dropped.
+	for _, id := range []string{"capture-1", "capture-2", "capture-3"} {
+		n.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: id}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.Nil(t, n.Run(ctx))
+	}()
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	events := r.received()
+	require.Equal(t, "capture-2", events[0].CaptureID)
+	require.Equal(t, "capture-3", events[1].CaptureID)
+}
+
+func TestNotifierLoadPendingEvents(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{unavailable: true}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	store := newMemoryStore()
+	cfg := newTestConfig(10, &config.WebhookConfig{URL: srv.URL})
+
+	// The owner crashes before the webhook is available, the events are
+	// left in the store.
+	oldOwner := NewNotifier(cfg, "default", store)
+	stop := runNotifier(oldOwner)
+	oldOwner.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-1"}))
+	oldOwner.Notify(NewCaptureLeftEvent(&model.CaptureInfo{ID: "capture-2"}))
+	require.Eventually(t, func() bool {
+		return store.count() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	stop()
+	require.Len(t, r.received(), 0)
+
+	// The new owner loads the events and delivers them before its own ones.
+	r.setUnavailable(false)
+	newOwner := NewNotifier(cfg, "default", store)
+	require.Nil(t, newOwner.Load(context.Background()))
+	newOwner.Notify(NewOwnerChangedEvent(&model.CaptureInfo{ID: "capture-3"}))
+	stop = runNotifier(newOwner)
+	defer stop()
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 3 && store.count() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	events := r.received()
+	require.Equal(t, EventCaptureJoined, events[0].Type)
+	require.Equal(t, "capture-1", events[0].CaptureID)
+	require.Equal(t, EventCaptureLeft, events[1].Type)
+	require.Equal(t, "capture-2", events[1].CaptureID)
+	require.Equal(t, EventOwnerChanged, events[2].Type)
+
+	// Loading again does not enqueue the delivered events.
+	require.Nil(t, newOwner.Load(context.Background()))
+	require.Nil(t, newOwner.webhooks[0].front())
+}
+
+func TestNotifierReportRejectedEvents(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{statuses: []int{http.StatusForbidden}}
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	store := newMemoryStore()
+
+	n := NewNotifier(newTestConfig(10, &config.WebhookConfig{URL: srv.URL}), "default", store)
+	stop := runNotifier(n)
+	defer stop()
+	event := NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-1"})
+	n.Notify(event)
+	n.Notify(NewCaptureJoinedEvent(&model.CaptureInfo{ID: "capture-2"}))
+
+	// The rejected event is not retried, it is reported and removed from
+	// the store together with the delivered one.
+	require.Eventually(t, func() bool {
+		return len(r.received()) == 1 && store.count() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "capture-2", r.received()[0].CaptureID)
+
+	var buf bytes.Buffer
+	n.WriteDebugInfo(&buf)
+	require.Contains(t, buf.String(), "pending events: 0")
+	require.Contains(t, buf.String(), "rejected event: "+event.ID)
+	require.Contains(t, buf.String(), "403 Forbidden")
+}
+
+func TestNotifierIsPending(t *testing.T) {
+	t.Parallel()
+
+	var n *Notifier
+	id := model.DefaultChangeFeedID("test")
+	require.False(t, n.IsPending(id, model.StateStopped))
+
+	n = NewNotifier(newTestConfig(10, &config.WebhookConfig{URL: "http://127.0.0.1:1"}),
+		"default", nil)
+	n.Notify(NewChangefeedStateChangedEvent(id, model.StateNormal, model.StateStopped, nil))
+	require.True(t, n.IsPending(id, model.StateStopped))
+	require.False(t, n.IsPending(id, model.StateFailed))
+	require.False(t, n.IsPending(model.DefaultChangeFeedID("test-1"), model.StateStopped))
+}
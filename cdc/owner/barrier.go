@@ -29,6 +29,9 @@ const (
 	syncPointBarrier
 	// finishBarrier denotes a barrier for changefeed finished.
 	finishBarrier
+	// partitionChangeBarrier denotes a barrier for changing the partition
+	// numbers of the topics of the MQ sinks.
+	partitionChangeBarrier
 )
 
 // barriers stores some barrierType and barrierTs, and can calculate the min barrierTs
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
@@ -81,6 +82,7 @@ type changefeed struct {
 	redoManager      redo.LogManager
 
 	schema      *schemaWrap4Owner
+	ddlRewriter *filter.DDLRewriter
 	sink        DDLSink
 	ddlPuller   puller.DDLPuller
 	initialized bool
@@ -97,6 +99,13 @@ type changefeed struct {
 	// And it contains only the tables of the ddl that have been processed.
 	// The ones that have not been executed yet do not have.
 	currentTableNames []model.TableName
+	// partitionNums are the new partition numbers of the topics which are
+	// applied at the partitionChangeBarrier, it is nil if there is no
+	// partition change in progress.
+	partitionNums map[string]int32
+	// partitionNumsRestored indicates whether the partition numbers in the
+	// changefeed status have been applied to the DDL sink.
+	partitionNumsRestored bool
 
 	errCh chan error
 	// cancel the running goroutine start by `DDLPuller`
@@ -257,6 +266,8 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 	if err := c.initialize(ctx); err != nil {
 		return errors.Trace(err)
 	}
+	// The schema history before the checkpoint is not needed any more.
+	c.schema.DoGC(checkpointTs)
 
 	select {
 	case err := <-c.errCh:
@@ -280,6 +291,10 @@ func (c *changefeed) tick(ctx cdcContext.Context, state *orchestrator.Changefeed
 	}
 	c.sink.emitCheckpointTs(checkpointTs, c.currentTableNames)
 
+	if err := c.handlePartitionChange(); err != nil {
+		return errors.Trace(err)
+	}
+
 	barrierTs, err := c.handleBarrier(ctx)
 	if err != nil {
 		return errors.Trace(err)
@@ -435,6 +450,10 @@ LOOP:
 	if err != nil {
 		return errors.Trace(err)
 	}
+	c.ddlRewriter, err = filter.NewDDLRewriter(c.state.Info.Config.Filter)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	cancelCtx, cancel := cdcContext.WithCancel(ctx)
 	c.cancel = cancel
 
@@ -515,6 +534,8 @@ func (c *changefeed) releaseResources(ctx cdcContext.Context) {
 	c.scheduler.Close(ctx)
 	c.scheduler = nil
 	c.barriers = nil
+	c.partitionNums = nil
+	c.partitionNumsRestored = false
 
 	changefeedCheckpointTsGauge.DeleteLabelValues(c.id.Namespace, c.id.ID)
 	changefeedCheckpointTsLagGauge.DeleteLabelValues(c.id.Namespace, c.id.ID)
@@ -623,6 +644,58 @@ func (c *changefeed) preflightCheck(captures map[model.CaptureID]*model.CaptureI
 	return
 }
 
+// handlePartitionChange sets a partitionChangeBarrier at the current resolved
+// ts if the partition numbers of some topics of the MQ sink are changed. The
+// partition numbers used to dispatch the events now are pinned in the status,
+// so the processors keep using them until the barrier is reached, including
+// the ones whose sinks are created after the change.
+func (c *changefeed) handlePartitionChange() error {
+	if !c.partitionNumsRestored {
+		c.sink.applyPartitionNums(c.state.Status.PartitionNums)
+		c.partitionNumsRestored = true
+	}
+	if c.partitionNums != nil {
+		return nil
+	}
+	oldPartitionNums, newPartitionNums, err := c.sink.getPartitionChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(newPartitionNums) == 0 {
+		return nil
+	}
+	c.patchPartitionNums(oldPartitionNums)
+	c.partitionNums = newPartitionNums
+	c.barriers.Update(partitionChangeBarrier, c.state.Status.ResolvedTs)
+	log.Info("partition numbers changed, wait for the barrier",
+		zap.String("namespace", c.id.Namespace),
+		zap.String("changefeed", c.id.ID),
+		zap.Any("oldPartitionNums", oldPartitionNums),
+		zap.Any("partitionNums", newPartitionNums),
+		zap.Uint64("barrierTs", c.state.Status.ResolvedTs))
+	return nil
+}
+
+func (c *changefeed) patchPartitionNums(partitionNums map[string]int32) {
+	c.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		changed := false
+		if status == nil {
+			return nil, changed, nil
+		}
+		for topic, partitionNum := range partitionNums {
+			if num, ok := status.PartitionNums[topic]; ok && num == partitionNum {
+				continue
+			}
+			if status.PartitionNums == nil {
+				status.PartitionNums = make(map[string]int32)
+			}
+			status.PartitionNums[topic] = partitionNum
+			changed = true
+		}
+		return status, changed, nil
+	})
+}
+
 func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 	barrierTp, barrierTs := c.barriers.Min()
 	phyBarrierTs := oracle.ExtractPhysical(barrierTs)
@@ -667,6 +740,24 @@ func (c *changefeed) handleBarrier(ctx cdcContext.Context) (uint64, error) {
 			return barrierTs, nil
 		}
 		c.feedStateManager.MarkFinished()
+
+	case partitionChangeBarrier:
+		if !blocked {
+			return barrierTs, nil
+		}
+		// All the events dispatched with the old partition numbers have been
+		// acknowledged, and no event after the barrier has been dispatched.
+		if err := c.sink.emitPartitionChange(ctx, barrierTs, c.partitionNums); err != nil {
+			return 0, errors.Trace(err)
+		}
+		c.patchPartitionNums(c.partitionNums)
+		log.Info("partition numbers applied",
+			zap.String("namespace", c.id.Namespace),
+			zap.String("changefeed", c.id.ID),
+			zap.Any("partitionNums", c.partitionNums),
+			zap.Uint64("barrierTs", barrierTs))
+		c.partitionNums = nil
+		c.barriers.Remove(partitionChangeBarrier)
 	default:
 		log.Panic("Unknown barrier type", zap.Int("barrierType", int(barrierTp)))
 	}
@@ -695,6 +786,10 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 				zap.Reflect("job", job), zap.Error(err))
 			return false, errors.Trace(err)
 		}
+		ddlEvents, err = c.rewriteDDLEvents(ddlEvents)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
 		c.ddlEventCache = ddlEvents
 		// We can't use the latest schema directly,
 		// we need to make sure we receive the ddl before we start or stop broadcasting checkpoint ts.
@@ -737,6 +832,48 @@ func (c *changefeed) asyncExecDDLJob(ctx cdcContext.Context,
 	return jobDone, nil
 }
 
+// rewriteDDLEvents rewrites the queries of the DDL events by the DDL rewrite
+// rules, the events which have nothing left to execute are dropped. The last
+// rewritten DDL is recorded in the changefeed status.
+func (c *changefeed) rewriteDDLEvents(
+	ddlEvents []*model.DDLEvent,
+) ([]*model.DDLEvent, error) {
+	res := ddlEvents[:0]
+	for _, ddlEvent := range ddlEvents {
+		query, err := c.ddlRewriter.Rewrite(ddlEvent)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if query != ddlEvent.Query {
+			log.Info("DDL is rewritten",
+				zap.String("namespace", c.id.Namespace),
+				zap.String("changefeed", c.id.ID),
+				zap.Uint64("commitTs", ddlEvent.CommitTs),
+				zap.String("query", ddlEvent.Query),
+				zap.String("rewrittenQuery", query))
+			rewritten := &model.RewrittenDDL{
+				CommitTs:       ddlEvent.CommitTs,
+				OriginalQuery:  ddlEvent.Query,
+				RewrittenQuery: query,
+			}
+			c.state.PatchStatus(
+				func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+					if status == nil {
+						return nil, false, nil
+					}
+					status.LastRewrittenDDL = rewritten
+					return status, true, nil
+				})
+			ddlEvent.Query = query
+		}
+		if ddlEvent.Query == "" {
+			continue
+		}
+		res = append(res, ddlEvent)
+	}
+	return res, nil
+}
+
 func (c *changefeed) asyncExecDDLEvent(ctx cdcContext.Context,
 	ddlEvent *model.DDLEvent,
 ) (done bool, err error) {
@@ -807,6 +944,21 @@ func (c *changefeed) GetInfoProvider() scheduler.InfoProvider {
 	return nil
 }
 
+// schemaResolvedTs returns the max ts at which the schema of the owner is
+// complete, that is, all DDL jobs finished before or at it are handled.
+func (c *changefeed) schemaResolvedTs() model.Ts {
+	ddlResolvedTs, job := c.ddlPuller.FrontDDL()
+	if job == nil || job.BinlogInfo == nil {
+		return ddlResolvedTs
+	}
+	if job.BinlogInfo.FinishedTS <= c.schema.ddlHandledTs {
+		// The front DDL job is being executed and it is already handled
+		// by the schema.
+		return c.schema.ddlHandledTs
+	}
+	return job.BinlogInfo.FinishedTS - 1
+}
+
 // addSpecialComment translate tidb feature to comment
 func addSpecialComment(ddlQuery string) (string, error) {
 	stms, _, err := parser.New().ParseSQL(ddlQuery)
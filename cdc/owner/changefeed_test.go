@@ -87,6 +87,11 @@ type mockDDLSink struct {
 	syncPoint    model.Ts
 	syncPointHis []model.Ts
 
+	// the partition numbers of the MQ sink
+	partitionNums        map[string]int32
+	pendingPartitionNums map[string]int32
+	partitionChangeTs    model.Ts
+
 	wg sync.WaitGroup
 }
 
@@ -122,6 +127,39 @@ func (m *mockDDLSink) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64)
 	return nil
 }
 
+func (m *mockDDLSink) getPartitionChanges() (
+	oldPartitionNums, newPartitionNums map[string]int32, err error,
+) {
+	if len(m.pendingPartitionNums) == 0 {
+		return nil, nil, nil
+	}
+	oldPartitionNums = make(map[string]int32)
+	newPartitionNums = make(map[string]int32)
+	for topic, partitionNum := range m.pendingPartitionNums {
+		oldPartitionNums[topic] = m.partitionNums[topic]
+		newPartitionNums[topic] = partitionNum
+	}
+	return oldPartitionNums, newPartitionNums, nil
+}
+
+func (m *mockDDLSink) applyPartitionNums(partitionNums map[string]int32) {
+	if m.partitionNums == nil {
+		m.partitionNums = make(map[string]int32)
+	}
+	for topic, partitionNum := range partitionNums {
+		m.partitionNums[topic] = partitionNum
+		delete(m.pendingPartitionNums, topic)
+	}
+}
+
+func (m *mockDDLSink) emitPartitionChange(
+	ctx cdcContext.Context, barrierTs uint64, partitionNums map[string]int32,
+) error {
+	m.partitionChangeTs = barrierTs
+	m.applyPartitionNums(partitionNums)
+	return nil
+}
+
 func (m *mockDDLSink) emitCheckpointTs(ts uint64, tableNames []model.TableName) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -150,6 +188,7 @@ func (m *mockDDLSink) Barrier(ctx context.Context) error {
 
 type mockScheduler struct {
 	currentTables []model.TableID
+	resyncTables  []model.TableID
 }
 
 func (m *mockScheduler) Tick(
@@ -162,12 +201,36 @@ func (m *mockScheduler) Tick(
 	return model.Ts(math.MaxUint64), model.Ts(math.MaxUint64), nil
 }
 
+// mockWatermarkScheduler returns the given watermarks instead of the
+// barrier ts, which simulates the tables replicated slowly.
+type mockWatermarkScheduler struct {
+	mockScheduler
+	checkpointTs model.Ts
+	resolvedTs   model.Ts
+}
+
+func (m *mockWatermarkScheduler) Tick(
+	ctx context.Context,
+	checkpointTs model.Ts,
+	currentTables []model.TableID,
+	captures map[model.CaptureID]*model.CaptureInfo,
+) (newCheckpointTs, newResolvedTs model.Ts, err error) {
+	m.currentTables = currentTables
+	return m.checkpointTs, m.resolvedTs, nil
+}
+
 // MoveTable is used to trigger manual table moves.
 func (m *mockScheduler) MoveTable(tableID model.TableID, target model.CaptureID) {}
 
 // Rebalance is used to trigger manual workload rebalances.
 func (m *mockScheduler) Rebalance() {}
 
+// ResyncTables is used to trigger a resync of tables.
+func (m *mockScheduler) ResyncTables(tableIDs []model.TableID) error {
+	m.resyncTables = append(m.resyncTables, tableIDs...)
+	return nil
+}
+
 // DrainCapture implement scheduler interface
 func (m *mockScheduler) DrainCapture(target model.CaptureID) (int, error) {
 	return 0, nil
@@ -445,6 +508,77 @@ func TestSyncPoint(t *testing.T) {
 	require.GreaterOrEqual(t, len(mockDDLSink.syncPointHis), 5)
 }
 
+func TestPartitionChange(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	cf, state, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+	startTs := ctx.ChangefeedVars().Info.StartTs
+	mockScheduler := &mockWatermarkScheduler{
+		checkpointTs: startTs + 10, resolvedTs: startTs + 20,
+	}
+	cf.newScheduler = func(
+		ctx cdcContext.Context, startTs uint64,
+	) (scheduler.Scheduler, error) {
+		return mockScheduler, nil
+	}
+
+	// pre check
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+
+	// initialize
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+
+	mockDDLPuller := cf.ddlPuller.(*mockDDLPuller)
+	mockDDLPuller.resolvedTs += 1000
+	ddlSink := cf.sink.(*mockDDLSink)
+	ddlSink.partitionNums = map[string]int32{"t1": 2, "t2": 3}
+	for i := 0; i < 2; i++ {
+		cf.Tick(ctx, state, captures)
+		tester.MustApplyPatches()
+	}
+	require.Equal(t, startTs+10, state.Status.CheckpointTs)
+	require.Equal(t, startTs+20, state.Status.ResolvedTs)
+
+	// The partition number of t1 is changed, the resolved ts is blocked
+	// at the barrier and the old partition number is pinned.
+	ddlSink.pendingPartitionNums = map[string]int32{"t1": 4}
+	mockScheduler.resolvedTs = startTs + 30
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.Equal(t, startTs+10, state.Status.CheckpointTs)
+	require.Equal(t, startTs+20, state.Status.ResolvedTs)
+	require.Equal(t, map[string]int32{"t1": 2}, state.Status.PartitionNums)
+	require.Zero(t, ddlSink.partitionChangeTs)
+
+	// The change is applied once all the captures have flushed
+	// the events before the barrier.
+	mockScheduler.checkpointTs = startTs + 30
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.Equal(t, startTs+20, state.Status.CheckpointTs)
+	require.Zero(t, ddlSink.partitionChangeTs)
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.Equal(t, startTs+20, ddlSink.partitionChangeTs)
+	require.Equal(t, map[string]int32{"t1": 4}, state.Status.PartitionNums)
+	require.Equal(t, int32(4), ddlSink.partitionNums["t1"])
+	require.Equal(t, startTs+20, state.Status.ResolvedTs)
+
+	// The resolved ts goes on after the change is applied.
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.Equal(t, startTs+30, state.Status.CheckpointTs)
+	require.Equal(t, startTs+30, state.Status.ResolvedTs)
+
+	// The DDL sink of a new owner uses the partition numbers in the status.
+	cf.releaseResources(ctx)
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	require.Equal(t, map[string]int32{"t1": 4}, cf.sink.(*mockDDLSink).partitionNums)
+}
+
 func TestFinished(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	ctx.ChangefeedVars().Info.TargetTs = ctx.ChangefeedVars().Info.StartTs + 1000
@@ -916,6 +1050,60 @@ func TestExecDropTablesDDL(t *testing.T) {
 	execDropStmt(jobs[1], "DROP TABLE `test1`.`tb1`")
 }
 
+func TestExecRewrittenDDL(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx.ChangefeedVars().Info.Config.Filter.DDLRewriteRules = []*config.DDLRewriteRule{
+		{Matcher: []string{"test1.*"}, Action: config.DDLRewriteStripShardRowIDBits},
+		{Matcher: []string{"test1.*"}, Action: config.DDLRewriteTruncateToDelete},
+	}
+	cf, state, captures, tester := createChangefeed4Test(ctx, t)
+	defer cf.Close(ctx)
+
+	// pre check
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+	// initialize
+	cf.Tick(ctx, state, captures)
+	tester.MustApplyPatches()
+
+	mockDDLSink := cf.sink.(*mockDDLSink)
+	execStmt := func(actualDDL, expectedDDL string) {
+		job := helper.DDL2Job(actualDDL)
+		done, err := cf.asyncExecDDLJob(ctx, job)
+		require.Nil(t, err)
+		require.Equal(t, false, done)
+		require.Equal(t, expectedDDL, mockDDLSink.ddlExecuting.Query)
+		mockDDLSink.ddlDone = true
+		done, err = cf.asyncExecDDLJob(ctx, job)
+		require.Nil(t, err)
+		require.Equal(t, true, done)
+		tester.MustApplyPatches()
+	}
+
+	execStmt("create database test1", "CREATE DATABASE `test1`")
+	require.Nil(t, state.Status.LastRewrittenDDL)
+	execStmt("create table test1.tb1(id int primary key nonclustered) shard_row_id_bits = 2",
+		"CREATE TABLE `test1`.`tb1` (`id` INT PRIMARY KEY /*T![clustered_index] NONCLUSTERED */)")
+	require.Equal(t, "create table test1.tb1(id int primary key nonclustered) shard_row_id_bits = 2",
+		state.Status.LastRewrittenDDL.OriginalQuery)
+	execStmt("truncate table test1.tb1", "DELETE FROM `test1`.`tb1`")
+	require.Equal(t, "DELETE FROM `test1`.`tb1`",
+		state.Status.LastRewrittenDDL.RewrittenQuery)
+
+	// the DDL is skipped if nothing is left after rewriting
+	mockDDLSink.ddlExecuting = nil
+	job := helper.DDL2Job("alter table test1.tb1 shard_row_id_bits = 4")
+	done, err := cf.asyncExecDDLJob(ctx, job)
+	require.Nil(t, err)
+	require.True(t, done)
+	require.Nil(t, mockDDLSink.ddlExecuting)
+	tester.MustApplyPatches()
+	require.Equal(t, job.BinlogInfo.FinishedTS, state.Status.LastRewrittenDDL.CommitTs)
+	require.Equal(t, "", state.Status.LastRewrittenDDL.RewrittenQuery)
+}
+
 func TestExecDropViewsDDL(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -25,7 +25,11 @@ import (
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	ddlsinkfactory "github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/factory"
+	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -51,6 +55,15 @@ type DDLSink interface {
 	// the caller of this function can call again and again until a true returned
 	emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent) (bool, error)
 	emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error
+	// getPartitionChanges returns the partition numbers used to dispatch the
+	// events and the new partition numbers of the topics whose partition
+	// numbers have been changed, see mq.PartitionChangeSink.
+	getPartitionChanges() (oldPartitionNums, newPartitionNums map[string]int32, err error)
+	// applyPartitionNums makes the sink use the given partition numbers.
+	applyPartitionNums(partitionNums map[string]int32)
+	// emitPartitionChange applies the new partition numbers at the barrier ts.
+	// It must be called when the checkpoint ts reaches the barrier ts.
+	emitPartitionChange(ctx cdcContext.Context, barrierTs uint64, partitionNums map[string]int32) error
 	// close the sink, cancel running goroutine.
 	close(ctx context.Context) error
 	isInitialized() bool
@@ -74,6 +87,9 @@ type ddlSinkImpl struct {
 	errCh chan error
 
 	sink sink.Sink
+	// sinkV2 is used instead of sink if the new sink is enabled and
+	// supports the sink URI.
+	sinkV2 ddlsink.DDLEventSink
 	// `sinkInitHandler` can be helpful in unit testing.
 	sinkInitHandler ddlSinkInitHandler
 
@@ -103,11 +119,20 @@ type ddlSinkInitHandler func(ctx cdcContext.Context, a *ddlSinkImpl, id model.Ch
 func ddlSinkInitializer(ctx cdcContext.Context, a *ddlSinkImpl, id model.ChangeFeedID, info *model.ChangeFeedInfo) error {
 	stdCtx := contextutil.PutChangefeedIDInCtx(ctx, id)
 	stdCtx = contextutil.PutRoleInCtx(stdCtx, util.RoleOwner)
-	s, err := sink.New(stdCtx, id, info.SinkURI, info.Config, a.errCh)
-	if err != nil {
-		return errors.Trace(err)
+	if config.GetGlobalServerConfig().Debug.EnableNewSink &&
+		ddlsinkfactory.IsSupported(info.SinkURI) {
+		s, err := ddlsinkfactory.New(stdCtx, info.SinkURI, info.Config)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		a.sinkV2 = s
+	} else {
+		s, err := sink.New(stdCtx, id, info.SinkURI, info.Config, a.errCh)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		a.sink = s
 	}
-	a.sink = s
 
 	if !info.SyncPointEnabled {
 		return nil
@@ -182,7 +207,7 @@ func (s *ddlSinkImpl) run(ctx cdcContext.Context, id model.ChangeFeedID, info *m
 				tables := s.mu.currentTableNames
 				s.mu.Unlock()
 				lastCheckpointTs = checkpointTs
-				if err := s.sink.EmitCheckpointTs(ctx, checkpointTs, tables); err != nil {
+				if err := s.emitCheckpointTsToSink(ctx, checkpointTs, tables); err != nil {
 					ctx.Throw(errors.Trace(err))
 					return
 				}
@@ -191,15 +216,25 @@ func (s *ddlSinkImpl) run(ctx cdcContext.Context, id model.ChangeFeedID, info *m
 					zap.String("namespace", ctx.ChangefeedVars().ID.Namespace),
 					zap.String("changefeed", ctx.ChangefeedVars().ID.ID),
 					zap.Any("DDL", ddl))
-				err := s.sink.EmitDDLEvent(ctx, ddl)
+				err := s.emitDDLEventToSink(ctx, ddl)
 				failpoint.Inject("InjectChangefeedDDLError", func() {
 					err = cerror.ErrExecDDLFailed.GenWithStackByArgs()
 				})
+				ignored := false
+				if err != nil && info.Config != nil && info.Config.Retry.ShouldSkipDDLError(err) {
+					log.Warn("Execute DDL failed, skip it by the retry config",
+						zap.String("namespace", ctx.ChangefeedVars().ID.Namespace),
+						zap.String("changefeed", ctx.ChangefeedVars().ID.ID),
+						zap.Error(err),
+						zap.Any("ddl", ddl))
+					ignored = true
+					err = nil
+				}
 				if err == nil {
 					log.Info("Execute DDL succeeded",
 						zap.String("namespace", ctx.ChangefeedVars().ID.Namespace),
 						zap.String("changefeed", ctx.ChangefeedVars().ID.ID),
-						zap.Bool("ignored", err != nil),
+						zap.Bool("ignored", ignored),
 						zap.Any("ddl", ddl))
 					// Force emitting checkpoint ts when a ddl event is finished.
 					// Otherwise, a kafka consumer may not execute that ddl event.
@@ -213,7 +248,7 @@ func (s *ddlSinkImpl) run(ctx cdcContext.Context, id model.ChangeFeedID, info *m
 					tables := s.mu.currentTableNames
 					s.mu.Unlock()
 					lastCheckpointTs = checkpointTs
-					if err := s.sink.EmitCheckpointTs(ctx, checkpointTs, tables); err != nil {
+					if err := s.emitCheckpointTsToSink(ctx, checkpointTs, tables); err != nil {
 						ctx.Throw(errors.Trace(err))
 						return
 					}
@@ -288,6 +323,22 @@ func (s *ddlSinkImpl) emitDDLEvent(ctx cdcContext.Context, ddl *model.DDLEvent)
 	return false, nil
 }
 
+func (s *ddlSinkImpl) emitCheckpointTsToSink(
+	ctx context.Context, ts uint64, tables []model.TableName,
+) error {
+	if s.sinkV2 != nil {
+		return s.sinkV2.WriteCheckpointTs(ctx, ts, tables)
+	}
+	return s.sink.EmitCheckpointTs(ctx, ts, tables)
+}
+
+func (s *ddlSinkImpl) emitDDLEventToSink(ctx context.Context, ddl *model.DDLEvent) error {
+	if s.sinkV2 != nil {
+		return s.sinkV2.WriteDDLEvent(ctx, ddl)
+	}
+	return s.sink.EmitDDLEvent(ctx, ddl)
+}
+
 func (s *ddlSinkImpl) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64) error {
 	if checkpointTs == s.lastSyncPoint {
 		return nil
@@ -297,11 +348,38 @@ func (s *ddlSinkImpl) emitSyncPoint(ctx cdcContext.Context, checkpointTs uint64)
 	return s.syncPointStore.SinkSyncpoint(ctx, ctx.ChangefeedVars().ID, checkpointTs)
 }
 
+func (s *ddlSinkImpl) getPartitionChanges() (
+	oldPartitionNums, newPartitionNums map[string]int32, err error,
+) {
+	if changeSink, ok := s.sink.(mq.PartitionChangeSink); ok {
+		return changeSink.GetPartitionChanges()
+	}
+	return nil, nil, nil
+}
+
+func (s *ddlSinkImpl) applyPartitionNums(partitionNums map[string]int32) {
+	if changeSink, ok := s.sink.(mq.PartitionChangeSink); ok {
+		changeSink.ApplyPartitionNums(partitionNums)
+	}
+}
+
+func (s *ddlSinkImpl) emitPartitionChange(
+	ctx cdcContext.Context, barrierTs uint64, partitionNums map[string]int32,
+) error {
+	if changeSink, ok := s.sink.(mq.PartitionChangeSink); ok {
+		return changeSink.EmitPartitionChange(ctx, barrierTs, partitionNums)
+	}
+	return nil
+}
+
 func (s *ddlSinkImpl) close(ctx context.Context) (err error) {
 	s.cancel()
 	if s.sink != nil {
 		err = s.sink.Close(ctx)
 	}
+	if s.sinkV2 != nil {
+		err = s.sinkV2.Close()
+	}
 	if s.syncPointStore != nil {
 		err = s.syncPointStore.Close()
 	}
@@ -167,3 +167,33 @@ func TestExecDDLError(t *testing.T) {
 	}
 	require.True(t, cerror.ErrExecDDLFailed.Equal(readResultErr()))
 }
+
+func TestExecDDLErrorSkipped(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx = cdcContext.WithErrorHandler(ctx, func(err error) error {
+		require.FailNow(t, "unexpected error", err.Error())
+		return nil
+	})
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	ddlSink, mSink := newDDLSink4Test()
+	defer func() {
+		cancel()
+		ddlSink.close(ctx)
+	}()
+
+	info, err := ctx.ChangefeedVars().Info.Clone()
+	require.Nil(t, err)
+	info.Config.Retry.AutoSkipDDLErrors = []string{"Error 1050"}
+	ddlSink.run(ctx, ctx.ChangefeedVars().ID, info)
+
+	mSink.ddlError = errors.New("Error 1050: Table 't' already exists")
+	ddl := &model.DDLEvent{CommitTs: 2}
+	for {
+		done, err := ddlSink.emitDDLEvent(ctx, ddl)
+		require.Nil(t, err)
+		if done {
+			require.Equal(t, mSink.GetDDL(), ddl)
+			break
+		}
+	}
+}
@@ -17,9 +17,9 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
 	"go.uber.org/zap"
@@ -39,6 +39,10 @@ const (
 	// is running steady. And then if we enter a state other than normal at next tick,
 	// the backoff must be reset.
 	defaultStateWindowSize = 512
+
+	// defaultLastErrorsSize is the number of the most recent errors recorded
+	// in the changefeed status.
+	defaultLastErrorsSize = 10
 )
 
 // feedStateManager manages the ReactorState of a changefeed
@@ -56,6 +60,24 @@ type feedStateManager struct {
 	lastErrorTime   time.Time                   // time of last error for a changefeed
 	backoffInterval time.Duration               // the interval for restarting a changefeed in 'error' state
 	errBackoff      *backoff.ExponentialBackOff // an exponential backoff for restarting a changefeed
+	// firstRetryTime is the time of the first error since the changefeed
+	// was last stable, it is zero if the changefeed is not retrying.
+	firstRetryTime time.Time
+	// retryInitInterval and retryMaxInterval are the backoff intervals last
+	// applied from the retry config, they are zero if nothing is applied.
+	retryInitInterval time.Duration
+	retryMaxInterval  time.Duration
+
+	// notifier posts an event on every state transition, it can be nil.
+	notifier *notification.Notifier
+	// pendingEvent is the state transition patched in the last tick, it is
+	// sent in the next tick once the patch is committed. A patch may be
+	// applied more than once if the etcd txn is retried, so the event can
+	// not be sent in the patch.
+	pendingEvent *notification.Event
+	// stateRederived is true once the current state is compared with the
+	// pending events in the notifier after the owner takes over.
+	stateRederived bool
 }
 
 // newFeedStateManager creates feedStateManager and initialize the exponential backoff
@@ -104,6 +126,43 @@ func (m *feedStateManager) resetErrBackoff() {
 	m.backoffInterval = m.errBackoff.NextBackOff()
 }
 
+// applyRetryConfig applies the backoff intervals in the retry config of
+// the changefeed, the backoff is reset if they are changed.
+func (m *feedStateManager) applyRetryConfig() {
+	if m.state.Info.Config == nil || m.state.Info.Config.Retry == nil {
+		return
+	}
+	retry := m.state.Info.Config.Retry
+	initInterval := time.Duration(retry.BackoffInitInterval)
+	maxInterval := time.Duration(retry.BackoffMaxInterval)
+	if initInterval <= 0 || maxInterval < initInterval {
+		return
+	}
+	if initInterval == m.retryInitInterval && maxInterval == m.retryMaxInterval {
+		return
+	}
+	firstApplied := m.retryInitInterval == 0
+	m.retryInitInterval, m.retryMaxInterval = initInterval, maxInterval
+	if firstApplied && initInterval == defaultBackoffInitInterval &&
+		maxInterval == defaultBackoffMaxInterval {
+		// The default intervals are the same as the backoff created with
+		// the feedStateManager, there is nothing to apply.
+		return
+	}
+	m.errBackoff.InitialInterval = initInterval
+	m.errBackoff.MaxInterval = maxInterval
+	m.resetErrBackoff()
+}
+
+// maxRetryDuration returns the max duration the changefeed keeps retrying
+// on retryable errors, 0 means retrying forever.
+func (m *feedStateManager) maxRetryDuration() time.Duration {
+	if m.state.Info.Config == nil || m.state.Info.Config.Retry == nil {
+		return 0
+	}
+	return time.Duration(m.state.Info.Config.Retry.MaxRetryDuration)
+}
+
 // isChangefeedStable check if there are states other than 'normal' in this sliding window.
 func (m *feedStateManager) isChangefeedStable() bool {
 	for _, val := range m.stateHistory {
@@ -126,6 +185,7 @@ func (m *feedStateManager) shiftStateWindow(state model.FeedState) {
 
 func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adminJobPending bool) {
 	m.state = state
+	m.notifyStateChanged()
 	m.shouldBeRunning = true
 	defer func() {
 		if m.shouldBeRunning {
@@ -140,6 +200,7 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 		adminJobPending = true
 		return
 	}
+	m.applyRetryConfig()
 	switch m.state.Info.State {
 	case model.StateRemoved:
 		m.shouldBeRunning = false
@@ -159,6 +220,43 @@ func (m *feedStateManager) Tick(state *orchestrator.ChangefeedReactorState) (adm
 	return
 }
 
+// notifyStateChanged sends the state transition patched in the last tick
+// if it is committed, otherwise the transition is dropped.
+func (m *feedStateManager) notifyStateChanged() {
+	if !m.stateRederived {
+		m.stateRederived = true
+		m.rederiveStateChanged()
+	}
+	event := m.pendingEvent
+	if event == nil {
+		return
+	}
+	m.pendingEvent = nil
+	if m.state.Info == nil || m.state.Info.State != event.NewState {
+		return
+	}
+	// The error patched in the same tick is committed together.
+	event.Error = m.state.Info.Error
+	m.notifier.Notify(event)
+}
+
+// rederiveStateChanged notifies the current state of the changefeed in the
+// first tick after the owner takes over. The previous owner may crash after
+// a state transition is committed but before it is notified, so the state
+// is notified again if it is not normal and no event for it is pending. The
+// old state is unknown, so it is left empty.
+func (m *feedStateManager) rederiveStateChanged() {
+	if m.state.Info == nil || m.state.Info.State == model.StateNormal {
+		return
+	}
+	state := m.state.Info.State
+	if m.notifier.IsPending(m.state.ID, state) {
+		return
+	}
+	m.notifier.Notify(notification.NewChangefeedStateChangedEvent(
+		m.state.ID, "", state, m.state.Info.Error))
+}
+
 func (m *feedStateManager) ShouldRunning() bool {
 	return m.shouldBeRunning
 }
@@ -212,6 +310,15 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		}
 		m.shouldBeRunning = false
 		jobsPending = true
+		if job.Error != nil {
+			m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+				if info == nil {
+					return nil, false, nil
+				}
+				info.Error = job.Error
+				return info, true, nil
+			})
+		}
 		m.patchState(model.StateStopped)
 	case model.AdminRemove:
 
@@ -259,6 +366,7 @@ func (m *feedStateManager) handleAdminJob() (jobsPending bool) {
 		m.resetErrBackoff()
 		// The lastErrorTime also needs to be cleared before a fresh run.
 		m.lastErrorTime = time.Unix(0, 0)
+		m.firstRetryTime = time.Time{}
 		jobsPending = true
 		m.patchState(model.StateNormal)
 
@@ -363,6 +471,13 @@ func (m *feedStateManager) patchState(feedState model.FeedState) {
 			return nil, changed, nil
 		}
 		if info.State != feedState {
+			oldState := info.State
+			if m.pendingEvent != nil {
+				// The state is patched more than once in a tick.
+				oldState = m.pendingEvent.OldState
+			}
+			m.pendingEvent = notification.NewChangefeedStateChangedEvent(
+				m.state.ID, oldState, feedState, nil)
 			info.State = feedState
 			changed = true
 		}
@@ -413,11 +528,39 @@ func (m *feedStateManager) errorsReportedByProcessors() []*model.RunningError {
 	return result
 }
 
+// recordErrors records the errors with their classes in the changefeed
+// status, only the most recent defaultLastErrorsSize errors are kept.
+func (m *feedStateManager) recordErrors(errs []*model.RunningError) {
+	if len(errs) == 0 {
+		return
+	}
+	now := time.Now()
+	m.state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		if status == nil {
+			return nil, false, nil
+		}
+		for _, err := range errs {
+			status.LastErrors = append(status.LastErrors, &model.RecordedError{
+				RunningError: *err,
+				Class:        err.ErrorClass(),
+				Time:         now,
+			})
+		}
+		if len(status.LastErrors) > defaultLastErrorsSize {
+			status.LastErrors = append([]*model.RecordedError(nil),
+				status.LastErrors[len(status.LastErrors)-defaultLastErrorsSize:]...)
+		}
+		return status, true, nil
+	})
+}
+
 func (m *feedStateManager) handleError(errs ...*model.RunningError) {
-	// if there are a fastFail error in errs, we can just fastFail the changefeed
+	m.recordErrors(errs)
+
+	// if there are a fatal error in errs, we can just fastFail the changefeed
 	// and no need to patch other error to the changefeed info
 	for _, err := range errs {
-		if cerrors.IsChangefeedFastFailErrorCode(errors.RFCErrorCode(err.Code)) {
+		if err.ErrorClass() == cerrors.ErrorClassFatal {
 			m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
 				if info == nil {
 					return nil, false, nil
@@ -431,11 +574,11 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 		}
 	}
 
-	// we need to patch changefeed unretryable error to the changefeed info,
-	// so we have to iterate all errs here to check wether it is a unretryable
-	// error in errs
+	// we need to patch the error requiring operator action to the changefeed
+	// info, so we have to iterate all errs here to check whether there is
+	// such an error in errs
 	for _, err := range errs {
-		if err.IsChangefeedUnRetryableError() {
+		if err.ErrorClass() == cerrors.ErrorClassOperatorAction {
 			m.state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
 				if info == nil {
 					return nil, false, nil
@@ -468,6 +611,10 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 		m.lastErrorTime = time.Now()
 		if m.isChangefeedStable() {
 			m.resetErrBackoff()
+			m.firstRetryTime = time.Time{}
+		}
+		if m.firstRetryTime.IsZero() {
+			m.firstRetryTime = m.lastErrorTime
 		}
 	} else {
 		if m.state.Info.State == model.StateNormal {
@@ -476,6 +623,21 @@ func (m *feedStateManager) handleError(errs ...*model.RunningError) {
 	}
 	m.shiftStateWindow(m.state.Info.State)
 
+	// The changefeed has been retrying for too long, the errors are unlikely
+	// to be transient, so fail it to notify the operator.
+	maxRetryDuration := m.maxRetryDuration()
+	if len(errs) > 0 && maxRetryDuration > 0 &&
+		time.Since(m.firstRetryTime) > maxRetryDuration {
+		log.Warn("changefeed is failed since it keeps retrying for too long",
+			zap.String("namespace", m.state.ID.Namespace),
+			zap.String("changefeed", m.state.ID.ID),
+			zap.Time("firstRetryTime", m.firstRetryTime),
+			zap.Duration("maxRetryDuration", maxRetryDuration))
+		m.shouldBeRunning = false
+		m.patchState(model.StateFailed)
+		return
+	}
+
 	if m.lastErrorTime == time.Unix(0, 0) {
 		return
 	}
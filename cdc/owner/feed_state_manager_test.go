@@ -14,16 +14,23 @@
 package owner
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
+	"github.com/pingcap/tiflow/pkg/orchestrator/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -585,3 +592,356 @@ func TestBackoffNeverStops(t *testing.T) {
 		tester.MustApplyPatches()
 	}
 }
+
+func TestHandleErrorRecordLastErrors(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager4Test(10, 10, 0, 1.0)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	for i := 0; i < defaultLastErrorsSize+2; i++ {
+		manager.handleError(&model.RunningError{
+			Code:    "CDC:ErrMySQLTxnError",
+			Message: fmt.Sprintf("fake error %d", i),
+		})
+		tester.MustApplyPatches()
+	}
+	require.Len(t, state.Status.LastErrors, defaultLastErrorsSize)
+	require.Equal(t, "fake error 2", state.Status.LastErrors[0].Message)
+	require.Equal(t, cerror.ErrorClassRetryable, state.Status.LastErrors[0].Class)
+	require.False(t, state.Status.LastErrors[0].Time.IsZero())
+
+	// the error requiring operator action stops the changefeed
+	manager.handleError(&model.RunningError{
+		Code:    "CDC:ErrSinkURIInvalid",
+		Message: "fake error for test",
+	})
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	require.Equal(t, model.StateError, state.Info.State)
+	require.Equal(t, cerror.ErrorClassOperatorAction,
+		state.Status.LastErrors[defaultLastErrorsSize-1].Class)
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+}
+
+func TestApplyRetryConfig(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager()
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{
+			Retry: &config.RetryConfig{
+				BackoffInitInterval: config.TomlDuration(time.Second),
+				BackoffMaxInterval:  config.TomlDuration(time.Minute),
+			},
+		}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	require.Equal(t, defaultBackoffInitInterval, manager.errBackoff.InitialInterval)
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, time.Second, manager.errBackoff.InitialInterval)
+	require.Equal(t, time.Minute, manager.errBackoff.MaxInterval)
+	require.LessOrEqual(t, manager.backoffInterval, 2*time.Second)
+}
+
+func TestMaxRetryDuration(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	manager := newFeedStateManager()
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{SinkURI: "123", Config: &config.ReplicaConfig{
+			Retry: &config.RetryConfig{
+				MaxRetryDuration:    config.TomlDuration(500 * time.Millisecond),
+				BackoffInitInterval: config.TomlDuration(100 * time.Millisecond),
+				BackoffMaxInterval:  config.TomlDuration(100 * time.Millisecond),
+			},
+		}}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	start := time.Now()
+	for {
+		require.True(t, manager.ShouldRunning())
+		state.PatchTaskPosition(ctx.GlobalVars().CaptureInfo.ID,
+			func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+				return &model.TaskPosition{Error: &model.RunningError{
+					Addr:    ctx.GlobalVars().CaptureInfo.AdvertiseAddr,
+					Code:    "CDC:ErrMySQLTxnError",
+					Message: "fake error for test",
+				}}, true, nil
+			})
+		tester.MustApplyPatches()
+		manager.Tick(state)
+		tester.MustApplyPatches()
+		require.False(t, manager.ShouldRunning())
+		if state.Info.State == model.StateFailed {
+			break
+		}
+		// the changefeed keeps retrying until the max retry duration exceeded
+		require.Equal(t, model.StateError, state.Info.State)
+		require.Less(t, time.Since(start), time.Second)
+		time.Sleep(120 * time.Millisecond)
+		manager.Tick(state)
+		tester.MustApplyPatches()
+	}
+	require.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	require.Equal(t, "fake error for test", state.Info.Error.Message)
+
+	// the failed changefeed can be resumed manually
+	manager.PushAdminJob(&model.AdminJob{
+		CfID: ctx.ChangefeedVars().ID,
+		Type: model.AdminResume,
+	})
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.True(t, manager.ShouldRunning())
+	require.True(t, manager.firstRetryTime.IsZero())
+}
+
+// newNotifier4Test creates a notifier posting the events to a test webhook,
+// the received events are sent to the returned channel.
+func newNotifier4Test(t *testing.T) (*notification.Notifier, <-chan *notification.Event) {
+	eventCh := make(chan *notification.Event, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		event := &notification.Event{}
+		require.Nil(t, json.NewDecoder(req.Body).Decode(event))
+		eventCh <- event
+	}))
+	notifier := notification.NewNotifier(&config.NotificationConfig{
+		Webhooks:  []*config.WebhookConfig{{URL: srv.URL}},
+		QueueSize: 16,
+		Timeout:   config.TomlDuration(time.Second),
+	}, etcd.DefaultCDCClusterID, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = notifier.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+		srv.Close()
+	})
+	return notifier, eventCh
+}
+
+func TestPatchStateNotify(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	notifier, eventCh := newNotifier4Test(t)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	manager.notifier = notifier
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		require.Nil(t, info)
+		return &model.ChangeFeedInfo{
+			SinkURI: "123", Config: &config.ReplicaConfig{}, State: model.StateNormal,
+		}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		require.Nil(t, status)
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	// No event is sent if the state is not changed.
+	select {
+	case event := <-eventCh:
+		require.FailNow(t, "unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	state.PatchTaskPosition(ctx.GlobalVars().CaptureInfo.ID,
+		func(position *model.TaskPosition) (*model.TaskPosition, bool, error) {
+			return &model.TaskPosition{Error: &model.RunningError{
+				Addr:    ctx.GlobalVars().CaptureInfo.AdvertiseAddr,
+				Code:    "CDC:ErrGCTTLExceeded",
+				Message: "fake error for test",
+			}}, true, nil
+		})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.Equal(t, model.StateFailed, state.Info.State)
+
+	// The event is sent in the next tick after the patch is committed.
+	select {
+	case event := <-eventCh:
+		require.FailNow(t, "unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+	manager.Tick(state)
+	tester.MustApplyPatches()
+
+	select {
+	case event := <-eventCh:
+		require.Equal(t, notification.EventChangefeedStateChanged, event.Type)
+		require.Equal(t, etcd.DefaultCDCClusterID, event.ClusterID)
+		require.Equal(t, ctx.ChangefeedVars().ID.Namespace, event.Namespace)
+		require.Equal(t, ctx.ChangefeedVars().ID.ID, event.Changefeed)
+		require.Equal(t, model.StateNormal, event.OldState)
+		require.Equal(t, model.StateFailed, event.NewState)
+		require.Equal(t, "CDC:ErrGCTTLExceeded", event.Error.Code)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "event is not received")
+	}
+
+	// The event is sent only once.
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	select {
+	case event := <-eventCh:
+		require.FailNow(t, "unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRederiveStateOnOwnerTakeover(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	notifier, eventCh := newNotifier4Test(t)
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		return &model.ChangeFeedInfo{
+			SinkURI: "123", Config: &config.ReplicaConfig{}, State: model.StateStopped,
+			AdminJobType: model.AdminStop,
+		}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		return &model.ChangeFeedStatus{AdminJobType: model.AdminStop}, true, nil
+	})
+	tester.MustApplyPatches()
+
+	// The new owner notifies the state which the previous owner may not
+	// have notified before it crashed.
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	manager.notifier = notifier
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	require.False(t, manager.ShouldRunning())
+	select {
+	case event := <-eventCh:
+		require.Equal(t, notification.EventChangefeedStateChanged, event.Type)
+		require.Equal(t, ctx.ChangefeedVars().ID.ID, event.Changefeed)
+		require.Equal(t, model.FeedState(""), event.OldState)
+		require.Equal(t, model.StateStopped, event.NewState)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "event is not received")
+	}
+
+	// The state is notified only once.
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	select {
+	case event := <-eventCh:
+		require.FailNow(t, "unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPatchStateNotifyAfterCommit(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	notifier, eventCh := newNotifier4Test(t)
+	manager := newFeedStateManager4Test(200, 1600, 0, 2.0)
+	manager.notifier = notifier
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		ctx.ChangefeedVars().ID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
+		return &model.ChangeFeedInfo{
+			SinkURI: "123", Config: &config.ReplicaConfig{}, State: model.StateNormal,
+		}, true, nil
+	})
+	state.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		return &model.ChangeFeedStatus{}, true, nil
+	})
+	tester.MustApplyPatches()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	expectNoEvent := func() {
+		select {
+		case event := <-eventCh:
+			require.FailNow(t, "unexpected event", "%v", event)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// The etcd txn is retried, so the patches are applied twice
+	// before they are committed.
+	manager.PushAdminJob(&model.AdminJob{CfID: ctx.ChangefeedVars().ID, Type: model.AdminStop})
+	manager.Tick(state)
+	patches := state.GetPatches()[0]
+	var valueMap map[util.EtcdKey][]byte
+	for i := 0; i < 2; i++ {
+		valueMap = make(map[util.EtcdKey][]byte)
+		for key, value := range tester.KVEntries() {
+			valueMap[util.NewEtcdKey(key)] = []byte(value)
+		}
+		for _, patch := range patches {
+			err := patch.Patch(valueMap, make(map[util.EtcdKey]struct{}))
+			require.Nil(t, err)
+		}
+	}
+	for key, value := range valueMap {
+		tester.MustUpdate(key.String(), value)
+	}
+	require.Equal(t, model.StateStopped, state.Info.State)
+	expectNoEvent()
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	select {
+	case event := <-eventCh:
+		require.Equal(t, model.StateNormal, event.OldState)
+		require.Equal(t, model.StateStopped, event.NewState)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "event is not received")
+	}
+	expectNoEvent()
+
+	// No event is sent if the patches are not committed.
+	manager.PushAdminJob(&model.AdminJob{CfID: ctx.ChangefeedVars().ID, Type: model.AdminResume})
+	manager.Tick(state)
+	_ = state.GetPatches()
+	require.Equal(t, model.StateStopped, state.Info.State)
+	manager.Tick(state)
+	tester.MustApplyPatches()
+	expectNoEvent()
+}
@@ -99,6 +99,18 @@ func (mr *MockOwnerMockRecorder) RebalanceTables(cfID, done interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebalanceTables", reflect.TypeOf((*MockOwner)(nil).RebalanceTables), cfID, done)
 }
 
+// ResyncTable mocks base method.
+func (m *MockOwner) ResyncTable(cfID model.ChangeFeedID, tableIDs []model.TableID, tableName model.TableName, policy model.ResyncPolicy, done chan<- error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ResyncTable", cfID, tableIDs, tableName, policy, done)
+}
+
+// ResyncTable indicates an expected call of ResyncTable.
+func (mr *MockOwnerMockRecorder) ResyncTable(cfID, tableIDs, tableName, policy, done interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResyncTable", reflect.TypeOf((*MockOwner)(nil).ResyncTable), cfID, tableIDs, tableName, policy, done)
+}
+
 // ScheduleTable mocks base method.
 func (m *MockOwner) ScheduleTable(cfID model.ChangeFeedID, toCapture model.CaptureID, tableID model.TableID, done chan<- error) {
 	m.ctrl.T.Helper()
@@ -16,6 +16,7 @@ package owner
 import (
 	"context"
 	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,7 +24,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -47,8 +50,14 @@ const (
 	ownerJobTypeAdminJob
 	ownerJobTypeDebugInfo
 	ownerJobTypeQuery
+	ownerJobTypeResyncTable
 )
 
+// maxResyncTableCount is the max number of physical tables that can be
+// re-synced in a request. The resync markers of the tables are written in
+// one etcd txn, which is limited to 128 operations.
+const maxResyncTableCount = 64
+
 // versionInconsistentLogRate represents the rate of log output when there are
 // captures with versions different from that of the owner
 const versionInconsistentLogRate = 1
@@ -63,6 +72,11 @@ type ownerJob struct {
 	// for ScheduleTable only
 	TableID model.TableID
 
+	// for ResyncTable only
+	ResyncTableIDs  []model.TableID
+	ResyncTableName model.TableName
+	ResyncPolicy    model.ResyncPolicy
+
 	// for Admin Job only
 	AdminJob *model.AdminJob
 
@@ -89,6 +103,10 @@ type Owner interface {
 		cfID model.ChangeFeedID, toCapture model.CaptureID,
 		tableID model.TableID, done chan<- error,
 	)
+	ResyncTable(
+		cfID model.ChangeFeedID, tableIDs []model.TableID,
+		tableName model.TableName, policy model.ResyncPolicy, done chan<- error,
+	)
 	DrainCapture(query *scheduler.Query, done chan<- error)
 	WriteDebugInfo(w io.Writer, done chan<- error)
 	Query(query *Query, done chan<- error)
@@ -96,10 +114,17 @@ type Owner interface {
 }
 
 type ownerImpl struct {
-	changefeeds     map[model.ChangeFeedID]*changefeed
-	captures        map[model.CaptureID]*model.CaptureInfo
-	upstreamManager *upstream.Manager
-	ownerJobQueue   struct {
+	changefeeds map[model.ChangeFeedID]*changefeed
+	captures    map[model.CaptureID]*model.CaptureInfo
+	// knownCaptures is a copy of the captures seen in the last tick, it is
+	// used to find out the captures joined or left the cluster.
+	knownCaptures map[model.CaptureID]*model.CaptureInfo
+	// overQuotaStopped are the changefeeds which exceed the quota of their
+	// namespaces and have been asked to stop, so the stop job is pushed
+	// only once while the changefeed is still running.
+	overQuotaStopped map[model.ChangeFeedID]struct{}
+	upstreamManager  *upstream.Manager
+	ownerJobQueue    struct {
 		sync.Mutex
 		queue []*ownerJob
 	}
@@ -119,11 +144,12 @@ type ownerImpl struct {
 // NewOwner creates a new Owner
 func NewOwner(upstreamManager *upstream.Manager) Owner {
 	return &ownerImpl{
-		upstreamManager: upstreamManager,
-		changefeeds:     make(map[model.ChangeFeedID]*changefeed),
-		lastTickTime:    time.Now(),
-		newChangefeed:   newChangefeed,
-		logLimiter:      rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
+		upstreamManager:  upstreamManager,
+		changefeeds:      make(map[model.ChangeFeedID]*changefeed),
+		overQuotaStopped: make(map[model.ChangeFeedID]struct{}),
+		lastTickTime:     time.Now(),
+		newChangefeed:    newChangefeed,
+		logLimiter:       rate.NewLimiter(versionInconsistentLogRate, versionInconsistentLogRate),
 	}
 }
 
@@ -163,6 +189,8 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		return state, nil
 	}
 
+	ctx := stdCtx.(cdcContext.Context)
+	o.notifyCaptureChanges(ctx, state.Captures)
 	o.captures = state.Captures
 	o.updateMetrics(state)
 
@@ -184,8 +212,10 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 		return nil, errors.Trace(err)
 	}
 
+	overQuota := o.changefeedsOverNamespaceQuota(state)
+	o.forgetOverQuotaStopped(overQuota)
+
 	// Tick all changefeeds.
-	ctx := stdCtx.(cdcContext.Context)
 	for changefeedID, changefeedState := range state.Changefeeds {
 		if changefeedState.Info == nil {
 			o.cleanUpChangefeed(changefeedState)
@@ -206,8 +236,12 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 				up = o.upstreamManager.AddUpstream(upstreamInfo.ID, upstreamInfo)
 			}
 			cfReactor = o.newChangefeed(changefeedID, up)
+			cfReactor.feedStateManager.notifier = ctx.GlobalVars().Notifier
 			o.changefeeds[changefeedID] = cfReactor
 		}
+		if err, ok := overQuota[changefeedID]; ok {
+			o.stopOverQuotaChangefeed(ctx, cfReactor, err)
+		}
 		cfReactor.Tick(ctx, changefeedState, state.Captures)
 	}
 
@@ -242,6 +276,97 @@ func (o *ownerImpl) Tick(stdCtx context.Context, rawState orchestrator.ReactorSt
 	return state, nil
 }
 
+// changefeedsOverNamespaceQuota returns the running changefeeds that exceed the
+// quota of their namespaces, which may happen if the quota is lowered after the
+// changefeeds are created. Earlier created changefeeds are kept running.
+func (o *ownerImpl) changefeedsOverNamespaceQuota(
+	state *orchestrator.GlobalReactorState,
+) map[model.ChangeFeedID]error {
+	if len(state.Namespaces) == 0 {
+		return nil
+	}
+	running := make(map[string][]*orchestrator.ChangefeedReactorState)
+	for _, changefeedState := range state.Changefeeds {
+		info := changefeedState.Info
+		if info == nil {
+			continue
+		}
+		namespace := changefeedState.ID.Namespace
+		if _, ok := state.Namespaces[namespace]; !ok {
+			continue
+		}
+		if info.State != model.StateNormal && info.State != model.StateError {
+			continue
+		}
+		running[namespace] = append(running[namespace], changefeedState)
+	}
+
+	var result map[model.ChangeFeedID]error
+	for namespace, changefeeds := range running {
+		sort.Slice(changefeeds, func(i, j int) bool {
+			ti, tj := changefeeds[i].Info.CreateTime, changefeeds[j].Info.CreateTime
+			if !ti.Equal(tj) {
+				return ti.Before(tj)
+			}
+			return changefeeds[i].ID.ID < changefeeds[j].ID.ID
+		})
+		nsInfo := state.Namespaces[namespace]
+		count, memoryQuota := 0, uint64(0)
+		for _, changefeedState := range changefeeds {
+			var cfMemoryQuota uint64
+			if changefeedState.Info.Config != nil {
+				cfMemoryQuota = changefeedState.Info.Config.MemoryQuota
+			}
+			if err := nsInfo.CheckQuota(count, memoryQuota, cfMemoryQuota); err != nil {
+				log.Warn("changefeed exceeds the quota of its namespace, stop it",
+					zap.String("namespace", namespace),
+					zap.String("changefeed", changefeedState.ID.ID),
+					zap.Error(err))
+				if result == nil {
+					result = make(map[model.ChangeFeedID]error)
+				}
+				result[changefeedState.ID] = err
+				continue
+			}
+			count++
+			memoryQuota += cfMemoryQuota
+		}
+	}
+	return result
+}
+
+// forgetOverQuotaStopped forgets the changefeeds which are stopped or no longer
+// exceed the quota, they are asked to stop again if they are resumed over
+// the quota.
+func (o *ownerImpl) forgetOverQuotaStopped(overQuota map[model.ChangeFeedID]error) {
+	for changefeedID := range o.overQuotaStopped {
+		if _, ok := overQuota[changefeedID]; !ok {
+			delete(o.overQuotaStopped, changefeedID)
+		}
+	}
+}
+
+// stopOverQuotaChangefeed asks the changefeed which exceeds the quota of its
+// namespace to stop, the stop job is pushed once until the changefeed is
+// stopped or no longer exceeds the quota.
+func (o *ownerImpl) stopOverQuotaChangefeed(
+	ctx cdcContext.Context, cfReactor *changefeed, err error,
+) {
+	if _, ok := o.overQuotaStopped[cfReactor.id]; ok {
+		return
+	}
+	o.overQuotaStopped[cfReactor.id] = struct{}{}
+	cfReactor.feedStateManager.PushAdminJob(&model.AdminJob{
+		CfID: cfReactor.id,
+		Type: model.AdminStop,
+		Error: &model.RunningError{
+			Addr:    contextutil.CaptureAddrFromCtx(ctx),
+			Code:    string(cerror.ErrNamespaceQuotaExceeded.RFCCode()),
+			Message: err.Error(),
+		},
+	})
+}
+
 // EnqueueJob enqueues an admin job into an internal queue,
 // and the Owner will handle the job in the next tick
 // `done` must be buffered to prevent blocking owner.
@@ -279,6 +404,23 @@ func (o *ownerImpl) ScheduleTable(
 	})
 }
 
+// ResyncTable re-syncs a table, all the physical tables (partitions) of the
+// table are removed and added back with a new snapshot.
+// `done` must be buffered to prevent blocking owner.
+func (o *ownerImpl) ResyncTable(
+	cfID model.ChangeFeedID, tableIDs []model.TableID,
+	tableName model.TableName, policy model.ResyncPolicy, done chan<- error,
+) {
+	o.pushOwnerJob(&ownerJob{
+		Tp:              ownerJobTypeResyncTable,
+		ChangefeedID:    cfID,
+		ResyncTableIDs:  tableIDs,
+		ResyncTableName: tableName,
+		ResyncPolicy:    policy,
+		done:            done,
+	})
+}
+
 // DrainCapture removes all tables at the target capture
 // `done` must be buffered to prevent blocking owner.
 func (o *ownerImpl) DrainCapture(query *scheduler.Query, done chan<- error) {
@@ -327,6 +469,8 @@ func (o *ownerImpl) cleanUpChangefeed(state *orchestrator.ChangefeedReactorState
 			return nil, position != nil, nil
 		})
 	}
+	state.RemoveHistory()
+	state.RemoveTableSnapshots()
 }
 
 // Bootstrap checks if the state contains incompatible or incorrect information and tries to fix it.
@@ -363,6 +507,32 @@ func (o *ownerImpl) cleanStaleMetrics() {
 	changefeedStatusGauge.Reset()
 }
 
+// notifyCaptureChanges sends events for the captures joined or left the
+// cluster since the last tick. The captures seen in the first tick are not
+// reported, because they joined before the owner was elected.
+func (o *ownerImpl) notifyCaptureChanges(
+	ctx cdcContext.Context, captures map[model.CaptureID]*model.CaptureInfo,
+) {
+	notifier := ctx.GlobalVars().Notifier
+	if o.knownCaptures != nil {
+		for id, info := range captures {
+			if _, ok := o.knownCaptures[id]; !ok {
+				notifier.Notify(notification.NewCaptureJoinedEvent(info))
+			}
+		}
+		for id, info := range o.knownCaptures {
+			if _, ok := captures[id]; !ok {
+				notifier.Notify(notification.NewCaptureLeftEvent(info))
+			}
+		}
+	}
+	// state.Captures is updated in place, so we have to copy it.
+	o.knownCaptures = make(map[model.CaptureID]*model.CaptureInfo, len(captures))
+	for id, info := range captures {
+		o.knownCaptures[id] = info
+	}
+}
+
 func (o *ownerImpl) updateMetrics(state *orchestrator.GlobalReactorState) {
 	// Keep the value of prometheus expression `rate(counter)` = 1
 	// Please also change alert rule in ticdc.rules.yml when change the expression value.
@@ -501,6 +671,8 @@ func (o *ownerImpl) handleJobs() {
 			if cfReactor.scheduler != nil {
 				cfReactor.scheduler.Rebalance()
 			}
+		case ownerJobTypeResyncTable:
+			job.done <- o.handleResyncTable(cfReactor, job)
 		case ownerJobTypeQuery:
 			job.done <- o.handleQueries(job.query)
 		case ownerJobTypeDebugInfo:
@@ -510,6 +682,40 @@ func (o *ownerImpl) handleJobs() {
 	}
 }
 
+func (o *ownerImpl) handleResyncTable(cfReactor *changefeed, job *ownerJob) error {
+	// Scheduler is created lazily, it is nil before initialization.
+	if cfReactor.scheduler == nil {
+		return cerror.ErrSchedulerRequestFailed.
+			GenWithStack("changefeed %s is not initialized", job.ChangefeedID.ID)
+	}
+	if len(job.ResyncTableIDs) > maxResyncTableCount {
+		return cerror.ErrSchedulerRequestFailed.GenWithStack(
+			"table %s has %d partitions, at most %d partitions can be re-synced",
+			job.ResyncTableName.QuoteString(), len(job.ResyncTableIDs), maxResyncTableCount)
+	}
+	// All the physical tables are accepted by the scheduler or none of them.
+	if err := cfReactor.scheduler.ResyncTables(job.ResyncTableIDs); err != nil {
+		return errors.Trace(err)
+	}
+	// The markers are written before the tables are added back, the new table
+	// pipelines find them and start the snapshot scan. The patches of a
+	// changefeed in a tick are committed in one etcd txn, so the markers of
+	// all the partitions are written together.
+	for _, tableID := range job.ResyncTableIDs {
+		cfReactor.state.PatchTableSnapshot(tableID,
+			func(_ *model.TableSnapshotProgress) (*model.TableSnapshotProgress, bool, error) {
+				return model.NewTableResyncRequest(job.ResyncTableName, job.ResyncPolicy), true, nil
+			})
+	}
+	log.Info("table resync triggered",
+		zap.String("namespace", job.ChangefeedID.Namespace),
+		zap.String("changefeed", job.ChangefeedID.ID),
+		zap.Int64s("tableIDs", job.ResyncTableIDs),
+		zap.String("tableName", job.ResyncTableName.QuoteString()),
+		zap.String("policy", string(job.ResyncPolicy)))
+	return nil
+}
+
 func (o *ownerImpl) handleQueries(query *Query) error {
 	switch query.Tp {
 	case QueryAllChangeFeedStatuses:
@@ -525,6 +731,8 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			ret[cfID].ResolvedTs = cfReactor.state.Status.ResolvedTs
 			ret[cfID].CheckpointTs = cfReactor.state.Status.CheckpointTs
 			ret[cfID].AdminJobType = cfReactor.state.Status.AdminJobType
+			ret[cfID].LastRewrittenDDL = cfReactor.state.Status.LastRewrittenDDL
+			ret[cfID].LastErrors = cfReactor.state.Status.LastErrors
 		}
 		query.Data = ret
 	case QueryAllChangeFeedInfo:
@@ -583,6 +791,40 @@ func (o *ownerImpl) handleQueries(query *Query) error {
 			return errors.Trace(err)
 		}
 		query.Data = ret
+	case QueryTableReplicationStates:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+
+		provider := cfReactor.GetInfoProvider()
+		if provider == nil {
+			// The scheduler has not been initialized yet.
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		query.Data = provider.GetTableReplicationStates()
+	case QueryTableInfo, QueryTableDDLJobs:
+		cfReactor, ok := o.changefeeds[query.ChangeFeedID]
+		if !ok {
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		if cfReactor.schema == nil {
+			// The changefeed has not been initialized yet.
+			return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(query.ChangeFeedID)
+		}
+		args := query.Data.(*tableSchemaQuery)
+		resolvedTs := cfReactor.schemaResolvedTs()
+		var err error
+		if query.Tp == QueryTableInfo {
+			query.Data, err = cfReactor.schema.TableInfoAt(
+				args.schemaName, args.tableName, args.endTs, resolvedTs)
+		} else {
+			query.Data, err = cfReactor.schema.TableDDLJobs(
+				args.schemaName, args.tableName, args.startTs, args.endTs, resolvedTs)
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
 	case QueryProcessors:
 		var ret []*model.ProcInfoSnap
 		for cfID, cfReactor := range o.changefeeds {
@@ -663,7 +905,12 @@ func (o *ownerImpl) updateGCSafepoint(
 	for upstreamID, minCheckpointTs := range minChekpoinTsMap {
 		up, ok := o.upstreamManager.Get(upstreamID)
 		if !ok {
-			upstreamInfo := state.Upstreams[upstreamID]
+			upstreamInfo, ok := state.Upstreams[upstreamID]
+			if !ok {
+				log.Warn("upstream info not found, skip updating gc safepoint",
+					zap.Uint64("id", upstreamID))
+				continue
+			}
 			up = o.upstreamManager.AddUpstream(upstreamInfo.ID, upstreamInfo)
 		}
 		if !up.IsNormal() {
@@ -685,6 +932,15 @@ func (o *ownerImpl) updateGCSafepoint(
 
 		err := up.GCManager.TryUpdateGCSafePoint(ctx, gcSafepointUpperBound, forceUpdate)
 		if err != nil {
+			// An unavailable upstream must not block the gc safepoint
+			// management of the other upstreams.
+			if !up.IsDefault() {
+				log.Warn("update gc safepoint failed, skip",
+					zap.Uint64("id", up.ID),
+					zap.Strings("pd", up.PdEndpoints),
+					zap.Error(err))
+				continue
+			}
 			return errors.Trace(err)
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -200,6 +201,82 @@ func TestStopChangefeed(t *testing.T) {
 	require.NotContains(t, state.Changefeeds, changefeedID)
 }
 
+func TestNamespaceQuota(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+	owner, state, tester := createOwner4Test(ctx, t)
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	defer cancel()
+
+	nsInfo := &model.NamespaceInfo{Name: "tenant", ChangefeedQuota: 1}
+	nsStr, err := nsInfo.Marshal()
+	require.Nil(t, err)
+	nsKey := etcd.CDCKey{
+		ClusterID: state.ClusterID,
+		Tp:        etcd.CDCKeyTypeNamespace,
+		Namespace: nsInfo.Name,
+	}
+	tester.MustUpdate(nsKey.String(), nsStr)
+
+	now := time.Now()
+	ids := []model.ChangeFeedID{
+		{Namespace: "tenant", ID: "test-changefeed-1"},
+		{Namespace: "tenant", ID: "test-changefeed-2"},
+	}
+	for i, id := range ids {
+		changefeedInfo := &model.ChangeFeedInfo{
+			StartTs:    oracle.GoTimeToTS(now),
+			CreateTime: now.Add(time.Duration(i) * time.Second),
+			State:      model.StateNormal,
+			Config:     config.GetDefaultReplicaConfig(),
+		}
+		changefeedStr, err := changefeedInfo.Marshal()
+		require.Nil(t, err)
+		cdcKey := etcd.CDCKey{
+			ClusterID:    state.ClusterID,
+			Tp:           etcd.CDCKeyTypeChangefeedInfo,
+			ChangefeedID: id,
+		}
+		tester.MustUpdate(cdcKey.String(), []byte(changefeedStr))
+	}
+
+	_, err = owner.Tick(ctx, state)
+	require.Nil(t, err)
+	require.Contains(t, owner.overQuotaStopped, ids[1])
+	tester.MustApplyPatches()
+
+	// The later created changefeed is stopped.
+	require.Equal(t, model.StateNormal, state.Changefeeds[ids[0]].Info.State)
+	require.Nil(t, state.Changefeeds[ids[0]].Info.Error)
+	require.Equal(t, model.StateStopped, state.Changefeeds[ids[1]].Info.State)
+	require.Equal(t, string(cerror.ErrNamespaceQuotaExceeded.RFCCode()),
+		state.Changefeeds[ids[1]].Info.Error.Code)
+
+	// A stopped changefeed does not count in the quota.
+	require.Empty(t, owner.changefeedsOverNamespaceQuota(state))
+}
+
+func TestStopOverQuotaChangefeedOnce(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+	owner, state, _ := createOwner4Test(ctx, t)
+	id := model.ChangeFeedID{Namespace: "tenant", ID: "test-changefeed"}
+	cfReactor := owner.newChangefeed(id, nil)
+	cfReactor.feedStateManager.state = orchestrator.NewChangefeedReactorState(state.ClusterID, id)
+
+	err := cerror.ErrNamespaceQuotaExceeded.GenWithStackByArgs("tenant", "changefeed", 2, 1)
+	owner.stopOverQuotaChangefeed(ctx, cfReactor, err)
+	owner.stopOverQuotaChangefeed(ctx, cfReactor, err)
+	require.Len(t, cfReactor.feedStateManager.adminJobQueue, 1)
+	require.Equal(t, model.AdminStop, cfReactor.feedStateManager.adminJobQueue[0].Type)
+
+	// The changefeed is asked to stop again once it is resumed over the quota.
+	owner.forgetOverQuotaStopped(map[model.ChangeFeedID]error{id: err})
+	require.Contains(t, owner.overQuotaStopped, id)
+	owner.forgetOverQuotaStopped(nil)
+	require.Empty(t, owner.overQuotaStopped)
+	owner.stopOverQuotaChangefeed(ctx, cfReactor, err)
+	require.Len(t, cfReactor.feedStateManager.adminJobQueue, 2)
+}
+
 func TestFixChangefeedState(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(false)
 	owner, state, tester := createOwner4Test(ctx, t)
@@ -481,6 +558,50 @@ func TestUpdateGCSafePoint(t *testing.T) {
 
 // make sure handleJobs works well even if there is two different
 // version of captures in the cluster
+func TestUpdateGCSafePointUnknownUpstream(t *testing.T) {
+	mockPDClient := &gc.MockPDClient{}
+	m := upstream.NewManager4Test(mockPDClient)
+	o := NewOwner(m).(*ownerImpl)
+	ctx := cdcContext.NewBackendContext4Test(true)
+	ctx, cancel := cdcContext.WithCancel(ctx)
+	defer cancel()
+	state := orchestrator.NewGlobalState(etcd.DefaultCDCClusterID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+
+	// The info of upstream 2 is not loaded, only the gc safepoint of the
+	// default upstream is updated.
+	ch := make(chan uint64, 1)
+	mockPDClient.UpdateServiceGCSafePointFunc = func(
+		ctx context.Context, serviceID string, ttl int64, safePoint uint64,
+	) (uint64, error) {
+		ch <- safePoint
+		return 0, nil
+	}
+	for id, upstreamID := range map[string]uint64{"cf-default": 0, "cf-other": 2} {
+		tester.MustUpdate(
+			fmt.Sprintf("%s/changefeed/info/%s",
+				etcd.DefaultClusterAndNamespacePrefix, id),
+			[]byte(fmt.Sprintf(`{"config":{},"state":"normal","upstream-id":%d}`,
+				upstreamID)))
+		tester.MustApplyPatches()
+		checkpointTs := 10 + upstreamID
+		state.Changefeeds[model.DefaultChangeFeedID(id)].PatchStatus(
+			func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+				return &model.ChangeFeedStatus{CheckpointTs: checkpointTs}, true, nil
+			})
+		tester.MustApplyPatches()
+	}
+	require.Nil(t, o.updateGCSafepoint(ctx, state))
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	case safePoint := <-ch:
+		require.Equal(t, uint64(9), safePoint)
+	}
+	_, ok := m.Get(2)
+	require.False(t, ok)
+}
+
 func TestHandleJobsDontBlock(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(false)
 	ctx, cancel := cdcContext.WithCancel(ctx)
@@ -672,3 +793,108 @@ func TestHandleDrainCapturesSchedulerNotReady(t *testing.T) {
 	require.EqualValues(t, 0, query.Resp.(*model.DrainCaptureResp).CurrentTableCount)
 	require.Nil(t, <-done)
 }
+
+func TestHandleResyncTable(t *testing.T) {
+	t.Parallel()
+
+	cfID := model.DefaultChangeFeedID("test-resync")
+	state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID, cfID)
+	tester := orchestrator.NewReactorStateTester(t, state, nil)
+	cf := &changefeed{
+		scheduler: nil, // scheduler is not set.
+		state:     state,
+	}
+	o := &ownerImpl{changefeeds: make(map[model.ChangeFeedID]*changefeed)}
+	o.changefeeds[cfID] = cf
+
+	done := make(chan error, 1)
+	o.ResyncTable(cfID, []model.TableID{1, 2},
+		model.TableName{Schema: "test", Table: "t", TableID: 1},
+		model.ResyncPolicyOverwrite, done)
+	o.handleJobs()
+	require.Regexp(t, ".*is not initialized.*", <-done)
+
+	mockScheduler := &mockScheduler{}
+	cf.scheduler = mockScheduler
+	done = make(chan error, 1)
+	o.ResyncTable(cfID, []model.TableID{1, 2},
+		model.TableName{Schema: "test", Table: "t", TableID: 1},
+		model.ResyncPolicyOverwrite, done)
+	o.handleJobs()
+	require.Nil(t, <-done)
+	require.Equal(t, []model.TableID{1, 2}, mockScheduler.resyncTables)
+
+	tester.MustApplyPatches()
+	for _, tableID := range []model.TableID{1, 2} {
+		key := etcd.GetEtcdKeyTableSnapshot(etcd.DefaultCDCClusterID, cfID, tableID)
+		progress := &model.TableSnapshotProgress{}
+		require.Nil(t, progress.Unmarshal([]byte(tester.KVEntries()[key])))
+		require.True(t, progress.ResyncPending())
+		require.Equal(t, "`test`.`t`", progress.TableName)
+		require.Equal(t, "t", progress.Table.Table)
+		require.Equal(t, model.ResyncPolicyOverwrite, progress.Policy)
+	}
+
+	// a table with too many partitions is rejected as a whole.
+	tableIDs := make([]model.TableID, maxResyncTableCount+1)
+	for i := range tableIDs {
+		tableIDs[i] = model.TableID(100 + i)
+	}
+	mockScheduler.resyncTables = nil
+	done = make(chan error, 1)
+	o.ResyncTable(cfID, tableIDs,
+		model.TableName{Schema: "test", Table: "p", TableID: 99},
+		model.ResyncPolicyOverwrite, done)
+	o.handleJobs()
+	require.Regexp(t, ".*at most 64 partitions.*", <-done)
+	require.Len(t, mockScheduler.resyncTables, 0)
+	require.Len(t, state.GetPatches()[0], 0)
+}
+
+func TestNotifyCaptureChanges(t *testing.T) {
+	notifier, eventCh := newNotifier4Test(t)
+	ctx := cdcContext.NewContext(context.Background(), &cdcContext.GlobalVars{
+		Notifier: notifier,
+	})
+	o := &ownerImpl{}
+
+	capture1 := &model.CaptureInfo{ID: "capture-1", AdvertiseAddr: "127.0.0.1:8300"}
+	capture2 := &model.CaptureInfo{ID: "capture-2", AdvertiseAddr: "127.0.0.1:8301"}
+	captures := map[model.CaptureID]*model.CaptureInfo{capture1.ID: capture1}
+	// The captures seen in the first tick are not reported.
+	o.notifyCaptureChanges(ctx, captures)
+
+	// The map is updated in place like GlobalReactorState does.
+	captures[capture2.ID] = capture2
+	o.notifyCaptureChanges(ctx, captures)
+	delete(captures, capture1.ID)
+	o.notifyCaptureChanges(ctx, captures)
+	o.notifyCaptureChanges(ctx, captures)
+
+	for _, expected := range []struct {
+		tp      notification.EventType
+		capture *model.CaptureInfo
+	}{
+		{notification.EventCaptureJoined, capture2},
+		{notification.EventCaptureLeft, capture1},
+	} {
+		select {
+		case event := <-eventCh:
+			require.Equal(t, expected.tp, event.Type)
+			require.Equal(t, expected.capture.ID, event.CaptureID)
+			require.Equal(t, expected.capture.AdvertiseAddr, event.CaptureAddr)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "event is not received")
+		}
+	}
+	select {
+	case event := <-eventCh:
+		require.FailNow(t, "unexpected event", "%v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// It is safe without a notifier.
+	ctx = cdcContext.NewContext(context.Background(), &cdcContext.GlobalVars{})
+	delete(captures, capture2.ID)
+	o.notifyCaptureChanges(ctx, captures)
+}
@@ -14,6 +14,9 @@
 package owner
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
@@ -39,6 +42,18 @@ type schemaWrap4Owner struct {
 	schemaVersion               int64
 	id                          model.ChangeFeedID
 	metricIgnoreDDLEventCounter prometheus.Counter
+
+	// startTs is the ts of the schema snapshot that the owner starts with,
+	// or the checkpoint ts the history is trimmed to by DoGC. The schema
+	// history before it is not available in the owner.
+	startTs model.Ts
+	// historySnapshots are the schema snapshots before each DDL job handled
+	// by the owner, in ascending order of their ts. A copied snapshot
+	// shares the versioned data with the latest one, so they are cheap.
+	historySnapshots []*schema.Snapshot
+	// handledDDLJobs are the DDL jobs handled by the owner, in ascending
+	// order of their finished ts.
+	handledDDLJobs []*timodel.Job
 }
 
 func newSchemaWrap4Owner(
@@ -77,6 +92,7 @@ func newSchemaWrap4Owner(
 		ddlHandledTs:   startTs,
 		schemaVersion:  version,
 		id:             id,
+		startTs:        startTs,
 		metricIgnoreDDLEventCounter: changefeedIgnoredDDLEventCounter.
 			WithLabelValues(id.Namespace, id.ID),
 	}, nil
@@ -131,6 +147,7 @@ func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 		return nil
 	}
 	s.allPhysicalTablesCache = nil
+	snap := s.schemaSnapshot.Copy()
 	err := s.schemaSnapshot.HandleDDL(job)
 	if err != nil {
 		log.Error("handle DDL failed",
@@ -147,11 +164,107 @@ func (s *schemaWrap4Owner) HandleDDL(job *timodel.Job) error {
 		zap.String("DDL", job.Query), zap.Stringer("job", job),
 		zap.Any("role", util.RoleOwner))
 
+	s.historySnapshots = append(s.historySnapshots, snap)
+	s.handledDDLJobs = append(s.handledDDLJobs, job)
 	s.ddlHandledTs = job.BinlogInfo.FinishedTS
 	s.schemaVersion = job.BinlogInfo.SchemaVersion
 	return nil
 }
 
+// TableInfoAt returns the info of the table in the schema snapshot at ts.
+// resolvedTs is the max ts at which the schema of the owner is complete.
+func (s *schemaWrap4Owner) TableInfoAt(
+	schemaName, tableName string, ts, resolvedTs model.Ts,
+) (*model.TableInfo, error) {
+	snap, err := s.snapshotAt(ts, resolvedTs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tableInfo, ok := snap.TableByName(schemaName, tableName)
+	if !ok {
+		return nil, cerror.ErrSnapshotTableNameNotFound.
+			GenWithStackByArgs(schemaName, tableName, ts)
+	}
+	return tableInfo, nil
+}
+
+// TableDDLJobs returns the DDL jobs of the table handled by the owner whose
+// finished ts are in (startTs, endTs], ordered by their finished ts. A job
+// belongs to the table if it has the same name, or it has the same table ID
+// as the table at startTs or at endTs, so that jobs like renaming or
+// truncating are included. A zero startTs means the ts of the snapshot that
+// the owner starts with.
+func (s *schemaWrap4Owner) TableDDLJobs(
+	schemaName, tableName string, startTs, endTs, resolvedTs model.Ts,
+) ([]*timodel.Job, error) {
+	if startTs == 0 {
+		startTs = s.startTs
+	}
+	tableIDs := make(map[int64]struct{})
+	for _, ts := range []model.Ts{startTs, endTs} {
+		snap, err := s.snapshotAt(ts, resolvedTs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if tableID, ok := snap.TableIDByName(schemaName, tableName); ok {
+			tableIDs[tableID] = struct{}{}
+		}
+	}
+	var result []*timodel.Job
+	for _, job := range s.handledDDLJobs {
+		finishedTs := job.BinlogInfo.FinishedTS
+		if finishedTs <= startTs || finishedTs > endTs {
+			continue
+		}
+		if _, ok := tableIDs[job.TableID]; ok ||
+			(strings.EqualFold(job.SchemaName, schemaName) &&
+				strings.EqualFold(job.TableName, tableName)) {
+			result = append(result, job)
+		}
+	}
+	return result, nil
+}
+
+// snapshotAt returns the schema snapshot at ts. It fails if ts is before
+// the snapshot the owner starts with, or after resolvedTs, because DDL jobs
+// after resolvedTs may not be handled by the owner yet.
+func (s *schemaWrap4Owner) snapshotAt(ts, resolvedTs model.Ts) (*schema.Snapshot, error) {
+	if ts < s.startTs {
+		return nil, cerror.ErrSchemaStorageGCed.GenWithStackByArgs(ts, s.startTs)
+	}
+	if ts > resolvedTs {
+		return nil, cerror.ErrSchemaStorageUnresolved.GenWithStackByArgs(ts, resolvedTs)
+	}
+	// The first snapshot whose next DDL job is finished after ts.
+	i := sort.Search(len(s.handledDDLJobs), func(i int) bool {
+		return s.handledDDLJobs[i].BinlogInfo.FinishedTS > ts
+	})
+	if i < len(s.historySnapshots) {
+		return s.historySnapshots[i], nil
+	}
+	return s.schemaSnapshot, nil
+}
+
+// DoGC drops the schema history before checkpointTs, which is not needed by
+// the changefeed any more, so that the memory of the owner does not grow with
+// the DDL jobs handled over the lifetime of the changefeed.
+func (s *schemaWrap4Owner) DoGC(checkpointTs model.Ts) {
+	if checkpointTs <= s.startTs {
+		return
+	}
+	// The jobs finished before or at checkpointTs, and the snapshots before
+	// them, are dropped. The snapshot at checkpointTs is the one before the
+	// first remaining job.
+	i := sort.Search(len(s.handledDDLJobs), func(i int) bool {
+		return s.handledDDLJobs[i].BinlogInfo.FinishedTS > checkpointTs
+	})
+	if i > 0 {
+		s.historySnapshots = append([]*schema.Snapshot(nil), s.historySnapshots[i:]...)
+		s.handledDDLJobs = append([]*timodel.Job(nil), s.handledDDLJobs[i:]...)
+	}
+	s.startTs = checkpointTs
+}
+
 func (s *schemaWrap4Owner) IsIneligibleTableID(tableID model.TableID) bool {
 	return s.schemaSnapshot.IsIneligibleTableID(tableID)
 }
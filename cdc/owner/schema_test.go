@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 )
@@ -94,6 +95,128 @@ func TestAllTableNames(t *testing.T) {
 	require.Equal(t, []model.TableName{{Schema: "test", Table: "t1"}}, schema.AllTableNames())
 }
 
+func TestSchemaHistory(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	startTs := ver.Ver
+	schema, err := newSchemaWrap4Owner(helper.Storage(), startTs,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+
+	createJob := helper.DDL2Job("create table test.t1(id int primary key, a int)")
+	require.Nil(t, schema.HandleDDL(createJob))
+	require.Nil(t, schema.HandleDDL(helper.DDL2Job("create table test.t2(id int primary key)")))
+	addColumnJob := helper.DDL2Job("alter table test.t1 add column b varchar(10)")
+	require.Nil(t, schema.HandleDDL(addColumnJob))
+	addIndexJob := helper.DDL2Job("alter table test.t1 add index idx_a(a)")
+	require.Nil(t, schema.HandleDDL(addIndexJob))
+	createdTs := createJob.BinlogInfo.FinishedTS
+	endTs := addIndexJob.BinlogInfo.FinishedTS
+
+	_, err = schema.TableInfoAt("test", "t1", startTs, endTs)
+	require.True(t, cerror.ErrSnapshotTableNameNotFound.Equal(err), err)
+
+	tableInfo, err := schema.TableInfoAt("test", "t1", createdTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Columns, 2)
+	require.Len(t, tableInfo.Indices, 1)
+
+	tableInfo, err = schema.TableInfoAt("test", "t1", addColumnJob.BinlogInfo.FinishedTS, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Columns, 3)
+	require.Len(t, tableInfo.Indices, 1)
+
+	tableInfo, err = schema.TableInfoAt("test", "t1", endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Columns, 3)
+	require.Equal(t, "b", tableInfo.Columns[2].Name.O)
+	require.Len(t, tableInfo.Indices, 2)
+	require.Equal(t, "idx_a", tableInfo.Indices[1].Name.O)
+
+	// the schema before the owner starts or after it is resolved is unknown
+	_, err = schema.TableInfoAt("test", "t1", startTs-1, endTs)
+	require.True(t, cerror.ErrSchemaStorageGCed.Equal(err), err)
+	_, err = schema.TableInfoAt("test", "t1", endTs+1, endTs)
+	require.True(t, cerror.ErrSchemaStorageUnresolved.Equal(err), err)
+
+	jobs, err := schema.TableDDLJobs("test", "t1", 0, endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, jobs, 3)
+	require.Equal(t, timodel.ActionCreateTable, jobs[0].Type)
+	require.Equal(t, timodel.ActionAddColumn, jobs[1].Type)
+	require.Equal(t, timodel.ActionAddIndex, jobs[2].Type)
+
+	jobs, err = schema.TableDDLJobs("test", "t1", createdTs, endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, jobs, 2)
+
+	jobs, err = schema.TableDDLJobs("test", "t2", createdTs, endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, timodel.ActionCreateTable, jobs[0].Type)
+
+	_, err = schema.TableDDLJobs("test", "t1", startTs-1, endTs, endTs)
+	require.True(t, cerror.ErrSchemaStorageGCed.Equal(err), err)
+}
+
+func TestSchemaHistoryGC(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	startTs := ver.Ver
+	schema, err := newSchemaWrap4Owner(helper.Storage(), startTs,
+		config.GetDefaultReplicaConfig(), dummyChangeFeedID)
+	require.Nil(t, err)
+
+	createJob := helper.DDL2Job("create table test.t1(id int primary key, a int)")
+	require.Nil(t, schema.HandleDDL(createJob))
+	addColumnJob := helper.DDL2Job("alter table test.t1 add column b varchar(10)")
+	require.Nil(t, schema.HandleDDL(addColumnJob))
+	addIndexJob := helper.DDL2Job("alter table test.t1 add index idx_a(a)")
+	require.Nil(t, schema.HandleDDL(addIndexJob))
+	endTs := addIndexJob.BinlogInfo.FinishedTS
+	require.Len(t, schema.historySnapshots, 3)
+	require.Len(t, schema.handledDDLJobs, 3)
+
+	// The checkpoint does not move.
+	schema.DoGC(startTs)
+	require.Len(t, schema.historySnapshots, 3)
+	require.Len(t, schema.handledDDLJobs, 3)
+
+	// The history before the checkpoint is dropped.
+	checkpointTs := addColumnJob.BinlogInfo.FinishedTS
+	schema.DoGC(checkpointTs)
+	require.Len(t, schema.historySnapshots, 1)
+	require.Equal(t, []*timodel.Job{addIndexJob}, schema.handledDDLJobs)
+	_, err = schema.TableInfoAt("test", "t1", checkpointTs-1, endTs)
+	require.True(t, cerror.ErrSchemaStorageGCed.Equal(err), err)
+	tableInfo, err := schema.TableInfoAt("test", "t1", checkpointTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Columns, 3)
+	require.Len(t, tableInfo.Indices, 1)
+	tableInfo, err = schema.TableInfoAt("test", "t1", endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Indices, 2)
+	jobs, err := schema.TableDDLJobs("test", "t1", 0, endTs, endTs)
+	require.Nil(t, err)
+	require.Equal(t, []*timodel.Job{addIndexJob}, jobs)
+
+	// All the history is dropped once the checkpoint passes the last job.
+	schema.DoGC(endTs)
+	require.Empty(t, schema.historySnapshots)
+	require.Empty(t, schema.handledDDLJobs)
+	tableInfo, err = schema.TableInfoAt("test", "t1", endTs, endTs)
+	require.Nil(t, err)
+	require.Len(t, tableInfo.Indices, 2)
+	// A regressed checkpoint does not change anything.
+	schema.DoGC(checkpointTs)
+	_, err = schema.TableInfoAt("test", "t1", checkpointTs, endTs)
+	require.True(t, cerror.ErrSchemaStorageGCed.Equal(err), err)
+}
+
 func TestIsIneligibleTableID(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
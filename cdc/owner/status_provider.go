@@ -19,6 +19,7 @@ import (
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 
 	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tiflow/cdc/model"
 )
 
@@ -48,6 +49,22 @@ type StatusProvider interface {
 
 	// GetCaptures returns the information about all captures.
 	GetCaptures(ctx context.Context) ([]*model.CaptureInfo, error)
+
+	// GetTableReplicationStates returns the replication states of the tables
+	// maintained by the scheduler for the specified changefeed.
+	GetTableReplicationStates(ctx context.Context,
+		changefeedID model.ChangeFeedID) ([]*model.TableReplicationState, error)
+
+	// GetTableInfo returns the info of a table at ts in the schema storage
+	// of the specified changefeed.
+	GetTableInfo(ctx context.Context, changefeedID model.ChangeFeedID,
+		schemaName, tableName string, ts uint64) (*model.TableInfo, error)
+
+	// GetTableDDLJobs returns the DDL jobs of a table handled by the
+	// specified changefeed whose finished ts are in (startTs, endTs]. A zero
+	// startTs means the earliest ts of the schema history of the changefeed.
+	GetTableDDLJobs(ctx context.Context, changefeedID model.ChangeFeedID,
+		schemaName, tableName string, startTs, endTs uint64) ([]*timodel.Job, error)
 }
 
 // QueryType is the type of different queries.
@@ -66,8 +83,23 @@ const (
 	QueryProcessors
 	// QueryCaptures is the type of query captures info.
 	QueryCaptures
+	// QueryTableReplicationStates is the type of query table replication states.
+	QueryTableReplicationStates
+	// QueryTableInfo is the type of query the info of a table at a ts.
+	QueryTableInfo
+	// QueryTableDDLJobs is the type of query the DDL jobs of a table.
+	QueryTableDDLJobs
 )
 
+// tableSchemaQuery is the arguments of QueryTableInfo and QueryTableDDLJobs.
+// The ts of QueryTableInfo is passed by endTs.
+type tableSchemaQuery struct {
+	schemaName string
+	tableName  string
+	startTs    uint64
+	endTs      uint64
+}
+
 // Query wraps query command and return results.
 type Query struct {
 	Tp           QueryType
@@ -171,6 +203,57 @@ func (p *ownerStatusProvider) GetCaptures(ctx context.Context) ([]*model.Capture
 	return query.Data.([]*model.CaptureInfo), nil
 }
 
+func (p *ownerStatusProvider) GetTableReplicationStates(ctx context.Context,
+	changefeedID model.ChangeFeedID,
+) ([]*model.TableReplicationState, error) {
+	query := &Query{
+		Tp:           QueryTableReplicationStates,
+		ChangeFeedID: changefeedID,
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*model.TableReplicationState), nil
+}
+
+func (p *ownerStatusProvider) GetTableInfo(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string, ts uint64,
+) (*model.TableInfo, error) {
+	query := &Query{
+		Tp:           QueryTableInfo,
+		ChangeFeedID: changefeedID,
+		Data: &tableSchemaQuery{
+			schemaName: schemaName,
+			tableName:  tableName,
+			endTs:      ts,
+		},
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.(*model.TableInfo), nil
+}
+
+func (p *ownerStatusProvider) GetTableDDLJobs(ctx context.Context,
+	changefeedID model.ChangeFeedID, schemaName, tableName string,
+	startTs, endTs uint64,
+) ([]*timodel.Job, error) {
+	query := &Query{
+		Tp:           QueryTableDDLJobs,
+		ChangeFeedID: changefeedID,
+		Data: &tableSchemaQuery{
+			schemaName: schemaName,
+			tableName:  tableName,
+			startTs:    startTs,
+			endTs:      endTs,
+		},
+	}
+	if err := p.sendQueryToOwner(ctx, query); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return query.Data.([]*timodel.Job), nil
+}
+
 func (p *ownerStatusProvider) sendQueryToOwner(ctx context.Context, query *Query) error {
 	doneCh := make(chan error, 1)
 	p.owner.Query(query, doneCh)
@@ -17,12 +17,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
+	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/orchestrator"
@@ -93,27 +96,49 @@ func (m *managerImpl) Tick(stdCtx context.Context, state orchestrator.ReactorSta
 
 	captureID := ctx.GlobalVars().CaptureInfo.ID
 	var inactiveChangefeedCount int
+	activeChangefeeds := make([]*orchestrator.ChangefeedReactorState, 0, len(globalState.Changefeeds))
 	for changefeedID, changefeedState := range globalState.Changefeeds {
 		if !changefeedState.Active(captureID) {
 			inactiveChangefeedCount++
 			m.closeProcessor(changefeedID)
 			continue
 		}
-		ctx := cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
-			ID:   changefeedID,
-			Info: changefeedState.Info,
-		})
-		processor, exist := m.processors[changefeedID]
-		if !exist {
+		activeChangefeeds = append(activeChangefeeds, changefeedState)
+		if _, exist := m.processors[changefeedID]; !exist {
 			up, ok := m.upstreamManager.Get(changefeedState.Info.UpstreamID)
 			if !ok {
 				upstreamInfo := globalState.Upstreams[changefeedState.Info.UpstreamID]
 				up = m.upstreamManager.AddUpstream(upstreamInfo.ID, upstreamInfo)
 			}
 			failpoint.Inject("processorManagerHandleNewChangefeedDelay", nil)
-			processor = m.newProcessor(ctx, up, m.liveness)
-			m.processors[changefeedID] = processor
+			ctx := cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
+				ID:   changefeedID,
+				Info: changefeedState.Info,
+			})
+			m.processors[changefeedID] = m.newProcessor(ctx, up, m.liveness)
+		}
+	}
+	// The processors of the changefeeds with higher priorities are ticked
+	// first, so that they are served first when the capture is busy.
+	sort.Slice(activeChangefeeds, func(i, j int) bool {
+		wi, wj := priorityWeight(activeChangefeeds[i]), priorityWeight(activeChangefeeds[j])
+		if wi != wj {
+			return wi > wj
 		}
+		if activeChangefeeds[i].ID.Namespace != activeChangefeeds[j].ID.Namespace {
+			return activeChangefeeds[i].ID.Namespace < activeChangefeeds[j].ID.Namespace
+		}
+		return activeChangefeeds[i].ID.ID < activeChangefeeds[j].ID.ID
+	})
+	m.allocateResources(ctx, activeChangefeeds)
+
+	for _, changefeedState := range activeChangefeeds {
+		changefeedID := changefeedState.ID
+		ctx := cdcContext.WithChangefeedVars(ctx, &cdcContext.ChangefeedVars{
+			ID:   changefeedID,
+			Info: changefeedState.Info,
+		})
+		processor := m.processors[changefeedID]
 		if _, err := processor.Tick(ctx, changefeedState); err != nil {
 			m.closeProcessor(changefeedID)
 			if cerrors.ErrReactorFinished.Equal(errors.Cause(err)) {
@@ -138,6 +163,51 @@ func (m *managerImpl) Tick(stdCtx context.Context, state orchestrator.ReactorSta
 	return state, nil
 }
 
+// priorityWeight returns the weight of the priority of the changefeed.
+func priorityWeight(changefeed *orchestrator.ChangefeedReactorState) uint64 {
+	var priority string
+	if changefeed.Info.Config != nil {
+		priority = changefeed.Info.Config.Priority
+	}
+	return config.ChangefeedPriorityWeight(priority)
+}
+
+// allocateResources allocates the resources shared by the changefeeds on the
+// capture according to their priorities. The share of a changefeed is
+// proportional to its weight, that is
+//   - the memory quota of the sink of each table, the sum of the quotas
+//     of all the changefeeds is the same as the one without priorities,
+//   - the share of the sorter disk quota.
+//
+// The iterators of the db sorter and the workers of the table actors are
+// also shared by the weights, they are allocated when the tables are created.
+func (m *managerImpl) allocateResources(
+	ctx cdcContext.Context, changefeeds []*orchestrator.ChangefeedReactorState,
+) {
+	var totalWeight uint64
+	for _, changefeed := range changefeeds {
+		totalWeight += priorityWeight(changefeed)
+	}
+	perTableMemoryQuota := config.GetGlobalServerConfig().PerTableMemoryQuota
+	var diskQuota *leveldb.DiskQuota
+	if sorterSystem := ctx.GlobalVars().SorterSystem; sorterSystem != nil {
+		diskQuota = sorterSystem.DiskQuota()
+	}
+	for _, changefeed := range changefeeds {
+		weight := priorityWeight(changefeed)
+		quota := perTableMemoryQuota * weight * uint64(len(changefeeds)) / totalWeight
+		if processor, ok := m.processors[changefeed.ID]; ok && processor.tableMemoryQuota != quota {
+			log.Info("allocate the table memory quota of the processor",
+				zap.String("namespace", changefeed.ID.Namespace),
+				zap.String("changefeed", changefeed.ID.ID),
+				zap.Uint64("weight", weight),
+				zap.Uint64("tableMemoryQuota", quota))
+			processor.tableMemoryQuota = quota
+		}
+		diskQuota.SetWeight(changefeed.ID, weight)
+	}
+}
+
 func (m *managerImpl) closeProcessor(changefeedID model.ChangeFeedID) {
 	if processor, exist := m.processors[changefeedID]; exist {
 		startTime := time.Now()
@@ -308,3 +308,32 @@ func TestQueryTableCount(t *testing.T) {
 		require.FailNow(t, "done must be closed")
 	}
 }
+
+func TestAllocateResourcesByPriority(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(false)
+	var liveness model.Liveness
+	m := NewManager(upstream.NewManager4Test(nil), &liveness).(*managerImpl)
+
+	newChangefeed := func(id string, priority string) *orchestrator.ChangefeedReactorState {
+		changefeedID := model.DefaultChangeFeedID(id)
+		state := orchestrator.NewChangefeedReactorState(etcd.DefaultCDCClusterID, changefeedID)
+		state.Info = &model.ChangeFeedInfo{Config: config.GetDefaultReplicaConfig()}
+		state.Info.Config.Priority = priority
+		m.processors[changefeedID] = &processor{}
+		return state
+	}
+	high := newChangefeed("high", config.ChangefeedPriorityHigh)
+	normal := newChangefeed("normal", "")
+	low := newChangefeed("low", config.ChangefeedPriorityLow)
+	perTableMemoryQuota := config.GetGlobalServerConfig().PerTableMemoryQuota
+
+	// The quota is kept if all changefeeds have the same priority.
+	m.allocateResources(ctx, []*orchestrator.ChangefeedReactorState{normal})
+	require.Equal(t, perTableMemoryQuota, m.processors[normal.ID].tableMemoryQuota)
+
+	// The quota is allocated by the weights 4:2:1.
+	m.allocateResources(ctx, []*orchestrator.ChangefeedReactorState{high, normal, low})
+	require.Equal(t, perTableMemoryQuota*4*3/7, m.processors[high.ID].tableMemoryQuota)
+	require.Equal(t, perTableMemoryQuota*2*3/7, m.processors[normal.ID].tableMemoryQuota)
+	require.Equal(t, perTableMemoryQuota*1*3/7, m.processors[low.ID].tableMemoryQuota)
+}
@@ -26,6 +26,8 @@ type ActorNode struct {
 	messageStash     *pmessage.Message
 	parentNode       AsyncMessageHolder
 	messageProcessor AsyncMessageProcessor
+	// batchSize is the max number of messages handled in one run.
+	batchSize int
 }
 
 // NewActorNode create a new ActorNode
@@ -35,6 +37,7 @@ func NewActorNode(
 	return &ActorNode{
 		parentNode:       parentNode,
 		messageProcessor: messageProcessor,
+		batchSize:        defaultOutputChannelSize,
 	}
 }
 
@@ -66,7 +69,7 @@ func (n *ActorNode) TryRun(ctx context.Context) error {
 		processedCount++
 		// processed too many messages may consume more than 1 second,
 		// return here to allow actor system poll other tables, and avoid dead loop
-		if processedCount >= defaultOutputChannelSize {
+		if processedCount >= n.batchSize {
 			return nil
 		}
 	}
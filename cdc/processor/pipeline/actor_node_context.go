@@ -61,7 +61,7 @@ func newContext(stdCtx sdtContext.Context,
 	}
 	return &actorNodeContext{
 		Context:          stdCtx,
-		outputCh:         make(chan pmessage.Message, defaultOutputChannelSize),
+		outputCh:         make(chan pmessage.Message, actorNodeBatchSize(changefeedVars)),
 		tableActorRouter: tableActorRouter,
 		tableActorID:     tableActorID,
 		changefeedVars:   changefeedVars,
@@ -73,6 +73,23 @@ func newContext(stdCtx sdtContext.Context,
 	}
 }
 
+// actorNodeBatchSize returns the capacity of the output channel of a node and
+// the max number of messages handled by the next node in a poll of the table
+// actor. The workers of the table actor system are shared by all the tables
+// on the capture, the tables of a changefeed with a higher priority handle
+// more messages in a poll, so they get larger shares of the workers when the
+// capture is busy.
+func actorNodeBatchSize(changefeedVars *context.ChangefeedVars) int {
+	var priority string
+	if changefeedVars != nil && changefeedVars.Info != nil &&
+		changefeedVars.Info.Config != nil {
+		priority = changefeedVars.Info.Config.Priority
+	}
+	weight := config.ChangefeedPriorityWeight(priority)
+	normalWeight := config.ChangefeedPriorityWeight(config.ChangefeedPriorityNormal)
+	return defaultOutputChannelSize * int(weight) / int(normalWeight)
+}
+
 func (c *actorNodeContext) setEventBatchSize(eventBatchSize uint32) {
 	atomic.StoreUint32(&c.eventBatchSize, eventBatchSize)
 }
@@ -19,6 +19,8 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/stretchr/testify/require"
 )
@@ -97,3 +99,44 @@ func TestTryRunLimited(t *testing.T) {
 	require.Nil(t, n.TryRun(context.TODO()))
 	require.Equal(t, defaultOutputChannelSize, processedCount)
 }
+
+func TestTryRunByPriority(t *testing.T) {
+	t.Parallel()
+
+	newNode := func(priority string) (*ActorNode, *int) {
+		var pN asyncMessageHolderFunc = func() *pmessage.Message {
+			return &pmessage.Message{
+				Tp:        pmessage.MessageTypeBarrier,
+				BarrierTs: 1,
+			}
+		}
+		processedCount := 0
+		var dp asyncMessageProcessorFunc = func(
+			ctx context.Context, msg pmessage.Message,
+		) (bool, error) {
+			processedCount++
+			return true, nil
+		}
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Priority = priority
+		n := NewActorNode(pN, dp)
+		n.batchSize = actorNodeBatchSize(&cdcContext.ChangefeedVars{
+			Info: &model.ChangeFeedInfo{Config: replicaConfig},
+		})
+		return n, &processedCount
+	}
+	high, highCount := newNode(config.ChangefeedPriorityHigh)
+	normal, normalCount := newNode("")
+	low, lowCount := newNode(config.ChangefeedPriorityLow)
+
+	// The tables are polled by turns, all of them have pending messages.
+	for i := 0; i < 10; i++ {
+		require.Nil(t, high.TryRun(context.TODO()))
+		require.Nil(t, normal.TryRun(context.TODO()))
+		require.Nil(t, low.TryRun(context.TODO()))
+	}
+	require.Equal(t, 10*defaultOutputChannelSize, *normalCount)
+	require.Equal(t, 4*(*lowCount), *highCount)
+	require.Equal(t, 2*(*lowCount), *normalCount)
+	require.Equal(t, defaultOutputChannelSize, actorNodeBatchSize(nil))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	"github.com/pingcap/tiflow/pkg/pipeline"
@@ -35,6 +36,7 @@ type pullerNode struct {
 	tableID    model.TableID
 	startTs    model.Ts
 	changefeed model.ChangeFeedID
+	tracker    *tracing.TableTracker
 	cancel     context.CancelFunc
 	wg         *errgroup.Group
 }
@@ -98,6 +100,9 @@ func (n *pullerNode) start(ctx pipeline.NodeContext,
 				if rawKV == nil {
 					continue
 				}
+				if rawKV.OpType != model.OpTypeResolved {
+					n.tracker.Observe(tracing.StagePuller, rawKV.StartTs, rawKV.CRTs)
+				}
 				pEvent := model.NewPolymorphicEvent(rawKV)
 				sorter.handleRawEvent(ctx, pEvent)
 			}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"go.uber.org/zap"
@@ -48,6 +49,17 @@ type sinkNode struct {
 
 	enableOldValue bool
 	splitTxn       bool
+
+	// tracker records the latency of transactions flushed to the sink.
+	tracker *tracing.TableTracker
+	// pendingTxns are the emitted transactions that are not flushed yet,
+	// ordered by commit ts. It is only maintained if tracker is not nil.
+	pendingTxns []pendingTxn
+}
+
+type pendingTxn struct {
+	startTs  model.Ts
+	commitTs model.Ts
 }
 
 func newSinkNode(
@@ -176,10 +188,22 @@ func (n *sinkNode) flushSink(ctx context.Context, resolved model.ResolvedTs) (er
 		return nil
 	}
 	n.checkpointTs.Store(checkpoint)
+	n.observeFlushedTxns(checkpoint.ResolvedMark())
 
 	return nil
 }
 
+// observeFlushedTxns records the sink latency of pending transactions
+// committed before or at checkpointTs.
+func (n *sinkNode) observeFlushedTxns(checkpointTs model.Ts) {
+	i := 0
+	for ; i < len(n.pendingTxns) && n.pendingTxns[i].commitTs <= checkpointTs; i++ {
+		n.tracker.Observe(tracing.StageSink,
+			n.pendingTxns[i].startTs, n.pendingTxns[i].commitTs)
+	}
+	n.pendingTxns = n.pendingTxns[i:]
+}
+
 // emitRowToSink checks event and emits event.Row to sink.
 func (n *sinkNode) emitRowToSink(ctx context.Context, event *model.PolymorphicEvent) error {
 	failpoint.Inject("ProcessorSyncResolvedPreEmit", func() {
@@ -221,6 +245,13 @@ func (n *sinkNode) emitRowToSink(ctx context.Context, event *model.PolymorphicEv
 		return nil
 	}
 
+	if n.tracker != nil {
+		txn := pendingTxn{startTs: event.StartTs, commitTs: event.CRTs}
+		if l := len(n.pendingTxns); l == 0 || n.pendingTxns[l-1] != txn {
+			n.pendingTxns = append(n.pendingTxns, txn)
+		}
+	}
+
 	// This indicates that it is an update event,
 	// and after enable old value internally by default(but disable in the configuration).
 	// We need to handle the update event to be compatible with the old format.
@@ -295,6 +326,8 @@ func splitUpdateEvent(updateEvent *model.PolymorphicEvent) (*model.PolymorphicEv
 	}
 	// Align with the old format if old value disabled.
 	deleteEvent.Row.TableInfoVersion = 0
+	// The checksum of the update covers the columns, which are dropped.
+	deleteEvent.Row.UpdateChecksum()
 
 	insertEvent := *updateEvent
 	insertEventRow := *updateEvent.Row
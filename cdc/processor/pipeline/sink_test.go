@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	pmessage "github.com/pingcap/tiflow/pkg/pipeline/message"
 	"github.com/stretchr/testify/require"
@@ -46,6 +47,14 @@ func (c *mockFlowController) Consume(
 	return nil
 }
 
+func (c *mockFlowController) ShouldSpill(msg *model.PolymorphicEvent, size uint64) bool {
+	return false
+}
+
+func (c *mockFlowController) SplitTxn(callBack func(batchID uint64) error) error {
+	return nil
+}
+
 func (c *mockFlowController) Release(resolved model.ResolvedTs) {
 }
 
@@ -647,10 +656,13 @@ func TestSplitUpdateEventWhenDisableOldValue(t *testing.T) {
 		},
 	}
 
+	checksum := model.CalcRowChecksum(columns)
 	msg = pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
 		CRTs:  1,
 		RawKV: &model.RawKVEntry{OpType: model.OpTypePut},
-		Row:   &model.RowChangedEvent{CommitTs: 1, Columns: columns, PreColumns: preColumns},
+		Row: &model.RowChangedEvent{
+			CommitTs: 1, Columns: columns, PreColumns: preColumns, Checksum: &checksum,
+		},
 	})
 	ok, err = node.HandleMessage(ctx, msg)
 	require.Nil(t, err)
@@ -672,9 +684,15 @@ func TestSplitUpdateEventWhenDisableOldValue(t *testing.T) {
 		sink.received[deleteEventIndex].row.PreColumns[handleKeyColIndex].Flag.IsHandleKey(),
 	)
 
+	// The checksum of the delete event covers the retained pre cols.
+	require.Equal(t,
+		model.CalcRowChecksum(sink.received[deleteEventIndex].row.PreColumns),
+		*sink.received[deleteEventIndex].row.Checksum)
+
 	insertEventIndex := 1
 	require.Len(t, sink.received[insertEventIndex].row.Columns, 3)
 	require.Len(t, sink.received[insertEventIndex].row.PreColumns, 0)
+	require.Equal(t, checksum, *sink.received[insertEventIndex].row.Checksum)
 }
 
 type flushFlowController struct {
@@ -772,3 +790,42 @@ func TestSplitTxn(t *testing.T) {
 	_, err = sNode.HandleMessage(ctx, msg)
 	require.Regexp(t, ".*batch mode resolved ts is not supported.*", err)
 }
+
+func TestSinkLatencyTracking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	state := TableStatePrepared
+	changefeed := model.DefaultChangeFeedID("changefeed-id-test-latency")
+	sNode := newSinkNode(1, &mockSink{}, 0, 10, &mockFlowController{},
+		redo.NewDisabledManager(), &state, changefeed, true, false)
+	sNode.tracker = tracing.NewTableTracker(changefeed, 1, "test.t")
+	defer sNode.tracker.Close()
+	sNode.barrierTs = 10
+
+	// two rows of txn 1 and one row of txn 2
+	for _, e := range []struct{ startTs, commitTs model.Ts }{{1, 2}, {1, 2}, {3, 4}} {
+		msg := pmessage.PolymorphicEventMessage(&model.PolymorphicEvent{
+			StartTs: e.startTs,
+			CRTs:    e.commitTs,
+			RawKV:   &model.RawKVEntry{OpType: model.OpTypePut},
+			Row: &model.RowChangedEvent{
+				StartTs:  e.startTs,
+				CommitTs: e.commitTs,
+				Columns:  []*model.Column{{Name: "a", Value: 1}},
+			},
+		})
+		_, err := sNode.HandleMessage(ctx, msg)
+		require.Nil(t, err)
+	}
+	require.Len(t, sNode.pendingTxns, 2)
+
+	sinkLatency := func() uint64 {
+		return sNode.tracker.Latency().Stages[tracing.StageSink].Count
+	}
+	require.Nil(t, sNode.flushSink(ctx, model.NewResolvedTs(3)))
+	require.Equal(t, uint64(1), sinkLatency())
+	require.Len(t, sNode.pendingTxns, 1)
+	require.Nil(t, sNode.flushSink(ctx, model.NewResolvedTs(4)))
+	require.Equal(t, uint64(2), sinkLatency())
+	require.Len(t, sNode.pendingTxns, 0)
+}
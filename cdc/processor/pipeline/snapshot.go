@@ -0,0 +1,302 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tidbkv "github.com/pingcap/tidb/kv"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSnapshotChunkSize is the max number of rows which are scanned
+	// and flushed to the sink in a chunk of the initial snapshot.
+	defaultSnapshotChunkSize = 1024
+	// snapshotFlushCheckInterval is the interval to check whether
+	// a chunk of the initial snapshot has been flushed to the downstream.
+	snapshotFlushCheckInterval = 50 * time.Millisecond
+	// snapshotProgressPersistInterval is the min interval to persist the
+	// progress of the initial snapshot of a table. The rows flushed after
+	// the last persisted progress are sent again if the snapshot is resumed,
+	// which is safe because they are written in safe mode.
+	snapshotProgressPersistInterval = 10 * time.Second
+)
+
+// snapshotProgressStore persists the initial snapshot progress of tables.
+type snapshotProgressStore interface {
+	GetTableSnapshotProgress(ctx context.Context,
+		changeFeedID model.ChangeFeedID, tableID model.TableID,
+	) (*model.TableSnapshotProgress, error)
+	PutTableSnapshotProgress(ctx context.Context,
+		changeFeedID model.ChangeFeedID, tableID model.TableID,
+		progress *model.TableSnapshotProgress,
+	) error
+}
+
+// snapshotLoader replicates the existing rows of a table at the start-ts of
+// the changefeed as inserts, before any incremental change of the table is
+// sent to the sink. The table is scanned in chunks, and the progress is
+// persisted periodically after a chunk is flushed to the downstream, so the
+// snapshot can be resumed when the table is moved to another capture.
+//
+// The loader also serves the table resync requests, a re-synced table is
+// snapshotted at the start-ts it is added back to the changefeed.
+type snapshotLoader struct {
+	changefeed model.ChangeFeedID
+	tableID    model.TableID
+	tableName  string
+	snapshotTs model.Ts
+	// initial is true if the table starts from the start-ts of the
+	// changefeed with the initial snapshot enabled.
+	initial bool
+
+	storage tidbkv.Storage
+	mounter entry.Mounter
+	sink    sink.Sink
+	store   snapshotProgressStore
+
+	chunkSize       int
+	persistInterval time.Duration
+}
+
+func newSnapshotLoader(
+	changefeed model.ChangeFeedID, tableID model.TableID, tableName string,
+	snapshotTs model.Ts, initial bool, storage tidbkv.Storage,
+	mounter entry.Mounter, sink sink.Sink, store snapshotProgressStore,
+) *snapshotLoader {
+	return &snapshotLoader{
+		changefeed: changefeed,
+		tableID:    tableID,
+		tableName:  tableName,
+		snapshotTs: snapshotTs,
+		initial:    initial,
+		storage:    storage,
+		mounter:    mounter,
+		sink:       sink,
+		store:      store,
+		chunkSize:  defaultSnapshotChunkSize,
+
+		persistInterval: snapshotProgressPersistInterval,
+	}
+}
+
+// run replicates the rows of the table at the snapshot ts, it returns
+// once all the rows have been flushed to the downstream.
+func (l *snapshotLoader) run(ctx context.Context) error {
+	progress, err := l.store.GetTableSnapshotProgress(ctx, l.changefeed, l.tableID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case progress != nil && progress.ResyncPending():
+		if err := l.startResync(ctx, progress); err != nil {
+			return errors.Trace(err)
+		}
+	case progress != nil && progress.SnapshotTs == l.snapshotTs:
+		// resume the interrupted snapshot.
+	case l.initial:
+		progress = &model.TableSnapshotProgress{
+			SnapshotTs: l.snapshotTs,
+			TableName:  l.tableName,
+		}
+	default:
+		return nil
+	}
+	if progress.Done {
+		log.Info("initial snapshot of table is already finished",
+			zap.String("namespace", l.changefeed.Namespace),
+			zap.String("changefeed", l.changefeed.ID),
+			zap.Int64("tableID", l.tableID),
+			zap.String("tableName", l.tableName),
+			zap.Uint64("rows", progress.Rows))
+		return nil
+	}
+
+	prefix := tablecodec.GenTableRecordPrefix(l.tableID)
+	startKey, endKey := prefix, prefix.PrefixNext()
+	if len(progress.NextKey) != 0 {
+		startKey = progress.NextKey
+	}
+	log.Info("start initial snapshot of table",
+		zap.String("namespace", l.changefeed.Namespace),
+		zap.String("changefeed", l.changefeed.ID),
+		zap.Int64("tableID", l.tableID),
+		zap.String("tableName", l.tableName),
+		zap.Uint64("snapshotTs", l.snapshotTs),
+		zap.Uint64("resumedRows", progress.Rows))
+
+	start := time.Now()
+	lastPersisted := start
+	snap := l.storage.GetSnapshot(tidbkv.NewVersion(l.snapshotTs))
+	batchID := uint64(0)
+	for {
+		rows, nextKey, err := l.scanChunk(ctx, snap, startKey, endKey)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rows) != 0 {
+			batchID++
+			if err := l.flush(ctx, rows, batchID); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		progress.NextKey = nextKey
+		progress.Rows += uint64(len(rows))
+		progress.Done = len(nextKey) == 0
+		progress.UpdateTime = time.Now()
+		if progress.Done || progress.UpdateTime.Sub(lastPersisted) >= l.persistInterval {
+			err = l.store.PutTableSnapshotProgress(ctx, l.changefeed, l.tableID, progress)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			lastPersisted = progress.UpdateTime
+		}
+		if progress.Done {
+			break
+		}
+		startKey = nextKey
+	}
+	log.Info("initial snapshot of table is finished",
+		zap.String("namespace", l.changefeed.Namespace),
+		zap.String("changefeed", l.changefeed.ID),
+		zap.Int64("tableID", l.tableID),
+		zap.String("tableName", l.tableName),
+		zap.Uint64("rows", progress.Rows),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// startResync resets the progress of a requested resync to snapshot the
+// table at the snapshot ts, the table is truncated in the downstream first
+// if the policy requires.
+func (l *snapshotLoader) startResync(
+	ctx context.Context, progress *model.TableSnapshotProgress,
+) error {
+	log.Info("start resync of table",
+		zap.String("namespace", l.changefeed.Namespace),
+		zap.String("changefeed", l.changefeed.ID),
+		zap.Int64("tableID", l.tableID),
+		zap.String("tableName", l.tableName),
+		zap.String("policy", string(progress.Policy)),
+		zap.Uint64("snapshotTs", l.snapshotTs))
+	if progress.Policy == model.ResyncPolicyTruncate && progress.Table != nil {
+		ddl := &model.DDLEvent{
+			StartTs:  l.snapshotTs,
+			CommitTs: l.snapshotTs,
+			TableInfo: &model.SimpleTableInfo{
+				Schema:  progress.Table.Schema,
+				Table:   progress.Table.Table,
+				TableID: progress.Table.TableID,
+			},
+			Query: "TRUNCATE TABLE " + progress.Table.QuoteString(),
+			Type:  timodel.ActionTruncateTable,
+		}
+		if err := l.sink.EmitDDLEvent(ctx, ddl); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// The progress is persisted after the table is truncated, so the
+	// table is never truncated once the snapshot has flushed any row.
+	progress.SnapshotTs = l.snapshotTs
+	progress.NextKey = nil
+	progress.Rows = 0
+	progress.UpdateTime = time.Now()
+	return l.store.PutTableSnapshotProgress(ctx, l.changefeed, l.tableID, progress)
+}
+
+// scanChunk decodes at most chunkSize rows from startKey, it returns the
+// decoded rows and the key to continue with, which is nil if all the rows
+// of the table have been scanned.
+func (l *snapshotLoader) scanChunk(
+	ctx context.Context, snap tidbkv.Snapshot, startKey, endKey tidbkv.Key,
+) ([]*model.RowChangedEvent, tidbkv.Key, error) {
+	iter, err := snap.Iter(startKey, endKey)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer iter.Close()
+
+	rows := make([]*model.RowChangedEvent, 0, l.chunkSize)
+	for scanned := 0; iter.Valid(); scanned++ {
+		if scanned == l.chunkSize {
+			return rows, iter.Key().Clone(), nil
+		}
+		// The row is decoded with the schema at the snapshot ts, which is
+		// the snapshot of the schema at CRTs-1 in the mounter.
+		event := model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     iter.Key().Clone(),
+			Value:   iter.Value(),
+			StartTs: l.snapshotTs,
+			CRTs:    l.snapshotTs + 1,
+		})
+		ignored, err := l.mounter.DecodeEvent(ctx, event)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if !ignored {
+			row := event.Row
+			row.CommitTs = l.snapshotTs
+			// The rows flushed before the snapshot is resumed may be sent
+			// again, so they are always written in safe mode.
+			row.ReplicatingTs = l.snapshotTs
+			rows = append(rows, row)
+		}
+		if err := iter.Next(); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return rows, nil, nil
+}
+
+// flush sends the rows to the sink and waits until they are flushed to the
+// downstream, a batch resolved ts at the snapshot ts is used so that the
+// checkpoint of the table does not pass the snapshot ts.
+func (l *snapshotLoader) flush(
+	ctx context.Context, rows []*model.RowChangedEvent, batchID uint64,
+) error {
+	if err := l.sink.EmitRowChangedEvents(ctx, rows...); err != nil {
+		return errors.Trace(err)
+	}
+	resolved := model.ResolvedTs{
+		Ts:      l.snapshotTs,
+		Mode:    model.BatchResolvedMode,
+		BatchID: batchID,
+	}
+	ticker := time.NewTicker(snapshotFlushCheckInterval)
+	defer ticker.Stop()
+	for {
+		checkpoint, err := l.sink.FlushRowChangedEvents(ctx, l.tableID, resolved)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if checkpoint.EqualOrGreater(resolved) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tidbkv "github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	pfilter "github.com/pingcap/tiflow/pkg/filter"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+type mockSnapshotProgressStore struct {
+	progresses map[model.TableID]*model.TableSnapshotProgress
+	puts       int
+}
+
+func (s *mockSnapshotProgressStore) GetTableSnapshotProgress(
+	_ context.Context, _ model.ChangeFeedID, tableID model.TableID,
+) (*model.TableSnapshotProgress, error) {
+	return s.progresses[tableID], nil
+}
+
+func (s *mockSnapshotProgressStore) PutTableSnapshotProgress(
+	_ context.Context, _ model.ChangeFeedID, tableID model.TableID,
+	progress *model.TableSnapshotProgress,
+) error {
+	s.puts++
+	cloned := *progress
+	s.progresses[tableID] = &cloned
+	return nil
+}
+
+// mockDDLRecordSink records the DDL events emitted to the sink.
+type mockDDLRecordSink struct {
+	*mockSink
+	ddls []*model.DDLEvent
+}
+
+func (s *mockDDLRecordSink) EmitDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	s.ddls = append(s.ddls, ddl)
+	return nil
+}
+
+func TestSnapshotLoader(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+	helper.Tk().MustExec("create table t(id int primary key, v varchar(10))")
+	for _, sql := range []string{
+		"insert into t values (1, 'a')",
+		"insert into t values (2, 'b')",
+		"insert into t values (3, 'c')",
+		"insert into t values (4, 'd')",
+		"insert into t values (5, 'e')",
+	} {
+		helper.Tk().MustExec(sql)
+	}
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+	// rows written after the snapshot ts are not scanned.
+	helper.Tk().MustExec("insert into t values (6, 'f')")
+
+	changefeed := model.DefaultChangeFeedID("test-snapshot")
+	cfg := config.GetDefaultReplicaConfig()
+	filter, err := pfilter.NewFilter(cfg, "")
+	require.Nil(t, err)
+	schemaStorage, err := entry.NewSchemaStorage(helper.GetCurrentMeta(),
+		ver.Ver, filter, false, changefeed)
+	require.Nil(t, err)
+	schemaStorage.AdvanceResolvedTs(ver.Ver)
+	tableInfo, ok := schemaStorage.GetLastSnapshot().TableByName("test", "t")
+	require.True(t, ok)
+	mounter := entry.NewMounter(schemaStorage, changefeed, time.Local, filter, true, false)
+
+	s := &mockSink{}
+	store := &mockSnapshotProgressStore{
+		progresses: map[model.TableID]*model.TableSnapshotProgress{
+			// the progress left by a removed changefeed is ignored.
+			tableInfo.ID: {SnapshotTs: ver.Ver - 1, Done: true},
+		},
+	}
+	loader := newSnapshotLoader(changefeed, tableInfo.ID, "`test`.`t`",
+		ver.Ver, true, helper.Storage(), mounter, s, store)
+	loader.chunkSize = 2
+	ctx := context.Background()
+	require.Nil(t, loader.run(ctx))
+
+	var ids []interface{}
+	var resolvedCount int
+	for _, r := range s.received {
+		if r.row == nil {
+			require.Equal(t, ver.Ver, r.resolvedTs)
+			resolvedCount++
+			continue
+		}
+		require.Equal(t, ver.Ver, r.row.CommitTs)
+		require.Equal(t, ver.Ver, r.row.ReplicatingTs)
+		require.Nil(t, r.row.PreColumns)
+		ids = append(ids, r.row.Columns[0].Value)
+	}
+	require.Equal(t, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}, ids)
+	require.Equal(t, 3, resolvedCount)
+	progress := store.progresses[tableInfo.ID]
+	require.True(t, progress.Done)
+	require.Equal(t, uint64(5), progress.Rows)
+	require.Equal(t, ver.Ver, progress.SnapshotTs)
+	// the progress is only persisted once all the rows are flushed, since
+	// the chunks are flushed within the persist interval.
+	require.Equal(t, 1, store.puts)
+
+	// a finished snapshot is not taken again.
+	s.received = nil
+	require.Nil(t, loader.run(ctx))
+	require.Len(t, s.received, 0)
+
+	// an interrupted snapshot is resumed from the next key, and the progress
+	// is persisted after every chunk without the persist interval.
+	store.progresses[tableInfo.ID] = &model.TableSnapshotProgress{
+		SnapshotTs: ver.Ver,
+		NextKey:    tablecodec.EncodeRowKeyWithHandle(tableInfo.ID, tidbkv.IntHandle(2)),
+		Rows:       1,
+	}
+	loader.persistInterval = 0
+	store.puts = 0
+	require.Nil(t, loader.run(ctx))
+	require.Len(t, s.received, 6)
+	require.Equal(t, int64(2), s.received[0].row.Columns[0].Value)
+	require.True(t, store.progresses[tableInfo.ID].Done)
+	require.Equal(t, uint64(5), store.progresses[tableInfo.ID].Rows)
+	require.Equal(t, 2, store.puts)
+}
+
+func TestSnapshotLoaderResync(t *testing.T) {
+	helper := entry.NewSchemaTestHelper(t)
+	defer helper.Close()
+	helper.Tk().MustExec("use test")
+	helper.Tk().MustExec("create table t(id int primary key, v varchar(10))")
+	helper.Tk().MustExec("insert into t values (1, 'a'), (2, 'b')")
+	ver, err := helper.Storage().CurrentVersion(oracle.GlobalTxnScope)
+	require.Nil(t, err)
+
+	changefeed := model.DefaultChangeFeedID("test-resync")
+	cfg := config.GetDefaultReplicaConfig()
+	filter, err := pfilter.NewFilter(cfg, "")
+	require.Nil(t, err)
+	schemaStorage, err := entry.NewSchemaStorage(helper.GetCurrentMeta(),
+		ver.Ver, filter, false, changefeed)
+	require.Nil(t, err)
+	schemaStorage.AdvanceResolvedTs(ver.Ver)
+	tableInfo, ok := schemaStorage.GetLastSnapshot().TableByName("test", "t")
+	require.True(t, ok)
+	mounter := entry.NewMounter(schemaStorage, changefeed, time.Local, filter, true, false)
+
+	s := &mockDDLRecordSink{mockSink: &mockSink{}}
+	store := &mockSnapshotProgressStore{
+		progresses: map[model.TableID]*model.TableSnapshotProgress{},
+	}
+	ctx := context.Background()
+
+	// no snapshot is taken if neither initial snapshot nor resync is required.
+	loader := newSnapshotLoader(changefeed, tableInfo.ID, "`test`.`t`",
+		ver.Ver, false, helper.Storage(), mounter, s, store)
+	require.Nil(t, loader.run(ctx))
+	require.Len(t, s.received, 0)
+	require.Equal(t, 0, store.puts)
+
+	// the table is truncated before it is re-synced.
+	table := model.TableName{Schema: "test", Table: "t", TableID: tableInfo.ID}
+	store.progresses[tableInfo.ID] = model.NewTableResyncRequest(
+		table, model.ResyncPolicyTruncate)
+	require.Nil(t, loader.run(ctx))
+	require.Len(t, s.ddls, 1)
+	require.Equal(t, "TRUNCATE TABLE `test`.`t`", s.ddls[0].Query)
+	require.Equal(t, "test", s.ddls[0].TableInfo.Schema)
+	require.Len(t, s.received, 3)
+	progress := store.progresses[tableInfo.ID]
+	require.True(t, progress.Done)
+	require.True(t, progress.Resync)
+	require.Equal(t, ver.Ver, progress.SnapshotTs)
+	require.Equal(t, uint64(2), progress.Rows)
+
+	// the rows are overwritten without truncating the table.
+	s.ddls = nil
+	s.received = nil
+	store.progresses[tableInfo.ID] = model.NewTableResyncRequest(
+		table, model.ResyncPolicyOverwrite)
+	require.Nil(t, loader.run(ctx))
+	require.Len(t, s.ddls, 0)
+	require.Len(t, s.received, 3)
+	require.True(t, store.progresses[tableInfo.ID].Done)
+}
@@ -23,10 +23,12 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/flowcontrol"
 	"github.com/pingcap/tiflow/cdc/sorter"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb"
 	"github.com/pingcap/tiflow/cdc/sorter/memory"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	"github.com/pingcap/tiflow/pkg/actor"
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -49,6 +51,12 @@ type sorterNode struct {
 
 	// for per-table flow control
 	flowController tableFlowController
+	// rateLimiter limits the throughput of the changefeed, it is nil if the
+	// changefeed is not limited. tableRateLimiter is derived from it when
+	// the first row of the table is received. The rate limit is enforced here
+	// rather than in the sink, so the throttled rows stay in the sorter.
+	rateLimiter      *flowcontrol.ChangefeedRateLimiter
+	tableRateLimiter *flowcontrol.TableRateLimiter
 
 	mounter entry.Mounter
 
@@ -72,6 +80,15 @@ type sorterNode struct {
 
 	redoLogEnabled bool
 	changefeed     model.ChangeFeedID
+
+	// snapshot replicates the existing rows of the table before the sorter
+	// output is sent to the next node, it is nil if the initial snapshot is
+	// not required.
+	snapshot *snapshotLoader
+
+	// tracker records the latency of events output by the sorter and
+	// the mounter.
+	tracker *tracing.TableTracker
 }
 
 func newSorterNode(
@@ -112,14 +129,19 @@ func createSorter(ctx pipeline.NodeContext, tableName string, tableID model.Tabl
 
 		if config.GetGlobalServerConfig().Debug.EnableDBSorter {
 			startTs := ctx.ChangefeedVars().Info.StartTs
+			var priority string
+			if ctx.ChangefeedVars().Info.Config != nil {
+				priority = ctx.ChangefeedVars().Info.Config.Priority
+			}
+			weight := config.ChangefeedPriorityWeight(priority)
 			ssystem := ctx.GlobalVars().SorterSystem
 			dbActorID := ssystem.DBActorID(uint64(tableID))
 			compactScheduler := ctx.GlobalVars().SorterSystem.CompactScheduler()
 			levelSorter, err := leveldb.NewSorter(
-				ctx, tableID, startTs, ssystem.DBRouter, dbActorID,
+				ctx, tableID, startTs, weight, ssystem.DBRouter, dbActorID,
 				ssystem.WriterSystem, ssystem.WriterRouter,
 				ssystem.ReaderSystem, ssystem.ReaderRouter,
-				compactScheduler, config.GetGlobalServerConfig().Debug.DB)
+				compactScheduler, ssystem.DiskQuota(), config.GetGlobalServerConfig().Debug.DB)
 			if err != nil {
 				return nil, err
 			}
@@ -220,7 +242,188 @@ func (n *sorterNode) start(
 		n.state.Store(TableStateReplicating)
 		eventSorter.EmitStartTs(stdCtx, startTs)
 
+		if n.snapshot != nil {
+			if err := n.snapshot.run(stdCtx); err != nil {
+				if stdCtx.Err() == nil {
+					ctx.Throw(errors.Trace(err))
+				}
+				return nil
+			}
+		}
+
+		sendBatchResolved := func(commitTs model.Ts, batchID uint64) {
+			msg := model.NewResolvedPolymorphicEvent(0, commitTs)
+			msg.Resolved = &model.ResolvedTs{
+				Ts:      commitTs,
+				Mode:    model.BatchResolvedMode,
+				BatchID: batchID,
+			}
+			ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
+		}
+
+		// handleEvent sends the event to the next node. It returns true without
+		// sending the event if the event should be spilled to disk because the
+		// memory quota of the changefeed is exhausted.
+		handleEvent := func(msg *model.PolymorphicEvent) (bool, error) {
+			if msg.RawKV.OpType == model.OpTypeResolved {
+				if msg.CRTs < lastSentResolvedTs {
+					return false, nil
+				}
+				tickMsg := message.ValueMessage(pmessage.TickMessage())
+				_ = tableActorRouter.Send(tableActorID, tickMsg)
+				lastSentResolvedTs = msg.CRTs
+				lastSendResolvedTsTime = time.Now()
+				ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
+				return false, nil
+			}
+
+			// Events replayed from the spill queue may have been mounted.
+			if msg.Row == nil {
+				ignored, err := n.mounter.DecodeEvent(ctx, msg)
+				if err != nil {
+					log.Error("Got an error from mounter, sorter will stop.", zap.Error(err))
+					return false, errors.Trace(err)
+				}
+				if ignored {
+					return false, nil
+				}
+				n.tracker.Observe(tracing.StageMounter, msg.StartTs, msg.CRTs)
+			}
+			commitTs := msg.CRTs
+			// We interpolate a resolved-ts if none has been sent for some time.
+			if time.Since(lastSendResolvedTsTime) > resolvedTsInterpolateInterval {
+				resolvedTsInterpolateFunc(commitTs)
+			}
+
+			// For all rows, we add table replicate ts, so mysql sink can
+			// determine when to turn off safe-mode.
+			msg.Row.ReplicatingTs = replicateTs
+			// We calculate memory consumption by RowChangedEvent size.
+			// It's much larger than RawKVEntry.
+			size := uint64(msg.Row.ApproximateBytes())
+			if n.flowController.ShouldSpill(msg, size) {
+				// Same as blocking, we send a Resolved Event here to elicit a
+				// sink-flush, so that the memory consumed by the table can be
+				// released.
+				if commitTs > lastCRTs {
+					resolvedTsInterpolateFunc(commitTs)
+					return true, nil
+				}
+				// The transaction is split, the rows consumed before are
+				// flushed by a batch resolved event.
+				err := n.flowController.SplitTxn(func(batchID uint64) error {
+					sendBatchResolved(commitTs, batchID)
+					return nil
+				})
+				if err != nil {
+					return false, errors.Trace(err)
+				}
+				return true, nil
+			}
+			if n.rateLimiter != nil && n.tableRateLimiter == nil {
+				n.tableRateLimiter = n.rateLimiter.ForTable(
+					msg.Row.Table.Schema, msg.Row.Table.Table)
+			}
+			err := n.tableRateLimiter.Wait(stdCtx, 1, int(size), func() error {
+				// Send a Resolved Event before blocking, so that the rows
+				// sent before can be flushed while the table is throttled.
+				resolvedTsInterpolateFunc(commitTs)
+				return nil
+			})
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			// NOTE when redo log enabled, we allow the quota to be exceeded if blocking
+			// means interrupting a transaction. Otherwise the pipeline would deadlock.
+			err = n.flowController.Consume(msg, size, func(batchID uint64) error {
+				if commitTs > lastCRTs {
+					// If we are blocking, we send a Resolved Event here to elicit a sink-flush.
+					// Not sending a Resolved Event here will very likely deadlock the pipeline.
+					resolvedTsInterpolateFunc(commitTs)
+				} else if commitTs == lastCRTs {
+					sendBatchResolved(commitTs, batchID)
+				} else {
+					log.Panic("flow control blocked, report a bug",
+						zap.Uint64("commitTs", commitTs),
+						zap.Uint64("lastCommitTs", lastCRTs),
+						zap.Uint64("lastSentResolvedTs", lastSentResolvedTs))
+				}
+				return nil
+			})
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			lastCRTs = msg.CRTs
+			ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
+			return false, nil
+		}
+
+		// Events are spilled to the sort dir instead of blocking the sorter
+		// when the memory quota of the changefeed is exhausted, and they are
+		// replayed in order once the quota is released. spillHead is the first
+		// spilled event, which has been popped from the queue but not sent.
+		spillQueue := unified.NewSpillQueue(
+			config.GetGlobalServerConfig().Sorter.SortDir, n.changefeed, n.tableID)
+		defer func() {
+			if err := spillQueue.Close(); err != nil {
+				log.Warn("failed to close spill queue",
+					zap.Int64("tableID", n.tableID),
+					zap.String("tableName", n.tableName),
+					zap.Error(err))
+			}
+		}()
+		var spillHead *model.PolymorphicEvent
+		replayTicker := time.NewTicker(spillReplayInterval)
+		defer replayTicker.Stop()
+
+		// replay sends spilled events to the next node until
+		// the spill queue is drained or the quota is exhausted again.
+		replay := func() error {
+			for {
+				msg := spillHead
+				if msg == nil {
+					var err error
+					msg, err = spillQueue.Pop()
+					if err != nil {
+						return errors.Trace(err)
+					}
+					if msg == nil {
+						log.Info("spilled events are replayed",
+							zap.Int64("tableID", n.tableID),
+							zap.String("tableName", n.tableName),
+							zap.String("namespace", n.changefeed.Namespace),
+							zap.String("changefeed", n.changefeed.ID))
+						return nil
+					}
+				}
+				spilled, err := handleEvent(msg)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				if spilled {
+					spillHead = msg
+					return nil
+				}
+				spillHead = nil
+			}
+		}
+
+		handleError := func(err error) {
+			if cerror.ErrFlowControllerAborted.Equal(err) {
+				log.Info("flow control cancelled for table",
+					zap.Int64("tableID", n.tableID),
+					zap.String("tableName", n.tableName))
+				return
+			}
+			ctx.Throw(err)
+		}
+
 		for {
+			spilling := spillHead != nil || spillQueue.Len() > 0
+			var replayCh <-chan time.Time
+			if spilling {
+				replayCh = replayTicker.C
+			}
 			// We must call `sorter.Output` before receiving resolved events.
 			// Skip calling `sorter.Output` and caching output channel may fail
 			// to receive any events.
@@ -228,6 +431,11 @@ func (n *sorterNode) start(
 			select {
 			case <-stdCtx.Done():
 				return nil
+			case <-replayCh:
+				if err := replay(); err != nil {
+					handleError(err)
+					return nil
+				}
 			case msg, ok := <-output:
 				if !ok {
 					// sorter output channel closed
@@ -244,75 +452,32 @@ func (n *sorterNode) start(
 						zap.Uint64("CRTs", msg.CRTs), zap.Uint64("startTs", startTs))
 					continue
 				}
-
 				if msg.RawKV.OpType != model.OpTypeResolved {
-					ignored, err := n.mounter.DecodeEvent(ctx, msg)
-					if err != nil {
-						log.Error("Got an error from mounter, sorter will stop.", zap.Error(err))
-						ctx.Throw(err)
-						return errors.Trace(err)
-					}
-					if ignored {
-						continue
-					}
-					commitTs := msg.CRTs
-					// We interpolate a resolved-ts if none has been sent for some time.
-					if time.Since(lastSendResolvedTsTime) > resolvedTsInterpolateInterval {
-						resolvedTsInterpolateFunc(commitTs)
-					}
+					n.tracker.Observe(tracing.StageSorter, msg.StartTs, msg.CRTs)
+				}
 
-					// For all rows, we add table replicate ts, so mysql sink can
-					// determine when to turn off safe-mode.
-					msg.Row.ReplicatingTs = replicateTs
-					// We calculate memory consumption by RowChangedEvent size.
-					// It's much larger than RawKVEntry.
-					size := uint64(msg.Row.ApproximateBytes())
-					// NOTE when redo log enabled, we allow the quota to be exceeded if blocking
-					// means interrupting a transaction. Otherwise the pipeline would deadlock.
-					err = n.flowController.Consume(msg, size, func(batchID uint64) error {
-						if commitTs > lastCRTs {
-							// If we are blocking, we send a Resolved Event here to elicit a sink-flush.
-							// Not sending a Resolved Event here will very likely deadlock the pipeline.
-							resolvedTsInterpolateFunc(commitTs)
-						} else if commitTs == lastCRTs {
-							// send batch resolve event
-							msg := model.NewResolvedPolymorphicEvent(0, lastCRTs)
-							msg.Resolved = &model.ResolvedTs{
-								Ts:      commitTs,
-								Mode:    model.BatchResolvedMode,
-								BatchID: batchID,
-							}
-							ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
-						} else {
-							log.Panic("flow control blocked, report a bug",
-								zap.Uint64("commitTs", commitTs),
-								zap.Uint64("lastCommitTs", lastCRTs),
-								zap.Uint64("lastSentResolvedTs", lastSentResolvedTs))
-						}
-						return nil
-					})
+				if !spilling {
+					spilled, err := handleEvent(msg)
 					if err != nil {
-						if cerror.ErrFlowControllerAborted.Equal(err) {
-							log.Info("flow control cancelled for table",
-								zap.Int64("tableID", n.tableID),
-								zap.String("tableName", n.tableName))
-						} else {
-							ctx.Throw(err)
-						}
+						handleError(err)
 						return nil
 					}
-					lastCRTs = msg.CRTs
-				} else {
-					// handle OpTypeResolved
-					if msg.CRTs < lastSentResolvedTs {
+					if !spilled {
 						continue
 					}
-					tickMsg := message.ValueMessage(pmessage.TickMessage())
-					_ = tableActorRouter.Send(tableActorID, tickMsg)
-					lastSentResolvedTs = msg.CRTs
-					lastSendResolvedTsTime = time.Now()
+					log.Info("memory quota exhausted, spill events to disk",
+						zap.Int64("tableID", n.tableID),
+						zap.String("tableName", n.tableName),
+						zap.Uint64("commitTs", msg.CRTs),
+						zap.String("namespace", n.changefeed.Namespace),
+						zap.String("changefeed", n.changefeed.ID))
+				}
+				// Once spilling started, all the following events must be
+				// spilled to keep them in order.
+				if err := spillQueue.Push(msg); err != nil {
+					handleError(err)
+					return nil
 				}
-				ctx.SendToNextNode(pmessage.PolymorphicEventMessage(msg))
 			}
 		}
 	})
@@ -24,6 +24,9 @@ const (
 	// TODO determine a reasonable default value
 	// This is part of sink performance optimization
 	resolvedTsInterpolateInterval = 200 * time.Millisecond
+	// spillReplayInterval is the interval to check whether the spilled
+	// events can be replayed.
+	spillReplayInterval = 100 * time.Millisecond
 )
 
 // TableState is state of the table pipeline
@@ -117,6 +120,8 @@ type tableFlowController interface {
 		size uint64,
 		blockCallBack func(batchID uint64) error,
 	) error
+	ShouldSpill(msg *model.PolymorphicEvent, size uint64) bool
+	SplitTxn(callBack func(batchID uint64) error) error
 	Release(resolved model.ResolvedTs)
 	Abort()
 	GetConsumption() uint64
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/flowcontrol"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	"github.com/pingcap/tiflow/pkg/actor"
 	"github.com/pingcap/tiflow/pkg/actor/message"
 	serverConfig "github.com/pingcap/tiflow/pkg/config"
@@ -79,6 +80,8 @@ type tableActor struct {
 	markTableID    int64
 	targetTs       model.Ts
 	memoryQuota    uint64
+	sharedQuota    *flowcontrol.ChangefeedMemoryQuota
+	rateLimiter    *flowcontrol.ChangefeedRateLimiter
 	replicaInfo    *model.TableReplicaInfo
 	replicaConfig  *serverConfig.ReplicaConfig
 	changefeedVars *cdcContext.ChangefeedVars
@@ -86,6 +89,8 @@ type tableActor struct {
 	// these fields below are used in logs and metrics only
 	changefeedID model.ChangeFeedID
 	tableName    string
+	// tracker records the replication latency of the table at each stage
+	tracker *tracing.TableTracker
 
 	// use to report error to processor
 	reportErr func(error)
@@ -106,6 +111,9 @@ func NewTableActor(cdcCtx cdcContext.Context,
 	replicaInfo *model.TableReplicaInfo,
 	sink sink.Sink,
 	redoManager redo.LogManager,
+	memoryQuota uint64,
+	sharedQuota *flowcontrol.ChangefeedMemoryQuota,
+	rateLimiter *flowcontrol.ChangefeedRateLimiter,
 	targetTs model.Ts,
 ) (TablePipeline, error) {
 	config := cdcCtx.ChangefeedVars().Info.Config
@@ -130,7 +138,9 @@ func NewTableActor(cdcCtx cdcContext.Context,
 		tableID:       tableID,
 		markTableID:   replicaInfo.MarkTableID,
 		tableName:     tableName,
-		memoryQuota:   serverConfig.GetGlobalServerConfig().PerTableMemoryQuota,
+		memoryQuota:   memoryQuota,
+		sharedQuota:   sharedQuota,
+		rateLimiter:   rateLimiter,
 		upstream:      up,
 		mounter:       mounter,
 		replicaInfo:   replicaInfo,
@@ -283,14 +293,28 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 		zap.Uint64("quota", t.memoryQuota))
 
 	splitTxn := t.replicaConfig.Sink.TxnAtomicity.ShouldSplitTxn()
+	t.tracker = tracing.NewTableTracker(t.changefeedID, t.tableID, t.tableName)
 
 	flowController := flowcontrol.NewTableFlowController(t.memoryQuota,
-		t.redoManager.Enabled(), splitTxn)
+		t.sharedQuota, t.redoManager.Enabled(), splitTxn)
 	sorterNode := newSorterNode(t.tableName, t.tableID,
 		t.replicaInfo.StartTs, flowController,
 		t.mounter, &t.state, t.changefeedID, t.redoManager.Enabled(),
 		t.upstream.PDClient,
 	)
+	sorterNode.tracker = t.tracker
+	sorterNode.rateLimiter = t.rateLimiter
+	// The initial snapshot is taken only when the table starts from the
+	// start-ts of the changefeed, the table is resumed from the persisted
+	// progress if the snapshot was interrupted. The loader is created for
+	// every table to find out whether the table is requested to be re-synced.
+	if t.globalVars.EtcdClient != nil {
+		initial := t.replicaConfig.InitialSnapshot &&
+			t.replicaInfo.StartTs == t.changefeedVars.Info.StartTs
+		sorterNode.snapshot = newSnapshotLoader(t.changefeedID, t.tableID,
+			t.tableName, t.replicaInfo.StartTs, initial, t.upstream.KVStorage,
+			t.mounter, t.tableSink, t.globalVars.EtcdClient)
+	}
 	t.sortNode = sorterNode
 	sortActorNodeContext := newContext(sdtTableContext, t.tableName,
 		t.globalVars.TableActorSystem.Router(),
@@ -304,6 +328,7 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	}
 
 	pullerNode := newPullerNode(t.tableID, t.replicaInfo.StartTs, t.tableName, t.changefeedVars.ID)
+	pullerNode.tracker = t.tracker
 	pullerActorNodeContext := newContext(sdtTableContext,
 		t.tableName,
 		t.globalVars.TableActorSystem.Router(),
@@ -323,6 +348,7 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	actorSinkNode := newSinkNode(t.tableID, t.tableSink,
 		t.replicaInfo.StartTs, t.targetTs, flowController, t.redoManager,
 		&t.state, t.changefeedID, t.replicaConfig.EnableOldValue, splitTxn)
+	actorSinkNode.tracker = t.tracker
 	t.sinkNode = actorSinkNode
 
 	// construct sink actor node, it gets message from sortNode
@@ -331,7 +357,9 @@ func (t *tableActor) start(sdtTableContext context.Context) error {
 	) (bool, error) {
 		return actorSinkNode.HandleMessage(sdtTableContext, msg)
 	}
-	t.nodes = append(t.nodes, NewActorNode(messageFetchFunc, messageProcessFunc))
+	node := NewActorNode(messageFetchFunc, messageProcessFunc)
+	node.batchSize = actorNodeBatchSize(t.changefeedVars)
+	t.nodes = append(t.nodes, node)
 
 	t.started = true
 	log.Info("table actor is started",
@@ -362,6 +390,7 @@ func (t *tableActor) stop(err error) {
 		t.sortNode.releaseResource(t.changefeedID)
 	}
 	t.cancel()
+	t.tracker.Close()
 	if t.sinkNode != nil {
 		if err := t.sinkNode.releaseResource(t.stopCtx); err != nil {
 			log.Warn("close sink failed",
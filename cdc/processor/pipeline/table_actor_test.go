@@ -396,7 +396,8 @@ func TestNewTableActor(t *testing.T) {
 		&model.TableReplicaInfo{
 			StartTs:     0,
 			MarkTableID: 1,
-		}, &mockSink{}, redo.NewDisabledManager(), 10)
+		}, &mockSink{}, redo.NewDisabledManager(),
+		serverConfig.GetGlobalServerConfig().PerTableMemoryQuota, nil, nil, 10)
 	require.NotNil(t, tbl)
 	require.Nil(t, err)
 	require.Equal(t, TableStatePreparing, tbl.State())
@@ -413,7 +414,8 @@ func TestNewTableActor(t *testing.T) {
 		&model.TableReplicaInfo{
 			StartTs:     0,
 			MarkTableID: 1,
-		}, &mockSink{}, redo.NewDisabledManager(), 10)
+		}, &mockSink{}, redo.NewDisabledManager(),
+		serverConfig.GetGlobalServerConfig().PerTableMemoryQuota, nil, nil, 10)
 	require.Nil(t, tbl)
 	require.NotNil(t, err)
 
@@ -34,7 +34,10 @@ import (
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
 	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/flowcontrol"
 	sinkmetric "github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/mq"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/factory"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -65,10 +68,30 @@ type processor struct {
 	schemaStorage entry.SchemaStorage
 	lastSchemaTs  model.Ts
 
-	filter      filter.Filter
-	mounter     entry.Mounter
-	sink        sink.Sink
-	redoManager redo.LogManager
+	filter  filter.Filter
+	mounter entry.Mounter
+	sink    sink.Sink
+	// sinkV2Factory creates the table sinks of sinkv2, it is used instead of
+	// sink if the new sink is enabled and supports the sink URI.
+	sinkV2Factory *factory.SinkFactory
+	// sinkV2DDLSink executes the DDLs of the re-synced tables if sinkv2
+	// is used.
+	sinkV2DDLSink *lazyDDLSinkV2
+	redoManager   redo.LogManager
+	// memoryQuota is shared by all tables, it is nil if the changefeed
+	// does not set a memory quota.
+	memoryQuota *flowcontrol.ChangefeedMemoryQuota
+	// rateLimiter is shared by all tables, it is nil if the changefeed
+	// does not set a rate limit.
+	rateLimiter *flowcontrol.ChangefeedRateLimiter
+	// tableMemoryQuota is the memory quota of the sink of each table, it is
+	// allocated by the processor manager according to the priorities of the
+	// changefeeds on the capture, 0 means the default per-table quota.
+	// It takes effect on the tables added after it is changed.
+	tableMemoryQuota uint64
+	// partitionNums are the partition numbers of the topics applied to the
+	// sink, see applyPartitionNums.
+	partitionNums map[string]int32
 
 	initialized bool
 	errCh       chan error
@@ -566,6 +589,7 @@ func (p *processor) tick(ctx cdcContext.Context, state *orchestrator.ChangefeedR
 	pdTime, _ := p.upstream.PDClock.CurrentTime()
 
 	p.handlePosition(oracle.GetPhysical(pdTime))
+	p.applyPartitionNums()
 	p.pushResolvedTs2Table()
 
 	p.doGCSchemaStorage(ctx)
@@ -656,6 +680,7 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 		contextutil.TimezoneFromCtx(ctx),
 		p.filter,
 		p.changefeed.Info.Config.EnableOldValue,
+		p.changefeed.Info.Config.EnableRowChecksum,
 	)
 
 	log.Info("processor try new sink",
@@ -663,13 +688,25 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 		zap.String("changefeed", p.changefeed.ID.ID))
 
 	start := time.Now()
-	p.sink, err = sink.New(
-		stdCtx,
-		p.changefeed.ID,
-		p.changefeed.Info.SinkURI,
-		p.changefeed.Info.Config,
-		errCh,
-	)
+	if config.GetGlobalServerConfig().Debug.EnableNewSink &&
+		factory.IsSupported(p.changefeed.Info.SinkURI) {
+		p.sinkV2Factory, err = factory.New(
+			stdCtx,
+			p.changefeed.Info.SinkURI,
+			p.changefeed.Info.Config,
+			errCh,
+		)
+		p.sinkV2DDLSink = newLazyDDLSinkV2(stdCtx,
+			p.changefeed.Info.SinkURI, p.changefeed.Info.Config)
+	} else {
+		p.sink, err = sink.New(
+			stdCtx,
+			p.changefeed.ID,
+			p.changefeed.Info.SinkURI,
+			p.changefeed.Info.Config,
+			errCh,
+		)
+	}
 	if err != nil {
 		log.Info("processor new sink failed",
 			zap.String("namespace", p.changefeedID.Namespace),
@@ -680,6 +717,15 @@ func (p *processor) lazyInitImpl(ctx cdcContext.Context) error {
 	log.Info("processor try new sink success",
 		zap.Duration("duration", time.Since(start)))
 
+	if quota := p.changefeed.Info.Config.MemoryQuota; quota > 0 {
+		p.memoryQuota = flowcontrol.NewChangefeedMemoryQuota(quota)
+	}
+	p.rateLimiter, err = flowcontrol.NewChangefeedRateLimiter(
+		p.changefeed.Info.Config.RateLimit, p.changefeed.Info.Config.CaseSensitive)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	redoManagerOpts := &redo.ManagerOptions{EnableBgRunner: true, ErrCh: errCh}
 	p.redoManager, err = redo.NewManager(stdCtx, p.changefeed.Info.Config.Consistent, redoManagerOpts)
 	if err != nil {
@@ -849,6 +895,41 @@ func (p *processor) handlePosition(currentTs int64) {
 	p.resolvedTs = minResolvedTs
 }
 
+// applyPartitionNums applies the partition numbers of the topics in the
+// changefeed status to the sink. They are changed by the owner at a resolved
+// ts barrier, so they must be applied before the events after the barrier
+// are dispatched, see mq.PartitionChangeSink.
+func (p *processor) applyPartitionNums() {
+	var changed map[string]int32
+	for topic, partitionNum := range p.changefeed.Status.PartitionNums {
+		if num, ok := p.partitionNums[topic]; ok && num == partitionNum {
+			continue
+		}
+		if changed == nil {
+			changed = make(map[string]int32)
+		}
+		changed[topic] = partitionNum
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if p.sinkV2Factory != nil {
+		p.sinkV2Factory.ApplyPartitionNums(changed)
+	} else if applier, ok := p.sink.(mq.PartitionNumsApplier); ok {
+		applier.ApplyPartitionNums(changed)
+	}
+	if p.partitionNums == nil {
+		p.partitionNums = make(map[string]int32)
+	}
+	for topic, partitionNum := range changed {
+		p.partitionNums[topic] = partitionNum
+	}
+	log.Info("processor applied the partition numbers",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.Any("partitionNums", changed))
+}
+
 // pushResolvedTs2Table sends global resolved ts to all the table pipelines.
 func (p *processor) pushResolvedTs2Table() {
 	resolvedTs := p.changefeed.Status.ResolvedTs
@@ -908,9 +989,22 @@ func (p *processor) createTablePipelineImpl(
 
 	tableName := p.getTableName(ctx, tableID)
 
-	s, err := sink.NewTableSink(p.sink, tableID, p.metricsTableSinkTotalRows)
-	if err != nil {
-		return nil, errors.Trace(err)
+	var (
+		s   sink.Sink
+		err error
+	)
+	if p.sinkV2Factory != nil {
+		s = newTableSinkV2(tableID, p.sinkV2Factory.CreateTableSink(tableID),
+			p.sinkV2DDLSink, p.metricsTableSinkTotalRows)
+	} else {
+		s, err = sink.NewTableSink(p.sink, tableID, p.metricsTableSinkTotalRows)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	tableMemoryQuota := p.tableMemoryQuota
+	if tableMemoryQuota == 0 {
+		tableMemoryQuota = config.GetGlobalServerConfig().PerTableMemoryQuota
 	}
 	table, err := pipeline.NewTableActor(
 		ctx,
@@ -921,6 +1015,9 @@ func (p *processor) createTablePipelineImpl(
 		replicaInfo,
 		s,
 		p.redoManager,
+		tableMemoryQuota,
+		p.memoryQuota,
+		p.rateLimiter,
 		p.changefeed.Info.GetTargetTs())
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1024,6 +1121,22 @@ func (p *processor) Close() error {
 			zap.String("changefeed", p.changefeedID.ID),
 			zap.Duration("duration", time.Since(start)))
 	}
+	if p.sinkV2Factory != nil {
+		if err := p.sinkV2Factory.Close(); err != nil {
+			log.Info("processor close sink factory failed",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.Error(err))
+			return errors.Trace(err)
+		}
+		if err := p.sinkV2DDLSink.Close(); err != nil {
+			log.Info("processor close ddl sink failed",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.Error(err))
+			return errors.Trace(err)
+		}
+	}
 	// mark tables share the same cdcContext with its original table, don't need to cancel
 	failpoint.Inject("processorStopDelay", nil)
 	resolvedTsGauge.DeleteLabelValues(p.changefeedID.Namespace, p.changefeedID.ID)
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/pipeline"
 	"github.com/pingcap/tiflow/cdc/redo"
 	"github.com/pingcap/tiflow/cdc/scheduler"
+	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -818,6 +819,68 @@ func TestUpdateBarrierTs(t *testing.T) {
 	require.Equal(t, tb.barrierTs, uint64(15))
 }
 
+type mockPartitionSink struct {
+	sink.Sink
+	applied func(partitionNums map[string]int32)
+}
+
+func (s *mockPartitionSink) ApplyPartitionNums(partitionNums map[string]int32) {
+	s.applied(partitionNums)
+}
+
+func TestApplyPartitionNums(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	liveness := model.LivenessCaptureAlive
+	p, tester := initProcessor4Test(ctx, t, &liveness)
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 5
+		status.ResolvedTs = 10
+		status.PartitionNums = map[string]int32{"t1": 2}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	p.schemaStorage.(*mockSchemaStorage).resolvedTs = 100
+
+	var applied []map[string]int32
+	var barrierTs model.Ts
+	p.sink = &mockPartitionSink{applied: func(partitionNums map[string]int32) {
+		applied = append(applied, partitionNums)
+		barrierTs = p.tables[model.TableID(1)].(*mockTablePipeline).barrierTs
+	}}
+	done, err := p.AddTable(ctx, model.TableID(1), 5, false)
+	require.True(t, done)
+	require.Nil(t, err)
+	// The first tick creates the task position.
+	for i := 0; i < 2; i++ {
+		_, err = p.Tick(ctx, p.changefeed)
+		require.Nil(t, err)
+		tester.MustApplyPatches()
+	}
+	require.Equal(t, []map[string]int32{{"t1": 2}}, applied)
+
+	// The partition numbers are applied only if they are changed.
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Len(t, applied, 1)
+
+	// The new partition numbers are applied before the events after
+	// the barrier are sent to the sink.
+	p.changefeed.PatchStatus(func(status *model.ChangeFeedStatus) (*model.ChangeFeedStatus, bool, error) {
+		status.CheckpointTs = 10
+		status.ResolvedTs = 20
+		status.PartitionNums = map[string]int32{"t1": 4, "t2": 3}
+		return status, true, nil
+	})
+	tester.MustApplyPatches()
+	_, err = p.Tick(ctx, p.changefeed)
+	require.Nil(t, err)
+	tester.MustApplyPatches()
+	require.Equal(t, []map[string]int32{{"t1": 2}, {"t1": 4, "t2": 3}}, applied)
+	require.Equal(t, uint64(10), barrierTs)
+	require.Equal(t, uint64(20), p.tables[model.TableID(1)].(*mockTablePipeline).barrierTs)
+}
+
 func TestProcessorLiveness(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	liveness := model.LivenessCaptureAlive
@@ -0,0 +1,156 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	ddlfactory "github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/factory"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var _ sink.Sink = (*tableSinkV2)(nil)
+
+// tableSinkV2 adapts a table sink of sinkv2 to the sink used by
+// the table pipeline.
+type tableSinkV2 struct {
+	tableID   model.TableID
+	tableSink tablesink.TableSink
+	ddlSink   ddlsink.DDLEventSink
+
+	metricsTableSinkTotalRows prometheus.Counter
+}
+
+func newTableSinkV2(
+	tableID model.TableID, tableSink tablesink.TableSink,
+	ddlSink ddlsink.DDLEventSink, totalRowsCounter prometheus.Counter,
+) *tableSinkV2 {
+	return &tableSinkV2{
+		tableID:                   tableID,
+		tableSink:                 tableSink,
+		ddlSink:                   ddlSink,
+		metricsTableSinkTotalRows: totalRowsCounter,
+	}
+}
+
+func (t *tableSinkV2) AddTable(tableID model.TableID) error {
+	return nil
+}
+
+func (t *tableSinkV2) EmitRowChangedEvents(
+	ctx context.Context, rows ...*model.RowChangedEvent,
+) error {
+	t.tableSink.AppendRowChangedEvents(rows...)
+	t.metricsTableSinkTotalRows.Add(float64(len(rows)))
+	return nil
+}
+
+// EmitDDLEvent is only used to truncate the table before it is re-synced,
+// the DDL is executed by the DDL sink of sinkv2 synchronously.
+func (t *tableSinkV2) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	return t.ddlSink.WriteDDLEvent(ctx, ddl)
+}
+
+// FlushRowChangedEvents writes the rows whose commitTs is not greater than
+// resolved to the sink asynchronously, and returns the current checkpoint.
+func (t *tableSinkV2) FlushRowChangedEvents(
+	ctx context.Context, tableID model.TableID, resolved model.ResolvedTs,
+) (model.ResolvedTs, error) {
+	if tableID != t.tableID {
+		log.Panic("inconsistent table sink",
+			zap.Int64("tableID", tableID), zap.Int64("sinkTableID", t.tableID))
+	}
+	t.tableSink.UpdateResolvedTs(resolved)
+	return t.tableSink.GetCheckpointTs(), nil
+}
+
+func (t *tableSinkV2) EmitCheckpointTs(_ context.Context, _ uint64, _ []model.TableName) error {
+	// the table sink doesn't receive the checkpoint event
+	return nil
+}
+
+// Close waits for all the events written to the sink to be flushed.
+func (t *tableSinkV2) Close(ctx context.Context) error {
+	return t.tableSink.Close(ctx)
+}
+
+func (t *tableSinkV2) RemoveTable(ctx context.Context, tableID model.TableID) error {
+	return nil
+}
+
+var _ ddlsink.DDLEventSink = (*lazyDDLSinkV2)(nil)
+
+// lazyDDLSinkV2 is the DDL sink of sinkv2 shared by the table sinks of a
+// processor. Only the re-synced tables execute DDLs, so the DDL sink is
+// created when the first DDL is written.
+type lazyDDLSinkV2 struct {
+	// ctx carries the changefeed ID used by the DDL sink.
+	ctx     context.Context
+	sinkURI string
+	cfg     *config.ReplicaConfig
+
+	mu   sync.Mutex
+	sink ddlsink.DDLEventSink
+}
+
+func newLazyDDLSinkV2(
+	ctx context.Context, sinkURI string, cfg *config.ReplicaConfig,
+) *lazyDDLSinkV2 {
+	return &lazyDDLSinkV2{ctx: ctx, sinkURI: sinkURI, cfg: cfg}
+}
+
+func (l *lazyDDLSinkV2) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sink == nil {
+		if !ddlfactory.IsSupported(l.sinkURI) {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"the new sink does not support executing %s", ddl.Query)
+		}
+		s, err := ddlfactory.New(l.ctx, l.sinkURI, l.cfg)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		l.sink = s
+	}
+	return l.sink.WriteDDLEvent(ctx, ddl)
+}
+
+func (l *lazyDDLSinkV2) WriteCheckpointTs(
+	_ context.Context, _ uint64, _ []model.TableName,
+) error {
+	// The checkpoint is written by the DDL sink of the owner.
+	return nil
+}
+
+func (l *lazyDDLSinkV2) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sink == nil {
+		return nil
+	}
+	err := l.sink.Close()
+	l.sink = nil
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDDLSinkV2 struct {
+	ddls   []*model.DDLEvent
+	closed bool
+}
+
+func (m *mockDDLSinkV2) WriteDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	m.ddls = append(m.ddls, ddl)
+	return nil
+}
+
+func (m *mockDDLSinkV2) WriteCheckpointTs(
+	_ context.Context, _ uint64, _ []model.TableName,
+) error {
+	return nil
+}
+
+func (m *mockDDLSinkV2) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestTableSinkV2EmitDDLEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ddl := &model.DDLEvent{Query: "TRUNCATE TABLE `test`.`t`"}
+
+	// The table is truncated by the DDL sink shared by the table sinks.
+	mockSink := &mockDDLSinkV2{}
+	ddlSink := newLazyDDLSinkV2(ctx, "mysql://127.0.0.1:3306/", config.GetDefaultReplicaConfig())
+	ddlSink.sink = mockSink
+	s := newTableSinkV2(1, nil, ddlSink, prometheus.NewCounter(prometheus.CounterOpts{}))
+	require.Nil(t, s.EmitDDLEvent(ctx, ddl))
+	require.Equal(t, []*model.DDLEvent{ddl}, mockSink.ddls)
+	require.Nil(t, ddlSink.Close())
+	require.True(t, mockSink.closed)
+	require.Nil(t, ddlSink.Close())
+
+	// The new kafka sink has no DDL sink, the table can not be truncated.
+	ddlSink = newLazyDDLSinkV2(ctx, "kafka://127.0.0.1:9092/topic", config.GetDefaultReplicaConfig())
+	s = newTableSinkV2(1, nil, ddlSink, prometheus.NewCounter(prometheus.CounterOpts{}))
+	err := s.EmitDDLEvent(ctx, ddl)
+	require.True(t, cerror.ErrSinkInvalidConfig.Equal(err), err)
+	require.Nil(t, ddlSink.Close())
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/puller/frontier"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/pdutil"
 	"github.com/pingcap/tiflow/pkg/regionspan"
@@ -119,6 +120,8 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 
 	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
 	tableID, _ := contextutil.TableIDFromCtx(ctx)
+	// tracker is nil if the puller does not pull a table, e.g. the ddl puller.
+	tracker := tracing.GetTableTracker(changefeedID, tableID)
 	metricOutputChanSize := outputChanSizeHistogram.
 		WithLabelValues(changefeedID.Namespace, changefeedID.ID)
 	metricEventChanSize := eventChanSizeHistogram.
@@ -185,6 +188,7 @@ func (p *pullerImpl) Run(ctx context.Context) error {
 
 			if e.Val != nil {
 				metricTxnCollectCounterKv.Inc()
+				tracker.Observe(tracing.StageKVClient, e.Val.StartTs, e.Val.CRTs)
 				if err := output(e.Val); err != nil {
 					return errors.Trace(err)
 				}
@@ -21,6 +21,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
@@ -90,6 +91,42 @@ func IsS3StorageEnabled(storage string) bool {
 	return consistentStorage(storage) == consistentStorageS3
 }
 
+// ValidateStorage checks whether the consistent storage is writable by
+// writing a test file to it, the file is removed afterwards.
+func ValidateStorage(ctx context.Context, cfg *config.ConsistentConfig) error {
+	if cfg == nil || !IsConsistentEnabled(cfg.Level) {
+		return nil
+	}
+	uri, err := storage.ParseRawURL(cfg.Storage)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrConsistentStorage, err)
+	}
+
+	var extStorage storage.ExternalStorage
+	switch consistentStorage(uri.Scheme) {
+	case consistentStorageBlackhole:
+		return nil
+	case consistentStorageLocal, consistentStorageNFS:
+		extStorage, err = storage.NewLocalStorage(uri.Path)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrRedoFileOp, err)
+		}
+	case consistentStorageS3:
+		extStorage, err = common.InitS3storage(ctx, *uri)
+		if err != nil {
+			return err
+		}
+	default:
+		return cerror.ErrConsistentStorage.GenWithStackByArgs(uri.Scheme)
+	}
+
+	name := "write_test_" + uuid.New().String()
+	if err := extStorage.WriteFile(ctx, name, []byte("test")); err != nil {
+		return cerror.WrapError(cerror.ErrRedoFileOp, err)
+	}
+	return cerror.WrapError(cerror.ErrRedoFileOp, extStorage.DeleteFile(ctx, name))
+}
+
 // LogManager defines an interface that is used to manage redo log
 type LogManager interface {
 	// Enabled returns whether the log manager is enabled
@@ -17,6 +17,7 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -25,6 +26,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/redo/writer"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -85,6 +87,32 @@ func TestConsistentConfig(t *testing.T) {
 	}
 }
 
+func TestValidateStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	require.Nil(t, ValidateStorage(ctx, nil))
+	require.Nil(t, ValidateStorage(ctx, &config.ConsistentConfig{
+		Level: string(ConsistentLevelNone), Storage: "invalid://",
+	}))
+	require.Nil(t, ValidateStorage(ctx, &config.ConsistentConfig{
+		Level: string(ConsistentLevelEventual), Storage: "blackhole://",
+	}))
+	err := ValidateStorage(ctx, &config.ConsistentConfig{
+		Level: string(ConsistentLevelEventual), Storage: "invalid://",
+	})
+	require.True(t, cerror.ErrConsistentStorage.Equal(err))
+
+	// the test file is removed after validation
+	dir := t.TempDir()
+	require.Nil(t, ValidateStorage(ctx, &config.ConsistentConfig{
+		Level: string(ConsistentLevelEventual), Storage: "local://" + dir,
+	}))
+	files, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 0)
+}
+
 // TestLogManagerInProcessor tests how redo log manager is used in processor,
 // where the redo log manager needs to handle DMLs and redo log meta data
 func TestLogManagerInProcessor(t *testing.T) {
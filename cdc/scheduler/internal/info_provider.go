@@ -42,4 +42,8 @@ type InfoProvider interface {
 	// TODO: Remove the API, as the only usage is metrics which
 	//       has already exported in the scheduler package.
 	GetPendingTableCounts() map[model.CaptureID]int
+
+	// GetTableReplicationStates returns the replication states of all tables,
+	// ordered by table ID.
+	GetTableReplicationStates() []*model.TableReplicationState
 }
@@ -48,6 +48,12 @@ type Scheduler interface {
 	// It is thread-safe.
 	MoveTable(tableID model.TableID, target model.CaptureID)
 
+	// ResyncTables requests that tables be removed and added back at the
+	// latest checkpoint, so the tables are replicated from a new snapshot.
+	// Either all the tables are accepted, or none of them is.
+	// It is thread-safe.
+	ResyncTables(tableIDs []model.TableID) error
+
 	// Rebalance triggers a rebalance operation.
 	// It is thread-safe
 	Rebalance()
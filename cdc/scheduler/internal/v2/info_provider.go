@@ -14,6 +14,8 @@
 package v2
 
 import (
+	"sort"
+
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v2/util"
 )
@@ -110,3 +112,33 @@ func (s *ScheduleDispatcher) GetPendingTableCounts() map[model.CaptureID]int {
 	}
 	return ret
 }
+
+// GetTableReplicationStates implements InfoProvider for BaseScheduleDispatcher.
+// The checkpoint and resolved ts of tables are not maintained.
+// Complexity Note: This function has O(#tables) cost. USE WITH CARE.
+func (s *ScheduleDispatcher) GetTableReplicationStates() []*model.TableReplicationState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tables := s.tables.GetAllTables()
+	states := make([]*model.TableReplicationState, 0, len(tables))
+	for tableID, record := range tables {
+		state := &model.TableReplicationState{
+			TableID: tableID,
+			Primary: record.CaptureID,
+		}
+		switch record.Status {
+		case util.AddingTable:
+			state.State = "Adding"
+		case util.RemovingTable:
+			state.State = "Removing"
+		case util.RunningTable:
+			state.State = "Replicating"
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].TableID < states[j].TableID
+	})
+	return states
+}
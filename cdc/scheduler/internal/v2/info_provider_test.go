@@ -132,3 +132,16 @@ func TestInfoProviderTableCounts(t *testing.T) {
 		"capture-2": 1,
 	}, dispatcher.GetPendingTableCounts())
 }
+
+func TestInfoProviderTableReplicationStates(t *testing.T) {
+	dispatcher := NewBaseScheduleDispatcher(model.DefaultChangeFeedID("cf-1"), nil, 1300)
+	injectSchedulerStateForInfoProviderTest(dispatcher)
+
+	require.Equal(t, []*model.TableReplicationState{
+		{TableID: 1, State: "Replicating", Primary: "capture-1"},
+		{TableID: 2, State: "Replicating", Primary: "capture-2"},
+		{TableID: 3, State: "Replicating", Primary: "capture-1"},
+		{TableID: 4, State: "Adding", Primary: "capture-2"},
+		{TableID: 5, State: "Removing", Primary: "capture-1"},
+	}, dispatcher.GetTableReplicationStates())
+}
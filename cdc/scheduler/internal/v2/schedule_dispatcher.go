@@ -24,6 +24,7 @@ import (
 	sched "github.com/pingcap/tiflow/cdc/scheduler/internal"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v2/protocol"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v2/util"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -482,6 +483,12 @@ func (s *ScheduleDispatcher) handleMoveTableJobs(ctx context.Context) (bool, err
 	return removeAllDone, nil
 }
 
+// ResyncTables implements the interface ScheduleDispatcher.
+func (s *ScheduleDispatcher) ResyncTables(tableIDs []model.TableID) error {
+	return cerror.ErrSchedulerRequestFailed.
+		GenWithStack("resync table is only supported by the scheduler v3")
+}
+
 // Rebalance implements the interface ScheduleDispatcher.
 func (s *ScheduleDispatcher) Rebalance() {
 	s.needRebalance = true
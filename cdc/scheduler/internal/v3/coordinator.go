@@ -52,6 +52,12 @@ type coordinator struct {
 
 	lastCollectTime time.Time
 	changefeedID    model.ChangeFeedID
+
+	// reloadedConfig is set when the server config is reloaded, and it is
+	// applied in the next tick.
+	reloadedConfigMu     sync.Mutex
+	reloadedConfig       *config.SchedulerConfig
+	unregisterReloadHook func()
 }
 
 // NewCoordinator returns a two phase scheduler.
@@ -71,6 +77,12 @@ func NewCoordinator(
 	}
 	coord := newCoordinator(captureID, changefeedID, ownerRevision, cfg)
 	coord.trans = trans
+	coord.unregisterReloadHook = config.OnServerConfigReloaded(
+		func(serverCfg *config.ServerConfig) {
+			coord.reloadedConfigMu.Lock()
+			defer coord.reloadedConfigMu.Unlock()
+			coord.reloadedConfig = serverCfg.Debug.Scheduler
+		})
 	return coord, nil
 }
 
@@ -106,6 +118,7 @@ func (c *coordinator) Tick(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.applyReloadedConfig()
 	return c.poll(ctx, checkpointTs, currentTables, aliveCaptures)
 }
 
@@ -127,6 +140,33 @@ func (c *coordinator) MoveTable(tableID model.TableID, target model.CaptureID) {
 	c.schedulerM.MoveTable(tableID, target)
 }
 
+// ResyncTables implement the scheduler interface
+func (c *coordinator) ResyncTables(tableIDs []model.TableID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.captureM.CheckAllCaptureInitialized() {
+		log.Info("schedulerv3: resync table request ignored, "+
+			"since not all captures initialized",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Int64s("tableIDs", tableIDs))
+		return cerror.ErrSchedulerRequestFailed.
+			GenWithStack("not all captures initialized")
+	}
+
+	if !c.schedulerM.ResyncTables(tableIDs) {
+		log.Info("schedulerv3: resync table request ignored, "+
+			"since the last triggered task not finished",
+			zap.String("namespace", c.changefeedID.Namespace),
+			zap.String("changefeed", c.changefeedID.ID),
+			zap.Int64s("tableIDs", tableIDs))
+		return cerror.ErrSchedulerRequestFailed.
+			GenWithStack("the table is being re-synced")
+	}
+	return nil
+}
+
 // Rebalance implement the scheduler interface
 func (c *coordinator) Rebalance() {
 	c.mu.Lock()
@@ -215,6 +255,9 @@ func (c *coordinator) Close(ctx context.Context) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.unregisterReloadHook != nil {
+		c.unregisterReloadHook()
+	}
 	_ = c.trans.Close()
 	c.captureM.CleanMetrics()
 	c.replicationM.CleanMetrics()
@@ -228,6 +271,24 @@ func (c *coordinator) Close(ctx context.Context) {
 
 // ===========
 
+func (c *coordinator) applyReloadedConfig() {
+	c.reloadedConfigMu.Lock()
+	cfg := c.reloadedConfig
+	c.reloadedConfig = nil
+	c.reloadedConfigMu.Unlock()
+	if cfg == nil {
+		return
+	}
+
+	c.replicationM.maxTaskConcurrency = cfg.MaxTaskConcurrency
+	c.captureM.heartbeatTick = cfg.HeartbeatTick
+	c.schedulerM.UpdateConfig(cfg)
+	log.Info("schedulerv3: scheduler config reloaded",
+		zap.String("namespace", c.changefeedID.Namespace),
+		zap.String("changefeed", c.changefeedID.ID),
+		zap.Any("config", cfg))
+}
+
 func (c *coordinator) poll(
 	ctx context.Context, checkpointTs model.Ts, currentTables []model.TableID,
 	aliveCaptures map[model.CaptureID]*model.CaptureInfo,
@@ -17,6 +17,7 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal/v3/schedulepb"
@@ -343,3 +344,27 @@ func TestCoordinatorDrainCapture(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 }
+
+func TestCoordinatorApplyReloadedConfig(t *testing.T) {
+	t.Parallel()
+
+	coord := newCoordinator("a", model.ChangeFeedID{}, 1, config.NewDefaultSchedulerConfig())
+	coord.trans = &mockTrans{}
+
+	coord.reloadedConfig = &config.SchedulerConfig{
+		HeartbeatTick:        5,
+		MaxTaskConcurrency:   3,
+		CheckBalanceInterval: config.TomlDuration(time.Second),
+	}
+	_, _, err := coord.Tick(context.Background(), 0, nil, nil)
+	require.Nil(t, err)
+	require.Nil(t, coord.reloadedConfig)
+	require.Equal(t, 5, coord.captureM.heartbeatTick)
+	require.Equal(t, 3, coord.replicationM.maxTaskConcurrency)
+	require.Equal(t, 3, coord.schedulerM.maxTaskConcurrency)
+	balance := coord.schedulerM.schedulers[schedulerPriorityBalance].(*balanceScheduler)
+	require.Equal(t, 3, balance.maxTaskConcurrency)
+	require.Equal(t, time.Second, balance.checkBalanceInterval)
+	drain := coord.schedulerM.schedulers[schedulerPriorityDrainCapture].(*drainCaptureScheduler)
+	require.Equal(t, 3, drain.maxTaskConcurrency)
+}
@@ -14,6 +14,8 @@
 package v3
 
 import (
+	"sort"
+
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/scheduler/internal"
 )
@@ -71,3 +73,26 @@ func (c *coordinator) GetTotalTableCounts() map[model.CaptureID]int {
 func (c *coordinator) GetPendingTableCounts() map[model.CaptureID]int {
 	return make(map[model.CaptureID]int)
 }
+
+// GetTableReplicationStates returns the replication states of all tables,
+// ordered by table ID.
+func (c *coordinator) GetTableReplicationStates() []*model.TableReplicationState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make([]*model.TableReplicationState, 0, len(c.replicationM.tables))
+	for tableID, rep := range c.replicationM.tables {
+		states = append(states, &model.TableReplicationState{
+			TableID:      tableID,
+			State:        rep.State.String(),
+			Primary:      rep.Primary,
+			Secondary:    rep.Secondary,
+			CheckpointTs: rep.Checkpoint.CheckpointTs,
+			ResolvedTs:   rep.Checkpoint.ResolvedTs,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].TableID < states[j].TableID
+	})
+	return states
+}
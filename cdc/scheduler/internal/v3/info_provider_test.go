@@ -58,4 +58,17 @@ func TestInfoProvider(t *testing.T) {
 	require.Len(t, pos, 2)
 	require.Len(t, ip.GetTotalTableCounts(), 2)
 	require.Empty(t, ip.GetPendingTableCounts())
+
+	coord.replicationM.tables[2] = &ReplicationSet{
+		TableID: 2, State: ReplicationSetStatePrepare, Primary: "a", Secondary: "b",
+		Checkpoint: schedulepb.Checkpoint{CheckpointTs: 2, ResolvedTs: 3},
+	}
+	coord.replicationM.tables[1] = &ReplicationSet{
+		TableID: 1, State: ReplicationSetStateReplicating, Primary: "a",
+		Checkpoint: schedulepb.Checkpoint{CheckpointTs: 1, ResolvedTs: 4},
+	}
+	require.Equal(t, []*model.TableReplicationState{
+		{TableID: 1, State: "Replicating", Primary: "a", CheckpointTs: 1, ResolvedTs: 4},
+		{TableID: 2, State: "Prepare", Primary: "a", Secondary: "b", CheckpointTs: 2, ResolvedTs: 3},
+	}, ip.GetTableReplicationStates())
 }
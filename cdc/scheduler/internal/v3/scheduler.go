@@ -36,6 +36,7 @@ const (
 	// schedulerPriorityDrainCapture has higher priority than other schedulers.
 	schedulerPriorityDrainCapture
 	schedulerPriorityMoveTable
+	schedulerPriorityResyncTable
 	schedulerPriorityRebalance
 	schedulerPriorityBalance
 	schedulerPriorityMax
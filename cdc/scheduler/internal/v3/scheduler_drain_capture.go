@@ -79,6 +79,12 @@ func checkStoppingCapture(captures map[model.CaptureID]*CaptureStatus) model.Cap
 	return captureIDNotDraining
 }
 
+func (d *drainCaptureScheduler) setMaxTaskConcurrency(concurrency int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxTaskConcurrency = concurrency
+}
+
 func (d *drainCaptureScheduler) Schedule(
 	_ model.Ts,
 	_ []model.TableID,
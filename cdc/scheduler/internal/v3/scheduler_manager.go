@@ -50,6 +50,7 @@ func newSchedulerManager(
 	sm.schedulers[schedulerPriorityBalance] = newBalanceScheduler(
 		time.Duration(cfg.CheckBalanceInterval), cfg.MaxTaskConcurrency)
 	sm.schedulers[schedulerPriorityMoveTable] = newMoveTableScheduler(changefeedID)
+	sm.schedulers[schedulerPriorityResyncTable] = newResyncTableScheduler(changefeedID)
 	sm.schedulers[schedulerPriorityRebalance] = newRebalanceScheduler(changefeedID)
 
 	return sm
@@ -92,6 +93,29 @@ func (sm *schedulerManager) Schedule(
 	return nil
 }
 
+func (sm *schedulerManager) UpdateConfig(cfg *config.SchedulerConfig) {
+	sm.maxTaskConcurrency = cfg.MaxTaskConcurrency
+
+	scheduler := sm.schedulers[schedulerPriorityDrainCapture]
+	drainCaptureScheduler, ok := scheduler.(*drainCaptureScheduler)
+	if !ok {
+		log.Panic("schedulerv3: invalid drain capture scheduler found",
+			zap.String("namespace", sm.changefeedID.Namespace),
+			zap.String("changefeed", sm.changefeedID.ID))
+	}
+	drainCaptureScheduler.setMaxTaskConcurrency(cfg.MaxTaskConcurrency)
+
+	scheduler = sm.schedulers[schedulerPriorityBalance]
+	balanceScheduler, ok := scheduler.(*balanceScheduler)
+	if !ok {
+		log.Panic("schedulerv3: invalid balance scheduler found",
+			zap.String("namespace", sm.changefeedID.Namespace),
+			zap.String("changefeed", sm.changefeedID.ID))
+	}
+	balanceScheduler.maxTaskConcurrency = cfg.MaxTaskConcurrency
+	balanceScheduler.checkBalanceInterval = time.Duration(cfg.CheckBalanceInterval)
+}
+
 func (sm *schedulerManager) MoveTable(tableID model.TableID, target model.CaptureID) {
 	scheduler := sm.schedulers[schedulerPriorityMoveTable]
 	moveTableScheduler, ok := scheduler.(*moveTableScheduler)
@@ -110,6 +134,17 @@ func (sm *schedulerManager) MoveTable(tableID model.TableID, target model.Captur
 	}
 }
 
+func (sm *schedulerManager) ResyncTables(tableIDs []model.TableID) bool {
+	scheduler := sm.schedulers[schedulerPriorityResyncTable]
+	resyncTableScheduler, ok := scheduler.(*resyncTableScheduler)
+	if !ok {
+		log.Panic("schedulerv3: invalid resync table scheduler found",
+			zap.String("namespace", sm.changefeedID.Namespace),
+			zap.String("changefeed", sm.changefeedID.ID))
+	}
+	return resyncTableScheduler.addTasks(tableIDs...)
+}
+
 func (sm *schedulerManager) Rebalance() {
 	scheduler := sm.schedulers[schedulerPriorityRebalance]
 	rebalanceScheduler, ok := scheduler.(*rebalanceScheduler)
@@ -0,0 +1,113 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"go.uber.org/zap"
+)
+
+var _ scheduler = &resyncTableScheduler{}
+
+// resyncTableScheduler removes the tables which are requested to be
+// re-synced, the removed tables are added back by the basic scheduler
+// at the latest checkpoint ts of the changefeed.
+type resyncTableScheduler struct {
+	mu    sync.Mutex
+	tasks map[model.TableID]*scheduleTask
+
+	changefeedID model.ChangeFeedID
+}
+
+func newResyncTableScheduler(changefeed model.ChangeFeedID) *resyncTableScheduler {
+	return &resyncTableScheduler{
+		tasks:        make(map[model.TableID]*scheduleTask),
+		changefeedID: changefeed,
+	}
+}
+
+func (r *resyncTableScheduler) Name() string {
+	return "resync-table-scheduler"
+}
+
+// addTasks adds the resync tasks of the tables, it declines all of them if
+// the previous triggered task of any table is not accepted yet.
+func (r *resyncTableScheduler) addTasks(tableIDs ...model.TableID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tableID := range tableIDs {
+		if _, ok := r.tasks[tableID]; ok {
+			return false
+		}
+	}
+	for _, tableID := range tableIDs {
+		tableID := tableID
+		r.tasks[tableID] = &scheduleTask{
+			removeTable: &removeTable{TableID: tableID},
+			accept: func() {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				delete(r.tasks, tableID)
+			},
+		}
+	}
+	return true
+}
+
+func (r *resyncTableScheduler) Schedule(
+	_ model.Ts,
+	currentTables []model.TableID,
+	captures map[model.CaptureID]*CaptureStatus,
+	replications map[model.TableID]*ReplicationSet,
+) []*scheduleTask {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]*scheduleTask, 0)
+	if len(r.tasks) == 0 {
+		return result
+	}
+
+	allTables := newTableSet()
+	for _, tableID := range currentTables {
+		allTables.add(tableID)
+	}
+
+	for tableID, task := range r.tasks {
+		// table may not in the all current tables
+		// if it was dropped after resync table triggered.
+		if !allTables.contain(tableID) {
+			log.Warn("schedulerv3: resync table ignored, since the table cannot found",
+				zap.String("namespace", r.changefeedID.Namespace),
+				zap.String("changefeed", r.changefeedID.ID),
+				zap.Int64("tableID", tableID))
+			delete(r.tasks, tableID)
+			continue
+		}
+
+		// Unlike moving a table, the task is kept until the table is
+		// replicating, so a requested resync is never lost.
+		rep, ok := replications[tableID]
+		if !ok || rep.State != ReplicationSetStateReplicating {
+			continue
+		}
+		task.removeTable.CaptureID = rep.Primary
+		result = append(result, task)
+	}
+
+	return result
+}
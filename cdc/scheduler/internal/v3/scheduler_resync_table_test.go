@@ -0,0 +1,80 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerResyncTable(t *testing.T) {
+	t.Parallel()
+
+	var checkpointTs model.Ts
+	captures := map[model.CaptureID]*CaptureStatus{"a": {
+		State: CaptureStateInitialized,
+	}}
+	currentTables := []model.TableID{1, 2, 3}
+
+	replications := map[model.TableID]*ReplicationSet{
+		1: {State: ReplicationSetStateReplicating, Primary: "a"},
+		2: {State: ReplicationSetStatePrepare, Primary: "a"},
+	}
+
+	scheduler := newResyncTableScheduler(model.ChangeFeedID{})
+	require.Equal(t, "resync-table-scheduler", scheduler.Name())
+
+	tasks := scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+
+	// resync a not exist table
+	require.True(t, scheduler.addTasks(model.TableID(4)))
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.NotContains(t, scheduler.tasks, model.TableID(4))
+
+	// the table is not replicating yet, the task is kept.
+	require.True(t, scheduler.addTasks(model.TableID(2)))
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.Contains(t, scheduler.tasks, model.TableID(2))
+
+	// the table is not in the replication set, the task is kept.
+	require.True(t, scheduler.addTasks(model.TableID(3)))
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 0)
+	require.Contains(t, scheduler.tasks, model.TableID(3))
+
+	// duplicate request is declined.
+	require.True(t, scheduler.addTasks(model.TableID(1)))
+	require.False(t, scheduler.addTasks(model.TableID(1)))
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, 1, tasks[0].removeTable.TableID)
+	require.EqualValues(t, "a", tasks[0].removeTable.CaptureID)
+	tasks[0].accept()
+	require.NotContains(t, scheduler.tasks, model.TableID(1))
+
+	// the table becomes replicating.
+	replications[2].State = ReplicationSetStateReplicating
+	tasks = scheduler.Schedule(checkpointTs, currentTables, captures, replications)
+	require.Len(t, tasks, 1)
+	require.EqualValues(t, 2, tasks[0].removeTable.TableID)
+
+	// none of the tables is accepted if any of them is being re-synced.
+	require.False(t, scheduler.addTasks(1, 2))
+	require.NotContains(t, scheduler.tasks, model.TableID(1))
+}
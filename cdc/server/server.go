@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/capture"
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/sorter/unified"
+	"github.com/pingcap/tiflow/cdc/tracing"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/etcd"
@@ -178,6 +179,13 @@ func (s *server) Run(ctx context.Context) error {
 		return errors.Trace(err)
 	}
 
+	closeTracer, err := tracing.InitTracer(conf.Tracing,
+		config.GetGlobalServerConfig().DataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer closeTracer()
+
 	kv.InitWorkerPool()
 
 	s.capture = capture.NewCapture(s.pdEndpoints, s.etcdClient, s.grpcService)
@@ -351,11 +359,17 @@ func (s *server) initDir(ctx context.Context) error {
 		"It is recommended that the disk for data-dir at least have %dGB available space",
 		conf.DataDir, diskInfo.Avail, conf.Sorter.SortDir, dataDirThreshold))
 
-	// Ensure sorter dir exists and read-writable.
+	// Ensure sorter dirs exist and read-writable.
 	_, err = checkDir(conf.Sorter.SortDir)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	for _, dir := range conf.Sorter.ExtraSortDirs {
+		_, err = checkDir(dir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
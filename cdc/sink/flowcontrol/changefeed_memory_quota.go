@@ -0,0 +1,170 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ChangefeedMemoryQuota is a memory quota shared by all the TableFlowControllers
+// of a changefeed on one capture.
+//
+// The sink of a table can only flush events up to the global resolved ts, which
+// is held back by the slowest table, so blocking a table on a quota filled by
+// other tables may lead to a deadlock. To avoid it, a table whose consumption is
+// below its fair share of the quota is never blocked by the changefeed quota.
+type ChangefeedMemoryQuota struct {
+	quota uint64 // should not be changed once initialized
+
+	mu         sync.Mutex
+	consumed   uint64
+	tableCount uint64
+	cond       *sync.Cond
+}
+
+// tableQuotaHandle records the consumption of a table in a ChangefeedMemoryQuota,
+// all the fields are protected by ChangefeedMemoryQuota.mu.
+type tableQuotaHandle struct {
+	consumed uint64
+	detached bool
+}
+
+// NewChangefeedMemoryQuota creates a new ChangefeedMemoryQuota
+// quota: max advised memory consumption in bytes of the changefeed.
+func NewChangefeedMemoryQuota(quota uint64) *ChangefeedMemoryQuota {
+	ret := &ChangefeedMemoryQuota{
+		quota: quota,
+	}
+	ret.cond = sync.NewCond(&ret.mu)
+	return ret
+}
+
+// attach registers a table to the quota.
+func (c *ChangefeedMemoryQuota) attach() *tableQuotaHandle {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tableCount++
+	return &tableQuotaHandle{}
+}
+
+// detach unregisters a table and releases all memory it consumed.
+func (c *ChangefeedMemoryQuota) detach(h *tableQuotaHandle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h.detached {
+		return
+	}
+	h.detached = true
+	c.tableCount--
+	c.consumed -= h.consumed
+	h.consumed = 0
+	c.cond.Broadcast()
+}
+
+// fairShare should be called only if c.mu is locked.
+func (c *ChangefeedMemoryQuota) fairShare() uint64 {
+	if c.tableCount == 0 {
+		return c.quota
+	}
+	return c.quota / c.tableCount
+}
+
+// consumeWithBlocking blocks until the table can consume nBytes, that is either
+// the changefeed has enough quota left or the table is within its fair share.
+// blockCallBack will be called if the function will block.
+func (c *ChangefeedMemoryQuota) consumeWithBlocking(
+	h *tableQuotaHandle, nBytes uint64, blockCallBack func() error,
+) error {
+	if nBytes >= c.quota {
+		return cerrors.ErrFlowControllerEventLargerThanQuota.GenWithStackByArgs(nBytes, c.quota)
+	}
+
+	c.mu.Lock()
+	if !c.hasRoom(h, nBytes) {
+		c.mu.Unlock()
+		if err := blockCallBack(); err != nil {
+			return errors.Trace(err)
+		}
+		c.mu.Lock()
+	}
+	defer c.mu.Unlock()
+
+	for {
+		if h.detached {
+			return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
+		}
+		if c.hasRoom(h, nBytes) {
+			break
+		}
+		c.cond.Wait()
+	}
+
+	c.consumed += nBytes
+	h.consumed += nBytes
+	return nil
+}
+
+// hasRoom should be called only if c.mu is locked.
+func (c *ChangefeedMemoryQuota) hasRoom(h *tableQuotaHandle, nBytes uint64) bool {
+	return c.consumed+nBytes < c.quota || h.consumed+nBytes <= c.fairShare()
+}
+
+// hasRoomFor returns whether the table can consume nBytes without blocking.
+func (c *ChangefeedMemoryQuota) hasRoomFor(h *tableQuotaHandle, nBytes uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return h.detached || c.hasRoom(h, nBytes)
+}
+
+// forceConsume records the memory consumption without blocking.
+func (c *ChangefeedMemoryQuota) forceConsume(h *tableQuotaHandle, nBytes uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h.detached {
+		return cerrors.ErrFlowControllerAborted.GenWithStackByArgs()
+	}
+	c.consumed += nBytes
+	h.consumed += nBytes
+	return nil
+}
+
+// release is called when a chuck of memory of the table is done being used.
+func (c *ChangefeedMemoryQuota) release(h *tableQuotaHandle, nBytes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h.detached || nBytes == 0 {
+		return
+	}
+	if h.consumed < nBytes {
+		log.Panic("ChangefeedMemoryQuota: releasing more than consumed, report a bug",
+			zap.Uint64("consumed", h.consumed),
+			zap.Uint64("released", nBytes))
+	}
+	h.consumed -= nBytes
+	c.consumed -= nBytes
+	// Wake up all waiters, since the fair share of each table is different.
+	c.cond.Broadcast()
+}
+
+// GetConsumption returns the current memory consumption of the changefeed.
+func (c *ChangefeedMemoryQuota) GetConsumption() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.consumed
+}
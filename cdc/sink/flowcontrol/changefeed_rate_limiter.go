@@ -0,0 +1,173 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	filter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerrors "github.com/pingcap/tiflow/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// ChangefeedRateLimiter limits the throughput of the rows sent to the sink,
+// it is shared by all the tables of a changefeed on one capture.
+//
+// The limits are enforced by token buckets, the burst of a bucket is the
+// limit of one second, so a changefeed catching up with a big backlog is
+// smoothed to the configured rate instead of saturating the downstream.
+//
+// The limiter is not called by the sink itself, it's enforced in the sorter
+// node of each table, where the rows are read from the sorter and sent to the
+// sink node. So the throttled rows stay in the sorter instead of the memory
+// of the sink, and the rows sent to the sink are written at the sink's speed.
+type ChangefeedRateLimiter struct {
+	rows  *rate.Limiter
+	bytes *rate.Limiter
+
+	tableRules []*tableRateLimitRule
+}
+
+type tableRateLimitRule struct {
+	filter filter.Filter
+	rows   *rate.Limiter
+	bytes  *rate.Limiter
+}
+
+// NewChangefeedRateLimiter creates a new ChangefeedRateLimiter, it returns nil
+// if no limit is configured.
+func NewChangefeedRateLimiter(
+	cfg *config.RateLimitConfig, caseSensitive bool,
+) (*ChangefeedRateLimiter, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	l := &ChangefeedRateLimiter{
+		rows:  newLimiter(cfg.MaxRowsPerSecond),
+		bytes: newLimiter(cfg.MaxBytesPerSecond),
+	}
+	for _, rule := range cfg.Tables {
+		f, err := filter.Parse(rule.Matcher)
+		if err != nil {
+			return nil, cerrors.WrapError(cerrors.ErrFilterRuleInvalid, err, rule.Matcher)
+		}
+		if !caseSensitive {
+			f = filter.CaseInsensitive(f)
+		}
+		l.tableRules = append(l.tableRules, &tableRateLimitRule{
+			filter: f,
+			rows:   newLimiter(rule.MaxRowsPerSecond),
+			bytes:  newLimiter(rule.MaxBytesPerSecond),
+		})
+	}
+	return l, nil
+}
+
+// newLimiter returns nil if the limit is 0, which means unlimited.
+func newLimiter(limit uint64) *rate.Limiter {
+	if limit == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(limit), int(limit))
+}
+
+// ForTable returns the limiter of the table, which consists of the
+// changefeed limits and the limits of the first matched table rule.
+func (l *ChangefeedRateLimiter) ForTable(schema, table string) *TableRateLimiter {
+	if l == nil {
+		return nil
+	}
+	t := &TableRateLimiter{}
+	t.appendLimiters(l.rows, l.bytes)
+	for _, rule := range l.tableRules {
+		if rule.filter.MatchTable(schema, table) {
+			t.appendLimiters(rule.rows, rule.bytes)
+			break
+		}
+	}
+	return t
+}
+
+// TableRateLimiter limits the throughput of the rows of a table.
+type TableRateLimiter struct {
+	rows  []*rate.Limiter
+	bytes []*rate.Limiter
+}
+
+func (t *TableRateLimiter) appendLimiters(rows, bytes *rate.Limiter) {
+	if rows != nil {
+		t.rows = append(t.rows, rows)
+	}
+	if bytes != nil {
+		t.bytes = append(t.bytes, bytes)
+	}
+}
+
+// Wait blocks until nRows rows of nBytes bytes can be written to the sink
+// without exceeding the limits, or the context is canceled.
+// blockCallBack will be called if the function will block.
+func (t *TableRateLimiter) Wait(
+	ctx context.Context, nRows, nBytes int, blockCallBack func() error,
+) error {
+	if t == nil {
+		return nil
+	}
+	for _, l := range t.rows {
+		if err := waitN(ctx, l, nRows, &blockCallBack); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, l := range t.bytes {
+		if err := waitN(ctx, l, nBytes, &blockCallBack); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// waitN consumes n tokens from the limiter, n may be larger than the burst.
+// The blockCallBack is called at most once, and it's reset after being called.
+func waitN(
+	ctx context.Context, l *rate.Limiter, n int, blockCallBack *func() error,
+) error {
+	for n > 0 {
+		batch := n
+		if batch > l.Burst() {
+			batch = l.Burst()
+		}
+		r := l.ReserveN(time.Now(), batch)
+		if delay := r.Delay(); delay > 0 {
+			if *blockCallBack != nil {
+				if err := (*blockCallBack)(); err != nil {
+					r.Cancel()
+					return errors.Trace(err)
+				}
+				*blockCallBack = nil
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				r.Cancel()
+				return errors.Trace(ctx.Err())
+			case <-timer.C:
+			}
+		}
+		n -= batch
+	}
+	return nil
+}
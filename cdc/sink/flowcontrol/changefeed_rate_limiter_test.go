@@ -0,0 +1,112 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedRateLimiterDisabled(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewChangefeedRateLimiter(nil, true)
+	require.Nil(t, err)
+	require.Nil(t, l)
+	tl := l.ForTable("test", "t1")
+	require.Nil(t, tl)
+	require.Nil(t, tl.Wait(context.Background(), 100, 1<<20, nil))
+}
+
+func TestChangefeedRateLimiterForTable(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewChangefeedRateLimiter(&config.RateLimitConfig{
+		MaxRowsPerSecond: 1000,
+		Tables: []*config.TableRateLimit{
+			{Matcher: []string{"test.BIG_*"}, MaxRowsPerSecond: 10, MaxBytesPerSecond: 1 << 20},
+			{Matcher: []string{"test.*"}, MaxRowsPerSecond: 100},
+		},
+	}, false)
+	require.Nil(t, err)
+
+	tl := l.ForTable("test", "big_t1")
+	require.Len(t, tl.rows, 2)
+	require.Len(t, tl.bytes, 1)
+	// The tables matched by a rule share its limits.
+	require.Same(t, tl.rows[1], l.ForTable("test", "big_t2").rows[1])
+
+	tl = l.ForTable("test", "t1")
+	require.Len(t, tl.rows, 2)
+	require.Empty(t, tl.bytes)
+
+	tl = l.ForTable("other", "t1")
+	require.Len(t, tl.rows, 1)
+	require.Empty(t, tl.bytes)
+
+	_, err = NewChangefeedRateLimiter(&config.RateLimitConfig{
+		Tables: []*config.TableRateLimit{{Matcher: []string{"["}, MaxRowsPerSecond: 1}},
+	}, true)
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+}
+
+func TestTableRateLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewChangefeedRateLimiter(&config.RateLimitConfig{
+		MaxRowsPerSecond:  20,
+		MaxBytesPerSecond: 1000,
+	}, true)
+	require.Nil(t, err)
+	tl := l.ForTable("test", "t1")
+	ctx := context.Background()
+	blocked := 0
+	blockCallBack := func() error {
+		blocked++
+		return nil
+	}
+
+	// The burst is consumed immediately.
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		require.Nil(t, tl.Wait(ctx, 1, 10, blockCallBack))
+	}
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Equal(t, 0, blocked)
+
+	// The rows more than the burst are throttled, and the
+	// callback is called once before blocking.
+	start = time.Now()
+	require.Nil(t, tl.Wait(ctx, 30, 10, blockCallBack))
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	require.Equal(t, 1, blocked)
+
+	// A row larger than the burst of bytes is allowed.
+	require.Nil(t, tl.Wait(ctx, 0, 1500, nil))
+
+	// The waiting is canceled with the context.
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, tl.Wait(ctx, 20, 0, nil), context.DeadlineExceeded)
+
+	// The error of the callback is returned.
+	require.Regexp(t, "blocked", tl.Wait(context.Background(), 20, 0, func() error {
+		return errors.New("blocked")
+	}))
+}
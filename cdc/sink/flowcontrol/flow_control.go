@@ -35,6 +35,10 @@ type TableFlowController struct {
 	memoryQuota  *tableMemoryQuota
 	lastCommitTs uint64
 
+	// changefeedQuota is optional, it is shared by all tables of the changefeed.
+	changefeedQuota *ChangefeedMemoryQuota
+	quotaHandle     *tableQuotaHandle
+
 	queueMu struct {
 		sync.Mutex
 		queue deque.Deque
@@ -64,10 +68,15 @@ type txnSizeEntry struct {
 	batchID  uint64
 }
 
-// NewTableFlowController creates a new TableFlowController
-func NewTableFlowController(quota uint64, redoLogEnabled bool, splitTxn bool) *TableFlowController {
+// NewTableFlowController creates a new TableFlowController.
+// changefeedQuota can be nil if the changefeed has no memory quota.
+func NewTableFlowController(
+	quota uint64, changefeedQuota *ChangefeedMemoryQuota,
+	redoLogEnabled bool, splitTxn bool,
+) *TableFlowController {
 	log.Info("create table flow controller",
 		zap.Uint64("quota", quota),
+		zap.Bool("changefeedQuotaEnabled", changefeedQuota != nil),
 		zap.Bool("redoLogEnabled", redoLogEnabled),
 		zap.Bool("splitTxn", splitTxn))
 	maxSizePerTxn := uint64(defaultSizePerTxn)
@@ -75,8 +84,15 @@ func NewTableFlowController(quota uint64, redoLogEnabled bool, splitTxn bool) *T
 		maxSizePerTxn = quota
 	}
 
+	var quotaHandle *tableQuotaHandle
+	if changefeedQuota != nil {
+		quotaHandle = changefeedQuota.attach()
+	}
+
 	return &TableFlowController{
-		memoryQuota: newTableMemoryQuota(quota),
+		memoryQuota:     newTableMemoryQuota(quota),
+		changefeedQuota: changefeedQuota,
+		quotaHandle:     quotaHandle,
 		queueMu: struct {
 			sync.Mutex
 			queue deque.Deque
@@ -100,7 +116,14 @@ func (c *TableFlowController) Consume(
 ) error {
 	commitTs := msg.CRTs
 	lastCommitTs := atomic.LoadUint64(&c.lastCommitTs)
+	// blockingCallBack may be needed by both the table quota and the changefeed
+	// quota, but it must be called at most once for an event.
+	called := false
 	blockingCallBack := func() (err error) {
+		if called {
+			return nil
+		}
+		called = true
 		if commitTs > lastCommitTs || c.splitTxn {
 			// Call `callback` in two condition:
 			// 1. commitTs > lastCommitTs, handle new txn and send a normal resolved ts
@@ -129,16 +152,61 @@ func (c *TableFlowController) Consume(
 		if err := c.memoryQuota.forceConsume(size); err != nil {
 			return errors.Trace(err)
 		}
+		if c.changefeedQuota != nil {
+			if err := c.changefeedQuota.forceConsume(c.quotaHandle, size); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	} else {
 		if err := c.memoryQuota.consumeWithBlocking(size, blockingCallBack); err != nil {
 			return errors.Trace(err)
 		}
+		if c.changefeedQuota != nil {
+			err := c.changefeedQuota.consumeWithBlocking(c.quotaHandle, size, blockingCallBack)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
 	}
 
 	c.enqueueSingleMsg(msg, size, blockingCallBack)
 	return nil
 }
 
+// ShouldSpill returns true if consuming the event would be blocked by the
+// changefeed memory quota, in which case the caller can spill the event to
+// disk instead of calling Consume, and consume it later.
+// Events in the middle of a transaction are spilled only if the transaction
+// can be split, since the others are force consumed without blocking. The
+// caller should call SplitTxn before spilling such an event, so that the rows
+// consumed before can be flushed to release the quota.
+func (c *TableFlowController) ShouldSpill(msg *model.PolymorphicEvent, size uint64) bool {
+	if c.changefeedQuota == nil {
+		return false
+	}
+	if msg.CRTs == atomic.LoadUint64(&c.lastCommitTs) && (c.redoLogEnabled || !c.splitTxn) {
+		return false
+	}
+	return !c.changefeedQuota.hasRoomFor(c.quotaHandle, size)
+}
+
+// SplitTxn ends the current batch of the transaction being consumed, callBack
+// is called with the ID of the batch to send a batch resolved event, so that
+// the rows of the batch can be flushed while the rest of the transaction is
+// spilled. It does nothing if no rows are consumed in the current batch.
+func (c *TableFlowController) SplitTxn(callBack func(batchID uint64) error) error {
+	if !c.splitTxn || c.batchGroupCount == 0 {
+		return nil
+	}
+	if err := callBack(c.batchID); err != nil {
+		return errors.Trace(err)
+	}
+	lastCommitTs := atomic.LoadUint64(&c.lastCommitTs)
+	c.batchID++
+	c.resetBatch(lastCommitTs, lastCommitTs)
+	return nil
+}
+
 // Release releases the memory quota based on the given resolved timestamp.
 func (c *TableFlowController) Release(resolved model.ResolvedTs) {
 	var nBytesToRelease uint64
@@ -157,6 +225,9 @@ func (c *TableFlowController) Release(resolved model.ResolvedTs) {
 	c.queueMu.Unlock()
 
 	c.memoryQuota.release(nBytesToRelease)
+	if c.changefeedQuota != nil {
+		c.changefeedQuota.release(c.quotaHandle, nBytesToRelease)
+	}
 }
 
 // Note that msgs received by enqueueSingleMsg must be sorted by commitTs_startTs order.
@@ -229,6 +300,9 @@ func (c *TableFlowController) resetBatch(lastCommitTs, commitTs uint64) {
 // Abort interrupts any ongoing Consume call
 func (c *TableFlowController) Abort() {
 	c.memoryQuota.abort()
+	if c.changefeedQuota != nil {
+		c.changefeedQuota.detach(c.quotaHandle)
+	}
 }
 
 // GetConsumption returns the current memory consumption
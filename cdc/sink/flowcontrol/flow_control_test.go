@@ -181,7 +181,7 @@ func TestFlowControlWithForceConsume(t *testing.T) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *txnSizeEntry, 1024)
-	flowController := NewTableFlowController(2048, true, true)
+	flowController := NewTableFlowController(2048, nil, true, true)
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
@@ -297,7 +297,7 @@ func TestFlowControlWithBatchAndForceConsume(t *testing.T) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *txnSizeEntry, 1024)
-	flowController := NewTableFlowController(512, true, true)
+	flowController := NewTableFlowController(512, nil, true, true)
 	maxBatch := uint64(3)
 
 	// simulate a big txn
@@ -418,7 +418,7 @@ func TestFlowControlWithoutForceConsume(t *testing.T) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *txnSizeEntry, 1024)
-	flowController := NewTableFlowController(512, false, true)
+	flowController := NewTableFlowController(512, nil, false, true)
 	maxBatch := uint64(3)
 
 	// simulate a big txn
@@ -535,7 +535,7 @@ func TestFlowControlAbort(t *testing.T) {
 	t.Parallel()
 
 	callBacker := &mockCallBacker{}
-	controller := NewTableFlowController(1024, false, false)
+	controller := NewTableFlowController(1024, nil, false, false)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -566,7 +566,7 @@ func TestFlowControlCallBack(t *testing.T) {
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *txnSizeEntry, 1024)
-	flowController := NewTableFlowController(512, false, false)
+	flowController := NewTableFlowController(512, nil, false, false)
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
@@ -670,7 +670,7 @@ func TestFlowControlCallBackNotBlockingRelease(t *testing.T) {
 	t.Parallel()
 
 	var wg sync.WaitGroup
-	controller := NewTableFlowController(512, false, false)
+	controller := NewTableFlowController(512, nil, false, false)
 	wg.Add(1)
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -712,7 +712,7 @@ func TestFlowControlCallBackError(t *testing.T) {
 	t.Parallel()
 
 	var wg sync.WaitGroup
-	controller := NewTableFlowController(512, false, false)
+	controller := NewTableFlowController(512, nil, false, false)
 	wg.Add(1)
 
 	ctx, cancel := context.WithCancel(context.TODO())
@@ -741,7 +741,7 @@ func TestFlowControlCallBackError(t *testing.T) {
 func TestFlowControlConsumeLargerThanQuota(t *testing.T) {
 	t.Parallel()
 
-	controller := NewTableFlowController(1024, false, false)
+	controller := NewTableFlowController(1024, nil, false, false)
 	err := controller.Consume(model.NewEmptyPolymorphicEvent(1), 2048, func(uint64) error {
 		t.Error("unreachable")
 		return nil
@@ -749,12 +749,170 @@ func TestFlowControlConsumeLargerThanQuota(t *testing.T) {
 	require.Regexp(t, ".*ErrFlowControllerEventLargerThanQuota.*", err)
 }
 
+func TestFlowControlChangefeedQuota(t *testing.T) {
+	t.Parallel()
+
+	changefeedQuota := NewChangefeedMemoryQuota(1024)
+	controllerA := NewTableFlowController(1024, changefeedQuota, false, false)
+	controllerB := NewTableFlowController(1024, changefeedQuota, false, false)
+	noBlocking := func(uint64) error {
+		t.Error("unreachable")
+		return nil
+	}
+
+	err := controllerA.Consume(model.NewEmptyPolymorphicEvent(1), 800, noBlocking)
+	require.Nil(t, err)
+	// The changefeed quota is exhausted, but table B is within its fair share.
+	err = controllerB.Consume(model.NewEmptyPolymorphicEvent(1), 400, noBlocking)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1200), changefeedQuota.GetConsumption())
+
+	var wg sync.WaitGroup
+	var timesCalled int32
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := controllerB.Consume(model.NewEmptyPolymorphicEvent(2), 400,
+			func(uint64) error {
+				atomic.AddInt32(&timesCalled, 1)
+				return nil
+			})
+		require.Nil(t, err)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&timesCalled))
+	require.Equal(t, uint64(1200), changefeedQuota.GetConsumption())
+	controllerA.Release(model.NewResolvedTs(1))
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&timesCalled))
+	require.Equal(t, uint64(800), changefeedQuota.GetConsumption())
+
+	// Aborting a table releases all its memory from the changefeed quota.
+	controllerB.Abort()
+	controllerB.Abort()
+	require.Equal(t, uint64(0), changefeedQuota.GetConsumption())
+	err = controllerB.Consume(model.NewEmptyPolymorphicEvent(3), 10, noBlocking)
+	require.Regexp(t, ".*ErrFlowControllerAborted.*", err)
+}
+
+func TestFlowControlChangefeedQuotaAbort(t *testing.T) {
+	t.Parallel()
+
+	changefeedQuota := NewChangefeedMemoryQuota(1024)
+	controllerA := NewTableFlowController(1024, changefeedQuota, false, false)
+	controllerB := NewTableFlowController(1024, changefeedQuota, false, false)
+	callBacker := &mockCallBacker{}
+
+	err := controllerA.Consume(model.NewEmptyPolymorphicEvent(1), 1000, callBacker.cb)
+	require.Nil(t, err)
+	err = controllerB.Consume(model.NewEmptyPolymorphicEvent(1), 500, callBacker.cb)
+	require.Nil(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := controllerB.Consume(model.NewEmptyPolymorphicEvent(2), 500, callBacker.cb)
+		require.Regexp(t, ".*ErrFlowControllerAborted.*", err)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	controllerB.Abort()
+	wg.Wait()
+	require.Equal(t, uint64(1000), changefeedQuota.GetConsumption())
+}
+
+func TestFlowControlShouldSpill(t *testing.T) {
+	t.Parallel()
+
+	// Tables never spill without a changefeed quota.
+	controller := NewTableFlowController(1024, nil, false, false)
+	require.False(t, controller.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 2048))
+
+	changefeedQuota := NewChangefeedMemoryQuota(1024)
+	controllerA := NewTableFlowController(1024, changefeedQuota, false, false)
+	controllerB := NewTableFlowController(1024, changefeedQuota, false, false)
+	callBacker := &mockCallBacker{}
+
+	require.False(t, controllerA.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 800))
+	err := controllerA.Consume(model.NewEmptyPolymorphicEvent(1), 800, callBacker.cb)
+	require.Nil(t, err)
+	// Table B is within its fair share.
+	require.False(t, controllerB.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 400))
+	err = controllerB.Consume(model.NewEmptyPolymorphicEvent(1), 400, callBacker.cb)
+	require.Nil(t, err)
+
+	// A new transaction would be blocked by the changefeed quota.
+	require.True(t, controllerA.ShouldSpill(model.NewEmptyPolymorphicEvent(2), 100))
+	require.True(t, controllerB.ShouldSpill(model.NewEmptyPolymorphicEvent(2), 400))
+	// Events in the middle of a transaction are not spilled if the
+	// transaction can not be split.
+	require.False(t, controllerA.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 100))
+
+	controllerA.Release(model.NewResolvedTs(1))
+	require.False(t, controllerB.ShouldSpill(model.NewEmptyPolymorphicEvent(2), 400))
+
+	// Aborted tables are not spilled, Consume returns an error instead.
+	controllerA.Abort()
+	require.False(t, controllerA.ShouldSpill(model.NewEmptyPolymorphicEvent(2), 2048))
+}
+
+func TestFlowControlShouldSpillSplitTxn(t *testing.T) {
+	t.Parallel()
+
+	changefeedQuota := NewChangefeedMemoryQuota(1024)
+	controller := NewTableFlowController(2048, changefeedQuota, false, true)
+	callBacker := &mockCallBacker{}
+
+	// A large transaction exceeds the changefeed quota.
+	require.False(t, controller.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 800))
+	err := controller.Consume(model.NewEmptyPolymorphicEvent(1), 800, callBacker.cb)
+	require.Nil(t, err)
+	msg := model.NewEmptyPolymorphicEvent(1)
+	require.True(t, controller.ShouldSpill(msg, 400))
+
+	// The transaction is split, the rows consumed before are flushed in
+	// the first batch.
+	var batchIDs []uint64
+	callBack := func(batchID uint64) error {
+		batchIDs = append(batchIDs, batchID)
+		return nil
+	}
+	require.Nil(t, controller.SplitTxn(callBack))
+	require.Equal(t, []uint64{1}, batchIDs)
+	// Nothing is consumed in the new batch, it is not split again.
+	require.Nil(t, controller.SplitTxn(callBack))
+	require.Equal(t, []uint64{1}, batchIDs)
+
+	controller.Release(model.ResolvedTs{
+		Mode: model.BatchResolvedMode, Ts: 1, BatchID: batchIDs[0],
+	})
+	require.Equal(t, uint64(0), changefeedQuota.GetConsumption())
+	require.False(t, controller.ShouldSpill(msg, 400))
+	err = controller.Consume(msg, 400, callBacker.cb)
+	require.Nil(t, err)
+	// The rest of the transaction is released in the next batch.
+	controller.Release(model.ResolvedTs{
+		Mode: model.BatchResolvedMode, Ts: 1, BatchID: batchIDs[0] + 1,
+	})
+	require.Equal(t, uint64(0), changefeedQuota.GetConsumption())
+
+	// The transaction can not be split with the redo log.
+	changefeedQuota = NewChangefeedMemoryQuota(1024)
+	controller = NewTableFlowController(2048, changefeedQuota, true, true)
+	err = controller.Consume(model.NewEmptyPolymorphicEvent(1), 800, callBacker.cb)
+	require.Nil(t, err)
+	require.False(t, controller.ShouldSpill(model.NewEmptyPolymorphicEvent(1), 400))
+	require.True(t, controller.ShouldSpill(model.NewEmptyPolymorphicEvent(2), 400))
+}
+
 func BenchmarkTableFlowController(B *testing.B) {
 	ctx, cancel := context.WithTimeout(context.TODO(), time.Second*5)
 	defer cancel()
 	errg, ctx := errgroup.WithContext(ctx)
 	mockedRowsCh := make(chan *txnSizeEntry, 102400)
-	flowController := NewTableFlowController(20*1024*1024, false, false) // 20M
+	flowController := NewTableFlowController(20*1024*1024, nil, false, false) // 20M
 
 	errg.Go(func() error {
 		lastCommitTs := uint64(1)
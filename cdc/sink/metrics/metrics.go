@@ -89,6 +89,46 @@ var (
 			Help:      "size of the DML bucket",
 		}, []string{"namespace", "changefeed", "bucket"})
 
+	// AdaptiveWorkerCountGauge is the number of active workers chosen by
+	// the adaptive controller of the mysql sink.
+	AdaptiveWorkerCountGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "adaptive_worker_count",
+			Help:      "The number of active workers chosen by the adaptive controller",
+		}, []string{"namespace", "changefeed"})
+
+	// AdaptiveMaxTxnRowGauge is the max txn rows chosen by the adaptive
+	// controller of the mysql sink.
+	AdaptiveMaxTxnRowGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "adaptive_max_txn_row",
+			Help:      "The max rows of a txn chosen by the adaptive controller",
+		}, []string{"namespace", "changefeed"})
+
+	// AdaptiveBatchReplaceSizeGauge is the batch replace size chosen by the
+	// adaptive controller of the mysql sink.
+	AdaptiveBatchReplaceSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "adaptive_batch_replace_size",
+			Help:      "The batch replace size chosen by the adaptive controller",
+		}, []string{"namespace", "changefeed"})
+
+	// AdaptiveDecisionCounter is the counter of the decisions made by the
+	// adaptive controller of the mysql sink.
+	AdaptiveDecisionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "adaptive_decision_count",
+			Help:      "The count of decisions made by the adaptive controller",
+		}, []string{"namespace", "changefeed", "decision"})
+
 	// TotalRowsCountGauge is the total number of rows that are processed by sink.
 	TotalRowsCountGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -126,6 +166,10 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(ExecutionErrorCounter)
 	registry.MustRegister(ConflictDetectDurationHis)
 	registry.MustRegister(BucketSizeCounter)
+	registry.MustRegister(AdaptiveWorkerCountGauge)
+	registry.MustRegister(AdaptiveMaxTxnRowGauge)
+	registry.MustRegister(AdaptiveBatchReplaceSizeGauge)
+	registry.MustRegister(AdaptiveDecisionCounter)
 	registry.MustRegister(TotalRowsCountGauge)
 	registry.MustRegister(TotalFlushedRowsCountGauge)
 	registry.MustRegister(TableSinkTotalRowsCountCounter)
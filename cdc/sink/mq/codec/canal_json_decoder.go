@@ -72,6 +72,9 @@ func (b *canalJSONBatchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, e
 	if err != nil {
 		return nil, err
 	}
+	if withExtension, ok := b.msg.(*canalJSONMessageWithTiDBExtension); ok {
+		result.Checksum = withExtension.Extensions.Checksum
+	}
 	b.msg = nil
 	return result, nil
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
@@ -35,6 +36,7 @@ type canalJSONBatchEncoder struct {
 	// When it is true, canal-json would generate TiDB extension information
 	// which, at the moment, only includes `tidbWaterMarkType` and `_tidb` fields.
 	enableTiDBExtension bool
+	renderer            *typeRenderer
 }
 
 // newCanalJSONBatchEncoder creates a new canalJSONBatchEncoder
@@ -117,8 +119,12 @@ func (c *canalJSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) (
 		Data:          make([]map[string]interface{}, 0),
 		Old:           nil,
 		tikvTs:        e.CommitTs,
+		checksum:      e.Checksum,
 	}
 
+	c.renderer.renderColumns(oldData, e.PreColumns, e.ColInfos)
+	c.renderer.renderColumns(data, e.Columns, e.ColInfos)
+
 	if e.IsDelete() {
 		msg.Data = append(msg.Data, oldData)
 	} else if e.IsInsert() {
@@ -136,7 +142,7 @@ func (c *canalJSONBatchEncoder) newJSONMessageForDML(e *model.RowChangedEvent) (
 
 	return &canalJSONMessageWithTiDBExtension{
 		canalJSONMessage: msg,
-		Extensions:       &tidbExtension{CommitTs: e.CommitTs},
+		Extensions:       &tidbExtension{CommitTs: e.CommitTs, Checksum: e.Checksum},
 	}, nil
 }
 
@@ -233,6 +239,7 @@ func (c *canalJSONBatchEncoder) Build() []*MQMessage {
 		}
 		m := newMsg(config.ProtocolCanalJSON, nil, value, msg.getTikvTs(), model.MessageTypeRow, msg.getSchema(), msg.getTable())
 		m.IncRowsCount()
+		appendRowChecksumHeader(m, msg.getChecksum())
 		ret[i] = m
 	}
 	c.messageBuf = make([]canalJSONMessageInterface, 0)
@@ -246,17 +253,24 @@ func (c *canalJSONBatchEncoder) Build() []*MQMessage {
 }
 
 type canalJSONBatchEncoderBuilder struct {
-	config *Config
+	config   *Config
+	renderer *typeRenderer
 }
 
-func newCanalJSONBatchEncoderBuilder(config *Config) EncoderBuilder {
-	return &canalJSONBatchEncoderBuilder{config: config}
+func newCanalJSONBatchEncoderBuilder(ctx context.Context, config *Config) EncoderBuilder {
+	return &canalJSONBatchEncoderBuilder{
+		config: config,
+		// canal-json only carries string values.
+		renderer: newTypeRenderer(
+			config.typeRendering, contextutil.TimezoneFromCtx(ctx)).withStringValues(),
+	}
 }
 
 // Build a `canalJSONBatchEncoder`
 func (b *canalJSONBatchEncoderBuilder) Build() EventBatchEncoder {
 	encoder := newCanalJSONBatchEncoder()
 	encoder.(*canalJSONBatchEncoder).enableTiDBExtension = b.config.enableTiDBExtension
+	encoder.(*canalJSONBatchEncoder).renderer = b.renderer
 
 	return encoder
 }
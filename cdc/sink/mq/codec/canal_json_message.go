@@ -30,6 +30,7 @@ const tidbWaterMarkType = "TIDB_WATERMARK"
 // canalJSONMessageInterface is used to support this without affect the original format.
 type canalJSONMessageInterface interface {
 	getTikvTs() uint64
+	getChecksum() *uint32
 	getSchema() *string
 	getTable() *string
 	getCommitTs() uint64
@@ -66,13 +67,18 @@ type canalJSONMessage struct {
 	Data []map[string]interface{} `json:"data"`
 	Old  []map[string]interface{} `json:"old"`
 	// Used internally by canalJSONBatchEncoder
-	tikvTs uint64
+	tikvTs   uint64
+	checksum *uint32
 }
 
 func (c *canalJSONMessage) getTikvTs() uint64 {
 	return c.tikvTs
 }
 
+func (c *canalJSONMessage) getChecksum() *uint32 {
+	return c.checksum
+}
+
 func (c *canalJSONMessage) getSchema() *string {
 	return &c.Schema
 }
@@ -137,8 +143,9 @@ func (c *canalJSONMessage) pkNameSet() map[string]struct{} {
 }
 
 type tidbExtension struct {
-	CommitTs    uint64 `json:"commitTs,omitempty"`
-	WatermarkTs uint64 `json:"watermarkTs,omitempty"`
+	CommitTs    uint64  `json:"commitTs,omitempty"`
+	WatermarkTs uint64  `json:"watermarkTs,omitempty"`
+	Checksum    *uint32 `json:"checksum,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RowChecksumHeader is the header key of the row checksums of a message.
+//
+// The value is the checksums of the rows in the message in decimal, separated
+// by commas in the order of the rows. It's only set if the row checksum is
+// enabled for the changefeed, so the consumers can verify the rows without
+// the help of the protocol.
+const RowChecksumHeader = "ticdc-row-checksum"
+
+// appendRowChecksumHeader appends the checksum of a row to the row checksum
+// header of the message, nothing is appended if the row has no checksum.
+func appendRowChecksumHeader(msg *MQMessage, checksum *uint32) {
+	if checksum == nil {
+		return
+	}
+	value := strconv.FormatUint(uint64(*checksum), 10)
+	if msg.Headers == nil {
+		msg.Headers = make(map[string][]byte)
+	}
+	if prev, ok := msg.Headers[RowChecksumHeader]; ok {
+		value = string(prev) + "," + value
+	}
+	msg.Headers[RowChecksumHeader] = []byte(value)
+}
+
+// ParseRowChecksumHeader parses the value of the row checksum header.
+func ParseRowChecksumHeader(value []byte) ([]uint32, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	parts := strings.Split(string(value), ",")
+	checksums := make([]uint32, 0, len(parts))
+	for _, part := range parts {
+		checksum, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+		}
+		checksums = append(checksums, uint32(checksum))
+	}
+	return checksums, nil
+}
+
+// VerifyRowChecksum verifies the checksum of a decoded row, it returns nil if
+// the row has no checksum.
+// The checksum can only be verified if the values of the row are encoded
+// without a type rendering policy.
+func VerifyRowChecksum(row *model.RowChangedEvent) error {
+	if row.Checksum == nil {
+		return nil
+	}
+	calculated := model.CalcRowChecksum(row.ChecksumColumns())
+	if calculated != *row.Checksum {
+		return cerror.ErrRowChecksumMismatch.GenWithStackByArgs(
+			*row.Checksum, calculated, row.Table)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func withChecksum(row *model.RowChangedEvent) *model.RowChangedEvent {
+	clone := *row
+	row = &clone
+	checksum := model.CalcRowChecksum(row.ChecksumColumns())
+	row.Checksum = &checksum
+	return row
+}
+
+func TestRowChecksumHeader(t *testing.T) {
+	t.Parallel()
+
+	msg := &MQMessage{}
+	appendRowChecksumHeader(msg, nil)
+	require.Nil(t, msg.Headers)
+
+	checksums := []uint32{1, 4294967295}
+	for i := range checksums {
+		appendRowChecksumHeader(msg, &checksums[i])
+	}
+	require.Equal(t, "1,4294967295", string(msg.Headers[RowChecksumHeader]))
+	parsed, err := ParseRowChecksumHeader(msg.Headers[RowChecksumHeader])
+	require.Nil(t, err)
+	require.Equal(t, checksums, parsed)
+
+	parsed, err = ParseRowChecksumHeader(nil)
+	require.Nil(t, err)
+	require.Nil(t, parsed)
+	_, err = ParseRowChecksumHeader([]byte("1,a"))
+	require.Regexp(t, ".*ErrDecodeFailed.*", err)
+}
+
+func TestVerifyRowChecksum(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, VerifyRowChecksum(testCaseInsert))
+
+	row := withChecksum(testCaseDelete)
+	require.Nil(t, VerifyRowChecksum(row))
+	*row.Checksum++
+	require.True(t, cerror.ErrRowChecksumMismatch.Equal(VerifyRowChecksum(row)))
+}
+
+func TestOpenProtocolRowChecksum(t *testing.T) {
+	t.Parallel()
+
+	rows := []*model.RowChangedEvent{
+		withChecksum(testCaseInsert),
+		withChecksum(testCaseUpdate),
+		withChecksum(testCaseDelete),
+	}
+	encoder := newOpenProtocolBatchEncoderBuilder(
+		context.Background(), NewConfig(config.ProtocolOpen)).Build()
+	for _, row := range rows {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+		require.Nil(t, err)
+	}
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	checksums, err := ParseRowChecksumHeader(msgs[0].Headers[RowChecksumHeader])
+	require.Nil(t, err)
+	require.Equal(t, []uint32{*rows[0].Checksum, *rows[1].Checksum, *rows[2].Checksum}, checksums)
+
+	decoder, err := NewOpenProtocolBatchDecoder(msgs[0].Key, msgs[0].Value)
+	require.Nil(t, err)
+	for _, row := range rows {
+		_, hasNext, err := decoder.HasNext()
+		require.Nil(t, err)
+		require.True(t, hasNext)
+		decoded, err := decoder.NextRowChangedEvent()
+		require.Nil(t, err)
+		require.Equal(t, *row.Checksum, *decoded.Checksum)
+		require.Nil(t, VerifyRowChecksum(decoded))
+
+		// The checksum mismatches if a value is changed.
+		decoded.ChecksumColumns()[0].Value = "tampered"
+		require.True(t, cerror.ErrRowChecksumMismatch.Equal(VerifyRowChecksum(decoded)))
+	}
+}
+
+func TestCanalJSONRowChecksum(t *testing.T) {
+	t.Parallel()
+
+	for _, enableTiDBExtension := range []bool{false, true} {
+		encoder := &canalJSONBatchEncoder{
+			builder:             newCanalEntryBuilder(),
+			enableTiDBExtension: enableTiDBExtension,
+		}
+		for _, row := range []*model.RowChangedEvent{
+			withChecksum(testCaseInsert),
+			withChecksum(testCaseUpdate),
+			withChecksum(testCaseDelete),
+		} {
+			err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+			require.Nil(t, err)
+			msgs := encoder.Build()
+			require.Len(t, msgs, 1)
+			checksums, err := ParseRowChecksumHeader(msgs[0].Headers[RowChecksumHeader])
+			require.Nil(t, err)
+			require.Equal(t, []uint32{*row.Checksum}, checksums)
+
+			decoder := NewCanalJSONBatchDecoder(msgs[0].Value, enableTiDBExtension)
+			_, hasNext, err := decoder.HasNext()
+			require.Nil(t, err)
+			require.True(t, hasNext)
+			decoded, err := decoder.NextRowChangedEvent()
+			require.Nil(t, err)
+			if !enableTiDBExtension {
+				// The checksum is only in the TiDB extension of the message.
+				require.Nil(t, decoded.Checksum)
+				decoded.Checksum = &checksums[0]
+			}
+			require.Equal(t, *row.Checksum, *decoded.Checksum)
+			require.Nil(t, VerifyRowChecksum(decoded))
+		}
+	}
+}
+
+func TestMaxwellRowChecksum(t *testing.T) {
+	t.Parallel()
+
+	encoder := newMaxwellBatchEncoder()
+	rows := []*model.RowChangedEvent{
+		withChecksum(testCaseInsert),
+		withChecksum(testCaseDelete),
+	}
+	for _, row := range rows {
+		err := encoder.AppendRowChangedEvent(context.Background(), "", row, nil)
+		require.Nil(t, err)
+	}
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	require.Contains(t, string(msgs[0].Value), "\"checksum\"")
+	checksums, err := ParseRowChecksumHeader(msgs[0].Headers[RowChecksumHeader])
+	require.Nil(t, err)
+	require.Equal(t, []uint32{*rows[0].Checksum, *rows[1].Checksum}, checksums)
+
+	// The checksums of the previous batch are not carried over.
+	err = encoder.AppendRowChangedEvent(context.Background(), "", testCaseInsert, nil)
+	require.Nil(t, err)
+	msgs = encoder.Build()
+	require.Len(t, msgs, 1)
+	require.NotContains(t, msgs[0].Headers, RowChecksumHeader)
+}
+
+func TestRowChecksumWithTypeRendering(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rendering := &config.TypeRenderingConfig{TimestampFormat: config.TimestampFormatISO}
+	for _, protocol := range []config.Protocol{config.ProtocolOpen, config.ProtocolCanalJSON} {
+		for _, cfg := range []*config.TypeRenderingConfig{nil, rendering} {
+			codecConfig := NewConfig(protocol)
+			codecConfig.enableTiDBExtension = protocol == config.ProtocolCanalJSON
+			codecConfig.typeRendering = cfg
+			require.Nil(t, codecConfig.Validate())
+			builder, err := NewEventBatchEncoderBuilder(ctx, codecConfig)
+			require.Nil(t, err)
+			encoder := builder.Build()
+			row := withChecksum(newTypeRenderingTestRow())
+			require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", row, nil))
+			msgs := encoder.Build()
+			require.Len(t, msgs, 1)
+
+			var decoder EventBatchDecoder
+			if protocol == config.ProtocolOpen {
+				decoder, err = NewOpenProtocolBatchDecoder(msgs[0].Key, msgs[0].Value)
+				require.Nil(t, err)
+			} else {
+				decoder = NewCanalJSONBatchDecoder(msgs[0].Value, true)
+			}
+			_, hasNext, err := decoder.HasNext()
+			require.Nil(t, err)
+			require.True(t, hasNext)
+			decoded, err := decoder.NextRowChangedEvent()
+			require.Nil(t, err)
+			require.Equal(t, *row.Checksum, *decoded.Checksum)
+			if cfg == nil {
+				require.Nil(t, VerifyRowChecksum(decoded), protocol.String())
+				continue
+			}
+			// The rendered values can not be verified, so enable-row-checksum
+			// is rejected together with type-rendering.
+			require.True(t, cerror.ErrRowChecksumMismatch.Equal(VerifyRowChecksum(decoded)),
+				protocol.String())
+		}
+	}
+}
@@ -115,6 +115,10 @@ func (c *column) toCanalJSONFormatColumn(name string, javaType JavaSQLType) *mod
 
 	if javaType == JavaSQLTypeBIT {
 		val, err := strconv.ParseUint(value, 10, 64)
+		if err != nil && c.Type == mysql.TypeSet {
+			// the set value is rendered as the members by type-rendering.
+			return col
+		}
 		if err != nil {
 			log.Panic("invalid column value for bit", zap.Any("col", c), zap.Error(err))
 		}
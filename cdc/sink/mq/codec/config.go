@@ -40,6 +40,9 @@ type Config struct {
 	avroSchemaRegistry             string
 	avroDecimalHandlingMode        string
 	avroBigintUnsignedHandlingMode string
+
+	// open-protocol, canal-json and maxwell only
+	typeRendering *config.TypeRenderingConfig
 }
 
 // NewConfig return a Config for codec
@@ -112,6 +115,10 @@ func (c *Config) Apply(sinkURI *url.URL, config *config.ReplicaConfig) error {
 		c.avroSchemaRegistry = config.Sink.SchemaRegistry
 	}
 
+	if config.Sink != nil {
+		c.typeRendering = config.Sink.TypeRendering
+	}
+
 	return nil
 }
 
@@ -130,6 +137,14 @@ func (c *Config) Validate() error {
 		)
 	}
 
+	if !c.typeRendering.IsEmpty() &&
+		!(c.protocol == config.ProtocolOpen || c.protocol == config.ProtocolDefault ||
+			c.protocol == config.ProtocolCanalJSON || c.protocol == config.ProtocolMaxwell) {
+		return cerror.ErrMQCodecInvalidConfig.GenWithStack(
+			`type-rendering only supports open-protocol/canal-json/maxwell protocol`,
+		)
+	}
+
 	if c.protocol == config.ProtocolAvro {
 		if c.avroSchemaRegistry == "" {
 			return cerror.ErrMQCodecInvalidConfig.GenWithStack(
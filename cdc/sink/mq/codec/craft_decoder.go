@@ -116,8 +116,8 @@ func (b *craftBatchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 	return event, nil
 }
 
-// newCraftBatchDecoder creates a new craftBatchDecoder.
-func newCraftBatchDecoder(bits []byte) (EventBatchDecoder, error) {
+// NewCraftBatchDecoder creates a new craftBatchDecoder.
+func NewCraftBatchDecoder(bits []byte) (EventBatchDecoder, error) {
 	return newCraftBatchDecoderWithAllocator(bits, craft.NewSliceAllocator(64))
 }
 
@@ -73,7 +73,7 @@ func TestCraftMaxBatchSize(t *testing.T) {
 	messages := encoder.Build()
 	sum := 0
 	for _, msg := range messages {
-		decoder, err := newCraftBatchDecoder(msg.Value)
+		decoder, err := NewCraftBatchDecoder(msg.Value)
 		require.Nil(t, err)
 		count := 0
 		for {
@@ -204,7 +204,7 @@ func testBatchCodec(
 func TestDefaultCraftBatchCodec(t *testing.T) {
 	cfg := NewConfig(config.ProtocolCraft).WithMaxMessageBytes(8192)
 	cfg.maxBatchSize = 64
-	testBatchCodec(t, newCraftBatchEncoderBuilder(cfg), newCraftBatchDecoder)
+	testBatchCodec(t, newCraftBatchEncoderBuilder(cfg), NewCraftBatchDecoder)
 }
 
 func TestCraftAppendRowChangedEventWithCallback(t *testing.T) {
@@ -49,15 +49,15 @@ type EncoderBuilder interface {
 func NewEventBatchEncoderBuilder(ctx context.Context, c *Config) (EncoderBuilder, error) {
 	switch c.protocol {
 	case config.ProtocolDefault, config.ProtocolOpen:
-		return newOpenProtocolBatchEncoderBuilder(c), nil
+		return newOpenProtocolBatchEncoderBuilder(ctx, c), nil
 	case config.ProtocolCanal:
 		return newCanalBatchEncoderBuilder(), nil
 	case config.ProtocolAvro:
 		return newAvroEventBatchEncoderBuilder(ctx, c)
 	case config.ProtocolMaxwell:
-		return newMaxwellBatchEncoderBuilder(), nil
+		return newMaxwellBatchEncoderBuilder(ctx, c), nil
 	case config.ProtocolCanalJSON:
-		return newCanalJSONBatchEncoderBuilder(c), nil
+		return newCanalJSONBatchEncoderBuilder(ctx, c), nil
 	case config.ProtocolCraft:
 		return newCraftBatchEncoderBuilder(c), nil
 	default:
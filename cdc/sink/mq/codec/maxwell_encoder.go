@@ -19,6 +19,7 @@ import (
 	"encoding/binary"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 )
@@ -28,7 +29,9 @@ type maxwellBatchEncoder struct {
 	keyBuf      *bytes.Buffer
 	valueBuf    *bytes.Buffer
 	callbackBuf []func()
+	checksumBuf []*uint32
 	batchSize   int
+	renderer    *typeRenderer
 }
 
 // EncodeCheckpointEvent implements the EventBatchEncoder interface
@@ -45,12 +48,13 @@ func (d *maxwellBatchEncoder) AppendRowChangedEvent(
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	_, valueMsg := rowChangeToMaxwellMsg(e)
+	_, valueMsg := rowChangeToMaxwellMsg(e, d.renderer)
 	value, err := valueMsg.encode()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	d.valueBuf.Write(value)
+	d.checksumBuf = append(d.checksumBuf, e.Checksum)
 	d.batchSize++
 	if callback != nil {
 		d.callbackBuf = append(d.callbackBuf, callback)
@@ -83,6 +87,9 @@ func (d *maxwellBatchEncoder) Build() []*MQMessage {
 	ret := newMsg(config.ProtocolMaxwell,
 		d.keyBuf.Bytes(), d.valueBuf.Bytes(), 0, model.MessageTypeRow, nil, nil)
 	ret.SetRowsCount(d.batchSize)
+	for _, checksum := range d.checksumBuf {
+		appendRowChecksumHeader(ret, checksum)
+	}
 	if len(d.callbackBuf) != 0 && len(d.callbackBuf) == d.batchSize {
 		callbacks := d.callbackBuf
 		ret.Callback = func() {
@@ -100,6 +107,7 @@ func (d *maxwellBatchEncoder) Build() []*MQMessage {
 func (d *maxwellBatchEncoder) reset() {
 	d.keyBuf.Reset()
 	d.valueBuf.Reset()
+	d.checksumBuf = d.checksumBuf[:0]
 	d.batchSize = 0
 	var versionByte [8]byte
 	binary.BigEndian.PutUint64(versionByte[:], BatchVersion1)
@@ -117,13 +125,19 @@ func newMaxwellBatchEncoder() EventBatchEncoder {
 	return batch
 }
 
-type maxwellBatchEncoderBuilder struct{}
+type maxwellBatchEncoderBuilder struct {
+	renderer *typeRenderer
+}
 
-func newMaxwellBatchEncoderBuilder() EncoderBuilder {
-	return &maxwellBatchEncoderBuilder{}
+func newMaxwellBatchEncoderBuilder(ctx context.Context, config *Config) EncoderBuilder {
+	return &maxwellBatchEncoderBuilder{
+		renderer: newTypeRenderer(config.typeRendering, contextutil.TimezoneFromCtx(ctx)),
+	}
 }
 
 // Build a `maxwellBatchEncoder`
 func (b *maxwellBatchEncoderBuilder) Build() EventBatchEncoder {
-	return newMaxwellBatchEncoder()
+	encoder := newMaxwellBatchEncoder()
+	encoder.(*maxwellBatchEncoder).renderer = b.renderer
+	return encoder
 }
@@ -34,6 +34,7 @@ type maxwellMessage struct {
 	Gtid     string                 `json:"gtid,omitempty"`
 	Data     map[string]interface{} `json:"data,omitempty"`
 	Old      map[string]interface{} `json:"old,omitempty"`
+	Checksum *uint32                `json:"checksum,omitempty"`
 }
 
 // Encode encodes the message to bytes
@@ -42,7 +43,9 @@ func (m *maxwellMessage) encode() ([]byte, error) {
 	return data, cerror.WrapError(cerror.ErrMaxwellEncodeFailed, err)
 }
 
-func rowChangeToMaxwellMsg(e *model.RowChangedEvent) (*mqMessageKey, *maxwellMessage) {
+func rowChangeToMaxwellMsg(
+	e *model.RowChangedEvent, renderer *typeRenderer,
+) (*mqMessageKey, *maxwellMessage) {
 	var partition *int64
 	if e.Table.IsPartition {
 		partition = &e.Table.TableID
@@ -60,6 +63,7 @@ func rowChangeToMaxwellMsg(e *model.RowChangedEvent) (*mqMessageKey, *maxwellMes
 		Table:    e.Table.Table,
 		Data:     make(map[string]interface{}),
 		Old:      make(map[string]interface{}),
+		Checksum: e.Checksum,
 	}
 
 	physicalTime, _ := tsoutil.ParseTS(e.CommitTs)
@@ -124,6 +128,12 @@ func rowChangeToMaxwellMsg(e *model.RowChangedEvent) (*mqMessageKey, *maxwellMes
 
 		}
 	}
+	if e.IsDelete() {
+		renderer.renderColumns(value.Old, e.PreColumns, e.ColInfos)
+	} else {
+		renderer.renderColumns(value.Data, e.Columns, e.ColInfos)
+		renderer.renderColumns(value.Old, e.PreColumns, e.ColInfos)
+	}
 	return key, value
 }
 
@@ -40,13 +40,16 @@ type MQMessage struct {
 	Protocol  config.Protocol   // protocol
 	rowsCount int               // rows in one MQ Message
 	Callback  func()            // Callback function will be called when the message is sent to the mqSink.
+	Headers   map[string][]byte // Headers are sent as the headers of the Kafka record.
 }
 
 // Length returns the expected size of the Kafka message
-// We didn't append any `Headers` when send the message, so ignore the calculations related to it.
-// If `ProducerMessage` Headers fields used, this method should also adjust.
 func (m *MQMessage) Length() int {
-	return len(m.Key) + len(m.Value) + MaxRecordOverhead
+	length := len(m.Key) + len(m.Value) + MaxRecordOverhead
+	for key, value := range m.Headers {
+		length += len(key) + len(value) + 2*binary.MaxVarintLen32
+	}
+	return length
 }
 
 // PhysicalTime returns physical time part of Ts in time.Time
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
@@ -35,6 +36,7 @@ type OpenProtocolBatchEncoder struct {
 	// configs
 	maxMessageBytes int
 	maxBatchSize    int
+	renderer        *typeRenderer
 }
 
 // GetMaxMessageBytes is only for unit testing.
@@ -54,7 +56,7 @@ func (d *OpenProtocolBatchEncoder) AppendRowChangedEvent(
 	e *model.RowChangedEvent,
 	callback func(),
 ) error {
-	keyMsg, valueMsg := rowChangeToMsg(e)
+	keyMsg, valueMsg := rowChangeToMsg(e, d.renderer)
 	key, err := keyMsg.encode()
 	if err != nil {
 		return errors.Trace(err)
@@ -99,6 +101,7 @@ func (d *OpenProtocolBatchEncoder) AppendRowChangedEvent(
 	message.Schema = &e.Table.Schema
 	message.Table = &e.Table.Table
 	message.IncRowsCount()
+	appendRowChecksumHeader(message, e.Checksum)
 
 	if callback != nil {
 		d.callbackBuff = append(d.callbackBuff, callback)
@@ -190,7 +193,8 @@ func (d *OpenProtocolBatchEncoder) tryBuildCallback() {
 }
 
 type openProtocolBatchEncoderBuilder struct {
-	config *Config
+	config   *Config
+	renderer *typeRenderer
 }
 
 // Build a OpenProtocolBatchEncoder
@@ -198,12 +202,16 @@ func (b *openProtocolBatchEncoderBuilder) Build() EventBatchEncoder {
 	encoder := newOpenProtocolBatchEncoder()
 	encoder.(*OpenProtocolBatchEncoder).maxMessageBytes = b.config.maxMessageBytes
 	encoder.(*OpenProtocolBatchEncoder).maxBatchSize = b.config.maxBatchSize
+	encoder.(*OpenProtocolBatchEncoder).renderer = b.renderer
 
 	return encoder
 }
 
-func newOpenProtocolBatchEncoderBuilder(config *Config) EncoderBuilder {
-	return &openProtocolBatchEncoderBuilder{config: config}
+func newOpenProtocolBatchEncoderBuilder(ctx context.Context, config *Config) EncoderBuilder {
+	return &openProtocolBatchEncoderBuilder{
+		config:   config,
+		renderer: newTypeRenderer(config.typeRendering, contextutil.TimezoneFromCtx(ctx)),
+	}
 }
 
 // newOpenProtocolBatchEncoder creates a new OpenProtocolBatchEncoder.
@@ -183,19 +183,19 @@ func TestMaxMessageBytes(t *testing.T) {
 	// for a single message, the overhead is 36(maxRecordOverhead) + 8(versionHea) = 44, just can hold it.
 	a := 88 + 44
 	config := NewConfig(config.ProtocolOpen).WithMaxMessageBytes(a)
-	encoder := newOpenProtocolBatchEncoderBuilder(config).Build()
+	encoder := newOpenProtocolBatchEncoderBuilder(context.Background(), config).Build()
 	err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.Nil(t, err)
 
 	// cannot hold a single message
 	config = config.WithMaxMessageBytes(a - 1)
-	encoder = newOpenProtocolBatchEncoderBuilder(config).Build()
+	encoder = newOpenProtocolBatchEncoderBuilder(context.Background(), config).Build()
 	err = encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 	require.NotNil(t, err)
 
 	// make sure each batch's `Length` not greater than `max-message-bytes`
 	config = config.WithMaxMessageBytes(256)
-	encoder = newOpenProtocolBatchEncoderBuilder(config).Build()
+	encoder = newOpenProtocolBatchEncoderBuilder(context.Background(), config).Build()
 	for i := 0; i < 10000; i++ {
 		err := encoder.AppendRowChangedEvent(ctx, topic, testEvent, nil)
 		require.Nil(t, err)
@@ -211,7 +211,7 @@ func TestMaxBatchSize(t *testing.T) {
 	t.Parallel()
 	config := NewConfig(config.ProtocolOpen).WithMaxMessageBytes(1048576)
 	config.maxBatchSize = 64
-	encoder := newOpenProtocolBatchEncoderBuilder(config).Build()
+	encoder := newOpenProtocolBatchEncoderBuilder(context.Background(), config).Build()
 
 	testEvent := &model.RowChangedEvent{
 		CommitTs: 1,
@@ -336,5 +336,5 @@ func TestOpenProtocolBatchCodec(t *testing.T) {
 	config := NewConfig(config.ProtocolOpen).WithMaxMessageBytes(8192)
 	config.maxBatchSize = 64
 	tester := newDefaultBatchTester()
-	tester.testBatchCodec(t, newOpenProtocolBatchEncoderBuilder(config), NewOpenProtocolBatchDecoder)
+	tester.testBatchCodec(t, newOpenProtocolBatchEncoderBuilder(context.Background(), config), NewOpenProtocolBatchDecoder)
 }
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -28,6 +29,9 @@ type mqMessageRow struct {
 	Update     map[string]column `json:"u,omitempty"`
 	PreColumns map[string]column `json:"p,omitempty"`
 	Delete     map[string]column `json:"d,omitempty"`
+	// Checksum is the checksum of the row, it's only set if the row checksum
+	// is enabled for the changefeed.
+	Checksum *uint32 `json:"checksum,omitempty"`
 }
 
 func (m *mqMessageRow) encode() ([]byte, error) {
@@ -75,7 +79,9 @@ func newResolvedMessage(ts uint64) *mqMessageKey {
 	}
 }
 
-func rowChangeToMsg(e *model.RowChangedEvent) (*mqMessageKey, *mqMessageRow) {
+func rowChangeToMsg(
+	e *model.RowChangedEvent, renderer *typeRenderer,
+) (*mqMessageKey, *mqMessageRow) {
 	var partition *int64
 	if e.Table.IsPartition {
 		partition = &e.Table.TableID
@@ -88,12 +94,12 @@ func rowChangeToMsg(e *model.RowChangedEvent) (*mqMessageKey, *mqMessageRow) {
 		Partition: partition,
 		Type:      model.MessageTypeRow,
 	}
-	value := &mqMessageRow{}
+	value := &mqMessageRow{Checksum: e.Checksum}
 	if e.IsDelete() {
-		value.Delete = rowChangeColumns2MQColumns(e.PreColumns)
+		value.Delete = rowChangeColumns2MQColumns(e.PreColumns, e.ColInfos, renderer)
 	} else {
-		value.Update = rowChangeColumns2MQColumns(e.Columns)
-		value.PreColumns = rowChangeColumns2MQColumns(e.PreColumns)
+		value.Update = rowChangeColumns2MQColumns(e.Columns, e.ColInfos, renderer)
+		value.PreColumns = rowChangeColumns2MQColumns(e.PreColumns, e.ColInfos, renderer)
 	}
 	return key, value
 }
@@ -119,17 +125,23 @@ func msgToRowChange(key *mqMessageKey, value *mqMessageRow) *model.RowChangedEve
 		e.Columns = mqColumns2RowChangeColumns(value.Update)
 		e.PreColumns = mqColumns2RowChangeColumns(value.PreColumns)
 	}
+	e.Checksum = value.Checksum
 	return e
 }
 
-func rowChangeColumns2MQColumns(cols []*model.Column) map[string]column {
+func rowChangeColumns2MQColumns(
+	cols []*model.Column, colInfos []rowcodec.ColInfo, renderer *typeRenderer,
+) map[string]column {
 	jsonCols := make(map[string]column, len(cols))
-	for _, col := range cols {
+	for i, col := range cols {
 		if col == nil {
 			continue
 		}
 		c := column{}
 		c.fromRowChangeColumn(col)
+		if v, ok := renderer.render(col, columnFieldType(colInfos, i)); ok {
+			c.Value = v
+		}
 		jsonCols[col.Name] = c
 	}
 	if len(jsonCols) == 0 {
@@ -0,0 +1,65 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// PartitionChangeMarkerHeader is the header key of the partition change marker.
+//
+// The partition number of a topic is changed by the owner at a changefeed-wide
+// resolved ts barrier. When all the events dispatched with the old partition
+// number have been acknowledged by all the captures, the owner broadcasts the
+// marker to all the partitions before any event is dispatched with the new
+// partition number. So the events of a partition before the marker are
+// dispatched with the old partition number, and the ones after it are
+// dispatched with the new partition number.
+//
+// The marker is only sent if the sink config enable-partition-change-marker is set.
+const PartitionChangeMarkerHeader = "ticdc-partition-change"
+
+// PartitionChangeMarker is the value of the partition change marker.
+type PartitionChangeMarker struct {
+	// Ts is the resolved ts at which the partition number is changed.
+	Ts              uint64 `json:"ts"`
+	OldPartitionNum int32  `json:"old-partition-num"`
+	PartitionNum    int32  `json:"partition-num"`
+}
+
+// NewPartitionChangeMarkerMessage creates the message of the partition change marker.
+// The message is independent of the protocol, it's identified by its header.
+func NewPartitionChangeMarkerMessage(marker *PartitionChangeMarker) (*MQMessage, error) {
+	value, err := json.Marshal(marker)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrEncodeFailed, err)
+	}
+	msg := newMsg(
+		config.ProtocolDefault, nil, value, marker.Ts, model.MessageTypeUnknown, nil, nil)
+	msg.Headers = map[string][]byte{PartitionChangeMarkerHeader: []byte("true")}
+	return msg, nil
+}
+
+// DecodePartitionChangeMarker decodes the value of the partition change marker.
+func DecodePartitionChangeMarker(value []byte) (*PartitionChangeMarker, error) {
+	marker := &PartitionChangeMarker{}
+	if err := json.Unmarshal(value, marker); err != nil {
+		return nil, cerror.WrapError(cerror.ErrDecodeFailed, err)
+	}
+	return marker, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionChangeMarker(t *testing.T) {
+	t.Parallel()
+
+	marker := &PartitionChangeMarker{Ts: 100, OldPartitionNum: 3, PartitionNum: 6}
+	msg, err := NewPartitionChangeMarkerMessage(marker)
+	require.Nil(t, err)
+	require.Nil(t, msg.Key)
+	require.Equal(t, uint64(100), msg.Ts)
+	require.Contains(t, msg.Headers, PartitionChangeMarkerHeader)
+	require.Greater(t, msg.Length(), len(msg.Value)+MaxRecordOverhead)
+
+	decoded, err := DecodePartitionChangeMarker(msg.Value)
+	require.Nil(t, err)
+	require.Equal(t, marker, decoded)
+
+	_, err = DecodePartitionChangeMarker([]byte("invalid"))
+	require.Regexp(t, "ErrDecodeFailed", err)
+}
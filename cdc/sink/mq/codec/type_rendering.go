@@ -0,0 +1,235 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+)
+
+const (
+	mysqlDateLayout     = "2006-01-02"
+	mysqlDatetimeLayout = "2006-01-02 15:04:05"
+	isoDatetimeLayout   = "2006-01-02T15:04:05.999999"
+	isoTimestampLayout  = "2006-01-02T15:04:05.999999Z07:00"
+)
+
+// typeRenderer renders the column values by the type rendering policy of
+// the changefeed, it is shared by the JSON based encoders. A nil
+// typeRenderer keeps the rendering of the protocols.
+type typeRenderer struct {
+	cfg config.TypeRenderingConfig
+	// tz is the time zone of the timestamp values, which are formatted by
+	// the mounter in the time zone of the changefeed.
+	tz *time.Location
+	// stringValues renders all values as strings, it is required by the
+	// protocols which only carry string values, such as canal-json.
+	stringValues bool
+}
+
+func newTypeRenderer(cfg *config.TypeRenderingConfig, tz *time.Location) *typeRenderer {
+	if cfg.IsEmpty() {
+		return nil
+	}
+	if tz == nil {
+		tz = time.Local
+	}
+	return &typeRenderer{cfg: *cfg, tz: tz}
+}
+
+// withStringValues makes the renderer render all values as strings.
+func (r *typeRenderer) withStringValues() *typeRenderer {
+	if r != nil {
+		r.stringValues = true
+	}
+	return r
+}
+
+// render returns the rendered value of the column and true, or false if the
+// column should be rendered by the protocol. ft is the field type of the
+// column, it can be nil if unknown, then enum and set values are rendered by
+// the protocol.
+func (r *typeRenderer) render(col *model.Column, ft *types.FieldType) (interface{}, bool) {
+	if r == nil || col == nil || col.Value == nil {
+		return nil, false
+	}
+	switch col.Type {
+	case mysql.TypeNewDecimal:
+		return r.renderDecimal(col)
+	case mysql.TypeDate, mysql.TypeNewDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return r.renderTime(col)
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
+		return r.renderBinary(col)
+	case mysql.TypeEnum, mysql.TypeSet:
+		return r.renderEnum(col, ft)
+	}
+	return nil, false
+}
+
+// renderColumns overwrites the values of the columns in values with the
+// rendered values, the columns which are not in values are ignored.
+func (r *typeRenderer) renderColumns(
+	values map[string]interface{}, cols []*model.Column, colInfos []rowcodec.ColInfo,
+) {
+	if r == nil {
+		return
+	}
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		if _, ok := values[col.Name]; !ok {
+			continue
+		}
+		if v, ok := r.render(col, columnFieldType(colInfos, i)); ok {
+			if r.stringValues {
+				v = renderedString(v)
+			}
+			values[col.Name] = v
+		}
+	}
+}
+
+// renderedString returns the string form of a rendered value.
+func renderedString(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	}
+	return v
+}
+
+// columnFieldType returns the field type of the i-th column of a row.
+func columnFieldType(colInfos []rowcodec.ColInfo, i int) *types.FieldType {
+	if i < len(colInfos) {
+		return colInfos[i].Ft
+	}
+	return nil
+}
+
+func (r *typeRenderer) renderDecimal(col *model.Column) (interface{}, bool) {
+	s, ok := col.Value.(string)
+	if !ok {
+		return nil, false
+	}
+	switch r.cfg.Decimal {
+	case config.DecimalRenderingString:
+		return s, true
+	case config.DecimalRenderingNumber:
+		return json.Number(s), true
+	}
+	return nil, false
+}
+
+func (r *typeRenderer) renderTime(col *model.Column) (interface{}, bool) {
+	s, ok := col.Value.(string)
+	if !ok || (r.cfg.TimestampZone == "" && r.cfg.TimestampFormat == "") {
+		return nil, false
+	}
+	isTimestamp := col.Type == mysql.TypeTimestamp
+	layout, loc := mysqlDatetimeLayout, time.UTC
+	if col.Type == mysql.TypeDate || col.Type == mysql.TypeNewDate {
+		layout = mysqlDateLayout
+	} else if fsp := strings.IndexByte(s, '.'); fsp > 0 {
+		layout += "." + strings.Repeat("0", len(s)-fsp-1)
+	}
+	if isTimestamp {
+		loc = r.tz
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		// Zero dates like 0000-00-00 can not be rendered.
+		return nil, false
+	}
+	if isTimestamp && r.cfg.TimestampZone == config.TimestampZoneUTC {
+		t = t.UTC()
+	}
+
+	switch r.cfg.TimestampFormat {
+	case config.TimestampFormatISO:
+		switch {
+		case layout == mysqlDateLayout:
+			return t.Format(mysqlDateLayout), true
+		case isTimestamp:
+			return t.Format(isoTimestampLayout), true
+		default:
+			return t.Format(isoDatetimeLayout), true
+		}
+	case config.TimestampFormatEpoch:
+		return t.UnixMilli(), true
+	}
+	// Only the time zone of timestamps is changed, the format is kept.
+	if !isTimestamp {
+		return nil, false
+	}
+	return t.Format(layout), true
+}
+
+func (r *typeRenderer) renderBinary(col *model.Column) (interface{}, bool) {
+	b, ok := col.Value.([]byte)
+	if !ok || !col.Flag.IsBinary() {
+		return nil, false
+	}
+	switch r.cfg.Binary {
+	case config.BinaryRenderingBase64:
+		return base64.StdEncoding.EncodeToString(b), true
+	case config.BinaryRenderingHex:
+		return hex.EncodeToString(b), true
+	}
+	return nil, false
+}
+
+func (r *typeRenderer) renderEnum(col *model.Column, ft *types.FieldType) (interface{}, bool) {
+	number, ok := col.Value.(uint64)
+	if !ok {
+		return nil, false
+	}
+	switch r.cfg.Enum {
+	case config.EnumRenderingIndex:
+		return number, true
+	case config.EnumRenderingValue:
+		if ft == nil {
+			return nil, false
+		}
+		if col.Type == mysql.TypeSet {
+			s, err := types.ParseSetValue(ft.GetElems(), number)
+			if err != nil {
+				return nil, false
+			}
+			return s.Name, true
+		}
+		// The index of the invalid enum value '' is 0.
+		e, err := types.ParseEnumValue(ft.GetElems(), number)
+		if err != nil {
+			return "", true
+		}
+		return e.Name, true
+	}
+	return nil, false
+}
@@ -0,0 +1,275 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTypeRenderingTestRow() *model.RowChangedEvent {
+	enumType := types.NewFieldType(mysql.TypeEnum)
+	enumType.SetElems([]string{"a", "b", "c"})
+	setType := types.NewFieldType(mysql.TypeSet)
+	setType.SetElems([]string{"a", "b", "c"})
+	columns := []*model.Column{
+		{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: int64(1)},
+		{Name: "d", Type: mysql.TypeNewDecimal, Value: "3.14"},
+		{Name: "ts", Type: mysql.TypeTimestamp, Value: "2022-07-01 08:00:00.123"},
+		{Name: "dt", Type: mysql.TypeDatetime, Value: "2022-07-01 08:00:00"},
+		{Name: "da", Type: mysql.TypeDate, Value: "2022-07-01"},
+		{Name: "b", Type: mysql.TypeBlob, Flag: model.BinaryFlag, Value: []byte{0x01, 0xff}},
+		{Name: "e", Type: mysql.TypeEnum, Value: uint64(2)},
+		{Name: "s", Type: mysql.TypeSet, Value: uint64(5)},
+	}
+	colInfos := make([]rowcodec.ColInfo, len(columns))
+	for i, col := range columns {
+		colInfos[i] = rowcodec.ColInfo{ID: int64(i + 1), Ft: types.NewFieldType(col.Type)}
+	}
+	colInfos[6].Ft = enumType
+	colInfos[7].Ft = setType
+	return &model.RowChangedEvent{
+		CommitTs: 434315488813891585,
+		Table:    &model.TableName{Schema: "test", Table: "t", TableID: 100},
+		ColInfos: colInfos,
+		Columns:  columns,
+	}
+}
+
+// encodeRenderingTestRow encodes the row by the protocol, and returns the
+// column values decoded from the message.
+func encodeRenderingTestRow(
+	t *testing.T, protocol config.Protocol, cfg *config.TypeRenderingConfig,
+) map[string]interface{} {
+	ctx := contextutil.PutTimezoneInCtx(context.Background(), time.FixedZone("CST", 8*3600))
+	codecConfig := NewConfig(protocol)
+	codecConfig.typeRendering = cfg
+	require.Nil(t, codecConfig.Validate())
+	builder, err := NewEventBatchEncoderBuilder(ctx, codecConfig)
+	require.Nil(t, err)
+	encoder := builder.Build()
+	require.Nil(t, encoder.AppendRowChangedEvent(ctx, "", newTypeRenderingTestRow(), nil))
+	msgs := encoder.Build()
+	require.Len(t, msgs, 1)
+	decodeRenderingTestMessage(t, protocol, msgs[0])
+
+	value := msgs[0].Value
+	if protocol == config.ProtocolOpen {
+		// skip the length of the value
+		value = value[8:]
+	}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	values := make(map[string]interface{})
+	switch protocol {
+	case config.ProtocolOpen:
+		msg := &struct {
+			Update map[string]struct {
+				Value interface{} `json:"v"`
+			} `json:"u"`
+		}{}
+		require.Nil(t, decoder.Decode(msg))
+		for name, col := range msg.Update {
+			values[name] = col.Value
+		}
+	case config.ProtocolCanalJSON:
+		msg := &struct {
+			Data []map[string]interface{} `json:"data"`
+		}{}
+		require.Nil(t, decoder.Decode(msg))
+		values = msg.Data[0]
+	case config.ProtocolMaxwell:
+		msg := &struct {
+			Data map[string]interface{} `json:"data"`
+		}{}
+		require.Nil(t, decoder.Decode(msg))
+		values = msg.Data
+	}
+	require.Len(t, values, 8)
+	return values
+}
+
+// decodeRenderingTestMessage decodes the message by the decoder of the
+// protocol, the rendered values must be accepted by the decoder.
+func decodeRenderingTestMessage(t *testing.T, protocol config.Protocol, msg *MQMessage) {
+	var decoder EventBatchDecoder
+	switch protocol {
+	case config.ProtocolOpen:
+		var err error
+		decoder, err = NewOpenProtocolBatchDecoder(msg.Key, msg.Value)
+		require.Nil(t, err)
+	case config.ProtocolCanalJSON:
+		decoder = NewCanalJSONBatchDecoder(msg.Value, false)
+	default:
+		// there is no decoder of the protocol in the repo.
+		return
+	}
+	tp, hasNext, err := decoder.HasNext()
+	require.Nil(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, tp)
+	row, err := decoder.NextRowChangedEvent()
+	require.Nil(t, err)
+	require.Len(t, row.Columns, 8)
+}
+
+func epochMillis(t *testing.T, value string) json.Number {
+	ts, err := time.Parse("2006-01-02 15:04:05.999", value)
+	require.Nil(t, err)
+	return json.Number(strconv.FormatInt(ts.UnixMilli(), 10))
+}
+
+func TestTypeRenderingCompatibility(t *testing.T) {
+	t.Parallel()
+
+	protocols := []config.Protocol{
+		config.ProtocolOpen,
+		config.ProtocolCanalJSON,
+		config.ProtocolMaxwell,
+	}
+	testCases := []struct {
+		name     string
+		cfg      *config.TypeRenderingConfig
+		expected map[string]interface{}
+	}{
+		{
+			name: "number-utc-iso-hex-value",
+			cfg: &config.TypeRenderingConfig{
+				Decimal:         config.DecimalRenderingNumber,
+				TimestampZone:   config.TimestampZoneUTC,
+				TimestampFormat: config.TimestampFormatISO,
+				Binary:          config.BinaryRenderingHex,
+				Enum:            config.EnumRenderingValue,
+			},
+			expected: map[string]interface{}{
+				"d":  json.Number("3.14"),
+				"ts": "2022-07-01T00:00:00.123Z",
+				"dt": "2022-07-01T08:00:00",
+				"da": "2022-07-01",
+				"b":  "01ff",
+				"e":  "b",
+				"s":  "a,c",
+			},
+		},
+		{
+			name: "string-local-iso-base64-index",
+			cfg: &config.TypeRenderingConfig{
+				Decimal:         config.DecimalRenderingString,
+				TimestampZone:   config.TimestampZoneLocal,
+				TimestampFormat: config.TimestampFormatISO,
+				Binary:          config.BinaryRenderingBase64,
+				Enum:            config.EnumRenderingIndex,
+			},
+			expected: map[string]interface{}{
+				"d":  "3.14",
+				"ts": "2022-07-01T08:00:00.123+08:00",
+				"b":  "Af8=",
+				"e":  json.Number("2"),
+				"s":  json.Number("5"),
+			},
+		},
+		{
+			name: "epoch",
+			cfg:  &config.TypeRenderingConfig{TimestampFormat: config.TimestampFormatEpoch},
+			expected: map[string]interface{}{
+				"ts": epochMillis(t, "2022-07-01 00:00:00.123"),
+				"dt": epochMillis(t, "2022-07-01 08:00:00"),
+				"da": epochMillis(t, "2022-07-01 00:00:00"),
+			},
+		},
+		{
+			name: "utc",
+			cfg:  &config.TypeRenderingConfig{TimestampZone: config.TimestampZoneUTC},
+			expected: map[string]interface{}{
+				"ts": "2022-07-01 00:00:00.123",
+				"dt": "2022-07-01 08:00:00",
+				"da": "2022-07-01",
+			},
+		},
+	}
+
+	for _, protocol := range protocols {
+		// An empty policy keeps the rendering of the protocol.
+		require.Equal(t,
+			encodeRenderingTestRow(t, protocol, nil),
+			encodeRenderingTestRow(t, protocol, &config.TypeRenderingConfig{}),
+			protocol.String())
+		for _, tc := range testCases {
+			values := encodeRenderingTestRow(t, protocol, tc.cfg)
+			for name, expected := range tc.expected {
+				// canal-json only carries string values.
+				if n, ok := expected.(json.Number); ok && protocol == config.ProtocolCanalJSON {
+					expected = string(n)
+				}
+				require.Equal(t, expected, values[name], "%s %s %s", protocol, tc.name, name)
+			}
+		}
+	}
+}
+
+func TestTypeRenderingUnsupportedProtocol(t *testing.T) {
+	t.Parallel()
+
+	for _, protocol := range []config.Protocol{
+		config.ProtocolCanal, config.ProtocolAvro, config.ProtocolCraft,
+	} {
+		codecConfig := NewConfig(protocol)
+		codecConfig.avroSchemaRegistry = "http://127.0.0.1:8081"
+		codecConfig.typeRendering = &config.TypeRenderingConfig{Enum: config.EnumRenderingValue}
+		require.Regexp(t, ".*type-rendering only supports.*", codecConfig.Validate())
+	}
+}
+
+func TestTypeRendererEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newTypeRenderer(nil, nil))
+	require.Nil(t, newTypeRenderer(&config.TypeRenderingConfig{}, nil))
+
+	r := newTypeRenderer(&config.TypeRenderingConfig{
+		TimestampFormat: config.TimestampFormatISO,
+		Binary:          config.BinaryRenderingHex,
+		Enum:            config.EnumRenderingValue,
+	}, time.UTC)
+	// zero dates are rendered by the protocol
+	_, ok := r.render(&model.Column{Type: mysql.TypeDatetime, Value: "0000-00-00 00:00:00"}, nil)
+	require.False(t, ok)
+	// text columns are not binary
+	_, ok = r.render(&model.Column{Type: mysql.TypeBlob, Value: []byte("a")}, nil)
+	require.False(t, ok)
+	// enum values can not be rendered without the field type
+	_, ok = r.render(&model.Column{Type: mysql.TypeEnum, Value: uint64(1)}, nil)
+	require.False(t, ok)
+	// the invalid enum value
+	ft := types.NewFieldType(mysql.TypeEnum)
+	ft.SetElems([]string{"a"})
+	v, ok := r.render(&model.Column{Type: mysql.TypeEnum, Value: uint64(0)}, ft)
+	require.True(t, ok)
+	require.Equal(t, "", v)
+	v, ok = r.render(&model.Column{Type: mysql.TypeDatetime, Value: "2022-07-01 08:00:00.500000"}, nil)
+	require.True(t, ok)
+	require.Equal(t, "2022-07-01T08:00:00.5", v)
+}
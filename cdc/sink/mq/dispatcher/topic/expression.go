@@ -35,6 +35,8 @@ var (
 	avroTopicNameRE = regexp.MustCompile(
 		`^[A-Za-z0-9\._\-]*\{schema\}[A-Za-z0-9\._\-]*\{table\}[A-Za-z0-9\._\-]*$`,
 	)
+	// quotedPlaceholderRE is used to match '{schema}' and '{table}' in a quoted topic expression
+	quotedPlaceholderRE = regexp.MustCompile(`\\\{(schema|table)\\\}`)
 )
 
 // The max length of kafka topic name is 249.
@@ -94,3 +96,12 @@ func (e Expression) Substitute(schema, table string) string {
 		return topicName
 	}
 }
+
+// Match checks whether the topic name can be generated by the topic expression.
+// An expression without '{schema}' and '{table}' only matches the topic with the same name.
+func (e Expression) Match(topic string) bool {
+	pattern := quotedPlaceholderRE.ReplaceAllString(
+		regexp.QuoteMeta(string(e)), `[A-Za-z0-9\._\-]+`)
+	// The quoted expression is always a valid regular expression.
+	return regexp.MustCompile("^" + pattern + "$").MatchString(topic)
+}
@@ -263,3 +263,28 @@ func BenchmarkSubstitute(b *testing.B) {
 		})
 	}
 }
+
+func TestMatchTopicExpression(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expression string
+		topic      string
+		match      bool
+	}{
+		{expression: "{schema}_{table}", topic: "db_tbl", match: true},
+		{expression: "{schema}_{table}", topic: "db", match: false},
+		{expression: "hello_{schema}", topic: "hello_db", match: true},
+		{expression: "hello_{schema}", topic: "hello_", match: false},
+		{expression: "hello_{schema}", topic: "world_db", match: false},
+		{expression: "{schema}.{table}", topic: "db.tbl", match: true},
+		{expression: "{schema}.{table}", topic: "db_tbl", match: false},
+		{expression: "test", topic: "test", match: true},
+		{expression: "test", topic: "test1", match: false},
+		{expression: "a.b", topic: "axb", match: false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.match, Expression(c.expression).Match(c.topic),
+			"expression: %s, topic: %s", c.expression, c.topic)
+	}
+}
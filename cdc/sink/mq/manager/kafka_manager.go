@@ -17,12 +17,14 @@ import (
 	"context"
 	gerrors "errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher/topic"
 	kafkaconfig "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/kafka"
@@ -38,8 +40,14 @@ type kafkaTopicManager struct {
 
 	cfg *kafkaconfig.AutoCreateTopicConfig
 
+	// topics holds the partition numbers used to dispatch the events.
 	topics sync.Map
 
+	// pendingPartitions holds the partition numbers which have been changed
+	// but are not applied yet, see ApplyPartitionNum.
+	pendingMu         sync.Mutex
+	pendingPartitions map[string]int32
+
 	lastMetadataRefresh atomic.Int64
 }
 
@@ -49,10 +57,19 @@ func NewKafkaTopicManager(
 	admin kafka.ClusterAdminClient,
 	cfg *kafkaconfig.AutoCreateTopicConfig,
 ) (*kafkaTopicManager, error) {
+	for _, template := range cfg.Templates {
+		if strings.Contains(template.Topic, "{") {
+			if err := topic.Expression(template.Topic).Validate(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+
 	mgr := &kafkaTopicManager{
-		client: client,
-		admin:  admin,
-		cfg:    cfg,
+		client:            client,
+		admin:             admin,
+		cfg:               cfg,
+		pendingPartitions: make(map[string]int32),
 	}
 
 	// do an initial metadata fetching using ListTopics
@@ -105,14 +122,56 @@ func (m *kafkaTopicManager) tryRefreshMeta() error {
 	return nil
 }
 
+// GetPendingPartitionNums returns the new partition numbers of the topics
+// which have been changed but are not applied yet.
+func (m *kafkaTopicManager) GetPendingPartitionNums() map[string]int32 {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if len(m.pendingPartitions) == 0 {
+		return nil
+	}
+	result := make(map[string]int32, len(m.pendingPartitions))
+	for topic, partitions := range m.pendingPartitions {
+		result[topic] = partitions
+	}
+	return result
+}
+
+// ApplyPartitionNum makes GetPartitionNum return the new partition number of the topic.
+func (m *kafkaTopicManager) ApplyPartitionNum(topic string, partitionNum int32) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if pending, ok := m.pendingPartitions[topic]; ok && pending == partitionNum {
+		delete(m.pendingPartitions, topic)
+	}
+	oldPartitions, _ := m.topics.Load(topic)
+	m.topics.Store(topic, partitionNum)
+	log.Info(
+		"apply topic partition number",
+		zap.String("topic", topic),
+		zap.Any("oldPartitionNumber", oldPartitions),
+		zap.Int32("newPartitionNumber", partitionNum),
+	)
+}
+
 // tryUpdatePartitionsAndLogging try to update the partitions of the topic.
+// The partition number of a known topic is not updated immediately, since
+// dispatching the events with a new partition number may break the order
+// of the events of a key, it's applied at a changefeed-wide resolved ts barrier
+// driven by the owner.
 func (m *kafkaTopicManager) tryUpdatePartitionsAndLogging(topic string, partitions int32) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
 	oldPartitions, ok := m.topics.Load(topic)
 	if ok {
-		if oldPartitions.(int32) != partitions {
-			m.topics.Store(topic, partitions)
+		if oldPartitions.(int32) == partitions {
+			delete(m.pendingPartitions, topic)
+			return
+		}
+		if pending, ok := m.pendingPartitions[topic]; !ok || pending != partitions {
+			m.pendingPartitions[topic] = partitions
 			log.Info(
-				"update topic partition number",
+				"topic partition number changed, wait for applying",
 				zap.String("topic", topic),
 				zap.Int32("oldPartitionNumber", oldPartitions.(int32)),
 				zap.Int32("newPartitionNumber", partitions),
@@ -274,16 +333,14 @@ func (m *kafkaTopicManager) createTopic(topicName string) (int32, error) {
 	}
 
 	start := time.Now()
-	err = m.admin.CreateTopic(topicName, &sarama.TopicDetail{
-		NumPartitions:     m.cfg.PartitionNum,
-		ReplicationFactor: m.cfg.ReplicationFactor,
-	}, false)
+	detail := m.topicDetail(topicName)
+	err = m.admin.CreateTopic(topicName, detail, false)
 	if err != nil && gerrors.Is(err, sarama.ErrTopicAlreadyExists) {
 		log.Error(
 			"Kafka admin client create the topic failed",
 			zap.String("topic", topicName),
-			zap.Int32("partitionNumber", m.cfg.PartitionNum),
-			zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+			zap.Int32("partitionNumber", detail.NumPartitions),
+			zap.Int16("replicationFactor", detail.ReplicationFactor),
 			zap.Error(err),
 			zap.Duration("duration", time.Since(start)),
 		)
@@ -293,13 +350,44 @@ func (m *kafkaTopicManager) createTopic(topicName string) (int32, error) {
 	log.Info(
 		"Kafka admin client create the topic success",
 		zap.String("topic", topicName),
-		zap.Int32("partitionNumber", m.cfg.PartitionNum),
-		zap.Int16("replicationFactor", m.cfg.ReplicationFactor),
+		zap.Int32("partitionNumber", detail.NumPartitions),
+		zap.Int16("replicationFactor", detail.ReplicationFactor),
+		zap.Int("topicConfigs", len(detail.ConfigEntries)),
 		zap.Duration("duration", time.Since(start)),
 	)
-	m.tryUpdatePartitionsAndLogging(topicName, m.cfg.PartitionNum)
+	m.tryUpdatePartitionsAndLogging(topicName, detail.NumPartitions)
+
+	return detail.NumPartitions, nil
+}
 
-	return m.cfg.PartitionNum, nil
+// topicDetail returns the detail used to create the topic. The first topic
+// template matching the topic takes effect, and the partition number and
+// the replication factor in the sink uri are used if they are not specified.
+func (m *kafkaTopicManager) topicDetail(topicName string) *sarama.TopicDetail {
+	detail := &sarama.TopicDetail{
+		NumPartitions:     m.cfg.PartitionNum,
+		ReplicationFactor: m.cfg.ReplicationFactor,
+	}
+	for _, template := range m.cfg.Templates {
+		if !topic.Expression(template.Topic).Match(topicName) {
+			continue
+		}
+		if template.PartitionNum > 0 {
+			detail.NumPartitions = template.PartitionNum
+		}
+		if template.ReplicationFactor > 0 {
+			detail.ReplicationFactor = template.ReplicationFactor
+		}
+		if len(template.TopicConfigs) > 0 {
+			detail.ConfigEntries = make(map[string]*string, len(template.TopicConfigs))
+			for name, value := range template.TopicConfigs {
+				value := value
+				detail.ConfigEntries[name] = &value
+			}
+		}
+		break
+	}
+	return detail
 }
 
 // CreateTopicAndWaitUntilVisible wraps createTopic and waitUntilTopicVisible together.
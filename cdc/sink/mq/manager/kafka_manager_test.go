@@ -18,6 +18,7 @@ import (
 	"time"
 
 	kafkaconfig "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
+	"github.com/pingcap/tiflow/pkg/config"
 	kafkamock "github.com/pingcap/tiflow/pkg/kafka"
 	"github.com/stretchr/testify/require"
 )
@@ -80,6 +81,56 @@ func TestTryRefreshMeta(t *testing.T) {
 	require.Equal(t, int32(4), partitionsNum)
 }
 
+func TestApplyPartitionNum(t *testing.T) {
+	t.Parallel()
+
+	client := kafkamock.NewClientMockImpl()
+	adminClient := kafkamock.NewClusterAdminClientMockImpl()
+	defer func(adminClient *kafkamock.ClusterAdminClientMockImpl) {
+		_ = adminClient.Close()
+	}(adminClient)
+	cfg := &kafkaconfig.AutoCreateTopicConfig{
+		AutoCreate:        true,
+		PartitionNum:      2,
+		ReplicationFactor: 1,
+	}
+
+	manager, err := NewKafkaTopicManager(client, adminClient, cfg)
+	require.Nil(t, err)
+	client.AddTopic("test", 4)
+	manager.lastMetadataRefresh.Store(time.Now().Add(-2 * time.Minute).Unix())
+	partitionsNum, err := manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionsNum)
+	require.Nil(t, manager.GetPendingPartitionNums())
+
+	// The changed partition number is pending until it's applied.
+	client.AddTopic("test", 6)
+	manager.lastMetadataRefresh.Store(time.Now().Add(-2 * time.Minute).Unix())
+	partitionsNum, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionsNum)
+	require.Equal(t, map[string]int32{"test": 6}, manager.GetPendingPartitionNums())
+
+	manager.ApplyPartitionNum("test", 6)
+	require.Nil(t, manager.GetPendingPartitionNums())
+	partitionsNum, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(6), partitionsNum)
+
+	// The pending partition number is dropped if it's changed back.
+	client.AddTopic("test", 8)
+	manager.lastMetadataRefresh.Store(time.Now().Add(-2 * time.Minute).Unix())
+	_, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, map[string]int32{"test": 8}, manager.GetPendingPartitionNums())
+	client.AddTopic("test", 6)
+	manager.lastMetadataRefresh.Store(time.Now().Add(-2 * time.Minute).Unix())
+	_, err = manager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Nil(t, manager.GetPendingPartitionNums())
+}
+
 func TestCreateTopic(t *testing.T) {
 	t.Parallel()
 
@@ -143,3 +194,59 @@ func TestCreateTopicWithDelay(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, int32(2), partitionNum)
 }
+
+func TestCreateTopicWithTemplates(t *testing.T) {
+	t.Parallel()
+
+	client := kafkamock.NewClientMockImpl()
+	adminClient := kafkamock.NewClusterAdminClientMockImpl()
+	defer func(adminClient *kafkamock.ClusterAdminClientMockImpl) {
+		_ = adminClient.Close()
+	}(adminClient)
+	cfg := &kafkaconfig.AutoCreateTopicConfig{
+		AutoCreate:        true,
+		PartitionNum:      2,
+		ReplicationFactor: 1,
+		Templates: []*config.TopicTemplate{
+			{
+				Topic:        "hot_{schema}_orders",
+				PartitionNum: 8,
+				TopicConfigs: map[string]string{
+					"retention.ms":   "86400000",
+					"cleanup.policy": "compact",
+				},
+			},
+			{
+				Topic:        "hot_{schema}_{table}",
+				PartitionNum: 4,
+			},
+		},
+	}
+
+	manager, err := NewKafkaTopicManager(client, adminClient, cfg)
+	require.Nil(t, err)
+
+	partitionNum, err := manager.createTopic("hot_test_orders")
+	require.Nil(t, err)
+	require.Equal(t, int32(8), partitionNum)
+	partitionNum, err = manager.createTopic("hot_test_users")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+	partitionNum, err = manager.createTopic("cold_test_users")
+	require.Nil(t, err)
+	require.Equal(t, int32(2), partitionNum)
+
+	topics, err := adminClient.ListTopics()
+	require.Nil(t, err)
+	detail := topics["hot_test_orders"]
+	require.Equal(t, int16(1), detail.ReplicationFactor)
+	require.Len(t, detail.ConfigEntries, 2)
+	require.Equal(t, "86400000", *detail.ConfigEntries["retention.ms"])
+	require.Equal(t, "compact", *detail.ConfigEntries["cleanup.policy"])
+	require.Empty(t, topics["hot_test_users"].ConfigEntries)
+
+	// The template with an invalid topic expression is rejected.
+	cfg.Templates = []*config.TopicTemplate{{Topic: "{table}_hot"}}
+	_, err = NewKafkaTopicManager(client, adminClient, cfg)
+	require.Regexp(t, ".*invalid topic expression.*", err)
+}
@@ -21,4 +21,11 @@ type TopicManager interface {
 	GetPartitionNum(topic string) (int32, error)
 	// CreateTopicAndWaitUntilVisible creates the topic and wait for the topic completion.
 	CreateTopicAndWaitUntilVisible(topicName string) (int32, error)
+	// GetPendingPartitionNums returns the new partition numbers of the topics
+	// which have been changed but are not applied yet.
+	GetPendingPartitionNums() map[string]int32
+	// ApplyPartitionNum makes GetPartitionNum return the new partition number
+	// of the topic. It should be called when all the events dispatched with
+	// the old partition number have been acknowledged.
+	ApplyPartitionNum(topic string, partitionNum int32)
 }
@@ -13,28 +13,42 @@
 
 package manager
 
-// pulsarTopicManager is the interface
-// that wraps the basic Pulsar topic management operations.
-// Right now it doesn't have any implementation,
-// Pulsar doesn't support multiple topics yet.
-// So it now just returns a fixed number of partitions for a fixed topic.
+// partitionNumGetter gets the partition number of a topic.
+type partitionNumGetter interface {
+	GetPartitionNum(topic string) (int32, error)
+}
+
+// pulsarTopicManager wraps the basic Pulsar topic management operations.
+// Pulsar creates the topics automatically when they are written, and
+// the producer knows the partition number of each topic, so the manager
+// just delegates to it.
 type pulsarTopicManager struct {
-	partitionNum int32
+	producer partitionNumGetter
 }
 
 // NewPulsarTopicManager creates a new TopicManager.
-func NewPulsarTopicManager(partitionNum int32) *pulsarTopicManager {
+func NewPulsarTopicManager(producer partitionNumGetter) *pulsarTopicManager {
 	return &pulsarTopicManager{
-		partitionNum: partitionNum,
+		producer: producer,
 	}
 }
 
 // GetPartitionNum returns the number of partitions of the topic.
-func (m *pulsarTopicManager) GetPartitionNum(_ string) (int32, error) {
-	return m.partitionNum, nil
+func (m *pulsarTopicManager) GetPartitionNum(topic string) (int32, error) {
+	return m.producer.GetPartitionNum(topic)
 }
 
-// CreateTopic do nothing.
-func (m *pulsarTopicManager) CreateTopicAndWaitUntilVisible(_ string) (int32, error) {
-	return m.partitionNum, nil
+// CreateTopicAndWaitUntilVisible creates the producer of the topic,
+// which makes the broker create the topic if it does not exist.
+func (m *pulsarTopicManager) CreateTopicAndWaitUntilVisible(topic string) (int32, error) {
+	return m.producer.GetPartitionNum(topic)
 }
+
+// GetPendingPartitionNums always returns nil, since the partition number
+// of a Pulsar topic is taken from the producer directly.
+func (m *pulsarTopicManager) GetPendingPartitionNums() map[string]int32 {
+	return nil
+}
+
+// ApplyPartitionNum is a no-op for Pulsar.
+func (m *pulsarTopicManager) ApplyPartitionNum(_ string, _ int32) {}
@@ -39,6 +39,33 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// PartitionNumsApplier is implemented by the sinks which dispatch the row
+// changed events by the partition numbers of the topics.
+type PartitionNumsApplier interface {
+	// ApplyPartitionNums makes the sink dispatch the events of the topics
+	// with the given partition numbers.
+	ApplyPartitionNums(partitionNums map[string]int32)
+}
+
+// PartitionChangeSink is implemented by the MQ sink of the owner.
+//
+// The partition number of a topic is changed at a changefeed-wide resolved ts
+// barrier. The owner finds the changes by GetPartitionChanges, and waits for
+// the checkpoint ts of the changefeed to reach the barrier, which means that
+// all the events dispatched with the old partition numbers by all the
+// processors have been acknowledged. Then it emits the change by
+// EmitPartitionChange, and publishes the new partition numbers in the
+// changefeed status, which are applied by the processors before they
+// dispatch the events after the barrier.
+type PartitionChangeSink interface {
+	PartitionNumsApplier
+	// GetPartitionChanges returns the partition numbers used to dispatch the
+	// events and the new partition numbers of the changed topics.
+	GetPartitionChanges() (oldPartitionNums, newPartitionNums map[string]int32, err error)
+	// EmitPartitionChange applies the new partition numbers at the resolved ts.
+	EmitPartitionChange(ctx context.Context, ts uint64, partitionNums map[string]int32) error
+}
+
 type resolvedTsEvent struct {
 	tableID  model.TableID
 	resolved model.ResolvedTs
@@ -50,7 +77,14 @@ type mqSink struct {
 	encoderBuilder codec.EncoderBuilder
 	protocol       config.Protocol
 
-	topicManager         manager.TopicManager
+	topicManager manager.TopicManager
+	// dispatchMu makes sure that no event is dispatched
+	// while the partition number of a topic is changing.
+	dispatchMu sync.RWMutex
+	// partitionChangeMarker indicates whether to broadcast the partition
+	// change markers, see EmitPartitionChange.
+	partitionChangeMarker bool
+
 	flushWorker          *flushWorker
 	tableCheckpointTsMap sync.Map
 	resolvedBuffer       *chann.Chann[resolvedTsEvent]
@@ -97,6 +131,8 @@ func newMqSink(
 		statistics:     statistics,
 		role:           role,
 		id:             changefeedID,
+
+		partitionChangeMarker: replicaConfig.Sink.EnablePartitionChangeMarker,
 	}
 
 	go func() {
@@ -133,6 +169,9 @@ func (k *mqSink) AddTable(tableID model.TableID) error {
 // EmitRowChangedEvents emits row changed events to the flush worker by partition.
 // Concurrency Note: This method is thread-safe.
 func (k *mqSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	k.dispatchMu.RLock()
+	defer k.dispatchMu.RUnlock()
+
 	rowsCount := 0
 	for _, row := range rows {
 		topic := k.eventRouter.GetTopicForRowChange(row)
@@ -229,6 +268,74 @@ func (k *mqSink) flushTsToWorker(ctx context.Context, resolvedTs model.ResolvedT
 	return nil
 }
 
+// GetPartitionChanges returns the partition numbers used to dispatch the
+// events and the new partition numbers of the topics which have been changed
+// but are not applied yet.
+func (k *mqSink) GetPartitionChanges() (oldPartitionNums, newPartitionNums map[string]int32, err error) {
+	newPartitionNums = k.topicManager.GetPendingPartitionNums()
+	if len(newPartitionNums) == 0 {
+		return nil, nil, nil
+	}
+	oldPartitionNums = make(map[string]int32, len(newPartitionNums))
+	for topic := range newPartitionNums {
+		oldPartitionNums[topic], err = k.topicManager.GetPartitionNum(topic)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return oldPartitionNums, newPartitionNums, nil
+}
+
+// ApplyPartitionNums makes the sink dispatch the events of the topics with
+// the given partition numbers.
+func (k *mqSink) ApplyPartitionNums(partitionNums map[string]int32) {
+	k.dispatchMu.Lock()
+	defer k.dispatchMu.Unlock()
+	for topic, partitionNum := range partitionNums {
+		k.topicManager.ApplyPartitionNum(topic, partitionNum)
+	}
+}
+
+// EmitPartitionChange applies the changed partition numbers of the topics at
+// the resolved ts ts. It must be called when all the events dispatched with
+// the old partition numbers have been acknowledged. If the partition change
+// marker is enabled, a marker is sent to all the partitions of the topic,
+// which tells the consumers from which offset the events are dispatched with
+// the new partition number.
+func (k *mqSink) EmitPartitionChange(
+	ctx context.Context, ts uint64, partitionNums map[string]int32,
+) error {
+	for topic, partitionNum := range partitionNums {
+		if k.partitionChangeMarker {
+			oldPartitionNum, err := k.topicManager.GetPartitionNum(topic)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			msg, err := codec.NewPartitionChangeMarkerMessage(&codec.PartitionChangeMarker{
+				Ts:              ts,
+				OldPartitionNum: oldPartitionNum,
+				PartitionNum:    partitionNum,
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = k.mqProducer.SyncBroadcastMessage(ctx, topic, partitionNum, msg)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		log.Info("MQ sink emitted the partition change",
+			zap.String("namespace", k.id.Namespace),
+			zap.String("changefeed", k.id.ID),
+			zap.Any("role", k.role),
+			zap.String("topic", topic),
+			zap.Int32("partitionNum", partitionNum),
+			zap.Uint64("ts", ts))
+	}
+	k.ApplyPartitionNums(partitionNums)
+	return nil
+}
+
 // EmitCheckpointTs emits the checkpointTs to
 // default topic or the topics of all tables.
 // Concurrency Note: EmitCheckpointTs is thread-safe.
@@ -430,10 +537,12 @@ func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
 	}
 
+	topicCfg := baseConfig.DeriveTopicConfig()
+	topicCfg.Templates = replicaConfig.Sink.TopicTemplates
 	topicManager, err := manager.NewKafkaTopicManager(
 		client,
 		adminClient,
-		baseConfig.DeriveTopicConfig(),
+		topicCfg,
 	)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
@@ -470,6 +579,38 @@ func NewKafkaSaramaSink(ctx context.Context, sinkURI *url.URL,
 	return sink, nil
 }
 
+// CheckKafkaReachable checks whether the Kafka cluster of the sink URI is
+// reachable by fetching the metadata of the topic, the topic is not created.
+func CheckKafkaReachable(ctx context.Context, sinkURI *url.URL) error {
+	topic := strings.TrimFunc(sinkURI.Path, func(r rune) bool {
+		return r == '/'
+	})
+	if topic == "" {
+		return cerror.ErrKafkaInvalidConfig.GenWithStack("no topic is specified in sink-uri")
+	}
+
+	baseConfig := kafka.NewConfig()
+	if err := baseConfig.Apply(sinkURI); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+	}
+
+	saramaConfig, err := kafka.NewSaramaConfig(ctx, baseConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	adminClient, err := kafka.NewAdminClientImpl(baseConfig.BrokerEndpoints, saramaConfig)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
+	}
+	defer adminClient.Close()
+
+	if err := kafka.AdjustConfig(adminClient, baseConfig, saramaConfig, topic); err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
+	}
+	return nil
+}
+
 // NewPulsarSink creates a new Pulsar mqSink.
 func NewPulsarSink(ctx context.Context, sinkURI *url.URL,
 	replicaConfig *config.ReplicaConfig, errCh chan error,
@@ -498,14 +639,12 @@ func NewPulsarSink(ctx context.Context, sinkURI *url.URL,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	fakeTopicManager := manager.NewPulsarTopicManager(
-		producer.GetPartitionNum(),
-	)
+	topicManager := manager.NewPulsarTopicManager(producer)
 	sink, err := newMqSink(
 		ctx,
-		fakeTopicManager,
+		topicManager,
 		producer,
-		"",
+		producer.DefaultTopic(),
 		replicaConfig,
 		encoderConfig,
 		errCh,
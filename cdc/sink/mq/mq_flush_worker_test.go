@@ -31,6 +31,7 @@ import (
 
 type mockProducer struct {
 	mqEvent      map[TopicPartitionKey][]*codec.MQMessage
+	broadcasts   map[string][]*codec.MQMessage
 	flushedTimes int
 
 	mockErr chan error
@@ -59,7 +60,10 @@ func (m *mockProducer) AsyncSendMessage(
 func (m *mockProducer) SyncBroadcastMessage(
 	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
-	panic("Not used")
+	for i := int32(0); i < partitionsNum; i++ {
+		m.broadcasts[topic] = append(m.broadcasts[topic], message)
+	}
+	return nil
 }
 
 func (m *mockProducer) Flush(ctx context.Context) error {
@@ -68,7 +72,7 @@ func (m *mockProducer) Flush(ctx context.Context) error {
 }
 
 func (m *mockProducer) Close() error {
-	panic("Not used")
+	return nil
 }
 
 func (m *mockProducer) InjectError(err error) {
@@ -77,8 +81,9 @@ func (m *mockProducer) InjectError(err error) {
 
 func NewMockProducer() *mockProducer {
 	return &mockProducer{
-		mqEvent: make(map[TopicPartitionKey][]*codec.MQMessage),
-		mockErr: make(chan error, 1),
+		mqEvent:    make(map[TopicPartitionKey][]*codec.MQMessage),
+		broadcasts: make(map[string][]*codec.MQMessage),
+		mockErr:    make(chan error, 1),
 	}
 }
 
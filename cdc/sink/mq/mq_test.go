@@ -17,15 +17,19 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/Shopify/sarama"
+	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pingcap/errors"
-	"github.com/pingcap/failpoint"
+	timodel "github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
 	kafkap "github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
+	pulsarp "github.com/pingcap/tiflow/cdc/sink/mq/producer/pulsar"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/kafka"
 	"github.com/pingcap/tiflow/pkg/retry"
@@ -165,12 +169,37 @@ func TestKafkaSink(t *testing.T) {
 	}
 }
 
+func TestCheckKafkaReachable(t *testing.T) {
+	ctx := context.Background()
+	kafkap.NewAdminClientImpl = kafka.NewMockAdminClient
+	defer func() {
+		kafkap.NewAdminClientImpl = kafka.NewSaramaAdminClient
+	}()
+
+	uriTemplate := "kafka://127.0.0.1:9092/%s?kafka-version=0.9.0.0" +
+		"&partition-num=%d&auto-create-topic=false&protocol=open-protocol"
+	sinkURI, err := url.Parse(fmt.Sprintf(uriTemplate, kafka.DefaultMockTopicName, 1))
+	require.Nil(t, err)
+	require.Nil(t, CheckKafkaReachable(ctx, sinkURI))
+
+	// the partition number is larger than the existing topic's
+	sinkURI, err = url.Parse(fmt.Sprintf(uriTemplate, kafka.DefaultMockTopicName, 100))
+	require.Nil(t, err)
+	require.Regexp(t, ".*ErrKafkaNewSaramaProducer.*", CheckKafkaReachable(ctx, sinkURI))
+
+	// no topic is specified
+	sinkURI, err = url.Parse("kafka://127.0.0.1:9092/?kafka-version=0.9.0.0")
+	require.Nil(t, err)
+	require.Regexp(t, ".*ErrKafkaInvalidConfig.*", CheckKafkaReachable(ctx, sinkURI))
+}
+
 func TestPulsarSinkEncoderConfig(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	err := failpoint.Enable("github.com/pingcap/tiflow/cdc/sink/mq/producer/pulsar/MockPulsar",
-		"return(true)")
-	require.Nil(t, err)
+	pulsarp.NewClientImpl = pulsarp.NewMockClientImpl
+	defer func() {
+		pulsarp.NewClientImpl = pulsar.NewClient
+	}()
 
 	uri := "pulsar://127.0.0.1:1234/kafka-test?" +
 		"max-message-bytes=4194304&max-batch-size=1&protocol=open-protocol"
@@ -188,12 +217,96 @@ func TestPulsarSinkEncoderConfig(t *testing.T) {
 	require.Equal(t, 1, encoder.(*codec.OpenProtocolBatchEncoder).GetMaxBatchSize())
 	require.Equal(t, 4194304, encoder.(*codec.OpenProtocolBatchEncoder).GetMaxMessageBytes())
 
-	// FIXME: mock pulsar client doesn't support close,
-	// so we can't call sink.Close() to close it.
-	// We will leak goroutine if we don't close it.
 	cancel()
-	sink.flushWorker.close()
-	sink.resolvedBuffer.Close()
+	err = sink.Close(ctx)
+	if err != nil {
+		require.Equal(t, context.Canceled, errors.Cause(err))
+	}
+}
+
+func TestPulsarSinkTopicDispatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := pulsarp.NewMockClient(pulsarp.DefaultMockPartitionNum)
+	pulsarp.NewClientImpl = func(_ pulsar.ClientOptions) (pulsar.Client, error) {
+		return client, nil
+	}
+	defer func() {
+		pulsarp.NewClientImpl = pulsar.NewClient
+	}()
+
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:1234/default-topic?protocol=open-protocol")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.*"}, TopicRule: "{schema}_{table}", PartitionRule: "table"},
+	}
+	require.Nil(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	errCh := make(chan error, 1)
+
+	sink, err := NewPulsarSink(ctx, sinkURI, replicaConfig, errCh)
+	require.Nil(t, err)
+
+	row := &model.RowChangedEvent{
+		Table:    &model.TableName{Schema: "test", Table: "t1", TableID: 1},
+		StartTs:  100,
+		CommitTs: 120,
+		Columns: []*model.Column{{
+			Name:  "col1",
+			Type:  mysql.TypeVarchar,
+			Value: []byte("aa"),
+		}},
+	}
+	require.Nil(t, sink.EmitRowChangedEvents(ctx, row))
+	_, err = sink.FlushRowChangedEvents(ctx, 1, model.NewResolvedTs(row.CommitTs))
+	require.Nil(t, err)
+	waitCheckpointTs(t, sink, 1, row.CommitTs)
+
+	// The row is dispatched to the table's topic.
+	messages := client.Messages("test_t1")
+	require.Len(t, messages, 1)
+	require.Equal(t, strconv.Itoa(int(model.MessageTypeRow)), messages[0].Properties()["type"])
+	require.NotContains(t, messages[0].Properties(), "$route")
+	partition := messages[0].Topic()
+
+	// Rows of the same table always go to the same partition.
+	row.CommitTs = 130
+	require.Nil(t, sink.EmitRowChangedEvents(ctx, row))
+	_, err = sink.FlushRowChangedEvents(ctx, 1, model.NewResolvedTs(row.CommitTs))
+	require.Nil(t, err)
+	waitCheckpointTs(t, sink, 1, row.CommitTs)
+	messages = client.Messages("test_t1")
+	require.Len(t, messages, 2)
+	require.Equal(t, partition, messages[1].Topic())
+
+	// The checkpoint is broadcast to all the partitions of the active topics.
+	err = sink.EmitCheckpointTs(ctx, 130, []model.TableName{{Schema: "test", Table: "t1"}})
+	require.Nil(t, err)
+	messages = client.Messages("test_t1")
+	require.Len(t, messages, 2+pulsarp.DefaultMockPartitionNum)
+	for _, msg := range messages[2:] {
+		require.Equal(t, strconv.Itoa(int(model.MessageTypeResolved)), msg.Properties()["type"])
+	}
+
+	// DDL events without a matched table are broadcast to the default topic.
+	sent := len(client.Messages("default-topic"))
+	err = sink.EmitDDLEvent(ctx, &model.DDLEvent{
+		CommitTs: 140,
+		Query:    "create database db1",
+		Type:     timodel.ActionCreateSchema,
+		TableInfo: &model.SimpleTableInfo{
+			Schema: "db1",
+		},
+	})
+	require.Nil(t, err)
+	messages = client.Messages("default-topic")
+	require.Len(t, messages, sent+pulsarp.DefaultMockPartitionNum)
+	for _, msg := range messages[sent:] {
+		require.Equal(t, strconv.Itoa(int(model.MessageTypeDDL)), msg.Properties()["type"])
+	}
+
+	require.Nil(t, sink.Close(ctx))
 }
 
 func TestFlushRowChangedEvents(t *testing.T) {
@@ -304,3 +417,129 @@ func TestFlushRowChangedEvents(t *testing.T) {
 		require.Equal(t, context.Canceled, errors.Cause(err))
 	}
 }
+
+type mockTopicManager struct {
+	mu         sync.Mutex
+	partitions map[string]int32
+	pending    map[string]int32
+}
+
+func (m *mockTopicManager) GetPartitionNum(topic string) (int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.partitions[topic], nil
+}
+
+func (m *mockTopicManager) CreateTopicAndWaitUntilVisible(topic string) (int32, error) {
+	return m.GetPartitionNum(topic)
+}
+
+func (m *mockTopicManager) GetPendingPartitionNums() map[string]int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.pending) == 0 {
+		return nil
+	}
+	result := make(map[string]int32, len(m.pending))
+	for topic, partitionNum := range m.pending {
+		result[topic] = partitionNum
+	}
+	return result
+}
+
+func (m *mockTopicManager) ApplyPartitionNum(topic string, partitionNum int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, topic)
+	m.partitions[topic] = partitionNum
+}
+
+func TestEmitPartitionChange(t *testing.T) {
+	for _, enableMarker := range []bool{true, false} {
+		testEmitPartitionChange(t, enableMarker)
+	}
+}
+
+func testEmitPartitionChange(t *testing.T, enableMarker bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topicManager := &mockTopicManager{
+		partitions: map[string]int32{"test": 2},
+		pending:    make(map[string]int32),
+	}
+	producer := NewMockProducer()
+	errCh := make(chan error, 1)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.Sink.EnablePartitionChangeMarker = enableMarker
+	sink, err := newMqSink(ctx, topicManager, producer, "test",
+		replicaConfig, codec.NewConfig(config.ProtocolOpen), errCh)
+	require.Nil(t, err)
+
+	tableID := model.TableID(1)
+	newRow := func(commitTs uint64) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			Table: &model.TableName{
+				Schema:  "test",
+				Table:   "t1",
+				TableID: tableID,
+			},
+			CommitTs: commitTs,
+			Columns: []*model.Column{{
+				Name:  "col1",
+				Type:  mysql.TypeVarchar,
+				Value: []byte("aa"),
+			}},
+		}
+	}
+	require.Nil(t, sink.EmitRowChangedEvents(ctx, newRow(100)))
+
+	// The partition number is changed after the row is dispatched,
+	// it's not applied by the sink itself.
+	topicManager.mu.Lock()
+	topicManager.pending["test"] = 4
+	topicManager.mu.Unlock()
+
+	_, err = sink.FlushRowChangedEvents(ctx, tableID, model.NewResolvedTs(110))
+	require.Nil(t, err)
+	waitCheckpointTs(t, sink, tableID, 110)
+
+	oldPartitionNums, newPartitionNums, err := sink.GetPartitionChanges()
+	require.Nil(t, err)
+	require.Equal(t, map[string]int32{"test": 2}, oldPartitionNums)
+	require.Equal(t, map[string]int32{"test": 4}, newPartitionNums)
+
+	require.Nil(t, sink.EmitPartitionChange(ctx, 110, newPartitionNums))
+	require.Nil(t, topicManager.GetPendingPartitionNums())
+	partitionNum, err := topicManager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+
+	if enableMarker {
+		// The marker is broadcast to all the new partitions.
+		require.Len(t, producer.broadcasts["test"], 4)
+		msg := producer.broadcasts["test"][0]
+		require.Equal(t, "true", string(msg.Headers[codec.PartitionChangeMarkerHeader]))
+		marker, err := codec.DecodePartitionChangeMarker(msg.Value)
+		require.Nil(t, err)
+		require.Equal(t, &codec.PartitionChangeMarker{
+			Ts:              110,
+			OldPartitionNum: 2,
+			PartitionNum:    4,
+		}, marker)
+	} else {
+		// The marker is opt-in.
+		require.Empty(t, producer.broadcasts["test"])
+	}
+
+	// The row dispatched before the barrier is sent with the old partition number.
+	rowsCount := 0
+	for key, msgs := range producer.mqEvent {
+		require.Less(t, key.Partition, int32(2))
+		rowsCount += len(msgs)
+	}
+	require.Equal(t, 1, rowsCount)
+
+	cancel()
+	require.Nil(t, sink.Close(context.Background()))
+}
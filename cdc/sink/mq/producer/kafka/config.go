@@ -183,7 +183,7 @@ func (c *Config) Apply(sinkURI *url.URL) error {
 		c.ReadTimeout = a
 	}
 
-	err := c.applySASL(params)
+	err := c.ApplySASL(params)
 	if err != nil {
 		return err
 	}
@@ -245,7 +245,8 @@ func (c *Config) applyTLS(params url.Values) error {
 	return nil
 }
 
-func (c *Config) applySASL(params url.Values) error {
+// ApplySASL applies the SASL related parameters of the uri to update Config.
+func (c *Config) ApplySASL(params url.Values) error {
 	s := params.Get("sasl-user")
 	if s != "" {
 		c.SASL.SASLUser = s
@@ -313,6 +314,64 @@ func (c *Config) applySASL(params url.Values) error {
 		c.SASL.GSSAPI.DisablePAFXFAST = disablePAFXFAST
 	}
 
+	return c.applySASLOAuth(params)
+}
+
+func (c *Config) applySASLOAuth(params url.Values) error {
+	s := params.Get("sasl-oauth-token-provider")
+	if s != "" {
+		tokenProvider, err := security.OAuthTokenProviderTypeFromString(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+		c.SASL.OAuth.TokenProvider = tokenProvider
+	}
+
+	s = params.Get("sasl-oauth-token")
+	if s != "" {
+		c.SASL.OAuth.Token = s
+	}
+
+	s = params.Get("sasl-oauth-client-id")
+	if s != "" {
+		c.SASL.OAuth.ClientID = s
+	}
+
+	s = params.Get("sasl-oauth-client-secret")
+	if s != "" {
+		c.SASL.OAuth.ClientSecret = s
+	}
+
+	s = params.Get("sasl-oauth-token-url")
+	if s != "" {
+		c.SASL.OAuth.TokenURL = s
+	}
+
+	s = params.Get("sasl-oauth-scopes")
+	if s != "" {
+		c.SASL.OAuth.Scopes = strings.Split(s, ",")
+	}
+
+	s = params.Get("sasl-oauth-audience")
+	if s != "" {
+		c.SASL.OAuth.Audience = s
+	}
+
+	s = params.Get("sasl-oauth-command")
+	if s != "" {
+		c.SASL.OAuth.Command = s
+	}
+
+	s = params.Get("sasl-oauth-aws-region")
+	if s != "" {
+		c.SASL.OAuth.AWSRegion = s
+	}
+
+	if c.SASL.SASLMechanism == security.OAuthMechanism {
+		if err := c.SASL.OAuth.Validate(); err != nil {
+			return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+		}
+	}
 	return nil
 }
 
@@ -321,6 +380,9 @@ type AutoCreateTopicConfig struct {
 	AutoCreate        bool
 	PartitionNum      int32
 	ReplicationFactor int16
+	// Templates are used to create the topics matching them,
+	// the first matched one takes effect.
+	Templates []*config.TopicTemplate
 }
 
 // DeriveTopicConfig derive a `topicConfig` from the `Config`
@@ -428,41 +490,60 @@ func NewSaramaConfig(ctx context.Context, c *Config) (*sarama.Config, error) {
 		}
 	}
 
-	completeSaramaSASLConfig(config, c)
+	err = CompleteSaramaSASLConfig(ctx, config, c.SASL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	return config, err
 }
 
-func completeSaramaSASLConfig(config *sarama.Config, c *Config) {
-	if c.SASL != nil && c.SASL.SASLMechanism != "" {
+// CompleteSaramaSASLConfig sets up the SASL authentication of the sarama config.
+// The OAuth tokens are refreshed in the background until the context is done,
+// so the context should live as long as the clients created by the config.
+func CompleteSaramaSASLConfig(
+	ctx context.Context, config *sarama.Config, sasl *security.SASL,
+) error {
+	if sasl != nil && sasl.SASLMechanism != "" {
 		config.Net.SASL.Enable = true
-		config.Net.SASL.Mechanism = sarama.SASLMechanism(c.SASL.SASLMechanism)
-		switch c.SASL.SASLMechanism {
+		config.Net.SASL.Mechanism = sarama.SASLMechanism(sasl.SASLMechanism)
+		switch sasl.SASLMechanism {
 		case sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512, sarama.SASLTypePlaintext:
-			config.Net.SASL.User = c.SASL.SASLUser
-			config.Net.SASL.Password = c.SASL.SASLPassword
-			if strings.EqualFold(string(c.SASL.SASLMechanism), sarama.SASLTypeSCRAMSHA256) {
+			config.Net.SASL.User = sasl.SASLUser
+			config.Net.SASL.Password = sasl.SASLPassword
+			if strings.EqualFold(string(sasl.SASLMechanism), sarama.SASLTypeSCRAMSHA256) {
 				config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
 					return &security.XDGSCRAMClient{HashGeneratorFcn: security.SHA256}
 				}
-			} else if strings.EqualFold(string(c.SASL.SASLMechanism), sarama.SASLTypeSCRAMSHA512) {
+			} else if strings.EqualFold(string(sasl.SASLMechanism), sarama.SASLTypeSCRAMSHA512) {
 				config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
 					return &security.XDGSCRAMClient{HashGeneratorFcn: security.SHA512}
 				}
 			}
 		case sarama.SASLTypeGSSAPI:
-			config.Net.SASL.GSSAPI.AuthType = int(c.SASL.GSSAPI.AuthType)
-			config.Net.SASL.GSSAPI.Username = c.SASL.GSSAPI.Username
-			config.Net.SASL.GSSAPI.ServiceName = c.SASL.GSSAPI.ServiceName
-			config.Net.SASL.GSSAPI.KerberosConfigPath = c.SASL.GSSAPI.KerberosConfigPath
-			config.Net.SASL.GSSAPI.Realm = c.SASL.GSSAPI.Realm
-			config.Net.SASL.GSSAPI.DisablePAFXFAST = c.SASL.GSSAPI.DisablePAFXFAST
-			switch c.SASL.GSSAPI.AuthType {
+			config.Net.SASL.GSSAPI.AuthType = int(sasl.GSSAPI.AuthType)
+			config.Net.SASL.GSSAPI.Username = sasl.GSSAPI.Username
+			config.Net.SASL.GSSAPI.ServiceName = sasl.GSSAPI.ServiceName
+			config.Net.SASL.GSSAPI.KerberosConfigPath = sasl.GSSAPI.KerberosConfigPath
+			config.Net.SASL.GSSAPI.Realm = sasl.GSSAPI.Realm
+			config.Net.SASL.GSSAPI.DisablePAFXFAST = sasl.GSSAPI.DisablePAFXFAST
+			switch sasl.GSSAPI.AuthType {
 			case security.UserAuth:
-				config.Net.SASL.GSSAPI.Password = c.SASL.GSSAPI.Password
+				config.Net.SASL.GSSAPI.Password = sasl.GSSAPI.Password
 			case security.KeyTabAuth:
-				config.Net.SASL.GSSAPI.KeyTabPath = c.SASL.GSSAPI.KeyTabPath
+				config.Net.SASL.GSSAPI.KeyTabPath = sasl.GSSAPI.KeyTabPath
 			}
+		case sarama.SASLTypeOAuth:
+			// The token provider caches the token and refreshes it before it
+			// expires, so the sarama client never needs to be recreated.
+			// Sarama never re-authenticates the existing connections, the
+			// refreshed tokens are only used by the new ones.
+			tokenProvider, err := security.NewOAuthTokenProvider(ctx, &sasl.OAuth)
+			if err != nil {
+				return cerror.WrapError(cerror.ErrKafkaInvalidConfig, err)
+			}
+			config.Net.SASL.TokenProvider = tokenProvider
 		}
 	}
+	return nil
 }
@@ -475,6 +475,42 @@ func TestApplySASL(t *testing.T) {
 				"&sasl-mechanism=a",
 			exceptErr: "unknown a SASL mechanism",
 		},
+		{
+			name: "valid OAUTHBEARER static token SASL",
+			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
+				"&sasl-mechanism=oauthbearer&sasl-oauth-token-provider=static" +
+				"&sasl-oauth-token=token",
+			exceptErr: "",
+		},
+		{
+			name: "valid OAUTHBEARER client credentials SASL",
+			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
+				"&sasl-mechanism=OAUTHBEARER&sasl-oauth-token-provider=client-credentials" +
+				"&sasl-oauth-client-id=id&sasl-oauth-client-secret=secret" +
+				"&sasl-oauth-token-url=http%3A%2F%2F127.0.0.1%3A8080%2Ftoken" +
+				"&sasl-oauth-scopes=a,b&sasl-oauth-audience=kafka",
+			exceptErr: "",
+		},
+		{
+			name: "valid OAUTHBEARER aws msk iam SASL",
+			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
+				"&sasl-mechanism=oauthbearer&sasl-oauth-token-provider=aws-msk-iam" +
+				"&sasl-oauth-aws-region=us-east-1",
+			exceptErr: "",
+		},
+		{
+			name: "invalid OAUTHBEARER token provider",
+			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
+				"&sasl-mechanism=oauthbearer&sasl-oauth-token-provider=a",
+			exceptErr: "unknown a token provider",
+		},
+		{
+			name: "OAUTHBEARER client credentials SASL without client secret",
+			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
+				"&sasl-mechanism=oauthbearer&sasl-oauth-token-provider=client-credentials" +
+				"&sasl-oauth-client-id=id&sasl-oauth-token-url=http%3A%2F%2F127.0.0.1%2Ftoken",
+			exceptErr: "sasl-oauth-client-secret and sasl-oauth-token-url are required",
+		},
 		{
 			name: "invalid GSSAPI auth type",
 			URI: "kafka://127.0.0.1:9092/abc?kafka-version=2.6.0&partition-num=0" +
@@ -484,15 +520,16 @@ func TestApplySASL(t *testing.T) {
 	}
 
 	for _, test := range tests {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 			cfg := NewConfig()
 			sinkURI, err := url.Parse(test.URI)
 			require.Nil(t, err)
 			if test.exceptErr == "" {
-				require.Nil(t, cfg.applySASL(sinkURI.Query()))
+				require.Nil(t, cfg.ApplySASL(sinkURI.Query()))
 			} else {
-				require.Regexp(t, test.exceptErr, cfg.applySASL(sinkURI.Query()).Error())
+				require.Regexp(t, test.exceptErr, cfg.ApplySASL(sinkURI.Query()).Error())
 			}
 		})
 	}
@@ -549,6 +586,7 @@ func TestApplyTLS(t *testing.T) {
 	}
 
 	for _, test := range tests {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 			cfg := NewConfig()
@@ -567,6 +605,8 @@ func TestApplyTLS(t *testing.T) {
 func TestCompleteSaramaSASLConfig(t *testing.T) {
 	t.Parallel()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	// Test that SASL is turned on correctly.
 	cfg := NewConfig()
 	cfg.SASL = &security.SASL{
@@ -576,10 +616,10 @@ func TestCompleteSaramaSASLConfig(t *testing.T) {
 		GSSAPI:        security.GSSAPI{},
 	}
 	saramaConfig := sarama.NewConfig()
-	completeSaramaSASLConfig(saramaConfig, cfg)
+	require.Nil(t, CompleteSaramaSASLConfig(ctx, saramaConfig, cfg.SASL))
 	require.False(t, saramaConfig.Net.SASL.Enable)
 	cfg.SASL.SASLMechanism = "plain"
-	completeSaramaSASLConfig(saramaConfig, cfg)
+	require.Nil(t, CompleteSaramaSASLConfig(ctx, saramaConfig, cfg.SASL))
 	require.True(t, saramaConfig.Net.SASL.Enable)
 	// Test that the SCRAMClientGeneratorFunc is set up correctly.
 	cfg = NewConfig()
@@ -590,9 +630,24 @@ func TestCompleteSaramaSASLConfig(t *testing.T) {
 		GSSAPI:        security.GSSAPI{},
 	}
 	saramaConfig = sarama.NewConfig()
-	completeSaramaSASLConfig(saramaConfig, cfg)
+	require.Nil(t, CompleteSaramaSASLConfig(ctx, saramaConfig, cfg.SASL))
 	require.Nil(t, saramaConfig.Net.SASL.SCRAMClientGeneratorFunc)
 	cfg.SASL.SASLMechanism = "SCRAM-SHA-512"
-	completeSaramaSASLConfig(saramaConfig, cfg)
+	require.Nil(t, CompleteSaramaSASLConfig(ctx, saramaConfig, cfg.SASL))
 	require.NotNil(t, saramaConfig.Net.SASL.SCRAMClientGeneratorFunc)
+
+	// Test that the TokenProvider is set up correctly.
+	cfg = NewConfig()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/abc?sasl-mechanism=oauthbearer" +
+		"&sasl-oauth-token-provider=static&sasl-oauth-token=token")
+	require.Nil(t, err)
+	require.Nil(t, cfg.ApplySASL(sinkURI.Query()))
+	saramaConfig = sarama.NewConfig()
+	require.Nil(t, CompleteSaramaSASLConfig(ctx, saramaConfig, cfg.SASL))
+	require.True(t, saramaConfig.Net.SASL.Enable)
+	require.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), saramaConfig.Net.SASL.Mechanism)
+	token, err := saramaConfig.Net.SASL.TokenProvider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token", token.Token)
+	require.Nil(t, saramaConfig.Validate())
 }
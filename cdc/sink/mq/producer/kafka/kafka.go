@@ -115,6 +115,7 @@ func (k *kafkaSaramaProducer) AsyncSendMessage(
 		Topic:     topic,
 		Key:       sarama.ByteEncoder(message.Key),
 		Value:     sarama.ByteEncoder(message.Value),
+		Headers:   newRecordHeaders(message),
 		Partition: partition,
 	}
 	k.mu.Lock()
@@ -132,6 +133,17 @@ func (k *kafkaSaramaProducer) AsyncSendMessage(
 	return nil
 }
 
+func newRecordHeaders(message *codec.MQMessage) []sarama.RecordHeader {
+	if len(message.Headers) == 0 {
+		return nil
+	}
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers))
+	for key, value := range message.Headers {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: value})
+	}
+	return headers
+}
+
 func (k *kafkaSaramaProducer) SyncBroadcastMessage(
 	ctx context.Context, topic string, partitionsNum int32, message *codec.MQMessage,
 ) error {
@@ -143,6 +155,7 @@ func (k *kafkaSaramaProducer) SyncBroadcastMessage(
 			Topic:     topic,
 			Key:       sarama.ByteEncoder(message.Key),
 			Value:     sarama.ByteEncoder(message.Value),
+			Headers:   newRecordHeaders(message),
 			Partition: int32(i),
 		}
 	}
@@ -0,0 +1,292 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+)
+
+// DefaultMockPartitionNum is the default partition number of the topics of MockClient.
+const DefaultMockPartitionNum = 4
+
+// MockClient is an in-memory pulsar client used in tests.
+// All the topics of it have the same number of partitions,
+// and the messages sent by its producers can be received by its consumers.
+type MockClient struct {
+	mu           sync.Mutex
+	partitionNum int
+	// messages holds the messages of each topic in the order they are sent.
+	messages map[string][]*mockMessage
+	// producers holds the number of the producers created for each topic.
+	producers map[string]int
+}
+
+// NewMockClient creates a new MockClient.
+func NewMockClient(partitionNum int) *MockClient {
+	return &MockClient{
+		partitionNum: partitionNum,
+		messages:     make(map[string][]*mockMessage),
+		producers:    make(map[string]int),
+	}
+}
+
+// NewMockClientImpl creates a MockClient with the default partition number,
+// it can be used to replace NewClientImpl.
+func NewMockClientImpl(_ pulsar.ClientOptions) (pulsar.Client, error) {
+	return NewMockClient(DefaultMockPartitionNum), nil
+}
+
+// CreateProducer implements the pulsar.Client interface.
+func (c *MockClient) CreateProducer(opt pulsar.ProducerOptions) (pulsar.Producer, error) {
+	if opt.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.producers[opt.Topic]++
+	return &mockProducer{client: c, opt: opt}, nil
+}
+
+// Subscribe implements the pulsar.Client interface.
+func (c *MockClient) Subscribe(opt pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+	if opt.Topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	return &mockConsumer{client: c, opt: opt}, nil
+}
+
+// CreateReader implements the pulsar.Client interface.
+func (c *MockClient) CreateReader(_ pulsar.ReaderOptions) (pulsar.Reader, error) {
+	return nil, errors.New("reader is not supported by the mock client")
+}
+
+// TopicPartitions implements the pulsar.Client interface.
+func (c *MockClient) TopicPartitions(topic string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	partitions := make([]string, 0, c.partitionNum)
+	for i := 0; i < c.partitionNum; i++ {
+		partitions = append(partitions, partitionTopic(topic, i))
+	}
+	return partitions, nil
+}
+
+// Close implements the pulsar.Client interface.
+func (c *MockClient) Close() {}
+
+// Messages returns the messages sent to the topic.
+func (c *MockClient) Messages(topic string) []pulsar.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	messages := make([]pulsar.Message, 0, len(c.messages[topic]))
+	for _, m := range c.messages[topic] {
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// SetPartitionNum changes the partition number of all the topics.
+func (c *MockClient) SetPartitionNum(partitionNum int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.partitionNum = partitionNum
+}
+
+// ProducerCount returns how many producers have been created for the topic.
+func (c *MockClient) ProducerCount(topic string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.producers[topic]
+}
+
+func (c *MockClient) append(msg *mockMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages[msg.baseTopic] = append(c.messages[msg.baseTopic], msg)
+}
+
+func partitionTopic(topic string, partition int) string {
+	return fmt.Sprintf("%s-partition-%d", topic, partition)
+}
+
+type mockTopicMetadata struct {
+	partitionNum uint32
+}
+
+func (m mockTopicMetadata) NumPartitions() uint32 {
+	return m.partitionNum
+}
+
+type mockProducer struct {
+	client *MockClient
+	opt    pulsar.ProducerOptions
+	seqID  int64
+}
+
+func (p *mockProducer) Topic() string {
+	return p.opt.Topic
+}
+
+func (p *mockProducer) Name() string {
+	return p.opt.Name
+}
+
+func (p *mockProducer) Send(
+	_ context.Context, msg *pulsar.ProducerMessage,
+) (pulsar.MessageID, error) {
+	properties := make(map[string]string, len(msg.Properties))
+	for k, v := range msg.Properties {
+		properties[k] = v
+	}
+	routed := &pulsar.ProducerMessage{
+		Payload:    msg.Payload,
+		Key:        msg.Key,
+		Properties: properties,
+		EventTime:  msg.EventTime,
+	}
+	p.client.mu.Lock()
+	partitionNum := p.client.partitionNum
+	p.client.mu.Unlock()
+	partition := 0
+	if p.opt.MessageRouter != nil {
+		partition = p.opt.MessageRouter(routed, mockTopicMetadata{
+			partitionNum: uint32(partitionNum),
+		})
+	}
+	if partition < 0 || partition >= partitionNum {
+		return nil, errors.Errorf("partition %d out of range", partition)
+	}
+	p.seqID++
+	p.client.append(&mockMessage{
+		baseTopic:  p.opt.Topic,
+		topic:      partitionTopic(p.opt.Topic, partition),
+		producer:   p.opt.Name,
+		msg:        routed,
+		publishAt:  time.Now(),
+		sequenceID: p.seqID,
+	})
+	return nil, nil
+}
+
+func (p *mockProducer) SendAsync(
+	ctx context.Context, msg *pulsar.ProducerMessage,
+	callback func(pulsar.MessageID, *pulsar.ProducerMessage, error),
+) {
+	id, err := p.Send(ctx, msg)
+	callback(id, msg, err)
+}
+
+func (p *mockProducer) LastSequenceID() int64 {
+	return p.seqID
+}
+
+func (p *mockProducer) Flush() error {
+	return nil
+}
+
+func (p *mockProducer) Close() {}
+
+type mockMessage struct {
+	baseTopic  string
+	topic      string
+	producer   string
+	msg        *pulsar.ProducerMessage
+	publishAt  time.Time
+	sequenceID int64
+}
+
+func (m *mockMessage) Topic() string                 { return m.topic }
+func (m *mockMessage) ProducerName() string          { return m.producer }
+func (m *mockMessage) Properties() map[string]string { return m.msg.Properties }
+func (m *mockMessage) Payload() []byte               { return m.msg.Payload }
+func (m *mockMessage) ID() pulsar.MessageID          { return nil }
+func (m *mockMessage) PublishTime() time.Time        { return m.publishAt }
+func (m *mockMessage) EventTime() time.Time          { return m.msg.EventTime }
+func (m *mockMessage) Key() string                   { return m.msg.Key }
+func (m *mockMessage) OrderingKey() string           { return m.msg.OrderingKey }
+func (m *mockMessage) RedeliveryCount() uint32       { return 0 }
+func (m *mockMessage) IsReplicated() bool            { return false }
+func (m *mockMessage) GetReplicatedFrom() string     { return "" }
+
+func (m *mockMessage) GetSchemaValue(_ interface{}) error {
+	return errors.New("schema is not supported by the mock client")
+}
+
+// mockConsumer receives all the messages of the subscribed topic
+// from the beginning, in the order they are sent.
+type mockConsumer struct {
+	client *MockClient
+	opt    pulsar.ConsumerOptions
+	offset int
+}
+
+func (c *mockConsumer) Subscription() string {
+	return c.opt.SubscriptionName
+}
+
+func (c *mockConsumer) Unsubscribe() error {
+	return nil
+}
+
+func (c *mockConsumer) Receive(ctx context.Context) (pulsar.Message, error) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		c.client.mu.Lock()
+		messages := c.client.messages[c.opt.Topic]
+		if c.offset < len(messages) {
+			msg := messages[c.offset]
+			c.offset++
+			c.client.mu.Unlock()
+			return msg, nil
+		}
+		c.client.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Trace(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Chan is not supported by the mock consumer, use Receive instead.
+func (c *mockConsumer) Chan() <-chan pulsar.ConsumerMessage {
+	return nil
+}
+
+func (c *mockConsumer) Ack(_ pulsar.Message)                             {}
+func (c *mockConsumer) AckID(_ pulsar.MessageID)                         {}
+func (c *mockConsumer) ReconsumeLater(_ pulsar.Message, _ time.Duration) {}
+func (c *mockConsumer) Nack(_ pulsar.Message)                            {}
+func (c *mockConsumer) NackID(_ pulsar.MessageID)                        {}
+func (c *mockConsumer) Close()                                           {}
+
+func (c *mockConsumer) Seek(_ pulsar.MessageID) error {
+	return errors.New("seek is not supported by the mock client")
+}
+
+func (c *mockConsumer) SeekByTime(_ time.Time) error {
+	return errors.New("seek is not supported by the mock client")
+}
+
+func (c *mockConsumer) Name() string {
+	return c.opt.Name
+}
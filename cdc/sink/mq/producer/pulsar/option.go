@@ -28,9 +28,19 @@ import (
 type Option struct {
 	clientOptions   *pulsar.ClientOptions
 	producerOptions *pulsar.ProducerOptions
+	// producerIdleTimeout is the duration after which
+	// the producer of an unused topic is closed.
+	producerIdleTimeout time.Duration
 }
 
-const route = "$route"
+const (
+	route = "$route"
+
+	defaultProducerIdleTimeout = 10 * time.Minute
+	// partitionNumRefreshInterval is the interval to refresh
+	// the partition number of the topics.
+	partitionNumRefreshInterval = time.Minute
+)
 
 func parseSinkOptions(u *url.URL) (opt *Option, err error) {
 	switch u.Scheme {
@@ -43,9 +53,16 @@ func parseSinkOptions(u *url.URL) (opt *Option, err error) {
 		return nil, err
 	}
 	p := parseProducerOptions(u)
+	if p.Topic == "" {
+		return nil, fmt.Errorf("no topic is specified in sink-uri")
+	}
 	opt = &Option{
-		clientOptions:   c,
-		producerOptions: p,
+		clientOptions:       c,
+		producerOptions:     p,
+		producerIdleTimeout: values(u.Query()).Duration("producerIdleTimeout"),
+	}
+	if opt.producerIdleTimeout <= 0 {
+		opt.producerIdleTimeout = defaultProducerIdleTimeout
 	}
 
 	p.MessageRouter = func(message *pulsar.ProducerMessage, metadata pulsar.TopicMetadata) int {
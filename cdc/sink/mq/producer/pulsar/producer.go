@@ -17,58 +17,191 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
-	"github.com/pingcap/failpoint"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 )
 
+// NewClientImpl specifies the build method for the pulsar client.
+// It can be replaced by a mock client in tests.
+var NewClientImpl = pulsar.NewClient
+
 // NewProducer create a pulsar producer.
 func NewProducer(u *url.URL, errCh chan error) (*Producer, error) {
-	failpoint.Inject("MockPulsar", func() {
-		failpoint.Return(&Producer{
-			errCh:        errCh,
-			partitionNum: 4,
-		}, nil)
-	})
-
 	opt, err := parseSinkOptions(u)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	client, err := pulsar.NewClient(*opt.clientOptions)
+	client, err := NewClientImpl(*opt.clientOptions)
 	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	producer, err := client.CreateProducer(*opt.producerOptions)
-	if err != nil {
+	p := &Producer{
+		errCh:     errCh,
+		opt:       *opt,
+		client:    client,
+		producers: make(map[string]*topicProducer),
+		closeCh:   make(chan struct{}),
+	}
+	// Create the producer of the default topic eagerly,
+	// so that an unreachable cluster is reported at once.
+	if _, err := p.GetPartitionNum(opt.producerOptions.Topic); err != nil {
 		client.Close()
+		return nil, errors.Trace(err)
+	}
+
+	p.wg.Add(2)
+	go func() {
+		defer p.wg.Done()
+		p.evictIdleProducers()
+	}()
+	go func() {
+		defer p.wg.Done()
+		p.refreshPartitionNums()
+	}()
+	return p, nil
+}
+
+// topicProducer is the producer of a single topic.
+type topicProducer struct {
+	producer     pulsar.Producer
+	partitionNum int32
+	lastUsed     time.Time
+	// inUse is the number of the blocking sends in progress,
+	// the producer is not evicted while it is greater than zero.
+	inUse int
+}
+
+// Producer provide a way to send msg to pulsar.
+// It holds one pulsar producer for each topic,
+// and the producers that have not been used for a while are closed.
+type Producer struct {
+	opt    Option
+	client pulsar.Client
+	errCh  chan error
+
+	mu        sync.Mutex
+	producers map[string]*topicProducer
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// DefaultTopic returns the topic specified in the sink URI.
+func (p *Producer) DefaultTopic() string {
+	return p.opt.producerOptions.Topic
+}
+
+// getProducer returns the producer of the topic, creates it if it not exists.
+// It must be called with the lock held.
+func (p *Producer) getProducer(topic string) (*topicProducer, error) {
+	if topic == "" {
+		topic = p.DefaultTopic()
+	}
+	if tp, ok := p.producers[topic]; ok {
+		tp.lastUsed = time.Now()
+		return tp, nil
+	}
+
+	partitions, err := p.client.TopicPartitions(topic)
+	if err != nil {
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	partitions, err := client.TopicPartitions(opt.producerOptions.Topic)
+	opt := *p.opt.producerOptions
+	opt.Topic = topic
+	producer, err := p.client.CreateProducer(opt)
 	if err != nil {
-		client.Close()
 		return nil, cerror.WrapError(cerror.ErrPulsarNewProducer, err)
 	}
-	return &Producer{
-		errCh:        errCh,
-		opt:          *opt,
-		client:       client,
+	tp := &topicProducer{
 		producer:     producer,
-		partitionNum: len(partitions),
-	}, nil
+		partitionNum: int32(len(partitions)),
+		lastUsed:     time.Now(),
+	}
+	p.producers[topic] = tp
+	log.Info("pulsar producer created",
+		zap.String("topic", topic), zap.Int32("partitionNum", tp.partitionNum))
+	return tp, nil
 }
 
-// Producer provide a way to send msg to pulsar.
-type Producer struct {
-	opt          Option
-	client       pulsar.Client
-	producer     pulsar.Producer
-	errCh        chan error
-	partitionNum int
+// evictIdleProducers closes the producers of the topics which
+// have not been written for longer than the idle timeout.
+// The producer of the default topic is never evicted.
+func (p *Producer) evictIdleProducers() {
+	ticker := time.NewTicker(p.opt.producerIdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.closeCh:
+			return
+		case now := <-ticker.C:
+			p.mu.Lock()
+			for topic, tp := range p.producers {
+				if topic == p.DefaultTopic() || tp.inUse > 0 ||
+					now.Sub(tp.lastUsed) < p.opt.producerIdleTimeout {
+					continue
+				}
+				if err := tp.producer.Flush(); err != nil {
+					p.sendError(err)
+				}
+				tp.producer.Close()
+				delete(p.producers, topic)
+				log.Info("idle pulsar producer evicted", zap.String("topic", topic))
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// refreshPartitionNums periodically refreshes the partition number of
+// the topics, so that the partitions added to a topic are used.
+func (p *Producer) refreshPartitionNums() {
+	ticker := time.NewTicker(partitionNumRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.closeCh:
+			return
+		case <-ticker.C:
+			p.refreshPartitionNumsOnce()
+		}
+	}
+}
+
+func (p *Producer) refreshPartitionNumsOnce() {
+	p.mu.Lock()
+	topics := make([]string, 0, len(p.producers))
+	for topic := range p.producers {
+		topics = append(topics, topic)
+	}
+	p.mu.Unlock()
+
+	// Query the partitions without holding the lock,
+	// it's a request to the pulsar cluster.
+	for _, topic := range topics {
+		partitions, err := p.client.TopicPartitions(topic)
+		if err != nil {
+			log.Warn("failed to refresh the partition number of pulsar topic",
+				zap.String("topic", topic), zap.Error(err))
+			continue
+		}
+		p.mu.Lock()
+		if tp, ok := p.producers[topic]; ok &&
+			int32(len(partitions)) != tp.partitionNum {
+			log.Info("pulsar topic partition number changed",
+				zap.String("topic", topic),
+				zap.Int32("oldPartitionNum", tp.partitionNum),
+				zap.Int("newPartitionNum", len(partitions)))
+			tp.partitionNum = int32(len(partitions))
+		}
+		p.mu.Unlock()
+	}
 }
 
 func createProperties(message *codec.MQMessage, partition int32) map[string]string {
@@ -82,14 +215,23 @@ func createProperties(message *codec.MQMessage, partition int32) map[string]stri
 	if message.Table != nil {
 		properties["table"] = *message.Table
 	}
+	for key, value := range message.Headers {
+		properties[key] = string(value)
+	}
 	return properties
 }
 
-// AsyncSendMessage send key-value msg to target partition.
+// AsyncSendMessage send key-value msg to target partition of the topic.
 func (p *Producer) AsyncSendMessage(
-	ctx context.Context, _ string, partition int32, message *codec.MQMessage,
+	ctx context.Context, topic string, partition int32, message *codec.MQMessage,
 ) error {
-	p.producer.SendAsync(ctx, &pulsar.ProducerMessage{
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tp, err := p.getProducer(topic)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tp.producer.SendAsync(ctx, &pulsar.ProducerMessage{
 		Payload:    message.Value,
 		Key:        string(message.Key),
 		Properties: createProperties(message, partition),
@@ -100,27 +242,49 @@ func (p *Producer) AsyncSendMessage(
 
 func (p *Producer) errors(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
 	if err != nil {
-		select {
-		case p.errCh <- cerror.WrapError(cerror.ErrPulsarSendMessage, err):
-		default:
-			log.Error("error channel is full", zap.Error(err))
-		}
+		p.sendError(err)
+	}
+}
+
+func (p *Producer) sendError(err error) {
+	select {
+	case p.errCh <- cerror.WrapError(cerror.ErrPulsarSendMessage, err):
+	default:
+		log.Error("error channel is full", zap.Error(err))
 	}
 }
 
-// SyncBroadcastMessage send key-value msg to all partition.
+// SyncBroadcastMessage send key-value msg to all partitions of the topic.
 func (p *Producer) SyncBroadcastMessage(
-	ctx context.Context, _ string, _ int32, message *codec.MQMessage,
+	ctx context.Context, topic string, _ int32, message *codec.MQMessage,
 ) error {
-	for partition := 0; partition < p.partitionNum; partition++ {
-		_, err := p.producer.Send(ctx, &pulsar.ProducerMessage{
+	// Take the producer under the lock and send the messages outside it,
+	// so that the blocking sends don't block the other topics.
+	p.mu.Lock()
+	tp, err := p.getProducer(topic)
+	if err != nil {
+		p.mu.Unlock()
+		return errors.Trace(err)
+	}
+	tp.inUse++
+	partitionNum := tp.partitionNum
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		tp.inUse--
+		tp.lastUsed = time.Now()
+		p.mu.Unlock()
+	}()
+
+	for partition := int32(0); partition < partitionNum; partition++ {
+		_, err := tp.producer.Send(ctx, &pulsar.ProducerMessage{
 			Payload:    message.Value,
 			Key:        string(message.Key),
-			Properties: createProperties(message, int32(partition)),
+			Properties: createProperties(message, partition),
 			EventTime:  message.PhysicalTime(),
 		})
 		if err != nil {
-			return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
 		}
 	}
 	return nil
@@ -128,21 +292,42 @@ func (p *Producer) SyncBroadcastMessage(
 
 // Flush flushes all in memory msgs to server.
 func (p *Producer) Flush(_ context.Context) error {
-	return cerror.WrapError(cerror.ErrPulsarSendMessage, p.producer.Flush())
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, tp := range p.producers {
+		if err := tp.producer.Flush(); err != nil {
+			return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+		}
+	}
+	return nil
 }
 
-// GetPartitionNum got current topic's partition size.
-func (p *Producer) GetPartitionNum() int32 {
-	return int32(p.partitionNum)
+// GetPartitionNum got the partition size of the topic.
+func (p *Producer) GetPartitionNum(topic string) (int32, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tp, err := p.getProducer(topic)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return tp.partitionNum, nil
 }
 
-// Close closes the producer and client.
+// Close closes the producers and client.
 func (p *Producer) Close() error {
-	err := p.producer.Flush()
-	if err != nil {
-		return cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+	close(p.closeCh)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var flushErr error
+	for topic, tp := range p.producers {
+		if err := tp.producer.Flush(); err != nil && flushErr == nil {
+			flushErr = cerror.WrapError(cerror.ErrPulsarSendMessage, err)
+		}
+		tp.producer.Close()
+		delete(p.producers, topic)
 	}
-	p.producer.Close()
 	p.client.Close()
-	return nil
+	return flushErr
 }
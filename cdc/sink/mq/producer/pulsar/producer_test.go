@@ -0,0 +1,186 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsar
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockProducer(t *testing.T, uri string) (*Producer, *MockClient) {
+	client := NewMockClient(DefaultMockPartitionNum)
+	NewClientImpl = func(_ pulsar.ClientOptions) (pulsar.Client, error) {
+		return client, nil
+	}
+	defer func() {
+		NewClientImpl = pulsar.NewClient
+	}()
+
+	u, err := url.Parse(uri)
+	require.Nil(t, err)
+	p, err := NewProducer(u, make(chan error, 1))
+	require.Nil(t, err)
+	return p, client
+}
+
+func TestProducerSendToTopics(t *testing.T) {
+	ctx := context.Background()
+	p, client := newMockProducer(t, "pulsar://127.0.0.1:6650/default")
+	require.Equal(t, "default", p.DefaultTopic())
+
+	msg := &codec.MQMessage{
+		Key:   []byte("key"),
+		Value: []byte("value"),
+		Ts:    100,
+		Type:  model.MessageTypeRow,
+	}
+	require.Nil(t, p.AsyncSendMessage(ctx, "t1", 2, msg))
+	require.Nil(t, p.AsyncSendMessage(ctx, "t1", 3, msg))
+	require.Nil(t, p.AsyncSendMessage(ctx, "", 1, msg))
+	require.Nil(t, p.Flush(ctx))
+
+	messages := client.Messages("t1")
+	require.Len(t, messages, 2)
+	require.Equal(t, "t1-partition-2", messages[0].Topic())
+	require.Equal(t, "t1-partition-3", messages[1].Topic())
+	require.Equal(t, "key", messages[0].Key())
+	require.Equal(t, []byte("value"), messages[0].Payload())
+	require.Equal(t, "100", messages[0].Properties()["ts"])
+	messages = client.Messages("default")
+	require.Len(t, messages, 1)
+	require.Equal(t, "default-partition-1", messages[0].Topic())
+	// The producer of each topic is created only once.
+	require.Equal(t, 1, client.ProducerCount("t1"))
+	require.Equal(t, 1, client.ProducerCount("default"))
+
+	partitionNum, err := p.GetPartitionNum("t2")
+	require.Nil(t, err)
+	require.Equal(t, int32(DefaultMockPartitionNum), partitionNum)
+
+	msg.Type = model.MessageTypeResolved
+	require.Nil(t, p.SyncBroadcastMessage(ctx, "t2", partitionNum, msg))
+	messages = client.Messages("t2")
+	require.Len(t, messages, DefaultMockPartitionNum)
+	for i, m := range messages {
+		require.Equal(t, partitionTopic("t2", i), m.Topic())
+	}
+
+	require.Nil(t, p.Close())
+}
+
+func TestProducerEvictIdleProducers(t *testing.T) {
+	ctx := context.Background()
+	p, client := newMockProducer(t,
+		"pulsar://127.0.0.1:6650/default?producerIdleTimeout=100ms")
+	require.Equal(t, 100*time.Millisecond, p.opt.producerIdleTimeout)
+
+	msg := &codec.MQMessage{Value: []byte("value")}
+	require.Nil(t, p.AsyncSendMessage(ctx, "t1", 0, msg))
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		_, ok := p.producers["t1"]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The producer of the default topic is never evicted.
+	p.mu.Lock()
+	require.Contains(t, p.producers, "default")
+	p.mu.Unlock()
+
+	// The evicted producer is created again when the topic is written.
+	require.Nil(t, p.AsyncSendMessage(ctx, "t1", 0, msg))
+	require.Equal(t, 2, client.ProducerCount("t1"))
+	require.Len(t, client.Messages("t1"), 2)
+
+	require.Nil(t, p.Close())
+}
+
+func TestProducerRefreshPartitionNums(t *testing.T) {
+	ctx := context.Background()
+	p, client := newMockProducer(t, "pulsar://127.0.0.1:6650/default")
+
+	client.SetPartitionNum(DefaultMockPartitionNum + 2)
+	partitionNum, err := p.GetPartitionNum("default")
+	require.Nil(t, err)
+	require.Equal(t, int32(DefaultMockPartitionNum), partitionNum)
+
+	p.refreshPartitionNumsOnce()
+	partitionNum, err = p.GetPartitionNum("default")
+	require.Nil(t, err)
+	require.Equal(t, int32(DefaultMockPartitionNum+2), partitionNum)
+
+	msg := &codec.MQMessage{Value: []byte("value"), Type: model.MessageTypeResolved}
+	require.Nil(t, p.SyncBroadcastMessage(ctx, "default", partitionNum, msg))
+	require.Len(t, client.Messages("default"), DefaultMockPartitionNum+2)
+
+	require.Nil(t, p.Close())
+}
+
+func TestProducerSyncBroadcastNotEvicted(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newMockProducer(t,
+		"pulsar://127.0.0.1:6650/default?producerIdleTimeout=100ms")
+
+	msg := &codec.MQMessage{Value: []byte("value"), Type: model.MessageTypeResolved}
+	require.Nil(t, p.SyncBroadcastMessage(ctx, "t1", 0, msg))
+	p.mu.Lock()
+	tp := p.producers["t1"]
+	require.NotNil(t, tp)
+	// The producer is released after the broadcast.
+	require.Equal(t, 0, tp.inUse)
+	// Simulate a broadcast in progress.
+	tp.inUse++
+	p.mu.Unlock()
+
+	time.Sleep(300 * time.Millisecond)
+	p.mu.Lock()
+	require.Contains(t, p.producers, "t1")
+	tp.inUse--
+	p.mu.Unlock()
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		_, ok := p.producers["t1"]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Nil(t, p.Close())
+}
+
+func TestParseSinkOptions(t *testing.T) {
+	u, err := url.Parse("pulsar://127.0.0.1:6650/")
+	require.Nil(t, err)
+	_, err = parseSinkOptions(u)
+	require.Regexp(t, "no topic is specified", err)
+
+	u, err = url.Parse("pulsar://127.0.0.1:6650/?topic=test")
+	require.Nil(t, err)
+	opt, err := parseSinkOptions(u)
+	require.Nil(t, err)
+	require.Equal(t, "test", opt.producerOptions.Topic)
+	require.Equal(t, defaultProducerIdleTimeout, opt.producerIdleTimeout)
+
+	u, err = url.Parse("kafka://127.0.0.1:6650/test")
+	require.Nil(t, err)
+	_, err = parseSinkOptions(u)
+	require.Regexp(t, "unsupported pulsar scheme", err)
+}
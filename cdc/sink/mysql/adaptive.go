@@ -0,0 +1,306 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+)
+
+const (
+	// adaptiveAdjustInterval is the length of the window in which the
+	// downstream latency and errors are measured before making a decision.
+	adaptiveAdjustInterval = 10 * time.Second
+	// adaptiveMaxErrorRate is the ratio of failed executions in a window,
+	// above which the controller shrinks the batches and the workers.
+	adaptiveMaxErrorRate = 0.1
+	// adaptiveIncreaseSteps is the number of healthy windows it takes to
+	// grow from the lower bounds to the upper bounds.
+	adaptiveIncreaseSteps = 8
+)
+
+const (
+	adaptiveDecisionIncrease            = "increase"
+	adaptiveDecisionDecreaseLatency     = "decrease-latency"
+	adaptiveDecisionDecreaseErrorRate   = "decrease-error-rate"
+	adaptiveDecisionBackoffLockWait     = "backoff-lock-wait-timeout"
+	adaptiveDecisionBackoffTooManyConns = "backoff-too-many-connections"
+)
+
+var adaptiveDecisions = []string{
+	adaptiveDecisionIncrease,
+	adaptiveDecisionDecreaseLatency,
+	adaptiveDecisionDecreaseErrorRate,
+	adaptiveDecisionBackoffLockWait,
+	adaptiveDecisionBackoffTooManyConns,
+}
+
+// adaptiveValue is a value tuned by the adaptive controller within [min, max].
+type adaptiveValue struct {
+	min   int
+	max   int
+	value atomic.Int64
+	gauge prometheus.Gauge
+}
+
+func newAdaptiveValue(min, max int, gauge prometheus.Gauge) *adaptiveValue {
+	if min > max {
+		min = max
+	}
+	v := &adaptiveValue{min: min, max: max, gauge: gauge}
+	v.store(max)
+	return v
+}
+
+func (v *adaptiveValue) load() int {
+	return int(v.value.Load())
+}
+
+// store clamps n into [min, max] and returns whether the value is changed.
+func (v *adaptiveValue) store(n int) bool {
+	if n < v.min {
+		n = v.min
+	}
+	if n > v.max {
+		n = v.max
+	}
+	old := v.value.Swap(int64(n))
+	v.gauge.Set(float64(n))
+	return old != int64(n)
+}
+
+func (v *adaptiveValue) increase() bool {
+	step := (v.max - v.min) / adaptiveIncreaseSteps
+	if step < 1 {
+		step = 1
+	}
+	return v.store(v.load() + step)
+}
+
+func (v *adaptiveValue) scale(numerator, denominator int) bool {
+	return v.store(v.load() * numerator / denominator)
+}
+
+// adaptiveController tunes the number of active workers, the max rows of a
+// txn and the batch replace size of the mysql sink. It measures the latency
+// and the errors of the DML executions in windows of adaptiveAdjustInterval,
+// grows the values additively while the downstream keeps up with the target
+// latency, and shrinks them multiplicatively when the downstream slows down,
+// reports too many errors, lock wait timeouts or too many connections.
+type adaptiveController struct {
+	changefeedID  model.ChangeFeedID
+	enabled       bool
+	targetLatency time.Duration
+
+	workerCount      *adaptiveValue
+	maxTxnRow        *adaptiveValue
+	batchReplaceSize *adaptiveValue
+
+	// onWorkerCountChange is called with the new number of active workers
+	// every time it is changed.
+	onWorkerCountChange func(int)
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	lastBackoff  time.Time
+	execCount    int
+	errCount     int
+	totalLatency time.Duration
+
+	// now is used to mock the clock in tests.
+	now func() time.Time
+
+	metricDecision *prometheus.CounterVec
+}
+
+func newAdaptiveController(params *sinkParams, onWorkerCountChange func(int)) *adaptiveController {
+	namespace, changefeed := params.changefeedID.Namespace, params.changefeedID.ID
+	c := &adaptiveController{
+		changefeedID:  params.changefeedID,
+		enabled:       params.adaptiveEnabled,
+		targetLatency: params.adaptiveTargetLatency,
+		workerCount: newAdaptiveValue(params.minWorkerCount, params.workerCount,
+			metrics.AdaptiveWorkerCountGauge.WithLabelValues(namespace, changefeed)),
+		maxTxnRow: newAdaptiveValue(params.minTxnRow, params.maxTxnRow,
+			metrics.AdaptiveMaxTxnRowGauge.WithLabelValues(namespace, changefeed)),
+		batchReplaceSize: newAdaptiveValue(1, params.batchReplaceSize,
+			metrics.AdaptiveBatchReplaceSizeGauge.WithLabelValues(namespace, changefeed)),
+		onWorkerCountChange: onWorkerCountChange,
+		now:                 time.Now,
+		metricDecision: metrics.AdaptiveDecisionCounter.MustCurryWith(
+			prometheus.Labels{"namespace": namespace, "changefeed": changefeed}),
+	}
+	c.windowStart = c.now()
+	return c
+}
+
+// getWorkerCount returns the number of workers that txns are dispatched to.
+func (c *adaptiveController) getWorkerCount() int {
+	return c.workerCount.load()
+}
+
+// getMaxTxnRow returns the max number of rows executed in one txn.
+func (c *adaptiveController) getMaxTxnRow() int {
+	return c.maxTxnRow.load()
+}
+
+// getBatchReplaceSize returns the max number of rows in one batch replace.
+func (c *adaptiveController) getBatchReplaceSize() int {
+	return c.batchReplaceSize.load()
+}
+
+// observe records the latency and the result of a DML execution, and makes
+// a decision if a window is complete or the downstream asks to back off.
+func (c *adaptiveController) observe(latency time.Duration, err error) {
+	if !c.enabled || errors.Cause(err) == context.Canceled {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.execCount++
+	c.totalLatency += latency
+	if err != nil {
+		c.errCount++
+		if decision, ok := backoffDecision(err); ok {
+			c.backoffLocked(now, decision)
+			return
+		}
+	}
+	if now.Sub(c.windowStart) < adaptiveAdjustInterval {
+		return
+	}
+	c.adjustLocked()
+	c.resetWindowLocked(now)
+}
+
+func (c *adaptiveController) backoffLocked(now time.Time, decision string) {
+	// Concurrent workers usually hit the same error at the same time, back
+	// off once per window to avoid shrinking everything to the lower bounds.
+	if now.Sub(c.lastBackoff) < adaptiveAdjustInterval {
+		return
+	}
+	c.lastBackoff = now
+	switch decision {
+	case adaptiveDecisionBackoffTooManyConns:
+		// Only the connections are exhausted, the batches are fine.
+		c.applyLocked(decision, 1, 2, false)
+	default:
+		c.applyLocked(decision, 1, 2, true)
+	}
+	c.resetWindowLocked(now)
+}
+
+func (c *adaptiveController) adjustLocked() {
+	if c.execCount == 0 {
+		return
+	}
+	errRate := float64(c.errCount) / float64(c.execCount)
+	avgLatency := c.totalLatency / time.Duration(c.execCount)
+	switch {
+	case errRate > adaptiveMaxErrorRate:
+		c.applyLocked(adaptiveDecisionDecreaseErrorRate, 1, 2, true)
+	case avgLatency > 2*c.targetLatency:
+		c.applyLocked(adaptiveDecisionDecreaseLatency, 3, 4, true)
+	case avgLatency < c.targetLatency:
+		workerCountChanged := c.workerCount.increase()
+		maxTxnRowChanged := c.maxTxnRow.increase()
+		batchReplaceSizeChanged := c.batchReplaceSize.increase()
+		c.reportLocked(adaptiveDecisionIncrease,
+			workerCountChanged, maxTxnRowChanged || batchReplaceSizeChanged)
+	}
+}
+
+// applyLocked scales the number of workers, and the batches if scaleBatch
+// is true, by numerator/denominator.
+func (c *adaptiveController) applyLocked(
+	decision string, numerator, denominator int, scaleBatch bool,
+) {
+	workerCountChanged := c.workerCount.scale(numerator, denominator)
+	batchChanged := false
+	if scaleBatch {
+		maxTxnRowChanged := c.maxTxnRow.scale(numerator, denominator)
+		batchReplaceSizeChanged := c.batchReplaceSize.scale(numerator, denominator)
+		batchChanged = maxTxnRowChanged || batchReplaceSizeChanged
+	}
+	c.reportLocked(decision, workerCountChanged, batchChanged)
+}
+
+func (c *adaptiveController) reportLocked(
+	decision string, workerCountChanged, batchChanged bool,
+) {
+	if !workerCountChanged && !batchChanged {
+		return
+	}
+	c.metricDecision.WithLabelValues(decision).Inc()
+	log.Info("mysql sink adaptive controller makes a decision",
+		zap.String("namespace", c.changefeedID.Namespace),
+		zap.String("changefeed", c.changefeedID.ID),
+		zap.String("decision", decision),
+		zap.Int("execCount", c.execCount),
+		zap.Int("errCount", c.errCount),
+		zap.Duration("totalLatency", c.totalLatency),
+		zap.Int("workerCount", c.workerCount.load()),
+		zap.Int("maxTxnRow", c.maxTxnRow.load()),
+		zap.Int("batchReplaceSize", c.batchReplaceSize.load()))
+	if workerCountChanged && c.onWorkerCountChange != nil {
+		c.onWorkerCountChange(c.workerCount.load())
+	}
+}
+
+func (c *adaptiveController) resetWindowLocked(now time.Time) {
+	c.windowStart = now
+	c.execCount = 0
+	c.errCount = 0
+	c.totalLatency = 0
+}
+
+func (c *adaptiveController) close() {
+	namespace, changefeed := c.changefeedID.Namespace, c.changefeedID.ID
+	metrics.AdaptiveWorkerCountGauge.DeleteLabelValues(namespace, changefeed)
+	metrics.AdaptiveMaxTxnRowGauge.DeleteLabelValues(namespace, changefeed)
+	metrics.AdaptiveBatchReplaceSizeGauge.DeleteLabelValues(namespace, changefeed)
+	for _, decision := range adaptiveDecisions {
+		metrics.AdaptiveDecisionCounter.DeleteLabelValues(namespace, changefeed, decision)
+	}
+}
+
+// backoffDecision returns the decision if the error indicates that the
+// downstream is overloaded and the sink should back off.
+func backoffDecision(err error) (string, bool) {
+	mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError)
+	if !ok {
+		return "", false
+	}
+	switch mysqlErr.Number {
+	case tmysql.ErrLockWaitTimeout:
+		return adaptiveDecisionBackoffLockWait, true
+	case tmysql.ErrConCount:
+		return adaptiveDecisionBackoffTooManyConns, true
+	}
+	return "", false
+}
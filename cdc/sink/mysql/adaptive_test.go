@@ -0,0 +1,151 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdaptiveController4Test(t *testing.T) (*adaptiveController, *time.Time, *[]int) {
+	params := defaultParams.Clone()
+	params.changefeedID = model.DefaultChangeFeedID(t.Name())
+	params.adaptiveEnabled = true
+	params.workerCount = 16
+	params.minWorkerCount = 2
+	params.maxTxnRow = 256
+	params.minTxnRow = 16
+	params.batchReplaceSize = 20
+	params.adaptiveTargetLatency = 100 * time.Millisecond
+
+	var workerCounts []int
+	c := newAdaptiveController(params, func(workerCount int) {
+		workerCounts = append(workerCounts, workerCount)
+	})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.windowStart = now
+	t.Cleanup(c.close)
+	return c, &now, &workerCounts
+}
+
+func TestAdaptiveControllerDisabled(t *testing.T) {
+	t.Parallel()
+
+	c, now, workerCounts := newAdaptiveController4Test(t)
+	c.enabled = false
+	c.observe(time.Second, &dmysql.MySQLError{Number: tmysql.ErrLockWaitTimeout})
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(time.Hour, nil)
+	require.Equal(t, 16, c.getWorkerCount())
+	require.Equal(t, 256, c.getMaxTxnRow())
+	require.Equal(t, 20, c.getBatchReplaceSize())
+	require.Empty(t, *workerCounts)
+}
+
+func TestAdaptiveControllerLatency(t *testing.T) {
+	t.Parallel()
+
+	c, now, workerCounts := newAdaptiveController4Test(t)
+
+	// The downstream is slow, shrink by a quarter.
+	c.observe(300*time.Millisecond, nil)
+	require.Equal(t, 16, c.getWorkerCount())
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(300*time.Millisecond, nil)
+	require.Equal(t, 12, c.getWorkerCount())
+	require.Equal(t, 192, c.getMaxTxnRow())
+	require.Equal(t, 15, c.getBatchReplaceSize())
+	require.Equal(t, []int{12}, *workerCounts)
+
+	// The latency is acceptable, hold.
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(150*time.Millisecond, nil)
+	require.Equal(t, 12, c.getWorkerCount())
+	require.Equal(t, 192, c.getMaxTxnRow())
+
+	// The downstream keeps up, grow additively up to the upper bounds.
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(10*time.Millisecond, nil)
+	require.Equal(t, 13, c.getWorkerCount())
+	require.Equal(t, 222, c.getMaxTxnRow())
+	require.Equal(t, 17, c.getBatchReplaceSize())
+	for i := 0; i < adaptiveIncreaseSteps; i++ {
+		*now = now.Add(adaptiveAdjustInterval)
+		c.observe(10*time.Millisecond, nil)
+	}
+	require.Equal(t, 16, c.getWorkerCount())
+	require.Equal(t, 256, c.getMaxTxnRow())
+	require.Equal(t, 20, c.getBatchReplaceSize())
+	require.Equal(t, []int{12, 13, 14, 15, 16}, *workerCounts)
+}
+
+func TestAdaptiveControllerErrorRate(t *testing.T) {
+	t.Parallel()
+
+	c, now, _ := newAdaptiveController4Test(t)
+	for i := 0; i < 8; i++ {
+		c.observe(time.Millisecond, nil)
+	}
+	c.observe(time.Millisecond, errors.New("retryable error"))
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(time.Millisecond, errors.New("retryable error"))
+	require.Equal(t, 8, c.getWorkerCount())
+	require.Equal(t, 128, c.getMaxTxnRow())
+	require.Equal(t, 10, c.getBatchReplaceSize())
+
+	// Shrinking stops at the lower bounds.
+	for i := 0; i < 10; i++ {
+		*now = now.Add(adaptiveAdjustInterval)
+		c.observe(time.Millisecond, errors.New("retryable error"))
+	}
+	require.Equal(t, 2, c.getWorkerCount())
+	require.Equal(t, 16, c.getMaxTxnRow())
+	require.Equal(t, 1, c.getBatchReplaceSize())
+}
+
+func TestAdaptiveControllerBackoff(t *testing.T) {
+	t.Parallel()
+
+	c, now, workerCounts := newAdaptiveController4Test(t)
+	lockWaitTimeout := cerror.WrapError(cerror.ErrMySQLTxnError,
+		&dmysql.MySQLError{Number: tmysql.ErrLockWaitTimeout})
+	tooManyConns := cerror.WrapError(cerror.ErrMySQLTxnError,
+		&dmysql.MySQLError{Number: tmysql.ErrConCount})
+
+	// Back off immediately on lock wait timeouts, only once in a window.
+	c.observe(time.Millisecond, lockWaitTimeout)
+	c.observe(time.Millisecond, lockWaitTimeout)
+	require.Equal(t, 8, c.getWorkerCount())
+	require.Equal(t, 128, c.getMaxTxnRow())
+	require.Equal(t, 10, c.getBatchReplaceSize())
+
+	// Too many connections only shrinks the workers.
+	*now = now.Add(adaptiveAdjustInterval)
+	c.observe(time.Millisecond, tooManyConns)
+	require.Equal(t, 4, c.getWorkerCount())
+	require.Equal(t, 128, c.getMaxTxnRow())
+	require.Equal(t, 10, c.getBatchReplaceSize())
+	require.Equal(t, []int{8, 4}, *workerCounts)
+
+	require.True(t, IsRetryableDMLError(lockWaitTimeout))
+	require.True(t, IsRetryableDMLError(tooManyConns))
+}
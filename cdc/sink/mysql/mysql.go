@@ -74,6 +74,9 @@ type mysqlSink struct {
 	metricConflictDetectDurationHis prometheus.Observer
 	metricBucketSizeCounters        []prometheus.Counter
 
+	// adaptive tunes the number of active workers and the batch sizes.
+	adaptive *adaptiveController
+
 	forceReplicate bool
 	cancel         func()
 
@@ -96,72 +99,7 @@ func NewMySQLSink(
 
 	params.enableOldValue = replicaConfig.EnableOldValue
 
-	// dsn format of the driver:
-	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
-	username := sinkURI.User.Username()
-	if username == "" {
-		username = "root"
-	}
-	password, _ := sinkURI.User.Password()
-	hostName := sinkURI.Hostname()
-	port := sinkURI.Port()
-	if port == "" {
-		port = "4000"
-	}
-
-	// This will handle the IPv6 address format.
-	host := net.JoinHostPort(hostName, port)
-	dsnStr := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, host, params.tls)
-	dsn, err := dmysql.ParseDSN(dsnStr)
-	if err != nil {
-		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
-	}
-
-	// create test db used for parameter detection
-	// Refer https://github.com/go-sql-driver/mysql#parameters
-	if dsn.Params == nil {
-		dsn.Params = make(map[string]string, 1)
-	}
-	if params.timezone != "" {
-		dsn.Params["time_zone"] = params.timezone
-	}
-	dsn.Params["readTimeout"] = params.readTimeout
-	dsn.Params["writeTimeout"] = params.writeTimeout
-	dsn.Params["timeout"] = params.dialTimeout
-	testDB, err := GetDBConnImpl(ctx, dsn.FormatDSN())
-	if err != nil {
-		return nil, err
-	}
-	defer testDB.Close()
-
-	// Adjust sql_mode for compatibility.
-	dsn.Params["sql_mode"], err = querySQLMode(ctx, testDB)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	dsn.Params["sql_mode"], err = dmutils.AdjustSQLModeCompatible(dsn.Params["sql_mode"])
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
-	// NOTE: quote the string is necessary to avoid ambiguities.
-	dsn.Params["sql_mode"] = strconv.Quote(dsn.Params["sql_mode"])
-
-	dsnStr, err = generateDSNByParams(ctx, dsn, params, testDB)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	// check if GBK charset is supported by downstream
-	gbkSupported, err := checkCharsetSupport(ctx, testDB, charset.CharsetGBK)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	if !gbkSupported {
-		log.Warn("gbk charset is not supported by downstream, "+
-			"some types of DDL may fail to be executed",
-			zap.String("hostname", hostName), zap.String("port", port))
-	}
-	db, err := GetDBConnImpl(ctx, dsnStr)
+	db, err := newDBConn(ctx, sinkURI, params)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +131,10 @@ func NewMySQLSink(
 		forceReplicate:                  replicaConfig.ForceReplicate,
 		cancel:                          cancel,
 	}
+	sink.adaptive = newAdaptiveController(params, func(workerCount int) {
+		db.SetMaxIdleConns(workerCount)
+		db.SetMaxOpenConns(workerCount)
+	})
 
 	err = sink.createSinkWorkers(ctx)
 	if err != nil {
@@ -318,7 +260,7 @@ func (s *mysqlSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEve
 }
 
 func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
-	shouldSwitchDB := needSwitchDB(ddl)
+	shouldSwitchDB := NeedSwitchDB(ddl)
 
 	failpoint.Inject("MySQLSinkExecDDLDelay", func() {
 		select {
@@ -362,7 +304,99 @@ func (s *mysqlSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
 	return nil
 }
 
-func needSwitchDB(ddl *model.DDLEvent) bool {
+// newDBConn checks the downstream with a test connection, adjusts the session
+// variables accordingly and opens the connection pool used by the sink.
+func newDBConn(ctx context.Context, sinkURI *url.URL, params *sinkParams) (*sql.DB, error) {
+	dsn, err := generateTestDSN(sinkURI, params)
+	if err != nil {
+		return nil, err
+	}
+	testDB, err := GetDBConnImpl(ctx, dsn.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	defer testDB.Close()
+
+	// Adjust sql_mode for compatibility.
+	dsn.Params["sql_mode"], err = querySQLMode(ctx, testDB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dsn.Params["sql_mode"], err = dmutils.AdjustSQLModeCompatible(dsn.Params["sql_mode"])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// NOTE: quote the string is necessary to avoid ambiguities.
+	dsn.Params["sql_mode"] = strconv.Quote(dsn.Params["sql_mode"])
+
+	dsnStr, err := generateDSNByParams(ctx, dsn, params, testDB)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// check if GBK charset is supported by downstream
+	gbkSupported, err := checkCharsetSupport(ctx, testDB, charset.CharsetGBK)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !gbkSupported {
+		log.Warn("gbk charset is not supported by downstream, "+
+			"some types of DDL may fail to be executed",
+			zap.String("host", dsn.Addr))
+	}
+	return GetDBConnImpl(ctx, dsnStr)
+}
+
+// Config is the configuration of a MySQL sink parsed from the sink URI and
+// the replica config. It is used by sinks that reuse the connection setup of
+// this package.
+type Config struct {
+	ChangefeedID        model.ChangeFeedID
+	WorkerCount         int
+	MaxTxnRow           int
+	BatchReplaceEnabled bool
+	BatchReplaceSize    int
+	SafeMode            bool
+	EnableOldValue      bool
+	ForceReplicate      bool
+}
+
+// NewDBConnAndConfig parses the sink URI, checks the downstream and opens a
+// connection pool sized by the worker count.
+func NewDBConnAndConfig(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*sql.DB, *Config, error) {
+	params, err := parseSinkURIToParams(ctx, changefeedID, sinkURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.enableOldValue = replicaConfig.EnableOldValue
+
+	db, err := newDBConn(ctx, sinkURI, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	db.SetMaxIdleConns(params.workerCount)
+	db.SetMaxOpenConns(params.workerCount)
+
+	return db, &Config{
+		ChangefeedID:        params.changefeedID,
+		WorkerCount:         params.workerCount,
+		MaxTxnRow:           params.maxTxnRow,
+		BatchReplaceEnabled: params.batchReplaceEnabled,
+		BatchReplaceSize:    params.batchReplaceSize,
+		SafeMode:            params.safeMode,
+		EnableOldValue:      params.enableOldValue,
+		ForceReplicate:      replicaConfig.ForceReplicate,
+	}, nil
+}
+
+// NeedSwitchDB returns whether the DDL must be executed after switching to
+// the schema it belongs to.
+func NeedSwitchDB(ddl *model.DDLEvent) bool {
 	if len(ddl.TableInfo.Schema) == 0 {
 		return false
 	}
@@ -412,6 +446,7 @@ func (s *mysqlSink) createSinkWorkers(ctx context.Context) error {
 		}
 		worker := newMySQLSinkWorker(
 			s.params.maxTxnRow, i, s.metricBucketSizeCounters[i], receiver, s.execDMLs)
+		worker.maxTxnRowFn = s.adaptive.getMaxTxnRow
 		s.workers[i] = worker
 		go func() {
 			err := worker.run(ctx)
@@ -471,7 +506,10 @@ func (s *mysqlSink) broadcastFinishTxn() {
 // 2) Each conflicting transaction will be executed in the order of the CommitTs
 // 3）Conflict-free transactions will be executed concurrently
 func (s *mysqlSink) dispatchAndExecTxns(ctx context.Context, txnsGroup map[model.TableID][]*model.SingleTableTxn) {
-	nWorkers := s.params.workerCount
+	// The number of active workers may be changed by the adaptive controller,
+	// it is fixed in a round of dispatching since all workers are waited at
+	// the end of the round.
+	nWorkers := s.adaptive.getWorkerCount()
 	causality := newCausality()
 	workerIndex := 0
 
@@ -537,6 +575,7 @@ func (s *mysqlSink) cleanTableResource(tableID model.TableID) {
 
 func (s *mysqlSink) Close(ctx context.Context) error {
 	s.execWaitNotifier.Close()
+	s.adaptive.close()
 	err := s.db.Close()
 	s.cancel()
 	return cerror.WrapError(cerror.ErrMySQLConnectionError, err)
@@ -606,7 +645,7 @@ func logDMLTxnErr(
 	err error, start time.Time, changefeed model.ChangeFeedID,
 	query string, count int, startTs []model.Ts,
 ) error {
-	if isRetryableDMLError(err) {
+	if IsRetryableDMLError(err) {
 		log.Warn("execute DMLs with error, retry later",
 			zap.Error(err), zap.Duration("duration", time.Since(start)),
 			zap.String("query", query), zap.Int("count", count),
@@ -623,7 +662,9 @@ func logDMLTxnErr(
 	return err
 }
 
-func isRetryableDMLError(err error) bool {
+// IsRetryableDMLError returns whether the error returned by executing DMLs
+// can be retried safely.
+func IsRetryableDMLError(err error) bool {
 	if !cerror.IsRetryableError(err) {
 		return false
 	}
@@ -631,6 +672,11 @@ func isRetryableDMLError(err error) bool {
 	if dmretry.IsConnectionError(err) {
 		return true
 	}
+	// Lock wait timeouts and too many connections are retryable after the
+	// downstream is relieved.
+	if _, ok := backoffDecision(err); ok {
+		return true
+	}
 	// Check if the error is an retriable TiDB error or MySQL error.
 	return dbutil.IsRetryableError(err)
 }
@@ -653,6 +699,7 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 		failpoint.Inject("MySQLSinkHangLongTime", func() {
 			time.Sleep(time.Hour)
 		})
+		execStart := time.Now()
 		err := s.statistics.RecordBatchExecution(func() (int, error) {
 			tx, err := s.db.BeginTx(ctx, nil)
 			if err != nil {
@@ -684,6 +731,7 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 			}
 			return dmls.rowCount, nil
 		})
+		s.adaptive.observe(time.Since(execStart), err)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -696,7 +744,7 @@ func (s *mysqlSink) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDML
 	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
 		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
 		retry.WithMaxTries(defaultDMLMaxRetry),
-		retry.WithIsRetryableErr(isRetryableDMLError))
+		retry.WithIsRetryableErr(IsRetryableDMLError))
 }
 
 type preparedDMLs struct {
@@ -728,7 +776,7 @@ func (s *mysqlSink) prepareDMLs(rows []*model.RowChangedEvent, bucket int) *prep
 	// flush cached batch replace or insert, to keep the sequence of DMLs
 	flushCacheDMLs := func() {
 		if s.params.batchReplaceEnabled && len(replaces) > 0 {
-			replaceSqls, replaceValues := reduceReplace(replaces, s.params.batchReplaceSize)
+			replaceSqls, replaceValues := reduceReplace(replaces, s.adaptive.getBatchReplaceSize())
 			sqls = append(sqls, replaceSqls...)
 			values = append(values, replaceValues...)
 			replaces = make(map[string][][]interface{})
@@ -1027,6 +1075,60 @@ func buildColumnList(names []string) string {
 	return b.String()
 }
 
+// generateTestDSN generates the dsn of the test db which is used for
+// parameter detection.
+func generateTestDSN(sinkURI *url.URL, params *sinkParams) (*dmysql.Config, error) {
+	// dsn format of the driver:
+	// [username[:password]@][protocol[(address)]]/dbname[?param1=value1&...&paramN=valueN]
+	username := sinkURI.User.Username()
+	if username == "" {
+		username = "root"
+	}
+	password, _ := sinkURI.User.Password()
+	port := sinkURI.Port()
+	if port == "" {
+		port = "4000"
+	}
+
+	// This will handle the IPv6 address format.
+	host := net.JoinHostPort(sinkURI.Hostname(), port)
+	dsnStr := fmt.Sprintf("%s:%s@tcp(%s)/%s", username, password, host, params.tls)
+	dsn, err := dmysql.ParseDSN(dsnStr)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+	}
+
+	// Refer https://github.com/go-sql-driver/mysql#parameters
+	if dsn.Params == nil {
+		dsn.Params = make(map[string]string, 1)
+	}
+	if params.timezone != "" {
+		dsn.Params["time_zone"] = params.timezone
+	}
+	dsn.Params["readTimeout"] = params.readTimeout
+	dsn.Params["writeTimeout"] = params.writeTimeout
+	dsn.Params["timeout"] = params.dialTimeout
+	return dsn, nil
+}
+
+// CheckReachable checks whether the downstream MySQL or TiDB of the sink URI
+// can be connected, nothing is written to the downstream.
+func CheckReachable(ctx context.Context, sinkURI *url.URL) error {
+	params, err := parseSinkURIToParams(ctx, model.DefaultChangeFeedID("sink-verify"), sinkURI)
+	if err != nil {
+		return err
+	}
+	dsn, err := generateTestDSN(sinkURI, params)
+	if err != nil {
+		return err
+	}
+	db, err := GetDBConnImpl(ctx, dsn.FormatDSN())
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
 // GetDBConnImpl is the implement holder to get db connection. Export it for tests
 var GetDBConnImpl = getDBConn
 
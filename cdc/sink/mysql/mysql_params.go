@@ -52,6 +52,11 @@ const (
 	defaultSafeMode            = true
 	defaultTxnIsolationRC      = "READ-COMMITTED"
 	defaultCharacterSet        = "utf8mb4"
+
+	defaultAdaptiveEnabled       = false
+	defaultAdaptiveTargetLatency = time.Second
+	defaultMinWorkerCount        = 1
+	defaultMinTxnRow             = 16
 )
 
 var (
@@ -69,6 +74,11 @@ var defaultParams = &sinkParams{
 	writeTimeout:        defaultWriteTimeout,
 	dialTimeout:         defaultDialTimeout,
 	safeMode:            defaultSafeMode,
+
+	adaptiveEnabled:       defaultAdaptiveEnabled,
+	adaptiveTargetLatency: defaultAdaptiveTargetLatency,
+	minWorkerCount:        defaultMinWorkerCount,
+	minTxnRow:             defaultMinTxnRow,
 }
 
 var validSchemes = map[string]bool{
@@ -92,6 +102,15 @@ type sinkParams struct {
 	safeMode            bool
 	timezone            string
 	tls                 string
+
+	// adaptiveEnabled enables tuning the number of active workers, the max
+	// txn rows and the batch replace size according to the downstream
+	// latency and errors, workerCount, maxTxnRow and batchReplaceSize are
+	// the upper bounds of the tuning.
+	adaptiveEnabled       bool
+	adaptiveTargetLatency time.Duration
+	minWorkerCount        int
+	minTxnRow             int
 }
 
 func (s *sinkParams) Clone() *sinkParams {
@@ -249,9 +268,75 @@ func parseSinkURIToParams(ctx context.Context,
 		params.dialTimeout = s
 	}
 
+	if err := parseAdaptiveParams(sinkURI, params); err != nil {
+		return nil, err
+	}
+
 	return params, nil
 }
 
+func parseAdaptiveParams(sinkURI *url.URL, params *sinkParams) error {
+	s := sinkURI.Query().Get("adaptive-enable")
+	if s != "" {
+		enable, err := strconv.ParseBool(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		params.adaptiveEnabled = enable
+	}
+	s = sinkURI.Query().Get("adaptive-target-latency")
+	if s != "" {
+		latency, err := time.ParseDuration(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if latency <= 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid adaptive-target-latency %s, which must be greater than 0", s))
+		}
+		params.adaptiveTargetLatency = latency
+	}
+	s = sinkURI.Query().Get("min-worker-count")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if c <= 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid min-worker-count %d, which must be greater than 0", c))
+		}
+		if c > params.workerCount {
+			log.Warn("min-worker-count larger than worker-count",
+				zap.Int("original", c), zap.Int("override", params.workerCount))
+		}
+		params.minWorkerCount = c
+	}
+	if params.minWorkerCount > params.workerCount {
+		params.minWorkerCount = params.workerCount
+	}
+	s = sinkURI.Query().Get("min-txn-row")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		if c <= 0 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid min-txn-row %d, which must be greater than 0", c))
+		}
+		if c > params.maxTxnRow {
+			log.Warn("min-txn-row larger than max-txn-row",
+				zap.Int("original", c), zap.Int("override", params.maxTxnRow))
+		}
+		params.minTxnRow = c
+	}
+	if params.minTxnRow > params.maxTxnRow {
+		params.minTxnRow = params.maxTxnRow
+	}
+	return nil
+}
+
 func generateDSNByParams(
 	ctx context.Context,
 	dsnCfg *dmysql.Config,
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	dmysql "github.com/go-sql-driver/mysql"
@@ -43,6 +44,11 @@ func TestSinkParamsClone(t *testing.T) {
 		writeTimeout:        defaultWriteTimeout,
 		dialTimeout:         defaultDialTimeout,
 		safeMode:            defaultSafeMode,
+
+		adaptiveEnabled:       defaultAdaptiveEnabled,
+		adaptiveTargetLatency: defaultAdaptiveTargetLatency,
+		minWorkerCount:        defaultMinWorkerCount,
+		minTxnRow:             defaultMinTxnRow,
 	}, param1)
 	require.Equal(t, &sinkParams{
 		changefeedID:        model.DefaultChangeFeedID("123"),
@@ -55,6 +61,11 @@ func TestSinkParamsClone(t *testing.T) {
 		writeTimeout:        defaultWriteTimeout,
 		dialTimeout:         defaultDialTimeout,
 		safeMode:            defaultSafeMode,
+
+		adaptiveEnabled:       defaultAdaptiveEnabled,
+		adaptiveTargetLatency: defaultAdaptiveTargetLatency,
+		minWorkerCount:        defaultMinWorkerCount,
+		minTxnRow:             defaultMinTxnRow,
 	}, param2)
 }
 
@@ -207,9 +218,14 @@ func TestParseSinkURIToParams(t *testing.T) {
 	expected.timezone = `"UTC"`
 	expected.changefeedID = model.DefaultChangeFeedID("cf-id")
 	expected.tidbTxnMode = "pessimistic"
+	expected.adaptiveEnabled = true
+	expected.adaptiveTargetLatency = 200 * time.Millisecond
+	expected.minWorkerCount = 4
+	expected.minTxnRow = 8
 	uriStr := "mysql://127.0.0.1:3306/?worker-count=64&max-txn-row=20" +
 		"&batch-replace-enable=true&batch-replace-size=50&safe-mode=true" +
-		"&tidb-txn-mode=pessimistic"
+		"&tidb-txn-mode=pessimistic&adaptive-enable=true" +
+		"&adaptive-target-latency=200ms&min-worker-count=4&min-txn-row=8"
 	uri, err := url.Parse(uriStr)
 	require.Nil(t, err)
 	params, err := parseSinkURIToParams(context.TODO(), expected.changefeedID, uri)
@@ -258,6 +274,16 @@ func TestParseSinkURIOverride(t *testing.T) {
 		checker: func(sp *sinkParams) {
 			require.EqualValues(t, sp.maxTxnRow, maxMaxTxnRow)
 		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?worker-count=2&min-worker-count=4",
+		checker: func(sp *sinkParams) {
+			require.EqualValues(t, sp.minWorkerCount, 2)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?max-txn-row=4",
+		checker: func(sp *sinkParams) {
+			require.EqualValues(t, sp.minTxnRow, 4)
+		},
 	}, {
 		uri: "mysql://127.0.0.1:3306/?tidb-txn-mode=badmode",
 		checker: func(sp *sinkParams) {
@@ -302,6 +328,11 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?write-timeout=badduration",
 		"mysql://127.0.0.1:3306/?read-timeout=badduration",
 		"mysql://127.0.0.1:3306/?timeout=badduration",
+		"mysql://127.0.0.1:3306/?adaptive-enable=not-bool",
+		"mysql://127.0.0.1:3306/?adaptive-target-latency=badduration",
+		"mysql://127.0.0.1:3306/?adaptive-target-latency=0s",
+		"mysql://127.0.0.1:3306/?min-worker-count=0",
+		"mysql://127.0.0.1:3306/?min-txn-row=not-number",
 	}
 	ctx := context.TODO()
 	var uri *url.URL
@@ -30,8 +30,11 @@ import (
 )
 
 type mysqlSinkWorker struct {
-	txnCh            chan *model.SingleTableTxn
-	maxTxnRow        int
+	txnCh     chan *model.SingleTableTxn
+	maxTxnRow int
+	// maxTxnRowFn returns the max txn rows tuned by the adaptive controller,
+	// maxTxnRow is used if it is nil.
+	maxTxnRowFn      func() int
 	bucket           int
 	execDMLs         func(context.Context, []*model.RowChangedEvent, int) error
 	metricBucketSize prometheus.Counter
@@ -81,6 +84,13 @@ func (w *mysqlSinkWorker) isNormal() bool {
 	return !w.hasError.Load()
 }
 
+func (w *mysqlSinkWorker) getMaxTxnRow() int {
+	if w.maxTxnRowFn != nil {
+		return w.maxTxnRowFn()
+	}
+	return w.maxTxnRow
+}
+
 func (w *mysqlSinkWorker) run(ctx context.Context) (err error) {
 	var (
 		toExecRows []*model.RowChangedEvent
@@ -143,7 +153,7 @@ func (w *mysqlSinkWorker) run(ctx context.Context) (err error) {
 				txn.FinishWg.Done()
 				continue
 			}
-			if len(toExecRows)+len(txn.Rows) > w.maxTxnRow {
+			if len(toExecRows)+len(txn.Rows) > w.getMaxTxnRow() {
 				if err := flushRows(); err != nil {
 					txnNum++
 					w.hasError.Store(true)
@@ -0,0 +1,360 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// translateDDL translates a TiDB DDL into PostgreSQL statements. The created
+// objects use IF NOT EXISTS and the dropped objects use IF EXISTS, so that
+// the statements are idempotent when the DDL is replayed after a restart.
+// MySQL index names are only unique in a table while PostgreSQL index names
+// are unique in a schema, so indexes are named as "<table>_<index>". An empty
+// statement list means the DDL has no effect on PostgreSQL. The DDLs that can
+// not be translated return ErrPostgresDDLUnsupported.
+func translateDDL(ddl *model.DDLEvent) ([]string, error) {
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	t := &ddlTranslator{}
+	if ddl.TableInfo != nil {
+		t.schema = ddl.TableInfo.Schema
+	}
+	switch s := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		t.add("CREATE SCHEMA IF NOT EXISTS " + quoteName(s.Name.O))
+	case *ast.DropDatabaseStmt:
+		t.add("DROP SCHEMA IF EXISTS " + quoteName(s.Name.O) + " CASCADE")
+	case *ast.CreateTableStmt:
+		err = t.createTable(s)
+	case *ast.DropTableStmt:
+		if s.IsView {
+			return nil, unsupportedDDL(ddl.Query)
+		}
+		for _, table := range s.Tables {
+			t.add("DROP TABLE IF EXISTS " + t.quoteTable(table))
+		}
+	case *ast.TruncateTableStmt:
+		t.add("TRUNCATE TABLE " + t.quoteTable(s.Table))
+	case *ast.RenameTableStmt:
+		for _, tt := range s.TableToTables {
+			err = t.renameTable(tt.OldTable, tt.NewTable)
+			if err != nil {
+				break
+			}
+		}
+	case *ast.AlterTableStmt:
+		err = t.alterTable(s)
+	case *ast.CreateIndexStmt:
+		var unique bool
+		switch s.KeyType {
+		case ast.IndexKeyTypeNone:
+		case ast.IndexKeyTypeUnique:
+			unique = true
+		default:
+			return nil, unsupportedDDL(ddl.Query)
+		}
+		err = t.createIndex(s.Table, s.IndexName, s.IndexPartSpecifications, unique)
+	case *ast.DropIndexStmt:
+		t.dropIndex(s.Table, s.IndexName)
+	default:
+		return nil, unsupportedDDL(ddl.Query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.stmts, nil
+}
+
+func unsupportedDDL(query string) error {
+	return cerror.ErrPostgresDDLUnsupported.GenWithStackByArgs(query)
+}
+
+type ddlTranslator struct {
+	// schema is used for the tables without a schema in the DDL.
+	schema string
+	stmts  []string
+}
+
+func (t *ddlTranslator) add(stmt string) {
+	t.stmts = append(t.stmts, stmt)
+}
+
+func (t *ddlTranslator) schemaOf(table *ast.TableName) string {
+	if table.Schema.O != "" {
+		return table.Schema.O
+	}
+	return t.schema
+}
+
+func (t *ddlTranslator) quoteTable(table *ast.TableName) string {
+	return quoteTable(t.schemaOf(table), table.Name.O)
+}
+
+func (t *ddlTranslator) quoteIndex(table *ast.TableName, index string) string {
+	return quoteTable(t.schemaOf(table), indexName(table.Name.O, index))
+}
+
+// indexName returns the PostgreSQL name of a MySQL index of the table.
+func indexName(table, index string) string {
+	return table + "_" + index
+}
+
+func (t *ddlTranslator) createTable(s *ast.CreateTableStmt) error {
+	if s.ReferTable != nil || s.Select != nil || len(s.Cols) == 0 {
+		return unsupportedDDL(restoreNode(s))
+	}
+	var defs []string
+	// Unique keys are created as unique indexes, so that they can be
+	// dropped by DROP INDEX as in TiDB. The indexes are created after the
+	// table, they are collected by another translator.
+	indexes := &ddlTranslator{schema: t.schema}
+	for _, col := range s.Cols {
+		def, err := columnDef(col)
+		if err != nil {
+			return err
+		}
+		defs = append(defs, def)
+		if hasColumnOption(col, ast.ColumnOptionUniqKey) {
+			err := indexes.createIndex(s.Table, col.Name.Name.O,
+				[]*ast.IndexPartSpecification{{Column: col.Name}}, true)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, constraint := range s.Constraints {
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			keys, err := keyList(constraint.Keys)
+			if err != nil {
+				return err
+			}
+			defs = append(defs, "PRIMARY KEY ("+keys+")")
+		default:
+			if err := indexes.addConstraint(s.Table, constraint); err != nil {
+				return err
+			}
+		}
+	}
+	t.add("CREATE TABLE IF NOT EXISTS " + t.quoteTable(s.Table) +
+		" (" + strings.Join(defs, ", ") + ")")
+	t.stmts = append(t.stmts, indexes.stmts...)
+	return nil
+}
+
+func (t *ddlTranslator) alterTable(s *ast.AlterTableStmt) error {
+	table := t.quoteTable(s.Table)
+	for _, spec := range s.Specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			for _, col := range spec.NewColumns {
+				def, err := columnDef(col)
+				if err != nil {
+					return err
+				}
+				t.add("ALTER TABLE " + table + " ADD COLUMN IF NOT EXISTS " + def)
+				if hasColumnOption(col, ast.ColumnOptionUniqKey) {
+					err := t.createIndex(s.Table, col.Name.Name.O,
+						[]*ast.IndexPartSpecification{{Column: col.Name}}, true)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		case ast.AlterTableDropColumn:
+			t.add("ALTER TABLE " + table + " DROP COLUMN IF EXISTS " +
+				quoteName(spec.OldColumnName.Name.O))
+		case ast.AlterTableRenameColumn:
+			t.add("ALTER TABLE " + table + " RENAME COLUMN " +
+				quoteName(spec.OldColumnName.Name.O) + " TO " +
+				quoteName(spec.NewColumnName.Name.O))
+		case ast.AlterTableAddConstraint:
+			if err := t.addConstraint(s.Table, spec.Constraint); err != nil {
+				return err
+			}
+		case ast.AlterTableDropIndex:
+			t.dropIndex(s.Table, spec.Name)
+		case ast.AlterTableDropPrimaryKey:
+			// The primary key constraint is named "<table>_pkey" by PostgreSQL.
+			t.add("ALTER TABLE " + table + " DROP CONSTRAINT IF EXISTS " +
+				quoteName(indexName(s.Table.Name.O, "pkey")))
+		case ast.AlterTableRenameIndex:
+			t.add("ALTER INDEX IF EXISTS " + t.quoteIndex(s.Table, spec.FromKey.O) +
+				" RENAME TO " + quoteName(indexName(s.Table.Name.O, spec.ToKey.O)))
+		case ast.AlterTableRenameTable:
+			if err := t.renameTable(s.Table, spec.NewTable); err != nil {
+				return err
+			}
+		case ast.AlterTableOption, ast.AlterTableLock, ast.AlterTableAlgorithm:
+			// Table options like comments and charsets have no effect.
+		default:
+			return unsupportedDDL(restoreNode(s))
+		}
+	}
+	return nil
+}
+
+func (t *ddlTranslator) renameTable(oldTable, newTable *ast.TableName) error {
+	// ALTER TABLE ... RENAME TO can not move a table to another schema.
+	if t.schemaOf(oldTable) != t.schemaOf(newTable) {
+		return unsupportedDDL(fmt.Sprintf("rename table %s to %s",
+			t.quoteTable(oldTable), t.quoteTable(newTable)))
+	}
+	t.add("ALTER TABLE " + t.quoteTable(oldTable) + " RENAME TO " +
+		quoteName(newTable.Name.O))
+	return nil
+}
+
+func (t *ddlTranslator) addConstraint(table *ast.TableName, constraint *ast.Constraint) error {
+	switch constraint.Tp {
+	case ast.ConstraintPrimaryKey:
+		keys, err := keyList(constraint.Keys)
+		if err != nil {
+			return err
+		}
+		t.add("ALTER TABLE " + t.quoteTable(table) + " ADD PRIMARY KEY (" + keys + ")")
+		return nil
+	case ast.ConstraintKey, ast.ConstraintIndex:
+		return t.createIndex(table, constraintName(constraint), constraint.Keys, false)
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		return t.createIndex(table, constraintName(constraint), constraint.Keys, true)
+	case ast.ConstraintForeignKey, ast.ConstraintCheck:
+		return nil
+	}
+	return unsupportedDDL(restoreNode(constraint))
+}
+
+// constraintName returns the name of an index, unnamed indexes are named
+// after the first column as in MySQL.
+func constraintName(constraint *ast.Constraint) string {
+	if constraint.Name != "" || len(constraint.Keys) == 0 || constraint.Keys[0].Column == nil {
+		return constraint.Name
+	}
+	return constraint.Keys[0].Column.Name.O
+}
+
+func (t *ddlTranslator) createIndex(
+	table *ast.TableName, index string,
+	keys []*ast.IndexPartSpecification, unique bool,
+) error {
+	columns, err := keyList(keys)
+	if err != nil {
+		return err
+	}
+	stmt := "CREATE INDEX IF NOT EXISTS "
+	if unique {
+		stmt = "CREATE UNIQUE INDEX IF NOT EXISTS "
+	}
+	t.add(stmt + quoteName(indexName(table.Name.O, index)) +
+		" ON " + t.quoteTable(table) + " (" + columns + ")")
+	return nil
+}
+
+func (t *ddlTranslator) dropIndex(table *ast.TableName, index string) {
+	t.add("DROP INDEX IF EXISTS " + t.quoteIndex(table, index))
+}
+
+// keyList returns the quoted columns of an index, prefix lengths are ignored
+// since PostgreSQL indexes the whole value.
+func keyList(keys []*ast.IndexPartSpecification) (string, error) {
+	columns := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.Expr != nil || key.Column == nil {
+			return "", unsupportedDDL("expression index " + restoreNode(key))
+		}
+		columns = append(columns, quoteName(key.Column.Name.O))
+	}
+	return strings.Join(columns, ","), nil
+}
+
+func hasColumnOption(col *ast.ColumnDef, tp ast.ColumnOptionType) bool {
+	for _, option := range col.Options {
+		if option.Tp == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// columnDef translates a column definition, unique keys are not included.
+func columnDef(col *ast.ColumnDef) (string, error) {
+	tp, err := toPostgresType(col.Tp)
+	if err != nil {
+		return "", err
+	}
+	def := quoteName(col.Name.Name.O) + " " + tp
+	for _, option := range col.Options {
+		switch option.Tp {
+		case ast.ColumnOptionNotNull:
+			def += " NOT NULL"
+		case ast.ColumnOptionNull:
+			def += " NULL"
+		case ast.ColumnOptionPrimaryKey:
+			def += " PRIMARY KEY"
+		case ast.ColumnOptionDefaultValue:
+			value, err := defaultValue(option.Expr)
+			if err != nil {
+				return "", err
+			}
+			def += " DEFAULT " + value
+		case ast.ColumnOptionGenerated:
+			// Generated columns are not replicated, their expressions
+			// can not be translated either.
+			return "", unsupportedDDL("generated column " + restoreNode(col))
+		default:
+			// The other options like AUTO_INCREMENT, ON UPDATE and COMMENT
+			// only affect the writes to TiDB.
+		}
+	}
+	return def, nil
+}
+
+// defaultValue translates the literals and the current timestamp.
+func defaultValue(expr ast.ExprNode) (string, error) {
+	switch e := expr.(type) {
+	case ast.ValueExpr:
+		return restoreNode(e), nil
+	case *ast.UnaryOperationExpr:
+		if _, ok := e.V.(ast.ValueExpr); ok {
+			return restoreNode(e), nil
+		}
+	case *ast.FuncCallExpr:
+		switch e.FnName.L {
+		case ast.CurrentTimestamp, ast.Now, ast.LocalTime, ast.LocalTimestamp:
+			return "CURRENT_TIMESTAMP", nil
+		}
+	}
+	return "", unsupportedDDL("default value " + restoreNode(expr))
+}
+
+func restoreNode(node ast.Node) string {
+	var sb strings.Builder
+	restoreFlags := format.RestoreKeyWordUppercase | format.RestoreStringSingleQuotes |
+		format.RestoreStringWithoutCharset
+	if err := node.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return fmt.Sprintf("%T", node)
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	_ "github.com/pingcap/tidb/types/parser_driver" // for parser driver
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateDDL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{
+			query:    "create database test",
+			expected: []string{`CREATE SCHEMA IF NOT EXISTS "test"`},
+		},
+		{
+			query:    "drop database test",
+			expected: []string{`DROP SCHEMA IF EXISTS "test" CASCADE`},
+		},
+		{
+			query: "create table t (id bigint unsigned auto_increment primary key, " +
+				"name varchar(32) not null default 'a', age tinyint unique, " +
+				"data json, ts timestamp(3) default current_timestamp(3), " +
+				"key idx_name(name(4)), unique key (age, name)) charset=utf8mb4",
+			expected: []string{
+				`CREATE TABLE IF NOT EXISTS "test"."t" (` +
+					`"id" numeric(20,0) PRIMARY KEY, ` +
+					`"name" varchar(32) NOT NULL DEFAULT 'a', ` +
+					`"age" smallint, "data" jsonb, ` +
+					`"ts" timestamp(3) DEFAULT CURRENT_TIMESTAMP)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "t_age" ON "test"."t" ("age")`,
+				`CREATE INDEX IF NOT EXISTS "t_idx_name" ON "test"."t" ("name")`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "t_age" ON "test"."t" ("age","name")`,
+			},
+		},
+		{
+			query: "create table test2.t (a int, b decimal(10, 2), c blob, primary key(a, b))",
+			expected: []string{
+				`CREATE TABLE IF NOT EXISTS "test2"."t" (` +
+					`"a" integer, "b" numeric(10,2), "c" bytea, PRIMARY KEY ("a","b"))`,
+			},
+		},
+		{
+			query:    "drop table t, test2.t2",
+			expected: []string{`DROP TABLE IF EXISTS "test"."t"`, `DROP TABLE IF EXISTS "test2"."t2"`},
+		},
+		{
+			query:    "truncate table t",
+			expected: []string{`TRUNCATE TABLE "test"."t"`},
+		},
+		{
+			query:    "rename table t to t1",
+			expected: []string{`ALTER TABLE "test"."t" RENAME TO "t1"`},
+		},
+		{
+			query: "alter table t add column c1 int not null, add column c2 char(4) unique, " +
+				"drop column c3, rename column c4 to c5, comment = 'x'",
+			expected: []string{
+				`ALTER TABLE "test"."t" ADD COLUMN IF NOT EXISTS "c1" integer NOT NULL`,
+				`ALTER TABLE "test"."t" ADD COLUMN IF NOT EXISTS "c2" char(4)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "t_c2" ON "test"."t" ("c2")`,
+				`ALTER TABLE "test"."t" DROP COLUMN IF EXISTS "c3"`,
+				`ALTER TABLE "test"."t" RENAME COLUMN "c4" TO "c5"`,
+			},
+		},
+		{
+			query: "alter table t add primary key (id), drop index idx, rename index i1 to i2",
+			expected: []string{
+				`ALTER TABLE "test"."t" ADD PRIMARY KEY ("id")`,
+				`DROP INDEX IF EXISTS "test"."t_idx"`,
+				`ALTER INDEX IF EXISTS "test"."t_i1" RENAME TO "t_i2"`,
+			},
+		},
+		{
+			query:    "alter table t drop primary key",
+			expected: []string{`ALTER TABLE "test"."t" DROP CONSTRAINT IF EXISTS "t_pkey"`},
+		},
+		{
+			query:    "create unique index idx on t (a, b)",
+			expected: []string{`CREATE UNIQUE INDEX IF NOT EXISTS "t_idx" ON "test"."t" ("a","b")`},
+		},
+		{
+			query:    "drop index idx on t",
+			expected: []string{`DROP INDEX IF EXISTS "test"."t_idx"`},
+		},
+		{
+			query:    "alter table t comment = 'x'",
+			expected: nil,
+		},
+	}
+	for _, tc := range testCases {
+		stmts, err := translateDDL(&model.DDLEvent{
+			TableInfo: &model.SimpleTableInfo{Schema: "test"},
+			Query:     tc.query,
+		})
+		require.Nil(t, err, tc.query)
+		require.Equal(t, tc.expected, stmts, tc.query)
+	}
+}
+
+func TestTranslateDDLUnsupported(t *testing.T) {
+	t.Parallel()
+
+	queries := []string{
+		"create view v as select 1",
+		"drop view v",
+		"create table t like t1",
+		"create table t (a int, b int as (a + 1))",
+		"create table t (a int default (rand()))",
+		"create table t (a varchar(10), key ((lower(a))))",
+		"create fulltext index idx on t (a)",
+		"rename table t to test2.t",
+		"alter table t modify column a bigint",
+		"alter table t partition by hash(a) partitions 4",
+		"create sequence s",
+	}
+	for _, query := range queries {
+		_, err := translateDDL(&model.DDLEvent{
+			TableInfo: &model.SimpleTableInfo{Schema: "test"},
+			Query:     query,
+		})
+		require.True(t, cerror.ErrPostgresDDLUnsupported.Equal(err), query)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+)
+
+// quoteName quotes an identifier of PostgreSQL.
+func quoteName(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteTable quotes a table name of PostgreSQL, the TiDB database is mapped
+// to a PostgreSQL schema.
+func quoteTable(schema, table string) string {
+	return quoteName(schema) + "." + quoteName(table)
+}
+
+// toQueryArg converts the value of a column to a query argument. Non-binary
+// strings are passed as strings so that they are not sent as bytea, and
+// unsigned integers out of the range of int64 are passed as strings since
+// database/sql does not support them.
+func toQueryArg(col *model.Column) interface{} {
+	switch v := col.Value.(type) {
+	case []byte:
+		if col.Charset != "" && col.Charset != charset.CharsetBin {
+			return string(v)
+		}
+	case uint64:
+		if v > math.MaxInt64 {
+			return strconv.FormatUint(v, 10)
+		}
+		return int64(v)
+	}
+	return col.Value
+}
+
+// toQueryArgs converts the values of the columns to query arguments, the
+// nil columns are the virtual generated columns, which are skipped by the
+// generated statements.
+func toQueryArgs(cols []*model.Column) []interface{} {
+	args := make([]interface{}, 0, len(cols))
+	for _, col := range cols {
+		if col == nil {
+			args = append(args, nil)
+			continue
+		}
+		args = append(args, toQueryArg(col))
+	}
+	return args
+}
+
+type preparedDMLs struct {
+	sqls     []string
+	values   [][]interface{}
+	rowCount int
+}
+
+// prepareDMLs converts the rows to statements of the PostgreSQL dialect by
+// sqlmodel, the statements of a row are executed in the same order as the
+// rows.
+func prepareDMLs(
+	rows []*model.RowChangedEvent, safeMode, enableOldValue, forceReplicate bool,
+) *preparedDMLs {
+	dmls := &preparedDMLs{
+		sqls:   make([]string, 0, len(rows)),
+		values: make([][]interface{}, 0, len(rows)),
+	}
+	appendDML := func(query string, args []interface{}) {
+		if query == "" {
+			return
+		}
+		dmls.sqls = append(dmls.sqls, query)
+		dmls.values = append(dmls.values, args)
+	}
+	// The rows are inserted and updated as they are only if none of them
+	// could have been written to PostgreSQL before, that is, they are
+	// committed after the tables start to be replicated. Otherwise, the
+	// rows overwrite the conflicting rows by INSERT ON CONFLICT, and the
+	// updates are replicated as DELETE + INSERT ON CONFLICT.
+	translateToInsert := enableOldValue && !safeMode
+	for _, row := range rows {
+		if !translateToInsert {
+			break
+		}
+		translateToInsert = row.CommitTs > row.ReplicatingTs
+	}
+	insertType := sqlmodel.DMLReplace
+	if translateToInsert {
+		insertType = sqlmodel.DMLInsert
+	}
+
+	for _, row := range rows {
+		cols := row.Columns
+		if len(cols) == 0 {
+			cols = row.PreColumns
+		}
+		tableInfo := model.BuildTiDBTableInfo(cols, row.IndexColumns)
+		newRowChange := func(preValues, postValues []interface{}) *sqlmodel.RowChange {
+			change := sqlmodel.NewRowChange(row.Table, nil,
+				preValues, postValues, tableInfo, nil, nil)
+			change.SetDialect(sqlmodel.DialectPostgreSQL)
+			return change
+		}
+		// The rows without a unique key can only be located by all the
+		// columns, which is allowed only if force-replicate is enabled.
+		appendLocated := func(change *sqlmodel.RowChange, tp sqlmodel.DMLType) {
+			if forceReplicate || change.HasNotNullUniqueIdx() {
+				appendDML(change.GenSQL(tp))
+			}
+		}
+
+		if translateToInsert && len(row.PreColumns) != 0 && len(row.Columns) != 0 {
+			appendLocated(newRowChange(
+				toQueryArgs(row.PreColumns), toQueryArgs(row.Columns)), sqlmodel.DMLUpdate)
+			dmls.rowCount++
+			continue
+		}
+		if len(row.PreColumns) != 0 {
+			appendLocated(newRowChange(toQueryArgs(row.PreColumns), nil), sqlmodel.DMLDelete)
+		}
+		if len(row.Columns) != 0 {
+			appendDML(newRowChange(nil, toQueryArgs(row.Columns)).GenSQL(insertType))
+		}
+		dmls.rowCount++
+	}
+	return dmls
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `"a"`, quoteName("a"))
+	require.Equal(t, `"a""b"`, quoteName(`a"b`))
+	require.Equal(t, `"s"."t"`, quoteTable("s", "t"))
+}
+
+func TestToQueryArg(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "abc", toQueryArg(&model.Column{
+		Charset: charset.CharsetUTF8MB4, Value: []byte("abc"),
+	}))
+	require.Equal(t, []byte("abc"), toQueryArg(&model.Column{
+		Charset: charset.CharsetBin, Value: []byte("abc"),
+	}))
+	require.Equal(t, int64(1), toQueryArg(&model.Column{Value: uint64(1)}))
+	require.Equal(t, "18446744073709551615",
+		toQueryArg(&model.Column{Value: uint64(18446744073709551615)}))
+	require.Nil(t, toQueryArg(&model.Column{Value: nil}))
+}
+
+func TestPrepareDMLs(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	preCols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: 1},
+		{Name: "b", Type: mysql.TypeVarchar, Charset: charset.CharsetUTF8MB4, Value: []byte("x")},
+		{Name: "c", Type: mysql.TypeLong, Flag: model.GeneratedColumnFlag, Value: 2},
+	}
+	cols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: 1},
+		{Name: "b", Type: mysql.TypeVarchar, Charset: charset.CharsetUTF8MB4, Value: []byte("y")},
+		{Name: "c", Type: mysql.TypeLong, Flag: model.GeneratedColumnFlag, Value: 2},
+	}
+	indexColumns := [][]int{{0}}
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs: 418658114257813514, CommitTs: 418658114257813515, Table: table,
+			Columns: cols, IndexColumns: indexColumns,
+		},
+		{
+			StartTs: 418658114257813516, CommitTs: 418658114257813517, Table: table,
+			PreColumns: preCols, Columns: cols, IndexColumns: indexColumns,
+		},
+		{
+			StartTs: 418658114257813518, CommitTs: 418658114257813519, Table: table,
+			PreColumns: preCols, IndexColumns: indexColumns,
+		},
+	}
+
+	testCases := []struct {
+		safeMode       bool
+		enableOldValue bool
+		expected       *preparedDMLs
+	}{
+		{
+			safeMode:       true,
+			enableOldValue: true,
+			expected: &preparedDMLs{
+				sqls: []string{
+					`INSERT INTO "test"."t" ("a","b") VALUES ($1,$2) ON CONFLICT ("a") DO UPDATE SET "b" = EXCLUDED."b"`,
+					`DELETE FROM "test"."t" WHERE "a" = $1`,
+					`INSERT INTO "test"."t" ("a","b") VALUES ($1,$2) ON CONFLICT ("a") DO UPDATE SET "b" = EXCLUDED."b"`,
+					`DELETE FROM "test"."t" WHERE "a" = $1`,
+				},
+				values:   [][]interface{}{{1, "y"}, {1}, {1, "y"}, {1}},
+				rowCount: 3,
+			},
+		},
+		{
+			safeMode:       false,
+			enableOldValue: true,
+			expected: &preparedDMLs{
+				sqls: []string{
+					`INSERT INTO "test"."t" ("a","b") VALUES ($1,$2)`,
+					`UPDATE "test"."t" SET "a" = $1, "b" = $2 WHERE "a" = $3`,
+					`DELETE FROM "test"."t" WHERE "a" = $1`,
+				},
+				values:   [][]interface{}{{1, "y"}, {1, "y", 1}, {1}},
+				rowCount: 3,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dmls := prepareDMLs(rows, tc.safeMode, tc.enableOldValue, false)
+		require.Equal(t, tc.expected, dmls)
+	}
+}
+
+func TestPrepareDMLsWithoutHandleKey(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "test", Table: "t"}
+	cols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Value: 1},
+		{Name: "b", Type: mysql.TypeLong, Value: nil},
+	}
+	rows := []*model.RowChangedEvent{
+		{StartTs: 1, CommitTs: 2, Table: table, PreColumns: cols},
+	}
+	// Rows without handle keys can not be located unless force-replicate.
+	dmls := prepareDMLs(rows, true, true, false)
+	require.Empty(t, dmls.sqls)
+	dmls = prepareDMLs(rows, true, true, true)
+	require.Equal(t, []string{`DELETE FROM "test"."t" WHERE "a" = $1 AND "b" IS NULL`}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1}}, dmls.values)
+
+	// The conflicting rows are skipped if all the columns are keys.
+	cols = []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: 1},
+	}
+	rows = []*model.RowChangedEvent{
+		{StartTs: 1, CommitTs: 2, Table: table, Columns: cols, IndexColumns: [][]int{{0}}},
+	}
+	dmls = prepareDMLs(rows, true, true, false)
+	require.Equal(t, []string{
+		`INSERT INTO "test"."t" ("a") VALUES ($1) ON CONFLICT ("a") DO NOTHING`,
+	}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1}}, dmls.values)
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWorkerCount is the default number of workers.
+	DefaultWorkerCount = 16
+	// DefaultMaxTxnRow is the default max number of rows in a transaction.
+	DefaultMaxTxnRow = 256
+	// The upper limit of max worker counts.
+	maxWorkerCount = 1024
+	// The upper limit of max txn rows.
+	maxMaxTxnRow = 2048
+
+	defaultSafeMode  = true
+	defaultDDLPolicy = DDLPolicyFail
+)
+
+const (
+	// DDLPolicySkip skips the DDLs that can not be translated for PostgreSQL.
+	DDLPolicySkip = "skip"
+	// DDLPolicyFail fails the changefeed on the DDLs that can not be
+	// translated for PostgreSQL, so that they can be applied manually.
+	DDLPolicyFail = "fail"
+)
+
+var validSchemes = map[string]bool{
+	"postgres":   true,
+	"postgresql": true,
+}
+
+// sinkQueryKeys are the parameters of the sink URI consumed by TiCDC, the
+// others are passed to the PostgreSQL driver.
+var sinkQueryKeys = []string{
+	"worker-count",
+	"max-txn-row",
+	"safe-mode",
+	"ddl-policy",
+	"transaction-atomicity",
+}
+
+type sinkParams struct {
+	changefeedID   model.ChangeFeedID
+	workerCount    int
+	maxTxnRow      int
+	safeMode       bool
+	enableOldValue bool
+	ddlPolicy      string
+	dsn            string
+}
+
+func parseSinkURIToParams(
+	changefeedID model.ChangeFeedID, sinkURI *url.URL,
+) (*sinkParams, error) {
+	params := &sinkParams{
+		changefeedID: changefeedID,
+		workerCount:  DefaultWorkerCount,
+		maxTxnRow:    DefaultMaxTxnRow,
+		safeMode:     defaultSafeMode,
+		ddlPolicy:    defaultDDLPolicy,
+	}
+
+	if sinkURI == nil {
+		return nil, cerror.ErrPostgresConnectionError.GenWithStack(
+			"fail to open PostgreSQL sink, empty URL")
+	}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	if !validSchemes[scheme] {
+		return nil, cerror.ErrPostgresConnectionError.GenWithStack(
+			"can't create PostgreSQL sink with unsupported scheme: %s", scheme)
+	}
+	query := sinkURI.Query()
+	s := query.Get("worker-count")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+		}
+		if c <= 0 {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid worker-count %d, which must be greater than 0", c))
+		}
+		if c > maxWorkerCount {
+			log.Warn("worker-count too large",
+				zap.Int("original", c), zap.Int("override", maxWorkerCount))
+			c = maxWorkerCount
+		}
+		params.workerCount = c
+	}
+	s = query.Get("max-txn-row")
+	if s != "" {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+		}
+		if c <= 0 {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid max-txn-row %d, which must be greater than 0", c))
+		}
+		if c > maxMaxTxnRow {
+			log.Warn("max-txn-row too large",
+				zap.Int("original", c), zap.Int("override", maxMaxTxnRow))
+			c = maxMaxTxnRow
+		}
+		params.maxTxnRow = c
+	}
+	s = query.Get("safe-mode")
+	if s != "" {
+		safeModeEnabled, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig, err)
+		}
+		params.safeMode = safeModeEnabled
+	}
+	s = query.Get("ddl-policy")
+	if s != "" {
+		s = strings.ToLower(s)
+		if s != DDLPolicySkip && s != DDLPolicyFail {
+			return nil, cerror.WrapError(cerror.ErrPostgresInvalidConfig,
+				fmt.Errorf("invalid ddl-policy %s, which must be skip or fail", s))
+		}
+		params.ddlPolicy = s
+	}
+
+	// The DSN is the sink URI without the parameters consumed by TiCDC,
+	// it is accepted by both lib/pq and pgx.
+	dsn := *sinkURI
+	dsn.Scheme = scheme
+	for _, key := range sinkQueryKeys {
+		query.Del(key)
+	}
+	dsn.RawQuery = query.Encode()
+	params.dsn = dsn.String()
+
+	return params, nil
+}
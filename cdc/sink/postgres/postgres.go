@@ -0,0 +1,555 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	// Register the PostgreSQL driver to database/sql.
+	_ "github.com/lib/pq"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+)
+
+const (
+	// driverName is the name of the PostgreSQL driver registered
+	// to database/sql by lib/pq.
+	driverName = "postgres"
+
+	backoffBaseDelayInMs = 500
+	backoffMaxDelayInMs  = 60 * 1000
+)
+
+var (
+	defaultDMLMaxRetry uint64 = 8
+	defaultDDLMaxRetry uint64 = 20
+)
+
+// tableTxns are the resolved txns of a table, the checkpoint of the table
+// is advanced to resolved after they are executed.
+type tableTxns struct {
+	tableID  model.TableID
+	txns     []*model.SingleTableTxn
+	resolved model.ResolvedTs
+}
+
+// postgresSink replicates the row changes and the translatable DDLs to
+// PostgreSQL. Each table is assigned to a worker, so the txns of a table are
+// executed in order, and a txn is never split into multiple PostgreSQL
+// transactions.
+type postgresSink struct {
+	db             *sql.DB
+	params         *sinkParams
+	forceReplicate bool
+
+	unresolvedRowsMu sync.Mutex
+	unresolvedRows   map[model.TableID][]*model.RowChangedEvent
+
+	tableCheckpointTs  sync.Map
+	tableMaxResolvedTs sync.Map
+	// tableDispatchedTs is the last resolved ts of each table sent to the
+	// workers, it's only written by dispatch and cleaned with the table.
+	tableDispatchedTs sync.Map
+
+	workerChs  []chan *tableTxns
+	resolvedCh chan struct{}
+
+	statistics *metrics.Statistics
+	cancel     func()
+	wg         sync.WaitGroup
+
+	// error is set when the sink has encountered an
+	// error and cannot work anymore.
+	error atomic.Error
+}
+
+// NewPostgresSink creates a new PostgreSQL sink.
+func NewPostgresSink(
+	ctx context.Context,
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*postgresSink, error) {
+	params, err := parseSinkURIToParams(changefeedID, sinkURI)
+	if err != nil {
+		return nil, err
+	}
+	params.enableOldValue = replicaConfig.EnableOldValue
+
+	db, err := GetDBConnImpl(ctx, params.dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxIdleConns(params.workerCount)
+	db.SetMaxOpenConns(params.workerCount)
+
+	log.Info("Start postgres sink",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Int("workerCount", params.workerCount),
+		zap.String("ddlPolicy", params.ddlPolicy))
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &postgresSink{
+		db:             db,
+		params:         params,
+		forceReplicate: replicaConfig.ForceReplicate,
+		unresolvedRows: make(map[model.TableID][]*model.RowChangedEvent),
+		workerChs:      make([]chan *tableTxns, params.workerCount),
+		resolvedCh:     make(chan struct{}, 1),
+		statistics:     metrics.NewStatistics(ctx, metrics.SinkTypeDB),
+		cancel:         cancel,
+	}
+	for i := range s.workerChs {
+		s.workerChs[i] = make(chan *tableTxns, 1024)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.run(ctx)
+		if err != nil && errors.Cause(err) != context.Canceled {
+			log.Error("postgres sink encountered error",
+				zap.String("namespace", changefeedID.Namespace),
+				zap.String("changefeed", changefeedID.ID),
+				zap.Error(err))
+			s.error.Store(err)
+		}
+	}()
+	return s, nil
+}
+
+// EmitRowChangedEvents appends row changed events to the unresolved rows.
+// Concurrency Note: EmitRowChangedEvents is thread-safe.
+func (s *postgresSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.RowChangedEvent) error {
+	s.unresolvedRowsMu.Lock()
+	for _, row := range rows {
+		tableID := row.Table.TableID
+		s.unresolvedRows[tableID] = append(s.unresolvedRows[tableID], row)
+	}
+	s.unresolvedRowsMu.Unlock()
+	s.statistics.AddRowsCount(len(rows))
+	return nil
+}
+
+// FlushRowChangedEvents asynchronously writes the rows of the table whose
+// commitTs are not greater than resolved, and returns the checkpoint ts of
+// the table.
+// Concurrency Note: FlushRowChangedEvents is thread-safe.
+func (s *postgresSink) FlushRowChangedEvents(
+	ctx context.Context, tableID model.TableID, resolved model.ResolvedTs,
+) (model.ResolvedTs, error) {
+	if err := s.error.Load(); err != nil {
+		return model.NewResolvedTs(0), err
+	}
+
+	v, ok := s.getTableResolvedTs(tableID)
+	if !ok || v.Less(resolved) {
+		s.tableMaxResolvedTs.Store(tableID, resolved)
+	}
+
+	select {
+	case <-ctx.Done():
+		return model.NewResolvedTs(0), ctx.Err()
+	case s.resolvedCh <- struct{}{}:
+	default:
+	}
+
+	checkpointTs := s.getTableCheckpointTs(tableID)
+	s.statistics.PrintStatus(ctx)
+	return checkpointTs, nil
+}
+
+func (s *postgresSink) run(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+	for _, ch := range s.workerChs {
+		ch := ch
+		g.Go(func() error {
+			return s.runWorker(ctx, ch)
+		})
+	}
+	g.Go(func() error {
+		return s.dispatch(ctx)
+	})
+	return g.Wait()
+}
+
+// dispatch sends the resolved txns of each table to the worker of the table.
+func (s *postgresSink) dispatch(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-s.resolvedCh:
+		}
+		var batches []*tableTxns
+		s.tableMaxResolvedTs.Range(func(k, v any) bool {
+			tableID := k.(model.TableID)
+			resolved := v.(model.ResolvedTs)
+			if last, ok := s.tableDispatchedTs.Load(tableID); ok && last.(model.ResolvedTs) == resolved {
+				return true
+			}
+			s.tableDispatchedTs.Store(tableID, resolved)
+			batches = append(batches, &tableTxns{
+				tableID:  tableID,
+				txns:     s.resolveTxns(tableID, resolved.Ts),
+				resolved: resolved,
+			})
+			return true
+		})
+		for _, batch := range batches {
+			ch := s.workerChs[uint64(batch.tableID)%uint64(len(s.workerChs))]
+			select {
+			case <-ctx.Done():
+				return errors.Trace(ctx.Err())
+			case ch <- batch:
+			}
+		}
+	}
+}
+
+// resolveTxns takes the rows of the table whose commitTs are not greater
+// than resolvedTs, and groups them into txns.
+func (s *postgresSink) resolveTxns(tableID model.TableID, resolvedTs model.Ts) []*model.SingleTableTxn {
+	s.unresolvedRowsMu.Lock()
+	rows := s.unresolvedRows[tableID]
+	i := sort.Search(len(rows), func(i int) bool {
+		return rows[i].CommitTs > resolvedTs
+	})
+	if i == len(rows) {
+		delete(s.unresolvedRows, tableID)
+	} else {
+		s.unresolvedRows[tableID] = rows[i:]
+	}
+	s.unresolvedRowsMu.Unlock()
+
+	var txns []*model.SingleTableTxn
+	for _, row := range rows[:i] {
+		var txn *model.SingleTableTxn
+		if len(txns) != 0 {
+			txn = txns[len(txns)-1]
+		}
+		if txn == nil || row.SplitTxn ||
+			txn.CommitTs != row.CommitTs || txn.StartTs != row.StartTs {
+			txn = &model.SingleTableTxn{
+				StartTs:  row.StartTs,
+				CommitTs: row.CommitTs,
+				Table:    row.Table,
+			}
+			txns = append(txns, txn)
+		}
+		txn.Append(row)
+	}
+	return txns
+}
+
+func (s *postgresSink) runWorker(ctx context.Context, ch chan *tableTxns) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case batch := <-ch:
+			if err := s.execTxns(ctx, batch.txns); err != nil {
+				return errors.Trace(err)
+			}
+			s.tableCheckpointTs.Store(batch.tableID, batch.resolved)
+		}
+	}
+}
+
+// execTxns executes the txns of a table in order. Successive txns are
+// executed in one PostgreSQL transaction until it has more than maxTxnRow
+// rows, a txn is never split.
+func (s *postgresSink) execTxns(ctx context.Context, txns []*model.SingleTableTxn) error {
+	var rows []*model.RowChangedEvent
+	for i, txn := range txns {
+		rows = append(rows, txn.Rows...)
+		if i != len(txns)-1 && len(rows)+len(txns[i+1].Rows) <= s.params.maxTxnRow {
+			continue
+		}
+		if err := s.execDMLs(ctx, rows); err != nil {
+			return err
+		}
+		rows = rows[:0]
+	}
+	return nil
+}
+
+func (s *postgresSink) execDMLs(ctx context.Context, rows []*model.RowChangedEvent) error {
+	s.statistics.ObserveRows(rows...)
+	dmls := prepareDMLs(rows, s.params.safeMode, s.params.enableOldValue, s.forceReplicate)
+	log.Debug("prepare DMLs", zap.Any("rows", rows),
+		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+	start := time.Now()
+	return retry.Do(ctx, func() error {
+		err := s.statistics.RecordBatchExecution(func() (int, error) {
+			tx, err := s.db.BeginTx(ctx, nil)
+			if err != nil {
+				return 0, cerror.WrapError(cerror.ErrPostgresTxnError, err)
+			}
+			for i, query := range dmls.sqls {
+				if _, err := tx.ExecContext(ctx, query, dmls.values[i]...); err != nil {
+					if rbErr := tx.Rollback(); rbErr != nil {
+						log.Warn("failed to rollback txn", zap.Error(rbErr))
+					}
+					return 0, cerror.WrapError(cerror.ErrPostgresTxnError, err)
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				return 0, cerror.WrapError(cerror.ErrPostgresTxnError, err)
+			}
+			return dmls.rowCount, nil
+		})
+		if err != nil {
+			log.Warn("execute DMLs with error",
+				zap.String("namespace", s.params.changefeedID.Namespace),
+				zap.String("changefeed", s.params.changefeedID.ID),
+				zap.Bool("retryable", isRetryableError(err)),
+				zap.Duration("duration", time.Since(start)),
+				zap.Int("count", dmls.rowCount),
+				zap.Error(err))
+		}
+		return err
+	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
+		retry.WithMaxTries(defaultDMLMaxRetry),
+		retry.WithIsRetryableErr(isRetryableError))
+}
+
+// isRetryableError returns whether the error returned by PostgreSQL can be
+// retried safely. The SQLSTATE is read through the SQLState method, which is
+// implemented by the errors of both lib/pq and pgx.
+func isRetryableError(err error) bool {
+	if !cerror.IsRetryableError(err) {
+		return false
+	}
+	cause := errors.Cause(err)
+	if cause == driver.ErrBadConn {
+		return true
+	}
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+	if pgErr, ok := cause.(interface{ SQLState() string }); ok {
+		state := pgErr.SQLState()
+		switch {
+		case strings.HasPrefix(state, "08"): // connection exception
+			return true
+		case strings.HasPrefix(state, "40"): // serialization failure and deadlock
+			return true
+		case state == "53300", // too many connections
+			state == "55P03", // lock not available
+			state == "57P01": // admin shutdown
+			return true
+		}
+	}
+	return false
+}
+
+// EmitCheckpointTs implements Sink.EmitCheckpointTs.
+func (s *postgresSink) EmitCheckpointTs(_ context.Context, ts uint64, _ []model.TableName) error {
+	// do nothing
+	log.Debug("emit checkpointTs", zap.Uint64("checkpointTs", ts))
+	return nil
+}
+
+// EmitDDLEvent translates the DDL and executes it in a transaction, the
+// DDLs that can not be translated are skipped or fail the changefeed
+// according to the ddl-policy of the sink URI.
+// Concurrency Note: EmitDDLEvent is thread-safe.
+func (s *postgresSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	s.statistics.AddDDLCount()
+	stmts, err := translateDDL(ddl)
+	if err != nil {
+		if cerror.ErrPostgresDDLUnsupported.Equal(err) && s.params.ddlPolicy == DDLPolicySkip {
+			log.Warn("DDL can not be translated for PostgreSQL, skip it",
+				zap.String("namespace", s.params.changefeedID.Namespace),
+				zap.String("changefeed", s.params.changefeedID.ID),
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.Error(err))
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if len(stmts) == 0 {
+		log.Info("DDL has no effect on PostgreSQL, skip it",
+			zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query))
+		return nil
+	}
+
+	return retry.Do(ctx, func() error {
+		err := s.statistics.RecordDDLExecution(func() error {
+			return s.execDDL(ctx, stmts)
+		})
+		if err != nil {
+			log.Warn("execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.Strings("stmts", stmts), zap.Error(err))
+			return cerror.WrapError(cerror.ErrPostgresTxnError, err)
+		}
+		log.Info("Exec DDL succeeded",
+			zap.String("ddl", ddl.Query), zap.Strings("stmts", stmts))
+		return nil
+	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+// execDDL executes the translated statements of a DDL in a transaction,
+// since DDLs are transactional in PostgreSQL.
+func (s *postgresSink) execDDL(ctx context.Context, stmts []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error("Failed to rollback", zap.String("sql", stmt), zap.Error(rbErr))
+			}
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// AddTable cleans up the residual values of the table.
+func (s *postgresSink) AddTable(tableID model.TableID) error {
+	s.cleanTableResource(tableID)
+	return nil
+}
+
+func (s *postgresSink) cleanTableResource(tableID model.TableID) {
+	s.tableMaxResolvedTs.Delete(tableID)
+	s.tableDispatchedTs.Delete(tableID)
+	s.tableCheckpointTs.Delete(tableID)
+	s.unresolvedRowsMu.Lock()
+	delete(s.unresolvedRows, tableID)
+	s.unresolvedRowsMu.Unlock()
+}
+
+// RemoveTable waits until all the rows of the table are written.
+func (s *postgresSink) RemoveTable(ctx context.Context, tableID model.TableID) error {
+	defer s.cleanTableResource(tableID)
+
+	warnDuration := 3 * time.Minute
+	ticker := time.NewTicker(warnDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-ticker.C:
+			maxResolved, ok := s.getTableResolvedTs(tableID)
+			log.Warn("RemoveTable doesn't return in time, may be stuck",
+				zap.Int64("tableID", tableID),
+				zap.Bool("hasResolvedTs", ok),
+				zap.Any("resolvedTs", maxResolved.Ts),
+				zap.Uint64("checkpointTs", s.getTableCheckpointTs(tableID).Ts))
+		default:
+			maxResolved, ok := s.getTableResolvedTs(tableID)
+			if !ok {
+				log.Info("No table resolvedTs is found", zap.Int64("tableID", tableID))
+				return nil
+			}
+			checkpoint, err := s.FlushRowChangedEvents(ctx, tableID, maxResolved)
+			if err != nil {
+				return err
+			}
+			if checkpoint.EqualOrGreater(maxResolved) {
+				return nil
+			}
+			// short sleep to avoid cpu spin
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+}
+
+func (s *postgresSink) getTableCheckpointTs(tableID model.TableID) model.ResolvedTs {
+	v, ok := s.tableCheckpointTs.Load(tableID)
+	if ok {
+		return v.(model.ResolvedTs)
+	}
+	return model.NewResolvedTs(0)
+}
+
+func (s *postgresSink) getTableResolvedTs(tableID model.TableID) (model.ResolvedTs, bool) {
+	v, ok := s.tableMaxResolvedTs.Load(tableID)
+	var resolved model.ResolvedTs
+	if ok {
+		resolved = v.(model.ResolvedTs)
+	}
+	return resolved, ok
+}
+
+// Close closes the sink.
+func (s *postgresSink) Close(ctx context.Context) error {
+	s.cancel()
+	s.wg.Wait()
+	err := s.db.Close()
+	return cerror.WrapError(cerror.ErrPostgresConnectionError, err)
+}
+
+// CheckReachable checks whether the downstream PostgreSQL of the sink URI
+// can be connected, nothing is written to the downstream.
+func CheckReachable(ctx context.Context, sinkURI *url.URL) error {
+	params, err := parseSinkURIToParams(model.DefaultChangeFeedID("sink-verify"), sinkURI)
+	if err != nil {
+		return err
+	}
+	db, err := GetDBConnImpl(ctx, params.dsn)
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// GetDBConnImpl is the implement holder to get db connection. Export it for tests
+var GetDBConnImpl = getDBConn
+
+func getDBConn(ctx context.Context, dsnStr string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsnStr)
+	if err != nil {
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack(
+			"fail to open PostgreSQL connection")
+	}
+	err = db.PingContext(ctx)
+	if err != nil {
+		// close db to recycle resources
+		if closeErr := db.Close(); closeErr != nil {
+			log.Warn("close db failed", zap.Error(closeErr))
+		}
+		return nil, cerror.ErrPostgresConnectionError.Wrap(err).GenWithStack(
+			"fail to open PostgreSQL connection")
+	}
+	return db, nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPgError struct {
+	state string
+}
+
+func (e *mockPgError) Error() string {
+	return "pq: " + e.state
+}
+
+func (e *mockPgError) SQLState() string {
+	return e.state
+}
+
+func newPostgresSink4Test(
+	ctx context.Context, t *testing.T, uri string,
+) (*postgresSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.Nil(t, err)
+	backupGetDBConn := GetDBConnImpl
+	GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		return db, nil
+	}
+	defer func() {
+		GetDBConnImpl = backupGetDBConn
+	}()
+
+	sinkURI, err := url.Parse(uri)
+	require.Nil(t, err)
+	s, err := NewPostgresSink(ctx, model.DefaultChangeFeedID("test"),
+		sinkURI, config.GetDefaultReplicaConfig())
+	require.Nil(t, err)
+	return s, mock
+}
+
+func newRow(commitTs uint64, id int) *model.RowChangedEvent {
+	return &model.RowChangedEvent{
+		StartTs:  commitTs - 1,
+		CommitTs: commitTs,
+		Table:    &model.TableName{Schema: "test", Table: "t", TableID: 1},
+		Columns: []*model.Column{
+			{Name: "id", Type: mysql.TypeLong, Flag: model.HandleKeyFlag, Value: id},
+		},
+		IndexColumns: [][]int{{0}},
+	}
+}
+
+func TestPostgresSinkExecTxns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mock := newPostgresSink4Test(ctx, t,
+		"postgres://127.0.0.1:5432/db?max-txn-row=2&safe-mode=false")
+
+	insert := `INSERT INTO "test"."t" ("id") VALUES ($1)`
+	// The first txn has two rows and is executed alone, the rows of the
+	// second txn are never split even if it has more than max-txn-row rows.
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insert).WithArgs(2).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WithArgs(3).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insert).WithArgs(4).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insert).WithArgs(5).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	require.Nil(t, s.AddTable(1))
+	err := s.EmitRowChangedEvents(ctx,
+		newRow(10, 1), newRow(10, 2), newRow(11, 3), newRow(11, 4), newRow(11, 5), newRow(20, 6))
+	require.Nil(t, err)
+
+	resolved := model.NewResolvedTs(11)
+	require.Eventually(t, func() bool {
+		checkpoint, err := s.FlushRowChangedEvents(ctx, 1, resolved)
+		require.Nil(t, err)
+		return checkpoint == resolved
+	}, 5*time.Second, 10*time.Millisecond)
+	// The row of commitTs 20 is not resolved yet.
+	s.unresolvedRowsMu.Lock()
+	require.Len(t, s.unresolvedRows[1], 1)
+	s.unresolvedRowsMu.Unlock()
+
+	_, ok := s.tableDispatchedTs.Load(model.TableID(1))
+	require.True(t, ok)
+
+	// Remove the table before the rows are resolved.
+	require.Nil(t, s.AddTable(1))
+	require.Nil(t, s.RemoveTable(ctx, 1))
+	_, ok = s.tableDispatchedTs.Load(model.TableID(1))
+	require.False(t, ok)
+	require.Nil(t, s.Close(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSinkRetryTxn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mock := newPostgresSink4Test(ctx, t, "postgres://127.0.0.1:5432/db")
+
+	upsert := `INSERT INTO "test"."t" ("id") VALUES ($1) ON CONFLICT ("id") DO NOTHING`
+	mock.ExpectBegin()
+	mock.ExpectExec(upsert).WithArgs(1).WillReturnError(&mockPgError{state: "40P01"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(upsert).WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+
+	require.Nil(t, s.EmitRowChangedEvents(ctx, newRow(10, 1)))
+	resolved := model.NewResolvedTs(10)
+	require.Eventually(t, func() bool {
+		checkpoint, err := s.FlushRowChangedEvents(ctx, 1, resolved)
+		require.Nil(t, err)
+		return checkpoint == resolved
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, s.Close(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSinkUnretryableError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, mock := newPostgresSink4Test(ctx, t, "postgres://127.0.0.1:5432/db")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "test"."t" ("id") VALUES ($1) ON CONFLICT ("id") DO NOTHING`).
+		WithArgs(1).WillReturnError(&mockPgError{state: "42P01"})
+	mock.ExpectRollback()
+	mock.ExpectClose()
+
+	require.Nil(t, s.EmitRowChangedEvents(ctx, newRow(10, 1)))
+	var err error
+	require.Eventually(t, func() bool {
+		_, err = s.FlushRowChangedEvents(ctx, 1, model.NewResolvedTs(10))
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Regexp(t, ".*ErrPostgresTxnError.*42P01.*", err)
+	require.Nil(t, s.Close(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresSinkEmitDDL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ddl := &model.DDLEvent{
+		StartTs:   1,
+		CommitTs:  2,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t"},
+		Query:     "alter table t add column a int, drop column b",
+	}
+	unsupported := &model.DDLEvent{
+		StartTs:   3,
+		CommitTs:  4,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t"},
+		Query:     "alter table t modify column a bigint",
+	}
+
+	s, mock := newPostgresSink4Test(ctx, t, "postgres://127.0.0.1:5432/db")
+	mock.ExpectBegin()
+	mock.ExpectExec(`ALTER TABLE "test"."t" ADD COLUMN IF NOT EXISTS "a" integer`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ALTER TABLE "test"."t" DROP COLUMN IF EXISTS "b"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	require.Nil(t, s.EmitDDLEvent(ctx, ddl))
+	err := s.EmitDDLEvent(ctx, unsupported)
+	require.True(t, cerror.ErrPostgresDDLUnsupported.Equal(err))
+	require.True(t, cerror.IsChangefeedUnRetryableError(err))
+	require.Nil(t, s.Close(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+
+	s, mock = newPostgresSink4Test(ctx, t, "postgres://127.0.0.1:5432/db?ddl-policy=skip")
+	mock.ExpectClose()
+	require.Nil(t, s.EmitDDLEvent(ctx, unsupported))
+	require.Nil(t, s.Close(ctx))
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestIsRetryableError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err       error
+		retryable bool
+	}{
+		{errors.New("test"), false},
+		{driver.ErrBadConn, true},
+		{cerror.WrapError(cerror.ErrPostgresTxnError, driver.ErrBadConn), true},
+		{cerror.WrapError(cerror.ErrPostgresTxnError, &mockPgError{state: "08006"}), true},
+		{&mockPgError{state: "40001"}, true},
+		{&mockPgError{state: "57P01"}, true},
+		{&mockPgError{state: "23505"}, false},
+		{context.Canceled, false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.retryable, isRetryableError(tc.err), tc.err)
+	}
+}
+
+func TestGetDBConnWithDriver(t *testing.T) {
+	t.Parallel()
+
+	// The driver is registered by lib/pq.
+	require.Contains(t, sql.Drivers(), driverName)
+
+	// Nothing listens on the address after the listener is closed.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	addr := l.Addr().String()
+	require.Nil(t, l.Close())
+
+	sinkURI, err := url.Parse("postgres://user:pass@" + addr + "/db?sslmode=disable")
+	require.Nil(t, err)
+	params, err := parseSinkURIToParams(model.DefaultChangeFeedID("test"), sinkURI)
+	require.Nil(t, err)
+	// The DSN of the sink is accepted by the driver.
+	_, err = pq.NewConnector(params.dsn)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = getDBConn(ctx, params.dsn)
+	require.Regexp(t, ".*ErrPostgresConnectionError.*", err)
+	require.Regexp(t, "connection refused", err)
+}
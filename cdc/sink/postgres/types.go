@@ -0,0 +1,129 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// the default precision of DECIMAL in MySQL.
+	defaultDecimalFlen = 10
+	// the max precision of TIMESTAMP in PostgreSQL.
+	maxTimestampFsp = 6
+)
+
+// toPostgresType maps a TiDB column type to a PostgreSQL column type. Values
+// are replicated as they are, so the PostgreSQL type must be able to accept
+// the values of the TiDB type, unsigned integers are widened, the binary
+// strings are mapped to bytea, ENUM and SET values are replicated as their
+// indexes and bitmasks.
+func toPostgresType(ft *types.FieldType) (string, error) {
+	unsigned := mysql.HasUnsignedFlag(ft.GetFlag())
+	binary := ft.GetCharset() == charset.CharsetBin
+	switch ft.GetType() {
+	case mysql.TypeTiny, mysql.TypeYear:
+		return "smallint", nil
+	case mysql.TypeShort:
+		if unsigned {
+			return "integer", nil
+		}
+		return "smallint", nil
+	case mysql.TypeInt24:
+		return "integer", nil
+	case mysql.TypeLong:
+		if unsigned {
+			return "bigint", nil
+		}
+		return "integer", nil
+	case mysql.TypeLonglong:
+		if unsigned {
+			return "numeric(20,0)", nil
+		}
+		return "bigint", nil
+	case mysql.TypeBit:
+		if ft.GetFlen() >= 64 {
+			return "numeric(20,0)", nil
+		}
+		return "bigint", nil
+	case mysql.TypeFloat:
+		return "real", nil
+	case mysql.TypeDouble:
+		return "double precision", nil
+	case mysql.TypeNewDecimal:
+		flen, decimal := ft.GetFlen(), ft.GetDecimal()
+		if flen == types.UnspecifiedLength {
+			flen = defaultDecimalFlen
+		}
+		if decimal == types.UnspecifiedLength {
+			decimal = 0
+		}
+		return fmt.Sprintf("numeric(%d,%d)", flen, decimal), nil
+	case mysql.TypeDate:
+		return "date", nil
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		// TIMESTAMP values are replicated in the time zone of the changefeed,
+		// so they are stored without time zone as DATETIME values.
+		return fmt.Sprintf("timestamp(%d)", timestampFsp(ft)), nil
+	case mysql.TypeDuration:
+		// TIME in TiDB ranges from -838:59:59 to 838:59:59.
+		return "interval", nil
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		if binary {
+			return "bytea", nil
+		}
+		return fmt.Sprintf("varchar(%d)", stringFlen(ft)), nil
+	case mysql.TypeString:
+		if binary {
+			return "bytea", nil
+		}
+		return fmt.Sprintf("char(%d)", stringFlen(ft)), nil
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		if binary {
+			return "bytea", nil
+		}
+		return "text", nil
+	case mysql.TypeEnum:
+		return "integer", nil
+	case mysql.TypeSet:
+		return "numeric(20,0)", nil
+	case mysql.TypeJSON:
+		return "jsonb", nil
+	}
+	return "", cerror.ErrPostgresDDLUnsupported.GenWithStackByArgs(
+		fmt.Sprintf("column type %s", ft.String()))
+}
+
+func timestampFsp(ft *types.FieldType) int {
+	fsp := ft.GetDecimal()
+	if fsp == types.UnspecifiedLength || fsp < 0 {
+		return 0
+	}
+	if fsp > maxTimestampFsp {
+		return maxTimestampFsp
+	}
+	return fsp
+}
+
+func stringFlen(ft *types.FieldType) int {
+	if ft.GetFlen() == types.UnspecifiedLength || ft.GetFlen() <= 0 {
+		return 1
+	}
+	return ft.GetFlen()
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/postgres"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
@@ -118,6 +119,15 @@ func init() {
 	sinkIniterMap["mysql+ssl"] = sinkIniterMap["mysql"]
 	sinkIniterMap["tidb+ssl"] = sinkIniterMap["mysql"]
 
+	// register postgres sink
+	sinkIniterMap["postgres"] = func(
+		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL, config *config.ReplicaConfig,
+		errCh chan error,
+	) (Sink, error) {
+		return postgres.NewPostgresSink(ctx, changefeedID, sinkURI, config)
+	}
+	sinkIniterMap["postgresql"] = sinkIniterMap["postgres"]
+
 	// register kafka sink
 	sinkIniterMap["kafka"] = func(
 		ctx context.Context, changefeedID model.ChangeFeedID, sinkURI *url.URL,
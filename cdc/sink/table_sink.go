@@ -57,9 +57,11 @@ func (t *tableSink) EmitRowChangedEvents(ctx context.Context, rows ...*model.Row
 	return nil
 }
 
+// EmitDDLEvent sends the DDL event to the backend sink directly, it is only
+// used to truncate the table in the downstream before the table is re-synced,
+// the DDL events of the changefeed are sent by the owner.
 func (t *tableSink) EmitDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
-	// the table sink doesn't receive the DDL event
-	return nil
+	return t.backendSink.EmitDDLEvent(ctx, ddl)
 }
 
 // FlushRowChangedEvents flushes sorted rows to sink manager, note the resolvedTs
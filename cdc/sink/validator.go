@@ -16,9 +16,13 @@ package sink
 import (
 	"context"
 	"net/url"
+	"strings"
 
 	"github.com/pingcap/tiflow/cdc/contextutil"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mq"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sink/postgres"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -56,6 +60,33 @@ func Validate(ctx context.Context, sinkURI string, cfg *config.ReplicaConfig) er
 	return nil
 }
 
+// CheckReachable checks whether the downstream of the sink is reachable
+// without writing anything to it. MySQL and PostgreSQL sinks ping the
+// downstream, Kafka sinks fetch the metadata of the topic. For other sinks,
+// a sink instance is created and closed like Validate.
+func CheckReachable(ctx context.Context, sinkURI string, cfg *config.ReplicaConfig) error {
+	if err := preCheckSinkURI(sinkURI); err != nil {
+		return err
+	}
+	sinkURIParsed, err := url.Parse(sinkURI)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+
+	switch strings.ToLower(sinkURIParsed.Scheme) {
+	case "mysql", "tidb", "mysql+ssl", "tidb+ssl":
+		return mysql.CheckReachable(ctx, sinkURIParsed)
+	case "postgres", "postgresql":
+		return postgres.CheckReachable(ctx, sinkURIParsed)
+	case "kafka", "kafka+ssl":
+		return mq.CheckKafkaReachable(ctx, sinkURIParsed)
+	case "blackhole":
+		return nil
+	default:
+		return Validate(ctx, sinkURI, cfg)
+	}
+}
+
 // preCheckSinkURI do some pre-check for sink URI.
 // 1. Check if sink URI is empty.
 // 2. Check if we use correct IPv6 format in URI.(if needed)
@@ -14,8 +14,10 @@
 package sink
 
 import (
+	"context"
 	"testing"
 
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -87,3 +89,14 @@ func TestPreCheckSinkURI(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckReachable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultReplicaConfig()
+	require.Nil(t, CheckReachable(ctx, "blackhole://", cfg))
+	require.Regexp(t, "sink uri is empty", CheckReachable(ctx, "", cfg))
+	require.Regexp(t, "the sink scheme \\(aaa\\) is not supported",
+		CheckReachable(ctx, "aaa://", cfg))
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package factory
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// IsSupported returns true if a DDL sink can be created for the sink URI.
+func IsSupported(sinkURIStr string) bool {
+	sinkURI, err := url.Parse(sinkURIStr)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(sinkURI.Scheme) {
+	case "mysql", "mysql+ssl", "tidb", "tidb+ssl":
+		return true
+	default:
+		return false
+	}
+}
+
+// New creates a new ddlsink.DDLEventSink by the scheme of the sink URI.
+func New(
+	ctx context.Context,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+) (ddlsink.DDLEventSink, error) {
+	sinkURI, err := url.Parse(sinkURIStr)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if err := cfg.ValidateAndAdjust(sinkURI); err != nil {
+		return nil, err
+	}
+
+	scheme := strings.ToLower(sinkURI.Scheme)
+	switch scheme {
+	case "mysql", "mysql+ssl", "tidb", "tidb+ssl":
+		s, err := mysql.NewMySQLDDLSink(ctx, sinkURI, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, cerror.ErrSinkURIInvalid.
+			GenWithStack("the sink scheme (%s) is not supported", sinkURI.Scheme)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sinkv2/ddlsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/errorutil"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDDLMaxRetry uint64 = 20
+
+	backoffBaseDelayInMs = 500
+	// in previous/backoff retry pkg, the DefaultMaxInterval = 60 * time.Second
+	backoffMaxDelayInMs = 60 * 1000
+)
+
+// Assert DDLEventSink implementation
+var _ ddlsink.DDLEventSink = (*mysqlDDLSink)(nil)
+
+type mysqlDDLSink struct {
+	// id indicates which changefeed this sink belongs to.
+	id model.ChangeFeedID
+	// db is used for DDL execution.
+	db         *sql.DB
+	statistics *metrics.Statistics
+}
+
+// NewMySQLDDLSink creates a new mysqlDDLSink.
+func NewMySQLDDLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) (*mysqlDDLSink, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	db, _, err := mysql.NewDBConnAndConfig(ctx, changefeedID, sinkURI, replicaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("MySQL DDL sink is created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID))
+	return &mysqlDDLSink{
+		id:         changefeedID,
+		db:         db,
+		statistics: metrics.NewStatistics(ctx, metrics.SinkTypeDB),
+	}, nil
+}
+
+// WriteDDLEvent executes the DDL event in the downstream.
+func (m *mysqlDDLSink) WriteDDLEvent(ctx context.Context, ddl *model.DDLEvent) error {
+	m.statistics.AddDDLCount()
+	return errors.Trace(m.execDDLWithMaxRetries(ctx, ddl))
+}
+
+func (m *mysqlDDLSink) execDDLWithMaxRetries(ctx context.Context, ddl *model.DDLEvent) error {
+	return retry.Do(ctx, func() error {
+		err := m.execDDL(ctx, ddl)
+		if errorutil.IsIgnorableMySQLDDLError(err) {
+			log.Info("execute DDL failed, but error can be ignored",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+			return nil
+		}
+		if err != nil {
+			log.Warn("execute DDL with error, retry later",
+				zap.Uint64("startTs", ddl.StartTs), zap.String("ddl", ddl.Query),
+				zap.String("namespace", m.id.Namespace),
+				zap.String("changefeed", m.id.ID),
+				zap.Error(err))
+		}
+		return err
+	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
+		retry.WithMaxTries(defaultDDLMaxRetry),
+		retry.WithIsRetryableErr(cerror.IsRetryableError))
+}
+
+func (m *mysqlDDLSink) execDDL(ctx context.Context, ddl *model.DDLEvent) error {
+	shouldSwitchDB := mysql.NeedSwitchDB(ddl)
+
+	failpoint.Inject("MySQLSinkExecDDLDelay", func() {
+		select {
+		case <-ctx.Done():
+			failpoint.Return(ctx.Err())
+		case <-time.After(time.Hour):
+		}
+		failpoint.Return(nil)
+	})
+
+	log.Info("start exec DDL", zap.Any("DDL", ddl),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	err := m.statistics.RecordDDLExecution(func() error {
+		tx, err := m.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if shouldSwitchDB {
+			_, err = tx.ExecContext(ctx, "USE "+quotes.QuoteName(ddl.TableInfo.Schema)+";")
+			if err != nil {
+				if rbErr := tx.Rollback(); rbErr != nil {
+					log.Error("Failed to rollback", zap.Error(err))
+				}
+				return err
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, ddl.Query); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error("Failed to rollback", zap.String("sql", ddl.Query), zap.Error(err))
+			}
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return cerror.WrapError(cerror.ErrMySQLTxnError, err)
+	}
+
+	log.Info("Exec DDL succeeded", zap.String("sql", ddl.Query),
+		zap.String("namespace", m.id.Namespace),
+		zap.String("changefeed", m.id.ID))
+	return nil
+}
+
+// WriteCheckpointTs does nothing, the checkpoint is not written to MySQL.
+func (m *mysqlDDLSink) WriteCheckpointTs(_ context.Context, ts uint64, _ []model.TableName) error {
+	log.Debug("write checkpointTs", zap.Uint64("checkpointTs", ts))
+	return nil
+}
+
+// Close closes the database connection.
+func (m *mysqlDDLSink) Close() error {
+	if err := m.db.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
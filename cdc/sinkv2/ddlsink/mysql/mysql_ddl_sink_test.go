@@ -0,0 +1,122 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/infoschema"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockTestDB(t *testing.T) *sql.DB {
+	// mock for test db, which is used querying TiDB session variable
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	mock.ExpectQuery("SELECT @@SESSION.sql_mode;").
+		WillReturnRows(sqlmock.NewRows([]string{"@@SESSION.sql_mode"}).
+			AddRow("ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE"))
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery("show session variables like 'allow_auto_random_explicit_insert';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("allow_auto_random_explicit_insert", "0"))
+	mock.ExpectQuery("show session variables like 'tidb_txn_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_txn_mode", "pessimistic"))
+	mock.ExpectQuery("show session variables like 'transaction_isolation';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("transaction_isolation", "REPEATED-READ"))
+	mock.ExpectQuery("show session variables like 'tidb_placement_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_placement_mode", "IGNORE"))
+	mock.ExpectQuery("select character_set_name from information_schema.character_sets " +
+		"where character_set_name = 'gbk';").
+		WillReturnRows(sqlmock.NewRows([]string{"character_set_name"}).AddRow("gbk"))
+	mock.ExpectClose()
+	return db
+}
+
+func TestWriteDDLEvent(t *testing.T) {
+	dbIndex := 0
+	backupGetDBConn := mysql.GetDBConnImpl
+	mysql.GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		if dbIndex == 0 {
+			return newMockTestDB(t), nil
+		}
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("ALTER TABLE test.t1 ADD COLUMN a int").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectExec("USE `test`;").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("ALTER TABLE test.t1 ADD COLUMN a int").
+			WillReturnError(&dmysql.MySQLError{
+				Number: uint16(infoschema.ErrColumnExists.Code()),
+			})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("CREATE DATABASE test").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+	defer func() {
+		mysql.GetDBConnImpl = backupGetDBConn
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=4")
+	require.Nil(t, err)
+	sink, err := NewMySQLDDLSink(ctx, sinkURI, config.GetDefaultReplicaConfig())
+	require.Nil(t, err)
+
+	ddl := &model.DDLEvent{
+		StartTs:  1000,
+		CommitTs: 1010,
+		TableInfo: &model.SimpleTableInfo{
+			Schema: "test",
+			Table:  "t1",
+		},
+		Type:  timodel.ActionAddColumn,
+		Query: "ALTER TABLE test.t1 ADD COLUMN a int",
+	}
+	require.Nil(t, sink.WriteDDLEvent(ctx, ddl))
+	// The column exists error can be ignored.
+	require.Nil(t, sink.WriteDDLEvent(ctx, ddl))
+
+	// Do not switch DB for schema DDLs.
+	ddl = &model.DDLEvent{
+		StartTs:  1020,
+		CommitTs: 1030,
+		TableInfo: &model.SimpleTableInfo{
+			Schema: "test",
+		},
+		Type:  timodel.ActionCreateSchema,
+		Query: "CREATE DATABASE test",
+	}
+	require.Nil(t, sink.WriteDDLEvent(ctx, ddl))
+
+	require.Nil(t, sink.WriteCheckpointTs(ctx, 1030, nil))
+	require.Nil(t, sink.Close())
+}
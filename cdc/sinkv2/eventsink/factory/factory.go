@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package factory
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
+	mqv1 "github.com/pingcap/tiflow/cdc/sink/mq"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/producer"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/txn"
+	"github.com/pingcap/tiflow/cdc/sinkv2/tablesink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/kafka"
+)
+
+// SinkFactory is the factory of sink.
+// It is responsible for creating the event sink and the table sinks on it.
+// Because there is no way to convert an eventsink.EventSink[*model.RowChangedEvent]
+// to an eventsink.EventSink[eventsink.TableEvent], we have to keep both of them.
+type SinkFactory struct {
+	rowSink eventsink.EventSink[*model.RowChangedEvent]
+	txnSink eventsink.EventSink[*model.SingleTableTxn]
+}
+
+// IsSupported returns true if a SinkFactory can be created for the sink URI.
+func IsSupported(sinkURIStr string) bool {
+	sinkURI, err := url.Parse(sinkURIStr)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(sinkURI.Scheme) {
+	case "mysql", "mysql+ssl", "tidb", "tidb+ssl", "kafka", "kafka+ssl":
+		return true
+	default:
+		return false
+	}
+}
+
+// New creates a new SinkFactory by the scheme of the sink URI.
+func New(ctx context.Context,
+	sinkURIStr string,
+	cfg *config.ReplicaConfig,
+	errCh chan error,
+) (*SinkFactory, error) {
+	sinkURI, err := url.Parse(sinkURIStr)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	if err := cfg.ValidateAndAdjust(sinkURI); err != nil {
+		return nil, err
+	}
+
+	s := &SinkFactory{}
+	scheme := strings.ToLower(sinkURI.Scheme)
+	switch scheme {
+	case "mysql", "mysql+ssl", "tidb", "tidb+ssl":
+		txnSink, err := txn.NewMySQLSink(ctx, sinkURI, cfg, errCh, 0)
+		if err != nil {
+			return nil, err
+		}
+		s.txnSink = txnSink
+	case "kafka", "kafka+ssl":
+		mqs, err := mq.NewKafkaSink(ctx, sinkURI, cfg, errCh,
+			kafka.NewSaramaAdminClient, producer.NewKafkaProducer)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		s.rowSink = mqs
+	default:
+		return nil, cerror.ErrSinkURIInvalid.
+			GenWithStack("the sink scheme (%s) is not supported", sinkURI.Scheme)
+	}
+
+	return s, nil
+}
+
+// CreateTableSink creates a TableSink by schema.
+func (s *SinkFactory) CreateTableSink(tableID model.TableID) tablesink.TableSink {
+	if s.txnSink != nil {
+		return tablesink.New[*model.SingleTableTxn](tableID, s.txnSink, &eventsink.TxnEventAppender{})
+	}
+	return tablesink.New[*model.RowChangedEvent](tableID, s.rowSink, &eventsink.RowChangeEventAppender{})
+}
+
+// ApplyPartitionNums makes the MQ sink dispatch the events of the topics with
+// the given partition numbers, it's a no-op for the other sinks.
+func (s *SinkFactory) ApplyPartitionNums(partitionNums map[string]int32) {
+	if applier, ok := s.rowSink.(mqv1.PartitionNumsApplier); ok {
+		applier.ApplyPartitionNums(partitionNums)
+	}
+}
+
+// Close closes the sink.
+func (s *SinkFactory) Close() error {
+	if s.txnSink != nil {
+		return s.txnSink.Close()
+	}
+	return s.rowSink.Close()
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package factory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockTestDB(t *testing.T) *sql.DB {
+	// mock for test db, which is used querying TiDB session variable
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	mock.ExpectQuery("SELECT @@SESSION.sql_mode;").
+		WillReturnRows(sqlmock.NewRows([]string{"@@SESSION.sql_mode"}).
+			AddRow("ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE"))
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery("show session variables like 'allow_auto_random_explicit_insert';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("allow_auto_random_explicit_insert", "0"))
+	mock.ExpectQuery("show session variables like 'tidb_txn_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_txn_mode", "pessimistic"))
+	mock.ExpectQuery("show session variables like 'transaction_isolation';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("transaction_isolation", "REPEATED-READ"))
+	mock.ExpectQuery("show session variables like 'tidb_placement_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_placement_mode", "IGNORE"))
+	mock.ExpectQuery("select character_set_name from information_schema.character_sets " +
+		"where character_set_name = 'gbk';").
+		WillReturnRows(sqlmock.NewRows([]string{"character_set_name"}).AddRow("gbk"))
+	mock.ExpectClose()
+	return db
+}
+
+func TestMySQLTableSink(t *testing.T) {
+	dbIndex := 0
+	backupGetDBConn := mysql.GetDBConnImpl
+	mysql.GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		if dbIndex == 0 {
+			return newMockTestDB(t), nil
+		}
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)").
+			WithArgs(1, "test", 2, "test").
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+	defer func() {
+		mysql.GetDBConnImpl = backupGetDBConn
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	f, err := New(ctx, "mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1",
+		config.GetDefaultReplicaConfig(), errCh)
+	require.Nil(t, err)
+
+	tableSink := f.CreateTableSink(1)
+	newRow := func(a int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  1,
+			CommitTs: 2,
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{Name: "a", Type: tmysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: a},
+				{Name: "b", Type: tmysql.TypeVarchar, Flag: 0, Value: "test"},
+			},
+			IndexColumns: [][]int{{0}},
+		}
+	}
+	tableSink.AppendRowChangedEvents(newRow(1), newRow(2))
+	tableSink.UpdateResolvedTs(model.NewResolvedTs(2))
+	require.Eventually(t, func() bool {
+		return tableSink.GetCheckpointTs() == model.NewResolvedTs(2)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Nil(t, tableSink.Close(ctx))
+	require.Nil(t, f.Close())
+	select {
+	case err := <-errCh:
+		require.FailNow(t, "unexpected error", err)
+	default:
+	}
+}
+
+func TestNewUnsupportedScheme(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, IsSupported("blackhole://"))
+	_, err := New(context.Background(), "blackhole://", config.GetDefaultReplicaConfig(), nil)
+	require.Regexp(t, ".*ErrSinkURIInvalid.*", err)
+}
+
+func TestIsSupported(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsSupported("mysql://root@127.0.0.1:3306/"))
+	require.True(t, IsSupported("tidb+ssl://root@127.0.0.1:4000/"))
+	require.True(t, IsSupported("kafka://127.0.0.1:9092/test"))
+	require.False(t, IsSupported("pulsar://127.0.0.1:6650/test"))
+	require.False(t, IsSupported("%%"))
+}
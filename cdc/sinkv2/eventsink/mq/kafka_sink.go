@@ -94,9 +94,11 @@ func NewKafkaSink(
 		}
 	}()
 
+	topicCfg := baseConfig.DeriveTopicConfig()
+	topicCfg.Templates = replicaConfig.Sink.TopicTemplates
 	topicManager, err := getTopicManagerAndTryCreateTopic(
 		baseConfig.BrokerEndpoints, topic,
-		baseConfig.DeriveTopicConfig(),
+		topicCfg,
 		adminClient,
 		saramaConfig,
 	)
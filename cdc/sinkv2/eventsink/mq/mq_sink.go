@@ -15,6 +15,7 @@ package mq
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
@@ -51,6 +52,9 @@ type sink struct {
 	// topicManager used to manage topics.
 	// It is also responsible for creating topics.
 	topicManager manager.TopicManager
+	// dispatchMu makes sure that no event is dispatched
+	// while the partition number of a topic is changing.
+	dispatchMu sync.RWMutex
 
 	// encoderBuilder builds encoder for the sink.
 	encoderBuilder codec.EncoderBuilder
@@ -106,6 +110,8 @@ func newSink(ctx context.Context,
 // WriteEvents writes events to the sink.
 // This is an asynchronously and thread-safe method.
 func (s *sink) WriteEvents(rows ...*eventsink.RowChangeCallbackableEvent) error {
+	s.dispatchMu.RLock()
+	defer s.dispatchMu.RUnlock()
 	for _, row := range rows {
 		topic := s.eventRouter.GetTopicForRowChange(row.Event)
 		partitionNum, err := s.topicManager.GetPartitionNum(topic)
@@ -125,6 +131,16 @@ func (s *sink) WriteEvents(rows ...*eventsink.RowChangeCallbackableEvent) error
 	return nil
 }
 
+// ApplyPartitionNums makes the sink dispatch the events of the topics with
+// the given partition numbers, see mqv1.PartitionChangeSink.
+func (s *sink) ApplyPartitionNums(partitionNums map[string]int32) {
+	s.dispatchMu.Lock()
+	defer s.dispatchMu.Unlock()
+	for topic, partitionNum := range partitionNums {
+		s.topicManager.ApplyPartitionNum(topic, partitionNum)
+	}
+}
+
 // Close closes the sink.
 func (s *sink) Close() error {
 	s.worker.close()
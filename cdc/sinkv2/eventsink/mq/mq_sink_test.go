@@ -17,12 +17,15 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline"
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/mq/producer"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -98,3 +101,94 @@ func TestWriteEvents(t *testing.T) {
 	err = s.Close()
 	require.Nil(t, err)
 }
+
+type mockTopicManager struct {
+	mu         sync.Mutex
+	partitions map[string]int32
+	pending    map[string]int32
+}
+
+func (m *mockTopicManager) GetPartitionNum(topic string) (int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.partitions[topic], nil
+}
+
+func (m *mockTopicManager) CreateTopicAndWaitUntilVisible(topic string) (int32, error) {
+	return m.GetPartitionNum(topic)
+}
+
+func (m *mockTopicManager) GetPendingPartitionNums() map[string]int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.pending) == 0 {
+		return nil
+	}
+	result := make(map[string]int32, len(m.pending))
+	for topic, partitionNum := range m.pending {
+		result[topic] = partitionNum
+	}
+	return result
+}
+
+func (m *mockTopicManager) ApplyPartitionNum(topic string, partitionNum int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, topic)
+	m.partitions[topic] = partitionNum
+}
+
+func TestApplyPartitionNums(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	topicManager := &mockTopicManager{
+		partitions: map[string]int32{"test": 1},
+		pending:    make(map[string]int32),
+	}
+	eventRouter, err := dispatcher.NewEventRouter(config.GetDefaultReplicaConfig(), "test")
+	require.Nil(t, err)
+	p, err := producer.NewMockProducer(ctx, nil, nil)
+	require.Nil(t, err)
+	s, err := newSink(ctx, p, topicManager, eventRouter,
+		codec.NewConfig(config.ProtocolOpen), make(chan error, 1))
+	require.Nil(t, err)
+	defer s.Close()
+
+	tableStatus := pipeline.TableStateReplicating
+	newEvent := func(value string) *eventsink.RowChangeCallbackableEvent {
+		return &eventsink.RowChangeCallbackableEvent{
+			Event: &model.RowChangedEvent{
+				CommitTs: 1,
+				Table:    &model.TableName{Schema: "a", Table: "b"},
+				Columns:  []*model.Column{{Name: "col1", Type: 1, Value: value}},
+			},
+			Callback:    func() {},
+			TableStatus: &tableStatus,
+		}
+	}
+
+	// The changed partition number is never applied by the sink itself,
+	// it's applied at the barrier driven by the owner.
+	topicManager.mu.Lock()
+	topicManager.pending["test"] = 4
+	topicManager.mu.Unlock()
+	require.Nil(t, s.WriteEvents(newEvent("aa")))
+	require.Eventually(t, func() bool {
+		return len(p.(*producer.MockProducer).GetEvents()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	partitionNum, err := topicManager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(1), partitionNum)
+
+	s.ApplyPartitionNums(map[string]int32{"test": 4})
+	partitionNum, err = topicManager.GetPartitionNum("test")
+	require.Nil(t, err)
+	require.Equal(t, int32(4), partitionNum)
+	require.Empty(t, topicManager.GetPendingPartitionNums())
+	require.Nil(t, s.WriteEvents(newEvent("bb")))
+	require.Eventually(t, func() bool {
+		return len(p.(*producer.MockProducer).GetEvents()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,358 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"go.uber.org/zap"
+)
+
+const (
+	backoffBaseDelayInMs = 500
+	// in previous/backoff retry pkg, the DefaultMaxInterval = 60 * time.Second
+	backoffMaxDelayInMs = 60 * 1000
+	defaultDMLMaxRetry  = 8
+
+	// To keep the latency low when the throughput is low.
+	maxFlushInterval = 10 * time.Millisecond
+)
+
+type mysqlBackend struct {
+	workerID   int
+	changefeed model.ChangeFeedID
+	db         *sql.DB
+	cfg        *mysql.Config
+	statistics *metrics.Statistics
+
+	events []*eventsink.TxnCallbackableEvent
+	rows   int
+}
+
+// NewMySQLBackends creates a new MySQL sink backend for every worker. All the
+// backends share the returned connection pool, the caller should close it
+// after all the backends are stopped.
+func NewMySQLBackends(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+) ([]*mysqlBackend, *sql.DB, error) {
+	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
+	db, cfg, err := mysql.NewDBConnAndConfig(ctx, changefeedID, sinkURI, replicaConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statistics := metrics.NewStatistics(ctx, metrics.SinkTypeDB)
+	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backends = append(backends, &mysqlBackend{
+			workerID:   i,
+			changefeed: changefeedID,
+			db:         db,
+			cfg:        cfg,
+			statistics: statistics,
+		})
+	}
+
+	log.Info("MySQL backends are created",
+		zap.String("namespace", changefeedID.Namespace),
+		zap.String("changefeed", changefeedID.ID),
+		zap.Int("workerCount", cfg.WorkerCount))
+	return backends, db, nil
+}
+
+// OnTxnEvent implements interface backend.
+// It returns true if the cached events should be flushed.
+func (s *mysqlBackend) OnTxnEvent(event *eventsink.TxnCallbackableEvent) (needFlush bool) {
+	s.events = append(s.events, event)
+	s.rows += len(event.Event.Rows)
+	return s.rows >= s.cfg.MaxTxnRow
+}
+
+// Flush implements interface backend.
+// All the cached events are executed in one downstream transaction.
+func (s *mysqlBackend) Flush(ctx context.Context) error {
+	if len(s.events) == 0 {
+		return nil
+	}
+
+	dmls := s.prepareDMLs()
+	log.Debug("prepare DMLs", zap.Int("workerID", s.workerID),
+		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+	for _, event := range s.events {
+		s.statistics.ObserveRows(event.Event.Rows...)
+	}
+	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
+		log.Error("execute DMLs failed", zap.Int("workerID", s.workerID), zap.Error(err))
+		return errors.Trace(err)
+	}
+
+	for _, event := range s.events {
+		if event.Callback != nil {
+			event.Callback()
+		}
+	}
+	s.events = s.events[:0]
+	s.rows = 0
+	return nil
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *mysqlBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+type preparedDMLs struct {
+	startTs  []model.Ts
+	sqls     []string
+	values   [][]interface{}
+	rowCount int
+}
+
+// prepareDMLs converts the cached events to SQLs and their arguments.
+// Successive row changes of the same kind on the same table are merged into
+// multi-row statements when batch-replace is enabled.
+func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	dmls := &preparedDMLs{
+		startTs: make([]model.Ts, 0, len(s.events)),
+		sqls:    make([]string, 0, s.rows),
+		values:  make([][]interface{}, 0, s.rows),
+	}
+
+	// translateToInsert controls the update and insert behavior.
+	translateToInsert := s.cfg.EnableOldValue && !s.cfg.SafeMode
+	for _, event := range s.events {
+		for _, row := range event.Event.Rows {
+			if !translateToInsert {
+				break
+			}
+			// It can be translated in to INSERT, if the row is committed after
+			// we starting replicating the table, which means it must not be
+			// replicated before, and there is no such row in downstream MySQL.
+			translateToInsert = row.CommitTs > row.ReplicatingTs
+		}
+	}
+	insertType := sqlmodel.DMLReplace
+	if translateToInsert {
+		insertType = sqlmodel.DMLInsert
+	}
+	batchSize := 1
+	if s.cfg.BatchReplaceEnabled && s.cfg.BatchReplaceSize > 1 {
+		batchSize = s.cfg.BatchReplaceSize
+	}
+
+	var (
+		pending     []*sqlmodel.RowChange
+		pendingType sqlmodel.RowChangeType
+	)
+	flushPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+		var query string
+		var args []interface{}
+		if pendingType == sqlmodel.RowChangeDelete {
+			query, args = sqlmodel.GenDeleteSQL(pending...)
+		} else {
+			query, args = sqlmodel.GenInsertSQL(insertType, pending...)
+		}
+		if query != "" {
+			dmls.sqls = append(dmls.sqls, query)
+			dmls.values = append(dmls.values, args)
+			dmls.rowCount += len(pending)
+		}
+		pending = pending[:0]
+	}
+	appendSingle := func(query string, args []interface{}) {
+		flushPending()
+		if query != "" {
+			dmls.sqls = append(dmls.sqls, query)
+			dmls.values = append(dmls.values, args)
+			dmls.rowCount++
+		}
+	}
+	appendBatched := func(tp sqlmodel.RowChangeType, change *sqlmodel.RowChange) {
+		if len(pending) > 0 && (pendingType != tp ||
+			len(pending) >= batchSize ||
+			!sqlmodel.SameTypeTargetAndColumns(pending[0], change)) {
+			flushPending()
+		}
+		pendingType = tp
+		pending = append(pending, change)
+	}
+
+	for _, event := range s.events {
+		if len(dmls.startTs) == 0 || dmls.startTs[len(dmls.startTs)-1] != event.Event.StartTs {
+			dmls.startTs = append(dmls.startTs, event.Event.StartTs)
+		}
+		for _, row := range event.Event.Rows {
+			cols := row.Columns
+			if len(cols) == 0 {
+				cols = row.PreColumns
+			}
+			tableInfo := model.BuildTiDBTableInfo(cols, row.IndexColumns)
+
+			// If the old value is enabled, is not in safe mode and is an update
+			// event, then translate to UPDATE.
+			// NOTICE: Only update events with the old value feature enabled will
+			// have both columns and preColumns.
+			if translateToInsert && len(row.PreColumns) != 0 && len(row.Columns) != 0 {
+				change := sqlmodel.NewRowChange(row.Table, nil,
+					getArgs(row.PreColumns), getArgs(row.Columns), tableInfo, nil, nil)
+				appendSingle(change.GenSQL(sqlmodel.DMLUpdate))
+				continue
+			}
+
+			// For update event, it is translated to DELETE + REPLACE if old value
+			// is disabled or in safe mode.
+			if len(row.PreColumns) != 0 {
+				change := sqlmodel.NewRowChange(row.Table, nil,
+					getArgs(row.PreColumns), nil, tableInfo, nil, nil)
+				// Multi-row DELETE uses `IN`, which can not match NULL values, so
+				// only the rows identified by a not null unique key are batched.
+				if change.HasNotNullUniqueIdx() {
+					appendBatched(sqlmodel.RowChangeDelete, change)
+				} else {
+					appendSingle(change.GenSQL(sqlmodel.DMLDelete))
+				}
+			}
+
+			if len(row.Columns) != 0 {
+				change := sqlmodel.NewRowChange(row.Table, nil,
+					nil, getArgs(row.Columns), tableInfo, nil, nil)
+				appendBatched(sqlmodel.RowChangeInsert, change)
+			}
+		}
+	}
+	flushPending()
+	return dmls
+}
+
+// getArgs returns the values of the columns. If the column value type is
+// []byte and charset is not binary, we get its string representation. Because
+// if we use the byte array representation, the go-sql-driver will
+// automatically set `_binary` charset for that column, which is not expected.
+// See https://github.com/go-sql-driver/mysql/blob/ce134bfc/connection.go#L267
+func getArgs(columns []*model.Column) []interface{} {
+	args := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		if col == nil {
+			args = append(args, nil)
+			continue
+		}
+		if col.Charset != "" && col.Charset != charset.CharsetBin {
+			if colValBytes, ok := col.Value.([]byte); ok {
+				args = append(args, string(colValBytes))
+				continue
+			}
+		}
+		args = append(args, col.Value)
+	}
+	return args
+}
+
+func (s *mysqlBackend) execDMLWithMaxRetries(ctx context.Context, dmls *preparedDMLs) error {
+	if len(dmls.sqls) != len(dmls.values) {
+		log.Panic("unexpected number of sqls and values",
+			zap.Strings("sqls", dmls.sqls),
+			zap.Any("values", dmls.values))
+	}
+
+	start := time.Now()
+	return retry.Do(ctx, func() error {
+		failpoint.Inject("MySQLSinkTxnRandomError", func() {
+			failpoint.Return(s.logDMLTxnErr(
+				errors.Trace(driver.ErrBadConn), start, "failpoint", dmls))
+		})
+		err := s.statistics.RecordBatchExecution(func() (int, error) {
+			tx, err := s.db.BeginTx(ctx, nil)
+			if err != nil {
+				return 0, s.logDMLTxnErr(
+					cerror.WrapError(cerror.ErrMySQLTxnError, err),
+					start, "BEGIN", dmls)
+			}
+
+			for i, query := range dmls.sqls {
+				args := dmls.values[i]
+				log.Debug("exec row", zap.Int("workerID", s.workerID),
+					zap.String("sql", query), zap.Any("args", args))
+				if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+					if rbErr := tx.Rollback(); rbErr != nil {
+						log.Warn("failed to rollback txn", zap.Error(rbErr))
+					}
+					return 0, s.logDMLTxnErr(
+						cerror.WrapError(cerror.ErrMySQLTxnError, err),
+						start, query, dmls)
+				}
+			}
+
+			if err = tx.Commit(); err != nil {
+				return 0, s.logDMLTxnErr(
+					cerror.WrapError(cerror.ErrMySQLTxnError, err),
+					start, "COMMIT", dmls)
+			}
+			return dmls.rowCount, nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		log.Debug("Exec Rows succeeded",
+			zap.String("namespace", s.changefeed.Namespace),
+			zap.String("changefeed", s.changefeed.ID),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRows", dmls.rowCount))
+		return nil
+	}, retry.WithBackoffBaseDelay(backoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(backoffMaxDelayInMs),
+		retry.WithMaxTries(defaultDMLMaxRetry),
+		retry.WithIsRetryableErr(mysql.IsRetryableDMLError))
+}
+
+func (s *mysqlBackend) logDMLTxnErr(
+	err error, start time.Time, query string, dmls *preparedDMLs,
+) error {
+	if mysql.IsRetryableDMLError(err) {
+		log.Warn("execute DMLs with error, retry later",
+			zap.Error(err), zap.Duration("duration", time.Since(start)),
+			zap.String("query", query), zap.Int("count", dmls.rowCount),
+			zap.Uint64s("startTs", dmls.startTs),
+			zap.String("namespace", s.changefeed.Namespace),
+			zap.String("changefeed", s.changefeed.ID))
+	} else {
+		log.Error("execute DMLs with error, can not retry",
+			zap.Error(err), zap.Duration("duration", time.Since(start)),
+			zap.String("query", query), zap.Int("count", dmls.rowCount),
+			zap.String("namespace", s.changefeed.Namespace),
+			zap.String("changefeed", s.changefeed.ID))
+	}
+	return err
+}
@@ -0,0 +1,303 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	tmysql "github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	mysqlv1 "github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockTestDB(t *testing.T) *sql.DB {
+	// mock for test db, which is used querying TiDB session variable
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	mock.ExpectQuery("SELECT @@SESSION.sql_mode;").
+		WillReturnRows(sqlmock.NewRows([]string{"@@SESSION.sql_mode"}).
+			AddRow("ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE"))
+	columns := []string{"Variable_name", "Value"}
+	mock.ExpectQuery("show session variables like 'allow_auto_random_explicit_insert';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("allow_auto_random_explicit_insert", "0"))
+	mock.ExpectQuery("show session variables like 'tidb_txn_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_txn_mode", "pessimistic"))
+	mock.ExpectQuery("show session variables like 'transaction_isolation';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("transaction_isolation", "REPEATED-READ"))
+	mock.ExpectQuery("show session variables like 'tidb_placement_mode';").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("tidb_placement_mode", "IGNORE"))
+	mock.ExpectQuery("select character_set_name from information_schema.character_sets " +
+		"where character_set_name = 'gbk';").
+		WillReturnRows(sqlmock.NewRows([]string{"character_set_name"}).AddRow("gbk"))
+	mock.ExpectClose()
+	return db
+}
+
+func newTestMySQLBackend(
+	ctx context.Context, t *testing.T, sinkURI string, dbMock func(sqlmock.Sqlmock),
+) *mysqlBackend {
+	dbIndex := 0
+	backupGetDBConn := mysqlv1.GetDBConnImpl
+	mysqlv1.GetDBConnImpl = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		if dbIndex == 0 {
+			return newMockTestDB(t), nil
+		}
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		dbMock(mock)
+		mock.ExpectClose()
+		return db, nil
+	}
+	defer func() {
+		mysqlv1.GetDBConnImpl = backupGetDBConn
+	}()
+
+	uri, err := url.Parse(sinkURI)
+	require.Nil(t, err)
+	backends, db, err := NewMySQLBackends(ctx, uri, config.GetDefaultReplicaConfig())
+	require.Nil(t, err)
+	require.Len(t, backends, 1)
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+	return backends[0]
+}
+
+func newTxnEvent(rows ...*model.RowChangedEvent) *eventsink.TxnCallbackableEvent {
+	return &eventsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{
+			Table:    rows[0].Table,
+			StartTs:  rows[0].StartTs,
+			CommitTs: rows[0].CommitTs,
+			Rows:     rows,
+		},
+	}
+}
+
+func TestPrepareDMLs(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "common_1", Table: "uk_without_pk", TableID: 47}
+	newColumns := func(a1 interface{}, a3 interface{}) []*model.Column {
+		return []*model.Column{nil, {
+			Name:  "a1",
+			Type:  tmysql.TypeLong,
+			Flag:  model.BinaryFlag | model.MultipleKeyFlag | model.HandleKeyFlag,
+			Value: a1,
+		}, {
+			Name:  "a3",
+			Type:  tmysql.TypeLong,
+			Flag:  model.BinaryFlag | model.MultipleKeyFlag | model.HandleKeyFlag,
+			Value: a3,
+		}}
+	}
+	testCases := []struct {
+		cfg      *mysqlv1.Config
+		rows     []*model.RowChangedEvent
+		expected *preparedDMLs
+	}{{
+		cfg: &mysqlv1.Config{SafeMode: true, BatchReplaceEnabled: true, BatchReplaceSize: 2},
+		rows: []*model.RowChangedEvent{{
+			StartTs: 418658114257813514, CommitTs: 418658114257813515, Table: table,
+			Columns: newColumns(1, 1), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813514, CommitTs: 418658114257813515, Table: table,
+			Columns: newColumns(2, 2), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813514, CommitTs: 418658114257813515, Table: table,
+			Columns: newColumns(3, 3), IndexColumns: [][]int{{1, 2}},
+		}},
+		expected: &preparedDMLs{
+			startTs: []model.Ts{418658114257813514},
+			sqls: []string{
+				"REPLACE INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?),(?,?)",
+				"REPLACE INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)",
+			},
+			values:   [][]interface{}{{1, 1, 2, 2}, {3, 3}},
+			rowCount: 3,
+		},
+	}, {
+		cfg: &mysqlv1.Config{SafeMode: true, BatchReplaceEnabled: true, BatchReplaceSize: 20},
+		rows: []*model.RowChangedEvent{{
+			StartTs: 418658114257813516, CommitTs: 418658114257813517, Table: table,
+			PreColumns: newColumns(1, 1), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813516, CommitTs: 418658114257813517, Table: table,
+			PreColumns: newColumns(2, 2), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813516, CommitTs: 418658114257813517, Table: table,
+			PreColumns: newColumns(3, 3), Columns: newColumns(4, 4), IndexColumns: [][]int{{1, 2}},
+		}},
+		expected: &preparedDMLs{
+			startTs: []model.Ts{418658114257813516},
+			sqls: []string{
+				"DELETE FROM `common_1`.`uk_without_pk` WHERE (`a1`,`a3`) IN ((?,?),(?,?),(?,?))",
+				"REPLACE INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)",
+			},
+			values:   [][]interface{}{{1, 1, 2, 2, 3, 3}, {4, 4}},
+			rowCount: 4,
+		},
+	}, {
+		cfg: &mysqlv1.Config{EnableOldValue: true, BatchReplaceEnabled: false},
+		rows: []*model.RowChangedEvent{{
+			StartTs: 418658114257813518, CommitTs: 418658114257813519, Table: table,
+			PreColumns: newColumns(1, 1), Columns: newColumns(2, 2), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813518, CommitTs: 418658114257813519, Table: table,
+			Columns: newColumns(3, 3), IndexColumns: [][]int{{1, 2}},
+		}, {
+			StartTs: 418658114257813518, CommitTs: 418658114257813519, Table: table,
+			Columns: newColumns(4, 4), IndexColumns: [][]int{{1, 2}},
+		}},
+		expected: &preparedDMLs{
+			startTs: []model.Ts{418658114257813518},
+			sqls: []string{
+				"UPDATE `common_1`.`uk_without_pk` SET `a1` = ?, `a3` = ? " +
+					"WHERE `a1` = ? AND `a3` = ? LIMIT 1",
+				"INSERT INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)",
+				"INSERT INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)",
+			},
+			values:   [][]interface{}{{2, 2, 1, 1}, {3, 3}, {4, 4}},
+			rowCount: 3,
+		},
+	}, {
+		// Rows without a not null unique key are deleted one by one.
+		cfg: &mysqlv1.Config{SafeMode: true, BatchReplaceEnabled: true, BatchReplaceSize: 20},
+		rows: []*model.RowChangedEvent{{
+			StartTs: 418658114257813520, CommitTs: 418658114257813521, Table: table,
+			PreColumns: []*model.Column{{
+				Name: "a1", Type: tmysql.TypeLong, Flag: model.NullableFlag, Value: nil,
+			}, {
+				Name: "a2", Type: tmysql.TypeVarchar, Charset: "utf8mb4",
+				Flag: model.NullableFlag, Value: []byte("你好"),
+			}},
+		}},
+		expected: &preparedDMLs{
+			startTs: []model.Ts{418658114257813520},
+			sqls: []string{
+				"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` IS ? AND `a2` = ? LIMIT 1",
+			},
+			values:   [][]interface{}{{nil, "你好"}},
+			rowCount: 1,
+		},
+	}}
+
+	for _, tc := range testCases {
+		backend := &mysqlBackend{cfg: tc.cfg}
+		for _, row := range tc.rows {
+			backend.OnTxnEvent(newTxnEvent(row))
+		}
+		require.Equal(t, tc.expected, backend.prepareDMLs())
+	}
+}
+
+func TestMySQLBackendFlush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestMySQLBackend(ctx, t,
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&max-txn-row=2",
+		func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`,`b`) VALUES (?,?),(?,?)").
+				WithArgs(1, "test", 2, "test").
+				WillReturnResult(sqlmock.NewResult(2, 2))
+			mock.ExpectCommit()
+		})
+
+	var flushed int32
+	newEvent := func(a int) *eventsink.TxnCallbackableEvent {
+		event := newTxnEvent(&model.RowChangedEvent{
+			StartTs:  uint64(a),
+			CommitTs: uint64(a + 1),
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{Name: "a", Type: tmysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: a},
+				{Name: "b", Type: tmysql.TypeVarchar, Flag: 0, Value: "test"},
+			},
+			IndexColumns: [][]int{{0}},
+		})
+		event.Callback = func() { atomic.AddInt32(&flushed, 1) }
+		return event
+	}
+
+	require.False(t, backend.OnTxnEvent(newEvent(1)))
+	require.True(t, backend.OnTxnEvent(newEvent(2)))
+	require.Nil(t, backend.Flush(ctx))
+	require.Equal(t, int32(2), atomic.LoadInt32(&flushed))
+
+	// Nothing is executed if there are no pending events.
+	require.Nil(t, backend.Flush(ctx))
+}
+
+func TestMySQLBackendFlushRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	backend := newTestMySQLBackend(ctx, t,
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1",
+		func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnError(errors.Trace(driver.ErrBadConn))
+			mock.ExpectRollback()
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(2).
+				WillReturnError(&mysql.MySQLError{
+					Number:  1062,
+					Message: "Duplicate entry '2' for key 'PRIMARY'",
+				})
+			mock.ExpectRollback()
+		})
+
+	newEvent := func(a int) *eventsink.TxnCallbackableEvent {
+		return newTxnEvent(&model.RowChangedEvent{
+			StartTs:  uint64(a),
+			CommitTs: uint64(a + 1),
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{Name: "a", Type: tmysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: a},
+			},
+			IndexColumns: [][]int{{0}},
+		})
+	}
+
+	// A bad connection is retried.
+	backend.OnTxnEvent(newEvent(1))
+	require.Nil(t, backend.Flush(ctx))
+
+	// A duplicate entry error is not retried.
+	backend.OnTxnEvent(newEvent(2))
+	err := backend.Flush(ctx)
+	require.Regexp(t, ".*ErrMySQLTxnError.*Duplicate entry.*", err)
+}
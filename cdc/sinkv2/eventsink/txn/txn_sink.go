@@ -14,9 +14,15 @@
 package txn
 
 import (
+	"context"
+	"io"
+	"net/url"
+
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink"
+	"github.com/pingcap/tiflow/cdc/sinkv2/eventsink/txn/mysql"
 	"github.com/pingcap/tiflow/pkg/causality"
+	"github.com/pingcap/tiflow/pkg/config"
 )
 
 const (
@@ -30,17 +36,48 @@ var _ eventsink.EventSink[*model.SingleTableTxn] = (*sink)(nil)
 type sink struct {
 	conflictDetector *causality.ConflictDetector[*worker, *txnEvent]
 	workers          []*worker
+	// sharedResource is shared by the backends, such as the connection pool,
+	// it is closed after all the workers are stopped. It can be nil.
+	sharedResource io.Closer
 }
 
-func newSink(backends []backend, conflictDetectorSlots int64) sink {
+// NewMySQLSink creates a mysql sink with given parameters.
+// Errors met by the backends are reported to errCh.
+func NewMySQLSink(
+	ctx context.Context,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	errCh chan<- error,
+	conflictDetectorSlots int64,
+) (eventsink.EventSink[*model.SingleTableTxn], error) {
+	backendImpls, db, err := mysql.NewMySQLBackends(ctx, sinkURI, replicaConfig)
+	if err != nil {
+		return nil, err
+	}
+	backends := make([]backend, 0, len(backendImpls))
+	for _, impl := range backendImpls {
+		backends = append(backends, impl)
+	}
+	if conflictDetectorSlots <= 0 {
+		conflictDetectorSlots = defaultConflictDetectorSlots
+	}
+	s := newSink(ctx, backends, errCh, conflictDetectorSlots)
+	s.sharedResource = db
+	return s, nil
+}
+
+func newSink(
+	ctx context.Context, backends []backend,
+	errCh chan<- error, conflictDetectorSlots int64,
+) *sink {
 	workers := make([]*worker, 0, len(backends))
 	for i, backend := range backends {
-		w := newWorker(i, backend)
-		w.runBackgroundLoop()
+		w := newWorker(i, backend, errCh)
+		w.runBackgroundLoop(ctx)
 		workers = append(workers, w)
 	}
 	detector := causality.NewConflictDetector[*worker, *txnEvent](workers, conflictDetectorSlots)
-	return sink{conflictDetector: detector, workers: workers}
+	return &sink{conflictDetector: detector, workers: workers}
 }
 
 // WriteEvents writes events to the sink.
@@ -60,5 +97,8 @@ func (s *sink) Close() error {
 	for _, w := range s.workers {
 		w.Close()
 	}
+	if s.sharedResource != nil {
+		return s.sharedResource.Close()
+	}
 	return nil
 }
@@ -15,6 +15,7 @@ package txn
 
 import (
 	"bytes"
+	"context"
 	"sort"
 	"sync/atomic"
 	"testing"
@@ -44,7 +45,7 @@ func (b *blackhole) OnTxnEvent(e *eventsink.TxnCallbackableEvent) bool {
 	return true
 }
 
-func (b *blackhole) Flush() error {
+func (b *blackhole) Flush(_ context.Context) error {
 	return nil
 }
 
@@ -59,7 +60,7 @@ func TestTxnSink(t *testing.T) {
 	for i := 0; i < 4; i++ {
 		bes = append(bes, &blackhole{block: int32(1), n: notify.Notifier{}})
 	}
-	sink := newSink(bes, defaultConflictDetectorSlots)
+	sink := newSink(context.Background(), bes, make(chan error, 1), defaultConflictDetectorSlots)
 
 	// Test `WriteEvents` shouldn't be blocked by slow workers.
 	var handled uint32 = 0
@@ -93,6 +94,67 @@ func TestTxnSink(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+// blockingBackend blocks in Flush until the context is canceled.
+type blockingBackend struct {
+	flushing chan struct{}
+}
+
+func (b *blockingBackend) OnTxnEvent(e *eventsink.TxnCallbackableEvent) bool {
+	return true
+}
+
+func (b *blockingBackend) Flush(ctx context.Context) error {
+	close(b.flushing)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *blockingBackend) MaxFlushInterval() time.Duration {
+	return time.Hour
+}
+
+type countingCloser struct {
+	closed int32
+}
+
+func (c *countingCloser) Close() error {
+	atomic.AddInt32(&c.closed, 1)
+	return nil
+}
+
+func TestTxnSinkClose(t *testing.T) {
+	t.Parallel()
+
+	bes := []backend{
+		&blockingBackend{flushing: make(chan struct{})},
+		&blockingBackend{flushing: make(chan struct{})},
+	}
+	sink := newSink(context.Background(), bes, make(chan error, 2), defaultConflictDetectorSlots)
+	closer := &countingCloser{}
+	sink.sharedResource = closer
+
+	err := sink.WriteEvents(&eventsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{
+			Rows: []*model.RowChangedEvent{{
+				Table:   &model.TableName{Schema: "test", Table: "t1"},
+				Columns: []*model.Column{{Name: "a", Value: 1}},
+			}},
+		},
+		Callback: func() {},
+	})
+	require.Nil(t, err)
+	select {
+	case <-bes[0].(*blockingBackend).flushing:
+	case <-bes[1].(*blockingBackend).flushing:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the event is not flushed")
+	}
+
+	// The flush in progress is canceled, and the shared resource is closed once.
+	require.Nil(t, sink.Close())
+	require.Equal(t, int32(1), atomic.LoadInt32(&closer.closed))
+}
+
 func TestGenKeys(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
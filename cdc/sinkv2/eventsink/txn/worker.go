@@ -14,6 +14,7 @@
 package txn
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -30,20 +31,22 @@ type txnWithNotifier struct {
 type worker struct {
 	ID      int
 	txnCh   *chann.Chann[txnWithNotifier]
-	stopped chan struct{}
+	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 	backend backend
+	errCh   chan<- error
 
 	// Fields only used in the background loop.
 	timer *time.Timer
 }
 
-func newWorker(ID int, backend backend) *worker {
+func newWorker(ID int, backend backend, errCh chan<- error) *worker {
 	return &worker{
 		ID:      ID,
 		txnCh:   chann.New[txnWithNotifier](chann.Cap(-1 /*unbounded*/)),
-		stopped: make(chan struct{}),
+		cancel:  func() {},
 		backend: backend,
+		errCh:   errCh,
 	}
 }
 
@@ -51,33 +54,35 @@ func (w *worker) Add(txn *txnEvent, unlock func()) {
 	w.txnCh.In() <- txnWithNotifier{txn, unlock}
 }
 
+// Close stops the background loop, a flush in progress is canceled.
 func (w *worker) Close() {
-	close(w.stopped)
+	w.cancel()
 	w.wg.Wait()
 	w.txnCh.Close()
 }
 
 // Run a background loop.
-func (w *worker) runBackgroundLoop() {
+func (w *worker) runBackgroundLoop(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
 	w.wg.Add(1)
 	go func() {
 		defer w.wg.Done()
 		w.timer = time.NewTimer(w.backend.MaxFlushInterval())
 		for {
 			select {
-			case <-w.stopped:
+			case <-ctx.Done():
 				log.Info("transaction sink backend worker exits expectedly",
 					zap.Int("workerID", w.ID))
 				return
 			case txn := <-w.txnCh.Out():
 				txn.wantMore()
-				if w.backend.OnTxnEvent(txn.txnEvent.TxnCallbackableEvent) && w.doFlush() {
-					log.Warn("transaction sink backend exits unexceptedly")
+				if w.backend.OnTxnEvent(txn.txnEvent.TxnCallbackableEvent) && w.doFlush(ctx) {
+					log.Warn("transaction sink backend exits unexceptedly", zap.Int("workerID", w.ID))
 					return
 				}
 			case <-w.timer.C:
-				if w.doFlush() {
-					log.Warn("transaction sink backend exits unexceptedly")
+				if w.doFlush(ctx) {
+					log.Warn("transaction sink backend exits unexceptedly", zap.Int("workerID", w.ID))
 					return
 				}
 			}
@@ -85,13 +90,25 @@ func (w *worker) runBackgroundLoop() {
 	}()
 }
 
-func (w *worker) doFlush() bool {
-	if err := w.backend.Flush(); err != nil {
-		// TODO: handle err.
+func (w *worker) doFlush(ctx context.Context) bool {
+	if err := w.backend.Flush(ctx); err != nil {
+		log.Warn("transaction sink backend fails to flush",
+			zap.Int("workerID", w.ID), zap.Error(err))
+		select {
+		case <-ctx.Done():
+		case w.errCh <- err:
+		default:
+			log.Error("error channel is full", zap.Int("workerID", w.ID), zap.Error(err))
+		}
 		return true
 	}
+	// The timer channel has already been drained if the flush is
+	// triggered by the timer itself.
 	if !w.timer.Stop() {
-		<-w.timer.C
+		select {
+		case <-w.timer.C:
+		default:
+		}
 	}
 	w.timer.Reset(w.backend.MaxFlushInterval())
 	return false
@@ -0,0 +1,340 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leveldb
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DiskQuota accounts the bytes stored on disk by the db sorter per changefeed.
+//
+// All changefeeds on a capture share the same db instances, so a lagging
+// changefeed could fill up the disk and break the others. To avoid it, every
+// changefeed is entitled to a fair share of the quota. Once a changefeed
+// exceeds its share, its input is either blocked until the sorted events are
+// consumed, or the changefeed fails, according to the policy.
+//
+// The sorted events of a table are consumed only after a resolved ts of the
+// table is received, so the input of a table is only blocked before the first
+// event after a resolved ts. The events before the next resolved ts are
+// recorded even if the changefeed exceeds its share, otherwise a large
+// transaction could block the table forever.
+type DiskQuota struct {
+	mu          sync.Mutex
+	quota       uint64
+	policy      string
+	changefeeds map[model.ChangeFeedID]*changefeedDiskUsage
+	// releasedCh is closed and recreated every time when bytes are released,
+	// to wake up all the blocked consumers.
+	releasedCh chan struct{}
+}
+
+type changefeedDiskUsage struct {
+	tables int
+	bytes  uint64
+	weight uint64
+	metric prometheus.Gauge
+}
+
+// NewDiskQuota creates a new DiskQuota, quota is the max bytes of data
+// stored on disk, 0 means unlimited.
+func NewDiskQuota(quota uint64, policy string) *DiskQuota {
+	if policy == "" {
+		policy = config.DiskQuotaPolicyBackpressure
+	}
+	return &DiskQuota{
+		quota:       quota,
+		policy:      policy,
+		changefeeds: make(map[model.ChangeFeedID]*changefeedDiskUsage),
+		releasedCh:  make(chan struct{}),
+	}
+}
+
+// attach registers a table of the changefeed.
+func (q *DiskQuota) attach(id model.ChangeFeedID) *tableDiskQuota {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok {
+		usage = &changefeedDiskUsage{
+			weight: config.ChangefeedPriorityWeight(config.ChangefeedPriorityNormal),
+			metric: sorterChangefeedDiskUsageGauge.WithLabelValues(id.Namespace, id.ID),
+		}
+		q.changefeeds[id] = usage
+	}
+	usage.tables++
+	return &tableDiskQuota{quota: q, changefeedID: id}
+}
+
+// detach unregisters a table of the changefeed.
+func (q *DiskQuota) detach(id model.ChangeFeedID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok {
+		return
+	}
+	usage.tables--
+	if usage.tables <= 0 {
+		delete(q.changefeeds, id)
+		sorterChangefeedDiskUsageGauge.DeleteLabelValues(id.Namespace, id.ID)
+		// The share of other changefeeds grows.
+		q.notifyReleased()
+	}
+}
+
+// SetQuota changes the quota and the policy, it is called when the server
+// config is reloaded. The blocked changefeeds are woken up to check their
+// new shares.
+func (q *DiskQuota) SetQuota(quota uint64, policy string) {
+	if q == nil {
+		return
+	}
+	if policy == "" {
+		policy = config.DiskQuotaPolicyBackpressure
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.quota == quota && q.policy == policy {
+		return
+	}
+	log.Info("sorter disk quota changed",
+		zap.Uint64("oldQuota", q.quota), zap.Uint64("newQuota", quota),
+		zap.String("oldPolicy", q.policy), zap.String("newPolicy", policy))
+	q.quota = quota
+	q.policy = policy
+	q.notifyReleased()
+}
+
+// SetWeight sets the weight of the changefeed, the share of a changefeed is
+// proportional to its weight. It takes effect only if the changefeed has
+// attached tables, and the weight is reset once all the tables are detached.
+func (q *DiskQuota) SetWeight(id model.ChangeFeedID, weight uint64) {
+	if q == nil || weight == 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok || usage.weight == weight {
+		return
+	}
+	usage.weight = weight
+	// The shares of all the changefeeds are changed.
+	q.notifyReleased()
+}
+
+// share should be called only if q.mu is locked.
+func (q *DiskQuota) share(usage *changefeedDiskUsage) uint64 {
+	var totalWeight uint64
+	for _, u := range q.changefeeds {
+		totalWeight += u.weight
+	}
+	if totalWeight == 0 {
+		return q.quota
+	}
+	return q.quota * usage.weight / totalWeight
+}
+
+// tryConsume records nBytes if the changefeed is within its share. A changefeed
+// holding nothing is always allowed to consume, so that an event larger than
+// the share does not block the changefeed forever.
+// It returns false with an error if the changefeed should fail.
+func (q *DiskQuota) tryConsume(id model.ChangeFeedID, nBytes uint64) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok {
+		// The table has been detached.
+		return true, nil
+	}
+	share := q.share(usage)
+	if q.quota != 0 && usage.bytes != 0 && usage.bytes+nBytes > share {
+		if q.policy == config.DiskQuotaPolicyFail {
+			return false, cerror.ErrSorterDiskQuotaExceeded.GenWithStackByArgs(
+				id, usage.bytes+nBytes, share)
+		}
+		return false, nil
+	}
+	usage.bytes += nBytes
+	usage.metric.Set(float64(usage.bytes))
+	return true, nil
+}
+
+// overshoot records nBytes even if the changefeed exceeds its share, unless
+// the policy is fail.
+func (q *DiskQuota) overshoot(id model.ChangeFeedID, nBytes uint64) error {
+	ok, err := q.tryConsume(id, nBytes)
+	if ok || err != nil {
+		return errors.Trace(err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok {
+		return nil
+	}
+	usage.bytes += nBytes
+	usage.metric.Set(float64(usage.bytes))
+	return nil
+}
+
+// consume records nBytes, it blocks until the changefeed is within its share
+// if the policy is backpressure.
+func (q *DiskQuota) consume(ctx context.Context, id model.ChangeFeedID, nBytes uint64) error {
+	for {
+		q.mu.Lock()
+		releasedCh := q.releasedCh
+		q.mu.Unlock()
+
+		ok, err := q.tryConsume(id, nBytes)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-releasedCh:
+		}
+	}
+}
+
+// release is called when nBytes of the changefeed have been consumed by
+// the downstream and can be deleted from the disk.
+func (q *DiskQuota) release(id model.ChangeFeedID, nBytes uint64) {
+	if nBytes == 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	usage, ok := q.changefeeds[id]
+	if !ok {
+		return
+	}
+	if usage.bytes < nBytes {
+		// Keys written more than once are only deleted once.
+		nBytes = usage.bytes
+	}
+	usage.bytes -= nBytes
+	usage.metric.Set(float64(usage.bytes))
+	q.notifyReleased()
+}
+
+// notifyReleased should be called only if q.mu is locked.
+func (q *DiskQuota) notifyReleased() {
+	close(q.releasedCh)
+	q.releasedCh = make(chan struct{})
+}
+
+// Usage returns the bytes stored on disk by the changefeed.
+func (q *DiskQuota) Usage(id model.ChangeFeedID) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if usage, ok := q.changefeeds[id]; ok {
+		return usage.bytes
+	}
+	return 0
+}
+
+// tableDiskQuota records the bytes consumed by a table in a DiskQuota.
+// A nil tableDiskQuota means the disk quota is disabled.
+type tableDiskQuota struct {
+	quota        *DiskQuota
+	changefeedID model.ChangeFeedID
+	bytes        uint64 // atomic
+	detached     int32  // atomic
+}
+
+func (t *tableDiskQuota) consume(ctx context.Context, nBytes uint64) error {
+	if t == nil || atomic.LoadInt32(&t.detached) != 0 {
+		return nil
+	}
+	if err := t.quota.consume(ctx, t.changefeedID, nBytes); err != nil {
+		return errors.Trace(err)
+	}
+	atomic.AddUint64(&t.bytes, nBytes)
+	return nil
+}
+
+func (t *tableDiskQuota) overshoot(nBytes uint64) error {
+	if t == nil || atomic.LoadInt32(&t.detached) != 0 {
+		return nil
+	}
+	if err := t.quota.overshoot(t.changefeedID, nBytes); err != nil {
+		return errors.Trace(err)
+	}
+	atomic.AddUint64(&t.bytes, nBytes)
+	return nil
+}
+
+func (t *tableDiskQuota) tryConsume(nBytes uint64) (bool, error) {
+	if t == nil || atomic.LoadInt32(&t.detached) != 0 {
+		return true, nil
+	}
+	ok, err := t.quota.tryConsume(t.changefeedID, nBytes)
+	if ok {
+		atomic.AddUint64(&t.bytes, nBytes)
+	}
+	return ok, errors.Trace(err)
+}
+
+func (t *tableDiskQuota) release(nBytes uint64) {
+	if t == nil {
+		return
+	}
+	for {
+		bytes := atomic.LoadUint64(&t.bytes)
+		released := nBytes
+		if bytes < released {
+			released = bytes
+		}
+		if atomic.CompareAndSwapUint64(&t.bytes, bytes, bytes-released) {
+			t.quota.release(t.changefeedID, released)
+			return
+		}
+	}
+}
+
+// detach releases all the bytes held by the table and unregisters the table.
+func (t *tableDiskQuota) detach() {
+	if t == nil || !atomic.CompareAndSwapInt32(&t.detached, 0, 1) {
+		return
+	}
+	t.quota.release(t.changefeedID, atomic.SwapUint64(&t.bytes, 0))
+	t.quota.detach(t.changefeedID)
+}
+
+// approximateDiskSize returns the approximate bytes of the event stored in db.
+func approximateDiskSize(event *model.PolymorphicEvent) uint64 {
+	// uniqueID, tableID, CRTs, startTs, Put/Delete and the key are encoded in
+	// the db key, see encoding.EncodeKey.
+	const keyOverhead = 4 + 8 + 8 + 8 + 2
+	return uint64(keyOverhead + event.RawKV.ApproximateDataSize())
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leveldb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskQuotaDisabled(t *testing.T) {
+	t.Parallel()
+
+	var quota *DiskQuota
+	table := quota.attach(model.DefaultChangeFeedID("test"))
+	require.Nil(t, table)
+	require.Nil(t, table.consume(context.Background(), 1024))
+	ok, err := table.tryConsume(1024)
+	require.True(t, ok)
+	require.Nil(t, err)
+	table.release(1024)
+	table.detach()
+}
+
+func TestDiskQuotaBackpressure(t *testing.T) {
+	t.Parallel()
+
+	quota := NewDiskQuota(1000, config.DiskQuotaPolicyBackpressure)
+	cf1 := model.DefaultChangeFeedID("test-1")
+	cf2 := model.DefaultChangeFeedID("test-2")
+	table1 := quota.attach(cf1)
+	table2 := quota.attach(cf1)
+	table3 := quota.attach(cf2)
+
+	// The share of each changefeed is 500.
+	ok, err := table1.tryConsume(300)
+	require.True(t, ok)
+	require.Nil(t, err)
+	ok, err = table2.tryConsume(200)
+	require.True(t, ok)
+	require.Nil(t, err)
+	ok, err = table2.tryConsume(1)
+	require.False(t, ok)
+	require.Nil(t, err)
+	require.Equal(t, uint64(500), quota.Usage(cf1))
+
+	// A changefeed holding nothing can always consume.
+	ok, err = table3.tryConsume(800)
+	require.True(t, ok)
+	require.Nil(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- table1.consume(context.Background(), 100)
+	}()
+	select {
+	case <-done:
+		t.Fatal("consume should be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	table2.release(200)
+	require.Nil(t, <-done)
+	require.Equal(t, uint64(400), quota.Usage(cf1))
+
+	// Releasing more than consumed is tolerated.
+	table2.release(100)
+	require.Equal(t, uint64(400), quota.Usage(cf1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- table1.consume(ctx, 200)
+	}()
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// Detaching the last table of a changefeed enlarges the share of others.
+	table3.detach()
+	table3.detach()
+	require.Equal(t, uint64(0), quota.Usage(cf2))
+	require.Nil(t, table1.consume(context.Background(), 500))
+	require.Equal(t, uint64(900), quota.Usage(cf1))
+
+	table1.detach()
+	require.Equal(t, uint64(0), quota.Usage(cf1))
+	table2.detach()
+}
+
+func TestDiskQuotaFail(t *testing.T) {
+	t.Parallel()
+
+	quota := NewDiskQuota(1000, config.DiskQuotaPolicyFail)
+	cf := model.DefaultChangeFeedID("test")
+	table := quota.attach(cf)
+	require.Nil(t, table.consume(context.Background(), 600))
+	err := table.consume(context.Background(), 600)
+	require.True(t, cerror.ErrSorterDiskQuotaExceeded.Equal(err), err)
+	ok, err := table.tryConsume(600)
+	require.False(t, ok)
+	require.True(t, cerror.ErrSorterDiskQuotaExceeded.Equal(err), err)
+	require.Equal(t, uint64(600), quota.Usage(cf))
+}
+
+func TestDiskQuotaWeight(t *testing.T) {
+	t.Parallel()
+
+	quota := NewDiskQuota(1000, config.DiskQuotaPolicyBackpressure)
+	cf1 := model.DefaultChangeFeedID("test-1")
+	cf2 := model.DefaultChangeFeedID("test-2")
+	// The weight of a changefeed without tables is ignored.
+	quota.SetWeight(cf1, config.ChangefeedPriorityWeight(config.ChangefeedPriorityHigh))
+	table1 := quota.attach(cf1)
+	table2 := quota.attach(cf2)
+	defer table1.detach()
+	defer table2.detach()
+
+	// The share of each changefeed is 500 by default.
+	ok, err := table1.tryConsume(500)
+	require.True(t, ok)
+	require.Nil(t, err)
+	ok, err = table1.tryConsume(1)
+	require.False(t, ok)
+	require.Nil(t, err)
+
+	// The shares of the high and low priority changefeeds are 800 and 200,
+	// and the blocked consumption is waked up once the weights are changed.
+	done := make(chan error, 1)
+	go func() {
+		done <- table1.consume(context.Background(), 300)
+	}()
+	select {
+	case <-done:
+		t.Fatal("consume should be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	quota.SetWeight(cf1, config.ChangefeedPriorityWeight(config.ChangefeedPriorityHigh))
+	quota.SetWeight(cf2, config.ChangefeedPriorityWeight(config.ChangefeedPriorityLow))
+	require.Nil(t, <-done)
+	require.Equal(t, uint64(800), quota.Usage(cf1))
+
+	ok, err = table2.tryConsume(200)
+	require.True(t, ok)
+	require.Nil(t, err)
+	ok, err = table2.tryConsume(1)
+	require.False(t, ok)
+	require.Nil(t, err)
+}
+
+func TestDiskQuotaSetQuota(t *testing.T) {
+	t.Parallel()
+
+	quota := NewDiskQuota(1000, config.DiskQuotaPolicyBackpressure)
+	cf := model.DefaultChangeFeedID("test")
+	table := quota.attach(cf)
+	defer table.detach()
+	require.Nil(t, table.consume(context.Background(), 1000))
+
+	// The blocked consumption is waked up once the quota is enlarged.
+	done := make(chan error, 1)
+	go func() {
+		done <- table.consume(context.Background(), 500)
+	}()
+	select {
+	case <-done:
+		t.Fatal("consume should be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	quota.SetQuota(2000, "")
+	require.Nil(t, <-done)
+	require.Equal(t, uint64(1500), quota.Usage(cf))
+
+	// The new policy is applied to the following consumption.
+	quota.SetQuota(1000, config.DiskQuotaPolicyFail)
+	err := table.consume(context.Background(), 1)
+	require.True(t, cerror.ErrSorterDiskQuotaExceeded.Equal(err), err)
+
+	var disabled *DiskQuota
+	disabled.SetQuota(1000, config.DiskQuotaPolicyFail)
+}
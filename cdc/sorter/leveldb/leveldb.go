@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
@@ -32,11 +33,33 @@ import (
 	"golang.org/x/sync/semaphore"
 )
 
-// Queue of IterRequest
+// iterStride is the stride of a changefeed with weight 1, the stride of a
+// changefeed is iterStride / weight.
+const iterStride = 1 << 20
+
+// Queue of IterRequest.
+//
+// The iterators of a db are shared by all the changefeeds on the capture,
+// they are allocated by stride scheduling, so that a changefeed gets the
+// iterators in proportion to the weight of its priority when the db is busy.
+// The requests of a changefeed are served in FIFO order.
 type iterQueue struct {
-	*list.List
 	// TableID set.
 	tables map[tableKey]struct{}
+	// The pending requests of each changefeed.
+	changefeeds map[model.ChangeFeedID]*changefeedIterQueue
+	// pass is the pass of the changefeed served last, changefeeds that
+	// become active start from it, so they can not claim the iterators
+	// for the time they are idle.
+	pass uint64
+}
+
+type changefeedIterQueue struct {
+	*list.List
+	weight uint64
+	// pass is advanced by the stride of the changefeed every time a request
+	// is served, the changefeed with the minimal pass is served first.
+	pass uint64
 }
 
 type iterItem struct {
@@ -49,6 +72,13 @@ type tableKey struct {
 	TableID uint64
 }
 
+func newIterQueue() iterQueue {
+	return iterQueue{
+		tables:      make(map[tableKey]struct{}),
+		changefeeds: make(map[model.ChangeFeedID]*changefeedIterQueue),
+	}
+}
+
 func (q *iterQueue) push(uid uint32, tableID uint64, req *message.IterRequest) {
 	key := tableKey{UID: uid, TableID: tableID}
 	_, ok := q.tables[key]
@@ -59,20 +89,58 @@ func (q *iterQueue) push(uid uint32, tableID uint64, req *message.IterRequest) {
 			zap.Uint64("resolvedTs", req.ResolvedTs))
 	}
 	q.tables[key] = struct{}{}
-	q.List.PushBack(iterItem{req: req, key: key})
+
+	cq, ok := q.changefeeds[req.ChangefeedID]
+	if !ok {
+		cq = &changefeedIterQueue{List: list.New(), pass: q.pass}
+		q.changefeeds[req.ChangefeedID] = cq
+	}
+	if cq.Len() == 0 && cq.pass < q.pass {
+		cq.pass = q.pass
+	}
+	cq.weight = req.Weight
+	if cq.weight == 0 {
+		cq.weight = config.ChangefeedPriorityWeight(config.ChangefeedPriorityNormal)
+	}
+	cq.PushBack(iterItem{req: req, key: key})
 }
 
 func (q *iterQueue) pop() (*message.IterRequest, bool) {
-	item := q.List.Front()
-	if item == nil {
+	var next *changefeedIterQueue
+	var nextID model.ChangeFeedID
+	for id, cq := range q.changefeeds {
+		if cq.Len() == 0 {
+			// The changefeed is idle and it has not been served ahead of
+			// others, it's the same as a new one.
+			if cq.pass <= q.pass {
+				delete(q.changefeeds, id)
+			}
+			continue
+		}
+		if next == nil || cq.pass < next.pass ||
+			(cq.pass == next.pass && lessChangefeedID(id, nextID)) {
+			next, nextID = cq, id
+		}
+	}
+	if next == nil {
 		return nil, false
 	}
-	q.List.Remove(item)
+	item := next.Front()
+	next.Remove(item)
+	q.pass = next.pass
+	next.pass += iterStride / next.weight
 	req := item.Value.(iterItem)
 	delete(q.tables, req.key)
 	return req.req, true
 }
 
+func lessChangefeedID(a, b model.ChangeFeedID) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.ID < b.ID
+}
+
 // DBActor is a db actor, it reads, writes and deletes key value pair in its db.
 type DBActor struct {
 	id      actor.ID
@@ -119,10 +187,7 @@ func NewDBActor(
 		db:      db,
 		wb:      wb,
 		iterSem: iterSema,
-		iterQ: iterQueue{
-			List:   list.New(),
-			tables: make(map[tableKey]struct{}),
-		},
+		iterQ:   newIterQueue(),
 		wbSize:  wbSize,
 		wbCap:   wbCap,
 		compact: compact,
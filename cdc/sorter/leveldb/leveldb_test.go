@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
@@ -285,6 +286,80 @@ func TestAcquireIterators(t *testing.T) {
 	require.Nil(t, db.Close())
 }
 
+func TestAcquireIteratorsByWeight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
+	cfg.Count = 1
+
+	db, err := db.OpenPebble(ctx, 1, t.TempDir(), cfg, db.WithTableCRTsCollectors())
+	require.Nil(t, err)
+	closedWg := new(sync.WaitGroup)
+
+	// Set max iterator count to 1, so the changefeeds contend for it.
+	cfg.Concurrency = 1
+	compact := NewCompactScheduler(actor.NewRouter[message.Task](t.Name()))
+	ldb, _, err := NewDBActor(0, db, cfg, compact, closedWg)
+	require.Nil(t, err)
+
+	high := model.DefaultChangeFeedID("high")
+	low := model.DefaultChangeFeedID("low")
+	weights := map[model.ChangeFeedID]uint64{
+		high: config.ChangefeedPriorityWeight(config.ChangefeedPriorityHigh),
+		low:  config.ChangefeedPriorityWeight(config.ChangefeedPriorityLow),
+	}
+	type grant struct {
+		changefeedID model.ChangeFeedID
+		tableID      uint64
+		iter         *message.LimitedIterator
+	}
+	grantCh := make(chan grant, 1)
+	request := func(changefeedID model.ChangeFeedID, tableID uint64) actormsg.Message[message.Task] {
+		return actormsg.ValueMessage(message.Task{
+			TableID: tableID,
+			IterReq: &message.IterRequest{
+				ChangefeedID: changefeedID,
+				Weight:       weights[changefeedID],
+				Range:        [2][]byte{{0x00}, {0xff}},
+				IterCallback: func(iter *message.LimitedIterator) {
+					grantCh <- grant{changefeedID: changefeedID, tableID: tableID, iter: iter}
+				},
+			},
+		})
+	}
+
+	// Every changefeed has 4 tables that keep reading from the db.
+	tasks := []actormsg.Message[message.Task]{}
+	for i := uint64(0); i < 4; i++ {
+		tasks = append(tasks, request(high, i), request(low, 100+i))
+	}
+	require.True(t, ldb.Poll(ctx, tasks))
+
+	granted := make(map[model.ChangeFeedID]int)
+	for i := 0; i < 100; i++ {
+		g := <-grantCh
+		granted[g.changefeedID]++
+		// The table requests a new iterator once it releases the old one.
+		require.Nil(t, g.iter.Release())
+		require.True(t, ldb.Poll(ctx, []actormsg.Message[message.Task]{
+			request(g.changefeedID, g.tableID),
+		}))
+	}
+	// The iterators are shared by the weights of the priorities.
+	require.Equal(t, 80, granted[high])
+	require.Equal(t, 20, granted[low])
+
+	g := <-grantCh
+	require.Nil(t, g.iter.Release())
+	require.False(t, ldb.Poll(ctx, []actormsg.Message[message.Task]{
+		actormsg.StopMessage[message.Task](),
+	}))
+	ldb.OnClose()
+	closedWg.Wait()
+	require.Nil(t, db.Close())
+}
+
 type sortedMap struct {
 	// sorted keys
 	kvs map[message.Key][]byte
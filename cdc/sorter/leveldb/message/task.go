@@ -70,6 +70,11 @@ type ReadTs struct {
 type IterRequest struct {
 	UID uint32
 
+	// The changefeed of the table and the weight of its priority, the
+	// iterators of a db are shared by the changefeeds by their weights.
+	ChangefeedID model.ChangeFeedID
+	Weight       uint64
+
 	// The resolved ts at the time of issuing the request.
 	ResolvedTs uint64
 	// Range of a requested iterator.
@@ -49,6 +49,13 @@ var (
 		Help:      "Bucketed histogram of db sorter iterator read duration",
 		Buckets:   prometheus.ExponentialBuckets(0.004, 2.0, 20),
 	}, []string{"namespace", "id", "call"})
+
+	sorterChangefeedDiskUsageGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "db_changefeed_on_disk_bytes",
+		Help:      "The approximate bytes of data stored on disk by db sorter for a changefeed",
+	}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -57,4 +64,5 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterCompactDurationHistogram)
 	registry.MustRegister(sorterWriteBytesHistogram)
 	registry.MustRegister(sorterIterReadDurationHistogram)
+	registry.MustRegister(sorterChangefeedDiskUsageGauge)
 }
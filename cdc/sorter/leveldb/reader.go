@@ -109,6 +109,8 @@ func (r *reader) outputBufferedResolvedEvents(buffer *outputBuffer) {
 	remainIdx := 0
 	// Commit ts of the last outputted events.
 	lastCommitTs := uint64(0)
+	// Bytes of outputted events, they are going to be deleted.
+	outputBytes := uint64(0)
 	for idx := range buffer.resolvedEvents {
 		event := buffer.resolvedEvents[idx]
 		ok := r.output(event)
@@ -121,9 +123,11 @@ func (r *reader) outputBufferedResolvedEvents(buffer *outputBuffer) {
 		// Delete sent events.
 		key := encoding.EncodeKey(r.uid, r.tableID, event)
 		buffer.appendDeleteKey(message.Key(key))
+		outputBytes += approximateDiskSize(event)
 		remainIdx = idx + 1
 	}
 	r.metricTotalEventsKV.Add(float64(remainIdx))
+	r.diskQuota.release(outputBytes)
 	// Remove outputted events.
 	buffer.shiftResolvedEvents(remainIdx)
 
@@ -252,6 +256,11 @@ type pollState struct {
 	readerID     actor.ID
 	readerRouter *actor.Router[message.Task]
 
+	// The changefeed of the table and the weight of its priority, they are
+	// carried by the iterator requests.
+	changefeedID model.ChangeFeedID
+	weight       uint64
+
 	// Compactor actor ID.
 	compactorID actor.ID
 	// A scheduler that triggers db compaction to speed up Iterator.Seek().
@@ -338,6 +347,8 @@ func (state *pollState) tryGetIterator(uid uint32, tableID uint64) (*message.Ite
 			lowerBoundTs = state.exhaustedResolvedTs
 		}
 		return &message.IterRequest{
+			ChangefeedID: state.changefeedID,
+			Weight:       state.weight,
 			Range: [2][]byte{
 				encoding.EncodeTsKey(uid, tableID, lowerBoundTs+1),
 				encoding.EncodeTsKey(uid, tableID, state.maxResolvedTs+1),
@@ -56,6 +56,9 @@ type common struct {
 	serde    *encoding.MsgPackGenSerde
 	errCh    chan error
 	closedWg *sync.WaitGroup
+
+	// diskQuota is nil if the sorter disk quota is disabled.
+	diskQuota *tableDiskQuota
 }
 
 // reportError notifies Sorter to return an error and close.
@@ -83,15 +86,19 @@ type Sorter struct {
 
 	outputCh chan *model.PolymorphicEvent
 	closed   int32
+	// unresolved is true if an event is added after the last resolved ts,
+	// the events before the next resolved ts overshoot the disk quota.
+	unresolved bool
 }
 
-// NewSorter creates a new Sorter
+// NewSorter creates a new Sorter, weight is the weight of the priority of the
+// changefeed, the iterators of a db are shared by the changefeeds by weights.
 func NewSorter(
-	ctx context.Context, tableID int64, startTs uint64,
+	ctx context.Context, tableID int64, startTs uint64, weight uint64,
 	dbRouter *actor.Router[message.Task], dbActorID actor.ID,
 	writerSystem *actor.System[message.Task], writerRouter *actor.Router[message.Task],
 	readerSystem *actor.System[message.Task], readerRouter *actor.Router[message.Task],
-	compact *CompactScheduler, cfg *config.DBConfig,
+	compact *CompactScheduler, diskQuota *DiskQuota, cfg *config.DBConfig,
 ) (*Sorter, error) {
 	changefeedID := contextutil.ChangefeedIDFromCtx(ctx)
 	metricIterDuration := sorterIterReadDurationHistogram.MustCurryWith(
@@ -119,6 +126,7 @@ func NewSorter(
 		serde:     &encoding.MsgPackGenSerde{},
 		errCh:     make(chan error, 1),
 		closedWg:  &sync.WaitGroup{},
+		diskQuota: diskQuota.attach(changefeedID),
 	}
 
 	w := &writer{
@@ -150,6 +158,9 @@ func NewSorter(
 			readerID:     actorID,
 			readerRouter: readerRouter,
 
+			changefeedID: changefeedID,
+			weight:       weight,
+
 			compactorID:           dbActorID,
 			compact:               compact,
 			iterMaxAliveDuration:  time.Duration(cfg.IteratorMaxAliveDuration) * time.Millisecond,
@@ -208,6 +219,7 @@ func (ls *Sorter) Run(ctx context.Context) error {
 	ls.closedWg.Wait()
 
 	_ = ls.cleanup(ctx1)
+	ls.diskQuota.detach()
 	return errors.Trace(err)
 }
 
@@ -216,6 +228,23 @@ func (ls *Sorter) AddEntry(ctx context.Context, event *model.PolymorphicEvent) {
 	if atomic.LoadInt32(&ls.closed) != 0 {
 		return
 	}
+	if event.IsResolved() {
+		ls.unresolved = false
+	} else {
+		var err error
+		if ls.unresolved {
+			err = ls.diskQuota.overshoot(approximateDiskSize(event))
+		} else {
+			err = ls.diskQuota.consume(ctx, approximateDiskSize(event))
+		}
+		if err != nil {
+			if errors.Cause(err) != context.Canceled {
+				ls.reportError("failed to consume sorter disk quota", err)
+			}
+			return
+		}
+		ls.unresolved = true
+	}
 	msg := actormsg.ValueMessage(message.Task{
 		UID:        ls.uid,
 		TableID:    ls.tableID,
@@ -231,6 +260,22 @@ func (ls *Sorter) TryAddEntry(
 	if atomic.LoadInt32(&ls.closed) != 0 {
 		return false, nil
 	}
+	var size uint64
+	if event.IsResolved() {
+		ls.unresolved = false
+	} else {
+		size = approximateDiskSize(event)
+		if ls.unresolved {
+			if err := ls.diskQuota.overshoot(size); err != nil {
+				return false, errors.Trace(err)
+			}
+		} else {
+			ok, err := ls.diskQuota.tryConsume(size)
+			if !ok || err != nil {
+				return false, errors.Trace(err)
+			}
+		}
+	}
 	msg := actormsg.ValueMessage(message.Task{
 		UID:        ls.uid,
 		TableID:    ls.tableID,
@@ -238,11 +283,16 @@ func (ls *Sorter) TryAddEntry(
 	})
 	err := ls.writerRouter.Send(ls.writerActorID, msg)
 	if err != nil {
+		// The event is not added, the caller may retry it later.
+		ls.diskQuota.release(size)
 		if cerror.ErrMailboxFull.Equal(err) {
 			return false, nil
 		}
 		return false, errors.Trace(err)
 	}
+	if !event.IsResolved() {
+		ls.unresolved = true
+	}
 	return true, nil
 }
 
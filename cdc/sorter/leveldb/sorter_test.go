@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sorter/leveldb/message"
 	"github.com/pingcap/tiflow/pkg/actor"
 	actormsg "github.com/pingcap/tiflow/pkg/actor/message"
+	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -65,6 +66,46 @@ func TestAddEntry(t *testing.T) {
 		}, task.Value)
 }
 
+func TestAddEntryDiskQuota(t *testing.T) {
+	t.Parallel()
+
+	s, mb := newTestSorter(t.Name(), 8)
+	quota := NewDiskQuota(100, config.DiskQuotaPolicyBackpressure)
+	s.diskQuota = quota.attach(model.DefaultChangeFeedID("test"))
+	defer s.diskQuota.detach()
+	newEvent := func() *model.PolymorphicEvent {
+		return model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType: model.OpTypePut, Key: make([]byte, 100), CRTs: 2,
+		})
+	}
+
+	// The events before the next resolved ts are never blocked.
+	s.AddEntry(context.Background(), newEvent())
+	s.AddEntry(context.Background(), newEvent())
+	require.Equal(t, 2*approximateDiskSize(newEvent()), quota.Usage(s.diskQuota.changefeedID))
+	s.AddEntry(context.Background(), model.NewResolvedPolymorphicEvent(0, 2))
+	for i := 0; i < 3; i++ {
+		_, ok := mb.Receive()
+		require.True(t, ok)
+	}
+
+	// The first event after a resolved ts is blocked until the quota is released.
+	done := make(chan struct{})
+	go func() {
+		s.AddEntry(context.Background(), newEvent())
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("add entry should be blocked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	s.diskQuota.release(2 * approximateDiskSize(newEvent()))
+	<-done
+	_, ok := mb.Receive()
+	require.True(t, ok)
+}
+
 func TestTryAddEntry(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -56,7 +56,8 @@ type System struct {
 	compactSystem *actor.System[message.Task]
 	compactRouter *actor.Router[message.Task]
 	compactSched  *lsorter.CompactScheduler
-	dir           string
+	diskQuota     *lsorter.DiskQuota
+	dirs          []string
 	memPercentage float64
 	cfg           *config.DBConfig
 	closedCh      chan struct{}
@@ -67,7 +68,12 @@ type System struct {
 }
 
 // NewSystem returns a system.
-func NewSystem(dir string, memPercentage float64, cfg *config.DBConfig) *System {
+// DB instances are striped across dirs, and diskQuota can be nil if
+// the sorter disk quota is disabled.
+func NewSystem(
+	dirs []string, memPercentage float64, cfg *config.DBConfig,
+	diskQuota *lsorter.DiskQuota,
+) *System {
 	// A system polles actors that read and write leveldb.
 	dbSystem, dbRouter := actor.NewSystemBuilder[message.Task]("sorter-db").
 		WorkerNumber(cfg.Count).Build()
@@ -91,7 +97,8 @@ func NewSystem(dir string, memPercentage float64, cfg *config.DBConfig) *System
 		compactSystem: compactSystem,
 		compactRouter: compactRouter,
 		compactSched:  compactSched,
-		dir:           dir,
+		diskQuota:     diskQuota,
+		dirs:          dirs,
 		memPercentage: memPercentage,
 		cfg:           cfg,
 		closedCh:      make(chan struct{}),
@@ -115,6 +122,12 @@ func (s *System) CompactScheduler() *lsorter.CompactScheduler {
 	return s.compactSched
 }
 
+// DiskQuota returns the sorter disk quota, it returns nil if
+// the disk quota is disabled.
+func (s *System) DiskQuota() *lsorter.DiskQuota {
+	return s.diskQuota
+}
+
 // Start starts a system.
 func (s *System) Start(ctx context.Context) error {
 	s.stateMu.Lock()
@@ -137,9 +150,10 @@ func (s *System) Start(ctx context.Context) error {
 	}
 	memInBytePerDB := float64(totalMemory) * s.memPercentage / float64(s.cfg.Count)
 	for id := 0; id < s.cfg.Count; id++ {
-		// Open db.
+		// Open db, db instances are striped across all the dirs.
+		dir := s.dirs[id%len(s.dirs)]
 		db, err := db.OpenPebble(
-			ctx, id, s.dir, s.cfg, db.WithCache(int(memInBytePerDB)), db.WithTableCRTsCollectors())
+			ctx, id, dir, s.cfg, db.WithCache(int(memInBytePerDB)), db.WithTableCRTsCollectors())
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -15,6 +15,8 @@ package system
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -32,7 +34,7 @@ func TestSystemStartStop(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 1
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 	require.Nil(t, sys.Stop())
 
@@ -42,12 +44,28 @@ func TestSystemStartStop(t *testing.T) {
 	require.Error(t, sys.Start(ctx))
 }
 
+func TestSystemStripeDirs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
+	cfg.Count = 4
+
+	dirs := []string{t.TempDir(), t.TempDir()}
+	sys := NewSystem(dirs, 1, cfg, nil)
+	require.Nil(t, sys.Start(ctx))
+	for id := 0; id < cfg.Count; id++ {
+		require.DirExists(t, filepath.Join(dirs[id%len(dirs)], fmt.Sprintf("%04d", id)))
+		require.NoDirExists(t, filepath.Join(dirs[(id+1)%len(dirs)], fmt.Sprintf("%04d", id)))
+	}
+	require.Nil(t, sys.Stop())
+}
+
 func TestSystemStopUnstarted(t *testing.T) {
 	t.Parallel()
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 1
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Stop())
 }
 
@@ -57,7 +75,7 @@ func TestCollectMetrics(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 2
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 	collectMetrics(sys.dbs)
 	require.Nil(t, sys.Stop())
@@ -69,7 +87,7 @@ func TestDBActorID(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 2
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 	id1 := sys.DBActorID(1)
 	id2 := sys.DBActorID(1)
@@ -85,7 +103,7 @@ func TestSystemStopSlowly(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 2
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 	msg := message.Task{Test: &message.Test{Sleep: 2 * time.Second}}
 	sys.DBRouter.Broadcast(ctx, actormsg.ValueMessage(msg))
@@ -99,7 +117,7 @@ func TestSystemStopMailboxFull(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 2
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 	msg := message.Task{Test: &message.Test{Sleep: 2 * time.Second}}
 	sys.DBRouter.Broadcast(ctx, actormsg.ValueMessage(msg))
@@ -119,7 +137,7 @@ func TestSystemStopWithManyTablesAndFewStragglers(t *testing.T) {
 	cfg := config.GetDefaultServerConfig().Clone().Debug.DB
 	cfg.Count = 8
 
-	sys := NewSystem(t.TempDir(), 1, cfg)
+	sys := NewSystem([]string{t.TempDir()}, 1, cfg, nil)
 	require.Nil(t, sys.Start(ctx))
 
 	ss := make([]*leveldb.Sorter, 0, 1000)
@@ -127,10 +145,10 @@ func TestSystemStopWithManyTablesAndFewStragglers(t *testing.T) {
 	for i := uint64(0); i < 1000; i++ {
 		dbActorID := sys.DBActorID(i)
 		s, err := leveldb.NewSorter(
-			ctx, int64(i), i, sys.DBRouter, dbActorID,
+			ctx, int64(i), i, 1, sys.DBRouter, dbActorID,
 			sys.WriterSystem, sys.WriterRouter,
 			sys.ReaderSystem, sys.ReaderRouter,
-			sys.CompactScheduler(), cfg)
+			sys.CompactScheduler(), nil, cfg)
 		require.Nil(t, err)
 		ss = append(ss, s)
 		sctx, scancel := context.WithCancel(ctx)
@@ -426,7 +426,9 @@ func (w *fileBackEndWriter) flushAndClose() error {
 
 	atomic.AddInt64(&openFDCount, -1)
 	w.backEnd.size = w.bytesWritten
-	atomic.AddInt64(&pool.onDiskDataSize, w.bytesWritten)
+	if pool != nil {
+		atomic.AddInt64(&pool.onDiskDataSize, w.bytesWritten)
+	}
 
 	failpoint.Inject("sorterDebug", func() {
 		atomic.StoreInt32(&w.backEnd.borrowed, 0)
@@ -47,6 +47,20 @@ var (
 		Help:      "Bucketed histogram of the number of events in individual merges performed by the sorter",
 		Buckets:   prometheus.ExponentialBuckets(16, 4, 10),
 	}, []string{"namespace", "changefeed"})
+
+	spilledBytesCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "spilled_bytes",
+		Help:      "the number of bytes spilled to disk because the memory quota of the changefeed is exhausted",
+	}, []string{"namespace", "changefeed"})
+
+	spillOnDiskDataSizeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "ticdc",
+		Subsystem: "sorter",
+		Name:      "spill_on_disk_data_size_gauge",
+		Help:      "The amount of spilled data which is waiting to be replayed",
+	}, []string{"namespace", "changefeed"})
 )
 
 // InitMetrics registers all metrics in this file
@@ -55,4 +69,6 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(sorterMergerStartTsGauge)
 	registry.MustRegister(sorterFlushCountHistogram)
 	registry.MustRegister(sorterMergeCountHistogram)
+	registry.MustRegister(spilledBytesCount)
+	registry.MustRegister(spillOnDiskDataSizeGauge)
 }
@@ -0,0 +1,200 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unified
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	sorterencoding "github.com/pingcap/tiflow/cdc/sorter/encoding"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// spillSegment is a file in a SpillQueue.
+type spillSegment struct {
+	backEnd *fileBackEnd
+	count   int
+	size    uint64
+}
+
+// SpillQueue is a FIFO queue of events backed by files in the sort dir.
+// It is used to spill the events out of memory when the memory quota of a
+// changefeed is exhausted. Only the raw kv entries of events are kept, so
+// events popped from the queue need to be mounted again.
+//
+// Events are appended to the last segment file, and read from the first one.
+// Once the last segment is being read, the following events are written to
+// a new segment. SpillQueue is not thread-safe.
+type SpillQueue struct {
+	filePrefix string
+	counter    uint64
+
+	segments []*spillSegment
+	writer   backEndWriter
+	reader   backEndReader
+	// the number of events left in the segment being read.
+	remaining int
+
+	len  int
+	size uint64
+
+	metricSpilledBytes prometheus.Counter
+	metricOnDiskSize   prometheus.Gauge
+}
+
+// NewSpillQueue creates a SpillQueue for the given table, files of
+// the queue are created lazily in dir.
+func NewSpillQueue(
+	dir string, changefeedID model.ChangeFeedID, tableID model.TableID,
+) *SpillQueue {
+	prefix := fmt.Sprintf("%s-%d-spill-%s-%s-%d-", sortDirDataFileMagicPrefix,
+		os.Getpid(), changefeedID.Namespace, changefeedID.ID, tableID)
+	return &SpillQueue{
+		filePrefix: filepath.Join(dir, prefix),
+		metricSpilledBytes: spilledBytesCount.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricOnDiskSize: spillOnDiskDataSizeGauge.
+			WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+	}
+}
+
+// Push appends an event to the end of the queue.
+func (q *SpillQueue) Push(event *model.PolymorphicEvent) error {
+	if q.writer == nil {
+		if err := checkDataDirSatisfied(); err != nil {
+			return errors.Trace(err)
+		}
+		q.counter++
+		fileName := fmt.Sprintf("%s%d.tmp", q.filePrefix, q.counter)
+		backEnd, err := newFileBackEnd(fileName, &sorterencoding.MsgPackGenSerde{})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		writer, err := backEnd.writer()
+		if err != nil {
+			_ = backEnd.free()
+			return errors.Trace(err)
+		}
+		q.writer = writer
+		q.segments = append(q.segments, &spillSegment{backEnd: backEnd})
+	}
+
+	sizeBefore := q.writer.dataSize()
+	if err := q.writer.writeNext(event); err != nil {
+		return errors.Trace(err)
+	}
+	size := q.writer.dataSize() - sizeBefore
+
+	tail := q.segments[len(q.segments)-1]
+	tail.count++
+	tail.size += size
+	q.len++
+	q.size += size
+	q.metricSpilledBytes.Add(float64(size))
+	q.metricOnDiskSize.Add(float64(size))
+	return nil
+}
+
+// Pop removes and returns the first event of the queue,
+// it returns nil if the queue is empty.
+func (q *SpillQueue) Pop() (*model.PolymorphicEvent, error) {
+	if q.len == 0 {
+		return nil, nil
+	}
+
+	head := q.segments[0]
+	if q.reader == nil {
+		if len(q.segments) == 1 && q.writer != nil {
+			// The head segment is still being written, close it so that
+			// it can be read, the following events go to a new segment.
+			if err := q.writer.flushAndClose(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			q.writer = nil
+		}
+		reader, err := head.backEnd.reader()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		q.reader = reader
+		q.remaining = head.count
+	}
+
+	event, err := q.reader.readNext()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if event == nil {
+		log.Panic("spill queue: unexpected EOF",
+			zap.String("file", head.backEnd.fileName),
+			zap.Int("remaining", q.remaining))
+	}
+	q.remaining--
+	q.len--
+
+	if q.remaining == 0 {
+		if err := q.reader.resetAndClose(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		q.reader = nil
+		if err := head.backEnd.free(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		q.segments = q.segments[1:]
+		q.size -= head.size
+		q.metricOnDiskSize.Sub(float64(head.size))
+	}
+	return event, nil
+}
+
+// Len returns the number of events in the queue.
+func (q *SpillQueue) Len() int {
+	return q.len
+}
+
+// Size returns the size in bytes of the segment files of the queue.
+func (q *SpillQueue) Size() uint64 {
+	return q.size
+}
+
+// Close removes all the files of the queue.
+func (q *SpillQueue) Close() error {
+	var firstErr error
+	if q.reader != nil {
+		if err := q.reader.resetAndClose(); err != nil {
+			firstErr = err
+		}
+		q.reader = nil
+	}
+	if q.writer != nil {
+		if err := q.writer.flushAndClose(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		q.writer = nil
+	}
+	for _, segment := range q.segments {
+		if err := segment.backEnd.free(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	q.segments = nil
+	q.metricOnDiskSize.Sub(float64(q.size))
+	q.len, q.size = 0, 0
+	return errors.Trace(firstErr)
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unified
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillQueue(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.GetDefaultServerConfig()
+	conf.DataDir = dir
+	config.StoreGlobalServerConfig(conf)
+
+	q := NewSpillQueue(dir, model.DefaultChangeFeedID("test-spill"), 1)
+	event, err := q.Pop()
+	require.Nil(t, err)
+	require.Nil(t, event)
+
+	for ts := uint64(10); ts < 20; ts++ {
+		require.Nil(t, q.Push(model.NewPolymorphicEvent(generateMockRawKV(ts))))
+	}
+	require.Equal(t, 10, q.Len())
+	require.Greater(t, q.Size(), uint64(0))
+
+	// Events pushed while reading are written to a new segment.
+	for ts := uint64(10); ts < 15; ts++ {
+		event, err := q.Pop()
+		require.Nil(t, err)
+		require.Equal(t, ts, event.CRTs)
+		require.Equal(t, ts-5, event.StartTs)
+	}
+	for ts := uint64(20); ts < 25; ts++ {
+		require.Nil(t, q.Push(model.NewPolymorphicEvent(generateMockRawKV(ts))))
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "sort-*"))
+	require.Nil(t, err)
+	require.Len(t, files, 2)
+
+	for ts := uint64(15); ts < 25; ts++ {
+		event, err := q.Pop()
+		require.Nil(t, err)
+		require.Equal(t, ts, event.CRTs)
+	}
+	event, err = q.Pop()
+	require.Nil(t, err)
+	require.Nil(t, event)
+	require.Equal(t, 0, q.Len())
+	require.Equal(t, uint64(0), q.Size())
+
+	// Close removes all the files.
+	require.Nil(t, q.Push(model.NewPolymorphicEvent(generateMockRawKV(30))))
+	require.Nil(t, q.Close())
+	files, err = filepath.Glob(filepath.Join(dir, "sort-*"))
+	require.Nil(t, err)
+	require.Len(t, files, 0)
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, entries, 0)
+}
@@ -0,0 +1,241 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// Stage is a step that a row change passes through during replication.
+type Stage int
+
+// Stages are listed in the order a row change passes through them.
+const (
+	// StageKVClient is reached when the event is received from TiKV.
+	StageKVClient Stage = iota
+	// StagePuller is reached when the event is output by the puller.
+	StagePuller
+	// StageSorter is reached when the event is output by the sorter.
+	StageSorter
+	// StageMounter is reached when the event is decoded by the mounter.
+	StageMounter
+	// StageSink is reached when the transaction is flushed to the downstream.
+	StageSink
+
+	stageCount
+)
+
+var stageNames = [stageCount]string{
+	StageKVClient: "kv-client",
+	StagePuller:   "puller",
+	StageSorter:   "sorter",
+	StageMounter:  "mounter",
+	StageSink:     "sink",
+}
+
+// String implements fmt.Stringer.
+func (s Stage) String() string {
+	if s < 0 || s >= stageCount {
+		return "unknown"
+	}
+	return stageNames[s]
+}
+
+// StageLatency is the latency statistics of one stage of a table.
+// All latencies are measured from the physical time of the commit ts.
+type StageLatency struct {
+	Stage Stage
+	Count uint64
+	Avg   time.Duration
+	Max   time.Duration
+	Last  time.Duration
+}
+
+// TableLatency is the latency statistics of all stages of a table.
+type TableLatency struct {
+	TableID   model.TableID
+	TableName string
+	Stages    []StageLatency
+}
+
+type stageStats struct {
+	observer prometheus.Observer
+	count    uint64
+	totalNs  int64
+	maxNs    int64
+	lastNs   int64
+
+	// lastStartTs and lastCommitTs identify the last observed transaction,
+	// they are only accessed by the goroutine which observes the stage.
+	lastStartTs  model.Ts
+	lastCommitTs model.Ts
+}
+
+// TableTracker records the replication latency of a table at each stage.
+// A nil *TableTracker is valid and records nothing.
+type TableTracker struct {
+	changefeed model.ChangeFeedID
+	tableID    model.TableID
+	tableName  string
+	stages     [stageCount]stageStats
+}
+
+var trackers = struct {
+	sync.RWMutex
+	m map[model.ChangeFeedID]map[model.TableID]*TableTracker
+}{m: make(map[model.ChangeFeedID]map[model.TableID]*TableTracker)}
+
+// NewTableTracker creates a TableTracker and registers it, so that it can be
+// found by GetTableTracker and reported by GetChangefeedLatency.
+func NewTableTracker(
+	changefeed model.ChangeFeedID, tableID model.TableID, tableName string,
+) *TableTracker {
+	t := &TableTracker{
+		changefeed: changefeed,
+		tableID:    tableID,
+		tableName:  tableName,
+	}
+	for i := range t.stages {
+		t.stages[i].observer = stageLatencyHistogram.WithLabelValues(
+			changefeed.Namespace, changefeed.ID, tableName, Stage(i).String())
+	}
+
+	trackers.Lock()
+	defer trackers.Unlock()
+	tables, ok := trackers.m[changefeed]
+	if !ok {
+		tables = make(map[model.TableID]*TableTracker)
+		trackers.m[changefeed] = tables
+	}
+	tables[tableID] = t
+	return t
+}
+
+// GetTableTracker returns the registered tracker of the table,
+// or nil if there is none.
+func GetTableTracker(
+	changefeed model.ChangeFeedID, tableID model.TableID,
+) *TableTracker {
+	trackers.RLock()
+	defer trackers.RUnlock()
+	return trackers.m[changefeed][tableID]
+}
+
+// GetChangefeedLatency returns the latency statistics of all tables of the
+// changefeed replicated by this capture, ordered by table ID.
+func GetChangefeedLatency(changefeed model.ChangeFeedID) []TableLatency {
+	trackers.RLock()
+	tables := make([]*TableTracker, 0, len(trackers.m[changefeed]))
+	for _, t := range trackers.m[changefeed] {
+		tables = append(tables, t)
+	}
+	trackers.RUnlock()
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].tableID < tables[j].tableID
+	})
+	res := make([]TableLatency, 0, len(tables))
+	for _, t := range tables {
+		res = append(res, t.Latency())
+	}
+	return res
+}
+
+// Observe records that the transaction identified by startTs and commitTs
+// has passed the given stage. It is called for every row, but a transaction
+// is only recorded once if its rows pass the stage one after another, which
+// is always true for the stages after the sorter. For the stages before the
+// sorter, rows of a transaction are contiguous in a region, so a transaction
+// is recorded once per region it spans at most. Observe must not be called
+// concurrently for the same stage.
+func (t *TableTracker) Observe(stage Stage, startTs, commitTs model.Ts) {
+	if t == nil {
+		return
+	}
+	s := &t.stages[stage]
+	if s.lastStartTs == startTs && s.lastCommitTs == commitTs {
+		return
+	}
+	s.lastStartTs, s.lastCommitTs = startTs, commitTs
+
+	now := time.Now()
+	commitTime := oracle.GetTimeFromTS(commitTs)
+	lag := now.Sub(commitTime)
+	if lag < 0 {
+		lag = 0
+	}
+
+	s.observer.Observe(lag.Seconds())
+	atomic.AddUint64(&s.count, 1)
+	atomic.AddInt64(&s.totalNs, int64(lag))
+	atomic.StoreInt64(&s.lastNs, int64(lag))
+	for {
+		max := atomic.LoadInt64(&s.maxNs)
+		if int64(lag) <= max || atomic.CompareAndSwapInt64(&s.maxNs, max, int64(lag)) {
+			break
+		}
+	}
+
+	traceStage(t, stage, startTs, commitTs, commitTime, now)
+}
+
+// Latency returns the latency statistics of the table.
+func (t *TableTracker) Latency() TableLatency {
+	res := TableLatency{
+		TableID:   t.tableID,
+		TableName: t.tableName,
+		Stages:    make([]StageLatency, 0, stageCount),
+	}
+	for i := range t.stages {
+		s := &t.stages[i]
+		l := StageLatency{
+			Stage: Stage(i),
+			Count: atomic.LoadUint64(&s.count),
+			Max:   time.Duration(atomic.LoadInt64(&s.maxNs)),
+			Last:  time.Duration(atomic.LoadInt64(&s.lastNs)),
+		}
+		if l.Count > 0 {
+			l.Avg = time.Duration(atomic.LoadInt64(&s.totalNs) / int64(l.Count))
+		}
+		res.Stages = append(res.Stages, l)
+	}
+	return res
+}
+
+// Close unregisters the tracker and removes its metrics.
+func (t *TableTracker) Close() {
+	if t == nil {
+		return
+	}
+	trackers.Lock()
+	if tables, ok := trackers.m[t.changefeed]; ok && tables[t.tableID] == t {
+		delete(tables, t.tableID)
+		if len(tables) == 0 {
+			delete(trackers.m, t.changefeed)
+		}
+	}
+	trackers.Unlock()
+
+	for i := range t.stages {
+		stageLatencyHistogram.DeleteLabelValues(
+			t.changefeed.Namespace, t.changefeed.ID, t.tableName, Stage(i).String())
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestTableTracker(t *testing.T) {
+	t.Parallel()
+
+	changefeed := model.DefaultChangeFeedID("test-table-tracker")
+	t1 := NewTableTracker(changefeed, 2, "test.t2")
+	t2 := NewTableTracker(changefeed, 1, "test.t1")
+	require.Equal(t, t1, GetTableTracker(changefeed, 2))
+	require.Nil(t, GetTableTracker(changefeed, 3))
+
+	now := time.Now()
+	t1.Observe(StagePuller, 1, oracle.GoTimeToTS(now.Add(-2*time.Second)))
+	t1.Observe(StagePuller, 2, oracle.GoTimeToTS(now.Add(-time.Second)))
+	// A commit ts in the future is counted as no lag.
+	t1.Observe(StageSink, 3, oracle.GoTimeToTS(now.Add(time.Hour)))
+
+	latency := GetChangefeedLatency(changefeed)
+	require.Len(t, latency, 2)
+	require.Equal(t, model.TableID(1), latency[0].TableID)
+	require.Equal(t, "test.t1", latency[0].TableName)
+	require.Equal(t, model.TableID(2), latency[1].TableID)
+
+	stages := latency[1].Stages
+	require.Len(t, stages, int(stageCount))
+	require.Equal(t, uint64(0), stages[StageKVClient].Count)
+	require.Equal(t, uint64(2), stages[StagePuller].Count)
+	require.GreaterOrEqual(t, stages[StagePuller].Max, 2*time.Second)
+	require.GreaterOrEqual(t, stages[StagePuller].Avg, 1500*time.Millisecond)
+	require.Less(t, stages[StagePuller].Last, stages[StagePuller].Max)
+	require.Equal(t, uint64(1), stages[StageSink].Count)
+	require.Equal(t, time.Duration(0), stages[StageSink].Max)
+
+	t1.Close()
+	require.Nil(t, GetTableTracker(changefeed, 2))
+	require.Len(t, GetChangefeedLatency(changefeed), 1)
+	t2.Close()
+	require.Empty(t, GetChangefeedLatency(changefeed))
+
+	// A nil tracker is a no-op.
+	var nilTracker *TableTracker
+	nilTracker.Observe(StageSorter, 1, 2)
+	nilTracker.Close()
+}
+
+func TestTableTrackerObserveTxnOnce(t *testing.T) {
+	t.Parallel()
+
+	changefeed := model.DefaultChangeFeedID("test-observe-txn-once")
+	tracker := NewTableTracker(changefeed, 1, "test.t1")
+	defer tracker.Close()
+
+	commitTs := oracle.GoTimeToTS(time.Now())
+	// Rows of the same transaction are recorded once.
+	for i := 0; i < 3; i++ {
+		tracker.Observe(StageSorter, 1, commitTs)
+	}
+	tracker.Observe(StageSorter, 2, commitTs+1)
+	tracker.Observe(StageSorter, 2, commitTs+1)
+	// Stages are deduplicated independently.
+	tracker.Observe(StageMounter, 1, commitTs)
+
+	stages := tracker.Latency().Stages
+	require.Equal(t, uint64(2), stages[StageSorter].Count)
+	require.Equal(t, uint64(1), stages[StageMounter].Count)
+}
+
+func TestStageString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "kv-client", StageKVClient.String())
+	require.Equal(t, "sink", StageSink.String())
+	require.Equal(t, "unknown", stageCount.String())
+}
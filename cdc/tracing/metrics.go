@@ -0,0 +1,33 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var stageLatencyHistogram = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "ticdc",
+		Subsystem: "processor",
+		Name:      "stage_latency",
+		Help: "Bucketed histogram of the lag between the commit ts of " +
+			"an event and the time it passes a replication stage",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 18),
+	}, []string{"namespace", "changefeed", "table", "stage"})
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(stageLatencyHistogram)
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	tracerName = "github.com/pingcap/tiflow/cdc/tracing"
+	// defaultTraceFileName is the span file created in the data dir
+	// if no file path is configured.
+	defaultTraceFileName = "spans.json"
+)
+
+// globalTracer holds a *tracer, it holds a nil pointer if tracing is disabled.
+var globalTracer atomic.Value
+
+func init() {
+	globalTracer.Store((*tracer)(nil))
+}
+
+type tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+	// A transaction is sampled if its hash shifted right by one bit is less
+	// than threshold.
+	threshold uint64
+}
+
+// InitTracer enables span sampling according to cfg. It returns a function
+// which flushes pending spans and disables tracing.
+func InitTracer(cfg *config.TracingConfig, dataDir string) (func(), error) {
+	if cfg == nil || cfg.SampleRatio == 0 {
+		return func() {}, nil
+	}
+	path := cfg.FilePath
+	if path == "" {
+		path = filepath.Join(dataDir, defaultTraceFileName)
+	}
+	exporter, err := newFileExporter(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	log.Info("transaction tracing is enabled",
+		zap.Float64("sampleRatio", cfg.SampleRatio),
+		zap.String("exporter", cfg.Exporter),
+		zap.String("filePath", path))
+	return setTracer(exporter, cfg.SampleRatio), nil
+}
+
+// setTracer installs a tracer which exports sampled spans to exporter.
+func setTracer(exporter sdktrace.SpanExporter, ratio float64) func() {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithBatcher(exporter))
+	threshold := uint64(math.MaxInt64)
+	if ratio < 1 {
+		threshold = uint64(ratio * (1 << 63))
+	}
+	t := &tracer{
+		provider:  provider,
+		tracer:    provider.Tracer(tracerName),
+		threshold: threshold,
+	}
+	globalTracer.Store(t)
+	return func() {
+		globalTracer.Store((*tracer)(nil))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Warn("failed to shutdown tracer provider", zap.Error(err))
+		}
+	}
+}
+
+// traceStage emits a span for the stage if the transaction is sampled.
+// The span starts at the commit time of the transaction and ends when
+// the stage is reached, so that spans of the same transaction nest.
+func traceStage(
+	t *TableTracker, stage Stage, startTs, commitTs model.Ts,
+	commitTime, now time.Time,
+) {
+	tr := globalTracer.Load().(*tracer)
+	if tr == nil {
+		return
+	}
+	h := txnHash(startTs)
+	if h>>1 >= tr.threshold {
+		return
+	}
+
+	// All spans of a transaction share the same trace and the same parent,
+	// which is derived from the start ts, so they can be grouped without
+	// passing any context along the pipeline.
+	var traceID trace.TraceID
+	binary.BigEndian.PutUint64(traceID[:8], h)
+	binary.BigEndian.PutUint64(traceID[8:], startTs)
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], h|1)
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+	_, span := tr.tracer.Start(ctx, stage.String(),
+		trace.WithTimestamp(commitTime),
+		trace.WithAttributes(
+			attribute.String("namespace", t.changefeed.Namespace),
+			attribute.String("changefeed", t.changefeed.ID),
+			attribute.Int64("table-id", t.tableID),
+			attribute.String("table", t.tableName),
+			attribute.Int64("start-ts", int64(startTs)),
+			attribute.Int64("commit-ts", int64(commitTs)),
+		))
+	span.End(trace.WithTimestamp(now))
+}
+
+// txnHash mixes the start ts of a transaction, so that transactions are
+// sampled uniformly. See splitmix64.
+func txnHash(startTs model.Ts) uint64 {
+	x := startTs + 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// fileSpan is the format of a span written by fileExporter.
+type fileSpan struct {
+	TraceID      string                 `json:"trace-id"`
+	SpanID       string                 `json:"span-id"`
+	ParentSpanID string                 `json:"parent-span-id"`
+	Name         string                 `json:"name"`
+	StartTime    time.Time              `json:"start-time"`
+	EndTime      time.Time              `json:"end-time"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+// fileExporter writes spans to a file, one JSON object per line.
+type fileExporter struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, cerror.WrapError(cerror.ErrIllegalTracingParameter, err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrIllegalTracingParameter, err)
+	}
+	return &fileExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// ExportSpans implements sdktrace.SpanExporter.
+func (e *fileExporter) ExportSpans(
+	ctx context.Context, spans []*sdktrace.SpanSnapshot,
+) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	for _, s := range spans {
+		attrs := make(map[string]interface{}, len(s.Attributes))
+		for _, kv := range s.Attributes {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		err := e.encoder.Encode(&fileSpan{
+			TraceID:      s.SpanContext.TraceID().String(),
+			SpanID:       s.SpanContext.SpanID().String(),
+			ParentSpanID: s.Parent.SpanID().String(),
+			Name:         s.Name,
+			StartTime:    s.StartTime,
+			EndTime:      s.EndTime,
+			Attributes:   attrs,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Shutdown implements sdktrace.SpanExporter.
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return errors.Trace(err)
+}
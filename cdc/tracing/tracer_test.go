@@ -0,0 +1,108 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Tests in this file install the global tracer, so they must not be parallel.
+
+// inMemoryExporter keeps the spans after shutdown.
+type inMemoryExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (e *inMemoryExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestTracerFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace", "spans.json")
+	closer, err := InitTracer(&config.TracingConfig{
+		SampleRatio: 1,
+		Exporter:    config.TracingExporterFile,
+		FilePath:    path,
+	}, "")
+	require.Nil(t, err)
+
+	tracker := NewTableTracker(model.DefaultChangeFeedID("test-tracer"), 1, "test.t1")
+	defer tracker.Close()
+	commitTs := oracle.GoTimeToTS(time.Now().Add(-time.Second))
+	tracker.Observe(StageSorter, 10, commitTs)
+	tracker.Observe(StageSink, 10, commitTs)
+	closer()
+
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+	var spans []fileSpan
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var span fileSpan
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans = append(spans, span)
+	}
+	require.Nil(t, scanner.Err())
+	require.Len(t, spans, 2)
+	require.Equal(t, "sorter", spans[0].Name)
+	require.Equal(t, "sink", spans[1].Name)
+	require.Equal(t, spans[0].TraceID, spans[1].TraceID)
+	require.Equal(t, spans[0].ParentSpanID, spans[1].ParentSpanID)
+	require.NotEqual(t, spans[0].SpanID, spans[1].SpanID)
+	require.Equal(t, "test.t1", spans[0].Attributes["table"])
+	require.EqualValues(t, commitTs, spans[0].Attributes["commit-ts"])
+	require.True(t, spans[0].StartTime.Before(spans[0].EndTime))
+
+	// Tracing is disabled after closing.
+	tracker.Observe(StageSink, 11, commitTs)
+	require.Nil(t, globalTracer.Load().(*tracer))
+}
+
+func TestTracerSampleRatio(t *testing.T) {
+	closer, err := InitTracer(&config.TracingConfig{}, t.TempDir())
+	require.Nil(t, err)
+	require.Nil(t, globalTracer.Load().(*tracer))
+	closer()
+
+	exporter := &inMemoryExporter{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	closer = setTracer(exporter, 0.25)
+
+	tracker := NewTableTracker(model.DefaultChangeFeedID("test-sample"), 1, "test.t1")
+	defer tracker.Close()
+	const txnCount = 2000
+	commitTs := oracle.GoTimeToTS(time.Now())
+	for startTs := model.Ts(1); startTs <= txnCount; startTs++ {
+		tracker.Observe(StageMounter, startTs, commitTs)
+	}
+	// closing the tracer exports all pending spans
+	closer()
+
+	sampled := len(exporter.GetSpans())
+	require.Greater(t, sampled, txnCount/8)
+	require.Less(t, sampled, txnCount/2)
+	require.Equal(t, uint64(txnCount), tracker.Latency().Stages[StageMounter].Count)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink"
 	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
 	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
 	cmdUtil "github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/filter"
@@ -57,6 +58,7 @@ var (
 	kafkaVersion         = "2.4.0"
 	kafkaMaxMessageBytes = math.MaxInt64
 	kafkaMaxBatchSize    = math.MaxInt64
+	kafkaSASL            *security.SASL
 
 	downstreamURIStr string
 
@@ -123,6 +125,13 @@ func init() {
 	})
 	kafkaAddrs = strings.Split(upstreamURI.Host, ",")
 
+	// The SASL parameters are the same as the ones of the Kafka sink.
+	saslConfig := kafka.NewConfig()
+	if err := saslConfig.ApplySASL(upstreamURI.Query()); err != nil {
+		log.Panic("invalid sasl config of upstream-uri", zap.Error(err))
+	}
+	kafkaSASL = saslConfig.SASL
+
 	saramaConfig, err := newSaramaConfig()
 	if err != nil {
 		log.Panic("Error creating sarama saramaConfig", zap.Error(err))
@@ -272,6 +281,10 @@ func newSaramaConfig() (*sarama.Config, error) {
 		}
 	}
 
+	if err := kafka.CompleteSaramaSASLConfig(context.Background(), config, kafkaSASL); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	return config, err
 }
 
@@ -351,6 +364,9 @@ type partitionSink struct {
 	resolvedTs  uint64
 	partitionNo int
 	tablesMap   sync.Map
+	// partitionNum is the partition number used to dispatch
+	// the row changed events received from the partition.
+	partitionNum int32
 }
 
 // Consumer represents a Sarama consumer group consumer
@@ -422,7 +438,7 @@ func NewConsumer(ctx context.Context) (*Consumer, error) {
 			cancel()
 			return nil, errors.Trace(err)
 		}
-		c.sinks[i] = &partitionSink{Sink: s, partitionNo: i}
+		c.sinks[i] = &partitionSink{Sink: s, partitionNo: i, partitionNum: kafkaPartitionNum}
 	}
 	sink, err := sink.New(ctx,
 		model.DefaultChangeFeedID("kafka-consumer"),
@@ -498,6 +514,26 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 
 	eventGroups := make(map[int64]*eventsGroup)
 	for message := range claim.Messages() {
+		if isPartitionChangeMarker(message) {
+			marker, err := codec.DecodePartitionChangeMarker(message.Value)
+			if err != nil {
+				log.Panic("decode partition change marker failed",
+					zap.ByteString("value", message.Value), zap.Error(err))
+			}
+			if marker.PartitionNum > int32(len(c.sinks)) {
+				log.Panic("partition number of the topic is increased, "+
+					"restart the consumer to consume the new partitions",
+					zap.Int32("partition", partition),
+					zap.Any("marker", marker),
+					zap.Int("consumedPartitionNum", len(c.sinks)))
+			}
+			log.Info("partition change marker received",
+				zap.Int32("partition", partition), zap.Any("marker", marker))
+			sink.partitionNum = marker.PartitionNum
+			session.MarkMessage(message, "")
+			continue
+		}
+
 		var (
 			decoder codec.EventBatchDecoder
 			err     error
@@ -553,12 +589,12 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 				}
 
 				if c.eventRouter != nil {
-					target := c.eventRouter.GetPartitionForRowChange(row, kafkaPartitionNum)
+					target := c.eventRouter.GetPartitionForRowChange(row, sink.partitionNum)
 					if partition != target {
 						log.Panic("RowChangedEvent dispatched to wrong partition",
 							zap.Int32("obtained", partition),
 							zap.Int32("expected", target),
-							zap.Int32("partitionNum", kafkaPartitionNum),
+							zap.Int32("partitionNum", sink.partitionNum),
 							zap.Any("row", row),
 						)
 					}
@@ -641,6 +677,16 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 	return nil
 }
 
+// isPartitionChangeMarker returns true if the message is a partition change marker.
+func isPartitionChangeMarker(message *sarama.ConsumerMessage) bool {
+	for _, header := range message.Headers {
+		if string(header.Key) == codec.PartitionChangeMarkerHeader {
+			return true
+		}
+	}
+	return false
+}
+
 // append DDL wait to be handled, only consider the constraint among DDLs.
 // for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
 func (c *Consumer) appendDDL(ddl *model.DDLEvent) {
@@ -0,0 +1,489 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/util"
+	"go.uber.org/zap"
+)
+
+// sinkBuilder builds the downstream sink of the consumer.
+type sinkBuilder func(ctx context.Context, option *consumerOption, errCh chan error) (sink.Sink, error)
+
+func newSink(ctx context.Context, option *consumerOption, errCh chan error) (sink.Sink, error) {
+	return sink.New(ctx,
+		model.DefaultChangeFeedID("pulsar-consumer"),
+		option.downstreamURI, config.GetDefaultReplicaConfig(), errCh)
+}
+
+type partitionSink struct {
+	sink.Sink
+	resolvedTs  uint64
+	partitionNo int
+	tablesMap   sync.Map
+	// eventGroups is only accessed by the goroutine consuming the messages.
+	eventGroups map[int64]*eventsGroup
+}
+
+// Consumer consumes the messages of a pulsar topic and writes them to the downstream.
+type Consumer struct {
+	option *consumerOption
+
+	ddlList            []*model.DDLEvent
+	ddlWithMaxCommitTs *model.DDLEvent
+	ddlListMu          sync.Mutex
+
+	sinks   []*partitionSink
+	sinksMu sync.Mutex
+
+	ddlSink              sink.Sink
+	fakeTableIDGenerator *fakeTableIDGenerator
+
+	// initialize to 0 by default
+	globalResolvedTs uint64
+
+	eventRouter *dispatcher.EventRouter
+	cancel      context.CancelFunc
+}
+
+// NewConsumer creates a new cdc pulsar consumer.
+func NewConsumer(ctx context.Context, option *consumerOption, newSink sinkBuilder) (*Consumer, error) {
+	tz, err := util.GetTimezone(option.timezone)
+	if err != nil {
+		return nil, errors.Annotate(err, "can not load timezone")
+	}
+	ctx = contextutil.PutTimezoneInCtx(ctx, tz)
+
+	c := &Consumer{
+		option: option,
+		fakeTableIDGenerator: &fakeTableIDGenerator{
+			tableIDs: make(map[string]int64),
+		},
+	}
+	// See the kafka consumer for the limitation of the dispatcher check,
+	// the decoded RowChangedEvent must contain the same information as the
+	// one on the CDC side.
+	if option.eventRouterReplicaConfig != nil {
+		eventRouter, err := dispatcher.NewEventRouter(option.eventRouterReplicaConfig, option.topic)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.eventRouter = eventRouter
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ctx = contextutil.PutRoleInCtx(ctx, util.RolePulsarConsumer)
+	c.cancel = cancel
+	errCh := make(chan error, 1)
+	c.sinks = make([]*partitionSink, option.partitionNum)
+	for i := 0; i < int(option.partitionNum); i++ {
+		s, err := newSink(ctx, option, errCh)
+		if err != nil {
+			cancel()
+			return nil, errors.Trace(err)
+		}
+		c.sinks[i] = &partitionSink{
+			Sink:        s,
+			partitionNo: i,
+			eventGroups: make(map[int64]*eventsGroup),
+		}
+	}
+	c.ddlSink, err = newSink(ctx, option, errCh)
+	if err != nil {
+		cancel()
+		return nil, errors.Trace(err)
+	}
+	go func() {
+		err := <-errCh
+		if errors.Cause(err) != context.Canceled {
+			log.Error("error on running consumer", zap.Error(err))
+		} else {
+			log.Info("consumer exited")
+		}
+		cancel()
+	}()
+	return c, nil
+}
+
+// Close closes all the sinks of the consumer.
+func (c *Consumer) Close(ctx context.Context) {
+	c.cancel()
+	_ = c.forEachSink(func(sink *partitionSink) error {
+		return sink.Close(ctx)
+	})
+	_ = c.ddlSink.Close(ctx)
+}
+
+type eventsGroup struct {
+	events []*model.RowChangedEvent
+}
+
+func newEventsGroup() *eventsGroup {
+	return &eventsGroup{
+		events: make([]*model.RowChangedEvent, 0),
+	}
+}
+
+func (g *eventsGroup) Append(e *model.RowChangedEvent) {
+	g.events = append(g.events, e)
+}
+
+func (g *eventsGroup) Resolve(resolveTs uint64) []*model.RowChangedEvent {
+	sort.Slice(g.events, func(i, j int) bool {
+		return g.events[i].CommitTs < g.events[j].CommitTs
+	})
+
+	i := sort.Search(len(g.events), func(i int) bool {
+		return g.events[i].CommitTs > resolveTs
+	})
+	result := g.events[:i]
+	g.events = g.events[i:]
+
+	return result
+}
+
+// partitionFromTopic returns the partition of the message topic,
+// pulsar names the partitions of a topic as `<topic>-partition-<index>`.
+// A non-partitioned topic has only the partition 0.
+func partitionFromTopic(topic string) (int32, error) {
+	i := strings.LastIndex(topic, "-partition-")
+	if i < 0 {
+		return 0, nil
+	}
+	partition, err := strconv.ParseInt(topic[i+len("-partition-"):], 10, 32)
+	if err != nil {
+		return 0, errors.Annotatef(err, "invalid partitioned topic %s", topic)
+	}
+	return int32(partition), nil
+}
+
+// Consume receives the messages from the pulsar consumer until the context is canceled.
+func (c *Consumer) Consume(ctx context.Context, consumer pulsar.Consumer) error {
+	for {
+		msg, err := consumer.Receive(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := c.handleMessage(ctx, msg); err != nil {
+			return errors.Trace(err)
+		}
+		consumer.Ack(msg)
+	}
+}
+
+func (c *Consumer) handleMessage(ctx context.Context, message pulsar.Message) error {
+	partition, err := partitionFromTopic(message.Topic())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if partition >= c.option.partitionNum {
+		return errors.Errorf("partition %d out of range, partitionNum: %d",
+			partition, c.option.partitionNum)
+	}
+	c.sinksMu.Lock()
+	sink := c.sinks[partition]
+	c.sinksMu.Unlock()
+
+	var decoder codec.EventBatchDecoder
+	switch c.option.protocol {
+	case config.ProtocolOpen, config.ProtocolDefault:
+		decoder, err = codec.NewOpenProtocolBatchDecoder([]byte(message.Key()), message.Payload())
+	case config.ProtocolCanalJSON:
+		decoder = codec.NewCanalJSONBatchDecoder(message.Payload(), c.option.enableTiDBExtension)
+	default:
+		return errors.Errorf("protocol %s not supported", c.option.protocol)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for {
+		tp, hasNext, err := decoder.HasNext()
+		if err != nil {
+			return errors.Annotate(err, "decode message key failed")
+		}
+		if !hasNext {
+			return nil
+		}
+
+		switch tp {
+		case model.MessageTypeDDL:
+			// DDL is broadcast to all partitions, only the one
+			// received from partition 0 is handled, see the kafka consumer.
+			ddl, err := decoder.NextDDLEvent()
+			if err != nil {
+				return errors.Annotate(err, "decode message value failed")
+			}
+			if partition == 0 {
+				if err := c.appendDDL(ddl); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case model.MessageTypeRow:
+			row, err := decoder.NextRowChangedEvent()
+			if err != nil {
+				return errors.Annotate(err, "decode message value failed")
+			}
+
+			if c.eventRouter != nil {
+				target := c.eventRouter.GetPartitionForRowChange(row, c.option.partitionNum)
+				if partition != target {
+					return errors.Errorf("RowChangedEvent dispatched to wrong partition, "+
+						"obtained: %d, expected: %d, table: %s", partition, target, row.Table)
+				}
+			}
+
+			globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
+			resolvedTs := atomic.LoadUint64(&sink.resolvedTs)
+			if row.CommitTs <= globalResolvedTs || row.CommitTs <= resolvedTs {
+				log.Warn("RowChangedEvent fallback row, ignore it",
+					zap.Uint64("commitTs", row.CommitTs),
+					zap.Uint64("globalResolvedTs", globalResolvedTs),
+					zap.Uint64("sinkResolvedTs", resolvedTs),
+					zap.Int32("partition", partition),
+					zap.Any("row", row))
+			}
+			// start-ts is not contained in TiCDC open protocol
+			row.StartTs = row.CommitTs
+			var partitionID int64
+			if row.Table.IsPartition {
+				partitionID = row.Table.TableID
+			}
+			tableID := c.fakeTableIDGenerator.
+				generateFakeTableID(row.Table.Schema, row.Table.Table, partitionID)
+			row.Table.TableID = tableID
+
+			group, ok := sink.eventGroups[tableID]
+			if !ok {
+				group = newEventsGroup()
+				sink.eventGroups[tableID] = group
+			}
+			group.Append(row)
+		case model.MessageTypeResolved:
+			ts, err := decoder.NextResolvedEvent()
+			if err != nil {
+				return errors.Annotate(err, "decode message value failed")
+			}
+			resolvedTs := atomic.LoadUint64(&sink.resolvedTs)
+			// `resolvedTs` should be monotonically increasing, it's allowed to receive redundant one.
+			if ts < resolvedTs {
+				return errors.Errorf("partition %d resolved ts fallback, ts: %d, resolvedTs: %d",
+					partition, ts, resolvedTs)
+			}
+			if ts == resolvedTs {
+				log.Info("redundant sink resolved ts",
+					zap.Uint64("ts", ts), zap.Int32("partition", partition))
+				continue
+			}
+			for tableID, group := range sink.eventGroups {
+				events := group.Resolve(ts)
+				if len(events) == 0 {
+					continue
+				}
+				if err := sink.EmitRowChangedEvents(ctx, events...); err != nil {
+					return errors.Trace(err)
+				}
+				commitTs := events[len(events)-1].CommitTs
+				lastCommitTs, ok := sink.tablesMap.Load(tableID)
+				if !ok || lastCommitTs.(uint64) < commitTs {
+					sink.tablesMap.Store(tableID, commitTs)
+				}
+			}
+			log.Debug("update sink resolved ts",
+				zap.Uint64("ts", ts), zap.Int32("partition", partition))
+			atomic.StoreUint64(&sink.resolvedTs, ts)
+		}
+	}
+}
+
+// append DDL wait to be handled, only consider the constraint among DDLs.
+// for DDL a / b received in the order, a.CommitTs < b.CommitTs should be true.
+func (c *Consumer) appendDDL(ddl *model.DDLEvent) error {
+	c.ddlListMu.Lock()
+	defer c.ddlListMu.Unlock()
+	if c.ddlWithMaxCommitTs != nil && ddl.CommitTs < c.ddlWithMaxCommitTs.CommitTs {
+		return errors.Errorf("DDL CommitTs %d < maxCommitTsDDL.CommitTs %d, DDL: %s",
+			ddl.CommitTs, c.ddlWithMaxCommitTs.CommitTs, ddl.Query)
+	}
+
+	c.ddlList = append(c.ddlList, ddl)
+	log.Info("DDL event received", zap.Any("DDL", ddl))
+	c.ddlWithMaxCommitTs = ddl
+	return nil
+}
+
+func (c *Consumer) getFrontDDL() *model.DDLEvent {
+	c.ddlListMu.Lock()
+	defer c.ddlListMu.Unlock()
+	if len(c.ddlList) > 0 {
+		return c.ddlList[0]
+	}
+	return nil
+}
+
+func (c *Consumer) popDDL() {
+	c.ddlListMu.Lock()
+	defer c.ddlListMu.Unlock()
+	if len(c.ddlList) > 0 {
+		c.ddlList = c.ddlList[1:]
+	}
+}
+
+func (c *Consumer) forEachSink(fn func(sink *partitionSink) error) error {
+	c.sinksMu.Lock()
+	defer c.sinksMu.Unlock()
+	for _, sink := range c.sinks {
+		if err := fn(sink); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) getMinPartitionResolvedTs() uint64 {
+	result := uint64(math.MaxUint64)
+	_ = c.forEachSink(func(sink *partitionSink) error {
+		a := atomic.LoadUint64(&sink.resolvedTs)
+		if a < result {
+			result = a
+		}
+		return nil
+	})
+	return result
+}
+
+// Run executes the DDLs and flushes the rows once all the partitions are resolved.
+func (c *Consumer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		minPartitionResolvedTs := c.getMinPartitionResolvedTs()
+
+		// handle DDL
+		todoDDL := c.getFrontDDL()
+		if todoDDL != nil && todoDDL.CommitTs <= minPartitionResolvedTs {
+			// flush DMLs
+			if err := c.forEachSink(func(sink *partitionSink) error {
+				return syncFlushRowChangedEvents(ctx, sink, todoDDL.CommitTs)
+			}); err != nil {
+				return errors.Trace(err)
+			}
+
+			// DDL can be executed, do it first.
+			if err := c.ddlSink.EmitDDLEvent(ctx, todoDDL); err != nil {
+				return errors.Trace(err)
+			}
+			c.popDDL()
+			minPartitionResolvedTs = todoDDL.CommitTs
+		}
+
+		globalResolvedTs := atomic.LoadUint64(&c.globalResolvedTs)
+		if globalResolvedTs > minPartitionResolvedTs {
+			return errors.Errorf("global ResolvedTs fallback, "+
+				"globalResolvedTs: %d, minPartitionResolvedTs: %d",
+				globalResolvedTs, minPartitionResolvedTs)
+		}
+		if globalResolvedTs == minPartitionResolvedTs {
+			continue
+		}
+		atomic.StoreUint64(&c.globalResolvedTs, minPartitionResolvedTs)
+
+		if err := c.forEachSink(func(sink *partitionSink) error {
+			return syncFlushRowChangedEvents(ctx, sink, minPartitionResolvedTs)
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+func syncFlushRowChangedEvents(ctx context.Context, sink *partitionSink, resolvedTs uint64) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		// tables are flushed
+		var (
+			err        error
+			checkpoint model.ResolvedTs
+		)
+		flushedResolvedTs := true
+		sink.tablesMap.Range(func(key, value interface{}) bool {
+			tableID := key.(int64)
+			checkpoint, err = sink.FlushRowChangedEvents(ctx,
+				tableID, model.NewResolvedTs(resolvedTs))
+			if err != nil {
+				return false
+			}
+			if checkpoint.Ts < resolvedTs {
+				flushedResolvedTs = false
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if flushedResolvedTs {
+			return nil
+		}
+	}
+}
+
+type fakeTableIDGenerator struct {
+	tableIDs       map[string]int64
+	currentTableID int64
+	mu             sync.Mutex
+}
+
+func (g *fakeTableIDGenerator) generateFakeTableID(schema, table string, partition int64) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := quotes.QuoteSchema(schema, table)
+	if partition != 0 {
+		key = fmt.Sprintf("%s.`%d`", key, partition)
+	}
+	if tableID, ok := g.tableIDs[key]; ok {
+		return tableID
+	}
+	g.currentTableID++
+	g.tableIDs[key] = g.currentTableID
+	return g.currentTableID
+}
@@ -0,0 +1,231 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq"
+	pulsarp "github.com/pingcap/tiflow/cdc/sink/mq/producer/pulsar"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink records the events written to it.
+type memorySink struct {
+	mu   sync.Mutex
+	rows []*model.RowChangedEvent
+	ddls []*model.DDLEvent
+}
+
+var _ sink.Sink = (*memorySink)(nil)
+
+func (s *memorySink) AddTable(_ model.TableID) error {
+	return nil
+}
+
+func (s *memorySink) EmitRowChangedEvents(_ context.Context, rows ...*model.RowChangedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = append(s.rows, rows...)
+	return nil
+}
+
+func (s *memorySink) EmitDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ddls = append(s.ddls, ddl)
+	return nil
+}
+
+func (s *memorySink) FlushRowChangedEvents(
+	_ context.Context, _ model.TableID, resolved model.ResolvedTs,
+) (model.ResolvedTs, error) {
+	return resolved, nil
+}
+
+func (s *memorySink) EmitCheckpointTs(_ context.Context, _ uint64, _ []model.TableName) error {
+	return nil
+}
+
+func (s *memorySink) Close(_ context.Context) error {
+	return nil
+}
+
+func (s *memorySink) RemoveTable(_ context.Context, _ model.TableID) error {
+	return nil
+}
+
+func TestNewConsumerOption(t *testing.T) {
+	option, err := newConsumerOption(
+		"pulsar://token@127.0.0.1:6650/test?protocol=canal-json"+
+			"&enable-tidb-extension=true&partition-num=3&subscription-name=sub", "")
+	require.Nil(t, err)
+	require.Equal(t, "pulsar://127.0.0.1:6650", option.address)
+	require.Equal(t, "token", option.token)
+	require.Equal(t, "test", option.topic)
+	require.Equal(t, "sub", option.subscriptionName)
+	require.Equal(t, int32(3), option.partitionNum)
+	require.Equal(t, config.ProtocolCanalJSON, option.protocol)
+	require.True(t, option.enableTiDBExtension)
+
+	_, err = newConsumerOption("kafka://127.0.0.1:9092/test", "")
+	require.Regexp(t, "invalid upstream-uri scheme", err)
+	_, err = newConsumerOption("pulsar://127.0.0.1:6650/", "")
+	require.Regexp(t, "no topic is specified", err)
+	_, err = newConsumerOption("pulsar://127.0.0.1:6650/test?enable-tidb-extension=true", "")
+	require.Regexp(t, "only work with canal-json", err)
+}
+
+func TestPartitionFromTopic(t *testing.T) {
+	partition, err := partitionFromTopic("persistent://public/default/test-partition-3")
+	require.Nil(t, err)
+	require.Equal(t, int32(3), partition)
+
+	partition, err = partitionFromTopic("persistent://public/default/test")
+	require.Nil(t, err)
+	require.Equal(t, int32(0), partition)
+
+	_, err = partitionFromTopic("test-partition-x")
+	require.Error(t, err)
+}
+
+func TestConsumeMessagesFromPulsarSink(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := pulsarp.NewMockClient(pulsarp.DefaultMockPartitionNum)
+	pulsarp.NewClientImpl = func(_ pulsar.ClientOptions) (pulsar.Client, error) {
+		return client, nil
+	}
+	defer func() {
+		pulsarp.NewClientImpl = pulsar.NewClient
+	}()
+
+	// Produce the events by the pulsar sink.
+	sinkURI, err := url.Parse("pulsar://127.0.0.1:6650/test?protocol=open-protocol")
+	require.Nil(t, err)
+	replicaConfig := config.GetDefaultReplicaConfig()
+	require.Nil(t, replicaConfig.ValidateAndAdjust(sinkURI))
+	pulsarSink, err := mq.NewPulsarSink(ctx, sinkURI, replicaConfig, make(chan error, 1))
+	require.Nil(t, err)
+	defer pulsarSink.Close(ctx)
+
+	ddl := &model.DDLEvent{
+		StartTs:  90,
+		CommitTs: 100,
+		Query:    "create table test.t1(id int primary key)",
+		Type:     timodel.ActionCreateTable,
+		TableInfo: &model.SimpleTableInfo{
+			Schema: "test",
+			Table:  "t1",
+		},
+	}
+	require.Nil(t, pulsarSink.EmitDDLEvent(ctx, ddl))
+
+	tables := []model.TableName{{Schema: "test", Table: "t1", TableID: 1}}
+	for i, commitTs := range []uint64{110, 120, 130} {
+		row := &model.RowChangedEvent{
+			Table:    &tables[0],
+			StartTs:  commitTs - 5,
+			CommitTs: commitTs,
+			Columns: []*model.Column{{
+				Name:  "id",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: int64(i),
+			}},
+		}
+		require.Nil(t, pulsarSink.EmitRowChangedEvents(ctx, row))
+	}
+	require.Eventually(t, func() bool {
+		checkpoint, err := pulsarSink.FlushRowChangedEvents(ctx, 1, model.NewResolvedTs(130))
+		return err == nil && checkpoint.Ts == 130
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Nil(t, pulsarSink.EmitCheckpointTs(ctx, 130, tables))
+
+	// Consume the events from the mock client.
+	option, err := newConsumerOption("pulsar://127.0.0.1:6650/test?partition-num=4", "")
+	require.Nil(t, err)
+	option.timezone = "UTC"
+	var sinks []*memorySink
+	consumer, err := NewConsumer(ctx, option,
+		func(_ context.Context, _ *consumerOption, _ chan error) (sink.Sink, error) {
+			s := &memorySink{}
+			sinks = append(sinks, s)
+			return s, nil
+		})
+	require.Nil(t, err)
+	defer consumer.Close(ctx)
+	require.Len(t, sinks, 1+pulsarp.DefaultMockPartitionNum)
+	ddlSink := sinks[len(sinks)-1]
+
+	pulsarConsumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:            option.topic,
+		SubscriptionName: option.subscriptionName,
+	})
+	require.Nil(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		err := consumer.Consume(ctx, pulsarConsumer)
+		require.Equal(t, context.Canceled, errors.Cause(err))
+	}()
+	go func() {
+		defer wg.Done()
+		err := consumer.Run(ctx)
+		require.Equal(t, context.Canceled, errors.Cause(err))
+	}()
+
+	require.Eventually(t, func() bool {
+		ddlSink.mu.Lock()
+		defer ddlSink.mu.Unlock()
+		return len(ddlSink.ddls) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, ddl.Query, ddlSink.ddls[0].Query)
+
+	// All the rows of the table are dispatched to the same partition.
+	var rows []*model.RowChangedEvent
+	require.Eventually(t, func() bool {
+		rows = rows[:0]
+		for _, s := range sinks[:len(sinks)-1] {
+			s.mu.Lock()
+			rows = append(rows, s.rows...)
+			s.mu.Unlock()
+		}
+		return len(rows) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	for i, row := range rows {
+		require.Equal(t, "t1", row.Table.Table)
+		require.Equal(t, uint64(110+10*i), row.CommitTs)
+	}
+	require.Eventually(t, func() bool {
+		return consumer.getMinPartitionResolvedTs() == 130
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
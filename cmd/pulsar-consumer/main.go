@@ -0,0 +1,221 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	cmdUtil "github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/logutil"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// consumerOption is the option of the pulsar consumer.
+type consumerOption struct {
+	address          string
+	token            string
+	topic            string
+	subscriptionName string
+	partitionNum     int32
+
+	protocol            config.Protocol
+	enableTiDBExtension bool
+
+	downstreamURI string
+	timezone      string
+
+	// eventRouterReplicaConfig only used to initialize the consumer's eventRouter
+	// which then can be used to check RowChangedEvent dispatched correctness
+	eventRouterReplicaConfig *config.ReplicaConfig
+}
+
+func newConsumerOption(upstreamURIStr, configFile string) (*consumerOption, error) {
+	upstreamURI, err := url.Parse(upstreamURIStr)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid upstream-uri")
+	}
+	scheme := strings.ToLower(upstreamURI.Scheme)
+	if scheme != "pulsar" && scheme != "pulsar+ssl" {
+		return nil, errors.Errorf("invalid upstream-uri scheme %s, "+
+			"the scheme of upstream-uri must be `pulsar` or `pulsar+ssl`", scheme)
+	}
+
+	option := &consumerOption{
+		address: (&url.URL{Scheme: scheme, Host: upstreamURI.Host}).String(),
+		token:   upstreamURI.User.Username(),
+		topic: strings.TrimFunc(upstreamURI.Path, func(r rune) bool {
+			return r == '/'
+		}),
+		subscriptionName: fmt.Sprintf("ticdc_pulsar_consumer_%s", uuid.New().String()),
+		protocol:         config.ProtocolOpen,
+	}
+	if option.topic == "" {
+		return nil, errors.New("no topic is specified in upstream-uri")
+	}
+
+	query := upstreamURI.Query()
+	if s := query.Get("subscription-name"); s != "" {
+		option.subscriptionName = s
+	}
+	if s := query.Get("partition-num"); s != "" {
+		c, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid partition-num of upstream-uri")
+		}
+		option.partitionNum = int32(c)
+	}
+	if s := query.Get("protocol"); s != "" {
+		if err := option.protocol.FromString(s); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if s := query.Get("enable-tidb-extension"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid enable-tidb-extension of upstream-uri")
+		}
+		if option.protocol != config.ProtocolCanalJSON && b {
+			return nil, errors.New("enable-tidb-extension only work with canal-json")
+		}
+		option.enableTiDBExtension = b
+	}
+
+	if configFile != "" {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = option.protocol.String()
+		err := cmdUtil.StrictDecodeFile(configFile, "pulsar consumer", replicaConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := filter.VerifyTableRules(replicaConfig.Filter); err != nil {
+			return nil, errors.Trace(err)
+		}
+		option.eventRouterReplicaConfig = replicaConfig
+	}
+	return option, nil
+}
+
+func main() {
+	var (
+		upstreamURIStr   string
+		downstreamURIStr string
+		configFile       string
+		logPath          string
+		logLevel         string
+		timezone         string
+	)
+	flag.StringVar(&upstreamURIStr, "upstream-uri", "", "Pulsar uri")
+	flag.StringVar(&downstreamURIStr, "downstream-uri", "", "downstream sink uri")
+	flag.StringVar(&configFile, "config", "", "config file for changefeed")
+	flag.StringVar(&logPath, "log-file", "cdc_pulsar_consumer.log", "log file path")
+	flag.StringVar(&logLevel, "log-level", "info", "log level")
+	flag.StringVar(&timezone, "tz", "System", "Specify time zone of Pulsar consumer")
+	flag.Parse()
+
+	err := logutil.InitLogger(&logutil.Config{
+		Level: logLevel,
+		File:  logPath,
+	}, logutil.WithInitGRPCLogger())
+	if err != nil {
+		log.Panic("init logger failed", zap.Error(err))
+	}
+
+	option, err := newConsumerOption(upstreamURIStr, configFile)
+	if err != nil {
+		log.Panic("invalid upstream-uri or config", zap.Error(err))
+	}
+	option.downstreamURI = downstreamURIStr
+	option.timezone = timezone
+
+	clientOption := pulsar.ClientOptions{URL: option.address}
+	if option.token != "" {
+		clientOption.Authentication = pulsar.NewAuthenticationToken(option.token)
+	}
+	client, err := pulsar.NewClient(clientOption)
+	if err != nil {
+		log.Panic("Error creating pulsar client", zap.Error(err))
+	}
+	defer client.Close()
+
+	if option.partitionNum == 0 {
+		partitions, err := client.TopicPartitions(option.topic)
+		if err != nil {
+			log.Panic("can not get partition number",
+				zap.String("topic", option.topic), zap.Error(err))
+		}
+		option.partitionNum = int32(len(partitions))
+	}
+	log.Info("Setting partitionNum", zap.Int32("partitionNum", option.partitionNum))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	consumer, err := NewConsumer(ctx, option, newSink)
+	if err != nil {
+		log.Panic("Error creating consumer", zap.Error(err))
+	}
+	defer consumer.Close(context.Background())
+
+	pulsarConsumer, err := client.Subscribe(pulsar.ConsumerOptions{
+		Topic:                       option.topic,
+		SubscriptionName:            option.subscriptionName,
+		Type:                        pulsar.Exclusive,
+		SubscriptionInitialPosition: pulsar.SubscriptionPositionEarliest,
+	})
+	if err != nil {
+		log.Panic("Error subscribing topic",
+			zap.String("topic", option.topic), zap.Error(err))
+	}
+	defer pulsarConsumer.Close()
+
+	log.Info("Starting a new TiCDC pulsar consumer",
+		zap.String("subscription", option.subscriptionName),
+		zap.Any("protocol", option.protocol))
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return consumer.Consume(ctx, pulsarConsumer)
+	})
+	g.Go(func() error {
+		return consumer.Run(ctx)
+	})
+	g.Go(func() error {
+		sigterm := make(chan os.Signal, 1)
+		signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+		select {
+		case <-ctx.Done():
+			log.Info("terminating: context cancelled")
+		case <-sigterm:
+			log.Info("terminating: via signal")
+		}
+		cancel()
+		return nil
+	})
+	if err := g.Wait(); err != nil && errors.Cause(err) != context.Canceled {
+		log.Panic("Error running consumer", zap.Error(err))
+	}
+}
@@ -29,11 +29,11 @@ type ChangefeedsGetter interface {
 // ChangefeedInterface has methods to work with Changfeed items.
 // We can also mock the changefeed operations by implement this interface.
 type ChangefeedInterface interface {
-	Get(ctx context.Context, name string) (*model.ChangefeedDetail, error)
-	List(ctx context.Context, state string) (*[]model.ChangefeedCommonInfo, error)
-	Delete(ctx context.Context, name string) error
-	Pause(ctx context.Context, name string) error
-	Resume(ctx context.Context, name string) error
+	Get(ctx context.Context, namespace string, name string) (*model.ChangefeedDetail, error)
+	List(ctx context.Context, namespace string, state string) (*[]model.ChangefeedCommonInfo, error)
+	Delete(ctx context.Context, namespace string, name string) error
+	Pause(ctx context.Context, namespace string, name string) error
+	Resume(ctx context.Context, namespace string, name string) error
 }
 
 // changefeeds implements ChangefeedInterface
@@ -50,23 +50,28 @@ func newChangefeeds(c *APIV1Client) *changefeeds {
 
 // Get takes name of the changefeed, and returns the corresponding changefeed object,
 // and an error if there is any.
-func (c *changefeeds) Get(ctx context.Context, name string) (*model.ChangefeedDetail, error) {
+func (c *changefeeds) Get(ctx context.Context,
+	namespace string, name string,
+) (*model.ChangefeedDetail, error) {
 	result := new(model.ChangefeedDetail)
 	u := fmt.Sprintf("changefeeds/%s", name)
 	err := c.client.Get().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).
 		Into(result)
 	return result, err
 }
 
-// List returns the list of changefeeds
+// List returns the list of changefeeds, changefeeds in all namespaces are
+// returned if namespace is empty.
 func (c *changefeeds) List(ctx context.Context,
-	state string,
+	namespace string, state string,
 ) (*[]model.ChangefeedCommonInfo, error) {
 	result := new([]model.ChangefeedCommonInfo)
 	err := c.client.Get().
 		WithURI("changefeeds").
+		WithParam("namespace", namespace).
 		WithParam("state", state).
 		Do(ctx).
 		Into(result)
@@ -74,25 +79,28 @@ func (c *changefeeds) List(ctx context.Context,
 }
 
 // Pause the changefeed
-func (c *changefeeds) Pause(ctx context.Context, name string) error {
+func (c *changefeeds) Pause(ctx context.Context, namespace string, name string) error {
 	u := fmt.Sprintf("changefeeds/%s/pause", name)
 	return c.client.Post().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).Error()
 }
 
 // Resume a changefeed
-func (c *changefeeds) Resume(ctx context.Context, name string) error {
+func (c *changefeeds) Resume(ctx context.Context, namespace string, name string) error {
 	u := fmt.Sprintf("changefeeds/%s/resume", name)
 	return c.client.Post().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).Error()
 }
 
 // Delete delete the changefeed
-func (c *changefeeds) Delete(ctx context.Context, name string) error {
+func (c *changefeeds) Delete(ctx context.Context, namespace string, name string) error {
 	u := fmt.Sprintf("changefeeds/%s", name)
 	return c.client.Delete().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).Error()
 }
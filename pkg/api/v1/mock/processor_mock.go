@@ -47,8 +47,7 @@ func (m *MockProcessorsGetter) Processors() v1.ProcessorInterface {
 // Processors indicates an expected call of Processors.
 func (mr *MockProcessorsGetterMockRecorder) Processors() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Processors",
-		reflect.TypeOf((*MockProcessorsGetter)(nil).Processors))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Processors", reflect.TypeOf((*MockProcessorsGetter)(nil).Processors))
 }
 
 // MockProcessorInterface is a mock of ProcessorInterface interface.
@@ -75,23 +74,18 @@ func (m *MockProcessorInterface) EXPECT() *MockProcessorInterfaceMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockProcessorInterface) Get(ctx context.Context, changefeedID,
-	captureID string,
-) (*model.ProcessorDetail, error) {
+func (m *MockProcessorInterface) Get(ctx context.Context, namespace, changefeedID, captureID string) (*model.ProcessorDetail, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, changefeedID, captureID)
+	ret := m.ctrl.Call(m, "Get", ctx, namespace, changefeedID, captureID)
 	ret0, _ := ret[0].(*model.ProcessorDetail)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProcessorInterfaceMockRecorder) Get(ctx, changefeedID,
-	captureID interface{},
-) *gomock.Call {
+func (mr *MockProcessorInterfaceMockRecorder) Get(ctx, namespace, changefeedID, captureID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get",
-		reflect.TypeOf((*MockProcessorInterface)(nil).Get), ctx, changefeedID, captureID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProcessorInterface)(nil).Get), ctx, namespace, changefeedID, captureID)
 }
 
 // List mocks base method.
@@ -106,6 +100,5 @@ func (m *MockProcessorInterface) List(ctx context.Context) (*[]model.ProcessorCo
 // List indicates an expected call of List.
 func (mr *MockProcessorInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List",
-		reflect.TypeOf((*MockProcessorInterface)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProcessorInterface)(nil).List), ctx)
 }
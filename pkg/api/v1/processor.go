@@ -29,7 +29,7 @@ type ProcessorsGetter interface {
 // ProcessorInterface has methods to work with Processor items.
 // We can also mock the processor operations by implement this interface.
 type ProcessorInterface interface {
-	Get(ctx context.Context, changefeedID, captureID string) (*model.ProcessorDetail, error)
+	Get(ctx context.Context, namespace, changefeedID, captureID string) (*model.ProcessorDetail, error)
 	List(ctx context.Context) (*[]model.ProcessorCommonInfo, error)
 }
 
@@ -47,11 +47,14 @@ func newProcessors(c *APIV1Client) *processors {
 
 // Get takes name of the processor, and returns the corresponding processor object,
 // and an error if there is any.
-func (c *processors) Get(ctx context.Context, changefeedID, captureID string) (*model.ProcessorDetail, error) {
+func (c *processors) Get(ctx context.Context,
+	namespace, changefeedID, captureID string,
+) (*model.ProcessorDetail, error) {
 	result := new(model.ProcessorDetail)
 	u := fmt.Sprintf("processors/%s/%s", changefeedID, captureID)
 	err := c.client.Get().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).
 		Into(result)
 	return result, err
@@ -24,7 +24,9 @@ import (
 type APIV2Interface interface {
 	RESTClient() rest.CDCRESTInterface
 	ChangefeedsGetter
+	NamespacesGetter
 	TsoGetter
+	UpstreamsGetter
 	UnsafeGetter
 }
 
@@ -66,6 +68,22 @@ func (c *APIV2Client) Changefeeds() ChangefeedInterface {
 	return newChangefeeds(c)
 }
 
+// Namespaces returns a NamespaceInterface with cdc api
+func (c *APIV2Client) Namespaces() NamespaceInterface {
+	if c == nil {
+		return nil
+	}
+	return newNamespaces(c)
+}
+
+// Upstreams returns a UpstreamInterface with cdc api
+func (c *APIV2Client) Upstreams() UpstreamInterface {
+	if c == nil {
+		return nil
+	}
+	return newUpstreams(c)
+}
+
 // NewAPIClient creates a new APIV1Client.
 func NewAPIClient(serverAddr string, credential *security.Credential) (*APIV2Client, error) {
 	c := &rest.Config{}
@@ -16,6 +16,7 @@ package v2
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	"github.com/pingcap/tiflow/pkg/api/internal/rest"
@@ -31,15 +32,48 @@ type ChangefeedsGetter interface {
 type ChangefeedInterface interface {
 	// Create creates a changefeed
 	Create(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error)
+	// DryRun checks a changefeed config without creating the changefeed
+	DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.DryRunReport, error)
 	// GetInfo gets a changefeed's info
-	GetInfo(ctx context.Context, name string) (*v2.ChangeFeedInfo, error)
+	GetInfo(ctx context.Context, namespace string, name string) (*v2.ChangeFeedInfo, error)
 	// VerifyTable verifies table for a changefeed
 	VerifyTable(ctx context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error)
-	// Update updates a changefeed
+	// Update updates a changefeed, the reason is recorded in its history
 	Update(ctx context.Context, cfg *v2.ChangefeedConfig,
-		name string) (*v2.ChangeFeedInfo, error)
+		namespace string, name string, reason string) (*v2.ChangeFeedInfo, error)
 	// Resume resumes a changefeed with given config
-	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error
+	Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig,
+		namespace string, name string) error
+	// History gets the latest revisions of a changefeed's info
+	History(ctx context.Context, namespace string, name string) (*v2.ChangefeedHistory, error)
+	// DiffHistory gets the config changes between two revisions of a changefeed,
+	// 0 means the default revision
+	DiffHistory(ctx context.Context, namespace string, name string,
+		from uint64, to uint64) (*v2.ChangefeedHistoryDiff, error)
+	// Rollback rolls back a changefeed's config to a previous revision
+	Rollback(ctx context.Context, cfg *v2.RollbackChangefeedConfig,
+		namespace string, name string) (*v2.ChangeFeedInfo, error)
+	// TableSchema gets the schema of a table at ts in the schema storage
+	// of a changefeed, 0 means the checkpoint ts of the changefeed
+	TableSchema(ctx context.Context, namespace string, name string,
+		schema string, table string, ts uint64) (*v2.TableSchema, error)
+	// TableDDLEvents gets the DDLs applied to a table in (startTs, endTs],
+	// 0 means the default ts
+	TableDDLEvents(ctx context.Context, namespace string, name string,
+		schema string, table string, startTs uint64, endTs uint64,
+	) (*v2.TableDDLEvents, error)
+	// SnapshotProgress gets the initial snapshot progress of the tables
+	SnapshotProgress(ctx context.Context, namespace string, name string,
+	) (*v2.ChangefeedSnapshotProgress, error)
+	// ResyncTable re-syncs a table of a running changefeed
+	ResyncTable(ctx context.Context, cfg *v2.ResyncTableConfig,
+		namespace string, name string) error
+	// Latency gets the replication latency of the tables at each stage
+	Latency(ctx context.Context, namespace string, name string,
+	) (*v2.ChangefeedLatency, error)
+	// Diagnose collects the diagnosis information of a changefeed
+	Diagnose(ctx context.Context, namespace string, name string,
+	) (*v2.ChangefeedDiagnosis, error)
 }
 
 // changefeeds implements ChangefeedInterface
@@ -65,6 +99,18 @@ func (c *changefeeds) Create(ctx context.Context,
 	return result, err
 }
 
+func (c *changefeeds) DryRun(ctx context.Context,
+	cfg *v2.ChangefeedConfig,
+) (*v2.DryRunReport, error) {
+	result := &v2.DryRunReport{}
+	err := c.client.Post().
+		WithURI("changefeeds").
+		WithParam("dry_run", "true").
+		WithBody(cfg).
+		Do(ctx).Into(result)
+	return result, err
+}
+
 func (c *changefeeds) VerifyTable(ctx context.Context,
 	cfg *v2.VerifyTableConfig,
 ) (*v2.Tables, error) {
@@ -78,24 +124,27 @@ func (c *changefeeds) VerifyTable(ctx context.Context,
 }
 
 func (c *changefeeds) GetInfo(ctx context.Context,
-	name string,
+	namespace string, name string,
 ) (*v2.ChangeFeedInfo, error) {
 	result := &v2.ChangeFeedInfo{}
 	u := fmt.Sprintf("changefeeds/%s/meta_info", name)
 	err := c.client.Get().
 		WithURI(u).
+		WithParam("namespace", namespace).
 		Do(ctx).
 		Into(result)
 	return result, err
 }
 
 func (c *changefeeds) Update(ctx context.Context,
-	cfg *v2.ChangefeedConfig, name string,
+	cfg *v2.ChangefeedConfig, namespace string, name string, reason string,
 ) (*v2.ChangeFeedInfo, error) {
 	result := &v2.ChangeFeedInfo{}
 	u := fmt.Sprintf("changefeeds/%s", name)
 	err := c.client.Put().
 		WithURI(u).
+		WithParam("namespace", namespace).
+		WithParam("reason", reason).
 		WithBody(cfg).
 		Do(ctx).
 		Into(result)
@@ -104,11 +153,155 @@ func (c *changefeeds) Update(ctx context.Context,
 
 // Resume a changefeed
 func (c *changefeeds) Resume(ctx context.Context,
-	cfg *v2.ResumeChangefeedConfig, name string,
+	cfg *v2.ResumeChangefeedConfig, namespace string, name string,
 ) error {
 	u := fmt.Sprintf("changefeeds/%s/resume", name)
 	return c.client.Post().
 		WithURI(u).
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).Error()
+}
+
+// History gets the latest revisions of a changefeed's info
+func (c *changefeeds) History(ctx context.Context,
+	namespace string, name string,
+) (*v2.ChangefeedHistory, error) {
+	result := &v2.ChangefeedHistory{}
+	u := fmt.Sprintf("changefeeds/%s/history", name)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// DiffHistory gets the config changes between two revisions of a changefeed
+func (c *changefeeds) DiffHistory(ctx context.Context,
+	namespace string, name string, from uint64, to uint64,
+) (*v2.ChangefeedHistoryDiff, error) {
+	result := &v2.ChangefeedHistoryDiff{}
+	u := fmt.Sprintf("changefeeds/%s/history/diff", name)
+	req := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace)
+	if from != 0 {
+		req = req.WithParam("from", strconv.FormatUint(from, 10))
+	}
+	if to != 0 {
+		req = req.WithParam("to", strconv.FormatUint(to, 10))
+	}
+	err := req.Do(ctx).Into(result)
+	return result, err
+}
+
+// Rollback rolls back a changefeed's config to a previous revision
+func (c *changefeeds) Rollback(ctx context.Context,
+	cfg *v2.RollbackChangefeedConfig, namespace string, name string,
+) (*v2.ChangeFeedInfo, error) {
+	result := &v2.ChangeFeedInfo{}
+	u := fmt.Sprintf("changefeeds/%s/rollback", name)
+	err := c.client.Post().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// TableSchema gets the schema of a table at ts in the schema storage
+// of a changefeed
+func (c *changefeeds) TableSchema(ctx context.Context,
+	namespace string, name string, schema string, table string, ts uint64,
+) (*v2.TableSchema, error) {
+	result := &v2.TableSchema{}
+	u := fmt.Sprintf("changefeeds/%s/schema", name)
+	req := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithParam("schema", schema).
+		WithParam("table", table)
+	if ts != 0 {
+		req = req.WithParam("ts", strconv.FormatUint(ts, 10))
+	}
+	err := req.Do(ctx).Into(result)
+	return result, err
+}
+
+// TableDDLEvents gets the DDLs applied to a table in (startTs, endTs]
+func (c *changefeeds) TableDDLEvents(ctx context.Context,
+	namespace string, name string, schema string, table string,
+	startTs uint64, endTs uint64,
+) (*v2.TableDDLEvents, error) {
+	result := &v2.TableDDLEvents{}
+	u := fmt.Sprintf("changefeeds/%s/schema/ddl", name)
+	req := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		WithParam("schema", schema).
+		WithParam("table", table)
+	if startTs != 0 {
+		req = req.WithParam("start_ts", strconv.FormatUint(startTs, 10))
+	}
+	if endTs != 0 {
+		req = req.WithParam("end_ts", strconv.FormatUint(endTs, 10))
+	}
+	err := req.Do(ctx).Into(result)
+	return result, err
+}
+
+// SnapshotProgress gets the initial snapshot progress of the tables
+func (c *changefeeds) SnapshotProgress(ctx context.Context,
+	namespace string, name string,
+) (*v2.ChangefeedSnapshotProgress, error) {
+	result := &v2.ChangefeedSnapshotProgress{}
+	u := fmt.Sprintf("changefeeds/%s/snapshot", name)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// ResyncTable re-syncs a table of a running changefeed
+func (c *changefeeds) ResyncTable(ctx context.Context,
+	cfg *v2.ResyncTableConfig, namespace string, name string,
+) error {
+	u := fmt.Sprintf("changefeeds/%s/resync", name)
+	return c.client.Post().
+		WithURI(u).
+		WithParam("namespace", namespace).
 		WithBody(cfg).
 		Do(ctx).Error()
 }
+
+// Latency gets the replication latency of the tables at each stage
+func (c *changefeeds) Latency(ctx context.Context,
+	namespace string, name string,
+) (*v2.ChangefeedLatency, error) {
+	result := &v2.ChangefeedLatency{}
+	u := fmt.Sprintf("changefeeds/%s/latency", name)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// Diagnose collects the diagnosis information of a changefeed
+func (c *changefeeds) Diagnose(ctx context.Context,
+	namespace string, name string,
+) (*v2.ChangefeedDiagnosis, error) {
+	result := &v2.ChangefeedDiagnosis{}
+	u := fmt.Sprintf("changefeeds/%s/diagnose", name)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
@@ -88,48 +88,197 @@ func (mr *MockChangefeedInterfaceMockRecorder) Create(ctx, cfg interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockChangefeedInterface)(nil).Create), ctx, cfg)
 }
 
+// Diagnose mocks base method.
+func (m *MockChangefeedInterface) Diagnose(ctx context.Context, namespace, name string) (*v2.ChangefeedDiagnosis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diagnose", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.ChangefeedDiagnosis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diagnose indicates an expected call of Diagnose.
+func (mr *MockChangefeedInterfaceMockRecorder) Diagnose(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diagnose", reflect.TypeOf((*MockChangefeedInterface)(nil).Diagnose), ctx, namespace, name)
+}
+
+// DiffHistory mocks base method.
+func (m *MockChangefeedInterface) DiffHistory(ctx context.Context, namespace, name string, from, to uint64) (*v2.ChangefeedHistoryDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffHistory", ctx, namespace, name, from, to)
+	ret0, _ := ret[0].(*v2.ChangefeedHistoryDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffHistory indicates an expected call of DiffHistory.
+func (mr *MockChangefeedInterfaceMockRecorder) DiffHistory(ctx, namespace, name, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffHistory", reflect.TypeOf((*MockChangefeedInterface)(nil).DiffHistory), ctx, namespace, name, from, to)
+}
+
+// DryRun mocks base method.
+func (m *MockChangefeedInterface) DryRun(ctx context.Context, cfg *v2.ChangefeedConfig) (*v2.DryRunReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", ctx, cfg)
+	ret0, _ := ret[0].(*v2.DryRunReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockChangefeedInterfaceMockRecorder) DryRun(ctx, cfg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockChangefeedInterface)(nil).DryRun), ctx, cfg)
+}
+
 // GetInfo mocks base method.
-func (m *MockChangefeedInterface) GetInfo(ctx context.Context, name string) (*v2.ChangeFeedInfo, error) {
+func (m *MockChangefeedInterface) GetInfo(ctx context.Context, namespace, name string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetInfo", ctx, name)
+	ret := m.ctrl.Call(m, "GetInfo", ctx, namespace, name)
 	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetInfo indicates an expected call of GetInfo.
-func (mr *MockChangefeedInterfaceMockRecorder) GetInfo(ctx, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) GetInfo(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockChangefeedInterface)(nil).GetInfo), ctx, namespace, name)
+}
+
+// History mocks base method.
+func (m *MockChangefeedInterface) History(ctx context.Context, namespace, name string) (*v2.ChangefeedHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.ChangefeedHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockChangefeedInterfaceMockRecorder) History(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockChangefeedInterface)(nil).History), ctx, namespace, name)
+}
+
+// Latency mocks base method.
+func (m *MockChangefeedInterface) Latency(ctx context.Context, namespace, name string) (*v2.ChangefeedLatency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latency", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.ChangefeedLatency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latency indicates an expected call of Latency.
+func (mr *MockChangefeedInterfaceMockRecorder) Latency(ctx, namespace, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockChangefeedInterface)(nil).GetInfo), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latency", reflect.TypeOf((*MockChangefeedInterface)(nil).Latency), ctx, namespace, name)
 }
 
 // Resume mocks base method.
-func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, name string) error {
+func (m *MockChangefeedInterface) Resume(ctx context.Context, cfg *v2.ResumeChangefeedConfig, namespace, name string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Resume", ctx, cfg, name)
+	ret := m.ctrl.Call(m, "Resume", ctx, cfg, namespace, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Resume indicates an expected call of Resume.
-func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Resume(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, namespace, name)
+}
+
+// ResyncTable mocks base method.
+func (m *MockChangefeedInterface) ResyncTable(ctx context.Context, cfg *v2.ResyncTableConfig, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResyncTable", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResyncTable indicates an expected call of ResyncTable.
+func (mr *MockChangefeedInterfaceMockRecorder) ResyncTable(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResyncTable", reflect.TypeOf((*MockChangefeedInterface)(nil).ResyncTable), ctx, cfg, namespace, name)
+}
+
+// Rollback mocks base method.
+func (m *MockChangefeedInterface) Rollback(ctx context.Context, cfg *v2.RollbackChangefeedConfig, namespace, name string) (*v2.ChangeFeedInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", ctx, cfg, namespace, name)
+	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockChangefeedInterfaceMockRecorder) Rollback(ctx, cfg, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockChangefeedInterface)(nil).Rollback), ctx, cfg, namespace, name)
+}
+
+// SnapshotProgress mocks base method.
+func (m *MockChangefeedInterface) SnapshotProgress(ctx context.Context, namespace, name string) (*v2.ChangefeedSnapshotProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotProgress", ctx, namespace, name)
+	ret0, _ := ret[0].(*v2.ChangefeedSnapshotProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotProgress indicates an expected call of SnapshotProgress.
+func (mr *MockChangefeedInterfaceMockRecorder) SnapshotProgress(ctx, namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotProgress", reflect.TypeOf((*MockChangefeedInterface)(nil).SnapshotProgress), ctx, namespace, name)
+}
+
+// TableDDLEvents mocks base method.
+func (m *MockChangefeedInterface) TableDDLEvents(ctx context.Context, namespace, name, schema, table string, startTs, endTs uint64) (*v2.TableDDLEvents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TableDDLEvents", ctx, namespace, name, schema, table, startTs, endTs)
+	ret0, _ := ret[0].(*v2.TableDDLEvents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TableDDLEvents indicates an expected call of TableDDLEvents.
+func (mr *MockChangefeedInterfaceMockRecorder) TableDDLEvents(ctx, namespace, name, schema, table, startTs, endTs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TableDDLEvents", reflect.TypeOf((*MockChangefeedInterface)(nil).TableDDLEvents), ctx, namespace, name, schema, table, startTs, endTs)
+}
+
+// TableSchema mocks base method.
+func (m *MockChangefeedInterface) TableSchema(ctx context.Context, namespace, name, schema, table string, ts uint64) (*v2.TableSchema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TableSchema", ctx, namespace, name, schema, table, ts)
+	ret0, _ := ret[0].(*v2.TableSchema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TableSchema indicates an expected call of TableSchema.
+func (mr *MockChangefeedInterfaceMockRecorder) TableSchema(ctx, namespace, name, schema, table, ts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockChangefeedInterface)(nil).Resume), ctx, cfg, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TableSchema", reflect.TypeOf((*MockChangefeedInterface)(nil).TableSchema), ctx, namespace, name, schema, table, ts)
 }
 
 // Update mocks base method.
-func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, name string) (*v2.ChangeFeedInfo, error) {
+func (m *MockChangefeedInterface) Update(ctx context.Context, cfg *v2.ChangefeedConfig, namespace, name, reason string) (*v2.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, cfg, name)
+	ret := m.ctrl.Call(m, "Update", ctx, cfg, namespace, name, reason)
 	ret0, _ := ret[0].(*v2.ChangeFeedInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, name interface{}) *gomock.Call {
+func (mr *MockChangefeedInterfaceMockRecorder) Update(ctx, cfg, namespace, name, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockChangefeedInterface)(nil).Update), ctx, cfg, namespace, name, reason)
 }
 
 // VerifyTable mocks base method.
@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: upstream.go
+
+// Package mock_v2 is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	v20 "github.com/pingcap/tiflow/pkg/api/v2"
+)
+
+// MockUpstreamsGetter is a mock of UpstreamsGetter interface.
+type MockUpstreamsGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpstreamsGetterMockRecorder
+}
+
+// MockUpstreamsGetterMockRecorder is the mock recorder for MockUpstreamsGetter.
+type MockUpstreamsGetterMockRecorder struct {
+	mock *MockUpstreamsGetter
+}
+
+// NewMockUpstreamsGetter creates a new mock instance.
+func NewMockUpstreamsGetter(ctrl *gomock.Controller) *MockUpstreamsGetter {
+	mock := &MockUpstreamsGetter{ctrl: ctrl}
+	mock.recorder = &MockUpstreamsGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpstreamsGetter) EXPECT() *MockUpstreamsGetterMockRecorder {
+	return m.recorder
+}
+
+// Upstreams mocks base method.
+func (m *MockUpstreamsGetter) Upstreams() v20.UpstreamInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upstreams")
+	ret0, _ := ret[0].(v20.UpstreamInterface)
+	return ret0
+}
+
+// Upstreams indicates an expected call of Upstreams.
+func (mr *MockUpstreamsGetterMockRecorder) Upstreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upstreams", reflect.TypeOf((*MockUpstreamsGetter)(nil).Upstreams))
+}
+
+// MockUpstreamInterface is a mock of UpstreamInterface interface.
+type MockUpstreamInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUpstreamInterfaceMockRecorder
+}
+
+// MockUpstreamInterfaceMockRecorder is the mock recorder for MockUpstreamInterface.
+type MockUpstreamInterfaceMockRecorder struct {
+	mock *MockUpstreamInterface
+}
+
+// NewMockUpstreamInterface creates a new mock instance.
+func NewMockUpstreamInterface(ctrl *gomock.Controller) *MockUpstreamInterface {
+	mock := &MockUpstreamInterface{ctrl: ctrl}
+	mock.recorder = &MockUpstreamInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUpstreamInterface) EXPECT() *MockUpstreamInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUpstreamInterface) Create(ctx context.Context, cfg *v2.UpstreamConfig, namespace string) (*v2.UpstreamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cfg, namespace)
+	ret0, _ := ret[0].(*v2.UpstreamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUpstreamInterfaceMockRecorder) Create(ctx, cfg, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUpstreamInterface)(nil).Create), ctx, cfg, namespace)
+}
+
+// Delete mocks base method.
+func (m *MockUpstreamInterface) Delete(ctx context.Context, namespace string, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, namespace, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUpstreamInterfaceMockRecorder) Delete(ctx, namespace, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUpstreamInterface)(nil).Delete), ctx, namespace, id)
+}
+
+// Get mocks base method.
+func (m *MockUpstreamInterface) Get(ctx context.Context, namespace string, id uint64) (*v2.UpstreamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, namespace, id)
+	ret0, _ := ret[0].(*v2.UpstreamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUpstreamInterfaceMockRecorder) Get(ctx, namespace, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUpstreamInterface)(nil).Get), ctx, namespace, id)
+}
+
+// List mocks base method.
+func (m *MockUpstreamInterface) List(ctx context.Context, namespace string) ([]v2.UpstreamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, namespace)
+	ret0, _ := ret[0].([]v2.UpstreamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockUpstreamInterfaceMockRecorder) List(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUpstreamInterface)(nil).List), ctx, namespace)
+}
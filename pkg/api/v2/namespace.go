@@ -0,0 +1,109 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// NamespacesGetter has a method to return a NamespaceInterface.
+type NamespacesGetter interface {
+	Namespaces() NamespaceInterface
+}
+
+// NamespaceInterface has methods to work with Namespace items.
+// We can also mock the namespace operations by implement this interface.
+type NamespaceInterface interface {
+	// List lists all namespaces
+	List(ctx context.Context) ([]v2.NamespaceInfo, error)
+	// Create creates a namespace
+	Create(ctx context.Context, info *v2.NamespaceInfo) (*v2.NamespaceInfo, error)
+	// Get gets a namespace
+	Get(ctx context.Context, name string) (*v2.NamespaceInfo, error)
+	// Update updates the quotas of a namespace
+	Update(ctx context.Context, info *v2.NamespaceInfo,
+		name string) (*v2.NamespaceInfo, error)
+	// Delete deletes an empty namespace
+	Delete(ctx context.Context, name string) error
+}
+
+// namespaces implements NamespaceInterface
+type namespaces struct {
+	client rest.CDCRESTInterface
+}
+
+// newNamespaces returns namespaces
+func newNamespaces(c *APIV2Client) *namespaces {
+	return &namespaces{
+		client: c.RESTClient(),
+	}
+}
+
+func (c *namespaces) List(ctx context.Context) ([]v2.NamespaceInfo, error) {
+	result := make([]v2.NamespaceInfo, 0)
+	err := c.client.Get().
+		WithURI("namespaces").
+		Do(ctx).
+		Into(&result)
+	return result, err
+}
+
+func (c *namespaces) Create(ctx context.Context,
+	info *v2.NamespaceInfo,
+) (*v2.NamespaceInfo, error) {
+	result := &v2.NamespaceInfo{}
+	err := c.client.Post().
+		WithURI("namespaces").
+		WithBody(info).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *namespaces) Get(ctx context.Context,
+	name string,
+) (*v2.NamespaceInfo, error) {
+	result := &v2.NamespaceInfo{}
+	u := fmt.Sprintf("namespaces/%s", name)
+	err := c.client.Get().
+		WithURI(u).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *namespaces) Update(ctx context.Context,
+	info *v2.NamespaceInfo, name string,
+) (*v2.NamespaceInfo, error) {
+	result := &v2.NamespaceInfo{}
+	u := fmt.Sprintf("namespaces/%s", name)
+	err := c.client.Put().
+		WithURI(u).
+		WithBody(info).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *namespaces) Delete(ctx context.Context, name string) error {
+	u := fmt.Sprintf("namespaces/%s", name)
+	return c.client.Delete().
+		WithURI(u).
+		Do(ctx).
+		Error()
+}
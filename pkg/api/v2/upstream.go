@@ -0,0 +1,102 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/pkg/api/internal/rest"
+)
+
+// UpstreamsGetter has a method to return a UpstreamInterface.
+type UpstreamsGetter interface {
+	Upstreams() UpstreamInterface
+}
+
+// UpstreamInterface has methods to work with Upstream items.
+// We can also mock the upstream operations by implement this interface.
+type UpstreamInterface interface {
+	// List lists all upstreams registered in a namespace
+	List(ctx context.Context, namespace string) ([]v2.UpstreamConfig, error)
+	// Create registers an upstream in a namespace
+	Create(ctx context.Context, cfg *v2.UpstreamConfig,
+		namespace string) (*v2.UpstreamConfig, error)
+	// Get gets a registered upstream
+	Get(ctx context.Context, namespace string, id uint64) (*v2.UpstreamConfig, error)
+	// Delete removes an upstream that is not used by any changefeed
+	Delete(ctx context.Context, namespace string, id uint64) error
+}
+
+// upstreams implements UpstreamInterface
+type upstreams struct {
+	client rest.CDCRESTInterface
+}
+
+// newUpstreams returns upstreams
+func newUpstreams(c *APIV2Client) *upstreams {
+	return &upstreams{
+		client: c.RESTClient(),
+	}
+}
+
+func (c *upstreams) List(ctx context.Context,
+	namespace string,
+) ([]v2.UpstreamConfig, error) {
+	result := make([]v2.UpstreamConfig, 0)
+	err := c.client.Get().
+		WithURI("upstreams").
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(&result)
+	return result, err
+}
+
+func (c *upstreams) Create(ctx context.Context,
+	cfg *v2.UpstreamConfig, namespace string,
+) (*v2.UpstreamConfig, error) {
+	result := &v2.UpstreamConfig{}
+	err := c.client.Post().
+		WithURI("upstreams").
+		WithParam("namespace", namespace).
+		WithBody(cfg).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *upstreams) Get(ctx context.Context,
+	namespace string, id uint64,
+) (*v2.UpstreamConfig, error) {
+	result := &v2.UpstreamConfig{}
+	u := fmt.Sprintf("upstreams/%d", id)
+	err := c.client.Get().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *upstreams) Delete(ctx context.Context,
+	namespace string, id uint64,
+) error {
+	u := fmt.Sprintf("upstreams/%d", id)
+	return c.client.Delete().
+		WithURI(u).
+		WithParam("namespace", namespace).
+		Do(ctx).
+		Error()
+}
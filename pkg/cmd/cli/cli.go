@@ -87,9 +87,11 @@ func NewCmdCli() *cobra.Command {
 	// Add subcommands.
 	cmds.AddCommand(newCmdCapture(f))
 	cmds.AddCommand(newCmdChangefeed(f))
+	cmds.AddCommand(newCmdNamespace(f))
 	cmds.AddCommand(newCmdProcessor(f))
 	cmds.AddCommand(newCmdTso(f))
 	cmds.AddCommand(newCmdUnsafe(f))
+	cmds.AddCommand(newCmdUpstream(f))
 
 	return cmds
 }
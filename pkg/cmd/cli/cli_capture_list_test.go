@@ -52,13 +52,19 @@ type mockAPIV2Client struct {
 	apiv2client.APIV2Interface
 	tso         apiv2client.TsoInterface
 	changefeeds apiv2client.ChangefeedInterface
+	namespaces  apiv2client.NamespaceInterface
 	unsafes     apiv2client.UnsafeInterface
+	upstreams   apiv2client.UpstreamInterface
 }
 
 func (f *mockAPIV2Client) Changefeeds() apiv2client.ChangefeedInterface {
 	return f.changefeeds
 }
 
+func (f *mockAPIV2Client) Namespaces() apiv2client.NamespaceInterface {
+	return f.namespaces
+}
+
 func (f *mockAPIV2Client) Tso() apiv2client.TsoInterface {
 	return f.tso
 }
@@ -67,6 +73,10 @@ func (f *mockAPIV2Client) Unsafe() apiv2client.UnsafeInterface {
 	return f.unsafes
 }
 
+func (f *mockAPIV2Client) Upstreams() apiv2client.UpstreamInterface {
+	return f.upstreams
+}
+
 type mockFactory struct {
 	factory.Factory
 	captures    *mock.MockCaptureInterface
@@ -75,8 +85,10 @@ type mockFactory struct {
 	status      *mock.MockStatusInterface
 
 	changefeedsv2 *v2mock.MockChangefeedInterface
+	namespaces    *v2mock.MockNamespaceInterface
 	tso           *v2mock.MockTsoInterface
 	unsafes       *v2mock.MockUnsafeInterface
+	upstreams     *v2mock.MockUpstreamInterface
 }
 
 func newMockFactory(ctrl *gomock.Controller) *mockFactory {
@@ -87,14 +99,18 @@ func newMockFactory(ctrl *gomock.Controller) *mockFactory {
 	unsafes := v2mock.NewMockUnsafeInterface(ctrl)
 	tso := v2mock.NewMockTsoInterface(ctrl)
 	cfv2 := v2mock.NewMockChangefeedInterface(ctrl)
+	namespaces := v2mock.NewMockNamespaceInterface(ctrl)
+	upstreams := v2mock.NewMockUpstreamInterface(ctrl)
 	return &mockFactory{
 		captures:      cps,
 		changefeeds:   cf,
 		processor:     processor,
 		status:        status,
 		changefeedsv2: cfv2,
+		namespaces:    namespaces,
 		tso:           tso,
 		unsafes:       unsafes,
+		upstreams:     upstreams,
 	}
 }
 
@@ -110,8 +126,10 @@ func (f *mockFactory) APIV1Client() (apiv1client.APIV1Interface, error) {
 func (f *mockFactory) APIV2Client() (apiv2client.APIV2Interface, error) {
 	return &mockAPIV2Client{
 		changefeeds: f.changefeedsv2,
+		namespaces:  f.namespaces,
 		tso:         f.tso,
 		unsafes:     f.unsafes,
+		upstreams:   f.upstreams,
 	}, nil
 }
 
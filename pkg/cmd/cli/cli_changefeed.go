@@ -34,6 +34,13 @@ func newCmdChangefeed(f factory.Factory) *cobra.Command {
 	cmds.AddCommand(newCmdQueryChangefeed(f))
 	cmds.AddCommand(newCmdRemoveChangefeed(f))
 	cmds.AddCommand(newCmdResumeChangefeed(f))
+	cmds.AddCommand(newCmdHistoryChangefeed(f))
+	cmds.AddCommand(newCmdRollbackChangefeed(f))
+	cmds.AddCommand(newCmdSchemaChangefeed(f))
+	cmds.AddCommand(newCmdSnapshotChangefeed(f))
+	cmds.AddCommand(newCmdResyncChangefeed(f))
+	cmds.AddCommand(newCmdLatencyChangefeed(f))
+	cmds.AddCommand(newCmdDiagnoseChangefeed(f))
 
 	return cmds
 }
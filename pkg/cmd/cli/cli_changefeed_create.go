@@ -108,10 +108,16 @@ type createChangefeedOptions struct {
 	commonChangefeedOptions *changefeedCommonOptions
 	apiClient               apiv2client.APIV2Interface
 
+	namespace               string
 	changefeedID            string
 	disableGCSafePointCheck bool
 	startTs                 uint64
 	timezone                string
+	dryRun                  bool
+	upstreamID              uint64
+
+	// registeredUpstream is the upstream specified by upstreamID.
+	registeredUpstream *v2.UpstreamConfig
 
 	cfg *config.ReplicaConfig
 }
@@ -127,9 +133,15 @@ func newCreateChangefeedOptions(commonChangefeedOptions *changefeedCommonOptions
 // flags related to template printing to it.
 func (o *createChangefeedOptions) addFlags(cmd *cobra.Command) {
 	o.commonChangefeedOptions.addFlags(cmd)
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	cmd.PersistentFlags().BoolVarP(&o.disableGCSafePointCheck, "disable-gc-check", "", false, "Disable GC safe point check")
 	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "Start ts of changefeed")
+	cmd.PersistentFlags().Uint64Var(&o.upstreamID, "upstream-id", 0,
+		"ID of a registered upstream to replicate from, the upstream of the server is used if not specified")
+	cmd.PersistentFlags().BoolVar(&o.dryRun, "dry-run", false,
+		"Check the changefeed and print a report without creating it, "+
+			"exit with 2 if any check fails and 3 if there are only warnings")
 	cmd.PersistentFlags().StringVar(&o.timezone, "tz", "SYSTEM", "timezone used when checking sink uri (changefeed timezone is determined by cdc server)")
 	// we don't support specify these flags below when cdc version >= 6.2.0
 	_ = cmd.PersistentFlags().MarkHidden("tz")
@@ -226,6 +238,9 @@ func (o *createChangefeedOptions) validate(cmd *cobra.Command) error {
 			"The --sort-dir here will be no-op\n"))
 		return errors.New("Creating changefeed with `--sort-dir`, it's invalid")
 	}
+	if o.upstreamID != 0 && o.commonChangefeedOptions.upstreamPDAddrs != "" {
+		return errors.New("--upstream-id and --upstream-pd can not be specified at the same time")
+	}
 
 	switch o.commonChangefeedOptions.sortEngine {
 	case model.SortInMemory:
@@ -244,6 +259,7 @@ func (o *createChangefeedOptions) getChangefeedConfig(cmd *cobra.Command) *v2.Ch
 	replicaConfig := v2.ToAPIReplicaConfig(o.cfg)
 	upstreamConfig := o.getUpstreamConfig()
 	return &v2.ChangefeedConfig{
+		Namespace:         o.namespace,
 		ID:                o.changefeedID,
 		StartTs:           o.startTs,
 		TargetTs:          o.commonChangefeedOptions.targetTs,
@@ -252,11 +268,16 @@ func (o *createChangefeedOptions) getChangefeedConfig(cmd *cobra.Command) *v2.Ch
 		ReplicaConfig:     replicaConfig,
 		SyncPointEnabled:  o.commonChangefeedOptions.syncPointEnabled,
 		SyncPointInterval: o.commonChangefeedOptions.syncPointInterval,
+		UpstreamID:        o.upstreamID,
 		PDConfig:          upstreamConfig.PDConfig,
 	}
 }
 
 func (o *createChangefeedOptions) getUpstreamConfig() *v2.UpstreamConfig {
+	if o.registeredUpstream != nil {
+		// Connect to the registered upstream with its own PD and TLS config.
+		return &v2.UpstreamConfig{PDConfig: o.registeredUpstream.PDConfig}
+	}
 	var (
 		pdAddrs  []string
 		caPath   string
@@ -282,6 +303,14 @@ func (o *createChangefeedOptions) getUpstreamConfig() *v2.UpstreamConfig {
 
 // run the `cli changefeed create` command.
 func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) error {
+	if o.upstreamID != 0 {
+		upstream, err := o.apiClient.Upstreams().Get(ctx, o.namespace, o.upstreamID)
+		if err != nil {
+			return err
+		}
+		o.registeredUpstream = upstream
+	}
+
 	tso, err := o.apiClient.Tso().Query(ctx, o.getUpstreamConfig())
 	if err != nil {
 		return err
@@ -291,6 +320,10 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 		o.startTs = oracle.ComposeTS(tso.Timestamp, tso.LogicTime)
 	}
 
+	if o.dryRun {
+		return o.runDryRun(ctx, cmd)
+	}
+
 	if !o.commonChangefeedOptions.noConfirm {
 		if err = confirmLargeDataGap(cmd, tso.Timestamp, o.startTs); err != nil {
 			return err
@@ -369,6 +402,38 @@ func (o *createChangefeedOptions) run(ctx context.Context, cmd *cobra.Command) e
 	return nil
 }
 
+// Exit codes of `cli changefeed create --dry-run`, 1 is used
+// when the dry run itself fails.
+const (
+	dryRunExitCodeFail = 2
+	dryRunExitCodeWarn = 3
+)
+
+// runDryRun checks the changefeed without creating it and prints the report.
+func (o *createChangefeedOptions) runDryRun(ctx context.Context, cmd *cobra.Command) error {
+	createChangefeedCfg := o.getChangefeedConfig(cmd)
+	createChangefeedCfg.ReplicaConfig.IgnoreIneligibleTable = o.commonChangefeedOptions.noConfirm
+
+	report, err := o.apiClient.Changefeeds().DryRun(ctx, createChangefeedCfg)
+	if err != nil {
+		return err
+	}
+	if err = util.JSONPrint(cmd, report); err != nil {
+		return err
+	}
+	// the report has been printed, there is no need to print the usage.
+	cmd.SilenceUsage = true
+	if report.HasStatus(v2.DryRunCheckFail) {
+		return util.NewExitCodeError(dryRunExitCodeFail,
+			"changefeed dry run failed, see the report above")
+	}
+	if report.HasStatus(v2.DryRunCheckWarn) {
+		return util.NewExitCodeError(dryRunExitCodeWarn,
+			"changefeed dry run passed with warnings, see the report above")
+	}
+	return nil
+}
+
 // newCmdCreateChangefeed creates the `cli changefeed create` command.
 func newCmdCreateChangefeed(f factory.Factory) *cobra.Command {
 	commonChangefeedOptions := newChangefeedCommonOptions()
@@ -14,7 +14,11 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,8 +26,10 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
 	v2 "github.com/pingcap/tiflow/cdc/api/v2"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -178,3 +184,122 @@ func TestChangefeedCreateCli(t *testing.T) {
 	}
 	require.True(t, strings.Contains(cmd.Execute().Error(), "sort-dir"))
 }
+
+func TestChangefeedCreateDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	passed := &v2.DryRunReport{ID: "abc"}
+	passed.AddCheck("sink", v2.DryRunCheckPass, "")
+	warned := &v2.DryRunReport{ID: "abc"}
+	warned.AddCheck("tables", v2.DryRunCheckWarn, "ineligible tables")
+	failed := &v2.DryRunReport{ID: "abc"}
+	failed.AddCheck("tables", v2.DryRunCheckWarn, "ineligible tables")
+	failed.AddCheck("sink", v2.DryRunCheckFail, "sink is unreachable")
+
+	cases := []struct {
+		report   *v2.DryRunReport
+		err      error
+		exitCode int
+	}{
+		{report: passed},
+		{report: warned, exitCode: dryRunExitCodeWarn},
+		{report: failed, exitCode: dryRunExitCodeFail},
+		{err: errors.New("dry run error"), exitCode: 1},
+	}
+	for _, c := range cases {
+		cmd := newCmdCreateChangefeed(f)
+		os.Args = []string{
+			"create",
+			"--sink-uri=blackhole://",
+			"--changefeed-id=abc",
+			"--dry-run",
+			"--no-confirm",
+		}
+		f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&v2.Tso{
+			Timestamp: time.Now().Unix() * 1000,
+		}, nil)
+		// neither tables are verified nor the changefeed is created
+		f.changefeedsv2.EXPECT().DryRun(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, cfg *v2.ChangefeedConfig) (*v2.DryRunReport, error) {
+				require.Equal(t, "abc", cfg.ID)
+				require.True(t, cfg.ReplicaConfig.IgnoreIneligibleTable)
+				return c.report, c.err
+			})
+		b := bytes.NewBufferString("")
+		cmd.SetOut(b)
+		err := cmd.Execute()
+		if c.exitCode == 0 {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+			require.Equal(t, c.exitCode, util.GetExitCode(err))
+		}
+		if c.report != nil {
+			out, err := io.ReadAll(b)
+			require.Nil(t, err)
+			report := &v2.DryRunReport{}
+			require.Nil(t, json.Unmarshal(out, report))
+			require.Equal(t, c.report, report)
+		}
+	}
+}
+
+func TestChangefeedCreateWithUpstreamID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	pdConfig := v2.PDConfig{
+		PDAddrs:       []string{"http://127.0.0.1:2379"},
+		CAPath:        "ca.pem",
+		CertAllowedCN: []string{"tidb"},
+	}
+	f.upstreams.EXPECT().Get(gomock.Any(), "ns1", uint64(123)).
+		Return(&v2.UpstreamConfig{ID: 123, PDConfig: pdConfig}, nil)
+	f.tso.EXPECT().Query(gomock.Any(), &v2.UpstreamConfig{PDConfig: pdConfig}).
+		Return(&v2.Tso{Timestamp: time.Now().Unix() * 1000}, nil)
+	f.changefeedsv2.EXPECT().VerifyTable(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, cfg *v2.VerifyTableConfig) (*v2.Tables, error) {
+			require.Equal(t, pdConfig, cfg.PDConfig)
+			return &v2.Tables{}, nil
+		})
+	f.changefeedsv2.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, cfg *v2.ChangefeedConfig) (*v2.ChangeFeedInfo, error) {
+			require.Equal(t, uint64(123), cfg.UpstreamID)
+			require.Equal(t, pdConfig, cfg.PDConfig)
+			return &v2.ChangeFeedInfo{UpstreamID: 123}, nil
+		})
+	cmd := newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--namespace=ns1",
+		"--sink-uri=blackhole://",
+		"--changefeed-id=abc",
+		"--upstream-id=123",
+		"--no-confirm",
+	}
+	require.Nil(t, cmd.Execute())
+
+	// the upstream is not registered
+	f.upstreams.EXPECT().Get(gomock.Any(), "ns1", uint64(100)).
+		Return(nil, errors.New("upstream not found"))
+	cmd = newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--namespace=ns1",
+		"--sink-uri=blackhole://",
+		"--upstream-id=100",
+	}
+	require.NotNil(t, cmd.Execute())
+
+	cmd = newCmdCreateChangefeed(f)
+	os.Args = []string{
+		"create",
+		"--sink-uri=blackhole://",
+		"--upstream-id=100",
+		"--upstream-pd=http://127.0.0.1:2379",
+	}
+	require.Contains(t, cmd.Execute().Error(), "--upstream-id")
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// diagnoseChangefeedOptions defines flags for the `cli changefeed diagnose` command.
+type diagnoseChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+	output       string
+}
+
+// newDiagnoseChangefeedOptions creates new options for the `cli changefeed diagnose` command.
+func newDiagnoseChangefeedOptions() *diagnoseChangefeedOptions {
+	return &diagnoseChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *diagnoseChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVarP(&o.output, "output", "o", "",
+		"Path of the tar.gz file to write, default to <namespace>-<changefeed-id>-diagnose-<time>.tar.gz")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *diagnoseChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	if o.output == "" {
+		o.output = fmt.Sprintf("%s-%s-diagnose-%s.tar.gz",
+			o.namespace, o.changefeedID, time.Now().Format("20060102150405"))
+	}
+	return nil
+}
+
+// run the `cli changefeed diagnose` command.
+func (o *diagnoseChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	diagnosis, err := o.apiClient.Changefeeds().
+		Diagnose(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	if err := writeDiagnosisArchive(o.output, diagnosis); err != nil {
+		return err
+	}
+
+	cmd.Print(formatFindings(diagnosis))
+	cmd.Printf("diagnosis written to %s\n", o.output)
+	return nil
+}
+
+// formatFindings renders the findings and collect errors of a diagnosis
+// in a human-readable form.
+func formatFindings(d *v2.ChangefeedDiagnosis) string {
+	buf := &bytes.Buffer{}
+	if len(d.Findings) == 0 {
+		fmt.Fprintln(buf, "no problem found")
+	}
+	for _, finding := range d.Findings {
+		fmt.Fprintf(buf, "[%s] %s: %s\n", finding.Severity, finding.Cause, finding.Message)
+	}
+	for _, collectErr := range d.CollectErrors {
+		fmt.Fprintf(buf, "[incomplete] %s\n", collectErr)
+	}
+	return buf.String()
+}
+
+// writeDiagnosisArchive packs the diagnosis into a single tar.gz file.
+// Sink URIs are masked again in case the diagnosis is returned by a server
+// which does not mask them.
+func writeDiagnosisArchive(path string, d *v2.ChangefeedDiagnosis) error {
+	d.MaskSinkURI()
+	diagnosisJSON, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	etcdJSON, err := json.MarshalIndent(d.Etcd, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "diagnosis.json", content: diagnosisJSON},
+		{name: "findings.txt", content: []byte(formatFindings(d))},
+		{name: "etcd.json", content: etcdJSON},
+	}
+	for _, c := range d.Captures {
+		content := c.DebugInfo
+		if c.Error != "" {
+			content = fmt.Sprintf("failed to collect debug info: %s\n", c.Error)
+		}
+		files = append(files, struct {
+			name    string
+			content []byte
+		}{name: fmt.Sprintf("captures/%s.txt", c.ID), content: []byte(content)})
+	}
+
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	modTime := time.Now()
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    file.name,
+			Mode:    0o644,
+			Size:    int64(len(file.content)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := tw.Write(file.content); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.WriteFile(path, buf.Bytes(), 0o644))
+}
+
+// newCmdDiagnoseChangefeed creates the `cli changefeed diagnose` command.
+func newCmdDiagnoseChangefeed(f factory.Factory) *cobra.Command {
+	o := newDiagnoseChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Collect the diagnosis information of a replication task (changefeed) into a tar.gz file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
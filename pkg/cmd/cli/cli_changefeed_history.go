@@ -0,0 +1,117 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// cfRevision is the summary of a revision of a changefeed's info.
+type cfRevision struct {
+	Revision   uint64         `json:"revision"`
+	UpdateTime model.JSONTime `json:"update_time"`
+	Reason     string         `json:"reason"`
+}
+
+// historyChangefeedOptions defines flags for the `cli changefeed history` command.
+type historyChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+	diff         bool
+	from         uint64
+	to           uint64
+}
+
+// newHistoryChangefeedOptions creates new options for the `cli changefeed history` command.
+func newHistoryChangefeedOptions() *historyChangefeedOptions {
+	return &historyChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *historyChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().BoolVar(&o.diff, "diff", false, "Output the config changes between two revisions")
+	cmd.PersistentFlags().Uint64Var(&o.from, "from", 0, "The revision to diff from, default to the previous revision of --to")
+	cmd.PersistentFlags().Uint64Var(&o.to, "to", 0, "The revision to diff to, default to the current revision")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *historyChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed history` command.
+func (o *historyChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	if o.diff || o.from != 0 || o.to != 0 {
+		diff, err := o.apiClient.Changefeeds().
+			DiffHistory(ctx, o.namespace, o.changefeedID, o.from, o.to)
+		if err != nil {
+			return err
+		}
+		return util.JSONPrint(cmd, diff)
+	}
+
+	history, err := o.apiClient.Changefeeds().History(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	revisions := make([]cfRevision, 0, len(history.Revisions))
+	for _, revision := range history.Revisions {
+		revisions = append(revisions, cfRevision{
+			Revision:   revision.Revision,
+			UpdateTime: model.JSONTime(revision.UpdateTime),
+			Reason:     revision.Reason,
+		})
+	}
+	return util.JSONPrint(cmd, revisions)
+}
+
+// newCmdHistoryChangefeed creates the `cli changefeed history` command.
+func newCmdHistoryChangefeed(f factory.Factory) *cobra.Command {
+	o := newHistoryChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "history",
+		Short: "List the config revisions of a replication task (changefeed) or diff two of them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedHistoryCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	// list revisions
+	cmd := newCmdHistoryChangefeed(f)
+	cfV2.EXPECT().History(gomock.Any(), "ns", "abc").Return(&v2.ChangefeedHistory{
+		Revisions: []v2.ChangefeedRevision{
+			{Revision: 1, UpdateTime: time.Now()},
+			{Revision: 2, UpdateTime: time.Now(), Reason: "change sink"},
+		},
+	}, nil)
+	os.Args = []string{"history", "-n=ns", "-c=abc"}
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	var revisions []cfRevision
+	require.Nil(t, json.Unmarshal(out, &revisions))
+	require.Len(t, revisions, 2)
+	require.Equal(t, "change sink", revisions[1].Reason)
+
+	// diff the current revision with the previous one
+	cmd = newCmdHistoryChangefeed(f)
+	cfV2.EXPECT().DiffHistory(gomock.Any(), "default", "abc", uint64(0), uint64(0)).
+		Return(&v2.ChangefeedHistoryDiff{From: 1, To: 2}, nil)
+	os.Args = []string{"history", "-c=abc", "--diff"}
+	require.Nil(t, cmd.Execute())
+
+	// diff two specified revisions
+	cmd = newCmdHistoryChangefeed(f)
+	cfV2.EXPECT().DiffHistory(gomock.Any(), "default", "abc", uint64(1), uint64(3)).
+		Return(&v2.ChangefeedHistoryDiff{
+			From: 1,
+			To:   3,
+			Changes: []v2.ChangefeedConfigChange{
+				{Path: "sink-uri", Old: "blackhole://", New: "kafka://127.0.0.1:9092/abc"},
+			},
+		}, nil)
+	os.Args = []string{"history", "-c=abc", "--from=1", "--to=3"}
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err = io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "kafka://127.0.0.1:9092/abc")
+}
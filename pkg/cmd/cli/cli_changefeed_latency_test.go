@@ -0,0 +1,64 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedLatencyCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	cmd := newCmdLatencyChangefeed(f)
+	cfV2.EXPECT().Latency(gomock.Any(), "ns", "abc").
+		Return(&v2.ChangefeedLatency{
+			Namespace: "ns",
+			ID:        "abc",
+			Tables: []v2.TableLatency{{
+				TableID:   1,
+				TableName: "test.t",
+				CaptureID: "capture-1",
+				Stages: []v2.StageLatency{
+					{Stage: "puller", Count: 3, AvgLagMs: 5, MaxLagMs: 8, LastLagMs: 4},
+				},
+			}},
+		}, nil)
+	os.Args = []string{"latency", "-n=ns", "-c=abc"}
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	latency := &v2.ChangefeedLatency{}
+	require.Nil(t, json.Unmarshal(out, latency))
+	require.Len(t, latency.Tables, 1)
+	require.Equal(t, int64(8), latency.Tables[0].Stages[0].MaxLagMs)
+
+	// changefeed id is required
+	cmd = newCmdLatencyChangefeed(f)
+	os.Args = []string{"latency"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -38,7 +38,8 @@ type changefeedCommonInfo struct {
 type listChangefeedOptions struct {
 	apiClient apiv1client.APIV1Interface
 
-	listAll bool
+	namespace string
+	listAll   bool
 }
 
 // newListChangefeedOptions creates new options for the `cli changefeed list` command.
@@ -50,6 +51,7 @@ func newListChangefeedOptions() *listChangefeedOptions {
 // flags related to template printing to it.
 func (o *listChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.listAll, "all", "a", false, "List all replication tasks(including removed and finished)")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "", "List replication tasks in the namespace, all namespaces are listed if empty")
 }
 
 // complete adapts from the command line args to the data and client required.
@@ -66,7 +68,7 @@ func (o *listChangefeedOptions) complete(f factory.Factory) error {
 func (o *listChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.GetDefaultContext()
 
-	raw, err := o.apiClient.Changefeeds().List(ctx, "all")
+	raw, err := o.apiClient.Changefeeds().List(ctx, o.namespace, "all")
 	if err != nil {
 		return err
 	}
@@ -34,7 +34,7 @@ func TestChangefeedListCli(t *testing.T) {
 	cmd := newCmdListChangefeed(f)
 	b := bytes.NewBufferString("")
 	cmd.SetOut(b)
-	cf.EXPECT().List(gomock.Any(), gomock.Any()).Return(&[]model.ChangefeedCommonInfo{
+	cf.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(&[]model.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -107,6 +107,6 @@ func TestChangefeedListCli(t *testing.T) {
 	require.Contains(t, string(out), "stopped-6")
 
 	os.Args = []string{"list", "--all=false"}
-	cf.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("test"))
+	cf.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("test"))
 	require.NotNil(t, cmd.Execute())
 }
@@ -24,6 +24,7 @@ import (
 type pauseChangefeedOptions struct {
 	apiClient apiv1client.APIV1Interface
 
+	namespace    string
 	changefeedID string
 }
 
@@ -35,6 +36,7 @@ func newPauseChangefeedOptions() *pauseChangefeedOptions {
 // addFlags receives a *cobra.Command reference and binds
 // flags related to template printing to it.
 func (o *pauseChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
@@ -53,7 +55,7 @@ func (o *pauseChangefeedOptions) complete(f factory.Factory) error {
 // run the `cli changefeed pause` command.
 func (o *pauseChangefeedOptions) run() error {
 	ctx := context.GetDefaultContext()
-	return o.apiClient.Changefeeds().Pause(ctx, o.changefeedID)
+	return o.apiClient.Changefeeds().Pause(ctx, o.namespace, o.changefeedID)
 }
 
 // newCmdPauseChangefeed creates the `cli changefeed pause` command.
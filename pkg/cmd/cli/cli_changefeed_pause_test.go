@@ -29,10 +29,10 @@ func TestChangefeedPauseCli(t *testing.T) {
 	cf := mock.NewMockChangefeedInterface(ctrl)
 	f := &mockFactory{changefeeds: cf}
 	cmd := newCmdPauseChangefeed(f)
-	cf.EXPECT().Pause(gomock.Any(), "abc").Return(nil)
+	cf.EXPECT().Pause(gomock.Any(), "default", "abc").Return(nil)
 	os.Args = []string{"pause", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
-	cf.EXPECT().Pause(gomock.Any(), "abc").Return(errors.New("test"))
+	cf.EXPECT().Pause(gomock.Any(), "default", "abc").Return(errors.New("test"))
 	os.Args = []string{"pause", "--changefeed-id=abc"}
 	require.NotNil(t, cmd.Execute())
 }
@@ -47,12 +47,16 @@ type cfMeta struct {
 	ErrorHis       []int64                   `json:"error_history"`
 	CreatorVersion string                    `json:"creator_version"`
 	TaskStatus     []model.CaptureTaskStatus `json:"task_status,omitempty"`
+
+	LastRewrittenDDL *model.RewrittenDDL    `json:"last_rewritten_ddl,omitempty"`
+	LastErrors       []*model.RecordedError `json:"last_errors,omitempty"`
 }
 
 // queryChangefeedOptions defines flags for the `cli changefeed query` command.
 type queryChangefeedOptions struct {
 	apiClient    apiv1client.APIV1Interface
 	apiClientV2  apiv2client.APIV2Interface
+	namespace    string
 	changefeedID string
 	simplified   bool
 }
@@ -66,6 +70,7 @@ func newQueryChangefeedOptions() *queryChangefeedOptions {
 // flags related to template printing to it.
 func (o *queryChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVarP(&o.simplified, "simple", "s", false, "Output simplified replication status")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
@@ -89,7 +94,7 @@ func (o *queryChangefeedOptions) complete(f factory.Factory) error {
 func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := context.Background()
 	if o.simplified {
-		infos, err := o.apiClient.Changefeeds().List(ctx, "all")
+		infos, err := o.apiClient.Changefeeds().List(ctx, o.namespace, "all")
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -101,12 +106,12 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		return cerror.ErrChangeFeedNotExists.GenWithStackByArgs(o.changefeedID)
 	}
 
-	detail, err := o.apiClient.Changefeeds().Get(ctx, o.changefeedID)
+	detail, err := o.apiClient.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
 		return err
 	}
 
-	info, err := o.apiClientV2.Changefeeds().GetInfo(ctx, o.changefeedID)
+	info, err := o.apiClientV2.Changefeeds().GetInfo(ctx, o.namespace, o.changefeedID)
 	if err != nil && cerror.ErrChangeFeedNotExists.NotEqual(err) {
 		return err
 	}
@@ -128,6 +133,9 @@ func (o *queryChangefeedOptions) run(cmd *cobra.Command) error {
 		ErrorHis:       detail.ErrorHis,
 		CreatorVersion: detail.CreatorVersion,
 		TaskStatus:     detail.TaskStatus,
+
+		LastRewrittenDDL: detail.LastRewrittenDDL,
+		LastErrors:       detail.LastErrors,
 	}
 	return util.JSONPrint(cmd, meta)
 }
@@ -37,7 +37,7 @@ func TestChangefeedQueryCli(t *testing.T) {
 	f := &mockFactory{changefeeds: cfV1, changefeedsv2: cfV2}
 
 	cmd := newCmdQueryChangefeed(f)
-	cfV1.EXPECT().List(gomock.Any(), "all").Return(&[]model.ChangefeedCommonInfo{
+	cfV1.EXPECT().List(gomock.Any(), "default", "all").Return(&[]model.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -48,7 +48,7 @@ func TestChangefeedQueryCli(t *testing.T) {
 	}, nil)
 	os.Args = []string{"query", "--simple=true", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
-	cfV1.EXPECT().List(gomock.Any(), "all").Return(&[]model.ChangefeedCommonInfo{
+	cfV1.EXPECT().List(gomock.Any(), "default", "all").Return(&[]model.ChangefeedCommonInfo{
 		{
 			UpstreamID:     1,
 			Namespace:      "default",
@@ -60,13 +60,13 @@ func TestChangefeedQueryCli(t *testing.T) {
 	os.Args = []string{"query", "--simple=true", "--changefeed-id=abcd"}
 	require.NotNil(t, cmd.Execute())
 
-	cfV1.EXPECT().List(gomock.Any(), "all").Return(nil, errors.New("test"))
+	cfV1.EXPECT().List(gomock.Any(), "default", "all").Return(nil, errors.New("test"))
 	os.Args = []string{"query", "--simple=true", "--changefeed-id=abcd"}
 	require.NotNil(t, cmd.Execute())
 
 	// query success
-	cfV1.EXPECT().Get(gomock.Any(), "bcd").Return(&model.ChangefeedDetail{}, nil)
-	cfV2.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(&v2.ChangeFeedInfo{
+	cfV1.EXPECT().Get(gomock.Any(), "default", "bcd").Return(&model.ChangefeedDetail{}, nil)
+	cfV2.EXPECT().GetInfo(gomock.Any(), gomock.Any(), gomock.Any()).Return(&v2.ChangeFeedInfo{
 		Config: v2.GetDefaultReplicaConfig(),
 	}, nil)
 	os.Args = []string{"query", "--simple=false", "--changefeed-id=bcd"}
@@ -79,7 +79,7 @@ func TestChangefeedQueryCli(t *testing.T) {
 	require.Contains(t, string(out), "config")
 
 	// query failed
-	cfV1.EXPECT().Get(gomock.Any(), "bcd").Return(nil, errors.New("test"))
+	cfV1.EXPECT().Get(gomock.Any(), "default", "bcd").Return(nil, errors.New("test"))
 	os.Args = []string{"query", "--simple=false", "--changefeed-id=bcd"}
 	require.NotNil(t, cmd.Execute())
 }
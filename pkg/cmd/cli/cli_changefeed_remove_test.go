@@ -31,17 +31,17 @@ func TestChangefeedRemoveCli(t *testing.T) {
 	cf := mock.NewMockChangefeedInterface(ctrl)
 	f := &mockFactory{changefeeds: cf}
 	cmd := newCmdRemoveChangefeed(f)
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{}, nil)
-	cf.EXPECT().Delete(gomock.Any(), "abc").Return(nil)
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil,
+	cf.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{}, nil)
+	cf.EXPECT().Delete(gomock.Any(), "default", "abc").Return(nil)
+	cf.EXPECT().Get(gomock.Any(), "default", "abc").Return(nil,
 		cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc"))
 	os.Args = []string{"remove", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil,
+	cf.EXPECT().Get(gomock.Any(), "default", "abc").Return(nil,
 		cerror.ErrChangeFeedNotExists.GenWithStackByArgs("abc"))
 	os.Args = []string{"remove", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
-	cf.EXPECT().Get(gomock.Any(), "abc").Return(nil, errors.New("abc"))
+	cf.EXPECT().Get(gomock.Any(), "default", "abc").Return(nil, errors.New("abc"))
 	os.Args = []string{"remove", "--changefeed-id=abc"}
 	require.NotNil(t, cmd.Execute())
 }
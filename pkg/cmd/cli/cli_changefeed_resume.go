@@ -34,6 +34,7 @@ type resumeChangefeedOptions struct {
 	apiV1Client apiv1client.APIV1Interface
 	apiV2Client apiv2client.APIV2Interface
 
+	namespace             string
 	changefeedID          string
 	changefeedDetail      *model.ChangefeedDetail
 	noConfirm             bool
@@ -55,6 +56,7 @@ func newResumeChangefeedOptions() *resumeChangefeedOptions {
 // addFlags receives a *cobra.Command reference and binds
 // flags related to template printing to it.
 func (o *resumeChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	cmd.PersistentFlags().BoolVar(&o.noConfirm, "no-confirm", false, "Don't ask user whether to ignore ineligible table")
 	cmd.PersistentFlags().StringVar(&o.overwriteCheckpointTs, "overwrite-checkpoint-ts", "",
@@ -138,7 +140,7 @@ func (o *resumeChangefeedOptions) getTSO(ctx context.Context) (*v2.Tso, error) {
 func (o *resumeChangefeedOptions) getChangefeedInfo(ctx context.Context) (
 	*model.ChangefeedDetail, error,
 ) {
-	detail, err := o.apiV1Client.Changefeeds().Get(ctx, o.changefeedID)
+	detail, err := o.apiV1Client.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +210,7 @@ func (o *resumeChangefeedOptions) run(cmd *cobra.Command) error {
 	if err := o.confirmResumeChangefeedCheck(ctx, cmd); err != nil {
 		return err
 	}
-	err := o.apiV2Client.Changefeeds().Resume(ctx, cfg, o.changefeedID)
+	err := o.apiV2Client.Changefeeds().Resume(ctx, cfg, o.namespace, o.changefeedID)
 
 	return err
 }
@@ -36,7 +36,7 @@ func TestChangefeedResumeCli(t *testing.T) {
 	cmd := newCmdResumeChangefeed(f)
 
 	// 1. test changefeed resume with non-nil changefeed get result, non-nil tso get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -48,17 +48,17 @@ func TestChangefeedResumeCli(t *testing.T) {
 	}, nil).AnyTimes()
 	f.changefeedsv2.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: 0,
-	}, "abc").Return(nil)
+	}, "default", "abc").Return(nil)
 	os.Args = []string{"resume", "--no-confirm=true", "--changefeed-id=abc"}
 	require.Nil(t, cmd.Execute())
 
 	// 2. test changefeed resume with nil changfeed get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{}, nil)
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{}, nil)
 	os.Args = []string{"resume", "--no-confirm=false", "--changefeed-id=abc"}
 	require.NotNil(t, cmd.Execute())
 
 	// 3. test changefeed resume with nil tso get result
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -71,7 +71,7 @@ func TestChangefeedResumeCli(t *testing.T) {
 
 	// 4. test changefeed resume with non-nil changefeed result, non-nil tso get result,
 	// and confirmation checking
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -105,7 +105,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	cmd := newCmdResumeChangefeed(f)
 
 	// 1. test changefeed resume with valid overwritten checkpointTs
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -118,7 +118,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(tso, nil).AnyTimes()
 	f.changefeedsv2.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: oracle.ComposeTS(tso.Timestamp, tso.LogicTime),
-	}, "abc").Return(nil)
+	}, "default", "abc").Return(nil)
 	os.Args = []string{
 		"resume", "--no-confirm=true", "--changefeed-id=abc",
 		"--overwrite-checkpoint-ts=now",
@@ -126,7 +126,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.Nil(t, cmd.Execute())
 
 	// 2. test changefeed resume with invalid overwritten checkpointTs
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -141,7 +141,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.NotNil(t, cmd.Execute())
 
 	// 3. test changefeed resume with checkpointTs larger than current tso
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -156,7 +156,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	require.NotNil(t, cmd.Execute())
 
 	// 4. test changefeed resume with checkpointTs smaller than gcSafePoint
-	f.changefeeds.EXPECT().Get(gomock.Any(), "abc").Return(&model.ChangefeedDetail{
+	f.changefeeds.EXPECT().Get(gomock.Any(), "default", "abc").Return(&model.ChangefeedDetail{
 		UpstreamID:     1,
 		Namespace:      "default",
 		ID:             "abc",
@@ -169,7 +169,7 @@ func TestChangefeedResumeWithNewCheckpointTs(t *testing.T) {
 	f.tso.EXPECT().Query(gomock.Any(), gomock.Any()).Return(tso, nil).AnyTimes()
 	f.changefeedsv2.EXPECT().Resume(gomock.Any(), &v2.ResumeChangefeedConfig{
 		OverwriteCheckpointTs: 262144,
-	}, "abc").
+	}, "default", "abc").
 		Return(cerror.ErrStartTsBeforeGC)
 	os.Args = []string{
 		"resume", "--no-confirm=true", "--changefeed-id=abc",
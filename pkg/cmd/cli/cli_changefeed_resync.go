@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/pingcap/tiflow/cdc/model"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// resyncChangefeedOptions defines flags for the `cli changefeed resync` command.
+type resyncChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+	table        string
+	policy       string
+
+	schemaName string
+	tableName  string
+}
+
+// newResyncChangefeedOptions creates new options for the `cli changefeed resync` command.
+func newResyncChangefeedOptions() *resyncChangefeedOptions {
+	return &resyncChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *resyncChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.table, "table", "", "The table to resync, in the format of schema.table")
+	cmd.PersistentFlags().StringVar(&o.policy, "policy", string(model.ResyncPolicyTruncate),
+		"How the rows in the downstream are handled, truncate or overwrite")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("table")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *resyncChangefeedOptions) complete(f factory.Factory) error {
+	parts := strings.SplitN(o.table, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("invalid table %s, the format should be schema.table", o.table)
+	}
+	o.schemaName, o.tableName = parts[0], parts[1]
+	if err := model.ResyncPolicy(o.policy).Validate(); err != nil {
+		return err
+	}
+
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed resync` command.
+func (o *resyncChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	err := o.apiClient.Changefeeds().ResyncTable(ctx, &v2.ResyncTableConfig{
+		SchemaName: o.schemaName,
+		TableName:  o.tableName,
+		Policy:     o.policy,
+	}, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Resync table %s of changefeed %s with policy %s successfully!\n",
+		o.table, o.changefeedID, o.policy)
+	return nil
+}
+
+// newCmdResyncChangefeed creates the `cli changefeed resync` command.
+func newCmdResyncChangefeed(f factory.Factory) *cobra.Command {
+	o := newResyncChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "resync",
+		Short: "Resync a table of a running replication task (changefeed) from a new snapshot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedResyncCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	cmd := newCmdResyncChangefeed(f)
+	cfV2.EXPECT().ResyncTable(gomock.Any(), &v2.ResyncTableConfig{
+		SchemaName: "test",
+		TableName:  "t.1",
+		Policy:     "overwrite",
+	}, "ns", "abc").Return(nil)
+	os.Args = []string{"resync", "-n=ns", "-c=abc", "--table=test.t.1", "--policy=overwrite"}
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "successfully")
+
+	// invalid table
+	cmd = newCmdResyncChangefeed(f)
+	os.Args = []string{"resync", "-c=abc", "--table=t"}
+	require.Regexp(t, ".*schema.table.*", cmd.Execute())
+
+	// invalid policy
+	cmd = newCmdResyncChangefeed(f)
+	os.Args = []string{"resync", "-c=abc", "--table=test.t", "--policy=drop"}
+	require.Regexp(t, ".*invalid resync policy.*", cmd.Execute())
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// rollbackChangefeedOptions defines flags for the `cli changefeed rollback` command.
+type rollbackChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+	revision     uint64
+	reason       string
+}
+
+// newRollbackChangefeedOptions creates new options for the `cli changefeed rollback` command.
+func newRollbackChangefeedOptions() *rollbackChangefeedOptions {
+	return &rollbackChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *rollbackChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().Uint64Var(&o.revision, "revision", 0, "The revision to roll back to")
+	cmd.PersistentFlags().StringVar(&o.reason, "reason", "", "The reason of the rollback, which is recorded in the changefeed history")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("revision")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *rollbackChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed rollback` command.
+func (o *rollbackChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	cfg := &v2.RollbackChangefeedConfig{
+		Revision: o.revision,
+		Reason:   o.reason,
+	}
+	info, err := o.apiClient.Changefeeds().Rollback(ctx, cfg, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	infoStr, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Rollback changefeed config successfully! "+
+		"\nID: %s\nRevision: %d\nInfo: %s\n", o.changefeedID, o.revision, infoStr)
+	return nil
+}
+
+// newCmdRollbackChangefeed creates the `cli changefeed rollback` command.
+func newCmdRollbackChangefeed(f factory.Factory) *cobra.Command {
+	o := newRollbackChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back the config of a paused replication task (changefeed) to a previous revision",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
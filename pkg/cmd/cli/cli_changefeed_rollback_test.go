@@ -0,0 +1,46 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedRollbackCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	cmd := newCmdRollbackChangefeed(f)
+	cfV2.EXPECT().Rollback(gomock.Any(), &v2.RollbackChangefeedConfig{
+		Revision: 2,
+		Reason:   "bad sink",
+	}, "default", "abc").Return(&v2.ChangeFeedInfo{}, nil)
+	os.Args = []string{"rollback", "-c=abc", "--revision=2", "--reason=bad sink"}
+	require.Nil(t, cmd.Execute())
+
+	cmd = newCmdRollbackChangefeed(f)
+	cfV2.EXPECT().Rollback(gomock.Any(), gomock.Any(), "default", "abc").
+		Return(nil, errors.New("changefeed is not stopped"))
+	os.Args = []string{"rollback", "-c=abc", "--revision=2"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// schemaChangefeedOptions defines flags for the `cli changefeed schema` command.
+type schemaChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+	schema       string
+	table        string
+	ts           uint64
+	ddl          bool
+	startTs      uint64
+	endTs        uint64
+}
+
+// newSchemaChangefeedOptions creates new options for the `cli changefeed schema` command.
+func newSchemaChangefeedOptions() *schemaChangefeedOptions {
+	return &schemaChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *schemaChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.schema, "schema", "", "The schema name of the table")
+	cmd.PersistentFlags().StringVar(&o.table, "table", "", "The name of the table")
+	cmd.PersistentFlags().Uint64Var(&o.ts, "ts", 0, "The ts to query the table schema at, default to the checkpoint ts of the changefeed")
+	cmd.PersistentFlags().BoolVar(&o.ddl, "ddl", false, "Output the DDLs applied to the table between --start-ts and --end-ts")
+	cmd.PersistentFlags().Uint64Var(&o.startTs, "start-ts", 0, "The ts to list DDLs from (exclusive), default to the earliest ts of the schema history kept by the owner")
+	cmd.PersistentFlags().Uint64Var(&o.endTs, "end-ts", 0, "The ts to list DDLs to (inclusive), default to the checkpoint ts of the changefeed")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+	_ = cmd.MarkPersistentFlagRequired("schema")
+	_ = cmd.MarkPersistentFlagRequired("table")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *schemaChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed schema` command.
+func (o *schemaChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	if o.ddl || o.startTs != 0 || o.endTs != 0 {
+		events, err := o.apiClient.Changefeeds().TableDDLEvents(ctx,
+			o.namespace, o.changefeedID, o.schema, o.table, o.startTs, o.endTs)
+		if err != nil {
+			return err
+		}
+		return util.JSONPrint(cmd, events)
+	}
+
+	schema, err := o.apiClient.Changefeeds().TableSchema(ctx,
+		o.namespace, o.changefeedID, o.schema, o.table, o.ts)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, schema)
+}
+
+// newCmdSchemaChangefeed creates the `cli changefeed schema` command.
+func newCmdSchemaChangefeed(f factory.Factory) *cobra.Command {
+	o := newSchemaChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "schema",
+		Short: "Query the schema of a table or the DDLs applied to it in the schema storage of a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
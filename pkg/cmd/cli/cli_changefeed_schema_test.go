@@ -0,0 +1,72 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedSchemaCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	// query the table schema
+	cmd := newCmdSchemaChangefeed(f)
+	cfV2.EXPECT().TableSchema(gomock.Any(), "ns", "abc", "test", "t", uint64(10)).
+		Return(&v2.TableSchema{
+			SchemaName:       "test",
+			TableName:        "t",
+			Columns:          []v2.ColumnSchema{{Name: "id", Type: "int(11)", IsPrimaryKey: true}},
+			HandleKeyColumns: []string{"id"},
+		}, nil)
+	os.Args = []string{"schema", "-n=ns", "-c=abc", "--schema=test", "--table=t", "--ts=10"}
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	schema := &v2.TableSchema{}
+	require.Nil(t, json.Unmarshal(out, schema))
+	require.Equal(t, []string{"id"}, schema.HandleKeyColumns)
+
+	// list the DDLs of the table
+	cmd = newCmdSchemaChangefeed(f)
+	cfV2.EXPECT().TableDDLEvents(gomock.Any(), "default", "abc", "test", "t", uint64(0), uint64(20)).
+		Return(&v2.TableDDLEvents{
+			Events: []v2.TableDDLEvent{{JobID: 1, CommitTs: 15, Type: "add column"}},
+		}, nil)
+	os.Args = []string{"schema", "-c=abc", "--schema=test", "--table=t", "--end-ts=20"}
+	b = bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err = io.ReadAll(b)
+	require.Nil(t, err)
+	require.Contains(t, string(out), "add column")
+
+	// schema and table are required
+	cmd = newCmdSchemaChangefeed(f)
+	os.Args = []string{"schema", "-c=abc", "--schema=test"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// snapshotChangefeedOptions defines flags for the `cli changefeed snapshot` command.
+type snapshotChangefeedOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace    string
+	changefeedID string
+}
+
+// newSnapshotChangefeedOptions creates new options for the `cli changefeed snapshot` command.
+func newSnapshotChangefeedOptions() *snapshotChangefeedOptions {
+	return &snapshotChangefeedOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *snapshotChangefeedOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
+	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *snapshotChangefeedOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli changefeed snapshot` command.
+func (o *snapshotChangefeedOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+	progress, err := o.apiClient.Changefeeds().
+		SnapshotProgress(ctx, o.namespace, o.changefeedID)
+	if err != nil {
+		return err
+	}
+	return util.JSONPrint(cmd, progress)
+}
+
+// newCmdSnapshotChangefeed creates the `cli changefeed snapshot` command.
+func newCmdSnapshotChangefeed(f factory.Factory) *cobra.Command {
+	o := newSnapshotChangefeedOptions()
+
+	command := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Query the initial snapshot progress of the tables in a replication task (changefeed)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
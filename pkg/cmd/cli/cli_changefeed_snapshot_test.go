@@ -0,0 +1,60 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	mock_v2 "github.com/pingcap/tiflow/pkg/api/v2/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangefeedSnapshotCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cfV2 := mock_v2.NewMockChangefeedInterface(ctrl)
+	f := &mockFactory{changefeedsv2: cfV2}
+
+	cmd := newCmdSnapshotChangefeed(f)
+	cfV2.EXPECT().SnapshotProgress(gomock.Any(), "ns", "abc").
+		Return(&v2.ChangefeedSnapshotProgress{
+			ID:         "abc",
+			Enabled:    true,
+			SnapshotTs: 10,
+			Tables: []v2.TableSnapshotProgress{
+				{TableID: 1, TableName: "`test`.`t`", Rows: 5, Done: true},
+			},
+		}, nil)
+	os.Args = []string{"snapshot", "-n=ns", "-c=abc"}
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	require.Nil(t, cmd.Execute())
+	out, err := io.ReadAll(b)
+	require.Nil(t, err)
+	progress := &v2.ChangefeedSnapshotProgress{}
+	require.Nil(t, json.Unmarshal(out, progress))
+	require.Len(t, progress.Tables, 1)
+	require.True(t, progress.Tables[0].Done)
+
+	// changefeed id is required
+	cmd = newCmdSnapshotChangefeed(f)
+	os.Args = []string{"snapshot"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -41,6 +41,7 @@ type statisticsChangefeedOptions struct {
 	apiV1Client apiv1client.APIV1Interface
 	apiV2Client apiv2client.APIV2Interface
 
+	namespace    string
 	changefeedID string
 	interval     uint
 }
@@ -54,6 +55,7 @@ func newStatisticsChangefeedOptions() *statisticsChangefeedOptions {
 // flags related to template printing to it.
 func (o *statisticsChangefeedOptions) addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().UintVarP(&o.interval, "interval", "I", 10, "Interval for outputing the latest statistics")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
@@ -82,14 +84,14 @@ func (o *statisticsChangefeedOptions) runCliWithAPIClient(ctx context.Context, c
 	}
 
 	for _, capture := range *captures {
-		processor, err := o.apiV1Client.Processors().Get(ctx, o.changefeedID, capture.ID)
+		processor, err := o.apiV1Client.Processors().Get(ctx, o.namespace, o.changefeedID, capture.ID)
 		if err != nil {
 			return err
 		}
 		count += processor.Count
 	}
 
-	changefeed, err := o.apiV1Client.Changefeeds().Get(ctx, o.changefeedID)
+	changefeed, err := o.apiV1Client.Changefeeds().Get(ctx, o.namespace, o.changefeedID)
 	if err != nil {
 		return err
 	}
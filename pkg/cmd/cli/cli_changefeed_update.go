@@ -34,7 +34,9 @@ type updateChangefeedOptions struct {
 	apiV2Client apiv2client.APIV2Interface
 
 	commonChangefeedOptions *changefeedCommonOptions
+	namespace               string
 	changefeedID            string
+	reason                  string
 }
 
 // newUpdateChangefeedOptions creates new options for the `cli changefeed update` command.
@@ -48,7 +50,9 @@ func newUpdateChangefeedOptions(commonChangefeedOptions *changefeedCommonOptions
 // flags related to template printing to it.
 func (o *updateChangefeedOptions) addFlags(cmd *cobra.Command) {
 	o.commonChangefeedOptions.addFlags(cmd)
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
+	cmd.PersistentFlags().StringVar(&o.reason, "reason", "", "The reason of the update, which is recorded in the changefeed history")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
 }
 
@@ -92,7 +96,7 @@ func (o *updateChangefeedOptions) complete(f factory.Factory) error {
 func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	ctx := cmdcontext.GetDefaultContext()
 
-	old, err := o.apiV2Client.Changefeeds().GetInfo(ctx, o.changefeedID)
+	old, err := o.apiV2Client.Changefeeds().GetInfo(ctx, o.namespace, o.changefeedID)
 	if err != nil {
 		return err
 	}
@@ -129,7 +133,8 @@ func (o *updateChangefeedOptions) run(cmd *cobra.Command) error {
 	}
 
 	changefeedConfig := o.getChangefeedConfig(cmd, newInfo)
-	info, err := o.apiV2Client.Changefeeds().Update(ctx, changefeedConfig, newInfo.ID)
+	info, err := o.apiV2Client.Changefeeds().Update(ctx, changefeedConfig,
+		newInfo.Namespace, newInfo.ID, o.reason)
 	if err != nil {
 		return err
 	}
@@ -174,7 +179,7 @@ func (o *updateChangefeedOptions) applyChanges(oldInfo *v2.ChangeFeedInfo,
 			newInfo.SyncPointInterval = o.commonChangefeedOptions.syncPointInterval
 		case "sort-dir":
 			log.Warn("this flag cannot be updated and will be ignored", zap.String("flagName", flag.Name))
-		case "changefeed-id", "no-confirm":
+		case "changefeed-id", "namespace", "no-confirm", "reason":
 			// Do nothing, these are some flags from the changefeed command,
 			// we don't use it to update, but we do use these flags.
 		case "interact":
@@ -103,18 +103,18 @@ func TestChangefeedUpdateCli(t *testing.T) {
 	f := newMockFactory(ctrl)
 
 	cmd := newCmdUpdateChangefeed(f)
-	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "abc").Return(nil, errors.New("test"))
+	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "default", "abc").Return(nil, errors.New("test"))
 	os.Args = []string{"update", "--no-confirm=true", "--changefeed-id=abc"}
 	require.NotNil(t, cmd.Execute())
 
-	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "abc").
+	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "default", "abc").
 		Return(&v2.ChangeFeedInfo{
 			ID: "abc",
 			Config: &v2.ReplicaConfig{
 				Sink: &v2.SinkConfig{},
 			},
 		}, nil)
-	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), "abc").
+	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), "abc", gomock.Any()).
 		Return(&v2.ChangeFeedInfo{}, nil)
 	dir := t.TempDir()
 	configPath := filepath.Join(dir, "cf.toml")
@@ -152,15 +152,15 @@ func TestChangefeedUpdateCli(t *testing.T) {
 
 	// no diff
 	cmd = newCmdUpdateChangefeed(f)
-	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "abc").
+	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "default", "abc").
 		Return(&v2.ChangeFeedInfo{}, nil)
 	os.Args = []string{"update", "--no-confirm=true", "-c", "abc"}
 	require.Nil(t, cmd.Execute())
 
 	cmd = newCmdUpdateChangefeed(f)
-	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "abc").
+	f.changefeedsv2.EXPECT().GetInfo(gomock.Any(), "default", "abc").
 		Return(&v2.ChangeFeedInfo{ID: "abc"}, nil)
-	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), "abc").
+	f.changefeedsv2.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), "abc", gomock.Any()).
 		Return(nil, errors.New("test"))
 	os.Args = []string{
 		"update", "--no-confirm=true",
@@ -0,0 +1,98 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// createNamespaceOptions defines flags for the `cli namespace create` command.
+type createNamespaceOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace       string
+	changefeedQuota int
+	memoryQuota     uint64
+}
+
+// newCreateNamespaceOptions creates new options for the `cli namespace create` command.
+func newCreateNamespaceOptions() *createNamespaceOptions {
+	return &createNamespaceOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *createNamespaceOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "", "Namespace name")
+	cmd.PersistentFlags().IntVar(&o.changefeedQuota, "changefeed-quota", 0,
+		"Max number of running changefeeds in the namespace, 0 means unlimited")
+	cmd.PersistentFlags().Uint64Var(&o.memoryQuota, "memory-quota", 0,
+		"Max sum of sink memory quota of running changefeeds in the namespace in bytes, 0 means unlimited")
+	_ = cmd.MarkPersistentFlagRequired("namespace")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *createNamespaceOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli namespace create` command.
+func (o *createNamespaceOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	info, err := o.apiClient.Namespaces().Create(ctx, &v2.NamespaceInfo{
+		Name:            o.namespace,
+		ChangefeedQuota: o.changefeedQuota,
+		MemoryQuota:     o.memoryQuota,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Create namespace successfully!\n")
+	return util.JSONPrint(cmd, info)
+}
+
+// newCmdCreateNamespace creates the `cli namespace create` command.
+func newCmdCreateNamespace(f factory.Factory) *cobra.Command {
+	o := newCreateNamespaceOptions()
+
+	command := &cobra.Command{
+		Use:   "create",
+		Short: "Create a new namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceCreateCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdCreateNamespace(f)
+
+	f.namespaces.EXPECT().Create(gomock.Any(), &v2.NamespaceInfo{
+		Name:            "ns1",
+		ChangefeedQuota: 3,
+		MemoryQuota:     1024,
+	}).Return(&v2.NamespaceInfo{Name: "ns1"}, nil)
+	os.Args = []string{
+		"create", "--namespace=ns1",
+		"--changefeed-quota=3", "--memory-quota=1024",
+	}
+	require.Nil(t, cmd.Execute())
+
+	f.namespaces.EXPECT().Create(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("test"))
+	os.Args = []string{"create", "--namespace=ns1"}
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestNamespaceListAndQueryCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	cmd := newCmdListNamespace(f)
+	f.namespaces.EXPECT().List(gomock.Any()).Return([]v2.NamespaceInfo{
+		{Name: "default"}, {Name: "ns1"},
+	}, nil)
+	os.Args = []string{"list"}
+	require.Nil(t, cmd.Execute())
+	f.namespaces.EXPECT().List(gomock.Any()).Return(nil, errors.New("test"))
+	os.Args = []string{"list"}
+	require.NotNil(t, cmd.Execute())
+
+	cmd = newCmdQueryNamespace(f)
+	f.namespaces.EXPECT().Get(gomock.Any(), "ns1").
+		Return(&v2.NamespaceInfo{Name: "ns1"}, nil)
+	os.Args = []string{"query", "-n", "ns1"}
+	require.Nil(t, cmd.Execute())
+	f.namespaces.EXPECT().Get(gomock.Any(), "ns2").
+		Return(nil, errors.New("test"))
+	os.Args = []string{"query", "-n", "ns2"}
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestNamespaceUpdateCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdUpdateNamespace(f)
+
+	// only the specified quota is changed
+	f.namespaces.EXPECT().Get(gomock.Any(), "ns1").Return(&v2.NamespaceInfo{
+		Name:            "ns1",
+		ChangefeedQuota: 3,
+		MemoryQuota:     1024,
+	}, nil)
+	f.namespaces.EXPECT().Update(gomock.Any(), &v2.NamespaceInfo{
+		Name:            "ns1",
+		ChangefeedQuota: 5,
+		MemoryQuota:     1024,
+	}, "ns1").Return(&v2.NamespaceInfo{Name: "ns1"}, nil)
+	os.Args = []string{"update", "--namespace=ns1", "--changefeed-quota=5"}
+	require.Nil(t, cmd.Execute())
+
+	f.namespaces.EXPECT().Get(gomock.Any(), "ns1").
+		Return(nil, errors.New("test"))
+	os.Args = []string{"update", "--namespace=ns1", "--memory-quota=1"}
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestNamespaceRemoveCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdRemoveNamespace(f)
+
+	f.namespaces.EXPECT().Delete(gomock.Any(), "ns1").Return(nil)
+	os.Args = []string{"remove", "--namespace=ns1"}
+	require.Nil(t, cmd.Execute())
+	f.namespaces.EXPECT().Delete(gomock.Any(), "ns1").
+		Return(errors.New("test"))
+	os.Args = []string{"remove", "--namespace=ns1"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// updateNamespaceOptions defines flags for the `cli namespace update` command.
+type updateNamespaceOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace       string
+	changefeedQuota int
+	memoryQuota     uint64
+}
+
+// newUpdateNamespaceOptions creates new options for the `cli namespace update` command.
+func newUpdateNamespaceOptions() *updateNamespaceOptions {
+	return &updateNamespaceOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *updateNamespaceOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "", "Namespace name")
+	cmd.PersistentFlags().IntVar(&o.changefeedQuota, "changefeed-quota", 0,
+		"Max number of running changefeeds in the namespace, 0 means unlimited")
+	cmd.PersistentFlags().Uint64Var(&o.memoryQuota, "memory-quota", 0,
+		"Max sum of sink memory quota of running changefeeds in the namespace in bytes, 0 means unlimited")
+	_ = cmd.MarkPersistentFlagRequired("namespace")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *updateNamespaceOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli namespace update` command.
+// Only the quotas specified by flags are changed.
+func (o *updateNamespaceOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	info, err := o.apiClient.Namespaces().Get(ctx, o.namespace)
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("changefeed-quota") {
+		info.ChangefeedQuota = o.changefeedQuota
+	}
+	if cmd.Flags().Changed("memory-quota") {
+		info.MemoryQuota = o.memoryQuota
+	}
+
+	info, err = o.apiClient.Namespaces().Update(ctx, info, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Update namespace successfully!\n")
+	return util.JSONPrint(cmd, info)
+}
+
+// newCmdUpdateNamespace creates the `cli namespace update` command.
+func newCmdUpdateNamespace(f factory.Factory) *cobra.Command {
+	o := newUpdateNamespaceOptions()
+
+	command := &cobra.Command{
+		Use:   "update",
+		Short: "Update quotas of a namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
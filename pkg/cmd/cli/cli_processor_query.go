@@ -35,6 +35,7 @@ type queryProcessorOptions struct {
 	etcdClient *etcd.CDCEtcdClient
 	apiClient  apiv1client.APIV1Interface
 
+	namespace        string
 	changefeedID     string
 	captureID        string
 	runWithAPIClient bool
@@ -58,6 +59,7 @@ func (o *queryProcessorOptions) complete(f factory.Factory) error {
 // addFlags receives a *cobra.Command reference and binds
 // flags related to template printing to it.
 func (o *queryProcessorOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default", "Replication task (changefeed) Namespace")
 	cmd.PersistentFlags().StringVarP(&o.changefeedID, "changefeed-id", "c", "", "Replication task (changefeed) ID")
 	cmd.PersistentFlags().StringVarP(&o.captureID, "capture-id", "p", "", "capture ID")
 	_ = cmd.MarkPersistentFlagRequired("changefeed-id")
@@ -66,7 +68,7 @@ func (o *queryProcessorOptions) addFlags(cmd *cobra.Command) {
 
 // run cli cmd with api client
 func (o *queryProcessorOptions) runCliWithAPIClient(ctx context.Context, cmd *cobra.Command) error {
-	processor, err := o.apiClient.Processors().Get(ctx, o.changefeedID, o.captureID)
+	processor, err := o.apiClient.Processors().Get(ctx, o.namespace, o.changefeedID, o.captureID)
 	if err != nil {
 		return err
 	}
@@ -30,13 +30,13 @@ func TestProcessorQueryCli(t *testing.T) {
 
 	cmd := newCmdQueryProcessor(f)
 	os.Args = []string{"query", "-c", "a", "-p", "b"}
-	f.processor.EXPECT().Get(gomock.Any(), "a", "b").
+	f.processor.EXPECT().Get(gomock.Any(), "default", "a", "b").
 		Return(nil, errors.New("test"))
 	require.NotNil(t, cmd.Execute())
 
 	cmd = newCmdQueryProcessor(f)
 	os.Args = []string{"query", "-c", "a", "-p", "b"}
-	f.processor.EXPECT().Get(gomock.Any(), "a", "b").
+	f.processor.EXPECT().Get(gomock.Any(), "default", "a", "b").
 		Return(&model.ProcessorDetail{
 			Tables: []int64{1, 2},
 		}, nil)
@@ -0,0 +1,35 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// newCmdUpstream creates the `cli upstream` command.
+func newCmdUpstream(f factory.Factory) *cobra.Command {
+	cmds := &cobra.Command{
+		Use:   "upstream",
+		Short: "Manage upstream (TiDB clusters changefeeds replicate from)",
+		Args:  cobra.NoArgs,
+	}
+
+	cmds.AddCommand(newCmdCreateUpstream(f))
+	cmds.AddCommand(newCmdListUpstream(f))
+	cmds.AddCommand(newCmdQueryUpstream(f))
+	cmds.AddCommand(newCmdRemoveUpstream(f))
+
+	return cmds
+}
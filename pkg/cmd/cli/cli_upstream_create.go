@@ -0,0 +1,118 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"strings"
+
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// createUpstreamOptions defines flags for the `cli upstream create` command.
+type createUpstreamOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace     string
+	upstreamID    uint64
+	pdAddrs       string
+	caPath        string
+	certPath      string
+	keyPath       string
+	certAllowedCN []string
+}
+
+// newCreateUpstreamOptions creates new options for the `cli upstream create` command.
+func newCreateUpstreamOptions() *createUpstreamOptions {
+	return &createUpstreamOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *createUpstreamOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default",
+		"Namespace the upstream is registered in")
+	cmd.PersistentFlags().Uint64Var(&o.upstreamID, "upstream-id", 0,
+		"Expected cluster ID of the upstream, it is checked against the upstream PD if specified")
+	cmd.PersistentFlags().StringVar(&o.pdAddrs, "upstream-pd", "",
+		"upstream PD address, use ',' to separate multiple PDs")
+	cmd.PersistentFlags().StringVar(&o.caPath, "upstream-ca", "",
+		"CA certificate path for TLS connection to upstream")
+	cmd.PersistentFlags().StringVar(&o.certPath, "upstream-cert", "",
+		"Certificate path for TLS connection to upstream")
+	cmd.PersistentFlags().StringVar(&o.keyPath, "upstream-key", "",
+		"Private key path for TLS connection to upstream")
+	cmd.PersistentFlags().StringSliceVar(&o.certAllowedCN, "upstream-cert-allowed-cn", nil,
+		"Verify the common names of the upstream's certificates")
+	_ = cmd.MarkPersistentFlagRequired("upstream-pd")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *createUpstreamOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli upstream create` command.
+func (o *createUpstreamOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	info, err := o.apiClient.Upstreams().Create(ctx, &v2.UpstreamConfig{
+		ID: o.upstreamID,
+		PDConfig: v2.PDConfig{
+			PDAddrs:       strings.Split(o.pdAddrs, ","),
+			CAPath:        o.caPath,
+			CertPath:      o.certPath,
+			KeyPath:       o.keyPath,
+			CertAllowedCN: o.certAllowedCN,
+		},
+	}, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Create upstream successfully!\n")
+	return util.JSONPrint(cmd, info)
+}
+
+// newCmdCreateUpstream creates the `cli upstream create` command.
+func newCmdCreateUpstream(f factory.Factory) *cobra.Command {
+	o := newCreateUpstreamOptions()
+
+	command := &cobra.Command{
+		Use:   "create",
+		Short: "Register an upstream TiDB cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// listUpstreamOptions defines flags for the `cli upstream list` command.
+type listUpstreamOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace string
+}
+
+// newListUpstreamOptions creates new options for the `cli upstream list` command.
+func newListUpstreamOptions() *listUpstreamOptions {
+	return &listUpstreamOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *listUpstreamOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default",
+		"Namespace the upstreams are registered in")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *listUpstreamOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli upstream list` command.
+func (o *listUpstreamOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	infos, err := o.apiClient.Upstreams().List(ctx, o.namespace)
+	if err != nil {
+		return err
+	}
+
+	return util.JSONPrint(cmd, infos)
+}
+
+// newCmdListUpstream creates the `cli upstream list` command.
+func newCmdListUpstream(f factory.Factory) *cobra.Command {
+	o := newListUpstreamOptions()
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List all registered upstreams in a namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/spf13/cobra"
+)
+
+// queryUpstreamOptions defines flags for the `cli upstream query` command.
+type queryUpstreamOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace  string
+	upstreamID uint64
+}
+
+// newQueryUpstreamOptions creates new options for the `cli upstream query` command.
+func newQueryUpstreamOptions() *queryUpstreamOptions {
+	return &queryUpstreamOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *queryUpstreamOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default",
+		"Namespace the upstream is registered in")
+	cmd.PersistentFlags().Uint64Var(&o.upstreamID, "upstream-id", 0, "Upstream ID")
+	_ = cmd.MarkPersistentFlagRequired("upstream-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *queryUpstreamOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli upstream query` command.
+func (o *queryUpstreamOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	info, err := o.apiClient.Upstreams().Get(ctx, o.namespace, o.upstreamID)
+	if err != nil {
+		return err
+	}
+
+	return util.JSONPrint(cmd, info)
+}
+
+// newCmdQueryUpstream creates the `cli upstream query` command.
+func newCmdQueryUpstream(f factory.Factory) *cobra.Command {
+	o := newQueryUpstreamOptions()
+
+	command := &cobra.Command{
+		Use:   "query",
+		Short: "Query information of a registered upstream",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	apiv2client "github.com/pingcap/tiflow/pkg/api/v2"
+	"github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/factory"
+	"github.com/spf13/cobra"
+)
+
+// removeUpstreamOptions defines flags for the `cli upstream remove` command.
+type removeUpstreamOptions struct {
+	apiClient apiv2client.APIV2Interface
+
+	namespace  string
+	upstreamID uint64
+}
+
+// newRemoveUpstreamOptions creates new options for the `cli upstream remove` command.
+func newRemoveUpstreamOptions() *removeUpstreamOptions {
+	return &removeUpstreamOptions{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *removeUpstreamOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "default",
+		"Namespace the upstream is registered in")
+	cmd.PersistentFlags().Uint64Var(&o.upstreamID, "upstream-id", 0, "Upstream ID")
+	_ = cmd.MarkPersistentFlagRequired("upstream-id")
+}
+
+// complete adapts from the command line args to the data and client required.
+func (o *removeUpstreamOptions) complete(f factory.Factory) error {
+	apiClient, err := f.APIV2Client()
+	if err != nil {
+		return err
+	}
+	o.apiClient = apiClient
+	return nil
+}
+
+// run the `cli upstream remove` command.
+func (o *removeUpstreamOptions) run(cmd *cobra.Command) error {
+	ctx := context.GetDefaultContext()
+
+	err := o.apiClient.Upstreams().Delete(ctx, o.namespace, o.upstreamID)
+	if err != nil {
+		cmd.Printf("Upstream remove failed.\nID: %d\nError: %s\n", o.upstreamID, err.Error())
+		return err
+	}
+
+	cmd.Printf("Upstream remove successfully.\nID: %d\n", o.upstreamID)
+	return nil
+}
+
+// newCmdRemoveUpstream creates the `cli upstream remove` command.
+func newCmdRemoveUpstream(f factory.Factory) *cobra.Command {
+	o := newRemoveUpstreamOptions()
+
+	command := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove an upstream that is not used by any changefeed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.complete(f)
+			if err != nil {
+				return err
+			}
+
+			return o.run(cmd)
+		},
+	}
+
+	o.addFlags(command)
+
+	return command
+}
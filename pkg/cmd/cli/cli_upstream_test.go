@@ -0,0 +1,94 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/errors"
+	v2 "github.com/pingcap/tiflow/cdc/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamCreateCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdCreateUpstream(f)
+
+	f.upstreams.EXPECT().Create(gomock.Any(), &v2.UpstreamConfig{
+		ID: 123,
+		PDConfig: v2.PDConfig{
+			PDAddrs:  []string{"http://127.0.0.1:2379", "http://127.0.0.2:2379"},
+			CAPath:   "ca.pem",
+			CertPath: "cert.pem",
+			KeyPath:  "key.pem",
+		},
+	}, "ns1").Return(&v2.UpstreamConfig{ID: 123}, nil)
+	os.Args = []string{
+		"create", "-n", "ns1", "--upstream-id=123",
+		"--upstream-pd=http://127.0.0.1:2379,http://127.0.0.2:2379",
+		"--upstream-ca=ca.pem", "--upstream-cert=cert.pem", "--upstream-key=key.pem",
+	}
+	require.Nil(t, cmd.Execute())
+
+	cmd = newCmdCreateUpstream(f)
+	f.upstreams.EXPECT().Create(gomock.Any(), gomock.Any(), "default").
+		Return(nil, errors.New("test"))
+	os.Args = []string{"create", "--upstream-pd=http://127.0.0.1:2379"}
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestUpstreamListAndQueryCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+
+	cmd := newCmdListUpstream(f)
+	f.upstreams.EXPECT().List(gomock.Any(), "default").Return([]v2.UpstreamConfig{
+		{ID: 1}, {ID: 2},
+	}, nil)
+	os.Args = []string{"list"}
+	require.Nil(t, cmd.Execute())
+	f.upstreams.EXPECT().List(gomock.Any(), "ns1").Return(nil, errors.New("test"))
+	os.Args = []string{"list", "-n", "ns1"}
+	require.NotNil(t, cmd.Execute())
+
+	cmd = newCmdQueryUpstream(f)
+	f.upstreams.EXPECT().Get(gomock.Any(), "default", uint64(1)).
+		Return(&v2.UpstreamConfig{ID: 1}, nil)
+	os.Args = []string{"query", "--upstream-id=1"}
+	require.Nil(t, cmd.Execute())
+	f.upstreams.EXPECT().Get(gomock.Any(), "default", uint64(2)).
+		Return(nil, errors.New("test"))
+	os.Args = []string{"query", "--upstream-id=2"}
+	require.NotNil(t, cmd.Execute())
+}
+
+func TestUpstreamRemoveCli(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newMockFactory(ctrl)
+	cmd := newCmdRemoveUpstream(f)
+
+	f.upstreams.EXPECT().Delete(gomock.Any(), "default", uint64(1)).Return(nil)
+	os.Args = []string{"remove", "--upstream-id=1"}
+	require.Nil(t, cmd.Execute())
+	f.upstreams.EXPECT().Delete(gomock.Any(), "default", uint64(1)).
+		Return(errors.New("test"))
+	os.Args = []string{"remove", "--upstream-id=1"}
+	require.NotNil(t, cmd.Execute())
+}
@@ -17,8 +17,10 @@ import (
 	"os"
 
 	"github.com/pingcap/tiflow/pkg/cmd/cli"
+	"github.com/pingcap/tiflow/pkg/cmd/consumer"
 	"github.com/pingcap/tiflow/pkg/cmd/redo"
 	"github.com/pingcap/tiflow/pkg/cmd/server"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
 	"github.com/pingcap/tiflow/pkg/cmd/version"
 	"github.com/spf13/cobra"
 )
@@ -46,9 +48,10 @@ func Run() {
 	cmd.AddCommand(cli.NewCmdCli())
 	cmd.AddCommand(version.NewCmdVersion())
 	cmd.AddCommand(redo.NewCmdRedo())
+	cmd.AddCommand(consumer.NewCmdConsumer())
 
 	if err := cmd.Execute(); err != nil {
 		cmd.PrintErrln(err)
-		os.Exit(1)
+		os.Exit(util.GetExitCode(err))
 	}
 }
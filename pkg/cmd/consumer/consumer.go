@@ -0,0 +1,203 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	sinkmetrics "github.com/pingcap/tiflow/cdc/sink/metrics"
+	cmdcontext "github.com/pingcap/tiflow/pkg/cmd/context"
+	"github.com/pingcap/tiflow/pkg/cmd/util"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/consumer"
+	"github.com/pingcap/tiflow/pkg/filter"
+	"github.com/pingcap/tiflow/pkg/logutil"
+	"github.com/pingcap/tiflow/pkg/security"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// options defines flags for the `consumer` command.
+type options struct {
+	upstreamURI   string
+	downstreamURI string
+	configFile    string
+	timezone      string
+	metricsAddr   string
+	// memoryCheckpoint allows to keep the checkpoints in memory
+	// if the downstream is not MySQL compatible.
+	memoryCheckpoint bool
+
+	logFile  string
+	logLevel string
+
+	ca, cert, key string
+
+	cfg *consumer.Config
+}
+
+// newOptions creates new options for the `consumer` command.
+func newOptions() *options {
+	return &options{}
+}
+
+// addFlags receives a *cobra.Command reference and binds
+// flags related to template printing to it.
+func (o *options) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.upstreamURI, "upstream-uri", "",
+		"Kafka uri, eg, \"kafka://127.0.0.1:9092/topic1,topic2?protocol=canal-json\", "+
+			"the supported protocols are open-protocol, craft and canal-json")
+	cmd.Flags().StringVar(&o.downstreamURI, "downstream-uri", "", "downstream sink uri")
+	cmd.Flags().StringVar(&o.configFile, "config", "",
+		"config file of the changefeed, used to check the dispatched partitions of the rows")
+	cmd.Flags().StringVar(&o.timezone, "tz", "System", "Specify time zone of the downstream sink")
+	cmd.Flags().StringVar(&o.metricsAddr, "metrics-addr", "",
+		"the address to expose the prometheus metrics, eg, \"127.0.0.1:8400\", disabled if empty")
+	cmd.Flags().BoolVar(&o.memoryCheckpoint, "memory-checkpoint", false,
+		"keep the checkpoints in memory if the downstream is not MySQL compatible, "+
+			"the consumer restarts from the offsets of the consumer group then")
+	cmd.Flags().StringVar(&o.logFile, "log-file", "", "log file path")
+	cmd.Flags().StringVar(&o.logLevel, "log-level", "info", "log level (etc: debug|info|warn|error)")
+	cmd.Flags().StringVar(&o.ca, "ca", "", "CA certificate path for Kafka SSL connection")
+	cmd.Flags().StringVar(&o.cert, "cert", "", "Certificate path for Kafka SSL connection")
+	cmd.Flags().StringVar(&o.key, "key", "", "Private key path for Kafka SSL connection")
+	// the possible error returned from MarkFlagRequired is `no such flag`
+	cmd.MarkFlagRequired("upstream-uri")   //nolint:errcheck
+	cmd.MarkFlagRequired("downstream-uri") //nolint:errcheck
+}
+
+// complete builds the consumer config from the flags.
+func (o *options) complete() error {
+	cfg, err := consumer.NewConfig(o.upstreamURI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg.SinkURI = o.downstreamURI
+	cfg.Timezone = o.timezone
+	cfg.Credential = &security.Credential{
+		CAPath:   o.ca,
+		CertPath: o.cert,
+		KeyPath:  o.key,
+	}
+	if o.configFile != "" {
+		replicaConfig := config.GetDefaultReplicaConfig()
+		replicaConfig.Sink.Protocol = cfg.Protocol.String()
+		err := util.StrictDecodeFile(o.configFile, "consumer", replicaConfig)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := filter.VerifyTableRules(replicaConfig.Filter); err != nil {
+			return errors.Trace(err)
+		}
+		cfg.ReplicaConfig = replicaConfig
+	}
+	o.cfg = cfg
+	return nil
+}
+
+// validate checks that the provided options are valid.
+func (o *options) validate() error {
+	if o.downstreamURI == "" {
+		return errors.New("empty downstream-uri")
+	}
+	if strings.HasPrefix(strings.ToLower(o.downstreamURI), "kafka") {
+		return errors.New("the downstream of the consumer can not be kafka")
+	}
+	if o.metricsAddr != "" {
+		if _, _, err := net.SplitHostPort(o.metricsAddr); err != nil {
+			return errors.Annotate(err, "invalid metrics-addr")
+		}
+	}
+	return nil
+}
+
+// serveMetrics exposes the prometheus metrics of the consumer and the sinks.
+func (o *options) serveMetrics() {
+	if o.metricsAddr == "" {
+		return
+	}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGoCollector())
+	consumer.InitMetrics(registry)
+	sinkmetrics.InitMetrics(registry)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	go func() {
+		log.Info("serve consumer metrics", zap.String("addr", o.metricsAddr))
+		if err := http.ListenAndServe(o.metricsAddr, mux); err != nil {
+			log.Warn("serve consumer metrics failed", zap.Error(err))
+		}
+	}()
+}
+
+// run runs the `consumer` command.
+func (o *options) run(cmd *cobra.Command) error {
+	cancel := util.InitCmd(cmd, &logutil.Config{File: o.logFile, Level: o.logLevel})
+	defer cancel()
+	util.InitSignalHandling(func() <-chan struct{} {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}, cancel)
+	o.serveMetrics()
+
+	ctx := cmdcontext.GetDefaultContext()
+	store, err := consumer.NewCheckpointStore(
+		ctx, o.cfg.SinkURI, o.cfg.GroupID, o.memoryCheckpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c, err := consumer.New(o.cfg, store, consumer.NewSinkFactory(o.cfg.SinkURI))
+	if err != nil {
+		_ = store.Close()
+		return errors.Trace(err)
+	}
+	defer c.Close(ctx)
+
+	err = c.Run(ctx)
+	if errors.Cause(err) == context.Canceled {
+		cmd.Println("Consumer exited")
+		return nil
+	}
+	return err
+}
+
+// NewCmdConsumer creates the `consumer` command.
+func NewCmdConsumer() *cobra.Command {
+	o := newOptions()
+
+	command := &cobra.Command{
+		Use:   "consumer",
+		Short: "Consume the messages of TiCDC from Kafka and write them to the downstream",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.complete(); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run(cmd)
+		},
+	}
+	o.addFlags(command)
+
+	return command
+}
@@ -44,6 +44,9 @@ type options struct {
 	serverConfig         *config.ServerConfig
 	serverPdAddr         string
 	serverConfigFilePath string
+	// configLoader loads the config file and the flags again, it is set
+	// after the options are completed.
+	configLoader func() (*config.ServerConfig, error)
 
 	// TODO(hi-rustin): Consider using a client construction factory here.
 	caPath        string
@@ -128,6 +131,12 @@ func (o *options) run(cmd *cobra.Command) error {
 	}
 
 	config.StoreGlobalServerConfig(o.serverConfig)
+	config.SetServerConfigLoader(o.configLoader)
+	config.OnServerConfigReloaded(func(cfg *config.ServerConfig) {
+		if err := logutil.SetLogLevel(cfg.LogLevel); err != nil {
+			log.Warn("fail to set log level", zap.String("level", cfg.LogLevel), zap.Error(err))
+		}
+	})
 	ctx := contextutil.PutTimezoneInCtx(cmdcontext.GetDefaultContext(), tz)
 	ctx = contextutil.PutCaptureAddrInCtx(ctx, o.serverConfig.AdvertiseAddr)
 
@@ -151,6 +160,11 @@ func (o *options) run(cmd *cobra.Command) error {
 	// Drain the server before shutdown.
 	shutdownNotify := func() <-chan struct{} { return server.Drain(ctx) }
 	util.InitSignalHandling(shutdownNotify, cancel)
+	util.InitReloadSignalHandling(ctx, func() {
+		if _, err := config.ReloadGlobalServerConfig(); err != nil {
+			log.Warn("fail to reload server config", zap.Error(err))
+		}
+	})
 
 	// Run TiCDC server.
 	err = server.Run(ctx)
@@ -169,12 +183,38 @@ func (o *options) run(cmd *cobra.Command) error {
 func (o *options) complete(cmd *cobra.Command) error {
 	o.serverConfig.Security = o.getCredential()
 
+	// Keep the config bound to the flags, it is used to load the config again
+	// when the config is reloaded.
+	flagConfig := o.serverConfig
+	cfg, err := o.loadServerConfig(cmd, flagConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if cfg.DataDir == "" {
+		cmd.Printf(color.HiYellowString("[WARN] TiCDC server data-dir is not set. " +
+			"Please use `cdc server --data-dir` to start the cdc server if possible.\n"))
+	}
+
+	o.serverConfig = cfg
+	o.configLoader = func() (*config.ServerConfig, error) {
+		return o.loadServerConfig(cmd, flagConfig)
+	}
+
+	return nil
+}
+
+// loadServerConfig loads the config file, and overrides it with the values
+// of the flags specified in the command line.
+func (o *options) loadServerConfig(
+	cmd *cobra.Command, flagConfig *config.ServerConfig,
+) (*config.ServerConfig, error) {
 	cfg := config.GetDefaultServerConfig()
 
 	if len(o.serverConfigFilePath) > 0 {
 		// strict decode config file, but ignore debug item
 		if err := util.StrictDecodeFile(o.serverConfigFilePath, "TiCDC server", cfg, config.DebugConfigurationItem); err != nil {
-			return err
+			return nil, err
 		}
 
 		// User specified sort-dir should not take effect, it's always `/tmp/sorter`
@@ -190,51 +230,51 @@ func (o *options) complete(cmd *cobra.Command) error {
 	cmd.Flags().Visit(func(flag *pflag.Flag) {
 		switch flag.Name {
 		case "addr":
-			cfg.Addr = o.serverConfig.Addr
+			cfg.Addr = flagConfig.Addr
 		case "advertise-addr":
-			cfg.AdvertiseAddr = o.serverConfig.AdvertiseAddr
+			cfg.AdvertiseAddr = flagConfig.AdvertiseAddr
 		case "tz":
-			cfg.TZ = o.serverConfig.TZ
+			cfg.TZ = flagConfig.TZ
 		case "gc-ttl":
-			cfg.GcTTL = o.serverConfig.GcTTL
+			cfg.GcTTL = flagConfig.GcTTL
 		case "log-file":
-			cfg.LogFile = o.serverConfig.LogFile
+			cfg.LogFile = flagConfig.LogFile
 		case "log-level":
-			cfg.LogLevel = o.serverConfig.LogLevel
+			cfg.LogLevel = flagConfig.LogLevel
 		case "data-dir":
-			cfg.DataDir = o.serverConfig.DataDir
+			cfg.DataDir = flagConfig.DataDir
 		case "owner-flush-interval":
-			cfg.OwnerFlushInterval = o.serverConfig.OwnerFlushInterval
+			cfg.OwnerFlushInterval = flagConfig.OwnerFlushInterval
 		case "processor-flush-interval":
-			cfg.ProcessorFlushInterval = o.serverConfig.ProcessorFlushInterval
+			cfg.ProcessorFlushInterval = flagConfig.ProcessorFlushInterval
 		case "sorter-num-workerpool-goroutine":
-			cfg.Sorter.NumWorkerPoolGoroutine = o.serverConfig.Sorter.NumWorkerPoolGoroutine
+			cfg.Sorter.NumWorkerPoolGoroutine = flagConfig.Sorter.NumWorkerPoolGoroutine
 		case "sorter-num-concurrent-worker":
-			cfg.Sorter.NumConcurrentWorker = o.serverConfig.Sorter.NumConcurrentWorker
+			cfg.Sorter.NumConcurrentWorker = flagConfig.Sorter.NumConcurrentWorker
 		case "sorter-chunk-size-limit":
-			cfg.Sorter.ChunkSizeLimit = o.serverConfig.Sorter.ChunkSizeLimit
+			cfg.Sorter.ChunkSizeLimit = flagConfig.Sorter.ChunkSizeLimit
 		case "sorter-max-memory-percentage":
-			cfg.Sorter.MaxMemoryPercentage = o.serverConfig.Sorter.MaxMemoryPercentage
+			cfg.Sorter.MaxMemoryPercentage = flagConfig.Sorter.MaxMemoryPercentage
 		case "sorter-max-memory-consumption":
-			cfg.Sorter.MaxMemoryConsumption = o.serverConfig.Sorter.MaxMemoryConsumption
+			cfg.Sorter.MaxMemoryConsumption = flagConfig.Sorter.MaxMemoryConsumption
 		case "ca":
-			cfg.Security.CAPath = o.serverConfig.Security.CAPath
+			cfg.Security.CAPath = flagConfig.Security.CAPath
 		case "cert":
-			cfg.Security.CertPath = o.serverConfig.Security.CertPath
+			cfg.Security.CertPath = flagConfig.Security.CertPath
 		case "key":
-			cfg.Security.KeyPath = o.serverConfig.Security.KeyPath
+			cfg.Security.KeyPath = flagConfig.Security.KeyPath
 		case "cert-allowed-cn":
-			cfg.Security.CertAllowedCN = o.serverConfig.Security.CertAllowedCN
+			cfg.Security.CertAllowedCN = flagConfig.Security.CertAllowedCN
 		case "sort-dir":
 			// user specified sorter dir should not take effect, it's always `/tmp/sorter`
 			// if user try to set sort-dir by flag, warn it.
-			if o.serverConfig.Sorter.SortDir != config.DefaultSortDir {
+			if flagConfig.Sorter.SortDir != config.DefaultSortDir {
 				cmd.Printf(color.HiYellowString("[WARN] --sort-dir is deprecated in server settings. " +
 					"sort-dir will be set to `{data-dir}/tmp/sorter`. The sort-dir here will be no-op\n"))
 			}
 			cfg.Sorter.SortDir = config.DefaultSortDir
 		case "cluster-id":
-			cfg.ClusterID = o.serverConfig.ClusterID
+			cfg.ClusterID = flagConfig.ClusterID
 		case "pd", "config":
 			// do nothing
 		default:
@@ -243,17 +283,9 @@ func (o *options) complete(cmd *cobra.Command) error {
 	})
 
 	if err := cfg.ValidateAndAdjust(); err != nil {
-		return errors.Trace(err)
-	}
-
-	if cfg.DataDir == "" {
-		cmd.Printf(color.HiYellowString("[WARN] TiCDC server data-dir is not set. " +
-			"Please use `cdc server --data-dir` to start the cdc server if possible.\n"))
+		return nil, errors.Trace(err)
 	}
-
-	o.serverConfig = cfg
-
-	return nil
+	return cfg, nil
 }
 
 // validate checks that the provided attach options are specified.
@@ -162,6 +162,7 @@ func TestParseCfg(t *testing.T) {
 			MaxMemoryConsumption:   60000,
 			NumWorkerPoolGoroutine: 90,
 			SortDir:                config.DefaultSortDir,
+			DiskQuotaPolicy:        config.DiskQuotaPolicyBackpressure,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
@@ -175,6 +176,13 @@ func TestParseCfg(t *testing.T) {
 			RegionScanLimit:     40,
 			RegionRetryDuration: config.TomlDuration(time.Minute),
 		},
+		Tracing: &config.TracingConfig{
+			Exporter: config.TracingExporterFile,
+		},
+		Notification: &config.NotificationConfig{
+			QueueSize: 1024,
+			Timeout:   config.TomlDuration(10 * time.Second),
+		},
 		Debug: &config.DebugConfig{
 			TableActor: &config.TableActorConfig{
 				EventBatchSize: 32,
@@ -254,6 +262,16 @@ sort-dir = "/tmp/just_a_test"
 [kv-client]
 region-retry-duration = "3s"
 
+[tracing]
+sample-ratio = 0.01
+file-path = "/tmp/spans.json"
+
+[notification]
+queue-size = 64
+[[notification.webhooks]]
+url = "https://example.com/ticdc"
+secret = "s3cret"
+
 [debug]
 enable-db-sorter = false
 enable-scheduler-v3 = true
@@ -324,6 +342,7 @@ check-balance-interval = "10s"
 			MaxMemoryConsumption:   2000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			DiskQuotaPolicy:        config.DiskQuotaPolicyBackpressure,
 		},
 		Security:            &config.SecurityConfig{},
 		PerTableMemoryQuota: config.DefaultTableMemoryQuota,
@@ -333,6 +352,19 @@ check-balance-interval = "10s"
 			RegionScanLimit:     40,
 			RegionRetryDuration: config.TomlDuration(3 * time.Second),
 		},
+		Tracing: &config.TracingConfig{
+			SampleRatio: 0.01,
+			Exporter:    config.TracingExporterFile,
+			FilePath:    "/tmp/spans.json",
+		},
+		Notification: &config.NotificationConfig{
+			Webhooks: []*config.WebhookConfig{{
+				URL:    "https://example.com/ticdc",
+				Secret: "s3cret",
+			}},
+			QueueSize: 64,
+			Timeout:   config.TomlDuration(10 * time.Second),
+		},
 		Debug: &config.DebugConfig{
 			TableActor: &config.TableActorConfig{
 				EventBatchSize: 32,
@@ -468,6 +500,7 @@ cert-allowed-cn = ["dd","ee"]
 			MaxMemoryConsumption:   60000000,
 			NumWorkerPoolGoroutine: 5,
 			SortDir:                config.DefaultSortDir,
+			DiskQuotaPolicy:        config.DiskQuotaPolicyBackpressure,
 		},
 		Security: &config.SecurityConfig{
 			CertPath:      "bb",
@@ -481,6 +514,13 @@ cert-allowed-cn = ["dd","ee"]
 			RegionScanLimit:     40,
 			RegionRetryDuration: config.TomlDuration(time.Minute),
 		},
+		Tracing: &config.TracingConfig{
+			Exporter: config.TracingExporterFile,
+		},
+		Notification: &config.NotificationConfig{
+			QueueSize: 1024,
+			Timeout:   config.TomlDuration(10 * time.Second),
+		},
 		Debug: &config.DebugConfig{
 			TableActor: &config.TableActorConfig{
 				EventBatchSize: 32,
@@ -588,3 +628,54 @@ unknown3 = 3
 		},
 	}, o.serverConfig.Debug)
 }
+
+func TestReloadCfg(t *testing.T) {
+	dataDir := t.TempDir()
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "ticdc.toml")
+	configContent := `
+log-level = "warn"
+gc-ttl = 500
+
+[sorter]
+max-memory-percentage = 3
+`
+	err := os.WriteFile(configPath, []byte(configContent), 0o644)
+	require.Nil(t, err)
+
+	cmd := new(cobra.Command)
+	o := newOptions()
+	o.addFlags(cmd)
+	require.Nil(t, cmd.ParseFlags([]string{
+		"--data-dir", dataDir,
+		"--gc-ttl", "10",
+		"--config", configPath,
+	}))
+	err = o.complete(cmd)
+	require.Nil(t, err)
+	require.Equal(t, "warn", o.serverConfig.LogLevel)
+	require.Equal(t, int64(10), o.serverConfig.GcTTL)
+	require.Equal(t, 3, o.serverConfig.Sorter.MaxMemoryPercentage)
+
+	configContent = `
+log-level = "debug"
+gc-ttl = 600
+
+[sorter]
+max-memory-percentage = 30
+`
+	err = os.WriteFile(configPath, []byte(configContent), 0o644)
+	require.Nil(t, err)
+	cfg, err := o.configLoader()
+	require.Nil(t, err)
+	require.Equal(t, "debug", cfg.LogLevel)
+	require.Equal(t, 30, cfg.Sorter.MaxMemoryPercentage)
+	// The flags still take precedence over the config file.
+	require.Equal(t, int64(10), cfg.GcTTL)
+	require.Equal(t, dataDir, cfg.DataDir)
+
+	err = os.WriteFile(configPath, []byte(`unknown-item = 1`), 0o644)
+	require.Nil(t, err)
+	_, err = o.configLoader()
+	require.Regexp(t, "contained unknown configuration options", err)
+}
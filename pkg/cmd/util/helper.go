@@ -60,6 +60,8 @@ func InitCmd(cmd *cobra.Command, logCfg *logutil.Config) context.CancelFunc {
 type shutdownNotify func() <-chan struct{}
 
 // InitSignalHandling initializes signal handling.
+// SIGHUP is not handled here, it is used to reload the configuration,
+// see InitReloadSignalHandling.
 // It must be called after InitCmd.
 func InitSignalHandling(shutdown shutdownNotify, cancel context.CancelFunc) {
 	// systemd and k8s send signals twice. The first is for graceful shutdown,
@@ -68,7 +70,6 @@ func InitSignalHandling(shutdown shutdownNotify, cancel context.CancelFunc) {
 	signalChanLen := 2
 	sc := make(chan os.Signal, signalChanLen)
 	signal.Notify(sc,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT)
@@ -87,6 +88,26 @@ func InitSignalHandling(shutdown shutdownNotify, cancel context.CancelFunc) {
 	}()
 }
 
+// InitReloadSignalHandling calls reload every time SIGHUP is received,
+// until the context is done.
+func InitReloadSignalHandling(ctx context.Context, reload func()) {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sc)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sc:
+				log.Info("got signal, reload the config", zap.Stringer("signal", sig))
+				reload()
+			}
+		}
+	}()
+}
+
 // LogHTTPProxies logs HTTP proxy relative environment variables.
 func LogHTTPProxies() {
 	fields := findProxyFields()
@@ -182,3 +203,28 @@ func JSONPrint(cmd *cobra.Command, v interface{}) error {
 	cmd.Printf("%s\n", data)
 	return nil
 }
+
+// ExitCodeError is returned by a command which needs to exit with
+// a specific code, e.g. to report check results to CI.
+type ExitCodeError struct {
+	Code int
+	Msg  string
+}
+
+// NewExitCodeError returns an ExitCodeError.
+func NewExitCodeError(code int, msg string) *ExitCodeError {
+	return &ExitCodeError{Code: code, Msg: msg}
+}
+
+// Error implements the error interface.
+func (e *ExitCodeError) Error() string {
+	return e.Msg
+}
+
+// GetExitCode returns the code which the process should exit with for err.
+func GetExitCode(err error) int {
+	if exitErr, ok := errors.Cause(err).(*ExitCodeError); ok {
+		return exitErr.Code
+	}
+	return 1
+}
@@ -15,6 +15,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -194,7 +196,30 @@ func TestAndWriteExampleReplicaTOML(t *testing.T) {
 			{Matcher: []string{"test3.*", "test4.*"}, Columns: []string{"!a", "column3"}},
 		},
 		Protocol: "open-protocol",
+		TypeRendering: &config.TypeRenderingConfig{
+			Decimal:         config.DecimalRenderingString,
+			TimestampZone:   config.TimestampZoneUTC,
+			TimestampFormat: config.TimestampFormatISO,
+			Binary:          config.BinaryRenderingBase64,
+			Enum:            config.EnumRenderingValue,
+		},
 	}, cfg.Sink)
+	require.Equal(t, &config.RetryConfig{
+		MaxRetryDuration:    config.TomlDuration(2 * time.Hour),
+		BackoffInitInterval: config.TomlDuration(10 * time.Second),
+		BackoffMaxInterval:  config.TomlDuration(30 * time.Minute),
+		AutoSkipDDLErrors:   []string{"Error 1050"},
+	}, cfg.Retry)
+	require.Equal(t, config.ChangefeedPriorityNormal, cfg.Priority)
+	require.False(t, cfg.EnableRowChecksum)
+	require.Equal(t, &config.RateLimitConfig{
+		MaxRowsPerSecond:  100000,
+		MaxBytesPerSecond: 64 * 1024 * 1024,
+		Tables: []*config.TableRateLimit{
+			{Matcher: []string{"test1.*"}, MaxRowsPerSecond: 1000},
+		},
+	}, cfg.RateLimit)
+	require.Nil(t, cfg.ValidateAndAdjust(nil))
 }
 
 func TestAndWriteExampleServerTOML(t *testing.T) {
@@ -328,3 +353,28 @@ func TestInitSignalHandlingForceShutdown(t *testing.T) {
 		require.Fail(t, "timeout")
 	}
 }
+
+func TestGetExitCode(t *testing.T) {
+	require.Equal(t, 1, GetExitCode(fmt.Errorf("test")))
+	require.Equal(t, 2, GetExitCode(NewExitCodeError(2, "test")))
+	require.Equal(t, 3, GetExitCode(errors.Trace(NewExitCodeError(3, "test"))))
+}
+
+func TestInitReloadSignalHandling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloadCh := make(chan struct{}, 1)
+	InitReloadSignalHandling(ctx, func() { reloadCh <- struct{}{} })
+	self, err := os.FindProcess(os.Getpid())
+	require.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = self.Signal(syscall.SIGHUP)
+		require.Nil(t, err)
+		select {
+		case <-reloadCh:
+		case <-time.After(1 * time.Second):
+			require.Fail(t, "timeout")
+		}
+	}
+}
@@ -54,6 +54,12 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": ""
+  },
+  "retry": {
+    "max-retry-duration": 0,
+    "backoff-init-interval": 10000000000,
+    "backoff-max-interval": 1800000000000,
+    "auto-skip-ddl-errors": null
   }
 }`
 
@@ -82,7 +88,10 @@ const (
     "max-memory-percentage": 30,
     "max-memory-consumption": 17179869184,
     "num-workerpool-goroutine": 16,
-    "sort-dir": "/tmp/sorter"
+    "sort-dir": "/tmp/sorter",
+    "extra-sort-dirs": null,
+    "disk-quota": 0,
+    "disk-quota-policy": "backpressure"
   },
   "security": {
     "ca-path": "",
@@ -97,6 +106,16 @@ const (
     "region-scan-limit": 40,
     "region-retry-duration": 60000000000
   },
+  "tracing": {
+    "sample-ratio": 0,
+    "exporter": "file",
+    "file-path": ""
+  },
+  "notification": {
+    "webhooks": null,
+    "queue-size": 1024,
+    "timeout": 10000000000
+  },
   "debug": {
     "table-actor": {
       "event-batch-size": 32
@@ -134,7 +153,8 @@ const (
       "heartbeat-tick": 2,
       "max-task-concurrency": 10,
       "check-balance-interval": 60000000000
-    }
+    },
+    "enable-new-sink": false
   },
   "cluster-id": "default"
 }`
@@ -176,6 +196,14 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": ""
+  },
+  "memory-quota": 0,
+  "initial-snapshot": false,
+  "retry": {
+    "max-retry-duration": 0,
+    "backoff-init-interval": 10000000000,
+    "backoff-max-interval": 1800000000000,
+    "auto-skip-ddl-errors": null
   }
 }`
 
@@ -213,6 +241,12 @@ const (
     "max-log-size": 64,
     "flush-interval": 2000,
     "storage": ""
+  },
+  "retry": {
+    "max-retry-duration": 0,
+    "backoff-init-interval": 10000000000,
+    "backoff-max-interval": 1800000000000,
+    "auto-skip-ddl-errors": null
   }
 }`
 )
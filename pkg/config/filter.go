@@ -14,9 +14,13 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
+
 	bf "github.com/pingcap/tidb-tools/pkg/binlog-filter"
 	"github.com/pingcap/tidb/parser/model"
 	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 )
 
 // FilterConfig represents filter config for a changefeed
@@ -26,6 +30,7 @@ type FilterConfig struct {
 	IgnoreTxnStartTs []uint64           `toml:"ignore-txn-start-ts" json:"ignore-txn-start-ts"`
 	DDLAllowlist     []model.ActionType `toml:"ddl-allow-list" json:"ddl-allow-list,omitempty"`
 	EventFilters     []*EventFilterRule `toml:"event-filters" json:"event-filters"`
+	DDLRewriteRules  []*DDLRewriteRule  `toml:"ddl-rewrite-rules" json:"ddl-rewrite-rules,omitempty"`
 }
 
 // EventFilterRule is used by sql event filter and expression filter
@@ -40,3 +45,85 @@ type EventFilterRule struct {
 	IgnoreUpdateOldValueExpr string `toml:"ignore-update-old-value-expr" json:"ignore-update-old-value-expr"`
 	IgnoreDeleteValueExpr    string `toml:"ignore-delete-value-expr" json:"ignore-delete-value-expr"`
 }
+
+// DDLRewriteAction is a builtin action to rewrite a DDL
+type DDLRewriteAction string
+
+const (
+	// DDLRewriteStripAutoRandom removes the AUTO_RANDOM attribute of columns.
+	DDLRewriteStripAutoRandom DDLRewriteAction = "strip-auto-random"
+	// DDLRewriteStripShardRowIDBits removes the SHARD_ROW_ID_BITS and
+	// PRE_SPLIT_REGIONS options of tables.
+	DDLRewriteStripShardRowIDBits DDLRewriteAction = "strip-shard-row-id-bits"
+	// DDLRewriteStripPlacementPolicy removes the placement policy options
+	// of tables and databases.
+	DDLRewriteStripPlacementPolicy DDLRewriteAction = "strip-placement-policy"
+	// DDLRewriteMapColumnType replaces the column types by TypeMapping.
+	DDLRewriteMapColumnType DDLRewriteAction = "map-column-type"
+	// DDLRewriteCharset replaces the charset and collation of tables,
+	// columns and databases by Charset and Collation.
+	DDLRewriteCharset DDLRewriteAction = "rewrite-charset"
+	// DDLRewriteTruncateToDelete converts TRUNCATE TABLE into DELETE FROM.
+	DDLRewriteTruncateToDelete DDLRewriteAction = "truncate-to-delete"
+	// DDLRewriteRegexpReplace replaces the matches of Pattern in the query
+	// by Replacement.
+	DDLRewriteRegexpReplace DDLRewriteAction = "regexp-replace"
+)
+
+// DDLRewriteRule is used to rewrite the DDLs of the matched tables before
+// they are sent to the downstream. Rules are applied in order.
+type DDLRewriteRule struct {
+	Matcher []string         `toml:"matcher" json:"matcher"`
+	Action  DDLRewriteAction `toml:"action" json:"action"`
+	// TypeMapping maps a column type name to a new column type,
+	// e.g. "json" -> "longtext", used by map-column-type.
+	TypeMapping map[string]string `toml:"type-mapping" json:"type-mapping,omitempty"`
+	// Charset and Collation are used by rewrite-charset.
+	Charset   string `toml:"charset" json:"charset,omitempty"`
+	Collation string `toml:"collation" json:"collation,omitempty"`
+	// Pattern is a regular expression and Replacement can refer to its
+	// submatches like $1, they are used by regexp-replace.
+	Pattern     string `toml:"pattern" json:"pattern,omitempty"`
+	Replacement string `toml:"replacement" json:"replacement,omitempty"`
+}
+
+func (r *DDLRewriteRule) validate() error {
+	if _, err := filter.Parse(r.Matcher); err != nil {
+		return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, r.Matcher)
+	}
+	switch r.Action {
+	case DDLRewriteStripAutoRandom, DDLRewriteStripShardRowIDBits,
+		DDLRewriteStripPlacementPolicy, DDLRewriteTruncateToDelete:
+	case DDLRewriteMapColumnType:
+		if len(r.TypeMapping) == 0 {
+			return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("type-mapping is required by ddl rewrite action %s", r.Action))
+		}
+	case DDLRewriteCharset:
+		if r.Charset == "" && r.Collation == "" {
+			return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("charset or collation is required by ddl rewrite action %s", r.Action))
+		}
+	case DDLRewriteRegexpReplace:
+		if r.Pattern == "" {
+			return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+				fmt.Sprintf("pattern is required by ddl rewrite action %s", r.Action))
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, r.Pattern)
+		}
+	default:
+		return cerror.ErrFilterRuleInvalid.GenWithStackByArgs(
+			fmt.Sprintf("unknown ddl rewrite action %s", r.Action))
+	}
+	return nil
+}
+
+func (c *FilterConfig) validate() error {
+	for _, rule := range c.DDLRewriteRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
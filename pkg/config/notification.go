@@ -0,0 +1,71 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/url"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// NotificationConfig represents config for the notifications of changefeed
+// state changes, owner changes and capture membership changes.
+type NotificationConfig struct {
+	// Webhooks are the endpoints the events are posted to, the notification
+	// is disabled if it is empty.
+	Webhooks []*WebhookConfig `toml:"webhooks" json:"webhooks"`
+	// QueueSize is the max number of pending events of each webhook,
+	// the oldest event is dropped once it is exceeded.
+	QueueSize int `toml:"queue-size" json:"queue-size"`
+	// Timeout is the timeout of posting an event to a webhook.
+	Timeout TomlDuration `toml:"timeout" json:"timeout"`
+}
+
+// WebhookConfig represents config for a webhook.
+type WebhookConfig struct {
+	URL string `toml:"url" json:"url"`
+	// Secret is the key to sign the events with HMAC-SHA256,
+	// the events are not signed if it is empty.
+	Secret string `toml:"secret" json:"secret"`
+}
+
+// ValidateAndAdjust validates and adjusts the notification configuration
+func (c *NotificationConfig) ValidateAndAdjust() error {
+	if c.QueueSize == 0 {
+		c.QueueSize = defaultServerConfig.Notification.QueueSize
+	}
+	if c.QueueSize < 0 {
+		return cerror.ErrIllegalNotificationParameter.GenWithStackByArgs(
+			"queue-size should be positive")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultServerConfig.Notification.Timeout
+	}
+	if c.Timeout < 0 {
+		return cerror.ErrIllegalNotificationParameter.GenWithStackByArgs(
+			"timeout should be positive")
+	}
+	for _, webhook := range c.Webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrIllegalNotificationParameter, err,
+				"invalid webhook url "+webhook.URL)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cerror.ErrIllegalNotificationParameter.GenWithStackByArgs(
+				"invalid webhook url " + webhook.URL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	filter "github.com/pingcap/tidb/util/table-filter"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// ChangefeedPriorityLow is the priority of the changefeeds which can
+	// lag behind when the capture is busy, such as the ones for analytics.
+	ChangefeedPriorityLow = "low"
+	// ChangefeedPriorityNormal is the default priority of changefeeds.
+	ChangefeedPriorityNormal = "normal"
+	// ChangefeedPriorityHigh is the priority of the changefeeds which should
+	// be served first when the capture is busy.
+	ChangefeedPriorityHigh = "high"
+)
+
+// ChangefeedPriorityWeight returns the weight of the priority, the resources
+// shared by the changefeeds on a capture are allocated by the weights, that is
+// the per-table sink memory quota, the share of the sorter disk quota, the
+// iterators of the db sorter and the messages handled in a poll of the table
+// actors. The processors of the changefeeds with higher weights are also
+// ticked first.
+// An empty priority is treated as normal.
+func ChangefeedPriorityWeight(priority string) uint64 {
+	switch priority {
+	case ChangefeedPriorityLow:
+		return 1
+	case ChangefeedPriorityHigh:
+		return 4
+	default:
+		return 2
+	}
+}
+
+func validateChangefeedPriority(priority string) error {
+	switch priority {
+	case "", ChangefeedPriorityLow, ChangefeedPriorityNormal, ChangefeedPriorityHigh:
+		return nil
+	default:
+		return cerror.ErrInvalidChangefeedPriority.GenWithStackByArgs(priority)
+	}
+}
+
+// RateLimitConfig limits the throughput of the rows sent to the sink by a
+// changefeed on each capture, so a changefeed catching up with a big backlog
+// does not saturate the downstream. The limits are enforced when the rows are
+// read from the sorter of each table. 0 means unlimited.
+type RateLimitConfig struct {
+	MaxRowsPerSecond  uint64 `toml:"max-rows-per-second" json:"max-rows-per-second"`
+	MaxBytesPerSecond uint64 `toml:"max-bytes-per-second" json:"max-bytes-per-second"`
+	// Tables are the limits of the matched tables, which are enforced
+	// together with the changefeed limits. All the tables matched by a
+	// rule share its limits, and only the first matched rule takes effect.
+	Tables []*TableRateLimit `toml:"tables" json:"tables,omitempty"`
+}
+
+// TableRateLimit limits the throughput of the rows of the matched tables.
+type TableRateLimit struct {
+	Matcher           []string `toml:"matcher" json:"matcher"`
+	MaxRowsPerSecond  uint64   `toml:"max-rows-per-second" json:"max-rows-per-second"`
+	MaxBytesPerSecond uint64   `toml:"max-bytes-per-second" json:"max-bytes-per-second"`
+}
+
+func (c *RateLimitConfig) validate() error {
+	for _, rule := range c.Tables {
+		if len(rule.Matcher) == 0 {
+			return cerror.ErrIllegalRateLimitParameter.GenWithStackByArgs(
+				"matcher of the table rate limit can not be empty")
+		}
+		if _, err := filter.Parse(rule.Matcher); err != nil {
+			return cerror.WrapError(cerror.ErrFilterRuleInvalid, err, rule.Matcher)
+		}
+		if rule.MaxRowsPerSecond == 0 && rule.MaxBytesPerSecond == 0 {
+			return cerror.ErrIllegalRateLimitParameter.GenWithStackByArgs(
+				"table rate limit must limit the rows or the bytes")
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns true if any limit is set.
+func (c *RateLimitConfig) IsEnabled() bool {
+	if c == nil {
+		return false
+	}
+	return c.MaxRowsPerSecond > 0 || c.MaxBytesPerSecond > 0 || len(c.Tables) > 0
+}
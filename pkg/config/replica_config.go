@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -43,6 +44,11 @@ var defaultReplicaConfig = &ReplicaConfig{
 		FlushIntervalInMs: 2000,
 		Storage:           "",
 	},
+	Retry: &RetryConfig{
+		MaxRetryDuration:    0,
+		BackoffInitInterval: TomlDuration(10 * time.Second),
+		BackoffMaxInterval:  TomlDuration(30 * time.Minute),
+	},
 }
 
 // ReplicaConfig represents some addition replication config for a changefeed
@@ -57,6 +63,24 @@ type replicaConfig struct {
 	Mounter          *MounterConfig    `toml:"mounter" json:"mounter"`
 	Sink             *SinkConfig       `toml:"sink" json:"sink"`
 	Consistent       *ConsistentConfig `toml:"consistent" json:"consistent"`
+	// MemoryQuota is the memory quota in bytes shared by all table sinks of
+	// the changefeed on one capture, 0 means only per-table quotas apply.
+	MemoryQuota uint64 `toml:"memory-quota" json:"memory-quota"`
+	// InitialSnapshot indicates whether the existing rows of all tables at
+	// the start-ts are replicated as inserts before incremental changes.
+	InitialSnapshot bool `toml:"initial-snapshot" json:"initial-snapshot"`
+	// Retry is the policy of restarting the changefeed on errors.
+	Retry *RetryConfig `toml:"retry" json:"retry"`
+	// RateLimit limits the throughput of the rows sent from the sorter to the sink.
+	RateLimit *RateLimitConfig `toml:"rate-limit" json:"rate-limit,omitempty"`
+	// Priority is used to allocate the sorter and sink resources shared by the
+	// changefeeds on a capture, it's one of low, normal and high. It doesn't
+	// change the rate limits.
+	Priority string `toml:"priority" json:"priority,omitempty"`
+	// EnableRowChecksum indicates whether the checksum of each row is
+	// calculated by the mounter and sent by the MQ sinks. It can not be
+	// enabled together with the type rendering of the sink.
+	EnableRowChecksum bool `toml:"enable-row-checksum" json:"enable-row-checksum,omitempty"`
 }
 
 // Marshal returns the json marshal format of a ReplicationConfig
@@ -122,6 +146,43 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error {
 			return err
 		}
 	}
+	if c.Filter != nil {
+		if err := c.Filter.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Retry != nil {
+		if err := c.Retry.validate(); err != nil {
+			return err
+		}
+	}
+	if c.RateLimit != nil {
+		if err := c.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateChangefeedPriority(c.Priority); err != nil {
+		return err
+	}
+	// Rows of the initial snapshot are not written to the redo log,
+	// so they can not be recovered from it.
+	if c.InitialSnapshot && c.Consistent != nil && c.Consistent.Level == "eventual" {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"initial-snapshot can not be enabled together with redo log")
+	}
+	// Rows are sent to the sink before the global resolved ts if the redo log
+	// is enabled, so they are not held by the partition change barrier.
+	if c.Sink != nil && c.Sink.EnablePartitionChangeMarker &&
+		c.Consistent != nil && c.Consistent.Level == "eventual" {
+		return cerror.ErrRedoConfigInvalid.GenWithStack(
+			"enable-partition-change-marker can not be enabled together with redo log")
+	}
+	// The checksum is calculated over the values of the mounter, it can not
+	// be verified by the consumer if the values are rendered.
+	if c.EnableRowChecksum && c.Sink != nil && !c.Sink.TypeRendering.IsEmpty() {
+		return cerror.ErrSinkInvalidConfig.GenWithStack(
+			"enable-row-checksum can not be enabled together with type-rendering")
+	}
 	return nil
 }
 
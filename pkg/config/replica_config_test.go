@@ -17,7 +17,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -118,3 +121,141 @@ func TestReplicaConfigValidate(t *testing.T) {
 	require.Equal(t, "p1", rules[1].PartitionRule)
 	require.Equal(t, "", rules[2].PartitionRule)
 }
+
+func TestReplicaConfigValidateDDLRewriteRules(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	conf.Filter.DDLRewriteRules = []*DDLRewriteRule{
+		{Matcher: []string{"*.*"}, Action: DDLRewriteStripAutoRandom},
+		{Matcher: []string{"test.*"}, Action: DDLRewriteMapColumnType, TypeMapping: map[string]string{"json": "longtext"}},
+		{Matcher: []string{"test.*"}, Action: DDLRewriteCharset, Charset: "utf8mb4"},
+		{Matcher: []string{"*.*"}, Action: DDLRewriteRegexpReplace, Pattern: "(?i)CLUSTERED", Replacement: ""},
+	}
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	testCases := []struct {
+		rule   *DDLRewriteRule
+		errMsg string
+	}{
+		{&DDLRewriteRule{Matcher: []string{"*.*"}, Action: "unknown"}, ".*unknown ddl rewrite action.*"},
+		{&DDLRewriteRule{Matcher: []string{"--"}, Action: DDLRewriteStripAutoRandom}, ".*ErrFilterRuleInvalid.*"},
+		{&DDLRewriteRule{Matcher: []string{"*.*"}, Action: DDLRewriteMapColumnType}, ".*type-mapping is required.*"},
+		{&DDLRewriteRule{Matcher: []string{"*.*"}, Action: DDLRewriteCharset}, ".*charset or collation is required.*"},
+		{&DDLRewriteRule{Matcher: []string{"*.*"}, Action: DDLRewriteRegexpReplace}, ".*pattern is required.*"},
+		{&DDLRewriteRule{Matcher: []string{"*.*"}, Action: DDLRewriteRegexpReplace, Pattern: "("}, ".*ErrFilterRuleInvalid.*"},
+	}
+	for _, tc := range testCases {
+		conf = GetDefaultReplicaConfig()
+		conf.Filter.DDLRewriteRules = []*DDLRewriteRule{tc.rule}
+		require.Regexp(t, tc.errMsg, conf.ValidateAndAdjust(nil))
+	}
+}
+
+func TestReplicaConfigValidateInitialSnapshot(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	conf.InitialSnapshot = true
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	conf.Consistent.Level = "eventual"
+	require.Regexp(t, ".*initial-snapshot can not be enabled together with redo log.*",
+		conf.ValidateAndAdjust(nil))
+}
+
+func TestReplicaConfigValidatePartitionChangeMarker(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	conf.Sink.EnablePartitionChangeMarker = true
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	conf.Consistent.Level = "eventual"
+	require.Regexp(t, ".*enable-partition-change-marker can not be enabled together with redo log.*",
+		conf.ValidateAndAdjust(nil))
+}
+
+func TestReplicaConfigValidateRowChecksum(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	conf.EnableRowChecksum = true
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+	conf.Sink.TypeRendering = &TypeRenderingConfig{}
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	conf.Sink.TypeRendering.Decimal = DecimalRenderingString
+	require.Regexp(t, ".*enable-row-checksum can not be enabled together with type-rendering.*",
+		conf.ValidateAndAdjust(nil))
+	conf.EnableRowChecksum = false
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+}
+
+func TestReplicaConfigValidateRetry(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+
+	conf.Retry.MaxRetryDuration = -1
+	require.Regexp(t, ".*max-retry-duration can not be negative.*",
+		conf.ValidateAndAdjust(nil))
+
+	conf = GetDefaultReplicaConfig()
+	conf.Retry.BackoffInitInterval = 0
+	require.Regexp(t, ".*backoff-init-interval must be positive.*",
+		conf.ValidateAndAdjust(nil))
+
+	conf = GetDefaultReplicaConfig()
+	conf.Retry.BackoffMaxInterval = TomlDuration(time.Second)
+	require.Regexp(t, ".*backoff-max-interval can not be less than.*",
+		conf.ValidateAndAdjust(nil))
+
+	conf = GetDefaultReplicaConfig()
+	conf.Retry.AutoSkipDDLErrors = []string{" "}
+	require.Regexp(t, ".*auto-skip-ddl-errors can not contain empty rule.*",
+		conf.ValidateAndAdjust(nil))
+}
+
+func TestRetryConfigShouldSkipDDLError(t *testing.T) {
+	t.Parallel()
+	var conf *RetryConfig
+	require.False(t, conf.ShouldSkipDDLError(errors.New("Error 1050: Table 't' already exists")))
+
+	conf = &RetryConfig{AutoSkipDDLErrors: []string{"Error 1050", "CDC:ErrExecDDLFailed"}}
+	require.False(t, conf.ShouldSkipDDLError(nil))
+	require.True(t, conf.ShouldSkipDDLError(errors.New("Error 1050: Table 't' already exists")))
+	require.True(t, conf.ShouldSkipDDLError(cerror.ErrExecDDLFailed.GenWithStackByArgs()))
+	require.True(t, conf.ShouldSkipDDLError(
+		cerror.WrapError(cerror.ErrExecDDLFailed, errors.New("Error 1146"))))
+	require.False(t, conf.ShouldSkipDDLError(errors.New("Error 1146: Table 't' doesn't exist")))
+}
+
+func TestReplicaConfigValidateRateLimitAndPriority(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultReplicaConfig()
+	conf.RateLimit = &RateLimitConfig{
+		MaxRowsPerSecond: 1000,
+		Tables: []*TableRateLimit{
+			{Matcher: []string{"test.big_*"}, MaxBytesPerSecond: 1 << 20},
+		},
+	}
+	conf.Priority = ChangefeedPriorityHigh
+	require.Nil(t, conf.ValidateAndAdjust(nil))
+	require.True(t, conf.RateLimit.IsEnabled())
+
+	conf.RateLimit.Tables[0].MaxBytesPerSecond = 0
+	require.Regexp(t, ".*table rate limit must limit the rows or the bytes.*",
+		conf.ValidateAndAdjust(nil))
+
+	conf.RateLimit.Tables[0] = &TableRateLimit{MaxRowsPerSecond: 1}
+	require.Regexp(t, ".*matcher of the table rate limit can not be empty.*",
+		conf.ValidateAndAdjust(nil))
+
+	conf = GetDefaultReplicaConfig()
+	require.False(t, conf.RateLimit.IsEnabled())
+	conf.Priority = "urgent"
+	require.Regexp(t, ".*invalid changefeed priority urgent.*",
+		conf.ValidateAndAdjust(nil))
+
+	require.Equal(t, uint64(1), ChangefeedPriorityWeight(ChangefeedPriorityLow))
+	require.Equal(t, uint64(2), ChangefeedPriorityWeight(""))
+	require.Equal(t, uint64(2), ChangefeedPriorityWeight(ChangefeedPriorityNormal))
+	require.Equal(t, uint64(4), ChangefeedPriorityWeight(ChangefeedPriorityHigh))
+}
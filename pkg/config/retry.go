@@ -0,0 +1,72 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// RetryConfig represents the policy of restarting a changefeed on errors.
+type RetryConfig struct {
+	// MaxRetryDuration is the max duration the changefeed keeps retrying
+	// on retryable errors before it is failed, 0 means retrying forever.
+	MaxRetryDuration TomlDuration `toml:"max-retry-duration" json:"max-retry-duration"`
+	// BackoffInitInterval and BackoffMaxInterval bound the exponential
+	// backoff between two restarts of the changefeed.
+	BackoffInitInterval TomlDuration `toml:"backoff-init-interval" json:"backoff-init-interval"`
+	BackoffMaxInterval  TomlDuration `toml:"backoff-max-interval" json:"backoff-max-interval"`
+	// AutoSkipDDLErrors are error codes, such as "CDC:ErrExecDDLFailed",
+	// or message fragments, such as "Error 1050". A DDL failing with an
+	// error matching one of them is skipped instead of stopping the changefeed.
+	AutoSkipDDLErrors []string `toml:"auto-skip-ddl-errors" json:"auto-skip-ddl-errors"`
+}
+
+func (c *RetryConfig) validate() error {
+	if c.MaxRetryDuration < 0 {
+		return cerror.ErrIllegalRetryParameter.GenWithStackByArgs(
+			"max-retry-duration can not be negative")
+	}
+	if c.BackoffInitInterval <= 0 {
+		return cerror.ErrIllegalRetryParameter.GenWithStackByArgs(
+			"backoff-init-interval must be positive")
+	}
+	if c.BackoffMaxInterval < c.BackoffInitInterval {
+		return cerror.ErrIllegalRetryParameter.GenWithStackByArgs(
+			"backoff-max-interval can not be less than backoff-init-interval")
+	}
+	for _, rule := range c.AutoSkipDDLErrors {
+		if strings.TrimSpace(rule) == "" {
+			return cerror.ErrIllegalRetryParameter.GenWithStackByArgs(
+				"auto-skip-ddl-errors can not contain empty rule")
+		}
+	}
+	return nil
+}
+
+// ShouldSkipDDLError returns true if a DDL failing with the error
+// should be skipped.
+func (c *RetryConfig) ShouldSkipDDLError(err error) bool {
+	if c == nil || err == nil {
+		return false
+	}
+	code, _ := cerror.RFCCode(err)
+	for _, rule := range c.AutoSkipDDLErrors {
+		if string(code) == rule || strings.Contains(err.Error(), rule) {
+			return true
+		}
+	}
+	return false
+}
@@ -108,6 +108,7 @@ var defaultServerConfig = &ServerConfig{
 		MaxMemoryConsumption:   16 * 1024 * 1024 * 1024, // 16GB
 		NumWorkerPoolGoroutine: 16,
 		SortDir:                DefaultSortDir,
+		DiskQuotaPolicy:        DiskQuotaPolicyBackpressure,
 	},
 	Security:            &SecurityConfig{},
 	PerTableMemoryQuota: DefaultTableMemoryQuota,
@@ -119,6 +120,14 @@ var defaultServerConfig = &ServerConfig{
 		// Use 1 minute to cover region leader missing.
 		RegionRetryDuration: TomlDuration(time.Minute),
 	},
+	Tracing: &TracingConfig{
+		SampleRatio: 0,
+		Exporter:    TracingExporterFile,
+	},
+	Notification: &NotificationConfig{
+		QueueSize: 1024,
+		Timeout:   TomlDuration(10 * time.Second),
+	},
 	Debug: &DebugConfig{
 		TableActor: &TableActorConfig{
 			EventBatchSize: 32,
@@ -172,12 +181,14 @@ type ServerConfig struct {
 	OwnerFlushInterval     TomlDuration `toml:"owner-flush-interval" json:"owner-flush-interval"`
 	ProcessorFlushInterval TomlDuration `toml:"processor-flush-interval" json:"processor-flush-interval"`
 
-	Sorter              *SorterConfig   `toml:"sorter" json:"sorter"`
-	Security            *SecurityConfig `toml:"security" json:"security"`
-	PerTableMemoryQuota uint64          `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
-	KVClient            *KVClientConfig `toml:"kv-client" json:"kv-client"`
-	Debug               *DebugConfig    `toml:"debug" json:"debug"`
-	ClusterID           string          `toml:"cluster-id" json:"cluster-id"`
+	Sorter              *SorterConfig       `toml:"sorter" json:"sorter"`
+	Security            *SecurityConfig     `toml:"security" json:"security"`
+	PerTableMemoryQuota uint64              `toml:"per-table-memory-quota" json:"per-table-memory-quota"`
+	KVClient            *KVClientConfig     `toml:"kv-client" json:"kv-client"`
+	Tracing             *TracingConfig      `toml:"tracing" json:"tracing"`
+	Notification        *NotificationConfig `toml:"notification" json:"notification"`
+	Debug               *DebugConfig        `toml:"debug" json:"debug"`
+	ClusterID           string              `toml:"cluster-id" json:"cluster-id"`
 }
 
 // Marshal returns the json marshal format of a ServerConfig
@@ -286,6 +297,20 @@ func (c *ServerConfig) ValidateAndAdjust() error {
 		return errors.Trace(err)
 	}
 
+	if c.Tracing == nil {
+		c.Tracing = defaultCfg.Tracing
+	}
+	if err = c.Tracing.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.Notification == nil {
+		c.Notification = defaultCfg.Notification
+	}
+	if err = c.Notification.ValidateAndAdjust(); err != nil {
+		return errors.Trace(err)
+	}
+
 	if c.Debug == nil {
 		c.Debug = defaultCfg.Debug
 	}
@@ -0,0 +1,249 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// hotReloadableItems are the items of the server configuration which can be
+// changed without restarting the server, keyed by their paths in the config
+// file. The components read these items from the global server configuration
+// when they use them, or register a hook by OnServerConfigReloaded.
+//
+// The memory limits of the unified sorter are read every time a sorter
+// allocates a backend, and the disk quota of the db sorter is applied by a
+// hook, so they take effect immediately. Other items of sorter are used to
+// create the sorters when the server starts, so they are not reloadable.
+var hotReloadableItems = map[string]func(dst, src *ServerConfig){
+	"log-level": func(dst, src *ServerConfig) {
+		dst.LogLevel = src.LogLevel
+	},
+	"kv-client.worker-concurrent": func(dst, src *ServerConfig) {
+		dst.KVClient.WorkerConcurrent = src.KVClient.WorkerConcurrent
+	},
+	"sorter.max-memory-consumption": func(dst, src *ServerConfig) {
+		dst.Sorter.MaxMemoryConsumption = src.Sorter.MaxMemoryConsumption
+	},
+	"sorter.max-memory-percentage": func(dst, src *ServerConfig) {
+		dst.Sorter.MaxMemoryPercentage = src.Sorter.MaxMemoryPercentage
+	},
+	"sorter.num-concurrent-worker": func(dst, src *ServerConfig) {
+		dst.Sorter.NumConcurrentWorker = src.Sorter.NumConcurrentWorker
+	},
+	"sorter.chunk-size-limit": func(dst, src *ServerConfig) {
+		dst.Sorter.ChunkSizeLimit = src.Sorter.ChunkSizeLimit
+	},
+	"sorter.disk-quota": func(dst, src *ServerConfig) {
+		dst.Sorter.DiskQuota = src.Sorter.DiskQuota
+	},
+	"sorter.disk-quota-policy": func(dst, src *ServerConfig) {
+		dst.Sorter.DiskQuotaPolicy = src.Sorter.DiskQuotaPolicy
+	},
+	"debug.scheduler.heartbeat-tick": func(dst, src *ServerConfig) {
+		dst.Debug.Scheduler.HeartbeatTick = src.Debug.Scheduler.HeartbeatTick
+	},
+	"debug.scheduler.max-task-concurrency": func(dst, src *ServerConfig) {
+		dst.Debug.Scheduler.MaxTaskConcurrency = src.Debug.Scheduler.MaxTaskConcurrency
+	},
+	"debug.scheduler.check-balance-interval": func(dst, src *ServerConfig) {
+		dst.Debug.Scheduler.CheckBalanceInterval = src.Debug.Scheduler.CheckBalanceInterval
+	},
+}
+
+// newTablesOnlyItems are the hot reloadable items which are read when a table
+// is added, so they are applied to the tables added after the reload, and the
+// running tables keep the old values.
+var newTablesOnlyItems = map[string]struct{}{
+	"kv-client.worker-concurrent":  {},
+	"sorter.num-concurrent-worker": {},
+	"sorter.chunk-size-limit":      {},
+}
+
+// isHotReloadable returns true if the item can be applied to the server
+// running with cfg without restarting it.
+func isHotReloadable(cfg *ServerConfig, item string) bool {
+	if _, ok := hotReloadableItems[item]; !ok {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(item, "debug.scheduler."):
+		// Only the scheduler v3 applies the reloaded scheduler config.
+		return cfg.Debug.EnableSchedulerV3
+	case strings.HasPrefix(item, "sorter.disk-quota"):
+		// The disk quota can not be enabled or disabled without restarting,
+		// because the db sorter accounts the disk usage only if it is enabled.
+		return cfg.Debug.EnableDBSorter && cfg.Sorter.DiskQuota > 0
+	case strings.HasPrefix(item, "sorter."):
+		// Other sorter items are used by the unified sorter only, the db
+		// sorter sizes its cache by the memory percentage when it starts.
+		return !cfg.Debug.EnableDBSorter
+	}
+	return true
+}
+
+// ReloadResult is the result of reloading the server configuration.
+type ReloadResult struct {
+	// Applied are the changed items which have been applied.
+	Applied []string `json:"applied"`
+	// AppliedToNewTables are the changed items which have been applied to
+	// the tables added after the reload only, the running tables keep the
+	// old values until they are moved or the server is restarted.
+	AppliedToNewTables []string `json:"applied-to-new-tables"`
+	// RestartRequired are the changed items which take effect only after
+	// the server is restarted.
+	RestartRequired []string `json:"restart-required"`
+}
+
+var serverConfigReload struct {
+	sync.Mutex
+	loader func() (*ServerConfig, error)
+	hooks  map[int]func(cfg *ServerConfig)
+	nextID int
+}
+
+// SetServerConfigLoader sets the function to load the server configuration
+// from the config file and the command line flags, it is used to reload the
+// server configuration.
+func SetServerConfigLoader(loader func() (*ServerConfig, error)) {
+	serverConfigReload.Lock()
+	defer serverConfigReload.Unlock()
+	serverConfigReload.loader = loader
+}
+
+// OnServerConfigReloaded registers a hook which is called with the new global
+// server configuration after some hot reloadable items are changed. The hook
+// must not block, the returned function unregisters the hook.
+func OnServerConfigReloaded(hook func(cfg *ServerConfig)) (unregister func()) {
+	serverConfigReload.Lock()
+	defer serverConfigReload.Unlock()
+	if serverConfigReload.hooks == nil {
+		serverConfigReload.hooks = make(map[int]func(cfg *ServerConfig))
+	}
+	id := serverConfigReload.nextID
+	serverConfigReload.nextID++
+	serverConfigReload.hooks[id] = hook
+	return func() {
+		serverConfigReload.Lock()
+		defer serverConfigReload.Unlock()
+		delete(serverConfigReload.hooks, id)
+	}
+}
+
+// ReloadGlobalServerConfig loads the server configuration by the loader set by
+// SetServerConfigLoader and applies the hot reloadable items to the global
+// server configuration. Other changed items are reported in the result.
+func ReloadGlobalServerConfig() (*ReloadResult, error) {
+	serverConfigReload.Lock()
+	loader := serverConfigReload.loader
+	serverConfigReload.Unlock()
+	if loader == nil {
+		return nil, cerror.ErrReloadServerConfig.GenWithStackByArgs(
+			"the server config is not loaded from the command line")
+	}
+	cfg, err := loader()
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrReloadServerConfig, err, err.Error())
+	}
+	return applyServerConfig(cfg), nil
+}
+
+// applyServerConfig applies the hot reloadable items of cfg to the global
+// server configuration and calls the hooks if any item is applied.
+func applyServerConfig(cfg *ServerConfig) *ReloadResult {
+	// Reloads are serialized, so that the hooks are called in order.
+	serverConfigReload.Lock()
+	defer serverConfigReload.Unlock()
+
+	current := GetGlobalServerConfig()
+	newCfg := current.Clone()
+	result := &ReloadResult{
+		Applied: []string{}, AppliedToNewTables: []string{}, RestartRequired: []string{},
+	}
+	applied := false
+	for _, item := range diffServerConfig(current, cfg) {
+		if isHotReloadable(current, item) {
+			hotReloadableItems[item](newCfg, cfg)
+			applied = true
+			if _, ok := newTablesOnlyItems[item]; ok {
+				result.AppliedToNewTables = append(result.AppliedToNewTables, item)
+			} else {
+				result.Applied = append(result.Applied, item)
+			}
+		} else {
+			result.RestartRequired = append(result.RestartRequired, item)
+		}
+	}
+	log.Info("reload server config",
+		zap.Strings("applied", result.Applied),
+		zap.Strings("appliedToNewTables", result.AppliedToNewTables),
+		zap.Strings("restartRequired", result.RestartRequired))
+	if !applied {
+		return result
+	}
+
+	StoreGlobalServerConfig(newCfg)
+	for _, hook := range serverConfigReload.hooks {
+		hook(newCfg)
+	}
+	return result
+}
+
+// diffServerConfig returns the sorted paths of the items which are different
+// in the two configurations.
+func diffServerConfig(a, b *ServerConfig) []string {
+	var items []string
+	diffValue(reflect.ValueOf(a), reflect.ValueOf(b), "", &items)
+	sort.Strings(items)
+	return items
+}
+
+func diffValue(a, b reflect.Value, path string, items *[]string) {
+	if a.Kind() == reflect.Ptr && a.Type().Elem().Kind() == reflect.Struct {
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*items = append(*items, path)
+			}
+			return
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if a.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*items = append(*items, path)
+		}
+		return
+	}
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		name := strings.Split(field.Tag.Get("toml"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		diffValue(a.Field(i), b.Field(i), name, items)
+	}
+}
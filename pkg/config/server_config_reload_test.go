@@ -0,0 +1,188 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadGlobalServerConfig(t *testing.T) {
+	oldCfg := GetGlobalServerConfig()
+	defer func() {
+		StoreGlobalServerConfig(oldCfg)
+		SetServerConfigLoader(nil)
+	}()
+	StoreGlobalServerConfig(GetDefaultServerConfig())
+
+	_, err := ReloadGlobalServerConfig()
+	require.True(t, cerror.ErrReloadServerConfig.Equal(err))
+
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		return nil, errors.New("invalid config file")
+	})
+	_, err = ReloadGlobalServerConfig()
+	require.Regexp(t, "ErrReloadServerConfig.*invalid config file", err)
+
+	loaded := GetDefaultServerConfig()
+	loaded.LogLevel = "debug"
+	loaded.Addr = "127.0.0.1:8301"
+	loaded.Sorter.MaxMemoryPercentage = 50
+	loaded.KVClient.WorkerConcurrent = 16
+	loaded.Debug.Scheduler.CheckBalanceInterval = TomlDuration(time.Second)
+	loaded.Security.CertAllowedCN = []string{"cdc"}
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		return loaded.Clone(), nil
+	})
+
+	var reloaded []*ServerConfig
+	unregister := OnServerConfigReloaded(func(cfg *ServerConfig) {
+		reloaded = append(reloaded, cfg)
+	})
+	defer unregister()
+
+	result, err := ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"debug.scheduler.check-balance-interval",
+		"log-level",
+	}, result.Applied)
+	require.Equal(t, []string{"kv-client.worker-concurrent"}, result.AppliedToNewTables)
+	require.Equal(t, []string{
+		"addr", "security.cert-allowed-cn", "sorter.max-memory-percentage",
+	}, result.RestartRequired)
+
+	cfg := GetGlobalServerConfig()
+	require.Equal(t, "debug", cfg.LogLevel)
+	require.Equal(t, GetDefaultServerConfig().Sorter.MaxMemoryPercentage,
+		cfg.Sorter.MaxMemoryPercentage)
+	require.Equal(t, 16, cfg.KVClient.WorkerConcurrent)
+	require.Equal(t, TomlDuration(time.Second), cfg.Debug.Scheduler.CheckBalanceInterval)
+	require.Equal(t, GetDefaultServerConfig().Addr, cfg.Addr)
+	require.Empty(t, cfg.Security.CertAllowedCN)
+	require.Len(t, reloaded, 1)
+	require.Same(t, cfg, reloaded[0])
+
+	// The items which require restarting are reported until the server is
+	// restarted, and the hooks are not called if nothing is applied.
+	result, err = ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Empty(t, result.AppliedToNewTables)
+	require.Equal(t, []string{
+		"addr", "security.cert-allowed-cn", "sorter.max-memory-percentage",
+	}, result.RestartRequired)
+	require.Len(t, reloaded, 1)
+
+	unregister()
+	loaded.LogLevel = "warn"
+	result, err = ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Equal(t, []string{"log-level"}, result.Applied)
+	require.Len(t, reloaded, 1)
+}
+
+func TestReloadSchedulerConfigV2(t *testing.T) {
+	oldCfg := GetGlobalServerConfig()
+	defer func() {
+		StoreGlobalServerConfig(oldCfg)
+		SetServerConfigLoader(nil)
+	}()
+	// The scheduler v2 does not apply the reloaded scheduler config.
+	current := GetDefaultServerConfig()
+	current.Debug.EnableSchedulerV3 = false
+	StoreGlobalServerConfig(current)
+
+	loaded := current.Clone()
+	loaded.Debug.Scheduler.CheckBalanceInterval = TomlDuration(time.Second)
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		return loaded.Clone(), nil
+	})
+	result, err := ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Equal(t, []string{"debug.scheduler.check-balance-interval"}, result.RestartRequired)
+}
+
+func TestReloadSorterConfig(t *testing.T) {
+	oldCfg := GetGlobalServerConfig()
+	defer func() {
+		StoreGlobalServerConfig(oldCfg)
+		SetServerConfigLoader(nil)
+	}()
+
+	// The memory limits of the unified sorter are applied immediately, and
+	// its concurrency and chunk size are applied to the new tables.
+	current := GetDefaultServerConfig()
+	current.Debug.EnableDBSorter = false
+	StoreGlobalServerConfig(current)
+	loaded := current.Clone()
+	loaded.Sorter.MaxMemoryPercentage = 50
+	loaded.Sorter.MaxMemoryConsumption = 1024 * 1024 * 1024
+	loaded.Sorter.NumConcurrentWorker = 8
+	loaded.Sorter.ChunkSizeLimit = 64 * 1024 * 1024
+	loaded.Sorter.NumWorkerPoolGoroutine = 32
+	SetServerConfigLoader(func() (*ServerConfig, error) {
+		return loaded.Clone(), nil
+	})
+	result, err := ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"sorter.max-memory-consumption", "sorter.max-memory-percentage",
+	}, result.Applied)
+	require.Equal(t, []string{
+		"sorter.chunk-size-limit", "sorter.num-concurrent-worker",
+	}, result.AppliedToNewTables)
+	require.Equal(t, []string{"sorter.num-workerpool-goroutine"}, result.RestartRequired)
+	cfg := GetGlobalServerConfig()
+	require.Equal(t, 50, cfg.Sorter.MaxMemoryPercentage)
+	require.Equal(t, 8, cfg.Sorter.NumConcurrentWorker)
+	require.Equal(t, current.Sorter.NumWorkerPoolGoroutine, cfg.Sorter.NumWorkerPoolGoroutine)
+
+	// The db sorter applies the disk quota by a hook if it is enabled, the
+	// unified sorter items are not used by it.
+	current = GetDefaultServerConfig()
+	current.Sorter.DiskQuota = 1024
+	StoreGlobalServerConfig(current)
+	loaded = current.Clone()
+	loaded.Sorter.DiskQuota = 2048
+	loaded.Sorter.DiskQuotaPolicy = DiskQuotaPolicyFail
+	loaded.Sorter.MaxMemoryPercentage = 50
+	var reloaded []*ServerConfig
+	unregister := OnServerConfigReloaded(func(cfg *ServerConfig) {
+		reloaded = append(reloaded, cfg)
+	})
+	defer unregister()
+	result, err = ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Equal(t, []string{"sorter.disk-quota", "sorter.disk-quota-policy"}, result.Applied)
+	require.Equal(t, []string{"sorter.max-memory-percentage"}, result.RestartRequired)
+	require.Len(t, reloaded, 1)
+	require.Equal(t, uint64(2048), reloaded[0].Sorter.DiskQuota)
+	require.Equal(t, DiskQuotaPolicyFail, reloaded[0].Sorter.DiskQuotaPolicy)
+
+	// The disk quota can not be enabled without restarting.
+	current.Sorter.DiskQuota = 0
+	StoreGlobalServerConfig(current)
+	result, err = ReloadGlobalServerConfig()
+	require.Nil(t, err)
+	require.Empty(t, result.Applied)
+	require.Equal(t, []string{
+		"sorter.disk-quota", "sorter.disk-quota-policy", "sorter.max-memory-percentage",
+	}, result.RestartRequired)
+}
@@ -97,6 +97,21 @@ func TestDBConfigValidateAndAdjust(t *testing.T) {
 	require.Error(t, conf.ValidateAndAdjust())
 }
 
+func TestSorterConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Sorter
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, DiskQuotaPolicyBackpressure, conf.DiskQuotaPolicy)
+	conf.DiskQuotaPolicy = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, DiskQuotaPolicyBackpressure, conf.DiskQuotaPolicy)
+	conf.DiskQuotaPolicy = DiskQuotaPolicyFail
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.DiskQuotaPolicy = "invalid"
+	require.Regexp(t, ".*disk-quota-policy.*", conf.ValidateAndAdjust())
+}
+
 func TestKVClientConfigValidateAndAdjust(t *testing.T) {
 	t.Parallel()
 	conf := GetDefaultServerConfig().Clone().KVClient
@@ -108,6 +123,42 @@ func TestKVClientConfigValidateAndAdjust(t *testing.T) {
 	require.Error(t, conf.ValidateAndAdjust())
 }
 
+func TestTracingConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Tracing
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.SampleRatio = 0.5
+	conf.Exporter = ""
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, TracingExporterFile, conf.Exporter)
+	conf.SampleRatio = 1.5
+	require.Regexp(t, ".*sample-ratio.*", conf.ValidateAndAdjust())
+	conf.SampleRatio = 0.5
+	conf.Exporter = "jaeger"
+	require.Regexp(t, ".*unsupported exporter.*", conf.ValidateAndAdjust())
+}
+
+func TestNotificationConfigValidateAndAdjust(t *testing.T) {
+	t.Parallel()
+	conf := GetDefaultServerConfig().Clone().Notification
+
+	require.Nil(t, conf.ValidateAndAdjust())
+	conf.QueueSize = 0
+	conf.Timeout = 0
+	conf.Webhooks = []*WebhookConfig{{URL: "https://example.com/hook", Secret: "s"}}
+	require.Nil(t, conf.ValidateAndAdjust())
+	require.Equal(t, 1024, conf.QueueSize)
+	require.Equal(t, TomlDuration(10*time.Second), conf.Timeout)
+	conf.QueueSize = -1
+	require.Regexp(t, ".*queue-size should be positive.*", conf.ValidateAndAdjust())
+	conf.QueueSize = 1
+	conf.Webhooks = []*WebhookConfig{{URL: "example.com/hook"}}
+	require.Regexp(t, ".*invalid webhook url.*", conf.ValidateAndAdjust())
+	conf.Webhooks = []*WebhookConfig{{URL: "ftp://example.com/hook"}}
+	require.Regexp(t, ".*invalid webhook url.*", conf.ValidateAndAdjust())
+}
+
 func TestSchedulerConfigValidateAndAdjust(t *testing.T) {
 	t.Parallel()
 	conf := GetDefaultServerConfig().Clone().Debug.Scheduler
@@ -67,6 +67,16 @@ type SinkConfig struct {
 	ColumnSelectors []*ColumnSelector `toml:"column-selectors" json:"column-selectors"`
 	SchemaRegistry  string            `toml:"schema-registry" json:"schema-registry"`
 	TxnAtomicity    AtomicityLevel    `toml:"transaction-atomicity" json:"transaction-atomicity"`
+	// TopicTemplates are used by the Kafka sink to create the topics.
+	TopicTemplates []*TopicTemplate `toml:"topic-templates" json:"topic-templates,omitempty"`
+	// EnablePartitionChangeMarker makes the MQ sink broadcast a marker message
+	// when the partition number of a topic is changed, see
+	// codec.PartitionChangeMarkerHeader. The consumers must be able to skip it.
+	// It can not be enabled together with the redo log.
+	EnablePartitionChangeMarker bool `toml:"enable-partition-change-marker" json:"enable-partition-change-marker,omitempty"`
+	// TypeRendering is the policy of rendering column values by the JSON
+	// based protocols.
+	TypeRendering *TypeRenderingConfig `toml:"type-rendering" json:"type-rendering,omitempty"`
 }
 
 // DispatchRule represents partition rule for a table.
@@ -86,6 +96,19 @@ type ColumnSelector struct {
 	Columns []string `toml:"columns" json:"columns"`
 }
 
+// TopicTemplate is used to create the topics whose names match the topic expression.
+// The partition number and the replication factor in the sink uri are used if
+// they are not specified in the template.
+type TopicTemplate struct {
+	// Topic is a topic expression such as `{schema}_{table}`,
+	// or the name of a topic.
+	Topic             string `toml:"topic" json:"topic"`
+	PartitionNum      int32  `toml:"partition-num" json:"partition-num"`
+	ReplicationFactor int16  `toml:"replication-factor" json:"replication-factor"`
+	// TopicConfigs are the topic-level configs, such as `retention.ms` and `cleanup.policy`.
+	TopicConfigs map[string]string `toml:"topic-configs" json:"topic-configs,omitempty"`
+}
+
 func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) error {
 	if err := s.applyParameter(sinkURI); err != nil {
 		return err
@@ -117,6 +140,24 @@ func (s *SinkConfig) validateAndAdjust(sinkURI *url.URL, enableOldValue bool) er
 		}
 	}
 
+	for _, template := range s.TopicTemplates {
+		if template.Topic == "" {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"topic of the topic template cannot be empty")
+		}
+		if template.PartitionNum < 0 || template.ReplicationFactor < 0 {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"partition-num and replication-factor of the topic template "+
+					"for %s cannot be negative", template.Topic)
+		}
+	}
+
+	if s.TypeRendering != nil {
+		if err := s.TypeRendering.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
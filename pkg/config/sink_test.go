@@ -194,3 +194,49 @@ func TestApplyParameter(t *testing.T) {
 		require.Equal(t, c.result, c.sinkConfig.Protocol)
 	}
 }
+
+func TestValidateTopicTemplates(t *testing.T) {
+	t.Parallel()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test?protocol=open-protocol")
+	require.Nil(t, err)
+
+	cfg := &SinkConfig{TopicTemplates: []*TopicTemplate{{
+		Topic:             "{schema}_{table}",
+		PartitionNum:      6,
+		ReplicationFactor: 3,
+		TopicConfigs:      map[string]string{"cleanup.policy": "compact"},
+	}}}
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.TopicTemplates = append(cfg.TopicTemplates, &TopicTemplate{PartitionNum: 1})
+	require.Regexp(t, "topic of the topic template cannot be empty",
+		cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.TopicTemplates[1] = &TopicTemplate{Topic: "test", PartitionNum: -1}
+	require.Regexp(t, "partition-num and replication-factor of the topic template for test",
+		cfg.validateAndAdjust(sinkURI, true))
+}
+
+func TestValidateTypeRendering(t *testing.T) {
+	t.Parallel()
+	sinkURI, err := url.Parse("kafka://127.0.0.1:9092/test?protocol=canal-json")
+	require.Nil(t, err)
+
+	cfg := &SinkConfig{TypeRendering: &TypeRenderingConfig{}}
+	require.True(t, cfg.TypeRendering.IsEmpty())
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.TypeRendering = &TypeRenderingConfig{
+		Decimal:         DecimalRenderingNumber,
+		TimestampZone:   TimestampZoneUTC,
+		TimestampFormat: TimestampFormatISO,
+		Binary:          BinaryRenderingHex,
+		Enum:            EnumRenderingValue,
+	}
+	require.False(t, cfg.TypeRendering.IsEmpty())
+	require.Nil(t, cfg.validateAndAdjust(sinkURI, true))
+
+	cfg.TypeRendering.Binary = "base32"
+	require.Regexp(t, `invalid type rendering binary "base32"`,
+		cfg.validateAndAdjust(sinkURI, true))
+}
@@ -15,6 +15,15 @@ package config
 
 import "github.com/pingcap/tiflow/pkg/errors"
 
+const (
+	// DiskQuotaPolicyBackpressure blocks the input of a changefeed until
+	// it is back within its share of the sorter disk quota.
+	DiskQuotaPolicyBackpressure = "backpressure"
+	// DiskQuotaPolicyFail fails a changefeed once it exceeds its share of
+	// the sorter disk quota.
+	DiskQuotaPolicyFail = "fail"
+)
+
 // SorterConfig represents sorter config for a changefeed
 type SorterConfig struct {
 	// number of concurrent heap sorts
@@ -29,6 +38,15 @@ type SorterConfig struct {
 	NumWorkerPoolGoroutine int `toml:"num-workerpool-goroutine" json:"num-workerpool-goroutine"`
 	// the directory used to store the temporary files generated by the sorter
 	SortDir string `toml:"sort-dir" json:"sort-dir"`
+	// the extra directories used by the db sorter, db instances are striped
+	// across sort-dir and the extra directories
+	ExtraSortDirs []string `toml:"extra-sort-dirs" json:"extra-sort-dirs"`
+	// the maximum bytes of data stored on disk by the db sorter, every
+	// changefeed is entitled to a fair share of it, 0 means unlimited
+	DiskQuota uint64 `toml:"disk-quota" json:"disk-quota"`
+	// what to do when a changefeed exceeds its share of disk-quota,
+	// "backpressure" or "fail"
+	DiskQuotaPolicy string `toml:"disk-quota-policy" json:"disk-quota-policy"`
 }
 
 // ValidateAndAdjust validates and adjusts the sorter configuration
@@ -57,6 +75,14 @@ func (c *SorterConfig) ValidateAndAdjust() error {
 		return errors.ErrIllegalSorterParameter.GenWithStackByArgs(
 			"max-memory-percentage should be a percentage and within (0, 80]")
 	}
+	switch c.DiskQuotaPolicy {
+	case "":
+		c.DiskQuotaPolicy = DiskQuotaPolicyBackpressure
+	case DiskQuotaPolicyBackpressure, DiskQuotaPolicyFail:
+	default:
+		return errors.ErrIllegalSorterParameter.GenWithStackByArgs(
+			"disk-quota-policy should be either backpressure or fail")
+	}
 
 	return nil
 }
@@ -0,0 +1,49 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import cerror "github.com/pingcap/tiflow/pkg/errors"
+
+// TracingExporterFile exports the spans to a local file, one span per line
+// in JSON format.
+const TracingExporterFile = "file"
+
+// TracingConfig represents config for the OpenTelemetry tracing of the
+// transactions replicated by changefeeds.
+type TracingConfig struct {
+	// SampleRatio is the fraction of transactions which are traced,
+	// the tracing is disabled if it is 0.
+	SampleRatio float64 `toml:"sample-ratio" json:"sample-ratio"`
+	// Exporter is the exporter of the spans, only "file" is supported.
+	Exporter string `toml:"exporter" json:"exporter"`
+	// FilePath is the path of the file exporter, it is a file in
+	// data-dir by default.
+	FilePath string `toml:"file-path" json:"file-path"`
+}
+
+// ValidateAndAdjust validates and adjusts the tracing configuration
+func (c *TracingConfig) ValidateAndAdjust() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return cerror.ErrIllegalTracingParameter.GenWithStackByArgs(
+			"sample-ratio should be in [0, 1]")
+	}
+	if c.Exporter == "" {
+		c.Exporter = TracingExporterFile
+	}
+	if c.Exporter != TracingExporterFile {
+		return cerror.ErrIllegalTracingParameter.GenWithStackByArgs(
+			"unsupported exporter " + c.Exporter)
+	}
+	return nil
+}
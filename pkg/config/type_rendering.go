@@ -0,0 +1,97 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+const (
+	// DecimalRenderingString renders decimals as JSON strings.
+	DecimalRenderingString = "string"
+	// DecimalRenderingNumber renders decimals as JSON numbers, the
+	// consumers may lose precision if they decode numbers as doubles.
+	DecimalRenderingNumber = "number"
+
+	// TimestampZoneLocal renders timestamps in the time zone of the changefeed.
+	TimestampZoneLocal = "local"
+	// TimestampZoneUTC renders timestamps in UTC.
+	TimestampZoneUTC = "utc"
+
+	// TimestampFormatISO renders date and time values in ISO 8601 format.
+	TimestampFormatISO = "iso"
+	// TimestampFormatEpoch renders date and time values as milliseconds
+	// since the Unix epoch, datetime and date values are treated as UTC.
+	TimestampFormatEpoch = "epoch"
+
+	// BinaryRenderingBase64 renders binary values in base64.
+	BinaryRenderingBase64 = "base64"
+	// BinaryRenderingHex renders binary values in lower case hex.
+	BinaryRenderingHex = "hex"
+
+	// EnumRenderingValue renders enum and set values as their names.
+	EnumRenderingValue = "value"
+	// EnumRenderingIndex renders enum values as their 1-based indexes and set
+	// values as their bitmasks.
+	EnumRenderingIndex = "index"
+)
+
+// TypeRenderingConfig is the policy of rendering column values by the JSON
+// based protocols, which are open-protocol, canal-json and maxwell. The
+// empty fields keep the rendering of each protocol, so the messages are
+// compatible with the existing consumers by default.
+type TypeRenderingConfig struct {
+	Decimal         string `toml:"decimal" json:"decimal"`
+	TimestampZone   string `toml:"timestamp-zone" json:"timestamp-zone"`
+	TimestampFormat string `toml:"timestamp-format" json:"timestamp-format"`
+	Binary          string `toml:"binary" json:"binary"`
+	Enum            string `toml:"enum" json:"enum"`
+}
+
+// IsEmpty returns true if the config does not change any rendering.
+func (c *TypeRenderingConfig) IsEmpty() bool {
+	return c == nil || *c == TypeRenderingConfig{}
+}
+
+func (c *TypeRenderingConfig) validate() error {
+	checks := []struct {
+		name   string
+		value  string
+		values []string
+	}{
+		{"decimal", c.Decimal, []string{DecimalRenderingString, DecimalRenderingNumber}},
+		{"timestamp-zone", c.TimestampZone, []string{TimestampZoneLocal, TimestampZoneUTC}},
+		{"timestamp-format", c.TimestampFormat, []string{TimestampFormatISO, TimestampFormatEpoch}},
+		{"binary", c.Binary, []string{BinaryRenderingBase64, BinaryRenderingHex}},
+		{"enum", c.Enum, []string{EnumRenderingValue, EnumRenderingIndex}},
+	}
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		valid := false
+		for _, v := range check.values {
+			if check.value == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return cerror.ErrSinkInvalidConfig.GenWithStack(
+				"invalid type rendering %s %q, which must be one of %v",
+				check.name, check.value, check.values)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/mysql"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// checkpointSchema is the name of database where the checkpoints sit.
+	checkpointSchema = "tidb_cdc"
+	// checkpointTable is the name of table where the checkpoints sit.
+	checkpointTable = "consumer_checkpoint"
+)
+
+// Checkpoint is the position up to which a partition has been consumed.
+type Checkpoint struct {
+	Topic     string
+	Partition int32
+	// Offset is the offset from which the partition is consumed after restarting.
+	Offset int64
+	// CheckpointTs is the ts before which (inclusive) all the events
+	// have been written to the downstream, the events not greater than it
+	// are skipped after restarting.
+	CheckpointTs uint64
+	// FlushingTs is the ts to which the events are being flushed, it's saved
+	// before the events are written to the downstream. The events not greater
+	// than it may have been written before restarting, so they are written
+	// idempotently after restarting, see Consumer for details.
+	FlushingTs uint64
+}
+
+// CheckpointStore persists the checkpoints of the consumer.
+// The checkpoints are saved after the events have been written to the
+// downstream, not in the same transaction as the events, so the events written
+// after the last saved checkpoint are replayed after restarting. The flushing
+// ts saved ahead of the events tells which of them must be replayed idempotently.
+type CheckpointStore interface {
+	// Load returns the saved checkpoints.
+	Load(ctx context.Context) ([]Checkpoint, error)
+	// Save saves the checkpoints atomically.
+	Save(ctx context.Context, checkpoints []Checkpoint) error
+	// Close closes the store.
+	Close() error
+}
+
+// NewCheckpointStore creates a CheckpointStore which saves the checkpoints in
+// the downstream database if the sink is MySQL compatible. Otherwise the
+// checkpoints can only be kept in memory, which must be allowed explicitly by
+// allowMemoryStore, since the consumer restarts from the offsets of the
+// consumer group then.
+func NewCheckpointStore(
+	ctx context.Context, sinkURIStr string, groupID string, allowMemoryStore bool,
+) (CheckpointStore, error) {
+	sinkURI, err := url.Parse(sinkURIStr)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrSinkURIInvalid, err)
+	}
+	switch strings.ToLower(sinkURI.Scheme) {
+	case "mysql", "tidb", "mysql+ssl", "tidb+ssl":
+	default:
+		if !allowMemoryStore {
+			return nil, cerror.ErrConsumerInvalidConfig.GenWithStack(
+				"the checkpoints can not be saved in the %s sink, "+
+					"enable memory-checkpoint to keep them in memory", sinkURI.Scheme)
+		}
+		log.Warn("the checkpoints are kept in memory since the sink is not MySQL compatible, "+
+			"the consumer restarts from the offsets of the consumer group",
+			zap.String("scheme", sinkURI.Scheme))
+		return NewMemoryCheckpointStore(), nil
+	}
+
+	db, _, err := mysql.NewDBConnAndConfig(ctx,
+		model.DefaultChangeFeedID(groupID), sinkURI, config.GetDefaultReplicaConfig())
+	if err != nil {
+		return nil, err
+	}
+	store, err := newMySQLCheckpointStore(ctx, db, groupID)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+type mysqlCheckpointStore struct {
+	db      *sql.DB
+	groupID string
+}
+
+func newMySQLCheckpointStore(
+	ctx context.Context, db *sql.DB, groupID string,
+) (*mysqlCheckpointStore, error) {
+	_, err := db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+checkpointSchema)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+	}
+	_, err = db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+
+		checkpointSchema+"."+checkpointTable+` (
+	group_id varchar(255) NOT NULL,
+	topic varchar(255) NOT NULL,
+	`+"`partition`"+` int NOT NULL,
+	`+"`offset`"+` bigint NOT NULL,
+	checkpoint_ts bigint unsigned NOT NULL,
+	flushing_ts bigint unsigned NOT NULL DEFAULT 0,
+	updated_at timestamp DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+	PRIMARY KEY (group_id, topic, `+"`partition`"+`)
+)`)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+	}
+	return &mysqlCheckpointStore{db: db, groupID: groupID}, nil
+}
+
+// Load implements the CheckpointStore interface.
+func (s *mysqlCheckpointStore) Load(ctx context.Context) ([]Checkpoint, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT topic, `partition`, `offset`, checkpoint_ts, flushing_ts FROM "+
+		checkpointSchema+"."+checkpointTable+" WHERE group_id = ?", s.groupID)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+	}
+	defer rows.Close()
+	var checkpoints []Checkpoint
+	for rows.Next() {
+		var cp Checkpoint
+		if err := rows.Scan(&cp.Topic, &cp.Partition, &cp.Offset, &cp.CheckpointTs, &cp.FlushingTs); err != nil {
+			return nil, cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+	}
+	return checkpoints, nil
+}
+
+// Save implements the CheckpointStore interface.
+func (s *mysqlCheckpointStore) Save(ctx context.Context, checkpoints []Checkpoint) error {
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	var builder strings.Builder
+	builder.WriteString("REPLACE INTO " + checkpointSchema + "." + checkpointTable +
+		" (group_id, topic, `partition`, `offset`, checkpoint_ts, flushing_ts) VALUES ")
+	args := make([]interface{}, 0, len(checkpoints)*5)
+	for i, cp := range checkpoints {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("(?,?,?,?,?,?)")
+		args = append(args, s.groupID, cp.Topic, cp.Partition, cp.Offset, cp.CheckpointTs, cp.FlushingTs)
+	}
+	// A single statement is atomic, so all the partitions move forward together.
+	// It's not in the transactions which write the events, so the events not
+	// greater than the flushing ts are replayed idempotently after restarting.
+	if _, err := s.db.ExecContext(ctx, builder.String(), args...); err != nil {
+		return cerror.WrapError(cerror.ErrConsumerCheckpoint, err)
+	}
+	return nil
+}
+
+// Close implements the CheckpointStore interface.
+func (s *mysqlCheckpointStore) Close() error {
+	return cerror.WrapError(cerror.ErrConsumerCheckpoint, s.db.Close())
+}
+
+type memoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]map[int32]Checkpoint
+}
+
+// NewMemoryCheckpointStore creates a CheckpointStore which keeps the checkpoints in memory.
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{
+		checkpoints: make(map[string]map[int32]Checkpoint),
+	}
+}
+
+// Load implements the CheckpointStore interface.
+func (s *memoryCheckpointStore) Load(_ context.Context) ([]Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var checkpoints []Checkpoint
+	for _, partitions := range s.checkpoints {
+		for _, cp := range partitions {
+			checkpoints = append(checkpoints, cp)
+		}
+	}
+	return checkpoints, nil
+}
+
+// Save implements the CheckpointStore interface.
+func (s *memoryCheckpointStore) Save(_ context.Context, checkpoints []Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cp := range checkpoints {
+		partitions, ok := s.checkpoints[cp.Topic]
+		if !ok {
+			partitions = make(map[int32]Checkpoint)
+			s.checkpoints[cp.Topic] = partitions
+		}
+		partitions[cp.Partition] = cp
+	}
+	return nil
+}
+
+// Close implements the CheckpointStore interface.
+func (s *memoryCheckpointStore) Close() error {
+	return nil
+}
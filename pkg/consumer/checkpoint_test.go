@@ -0,0 +1,100 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	mock.ExpectExec("CREATE DATABASE IF NOT EXISTS tidb_cdc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS tidb_cdc.consumer_checkpoint").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	store, err := newMySQLCheckpointStore(ctx, db, "g1")
+	require.Nil(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT topic, `partition`, `offset`, checkpoint_ts, flushing_ts " +
+		"FROM tidb_cdc.consumer_checkpoint WHERE group_id = ?")).
+		WithArgs("g1").
+		WillReturnRows(sqlmock.NewRows([]string{"topic", "partition", "offset", "checkpoint_ts", "flushing_ts"}).
+			AddRow("t1", 0, 10, 100, 150).
+			AddRow("t1", 1, 20, 100, 150))
+	checkpoints, err := store.Load(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []Checkpoint{
+		{Topic: "t1", Partition: 0, Offset: 10, CheckpointTs: 100, FlushingTs: 150},
+		{Topic: "t1", Partition: 1, Offset: 20, CheckpointTs: 100, FlushingTs: 150},
+	}, checkpoints)
+
+	mock.ExpectExec(regexp.QuoteMeta("REPLACE INTO tidb_cdc.consumer_checkpoint "+
+		"(group_id, topic, `partition`, `offset`, checkpoint_ts, flushing_ts) "+
+		"VALUES (?,?,?,?,?,?),(?,?,?,?,?,?)")).
+		WithArgs("g1", "t1", 0, 11, 200, 200, "g1", "t1", 1, 21, 200, 200).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	require.Nil(t, store.Save(ctx, []Checkpoint{
+		{Topic: "t1", Partition: 0, Offset: 11, CheckpointTs: 200, FlushingTs: 200},
+		{Topic: "t1", Partition: 1, Offset: 21, CheckpointTs: 200, FlushingTs: 200},
+	}))
+
+	mock.ExpectClose()
+	require.Nil(t, store.Close())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestNewCheckpointStoreNotMySQL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// The memory store must be allowed explicitly.
+	_, err := NewCheckpointStore(ctx, "blackhole://", "g1", false)
+	require.Regexp(t, "ErrConsumerInvalidConfig", err)
+	store, err := NewCheckpointStore(ctx, "blackhole://", "g1", true)
+	require.Nil(t, err)
+	require.IsType(t, &memoryCheckpointStore{}, store)
+	require.Nil(t, store.Close())
+}
+
+func TestMemoryCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewMemoryCheckpointStore()
+	checkpoints, err := store.Load(ctx)
+	require.Nil(t, err)
+	require.Empty(t, checkpoints)
+
+	require.Nil(t, store.Save(ctx, []Checkpoint{
+		{Topic: "t1", Partition: 0, Offset: 1, CheckpointTs: 100},
+	}))
+	require.Nil(t, store.Save(ctx, []Checkpoint{
+		{Topic: "t1", Partition: 0, Offset: 2, CheckpointTs: 200},
+	}))
+	checkpoints, err = store.Load(ctx)
+	require.Nil(t, err)
+	require.Equal(t, []Checkpoint{
+		{Topic: "t1", Partition: 0, Offset: 2, CheckpointTs: 200},
+	}, checkpoints)
+	require.Nil(t, store.Close())
+}
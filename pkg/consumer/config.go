@@ -0,0 +1,168 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/sink/mq/producer/kafka"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/security"
+)
+
+const (
+	defaultKafkaVersion = "2.4.0"
+	defaultGroupID      = "ticdc_consumer"
+	defaultClientID     = "ticdc_consumer"
+)
+
+// Config is the configuration of a consumer.
+type Config struct {
+	// Addrs is the addresses of the Kafka brokers.
+	Addrs []string
+	// Topics is the topics to consume.
+	Topics []string
+	// GroupID is the consumer group id, it also identifies
+	// the checkpoints of the consumer in the downstream.
+	GroupID string
+	// Version is the version of the Kafka cluster.
+	Version string
+
+	Protocol            config.Protocol
+	EnableTiDBExtension bool
+	MaxMessageBytes     int
+	MaxBatchSize        int
+
+	// Credential is used to connect to the Kafka cluster with TLS.
+	Credential *security.Credential
+	// SASL is the SASL authentication of the Kafka brokers, it's set by the
+	// same uri parameters as the ones of the Kafka sink.
+	SASL *security.SASL
+
+	// SinkURI is the downstream sink uri.
+	SinkURI string
+	// Timezone is the timezone used by the downstream sink.
+	Timezone string
+	// ReplicaConfig is the config of the changefeed which produces the messages,
+	// it is used to check whether the rows are dispatched to the right partition.
+	// The check is skipped if it is nil.
+	ReplicaConfig *config.ReplicaConfig
+}
+
+// NewConfig creates a Config from the upstream uri, such as
+// `kafka://127.0.0.1:9092/topic1,topic2?protocol=canal-json`.
+func NewConfig(upstreamURI string) (*Config, error) {
+	uri, err := url.Parse(upstreamURI)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+	}
+	if strings.ToLower(uri.Scheme) != "kafka" {
+		return nil, cerror.ErrConsumerInvalidConfig.GenWithStack(
+			"the scheme of upstream-uri must be kafka, but got %s", uri.Scheme)
+	}
+	topics := strings.Trim(uri.Path, "/")
+	if topics == "" {
+		return nil, cerror.ErrConsumerInvalidConfig.GenWithStack(
+			"no topic is specified in upstream-uri")
+	}
+
+	cfg := &Config{
+		Addrs:           strings.Split(uri.Host, ","),
+		Topics:          strings.Split(topics, ","),
+		GroupID:         defaultGroupID,
+		Version:         defaultKafkaVersion,
+		Protocol:        config.ProtocolOpen,
+		MaxMessageBytes: math.MaxInt64,
+		MaxBatchSize:    math.MaxInt64,
+		Timezone:        "System",
+	}
+	params := uri.Query()
+	if s := params.Get("consumer-group-id"); s != "" {
+		cfg.GroupID = s
+	}
+	if s := params.Get("version"); s != "" {
+		cfg.Version = s
+	}
+	if s := params.Get(config.ProtocolKey); s != "" {
+		if err := cfg.Protocol.FromString(s); err != nil {
+			return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+		}
+	}
+	if s := params.Get("enable-tidb-extension"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+		}
+		if b && cfg.Protocol != config.ProtocolCanalJSON {
+			return nil, cerror.ErrConsumerInvalidConfig.GenWithStack(
+				"enable-tidb-extension only works with canal-json")
+		}
+		cfg.EnableTiDBExtension = b
+	}
+	if s := params.Get("max-message-bytes"); s != "" {
+		if cfg.MaxMessageBytes, err = strconv.Atoi(s); err != nil {
+			return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+		}
+	}
+	if s := params.Get("max-batch-size"); s != "" {
+		if cfg.MaxBatchSize, err = strconv.Atoi(s); err != nil {
+			return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+		}
+	}
+	saslConfig := kafka.NewConfig()
+	if err := saslConfig.ApplySASL(params); err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+	}
+	cfg.SASL = saslConfig.SASL
+	if _, err := newDecoderBuilder(cfg.Protocol, cfg.EnableTiDBExtension); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg, nil
+}
+
+// newSaramaConfig creates the config of the sarama consumer group.
+func (c *Config) newSaramaConfig(ctx context.Context) (*sarama.Config, error) {
+	saramaConfig := sarama.NewConfig()
+	version, err := sarama.ParseKafkaVersion(c.Version)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+	}
+	saramaConfig.Version = version
+	saramaConfig.ClientID = fmt.Sprintf("%s_%s", defaultClientID, c.GroupID)
+	saramaConfig.Metadata.Retry.Max = 10000
+	saramaConfig.Metadata.Retry.Backoff = 500 * time.Millisecond
+	saramaConfig.Consumer.Retry.Backoff = 500 * time.Millisecond
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	if c.Credential != nil && c.Credential.IsTLSEnabled() {
+		saramaConfig.Net.TLS.Enable = true
+		saramaConfig.Net.TLS.Config, err = c.Credential.ToTLSConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := kafka.CompleteSaramaSASLConfig(ctx, saramaConfig, c.SASL); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return saramaConfig, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg, err := NewConfig("kafka://127.0.0.1:9092,127.0.0.1:9093/t1,t2")
+	require.Nil(t, err)
+	require.Equal(t, []string{"127.0.0.1:9092", "127.0.0.1:9093"}, cfg.Addrs)
+	require.Equal(t, []string{"t1", "t2"}, cfg.Topics)
+	require.Equal(t, defaultGroupID, cfg.GroupID)
+	require.Equal(t, config.ProtocolOpen, cfg.Protocol)
+	require.Equal(t, math.MaxInt64, cfg.MaxMessageBytes)
+	require.Equal(t, math.MaxInt64, cfg.MaxBatchSize)
+
+	cfg, err = NewConfig("kafka://127.0.0.1:9092/t1?consumer-group-id=g1&version=2.6.0" +
+		"&protocol=canal-json&enable-tidb-extension=true&max-message-bytes=1024&max-batch-size=16")
+	require.Nil(t, err)
+	require.Equal(t, "g1", cfg.GroupID)
+	require.Equal(t, "2.6.0", cfg.Version)
+	require.Equal(t, config.ProtocolCanalJSON, cfg.Protocol)
+	require.True(t, cfg.EnableTiDBExtension)
+	require.Equal(t, 1024, cfg.MaxMessageBytes)
+	require.Equal(t, 16, cfg.MaxBatchSize)
+	saramaConfig, err := cfg.newSaramaConfig(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "ticdc_consumer_g1", saramaConfig.ClientID)
+	require.False(t, saramaConfig.Net.SASL.Enable)
+
+	cfg, err = NewConfig("kafka://127.0.0.1:9092/t1?sasl-mechanism=oauthbearer" +
+		"&sasl-oauth-token-provider=static&sasl-oauth-token=token")
+	require.Nil(t, err)
+	saramaConfig, err = cfg.newSaramaConfig(ctx)
+	require.Nil(t, err)
+	require.True(t, saramaConfig.Net.SASL.Enable)
+	token, err := saramaConfig.Net.SASL.TokenProvider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token", token.Token)
+
+	testCases := []struct {
+		uri string
+		err string
+	}{
+		{"pulsar://127.0.0.1:6650/t1", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092/t1?protocol=unknown", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092/t1?enable-tidb-extension=true", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092/t1?max-batch-size=a", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092/t1?sasl-mechanism=oauthbearer", "ErrConsumerInvalidConfig"},
+		{"kafka://127.0.0.1:9092/t1?protocol=avro", "ErrConsumerUnsupportedProtocol"},
+		{"kafka://127.0.0.1:9092/t1?protocol=maxwell", "ErrConsumerUnsupportedProtocol"},
+		{"kafka://127.0.0.1:9092/t1?protocol=canal", "ErrConsumerUnsupportedProtocol"},
+	}
+	for _, tc := range testCases {
+		_, err := NewConfig(tc.uri)
+		require.Regexp(t, tc.err, err, "uri: %s", tc.uri)
+	}
+
+	_, err = NewConfig("kafka://127.0.0.1:9092/t1?protocol=avro")
+	require.Contains(t, err.Error(), "protocol avro is not supported by the consumer "+
+		"since it has no decoder of the protocol, the supported protocols are canal-json, craft, open-protocol")
+}
@@ -0,0 +1,828 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tiflow/cdc/contextutil"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/pingcap/tiflow/cdc/sink/mq/dispatcher"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	consumerChangefeed = "consumer"
+	checkpointInterval = 100 * time.Millisecond
+)
+
+// SinkFactory creates a downstream sink.
+type SinkFactory func(ctx context.Context, errCh chan error) (sink.Sink, error)
+
+// NewSinkFactory returns a SinkFactory which creates the sinks of the sink uri.
+func NewSinkFactory(sinkURI string) SinkFactory {
+	return func(ctx context.Context, errCh chan error) (sink.Sink, error) {
+		return sink.New(ctx, model.DefaultChangeFeedID(consumerChangefeed),
+			sinkURI, config.GetDefaultReplicaConfig(), errCh)
+	}
+}
+
+// tableIDAllocator allocates table IDs for the decoded rows. The protocols
+// don't carry the table IDs of the upstream, and the downstream sink only uses
+// them to group the rows, so a stable ID for each table name is enough.
+type tableIDAllocator struct {
+	mu     sync.Mutex
+	ids    map[string]model.TableID
+	nextID model.TableID
+}
+
+func newTableIDAllocator() *tableIDAllocator {
+	return &tableIDAllocator{ids: make(map[string]model.TableID)}
+}
+
+func (a *tableIDAllocator) allocate(table *model.TableName) model.TableID {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := quotes.QuoteSchema(table.Schema, table.Table)
+	if table.IsPartition {
+		key = fmt.Sprintf("%s.`%d`", key, table.TableID)
+	}
+	if id, ok := a.ids[key]; ok {
+		return id
+	}
+	a.nextID++
+	a.ids[key] = a.nextID
+	return a.nextID
+}
+
+type eventsGroup struct {
+	events []*model.RowChangedEvent
+}
+
+func (g *eventsGroup) append(e *model.RowChangedEvent) {
+	g.events = append(g.events, e)
+}
+
+// resolve returns the events not greater than the resolved ts, sorted by commit ts.
+func (g *eventsGroup) resolve(resolvedTs uint64) []*model.RowChangedEvent {
+	sort.SliceStable(g.events, func(i, j int) bool {
+		return g.events[i].CommitTs < g.events[j].CommitTs
+	})
+	i := sort.Search(len(g.events), func(i int) bool {
+		return g.events[i].CommitTs > resolvedTs
+	})
+	result := g.events[:i]
+	g.events = g.events[i:]
+	return result
+}
+
+// pendingEvent is an event which has not been written to the downstream,
+// its message must be consumed again after restarting.
+type pendingEvent struct {
+	offset   int64
+	commitTs uint64
+}
+
+// partitionState is the consuming state of a partition.
+type partitionState struct {
+	topic     string
+	partition int32
+	sink      sink.Sink
+
+	mu         sync.Mutex
+	resolvedTs uint64
+	// partitionNum is the partition number used to dispatch the rows,
+	// it's changed by the partition change marker.
+	partitionNum int32
+	// nextOffset is the offset of the next message, -1 if nothing is consumed.
+	nextOffset int64
+	pending    []pendingEvent
+	// tables records the tables of which rows have been emitted to the sink.
+	tables      map[model.TableID]struct{}
+	eventGroups map[model.TableID]*eventsGroup
+}
+
+// restartOffset returns the offset from which the partition should be
+// consumed to replay the events greater than the checkpoint ts.
+// It must be called with the lock held.
+func (p *partitionState) restartOffset(checkpointTs uint64) int64 {
+	offset := p.nextOffset
+	for _, e := range p.pending {
+		if e.commitTs > checkpointTs && e.offset < offset {
+			offset = e.offset
+		}
+	}
+	return offset
+}
+
+// Consumer consumes the messages produced by a TiCDC MQ sink from Kafka, and
+// writes the events to the downstream sink.
+//
+// The rows of each partition are written to a dedicated sink once the resolved
+// ts of the partition passes them, and they are flushed when all the partitions
+// of all the topics are resolved. The DDLs are executed in the order of their
+// commit ts, after all the partitions are resolved to the commit ts.
+// Whenever the partitions are flushed, the checkpoint ts and the offset from
+// which each partition should be consumed again are saved in the checkpoint
+// store, so after restarting the consumer replays the messages from the saved
+// offsets and skips the events which have been written. The checkpoints are not
+// saved atomically with the events, so before flushing, the ts to which the
+// events are flushed is saved as the flushing ts. After restarting, the replayed
+// rows not greater than the flushing ts are written in safe mode, that is, by
+// REPLACE and DELETE, so a row written before restarting is not applied twice
+// as long as the table has a primary key or a not null unique key. The replayed
+// DDLs rely on the downstream ignoring the errors of DDLs executed already.
+//
+// Only one consumer should run for a consumer group.
+type Consumer struct {
+	cfg            *Config
+	decoderBuilder DecoderBuilder
+	store          CheckpointStore
+	newSink        SinkFactory
+	tableIDs       *tableIDAllocator
+	errCh          chan error
+	// eventRouters holds the event router of each topic, the default
+	// topic of a router is the topic itself.
+	eventRouters map[string]*dispatcher.EventRouter
+
+	// partitions and ddlSink are only modified by initPartitions.
+	partitions map[string][]*partitionState
+	ddlSink    sink.Sink
+
+	// startTs is the checkpoint ts loaded when the consumer starts,
+	// the events not greater than it are skipped.
+	startTs      uint64
+	savedOffsets map[string]map[int32]int64
+	checkpointTs uint64
+	// replayTs is the flushing ts loaded when the consumer starts, the rows
+	// not greater than it may have been written and are written in safe mode.
+	replayTs uint64
+	// flushingTs is the saved flushing ts, it's only accessed by advanceCheckpoint.
+	flushingTs uint64
+
+	ddlMu sync.Mutex
+	// ddls is the DDLs to be executed, sorted by commit ts.
+	ddls []*model.DDLEvent
+	// receivedDDLs is used to remove the duplicated DDLs, since a DDL may
+	// be broadcast to all the partitions.
+	receivedDDLs map[string]struct{}
+
+	sessionMu sync.Mutex
+	session   sarama.ConsumerGroupSession
+}
+
+// New creates a Consumer.
+func New(cfg *Config, store CheckpointStore, newSink SinkFactory) (*Consumer, error) {
+	decoderBuilder, err := newDecoderBuilder(cfg.Protocol, cfg.EnableTiDBExtension)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &Consumer{
+		cfg:            cfg,
+		decoderBuilder: decoderBuilder,
+		store:          store,
+		newSink:        newSink,
+		tableIDs:       newTableIDAllocator(),
+		errCh:          make(chan error, 1),
+		partitions:     make(map[string][]*partitionState),
+		savedOffsets:   make(map[string]map[int32]int64),
+		receivedDDLs:   make(map[string]struct{}),
+	}
+	// Some protocols don't carry enough information to check the dispatched
+	// partitions, such as the open protocol which lacks the index columns,
+	// make sure the decoded rows are identical to the CDC side if it's enabled.
+	// The rows not matching any topic rule are sent to the default topic of
+	// the changefeed, which is unknown here, so they are accepted by any topic.
+	if cfg.ReplicaConfig != nil {
+		c.eventRouters = make(map[string]*dispatcher.EventRouter, len(cfg.Topics))
+		for _, topic := range cfg.Topics {
+			c.eventRouters[topic], err = dispatcher.NewEventRouter(cfg.ReplicaConfig, topic)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// initPartitions loads the checkpoints and creates the sinks of the partitions.
+func (c *Consumer) initPartitions(ctx context.Context, partitionNums map[string]int32) error {
+	checkpoints, err := c.store.Load(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, cp := range checkpoints {
+		if i == 0 || cp.CheckpointTs < c.startTs {
+			c.startTs = cp.CheckpointTs
+		}
+		if cp.FlushingTs > c.replayTs {
+			c.replayTs = cp.FlushingTs
+		}
+		if _, ok := c.savedOffsets[cp.Topic]; !ok {
+			c.savedOffsets[cp.Topic] = make(map[int32]int64)
+		}
+		c.savedOffsets[cp.Topic][cp.Partition] = cp.Offset
+	}
+	atomic.StoreUint64(&c.checkpointTs, c.startTs)
+	c.flushingTs = c.replayTs
+	log.Info("consumer checkpoints loaded",
+		zap.String("groupID", c.cfg.GroupID),
+		zap.Uint64("checkpointTs", c.startTs),
+		zap.Uint64("replayTs", c.replayTs),
+		zap.Any("offsets", c.savedOffsets))
+
+	tz, err := util.GetTimezone(c.cfg.Timezone)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrConsumerInvalidConfig, err)
+	}
+	ctx = contextutil.PutTimezoneInCtx(ctx, tz)
+	ctx = contextutil.PutRoleInCtx(ctx, util.RoleKafkaConsumer)
+	for topic, partitionNum := range partitionNums {
+		partitions := make([]*partitionState, 0, partitionNum)
+		for i := int32(0); i < partitionNum; i++ {
+			s, err := c.newSink(ctx, c.errCh)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			partitions = append(partitions, &partitionState{
+				topic:        topic,
+				partition:    i,
+				sink:         s,
+				resolvedTs:   c.startTs,
+				partitionNum: partitionNum,
+				nextOffset:   -1,
+				tables:       make(map[model.TableID]struct{}),
+				eventGroups:  make(map[model.TableID]*eventsGroup),
+			})
+		}
+		c.partitions[topic] = partitions
+	}
+	c.ddlSink, err = c.newSink(ctx, c.errCh)
+	return errors.Trace(err)
+}
+
+// Run consumes the topics until the context is canceled or an error occurs.
+func (c *Consumer) Run(ctx context.Context) error {
+	saramaConfig, err := c.cfg.newSaramaConfig(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := sarama.NewClient(c.cfg.Addrs, saramaConfig)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrKafkaNewSaramaProducer, err)
+	}
+	defer client.Close()
+
+	partitionNums := make(map[string]int32, len(c.cfg.Topics))
+	for _, topic := range c.cfg.Topics {
+		partitions, err := client.Partitions(topic)
+		if err != nil {
+			return errors.Annotatef(err, "get partitions of topic %s", topic)
+		}
+		partitionNums[topic] = int32(len(partitions))
+	}
+	if err := c.initPartitions(ctx, partitionNums); err != nil {
+		return errors.Trace(err)
+	}
+
+	group, err := sarama.NewConsumerGroupFromClient(c.cfg.GroupID, client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer group.Close()
+
+	log.Info("consumer started",
+		zap.String("groupID", c.cfg.GroupID),
+		zap.Strings("topics", c.cfg.Topics),
+		zap.Any("partitions", partitionNums),
+		zap.String("protocol", c.cfg.Protocol.String()))
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		for {
+			// `Consume` should be called inside an infinite loop, when a
+			// server-side rebalance happens, the consumer session will need to be
+			// recreated to get the new claims.
+			if err := group.Consume(ctx, c.cfg.Topics, c); err != nil {
+				return errors.Trace(err)
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	})
+	g.Go(func() error {
+		ticker := time.NewTicker(checkpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-c.errCh:
+				return errors.Trace(err)
+			case <-ticker.C:
+			}
+			if err := c.advanceCheckpoint(ctx); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	})
+	return g.Wait()
+}
+
+// Close closes the sinks and the checkpoint store.
+func (c *Consumer) Close(ctx context.Context) {
+	for _, partitions := range c.partitions {
+		for _, p := range partitions {
+			if err := p.sink.Close(ctx); err != nil {
+				log.Warn("close sink failed", zap.Error(err))
+			}
+		}
+	}
+	if c.ddlSink != nil {
+		if err := c.ddlSink.Close(ctx); err != nil {
+			log.Warn("close ddl sink failed", zap.Error(err))
+		}
+	}
+	if err := c.store.Close(); err != nil {
+		log.Warn("close checkpoint store failed", zap.Error(err))
+	}
+}
+
+func (c *Consumer) getPartition(topic string, partition int32) (*partitionState, error) {
+	partitions := c.partitions[topic]
+	if partition < 0 || int(partition) >= len(partitions) {
+		return nil, cerror.ErrConsumerInvalidConfig.GenWithStack(
+			"partition %d of topic %s is not found, the partitions may have been scaled out",
+			partition, topic)
+	}
+	return partitions[partition], nil
+}
+
+// Setup implements the sarama.ConsumerGroupHandler interface.
+// It moves the claimed partitions to the offsets from which they should be consumed.
+func (c *Consumer) Setup(session sarama.ConsumerGroupSession) error {
+	for topic, partitions := range session.Claims() {
+		for _, partition := range partitions {
+			p, err := c.getPartition(topic, partition)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			p.mu.Lock()
+			offset := p.nextOffset
+			p.mu.Unlock()
+			if offset < 0 {
+				saved, ok := c.savedOffsets[topic][partition]
+				if !ok {
+					continue
+				}
+				offset = saved
+			}
+			// ResetOffset only moves the offset backward and MarkOffset only
+			// moves it forward, one of them takes effect.
+			session.ResetOffset(topic, partition, offset, "")
+			session.MarkOffset(topic, partition, offset, "")
+			log.Info("consume partition from offset",
+				zap.String("topic", topic),
+				zap.Int32("partition", partition),
+				zap.Int64("offset", offset))
+		}
+	}
+	c.sessionMu.Lock()
+	c.session = session
+	c.sessionMu.Unlock()
+	return nil
+}
+
+// Cleanup implements the sarama.ConsumerGroupHandler interface.
+func (c *Consumer) Cleanup(sarama.ConsumerGroupSession) error {
+	c.sessionMu.Lock()
+	c.session = nil
+	c.sessionMu.Unlock()
+	return nil
+}
+
+// ConsumeClaim implements the sarama.ConsumerGroupHandler interface.
+func (c *Consumer) ConsumeClaim(
+	session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim,
+) error {
+	p, err := c.getPartition(claim.Topic(), claim.Partition())
+	if err != nil {
+		return c.reportError(err)
+	}
+	counter := receivedMessageCounter.WithLabelValues(
+		claim.Topic(), strconv.Itoa(int(claim.Partition())))
+	for message := range claim.Messages() {
+		counter.Inc()
+		if isPartitionChangeMarker(message) {
+			err = c.handlePartitionChangeMarker(p, message.Offset, message.Value)
+		} else {
+			err = c.handleMessage(session.Context(), p, message.Offset, message.Key, message.Value)
+		}
+		if err != nil {
+			return c.reportError(err)
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) reportError(err error) error {
+	select {
+	case c.errCh <- err:
+	default:
+		log.Error("error channel is full", zap.Error(err))
+	}
+	return errors.Trace(err)
+}
+
+func (c *Consumer) handleMessage(
+	ctx context.Context, p *partitionState, offset int64, key, value []byte,
+) error {
+	decoder, err := c.decoderBuilder(key, value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	counter := 0
+	for {
+		tp, hasNext, err := decoder.HasNext()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !hasNext {
+			break
+		}
+		counter++
+		// A message containing only one event is allowed to exceed the limit.
+		if len(key)+len(value) > c.cfg.MaxMessageBytes && counter > 1 {
+			return cerror.ErrConsumerInvalidConfig.GenWithStack(
+				"max-message-bytes %d exceeded, received %d bytes",
+				c.cfg.MaxMessageBytes, len(key)+len(value))
+		}
+		receivedEventCounter.WithLabelValues(p.topic, strconv.Itoa(int(tp))).Inc()
+
+		switch tp {
+		case model.MessageTypeDDL:
+			ddl, err := decoder.NextDDLEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if ddl.CommitTs <= c.startTs {
+				skippedEventCounter.WithLabelValues(p.topic).Inc()
+				continue
+			}
+			p.pending = append(p.pending, pendingEvent{offset: offset, commitTs: ddl.CommitTs})
+			if err := c.appendDDL(ddl); err != nil {
+				return errors.Trace(err)
+			}
+		case model.MessageTypeRow:
+			row, err := decoder.NextRowChangedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err := codec.VerifyRowChecksum(row); err != nil {
+				return errors.Trace(err)
+			}
+			if row.CommitTs <= c.startTs {
+				skippedEventCounter.WithLabelValues(p.topic).Inc()
+				continue
+			}
+			if router, ok := c.eventRouters[p.topic]; ok {
+				if topic := router.GetTopicForRowChange(row); topic != p.topic {
+					return cerror.ErrConsumerEventOutOfOrder.GenWithStackByArgs(fmt.Sprintf(
+						"row of %s is dispatched to topic %s, but expected %s",
+						row.Table, p.topic, topic))
+				}
+				target := router.GetPartitionForRowChange(row, p.partitionNum)
+				if target != p.partition {
+					return cerror.ErrConsumerEventOutOfOrder.GenWithStackByArgs(fmt.Sprintf(
+						"row of %s is dispatched to partition %d, but expected %d",
+						row.Table, p.partition, target))
+				}
+			}
+			if row.CommitTs <= p.resolvedTs {
+				log.Warn("row fallback, ignore it",
+					zap.String("topic", p.topic),
+					zap.Int32("partition", p.partition),
+					zap.Uint64("commitTs", row.CommitTs),
+					zap.Uint64("resolvedTs", p.resolvedTs),
+					zap.Stringer("table", row.Table))
+				continue
+			}
+			// start-ts is not contained in some protocols.
+			if row.StartTs == 0 {
+				row.StartTs = row.CommitTs
+			}
+			// The row may have been written before restarting, write it in
+			// safe mode to avoid applying it twice.
+			if row.CommitTs <= c.replayTs {
+				row.ReplicatingTs = c.replayTs
+			}
+			row.Table.TableID = c.tableIDs.allocate(row.Table)
+			group, ok := p.eventGroups[row.Table.TableID]
+			if !ok {
+				group = &eventsGroup{}
+				p.eventGroups[row.Table.TableID] = group
+			}
+			group.append(row)
+			p.pending = append(p.pending, pendingEvent{offset: offset, commitTs: row.CommitTs})
+		case model.MessageTypeResolved:
+			ts, err := decoder.NextResolvedEvent()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if ts <= p.resolvedTs {
+				// Redundant resolved events are allowed, and the ones not greater
+				// than the checkpoint ts are replayed after restarting.
+				if ts < p.resolvedTs && ts > c.startTs {
+					return cerror.ErrConsumerEventOutOfOrder.GenWithStackByArgs(fmt.Sprintf(
+						"resolved ts of partition %d of topic %s falls back from %d to %d",
+						p.partition, p.topic, p.resolvedTs, ts))
+				}
+				continue
+			}
+			for tableID, group := range p.eventGroups {
+				events := group.resolve(ts)
+				if len(events) == 0 {
+					continue
+				}
+				if err := p.sink.EmitRowChangedEvents(ctx, events...); err != nil {
+					return errors.Trace(err)
+				}
+				p.tables[tableID] = struct{}{}
+			}
+			p.resolvedTs = ts
+			partitionResolvedTsGauge.WithLabelValues(p.topic, strconv.Itoa(int(p.partition))).
+				Set(float64(oracle.ExtractPhysical(ts)))
+		}
+	}
+	if counter > c.cfg.MaxBatchSize {
+		return cerror.ErrConsumerInvalidConfig.GenWithStack(
+			"max-batch-size %d exceeded, received %d events", c.cfg.MaxBatchSize, counter)
+	}
+	p.nextOffset = offset + 1
+	return nil
+}
+
+// isPartitionChangeMarker returns true if the message is a partition change marker.
+func isPartitionChangeMarker(message *sarama.ConsumerMessage) bool {
+	for _, header := range message.Headers {
+		if string(header.Key) == codec.PartitionChangeMarkerHeader {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePartitionChangeMarker switches the partition number used to check
+// the dispatched rows. The rows after the marker are dispatched with the
+// new partition number.
+func (c *Consumer) handlePartitionChangeMarker(
+	p *partitionState, offset int64, value []byte,
+) error {
+	marker, err := codec.DecodePartitionChangeMarker(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if marker.PartitionNum > int32(len(c.partitions[p.topic])) {
+		return cerror.ErrConsumerPartitionNumChanged.GenWithStackByArgs(
+			p.topic, len(c.partitions[p.topic]), marker.PartitionNum)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.partitionNum != marker.PartitionNum {
+		log.Info("partition number changed",
+			zap.String("topic", p.topic),
+			zap.Int32("partition", p.partition),
+			zap.Int32("oldPartitionNum", p.partitionNum),
+			zap.Int32("partitionNum", marker.PartitionNum),
+			zap.Uint64("ts", marker.Ts))
+		p.partitionNum = marker.PartitionNum
+	}
+	p.nextOffset = offset + 1
+	return nil
+}
+
+// appendDDL adds the DDL to the list of DDLs to be executed,
+// the duplicated ones are ignored.
+func (c *Consumer) appendDDL(ddl *model.DDLEvent) error {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	key := fmt.Sprintf("%d-%s", ddl.CommitTs, ddl.Query)
+	if _, ok := c.receivedDDLs[key]; ok {
+		return nil
+	}
+	checkpointTs := atomic.LoadUint64(&c.checkpointTs)
+	if ddl.CommitTs <= checkpointTs {
+		return cerror.ErrConsumerEventOutOfOrder.GenWithStackByArgs(fmt.Sprintf(
+			"DDL %s with commit ts %d is received after the checkpoint ts %d",
+			ddl.Query, ddl.CommitTs, checkpointTs))
+	}
+	c.receivedDDLs[key] = struct{}{}
+	// A rename tables DDL job contains multiple DDL events with the same
+	// commit ts, so the stable sort keeps them in the order received.
+	c.ddls = append(c.ddls, ddl)
+	sort.SliceStable(c.ddls, func(i, j int) bool {
+		return c.ddls[i].CommitTs < c.ddls[j].CommitTs
+	})
+	log.Info("DDL event received", zap.Uint64("commitTs", ddl.CommitTs), zap.String("query", ddl.Query))
+	return nil
+}
+
+func (c *Consumer) frontDDL() *model.DDLEvent {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	if len(c.ddls) == 0 {
+		return nil
+	}
+	return c.ddls[0]
+}
+
+func (c *Consumer) popDDL() {
+	c.ddlMu.Lock()
+	defer c.ddlMu.Unlock()
+	ddl := c.ddls[0]
+	c.ddls = c.ddls[1:]
+	delete(c.receivedDDLs, fmt.Sprintf("%d-%s", ddl.CommitTs, ddl.Query))
+}
+
+func (c *Consumer) minResolvedTs() uint64 {
+	result := uint64(math.MaxUint64)
+	for _, partitions := range c.partitions {
+		for _, p := range partitions {
+			p.mu.Lock()
+			if p.resolvedTs < result {
+				result = p.resolvedTs
+			}
+			p.mu.Unlock()
+		}
+	}
+	return result
+}
+
+// advanceCheckpoint executes the DDLs and flushes the rows
+// which all the partitions are resolved to, then saves the checkpoints.
+func (c *Consumer) advanceCheckpoint(ctx context.Context) error {
+	minResolvedTs := c.minResolvedTs()
+	for {
+		ddl := c.frontDDL()
+		if ddl == nil || ddl.CommitTs > minResolvedTs {
+			break
+		}
+		if err := c.saveFlushingTs(ctx, ddl.CommitTs); err != nil {
+			return errors.Trace(err)
+		}
+		if err := c.flush(ctx, ddl.CommitTs); err != nil {
+			return errors.Trace(err)
+		}
+		start := time.Now()
+		if err := c.ddlSink.EmitDDLEvent(ctx, ddl); err != nil {
+			return errors.Trace(err)
+		}
+		ddlExecDurationHistogram.Observe(time.Since(start).Seconds())
+		c.popDDL()
+		if err := c.saveCheckpoint(ctx, ddl.CommitTs); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if minResolvedTs <= atomic.LoadUint64(&c.checkpointTs) {
+		return nil
+	}
+	if err := c.saveFlushingTs(ctx, minResolvedTs); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.flush(ctx, minResolvedTs); err != nil {
+		return errors.Trace(err)
+	}
+	return c.saveCheckpoint(ctx, minResolvedTs)
+}
+
+// saveFlushingTs saves the flushing ts with the current checkpoints before
+// the events not greater than it are written to the downstream.
+func (c *Consumer) saveFlushingTs(ctx context.Context, flushingTs uint64) error {
+	if flushingTs <= c.flushingTs {
+		return nil
+	}
+	c.flushingTs = flushingTs
+	return c.saveCheckpoint(ctx, atomic.LoadUint64(&c.checkpointTs))
+}
+
+// flush flushes the rows not greater than the resolved ts of all the partitions.
+func (c *Consumer) flush(ctx context.Context, resolvedTs uint64) error {
+	for _, partitions := range c.partitions {
+		for _, p := range partitions {
+			p.mu.Lock()
+			tables := make([]model.TableID, 0, len(p.tables))
+			for tableID := range p.tables {
+				tables = append(tables, tableID)
+			}
+			p.mu.Unlock()
+			for _, tableID := range tables {
+				if err := syncFlushRowChangedEvents(ctx, p.sink, tableID, resolvedTs); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func syncFlushRowChangedEvents(
+	ctx context.Context, s sink.Sink, tableID model.TableID, resolvedTs uint64,
+) error {
+	for {
+		checkpoint, err := s.FlushRowChangedEvents(ctx, tableID, model.NewResolvedTs(resolvedTs))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if checkpoint.Ts >= resolvedTs {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// saveCheckpoint saves the checkpoints of all the partitions
+// after the events not greater than checkpointTs have been written.
+func (c *Consumer) saveCheckpoint(ctx context.Context, checkpointTs uint64) error {
+	var checkpoints []Checkpoint
+	for topic, partitions := range c.partitions {
+		for _, p := range partitions {
+			p.mu.Lock()
+			offset := p.restartOffset(checkpointTs)
+			pending := p.pending[:0]
+			for _, e := range p.pending {
+				if e.commitTs > checkpointTs {
+					pending = append(pending, e)
+				}
+			}
+			p.pending = pending
+			p.mu.Unlock()
+			if offset < 0 {
+				saved, ok := c.savedOffsets[topic][p.partition]
+				if !ok {
+					continue
+				}
+				offset = saved
+			}
+			checkpoints = append(checkpoints, Checkpoint{
+				Topic:        topic,
+				Partition:    p.partition,
+				Offset:       offset,
+				CheckpointTs: checkpointTs,
+				FlushingTs:   c.flushingTs,
+			})
+		}
+	}
+	if err := c.store.Save(ctx, checkpoints); err != nil {
+		return errors.Trace(err)
+	}
+	atomic.StoreUint64(&c.checkpointTs, checkpointTs)
+	physical := oracle.ExtractPhysical(checkpointTs)
+	checkpointTsGauge.Set(float64(physical))
+	checkpointLagGauge.Set(float64(oracle.GetPhysical(time.Now())-physical) / 1e3)
+
+	// The offsets of the consumer group never exceed the saved ones.
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	if c.session != nil {
+		for _, cp := range checkpoints {
+			c.session.MarkOffset(cp.Topic, cp.Partition, cp.Offset, "")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,488 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/pingcap/errors"
+	timodel "github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink"
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// downstream is a table keyed by the id column, it fails to insert a row
+// which exists already, as a MySQL compatible database does.
+type downstream struct {
+	mu   sync.Mutex
+	rows map[int64]uint64
+}
+
+func newDownstream() *downstream {
+	return &downstream{rows: make(map[int64]uint64)}
+}
+
+func (d *downstream) apply(row *model.RowChangedEvent) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := row.Columns[0].Value.(int64)
+	// The rows are written by REPLACE in safe mode.
+	if _, ok := d.rows[id]; ok && row.CommitTs > row.ReplicatingTs {
+		return errors.Errorf("duplicate entry %d", id)
+	}
+	d.rows[id] = row.CommitTs
+	return nil
+}
+
+// memorySink records the events written to it.
+type memorySink struct {
+	mu   sync.Mutex
+	rows []*model.RowChangedEvent
+	ddls []*model.DDLEvent
+	// flushed records the resolved ts each table is flushed to.
+	flushed map[model.TableID]uint64
+	// downstream, if not nil, is where the rows are written when flushing.
+	downstream *downstream
+	unflushed  []*model.RowChangedEvent
+}
+
+var _ sink.Sink = (*memorySink)(nil)
+
+func (s *memorySink) AddTable(_ model.TableID) error {
+	return nil
+}
+
+func (s *memorySink) EmitRowChangedEvents(_ context.Context, rows ...*model.RowChangedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = append(s.rows, rows...)
+	s.unflushed = append(s.unflushed, rows...)
+	return nil
+}
+
+func (s *memorySink) EmitDDLEvent(_ context.Context, ddl *model.DDLEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ddls = append(s.ddls, ddl)
+	return nil
+}
+
+func (s *memorySink) FlushRowChangedEvents(
+	_ context.Context, tableID model.TableID, resolved model.ResolvedTs,
+) (model.ResolvedTs, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unflushed := s.unflushed[:0]
+	for _, row := range s.unflushed {
+		if row.Table.TableID != tableID || row.CommitTs > resolved.Ts || s.downstream == nil {
+			unflushed = append(unflushed, row)
+			continue
+		}
+		if err := s.downstream.apply(row); err != nil {
+			return model.ResolvedTs{}, err
+		}
+	}
+	s.unflushed = unflushed
+	s.flushed[tableID] = resolved.Ts
+	return resolved, nil
+}
+
+func (s *memorySink) EmitCheckpointTs(_ context.Context, _ uint64, _ []model.TableName) error {
+	return nil
+}
+
+func (s *memorySink) Close(_ context.Context) error {
+	return nil
+}
+
+func (s *memorySink) RemoveTable(_ context.Context, _ model.TableID) error {
+	return nil
+}
+
+type mockSession struct {
+	ctx    context.Context
+	claims map[string][]int32
+	// offsets records the offsets reset or marked for each partition.
+	offsets map[string]map[int32]int64
+}
+
+func newMockSession(ctx context.Context, claims map[string][]int32) *mockSession {
+	return &mockSession{
+		ctx:     ctx,
+		claims:  claims,
+		offsets: make(map[string]map[int32]int64),
+	}
+}
+
+func (s *mockSession) Claims() map[string][]int32 { return s.claims }
+func (s *mockSession) MemberID() string           { return "member" }
+func (s *mockSession) GenerationID() int32        { return 1 }
+func (s *mockSession) Commit()                    {}
+func (s *mockSession) Context() context.Context   { return s.ctx }
+
+func (s *mockSession) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	if _, ok := s.offsets[topic]; !ok {
+		s.offsets[topic] = make(map[int32]int64)
+	}
+	s.offsets[topic][partition] = offset
+}
+
+func (s *mockSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	s.MarkOffset(topic, partition, offset, metadata)
+}
+
+func (s *mockSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+type mockClaim struct {
+	topic     string
+	partition int32
+	messages  chan *sarama.ConsumerMessage
+}
+
+func (c *mockClaim) Topic() string                            { return c.topic }
+func (c *mockClaim) Partition() int32                         { return c.partition }
+func (c *mockClaim) InitialOffset() int64                     { return 0 }
+func (c *mockClaim) HighWaterMarkOffset() int64               { return 0 }
+func (c *mockClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// messageBuilder encodes the events with the open protocol.
+type messageBuilder struct {
+	t       *testing.T
+	encoder codec.EventBatchEncoder
+}
+
+func newMessageBuilder(t *testing.T) *messageBuilder {
+	builder, err := codec.NewEventBatchEncoderBuilder(
+		context.Background(), codec.NewConfig(config.ProtocolOpen))
+	require.Nil(t, err)
+	return &messageBuilder{t: t, encoder: builder.Build()}
+}
+
+func (b *messageBuilder) ddl(commitTs uint64, table string) *codec.MQMessage {
+	msg, err := b.encoder.EncodeDDLEvent(&model.DDLEvent{
+		CommitTs:  commitTs,
+		Query:     "create table test." + table + "(id int primary key)",
+		Type:      timodel.ActionCreateTable,
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: table},
+	})
+	require.Nil(b.t, err)
+	return msg
+}
+
+func (b *messageBuilder) row(commitTs uint64, table string) *codec.MQMessage {
+	err := b.encoder.AppendRowChangedEvent(context.Background(), "", &model.RowChangedEvent{
+		CommitTs: commitTs,
+		Table:    &model.TableName{Schema: "test", Table: table},
+		Columns: []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: int64(commitTs),
+		}},
+	}, nil)
+	require.Nil(b.t, err)
+	msgs := b.encoder.Build()
+	require.Len(b.t, msgs, 1)
+	return msgs[0]
+}
+
+func (b *messageBuilder) resolved(ts uint64) *codec.MQMessage {
+	msg, err := b.encoder.EncodeCheckpointEvent(ts)
+	require.Nil(b.t, err)
+	return msg
+}
+
+type testConsumer struct {
+	*Consumer
+	sinks []*memorySink
+}
+
+func newTestConsumer(t *testing.T, store CheckpointStore) *testConsumer {
+	cfg, err := NewConfig("kafka://127.0.0.1:9092/t1,t2")
+	require.Nil(t, err)
+	return newTestConsumerWithConfig(t, cfg, store, nil)
+}
+
+func newTestConsumerWithConfig(
+	t *testing.T, cfg *Config, store CheckpointStore, d *downstream,
+) *testConsumer {
+	tc := &testConsumer{}
+	c, err := New(cfg, store, func(_ context.Context, _ chan error) (sink.Sink, error) {
+		s := &memorySink{flushed: make(map[model.TableID]uint64), downstream: d}
+		tc.sinks = append(tc.sinks, s)
+		return s, nil
+	})
+	require.Nil(t, err)
+	tc.Consumer = c
+	require.Nil(t, c.initPartitions(context.Background(),
+		map[string]int32{"t1": 2, "t2": 1}))
+	return tc
+}
+
+func (tc *testConsumer) feed(
+	t *testing.T, topic string, partition int32, offset int64, msgs ...*codec.MQMessage,
+) {
+	p, err := tc.getPartition(topic, partition)
+	require.Nil(t, err)
+	for i, msg := range msgs {
+		err := tc.handleMessage(context.Background(), p, offset+int64(i), msg.Key, msg.Value)
+		require.Nil(t, err)
+	}
+}
+
+func (tc *testConsumer) partitionSink(topic string, partition int32) *memorySink {
+	p, _ := tc.getPartition(topic, partition)
+	return p.sink.(*memorySink)
+}
+
+func (tc *testConsumer) ddlSinkRecords() []*model.DDLEvent {
+	return tc.ddlSink.(*memorySink).ddls
+}
+
+func loadCheckpoints(t *testing.T, store CheckpointStore) (uint64, map[string]map[int32]int64) {
+	checkpoints, err := store.Load(context.Background())
+	require.Nil(t, err)
+	offsets := make(map[string]map[int32]int64)
+	var checkpointTs uint64
+	for _, cp := range checkpoints {
+		checkpointTs = cp.CheckpointTs
+		if _, ok := offsets[cp.Topic]; !ok {
+			offsets[cp.Topic] = make(map[int32]int64)
+		}
+		offsets[cp.Topic][cp.Partition] = cp.Offset
+	}
+	return checkpointTs, offsets
+}
+
+func TestConsumerCheckpointAndDDLOrder(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCheckpointStore()
+	tc := newTestConsumer(t, store)
+	b := newMessageBuilder(t)
+
+	// The DDL is broadcast to both partitions of t1.
+	tc.feed(t, "t1", 0, 0, b.ddl(100, "a"), b.row(110, "a"), b.resolved(120))
+	tc.feed(t, "t1", 1, 0, b.ddl(100, "a"), b.resolved(105))
+	tc.feed(t, "t2", 0, 0, b.resolved(90))
+
+	// t2 is only resolved to 90, so the DDL can't be executed.
+	require.Nil(t, tc.advanceCheckpoint(ctx))
+	require.Empty(t, tc.ddlSinkRecords())
+	checkpointTs, offsets := loadCheckpoints(t, store)
+	require.Equal(t, uint64(90), checkpointTs)
+	require.Equal(t, map[string]map[int32]int64{
+		"t1": {0: 0, 1: 0},
+		"t2": {0: 1},
+	}, offsets)
+
+	// The DDL is executed only once after all the partitions are resolved,
+	// the row of t1 partition 0 is emitted but not flushed yet.
+	tc.feed(t, "t2", 0, 1, b.resolved(125))
+	require.Nil(t, tc.advanceCheckpoint(ctx))
+	require.Len(t, tc.ddlSinkRecords(), 1)
+	require.Equal(t, uint64(100), tc.ddlSinkRecords()[0].CommitTs)
+	require.Len(t, tc.partitionSink("t1", 0).rows, 1)
+	checkpointTs, offsets = loadCheckpoints(t, store)
+	require.Equal(t, uint64(105), checkpointTs)
+	require.Equal(t, map[string]map[int32]int64{
+		"t1": {0: 1, 1: 2},
+		"t2": {0: 2},
+	}, offsets)
+
+	tc.feed(t, "t1", 1, 2, b.row(115, "b"), b.resolved(130))
+	require.Nil(t, tc.advanceCheckpoint(ctx))
+	checkpointTs, offsets = loadCheckpoints(t, store)
+	require.Equal(t, uint64(120), checkpointTs)
+	require.Equal(t, map[string]map[int32]int64{
+		"t1": {0: 3, 1: 4},
+		"t2": {0: 2},
+	}, offsets)
+	rowA := tc.partitionSink("t1", 0).rows[0]
+	rowB := tc.partitionSink("t1", 1).rows[0]
+	require.Equal(t, uint64(110), rowA.CommitTs)
+	require.Equal(t, uint64(115), rowB.CommitTs)
+	require.NotEqual(t, rowA.Table.TableID, rowB.Table.TableID)
+	require.Equal(t, uint64(120), tc.partitionSink("t1", 0).flushed[rowA.Table.TableID])
+	require.Equal(t, uint64(120), tc.partitionSink("t1", 1).flushed[rowB.Table.TableID])
+
+	// After restarting, the partitions are consumed from the saved offsets,
+	// and the events which have been written are skipped.
+	tc = newTestConsumer(t, store)
+	require.Equal(t, uint64(120), tc.startTs)
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	session := newMockSession(sessionCtx, map[string][]int32{"t1": {0, 1}, "t2": {0}})
+	require.Nil(t, tc.Setup(session))
+	require.Equal(t, offsets, session.offsets)
+
+	claim := &mockClaim{topic: "t1", partition: 0, messages: make(chan *sarama.ConsumerMessage, 4)}
+	for i, msg := range []*codec.MQMessage{
+		b.ddl(100, "a"), b.row(110, "a"), b.resolved(120), b.row(140, "a"),
+	} {
+		claim.messages <- &sarama.ConsumerMessage{
+			Topic: "t1", Partition: 0, Offset: int64(i), Key: msg.Key, Value: msg.Value,
+		}
+	}
+	close(claim.messages)
+	require.Nil(t, tc.ConsumeClaim(session, claim))
+	require.Empty(t, tc.ddlSinkRecords())
+	p, err := tc.getPartition("t1", 0)
+	require.Nil(t, err)
+	require.Len(t, p.eventGroups, 1)
+	for _, group := range p.eventGroups {
+		require.Len(t, group.events, 1)
+		require.Equal(t, uint64(140), group.events[0].CommitTs)
+	}
+	require.Equal(t, int64(4), p.nextOffset)
+	require.Nil(t, tc.Cleanup(session))
+}
+
+func TestConsumerResolvedTsFallback(t *testing.T) {
+	tc := newTestConsumer(t, NewMemoryCheckpointStore())
+	b := newMessageBuilder(t)
+	tc.feed(t, "t1", 0, 0, b.resolved(150), b.resolved(150))
+
+	p, err := tc.getPartition("t1", 0)
+	require.Nil(t, err)
+	msg := b.resolved(140)
+	err = tc.handleMessage(context.Background(), p, 2, msg.Key, msg.Value)
+	require.True(t, cerror.ErrConsumerEventOutOfOrder.Equal(err))
+
+	_, err = tc.getPartition("t1", 2)
+	require.True(t, cerror.ErrConsumerInvalidConfig.Equal(err))
+}
+
+func TestConsumerPartitionChangeMarker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := newTestConsumer(t, NewMemoryCheckpointStore())
+	session := newMockSession(ctx, map[string][]int32{"t1": {0, 1}, "t2": {0}})
+	p, err := tc.getPartition("t1", 1)
+	require.Nil(t, err)
+	p.partitionNum = 1
+
+	newClaim := func(offset int64, partitionNum int32) *mockClaim {
+		msg, err := codec.NewPartitionChangeMarkerMessage(&codec.PartitionChangeMarker{
+			Ts: 100, OldPartitionNum: 1, PartitionNum: partitionNum,
+		})
+		require.Nil(t, err)
+		claim := &mockClaim{topic: "t1", partition: 1, messages: make(chan *sarama.ConsumerMessage, 1)}
+		claim.messages <- &sarama.ConsumerMessage{
+			Topic: "t1", Partition: 1, Offset: offset, Value: msg.Value,
+			Headers: []*sarama.RecordHeader{{
+				Key:   []byte(codec.PartitionChangeMarkerHeader),
+				Value: msg.Headers[codec.PartitionChangeMarkerHeader],
+			}},
+		}
+		close(claim.messages)
+		return claim
+	}
+
+	require.Nil(t, tc.ConsumeClaim(session, newClaim(3, 2)))
+	require.Equal(t, int32(2), p.partitionNum)
+	require.Equal(t, int64(4), p.nextOffset)
+
+	// The consumer must be restarted to consume the new partitions.
+	err = tc.ConsumeClaim(session, newClaim(4, 4))
+	require.True(t, cerror.ErrConsumerPartitionNumChanged.Equal(err))
+	require.Equal(t, int32(2), p.partitionNum)
+	require.Equal(t, int64(4), p.nextOffset)
+}
+
+// failingStore fails to save the checkpoints not less than failTs.
+type failingStore struct {
+	CheckpointStore
+	failTs uint64
+}
+
+func (s *failingStore) Save(ctx context.Context, checkpoints []Checkpoint) error {
+	for _, cp := range checkpoints {
+		if cp.CheckpointTs >= s.failTs {
+			return errors.New("injected error")
+		}
+	}
+	return s.CheckpointStore.Save(ctx, checkpoints)
+}
+
+func TestConsumerReplayAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	cfg, err := NewConfig("kafka://127.0.0.1:9092/t1,t2")
+	require.Nil(t, err)
+	store := NewMemoryCheckpointStore()
+	d := newDownstream()
+	tc := newTestConsumerWithConfig(t, cfg, &failingStore{CheckpointStore: store, failTs: 120}, d)
+	b := newMessageBuilder(t)
+
+	// The consumer exits after the row is written but before the checkpoints are saved.
+	tc.feed(t, "t1", 0, 0, b.row(110, "a"), b.resolved(120))
+	tc.feed(t, "t1", 1, 0, b.resolved(120))
+	tc.feed(t, "t2", 0, 0, b.resolved(120))
+	require.Error(t, tc.advanceCheckpoint(ctx))
+	require.Equal(t, map[int64]uint64{110: 110}, d.rows)
+	checkpoints, err := store.Load(ctx)
+	require.Nil(t, err)
+	require.Len(t, checkpoints, 3)
+	for _, cp := range checkpoints {
+		require.Equal(t, uint64(0), cp.CheckpointTs)
+		require.Equal(t, uint64(120), cp.FlushingTs)
+	}
+
+	// After restarting, the written row is replayed in safe mode,
+	// so it is not applied twice.
+	tc = newTestConsumerWithConfig(t, cfg, store, d)
+	require.Equal(t, uint64(120), tc.replayTs)
+	tc.feed(t, "t1", 0, 0, b.row(110, "a"), b.resolved(120), b.row(130, "a"), b.resolved(140))
+	tc.feed(t, "t1", 1, 1, b.resolved(140))
+	tc.feed(t, "t2", 0, 1, b.resolved(140))
+	require.Nil(t, tc.advanceCheckpoint(ctx))
+	rows := tc.partitionSink("t1", 0).rows
+	require.Len(t, rows, 2)
+	require.Equal(t, uint64(120), rows[0].ReplicatingTs)
+	require.Equal(t, uint64(0), rows[1].ReplicatingTs)
+	require.Equal(t, map[int64]uint64{110: 110, 130: 130}, d.rows)
+	checkpointTs, _ := loadCheckpoints(t, store)
+	require.Equal(t, uint64(140), checkpointTs)
+}
+
+func TestConsumerCheckDispatchedTopic(t *testing.T) {
+	cfg, err := NewConfig("kafka://127.0.0.1:9092/t1,t2")
+	require.Nil(t, err)
+	cfg.ReplicaConfig = config.GetDefaultReplicaConfig()
+	cfg.ReplicaConfig.Sink.DispatchRules = []*config.DispatchRule{
+		{Matcher: []string{"test.a"}, PartitionRule: "table", TopicRule: "t1"},
+		{Matcher: []string{"test.b"}, PartitionRule: "table", TopicRule: "t2"},
+	}
+	tc := newTestConsumerWithConfig(t, cfg, NewMemoryCheckpointStore(), nil)
+	require.Len(t, tc.eventRouters, 2)
+	b := newMessageBuilder(t)
+
+	// The rows not matching any topic rule are accepted by any topic.
+	tc.feed(t, "t2", 0, 0, b.row(100, "b"), b.row(100, "c"))
+	p, err := tc.getPartition("t1", 0)
+	require.Nil(t, err)
+	msg := b.row(100, "b")
+	err = tc.handleMessage(context.Background(), p, 0, msg.Key, msg.Value)
+	require.True(t, cerror.ErrConsumerEventOutOfOrder.Equal(err))
+	require.Contains(t, err.Error(), "is dispatched to topic t1, but expected t2")
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tiflow/cdc/sink/mq/codec"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+)
+
+// DecoderBuilder builds a decoder for the key and value of a message.
+type DecoderBuilder func(key, value []byte) (codec.EventBatchDecoder, error)
+
+// DecoderFactory creates the DecoderBuilder of a protocol.
+type DecoderFactory func(enableTiDBExtension bool) DecoderBuilder
+
+// decoderFactories holds the decoders of the open protocol, craft and
+// canal-json by default. There are no decoders of avro, maxwell and canal in
+// TiCDC, so the consumer config of them is rejected with
+// ErrConsumerUnsupportedProtocol unless they are registered by RegisterDecoder.
+var (
+	decoderFactoriesMu sync.RWMutex
+	decoderFactories   = map[config.Protocol]DecoderFactory{
+		config.ProtocolDefault: newOpenProtocolDecoderBuilder,
+		config.ProtocolOpen:    newOpenProtocolDecoderBuilder,
+		config.ProtocolCraft: func(_ bool) DecoderBuilder {
+			return func(_, value []byte) (codec.EventBatchDecoder, error) {
+				return codec.NewCraftBatchDecoder(value)
+			}
+		},
+		config.ProtocolCanalJSON: func(enableTiDBExtension bool) DecoderBuilder {
+			return func(_, value []byte) (codec.EventBatchDecoder, error) {
+				return codec.NewCanalJSONBatchDecoder(value, enableTiDBExtension), nil
+			}
+		},
+	}
+)
+
+func newOpenProtocolDecoderBuilder(_ bool) DecoderBuilder {
+	return codec.NewOpenProtocolBatchDecoder
+}
+
+// RegisterDecoder registers the decoder factory of the protocol,
+// the existing one of the protocol is replaced.
+func RegisterDecoder(protocol config.Protocol, factory DecoderFactory) {
+	decoderFactoriesMu.Lock()
+	defer decoderFactoriesMu.Unlock()
+	decoderFactories[protocol] = factory
+}
+
+func newDecoderBuilder(protocol config.Protocol, enableTiDBExtension bool) (DecoderBuilder, error) {
+	decoderFactoriesMu.RLock()
+	defer decoderFactoriesMu.RUnlock()
+	factory, ok := decoderFactories[protocol]
+	if !ok {
+		return nil, cerror.ErrConsumerUnsupportedProtocol.GenWithStackByArgs(
+			protocol.String(), supportedProtocols())
+	}
+	return factory(enableTiDBExtension), nil
+}
+
+// supportedProtocols returns the protocols which have decoders.
+// It must be called with decoderFactoriesMu held.
+func supportedProtocols() string {
+	protocols := make([]string, 0, len(decoderFactories))
+	for protocol := range decoderFactories {
+		if protocol == config.ProtocolDefault {
+			continue
+		}
+		protocols = append(protocols, protocol.String())
+	}
+	sort.Strings(protocols)
+	return strings.Join(protocols, ", ")
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// receivedMessageCounter counts the messages received from each partition.
+	receivedMessageCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "received_message_count",
+			Help:      "The number of messages received from the partition.",
+		}, []string{"topic", "partition"})
+
+	// receivedEventCounter counts the events decoded from the messages.
+	receivedEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "received_event_count",
+			Help:      "The number of events decoded from the messages.",
+		}, []string{"topic", "type"}) // type is for `model.MessageType`
+
+	// skippedEventCounter counts the events skipped since they have been written
+	// to the downstream before restarting.
+	skippedEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "skipped_event_count",
+			Help:      "The number of events which are not greater than the checkpoint ts.",
+		}, []string{"topic"})
+
+	// partitionResolvedTsGauge records the resolved ts of each partition.
+	partitionResolvedTsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "partition_resolved_ts",
+			Help:      "The resolved ts (physical time in ms) of the partition.",
+		}, []string{"topic", "partition"})
+
+	// checkpointTsGauge records the checkpoint ts of the consumer.
+	checkpointTsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "checkpoint_ts",
+			Help:      "The checkpoint ts (physical time in ms) of the consumer.",
+		})
+
+	// checkpointLagGauge records the lag between now and the checkpoint ts.
+	checkpointLagGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "checkpoint_ts_lag",
+			Help:      "The lag (in seconds) between now and the checkpoint ts.",
+		})
+
+	// ddlExecDurationHistogram records the execution time of the DDLs.
+	ddlExecDurationHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "consumer",
+			Name:      "ddl_exec_duration",
+			Help:      "Bucketed histogram of processing time (s) of a ddl.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 18),
+		})
+)
+
+// InitMetrics registers all metrics in this file.
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(receivedMessageCounter)
+	registry.MustRegister(receivedEventCounter)
+	registry.MustRegister(skippedEventCounter)
+	registry.MustRegister(partitionResolvedTsGauge)
+	registry.MustRegister(checkpointTsGauge)
+	registry.MustRegister(checkpointLagGauge)
+	registry.MustRegister(ddlExecDurationHistogram)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/notification"
 	"github.com/pingcap/tiflow/cdc/processor/pipeline/system"
 	ssystem "github.com/pingcap/tiflow/cdc/sorter/leveldb/system"
 	"github.com/pingcap/tiflow/pkg/config"
@@ -44,6 +45,9 @@ type GlobalVars struct {
 	// MessageServer and MessageRouter are for peer-messaging
 	MessageServer *p2p.MessageServer
 	MessageRouter p2p.MessageRouter
+
+	// Notifier posts the events to the webhooks, it can be nil.
+	Notifier *notification.Notifier
 }
 
 // ChangefeedVars contains some vars which can be used anywhere in a pipeline
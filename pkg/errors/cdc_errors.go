@@ -351,6 +351,22 @@ var (
 		"MySQL worker panic",
 		errors.RFCCodeText("CDC:ErrMySQLWorkerPanic"),
 	)
+	ErrPostgresTxnError = errors.Normalize(
+		"PostgreSQL txn error",
+		errors.RFCCodeText("CDC:ErrPostgresTxnError"),
+	)
+	ErrPostgresConnectionError = errors.Normalize(
+		"PostgreSQL connection error",
+		errors.RFCCodeText("CDC:ErrPostgresConnectionError"),
+	)
+	ErrPostgresInvalidConfig = errors.Normalize(
+		"PostgreSQL config invalid",
+		errors.RFCCodeText("CDC:ErrPostgresInvalidConfig"),
+	)
+	ErrPostgresDDLUnsupported = errors.Normalize(
+		"DDL can not be translated for PostgreSQL: %s",
+		errors.RFCCodeText("CDC:ErrPostgresDDLUnsupported"),
+	)
 	ErrAvroToEnvelopeError = errors.Normalize(
 		"to envelope failed",
 		errors.RFCCodeText("CDC:ErrAvroToEnvelopeError"),
@@ -415,6 +431,10 @@ var (
 		"craft codec invalid data",
 		errors.RFCCodeText("CDC:ErrCraftCodecInvalidData"),
 	)
+	ErrRowChecksumMismatch = errors.Normalize(
+		"the checksum of the row is %d, but the calculated checksum is %d, table: %s",
+		errors.RFCCodeText("CDC:ErrRowChecksumMismatch"),
+	)
 
 	// utilities related errors
 	ErrToTLSConfigFailed = errors.Normalize(
@@ -522,6 +542,22 @@ var (
 			`eg, "simple-namespace-test"`),
 		errors.RFCCodeText("CDC:ErrInvalidNamespace"),
 	)
+	ErrNamespaceNotExists = errors.Normalize(
+		"namespace not exists, %s",
+		errors.RFCCodeText("CDC:ErrNamespaceNotExists"),
+	)
+	ErrNamespaceAlreadyExists = errors.Normalize(
+		"namespace already exists, %s",
+		errors.RFCCodeText("CDC:ErrNamespaceAlreadyExists"),
+	)
+	ErrNamespaceNotEmpty = errors.Normalize(
+		"namespace %s still has %d changefeeds, remove them first",
+		errors.RFCCodeText("CDC:ErrNamespaceNotEmpty"),
+	)
+	ErrNamespaceQuotaExceeded = errors.Normalize(
+		"namespace %s exceeds its %s quota, required %d, quota %d",
+		errors.RFCCodeText("CDC:ErrNamespaceQuotaExceeded"),
+	)
 	ErrInvalidEtcdKey = errors.Normalize(
 		"invalid key: %s",
 		errors.RFCCodeText("CDC:ErrInvalidEtcdKey"),
@@ -552,6 +588,10 @@ var (
 		"table %d not found in schema snapshot",
 		errors.RFCCodeText("CDC:ErrSnapshotTableNotFound"),
 	)
+	ErrSnapshotTableNameNotFound = errors.Normalize(
+		"table %s.%s not found in schema snapshot at ts %d",
+		errors.RFCCodeText("CDC:ErrSnapshotTableNameNotFound"),
+	)
 	ErrSnapshotSchemaExists = errors.Normalize(
 		"schema %s(%d) already exists",
 		errors.RFCCodeText("CDC:ErrSnapshotSchemaExists"),
@@ -680,6 +720,10 @@ var (
 		"changefeed update error: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateRefused"),
 	)
+	ErrChangefeedRevisionNotFound = errors.Normalize(
+		"changefeed %s revision %d not found",
+		errors.RFCCodeText("CDC:ErrChangefeedRevisionNotFound"),
+	)
 	ErrChangefeedUpdateFailedTransaction = errors.Normalize(
 		"changefeed update failed due to unexpected etcd transaction failure: %s",
 		errors.RFCCodeText("CDC:ErrChangefeedUpdateFailed"),
@@ -881,6 +925,34 @@ var (
 		"illegal parameter for sorter: %s",
 		errors.RFCCodeText("CDC:ErrIllegalSorterParameter"),
 	)
+	ErrIllegalTracingParameter = errors.Normalize(
+		"illegal parameter for tracing: %s",
+		errors.RFCCodeText("CDC:ErrIllegalTracingParameter"),
+	)
+	ErrIllegalNotificationParameter = errors.Normalize(
+		"illegal parameter for notification: %s",
+		errors.RFCCodeText("CDC:ErrIllegalNotificationParameter"),
+	)
+	ErrReloadServerConfig = errors.Normalize(
+		"fail to reload server config: %s",
+		errors.RFCCodeText("CDC:ErrReloadServerConfig"),
+	)
+	ErrIllegalRetryParameter = errors.Normalize(
+		"illegal parameter for changefeed retry: %s",
+		errors.RFCCodeText("CDC:ErrIllegalRetryParameter"),
+	)
+	ErrIllegalRateLimitParameter = errors.Normalize(
+		"illegal parameter for changefeed rate limit: %s",
+		errors.RFCCodeText("CDC:ErrIllegalRateLimitParameter"),
+	)
+	ErrInvalidChangefeedPriority = errors.Normalize(
+		"invalid changefeed priority %s, it must be one of low, normal and high",
+		errors.RFCCodeText("CDC:ErrInvalidChangefeedPriority"),
+	)
+	ErrGetCaptureLatencyFailed = errors.Normalize(
+		"failed to get the replication latency from capture %s: %s",
+		errors.RFCCodeText("CDC:ErrGetCaptureLatencyFailed"),
+	)
 	ErrAsyncIOCancelled = errors.Normalize(
 		"asynchronous IO operation is cancelled. Internal use only, "+
 			"report a bug if seen in log",
@@ -902,6 +974,11 @@ var (
 		"sorter is closed",
 		errors.RFCCodeText("CDC:ErrSorterClosed"),
 	)
+	ErrSorterDiskQuotaExceeded = errors.Normalize(
+		"changefeed %s exceeds its share of sorter disk quota, "+
+			"usage: %d bytes, share: %d bytes",
+		errors.RFCCodeText("CDC:ErrSorterDiskQuotaExceeded"),
+	)
 
 	// processor errors
 	ErrProcessorDuplicateOperations = errors.Normalize(
@@ -1058,12 +1135,44 @@ var (
 		"upstream missmatch,old: %d, new %d",
 		errors.RFCCodeText("CDC:ErrUpstreamMissMatch"),
 	)
+	ErrUpstreamAlreadyExists = errors.Normalize(
+		"upstream %d already exists in namespace %s",
+		errors.RFCCodeText("CDC:ErrUpstreamAlreadyExists"),
+	)
+	ErrUpstreamInUse = errors.Normalize(
+		"upstream %d is still used by changefeed %s, remove the changefeed first",
+		errors.RFCCodeText("CDC:ErrUpstreamInUse"),
+	)
 
 	ErrServerIsNotReady = errors.Normalize(
 		"cdc server is not ready",
 		errors.RFCCodeText("CDC:ErrServerIsNotReady"),
 	)
 
+	// consumer error
+	ErrConsumerInvalidConfig = errors.Normalize(
+		"consumer config invalid",
+		errors.RFCCodeText("CDC:ErrConsumerInvalidConfig"),
+	)
+	ErrConsumerUnsupportedProtocol = errors.Normalize(
+		"protocol %s is not supported by the consumer since it has no decoder of the protocol, "+
+			"the supported protocols are %s",
+		errors.RFCCodeText("CDC:ErrConsumerUnsupportedProtocol"),
+	)
+	ErrConsumerCheckpoint = errors.Normalize(
+		"failed to access the consumer checkpoint",
+		errors.RFCCodeText("CDC:ErrConsumerCheckpoint"),
+	)
+	ErrConsumerEventOutOfOrder = errors.Normalize(
+		"event out of order: %s",
+		errors.RFCCodeText("CDC:ErrConsumerEventOutOfOrder"),
+	)
+	ErrConsumerPartitionNumChanged = errors.Normalize(
+		"partition number of topic %s is increased from %d to %d, "+
+			"restart the consumer to consume the new partitions",
+		errors.RFCCodeText("CDC:ErrConsumerPartitionNumChanged"),
+	)
+
 	// cli error
 	ErrCliInvalidCheckpointTs = errors.Normalize(
 		"invalid overwrite-checkpoint-ts %s, "+
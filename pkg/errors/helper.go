@@ -68,8 +68,15 @@ func IsChangefeedFastFailErrorCode(errCode errors.RFCErrorCode) bool {
 	return false
 }
 
+// changefeedUnRetryableErrors is read only.
+// If this type of error occurs in a changefeed, retrying it can not succeed
+// until the operator fixes the configuration or the downstream, so the
+// changefeed is stopped until it is resumed manually.
 var changefeedUnRetryableErrors = []*errors.Error{
 	ErrExpressionColumnNotFound, ErrExpressionParseFailed,
+	ErrSinkURIInvalid, ErrSinkInvalidConfig,
+	ErrMySQLInvalidConfig, ErrKafkaInvalidConfig,
+	ErrPostgresInvalidConfig, ErrPostgresDDLUnsupported,
 }
 
 // IsChangefeedUnRetryableError returns true if a error is a changefeed not retry error.
@@ -90,6 +97,33 @@ func IsChangefeedUnRetryableError(err error) bool {
 	return false
 }
 
+// ErrorClass is the class of a changefeed error, it decides how the
+// changefeed is handled when the error occurs.
+type ErrorClass string
+
+const (
+	// ErrorClassRetryable means the error is transient, the changefeed is
+	// restarted automatically with backoff.
+	ErrorClassRetryable ErrorClass = "retryable"
+	// ErrorClassOperatorAction means the changefeed can only be resumed
+	// after the operator fixes the problem.
+	ErrorClassOperatorAction ErrorClass = "retryable-after-operator-action"
+	// ErrorClassFatal means the changefeed can not make progress anymore,
+	// and it is failed immediately.
+	ErrorClassFatal ErrorClass = "fatal"
+)
+
+// ClassifyError returns the class of an error occurred in a changefeed.
+func ClassifyError(err error) ErrorClass {
+	if IsChangefeedFastFailError(err) {
+		return ErrorClassFatal
+	}
+	if IsChangefeedUnRetryableError(err) {
+		return ErrorClassOperatorAction
+	}
+	return ErrorClassRetryable
+}
+
 // RFCCode returns a RFCCode from an error
 func RFCCode(err error) (errors.RFCErrorCode, bool) {
 	type rfcCoder interface {
@@ -174,3 +174,40 @@ func TestChangefeedNotRetryError(t *testing.T) {
 		require.Equal(t, c.expected, IsChangefeedUnRetryableError(c.err))
 	}
 }
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		err      error
+		expected ErrorClass
+	}{
+		{
+			err:      ErrMySQLTxnError.FastGenByArgs(),
+			expected: ErrorClassRetryable,
+		},
+		{
+			err:      errors.New("connection refused"),
+			expected: ErrorClassRetryable,
+		},
+		{
+			err:      ErrSinkURIInvalid.FastGenByArgs(),
+			expected: ErrorClassOperatorAction,
+		},
+		{
+			err:      WrapError(ErrKafkaInvalidConfig, errors.New("invalid partition num")),
+			expected: ErrorClassOperatorAction,
+		},
+		{
+			err:      ErrSnapshotLostByGC.FastGenByArgs(),
+			expected: ErrorClassFatal,
+		},
+		{
+			err:      WrapError(ErrStartTsBeforeGC, ErrSnapshotLostByGC.FastGenByArgs()),
+			expected: ErrorClassFatal,
+		},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, ClassifyError(c.err), c.err.Error())
+	}
+}
@@ -38,6 +38,20 @@ type CDCEtcdClientForAPI interface {
 		namespace string,
 	) (*model.UpstreamInfo, error)
 
+	CreateUpstreamInfo(ctx context.Context,
+		info *model.UpstreamInfo,
+		namespace string,
+	) error
+
+	GetAllUpstreamInfo(ctx context.Context,
+		namespace string,
+	) (map[model.UpstreamID]*model.UpstreamInfo, error)
+
+	DeleteUpstreamInfo(ctx context.Context,
+		upstreamID model.UpstreamID,
+		namespace string,
+	) error
+
 	GetGCServiceID() string
 
 	GetEnsureGCServiceID(tag string) string
@@ -57,5 +71,32 @@ type CDCEtcdClientForAPI interface {
 		upstreamInfo *model.UpstreamInfo,
 		changeFeedInfo *model.ChangeFeedInfo,
 		changeFeedID model.ChangeFeedID,
+		reason string,
 	) error
+
+	UpdateChangefeedInfo(ctx context.Context,
+		changeFeedInfo *model.ChangeFeedInfo,
+		changeFeedID model.ChangeFeedID,
+		reason string,
+	) error
+
+	GetChangeFeedHistory(ctx context.Context,
+		changeFeedID model.ChangeFeedID,
+	) (*model.ChangeFeedHistory, error)
+
+	GetChangeFeedSnapshotProgress(ctx context.Context,
+		changeFeedID model.ChangeFeedID,
+	) (map[model.TableID]*model.TableSnapshotProgress, error)
+
+	CreateNamespace(ctx context.Context, info *model.NamespaceInfo) error
+
+	UpdateNamespace(ctx context.Context, info *model.NamespaceInfo) error
+
+	GetNamespaceInfo(ctx context.Context,
+		namespace string,
+	) (*model.NamespaceInfo, error)
+
+	GetAllNamespaceInfo(ctx context.Context) (map[string]*model.NamespaceInfo, error)
+
+	DeleteNamespace(ctx context.Context, namespace string) error
 }
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -71,6 +72,28 @@ func GetEtcdKeyChangeFeedInfo(clusterID string, changefeedID model.ChangeFeedID)
 		changefeedID.Namespace), changefeedID.ID)
 }
 
+// GetEtcdKeyChangeFeedHistory returns the key of a changefeed info history
+func GetEtcdKeyChangeFeedHistory(clusterID string, changefeedID model.ChangeFeedID) string {
+	return NamespacedPrefix(clusterID, changefeedID.Namespace) +
+		ChangefeedHistoryKey + "/" + changefeedID.ID
+}
+
+// GetEtcdKeyChangeFeedSnapshot returns the prefix key of the initial
+// snapshot progress of all tables in a changefeed
+func GetEtcdKeyChangeFeedSnapshot(clusterID string, changefeedID model.ChangeFeedID) string {
+	return NamespacedPrefix(clusterID, changefeedID.Namespace) +
+		ChangefeedSnapshotKey + "/" + changefeedID.ID + "/"
+}
+
+// GetEtcdKeyTableSnapshot returns the key of the initial snapshot progress of a table
+func GetEtcdKeyTableSnapshot(clusterID string,
+	changefeedID model.ChangeFeedID,
+	tableID model.TableID,
+) string {
+	return GetEtcdKeyChangeFeedSnapshot(clusterID, changefeedID) +
+		strconv.FormatInt(tableID, 10)
+}
+
 // GetEtcdKeyTaskPosition returns the key of a task position
 func GetEtcdKeyTaskPosition(clusterID string,
 	changefeedID model.ChangeFeedID,
@@ -85,6 +108,22 @@ func GetEtcdKeyCaptureInfo(clusterID, id string) string {
 	return CaptureInfoKeyPrefix(clusterID) + "/" + id
 }
 
+// NamespaceKeyPrefix is the prefix of namespace info keys
+func NamespaceKeyPrefix(clusterID string) string {
+	return BaseKey(clusterID) + metaPrefix + namespaceKey
+}
+
+// GetEtcdKeyNamespace returns the key of a namespace info
+func GetEtcdKeyNamespace(clusterID, namespace string) string {
+	return NamespaceKeyPrefix(clusterID) + "/" + namespace
+}
+
+// NotificationKeyPrefix returns the prefix key of the pending notification
+// events of a webhook
+func NotificationKeyPrefix(clusterID, webhookID string) string {
+	return BaseKey(clusterID) + metaPrefix + notificationKey + "/" + webhookID + "/"
+}
+
 // GetEtcdKeyJob returns the key for a job status
 func GetEtcdKeyJob(clusterID string, changeFeedID model.ChangeFeedID) string {
 	return ChangefeedStatusKeyPrefix(clusterID, changeFeedID.Namespace) + "/" + changeFeedID.ID
@@ -394,6 +433,15 @@ func (c CDCEtcdClient) CreateChangefeedInfo(ctx context.Context,
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// The history left by a removed changefeed with the same ID is overwritten.
+	history := &model.ChangeFeedHistory{}
+	if err := history.AddRevision(info, "", info.CreateTime); err != nil {
+		return errors.Trace(err)
+	}
+	historyData, err := history.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	upstreamResp, err := c.Client.Get(ctx, upstreamEtcdKeyStr)
 	if err != nil {
 		return errors.Trace(err)
@@ -409,6 +457,12 @@ func (c CDCEtcdClient) CreateChangefeedInfo(ctx context.Context,
 	opsThen := []clientv3.Op{
 		clientv3.OpPut(infoKey, value),
 		clientv3.OpPut(upstreamEtcdKeyStr, string(upstreamData)),
+		clientv3.OpPut(GetEtcdKeyChangeFeedHistory(c.ClusterID, changeFeedID),
+			string(historyData)),
+		// The snapshot progress left by a removed changefeed with the same ID
+		// must not be resumed.
+		clientv3.OpDelete(GetEtcdKeyChangeFeedSnapshot(c.ClusterID, changeFeedID),
+			clientv3.WithPrefix()),
 	}
 	if len(upstreamResp.Kvs) == 0 {
 		cmps = append(cmps,
@@ -433,17 +487,14 @@ func (c CDCEtcdClient) CreateChangefeedInfo(ctx context.Context,
 	return errors.Trace(err)
 }
 
-// UpdateChangefeedAndUpstream updates the changefeed's info and its upstream info into etcd
+// UpdateChangefeedAndUpstream updates the changefeed's info and its upstream info into etcd,
+// the new changefeed info is recorded in the history of the changefeed with the given reason.
 func (c CDCEtcdClient) UpdateChangefeedAndUpstream(ctx context.Context,
 	upstreamInfo *model.UpstreamInfo,
 	changeFeedInfo *model.ChangeFeedInfo,
 	changeFeedID model.ChangeFeedID,
+	reason string,
 ) error {
-	infoKey := GetEtcdKeyChangeFeedInfo(c.ClusterID, changeFeedID)
-	changeFeedInfoStr, err := changeFeedInfo.Marshal()
-	if err != nil {
-		return errors.Trace(err)
-	}
 	upstreamKey := CDCKey{
 		Tp:         CDCKeyTypeUpStream,
 		ClusterID:  c.ClusterID,
@@ -455,12 +506,77 @@ func (c CDCEtcdClient) UpdateChangefeedAndUpstream(ctx context.Context,
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return c.updateChangefeedWithHistory(ctx, changeFeedInfo, changeFeedID, reason,
+		clientv3.OpPut(upstreamKeyStr, string(upstreamInfoStr)))
+}
+
+// UpdateChangefeedInfo updates the changefeed's info into etcd, the new
+// changefeed info is recorded in the history of the changefeed with the
+// given reason.
+func (c CDCEtcdClient) UpdateChangefeedInfo(ctx context.Context,
+	changeFeedInfo *model.ChangeFeedInfo,
+	changeFeedID model.ChangeFeedID,
+	reason string,
+) error {
+	return c.updateChangefeedWithHistory(ctx, changeFeedInfo, changeFeedID, reason)
+}
+
+// updateChangefeedWithHistory puts the changefeed info and appends it to the
+// history of the changefeed in one transaction with the extra operations.
+func (c CDCEtcdClient) updateChangefeedWithHistory(ctx context.Context,
+	changeFeedInfo *model.ChangeFeedInfo,
+	changeFeedID model.ChangeFeedID,
+	reason string,
+	extraOps ...clientv3.Op,
+) error {
+	infoKey := GetEtcdKeyChangeFeedInfo(c.ClusterID, changeFeedID)
+	changeFeedInfoStr, err := changeFeedInfo.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	historyKey := GetEtcdKeyChangeFeedHistory(c.ClusterID, changeFeedID)
+	historyResp, err := c.Client.Get(ctx, historyKey)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	history := &model.ChangeFeedHistory{}
+	historyRevision := int64(0)
+	if len(historyResp.Kvs) != 0 {
+		historyRevision = historyResp.Kvs[0].ModRevision
+		if err := history.Unmarshal(historyResp.Kvs[0].Value); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		// The changefeed is created by an older version without history,
+		// record its original info as the first revision.
+		oldInfo, err := c.GetChangeFeedInfo(ctx, changeFeedID)
+		if err != nil && !cerror.ErrChangeFeedNotExists.Equal(err) {
+			return errors.Trace(err)
+		}
+		if oldInfo != nil {
+			if err := history.AddRevision(oldInfo, "", oldInfo.CreateTime); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	if err := history.AddRevision(changeFeedInfo, reason, time.Now()); err != nil {
+		return errors.Trace(err)
+	}
+	historyData, err := history.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Make sure no revision is lost by concurrent updates.
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(historyKey), "=", historyRevision),
+	}
 	opsThen := []clientv3.Op{
 		clientv3.OpPut(infoKey, changeFeedInfoStr),
-		clientv3.OpPut(upstreamKeyStr, string(upstreamInfoStr)),
+		clientv3.OpPut(historyKey, string(historyData)),
 	}
+	opsThen = append(opsThen, extraOps...)
 
-	resp, err := c.Client.Txn(ctx, txnEmptyCmps, opsThen, TxnEmptyOpsElse)
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
 	if err != nil {
 		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
 	}
@@ -473,6 +589,83 @@ func (c CDCEtcdClient) UpdateChangefeedAndUpstream(ctx context.Context,
 	return nil
 }
 
+// GetChangeFeedHistory queries the info history of a changefeed,
+// the history is empty if the changefeed is created by an older version
+// and has never been updated.
+func (c CDCEtcdClient) GetChangeFeedHistory(ctx context.Context,
+	changeFeedID model.ChangeFeedID,
+) (*model.ChangeFeedHistory, error) {
+	key := GetEtcdKeyChangeFeedHistory(c.ClusterID, changeFeedID)
+	resp, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	history := &model.ChangeFeedHistory{}
+	if resp.Count == 0 {
+		return history, nil
+	}
+	err = history.Unmarshal(resp.Kvs[0].Value)
+	return history, errors.Trace(err)
+}
+
+// GetTableSnapshotProgress queries the initial snapshot progress of a table,
+// it returns nil if the snapshot of the table has not been started.
+func (c CDCEtcdClient) GetTableSnapshotProgress(ctx context.Context,
+	changeFeedID model.ChangeFeedID, tableID model.TableID,
+) (*model.TableSnapshotProgress, error) {
+	key := GetEtcdKeyTableSnapshot(c.ClusterID, changeFeedID, tableID)
+	resp, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Count == 0 {
+		return nil, nil
+	}
+	progress := &model.TableSnapshotProgress{}
+	err = progress.Unmarshal(resp.Kvs[0].Value)
+	return progress, errors.Trace(err)
+}
+
+// PutTableSnapshotProgress stores the initial snapshot progress of a table.
+func (c CDCEtcdClient) PutTableSnapshotProgress(ctx context.Context,
+	changeFeedID model.ChangeFeedID, tableID model.TableID,
+	progress *model.TableSnapshotProgress,
+) error {
+	key := GetEtcdKeyTableSnapshot(c.ClusterID, changeFeedID, tableID)
+	data, err := progress.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = c.Client.Put(ctx, key, string(data))
+	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+}
+
+// GetChangeFeedSnapshotProgress queries the initial snapshot progress of
+// all tables in a changefeed, and returns a map mapping from table ID to
+// the progress.
+func (c CDCEtcdClient) GetChangeFeedSnapshotProgress(ctx context.Context,
+	changeFeedID model.ChangeFeedID,
+) (map[model.TableID]*model.TableSnapshotProgress, error) {
+	resp, err := c.Client.Get(ctx,
+		GetEtcdKeyChangeFeedSnapshot(c.ClusterID, changeFeedID), clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	progresses := make(map[model.TableID]*model.TableSnapshotProgress, resp.Count)
+	for _, rawKv := range resp.Kvs {
+		k := new(CDCKey)
+		if err := k.Parse(c.ClusterID, string(rawKv.Key)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		progress := &model.TableSnapshotProgress{}
+		if err := progress.Unmarshal(rawKv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		progresses[k.TableID] = progress
+	}
+	return progresses, nil
+}
+
 // SaveChangeFeedInfo stores change feed info into etcd
 // TODO: this should be called from outer system, such as from a TiDB client
 func (c CDCEtcdClient) SaveChangeFeedInfo(ctx context.Context,
@@ -593,6 +786,43 @@ func (c CDCEtcdClient) DeleteCaptureInfo(ctx context.Context, id string) error {
 	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
 }
 
+// PutNotificationEvent stores a notification event which is not delivered
+// to the webhook yet.
+func (c CDCEtcdClient) PutNotificationEvent(ctx context.Context,
+	webhookID, eventID string, data []byte,
+) error {
+	key := NotificationKeyPrefix(c.ClusterID, webhookID) + eventID
+	_, err := c.Client.Put(ctx, key, string(data))
+	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+}
+
+// DeleteNotificationEvent deletes a notification event once it is delivered
+// to the webhook or rejected by the webhook.
+func (c CDCEtcdClient) DeleteNotificationEvent(ctx context.Context,
+	webhookID, eventID string,
+) error {
+	key := NotificationKeyPrefix(c.ClusterID, webhookID) + eventID
+	_, err := c.Client.Delete(ctx, key)
+	return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+}
+
+// GetNotificationEvents returns all notification events of a webhook which
+// are not delivered yet.
+func (c CDCEtcdClient) GetNotificationEvents(ctx context.Context,
+	webhookID string,
+) ([][]byte, error) {
+	resp, err := c.Client.Get(ctx,
+		NotificationKeyPrefix(c.ClusterID, webhookID), clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	events := make([][]byte, 0, len(resp.Kvs))
+	for _, rawKv := range resp.Kvs {
+		events = append(events, rawKv.Value)
+	}
+	return events, nil
+}
+
 // GetOwnerID returns the owner id by querying etcd
 func (c CDCEtcdClient) GetOwnerID(ctx context.Context) (string, error) {
 	resp, err := c.Client.Get(ctx, CaptureOwnerKey(c.ClusterID),
@@ -656,6 +886,258 @@ func (c CDCEtcdClient) GetUpstreamInfo(ctx context.Context,
 	return info, errors.Trace(err)
 }
 
+// UpstreamKeyPrefix returns the etcd prefix of the upstreams in a namespace
+func UpstreamKeyPrefix(clusterID, namespace string) string {
+	return NamespacedPrefix(clusterID, namespace) + upstreamKey
+}
+
+// CreateUpstreamInfo registers an upstream in a namespace and fails if it
+// is already exists.
+func (c CDCEtcdClient) CreateUpstreamInfo(ctx context.Context,
+	info *model.UpstreamInfo,
+	namespace string,
+) error {
+	key := CDCKey{
+		Tp:         CDCKeyTypeUpStream,
+		ClusterID:  c.ClusterID,
+		UpstreamID: info.ID,
+		Namespace:  namespace,
+	}
+	keyStr := key.String()
+	value, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(keyStr), "=", 0),
+	}
+	opsThen := []clientv3.Op{
+		clientv3.OpPut(keyStr, string(value)),
+	}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrUpstreamAlreadyExists.GenWithStackByArgs(info.ID, namespace)
+	}
+	return nil
+}
+
+// GetAllUpstreamInfo queries the infos of all upstreams in a namespace.
+func (c CDCEtcdClient) GetAllUpstreamInfo(ctx context.Context,
+	namespace string,
+) (map[model.UpstreamID]*model.UpstreamInfo, error) {
+	resp, err := c.Client.Get(ctx, UpstreamKeyPrefix(c.ClusterID, namespace)+"/",
+		clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	infos := make(map[model.UpstreamID]*model.UpstreamInfo, resp.Count)
+	for _, rawKv := range resp.Kvs {
+		info := &model.UpstreamInfo{}
+		if err := info.Unmarshal(rawKv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		infos[info.ID] = info
+	}
+	return infos, nil
+}
+
+// DeleteUpstreamInfo removes an upstream from a namespace, it fails if the
+// upstream is still used by a changefeed in the namespace.
+func (c CDCEtcdClient) DeleteUpstreamInfo(ctx context.Context,
+	upstreamID model.UpstreamID,
+	namespace string,
+) error {
+	key := CDCKey{
+		Tp:         CDCKeyTypeUpStream,
+		ClusterID:  c.ClusterID,
+		UpstreamID: upstreamID,
+		Namespace:  namespace,
+	}
+	keyStr := key.String()
+	upstreamResp, err := c.Client.Get(ctx, keyStr)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if upstreamResp.Count == 0 {
+		return cerror.ErrUpstreamNotFound.GenWithStackByArgs(upstreamID)
+	}
+
+	cfResp, err := c.Client.Get(ctx,
+		GetEtcdKeyChangeFeedList(c.ClusterID, namespace)+"/", clientv3.WithPrefix())
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	for _, rawKv := range cfResp.Kvs {
+		info := &model.ChangeFeedInfo{}
+		if err := info.Unmarshal(rawKv.Value); err != nil {
+			return errors.Trace(err)
+		}
+		if info.UpstreamID == upstreamID {
+			changefeed, err := extractKeySuffix(string(rawKv.Key))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return cerror.ErrUpstreamInUse.GenWithStackByArgs(upstreamID, changefeed)
+		}
+	}
+
+	cmps := []clientv3.Cmp{
+		// A changefeed created concurrently rewrites the upstream info.
+		clientv3.Compare(clientv3.ModRevision(keyStr),
+			"=", upstreamResp.Kvs[0].ModRevision),
+	}
+	opsThen := []clientv3.Op{
+		clientv3.OpDelete(keyStr),
+	}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		// The upstream is removed or rewritten concurrently, check it again.
+		return c.DeleteUpstreamInfo(ctx, upstreamID, namespace)
+	}
+	return nil
+}
+
+// CreateNamespace creates a namespace info into etcd and fails if it is already exists.
+func (c CDCEtcdClient) CreateNamespace(ctx context.Context,
+	info *model.NamespaceInfo,
+) error {
+	key := GetEtcdKeyNamespace(c.ClusterID, info.Name)
+	value, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(key), "=", 0),
+	}
+	opsThen := []clientv3.Op{
+		clientv3.OpPut(key, string(value)),
+	}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrNamespaceAlreadyExists.GenWithStackByArgs(info.Name)
+	}
+	return nil
+}
+
+// UpdateNamespace updates the quotas of a namespace. The default namespace
+// is created implicitly if it has not been stored in etcd yet.
+func (c CDCEtcdClient) UpdateNamespace(ctx context.Context,
+	info *model.NamespaceInfo,
+) error {
+	key := GetEtcdKeyNamespace(c.ClusterID, info.Name)
+	value, err := info.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cmps := txnEmptyCmps
+	if info.Name != model.DefaultNamespace {
+		cmps = []clientv3.Cmp{
+			clientv3.Compare(clientv3.ModRevision(key), ">", 0),
+		}
+	}
+	opsThen := []clientv3.Op{
+		clientv3.OpPut(key, string(value)),
+	}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrNamespaceNotExists.GenWithStackByArgs(info.Name)
+	}
+	return nil
+}
+
+// GetNamespaceInfo queries the info of a given namespace.
+func (c CDCEtcdClient) GetNamespaceInfo(ctx context.Context,
+	namespace string,
+) (*model.NamespaceInfo, error) {
+	key := GetEtcdKeyNamespace(c.ClusterID, namespace)
+	resp, err := c.Client.Get(ctx, key)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if resp.Count == 0 {
+		if namespace == model.DefaultNamespace {
+			return model.NewDefaultNamespaceInfo(), nil
+		}
+		return nil, cerror.ErrNamespaceNotExists.GenWithStackByArgs(namespace)
+	}
+	info := &model.NamespaceInfo{}
+	err = info.Unmarshal(resp.Kvs[0].Value)
+	return info, errors.Trace(err)
+}
+
+// GetAllNamespaceInfo queries the infos of all namespaces, including the
+// default namespace.
+func (c CDCEtcdClient) GetAllNamespaceInfo(ctx context.Context) (
+	map[string]*model.NamespaceInfo, error,
+) {
+	resp, err := c.Client.Get(ctx, NamespaceKeyPrefix(c.ClusterID)+"/",
+		clientv3.WithPrefix())
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	infos := make(map[string]*model.NamespaceInfo, resp.Count+1)
+	infos[model.DefaultNamespace] = model.NewDefaultNamespaceInfo()
+	for _, rawKv := range resp.Kvs {
+		info := &model.NamespaceInfo{}
+		if err := info.Unmarshal(rawKv.Value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		infos[info.Name] = info
+	}
+	return infos, nil
+}
+
+// DeleteNamespace deletes a namespace and all the metadata left under it,
+// it fails if there are still changefeeds in the namespace.
+func (c CDCEtcdClient) DeleteNamespace(ctx context.Context, namespace string) error {
+	if namespace == model.DefaultNamespace {
+		return cerror.ErrAPIInvalidParam.GenWithStack(
+			"the default namespace can not be deleted")
+	}
+	changefeedPrefix := GetEtcdKeyChangeFeedList(c.ClusterID, namespace) + "/"
+	cfResp, err := c.Client.Get(ctx, changefeedPrefix,
+		clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if cfResp.Count > 0 {
+		return cerror.ErrNamespaceNotEmpty.GenWithStackByArgs(namespace, cfResp.Count)
+	}
+
+	key := GetEtcdKeyNamespace(c.ClusterID, namespace)
+	cmps := []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(key), ">", 0),
+		// Make sure no changefeed is created concurrently.
+		clientv3.Compare(clientv3.CreateRevision(changefeedPrefix),
+			"=", 0).WithPrefix(),
+	}
+	opsThen := []clientv3.Op{
+		clientv3.OpDelete(key),
+		clientv3.OpDelete(NamespacedPrefix(c.ClusterID, namespace)+"/",
+			clientv3.WithPrefix()),
+	}
+	resp, err := c.Client.Txn(ctx, cmps, opsThen, TxnEmptyOpsElse)
+	if err != nil {
+		return cerror.WrapError(cerror.ErrPDEtcdAPIError, err)
+	}
+	if !resp.Succeeded {
+		return cerror.ErrNamespaceNotExists.GenWithStackByArgs(namespace)
+	}
+	return nil
+}
+
 // GcServiceIDForTest returns the gc service ID for tests
 func GcServiceIDForTest() string {
 	return fmt.Sprintf("ticdc-%s-%d", "default", 0)
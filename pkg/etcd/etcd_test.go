@@ -279,7 +279,7 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 		SinkURI:   "blackhole://",
 	}
 
-	err := s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	err := s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID, "")
 	require.NoError(t, err)
 
 	var upstreamResult *model.UpstreamInfo
@@ -294,6 +294,231 @@ func TestUpdateChangefeedAndUpstream(t *testing.T) {
 	require.Equal(t, changeFeedInfo.SinkURI, changefeedResult.SinkURI)
 }
 
+func TestChangefeedHistory(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	upstreamInfo := &model.UpstreamInfo{ID: 1}
+	changeFeedID := model.DefaultChangeFeedID("test-history")
+	changeFeedInfo := &model.ChangeFeedInfo{
+		ID:         changeFeedID.ID,
+		Namespace:  changeFeedID.Namespace,
+		SinkURI:    "blackhole://",
+		CreateTime: time.Now(),
+	}
+	history, err := s.client.GetChangeFeedHistory(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Nil(t, history.Current())
+
+	// the info of a changefeed without history is recorded as the first revision.
+	err = s.client.SaveChangeFeedInfo(ctx, changeFeedInfo, changeFeedID)
+	require.NoError(t, err)
+	changeFeedInfo.SinkURI = "mysql://127.0.0.1:3306/"
+	err = s.client.UpdateChangefeedAndUpstream(ctx, upstreamInfo,
+		changeFeedInfo, changeFeedID, "change sink")
+	require.NoError(t, err)
+	history, err = s.client.GetChangeFeedHistory(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 2)
+	require.Equal(t, "blackhole://", history.Revisions[0].Info.SinkURI)
+	require.Equal(t, uint64(2), history.Current().Revision)
+	require.Equal(t, "change sink", history.Current().Reason)
+	require.Equal(t, "mysql://127.0.0.1:3306/", history.Current().Info.SinkURI)
+
+	// updating the info without the upstream is also recorded.
+	changeFeedInfo.TargetTs = 100
+	err = s.client.UpdateChangefeedInfo(ctx, changeFeedInfo, changeFeedID, "")
+	require.NoError(t, err)
+	history, err = s.client.GetChangeFeedHistory(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 3)
+	require.Equal(t, uint64(100), history.Current().Info.TargetTs)
+	info, err := s.client.GetChangeFeedInfo(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), info.TargetTs)
+
+	// creating a changefeed resets its history
+	_, err = s.client.Client.Delete(ctx,
+		GetEtcdKeyChangeFeedInfo(s.client.ClusterID, changeFeedID))
+	require.NoError(t, err)
+	err = s.client.CreateChangefeedInfo(ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	require.NoError(t, err)
+	history, err = s.client.GetChangeFeedHistory(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+	require.Equal(t, uint64(1), history.Current().Revision)
+}
+
+func TestTableSnapshotProgress(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	changeFeedID := model.DefaultChangeFeedID("test-snapshot")
+	progress, err := s.client.GetTableSnapshotProgress(ctx, changeFeedID, 1)
+	require.NoError(t, err)
+	require.Nil(t, progress)
+
+	err = s.client.PutTableSnapshotProgress(ctx, changeFeedID, 1,
+		&model.TableSnapshotProgress{SnapshotTs: 10, NextKey: []byte("k"), Rows: 2})
+	require.NoError(t, err)
+	err = s.client.PutTableSnapshotProgress(ctx, changeFeedID, 2,
+		&model.TableSnapshotProgress{SnapshotTs: 10, Rows: 5, Done: true})
+	require.NoError(t, err)
+	// the progress of other changefeeds is not listed.
+	err = s.client.PutTableSnapshotProgress(ctx, model.DefaultChangeFeedID("test-snapshot-1"), 3,
+		&model.TableSnapshotProgress{SnapshotTs: 10})
+	require.NoError(t, err)
+
+	progress, err = s.client.GetTableSnapshotProgress(ctx, changeFeedID, 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("k"), progress.NextKey)
+	require.Equal(t, uint64(2), progress.Rows)
+	progresses, err := s.client.GetChangeFeedSnapshotProgress(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, progresses, 2)
+	require.True(t, progresses[2].Done)
+
+	// creating a changefeed clears its snapshot progress
+	err = s.client.CreateChangefeedInfo(ctx, &model.UpstreamInfo{ID: 1},
+		&model.ChangeFeedInfo{SinkURI: "blackhole://"}, changeFeedID)
+	require.NoError(t, err)
+	progresses, err = s.client.GetChangeFeedSnapshotProgress(ctx, changeFeedID)
+	require.NoError(t, err)
+	require.Len(t, progresses, 0)
+}
+
+func TestNotificationEvents(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	events, err := s.client.GetNotificationEvents(ctx, "hook-1")
+	require.NoError(t, err)
+	require.Len(t, events, 0)
+
+	err = s.client.PutNotificationEvent(ctx, "hook-1", "event-1", []byte("e1"))
+	require.NoError(t, err)
+	err = s.client.PutNotificationEvent(ctx, "hook-1", "event-2", []byte("e2"))
+	require.NoError(t, err)
+	// the events of other webhooks are not listed.
+	err = s.client.PutNotificationEvent(ctx, "hook-10", "event-3", []byte("e3"))
+	require.NoError(t, err)
+	events, err = s.client.GetNotificationEvents(ctx, "hook-1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, [][]byte{[]byte("e1"), []byte("e2")}, events)
+
+	err = s.client.DeleteNotificationEvent(ctx, "hook-1", "event-1")
+	require.NoError(t, err)
+	events, err = s.client.GetNotificationEvents(ctx, "hook-1")
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("e2")}, events)
+}
+
+func TestNamespaceCRUD(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	info := &model.NamespaceInfo{Name: "tenant-a", ChangefeedQuota: 1}
+	err := s.client.CreateNamespace(ctx, info)
+	require.NoError(t, err)
+	err = s.client.CreateNamespace(ctx, info)
+	require.True(t, cerror.ErrNamespaceAlreadyExists.Equal(err))
+
+	info.MemoryQuota = 1024
+	require.NoError(t, s.client.UpdateNamespace(ctx, info))
+	err = s.client.UpdateNamespace(ctx, &model.NamespaceInfo{Name: "not-exist"})
+	require.True(t, cerror.ErrNamespaceNotExists.Equal(err))
+
+	result, err := s.client.GetNamespaceInfo(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, info, result)
+	_, err = s.client.GetNamespaceInfo(ctx, "not-exist")
+	require.True(t, cerror.ErrNamespaceNotExists.Equal(err))
+	result, err = s.client.GetNamespaceInfo(ctx, model.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, model.NewDefaultNamespaceInfo(), result)
+
+	infos, err := s.client.GetAllNamespaceInfo(ctx)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, info, infos["tenant-a"])
+
+	// A namespace with changefeeds can not be deleted.
+	changefeedID := model.ChangeFeedID{Namespace: "tenant-a", ID: "test"}
+	err = s.client.CreateChangefeedInfo(ctx, &model.UpstreamInfo{ID: 1},
+		&model.ChangeFeedInfo{SinkURI: "blackhole://"}, changefeedID)
+	require.NoError(t, err)
+	err = s.client.DeleteNamespace(ctx, "tenant-a")
+	require.True(t, cerror.ErrNamespaceNotEmpty.Equal(err))
+	err = s.client.DeleteChangeFeedInfo(ctx, changefeedID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.client.DeleteNamespace(ctx, "tenant-a"))
+	err = s.client.DeleteNamespace(ctx, "tenant-a")
+	require.True(t, cerror.ErrNamespaceNotExists.Equal(err))
+	err = s.client.DeleteNamespace(ctx, model.DefaultNamespace)
+	require.True(t, cerror.ErrAPIInvalidParam.Equal(err))
+	resp, err := s.client.Client.Get(ctx,
+		NamespacedPrefix(DefaultCDCClusterID, "tenant-a")+"/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), resp.Count)
+}
+
+func TestUpstreamInfoCRUD(t *testing.T) {
+	s := &Tester{}
+	s.SetUpTest(t)
+	defer s.TearDownTest(t)
+
+	ctx := context.Background()
+	info := &model.UpstreamInfo{
+		ID:          2,
+		PDEndpoints: "http://127.0.0.1:2379",
+		CAPath:      "ca.pem",
+	}
+	require.NoError(t, s.client.CreateUpstreamInfo(ctx, info, model.DefaultNamespace))
+	err := s.client.CreateUpstreamInfo(ctx, info, model.DefaultNamespace)
+	require.True(t, cerror.ErrUpstreamAlreadyExists.Equal(err))
+	require.NoError(t, s.client.CreateUpstreamInfo(ctx,
+		&model.UpstreamInfo{ID: 3}, model.DefaultNamespace))
+
+	result, err := s.client.GetUpstreamInfo(ctx, 2, model.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, info, result)
+	infos, err := s.client.GetAllUpstreamInfo(ctx, model.DefaultNamespace)
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	require.Equal(t, info, infos[2])
+	infos, err = s.client.GetAllUpstreamInfo(ctx, "other")
+	require.NoError(t, err)
+	require.Len(t, infos, 0)
+
+	// An upstream used by changefeeds can not be removed.
+	changefeedID := model.DefaultChangeFeedID("test")
+	err = s.client.CreateChangefeedInfo(ctx, info,
+		&model.ChangeFeedInfo{SinkURI: "blackhole://"}, changefeedID)
+	require.NoError(t, err)
+	err = s.client.DeleteUpstreamInfo(ctx, 2, model.DefaultNamespace)
+	require.True(t, cerror.ErrUpstreamInUse.Equal(err))
+	require.Contains(t, err.Error(), "test")
+	require.NoError(t, s.client.DeleteUpstreamInfo(ctx, 3, model.DefaultNamespace))
+	err = s.client.DeleteChangeFeedInfo(ctx, changefeedID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.client.DeleteUpstreamInfo(ctx, 2, model.DefaultNamespace))
+	err = s.client.DeleteUpstreamInfo(ctx, 2, model.DefaultNamespace)
+	require.True(t, cerror.ErrUpstreamNotFound.Equal(err))
+	infos, err = s.client.GetAllUpstreamInfo(ctx, model.DefaultNamespace)
+	require.NoError(t, err)
+	require.Len(t, infos, 0)
+}
+
 func TestGetAllCaptureLeases(t *testing.T) {
 	s := &Tester{}
 	s.SetUpTest(t)
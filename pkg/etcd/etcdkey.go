@@ -28,12 +28,19 @@ const (
 
 	ownerKey        = "/owner"
 	captureKey      = "/capture"
+	namespaceKey    = "/namespace"
+	notificationKey = "/notification"
 	taskPositionKey = "/task/position"
 
 	// ChangefeedInfoKey is the key path for changefeed info
 	ChangefeedInfoKey = "/changefeed/info"
 	// ChangefeedStatusKey is the key path for changefeed status
 	ChangefeedStatusKey = "/changefeed/status"
+	// ChangefeedHistoryKey is the key path for changefeed info history
+	ChangefeedHistoryKey = "/changefeed/history"
+	// ChangefeedSnapshotKey is the key path for the initial snapshot
+	// progress of tables
+	ChangefeedSnapshotKey = "/changefeed/snapshot"
 	// metaVersionKey is the key path for metadata version
 	metaVersionKey = "/meta/meta-version"
 	upstreamKey    = "/upstream"
@@ -63,6 +70,10 @@ const (
 	CDCKeyTypeTaskPosition
 	CDCKeyTypeMetaVersion
 	CDCKeyTypeUpStream
+	CDCKeyTypeNamespace
+	CDCKeyTypeChangefeedHistory
+	CDCKeyTypeTableSnapshot
+	CDCKeyTypeNotification
 )
 
 // CDCKey represents an etcd key which is defined by TiCDC
@@ -97,6 +108,10 @@ type CDCKey struct {
 	ClusterID    string
 	UpstreamID   model.UpstreamID
 	Namespace    string
+	TableID      model.TableID
+	// Notification is the webhook ID and the event ID of a pending
+	// notification event, separated by a slash.
+	Notification string
 }
 
 // BaseKey is the common prefix of the keys with cluster id in CDC
@@ -135,6 +150,20 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 			k.OwnerLeaseID = ""
 		case strings.HasPrefix(key, metaVersionKey):
 			k.Tp = CDCKeyTypeMetaVersion
+		case strings.HasPrefix(key, namespaceKey):
+			namespace, ok := trimKeyPrefix(key, namespaceKey)
+			if !ok {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			k.Tp = CDCKeyTypeNamespace
+			k.Namespace = namespace
+		case strings.HasPrefix(key, notificationKey):
+			notification, ok := trimKeyPrefix(key, notificationKey)
+			if !ok {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			k.Tp = CDCKeyTypeNotification
+			k.Notification = notification
 		default:
 			return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
 		}
@@ -167,6 +196,39 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 				ID:        key[len(ChangefeedStatusKey)+1:],
 			}
 			k.OwnerLeaseID = ""
+		case strings.HasPrefix(key, ChangefeedHistoryKey):
+			id, ok := trimKeyPrefix(key, ChangefeedHistoryKey)
+			if !ok {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			k.Tp = CDCKeyTypeChangefeedHistory
+			k.CaptureID = ""
+			k.ChangefeedID = model.ChangeFeedID{
+				Namespace: namespace,
+				ID:        id,
+			}
+			k.OwnerLeaseID = ""
+		case strings.HasPrefix(key, ChangefeedSnapshotKey):
+			suffix, ok := trimKeyPrefix(key, ChangefeedSnapshotKey)
+			if !ok {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			splitKey := strings.SplitN(suffix, "/", 2)
+			if len(splitKey) != 2 {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			tableID, err := strconv.ParseInt(splitKey[1], 10, 64)
+			if err != nil {
+				return cerror.ErrInvalidEtcdKey.GenWithStackByArgs(key)
+			}
+			k.Tp = CDCKeyTypeTableSnapshot
+			k.CaptureID = ""
+			k.ChangefeedID = model.ChangeFeedID{
+				Namespace: namespace,
+				ID:        splitKey[0],
+			}
+			k.TableID = tableID
+			k.OwnerLeaseID = ""
 		case strings.HasPrefix(key, taskPositionKey):
 			splitKey := strings.SplitN(key[len(taskPositionKey)+1:], "/", 2)
 			if len(splitKey) != 2 {
@@ -186,6 +248,15 @@ func (k *CDCKey) Parse(clusterID, key string) error {
 	return nil
 }
 
+// trimKeyPrefix trims the prefix and the separator after it from the key,
+// it returns false if the key doesn't continue with a separator.
+func trimKeyPrefix(key, prefix string) (string, bool) {
+	if len(key) <= len(prefix) || key[len(prefix)] != '/' {
+		return "", false
+	}
+	return key[len(prefix)+1:], true
+}
+
 func (k *CDCKey) String() string {
 	switch k.Tp {
 	case CDCKeyTypeOwner:
@@ -201,11 +272,21 @@ func (k *CDCKey) String() string {
 	case CDCKeyTypeChangeFeedStatus:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedStatusKey +
 			"/" + k.ChangefeedID.ID
+	case CDCKeyTypeChangefeedHistory:
+		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedHistoryKey +
+			"/" + k.ChangefeedID.ID
+	case CDCKeyTypeTableSnapshot:
+		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + ChangefeedSnapshotKey +
+			"/" + k.ChangefeedID.ID + "/" + strconv.FormatInt(k.TableID, 10)
 	case CDCKeyTypeTaskPosition:
 		return NamespacedPrefix(k.ClusterID, k.ChangefeedID.Namespace) + taskPositionKey +
 			"/" + k.CaptureID + "/" + k.ChangefeedID.ID
 	case CDCKeyTypeMetaVersion:
 		return BaseKey(k.ClusterID) + metaPrefix + metaVersionKey
+	case CDCKeyTypeNamespace:
+		return BaseKey(k.ClusterID) + metaPrefix + namespaceKey + "/" + k.Namespace
+	case CDCKeyTypeNotification:
+		return BaseKey(k.ClusterID) + metaPrefix + notificationKey + "/" + k.Notification
 	case CDCKeyTypeUpStream:
 		return fmt.Sprintf("%s%s/%d",
 			NamespacedPrefix(k.ClusterID, k.Namespace),
@@ -74,6 +74,25 @@ func TestEtcdKey(t *testing.T) {
 			ClusterID:    DefaultCDCClusterID,
 			Namespace:    model.DefaultNamespace,
 		},
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/snapshot/test-changefeed/66",
+		expected: &CDCKey{
+			Tp:           CDCKeyTypeTableSnapshot,
+			ChangefeedID: model.DefaultChangeFeedID("test-changefeed"),
+			TableID:      66,
+			ClusterID:    DefaultCDCClusterID,
+			Namespace:    model.DefaultNamespace,
+		},
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/history/test-changefeed",
+		expected: &CDCKey{
+			Tp:           CDCKeyTypeChangefeedHistory,
+			ChangefeedID: model.DefaultChangeFeedID("test-changefeed"),
+			ClusterID:    DefaultCDCClusterID,
+			Namespace:    model.DefaultNamespace,
+		},
 	}, {
 		key: "/tidb/cdc/default/name/task" +
 			"/position/6bbc01c8-0605-4f86-a0f9-b3119109b225/test-changefeed",
@@ -111,6 +130,21 @@ func TestEtcdKey(t *testing.T) {
 			Tp:        CDCKeyTypeMetaVersion,
 			ClusterID: DefaultCDCClusterID,
 		},
+	}, {
+		key: fmt.Sprintf("%s%s/tenant-a", DefaultClusterAndMetaPrefix, namespaceKey),
+		expected: &CDCKey{
+			Tp:        CDCKeyTypeNamespace,
+			ClusterID: DefaultCDCClusterID,
+			Namespace: "tenant-a",
+		},
+	}, {
+		key: fmt.Sprintf("%s%s/3f2a1b/6bbc01c8-0605-4f86-a0f9-b3119109b225",
+			DefaultClusterAndMetaPrefix, notificationKey),
+		expected: &CDCKey{
+			Tp:           CDCKeyTypeNotification,
+			ClusterID:    DefaultCDCClusterID,
+			Notification: "3f2a1b/6bbc01c8-0605-4f86-a0f9-b3119109b225",
+		},
 	}}
 	for _, tc := range testcases {
 		k := new(CDCKey)
@@ -137,6 +171,28 @@ func TestEtcdKeyParseError(t *testing.T) {
 		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
 			"/task/position/6bbc01c8-0605-4f86-a0f9-b3119109b225",
 		error: true,
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/snapshot/test-changefeed",
+		error: true,
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			"/changefeed/snapshot/test-changefeed/abc",
+		error: true,
+	}, {
+		key:   "/tidb/cdc/default" + metaPrefix + namespaceKey,
+		error: true,
+	}, {
+		key:   "/tidb/cdc/default" + metaPrefix + notificationKey,
+		error: true,
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			ChangefeedHistoryKey,
+		error: true,
+	}, {
+		key: fmt.Sprintf("%s", DefaultClusterAndNamespacePrefix) +
+			ChangefeedSnapshotKey,
+		error: true,
 	}, {
 		key:   "/tidb/cd",
 		error: true,
@@ -160,7 +216,7 @@ func TestEtcdKeyParseError(t *testing.T) {
 		}
 	}
 	k := new(CDCKey)
-	k.Tp = CDCKeyTypeUpStream + 1
+	k.Tp = CDCKeyTypeNotification + 1
 	require.Panics(t, func() {
 		_ = k.String()
 	})
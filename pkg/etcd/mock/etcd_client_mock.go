@@ -50,6 +50,62 @@ func (mr *MockCDCEtcdClientForAPIMockRecorder) CreateChangefeedInfo(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChangefeedInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).CreateChangefeedInfo), arg0, arg1, arg2, arg3)
 }
 
+// CreateNamespace mocks base method.
+func (m *MockCDCEtcdClientForAPI) CreateNamespace(ctx context.Context, info *model.NamespaceInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNamespace", ctx, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNamespace indicates an expected call of CreateNamespace.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) CreateNamespace(ctx, info interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNamespace", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).CreateNamespace), ctx, info)
+}
+
+// CreateUpstreamInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) CreateUpstreamInfo(ctx context.Context, info *model.UpstreamInfo, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpstreamInfo", ctx, info, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUpstreamInfo indicates an expected call of CreateUpstreamInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) CreateUpstreamInfo(ctx, info, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpstreamInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).CreateUpstreamInfo), ctx, info, namespace)
+}
+
+// DeleteNamespace mocks base method.
+func (m *MockCDCEtcdClientForAPI) DeleteNamespace(ctx context.Context, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespace", ctx, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNamespace indicates an expected call of DeleteNamespace.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) DeleteNamespace(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespace", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).DeleteNamespace), ctx, namespace)
+}
+
+// DeleteUpstreamInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) DeleteUpstreamInfo(ctx context.Context, upstreamID model.UpstreamID, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUpstreamInfo", ctx, upstreamID, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUpstreamInfo indicates an expected call of DeleteUpstreamInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) DeleteUpstreamInfo(ctx, upstreamID, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUpstreamInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).DeleteUpstreamInfo), ctx, upstreamID, namespace)
+}
+
 // GetAllCDCInfo mocks base method.
 func (m *MockCDCEtcdClientForAPI) GetAllCDCInfo(ctx context.Context) ([]*mvccpb.KeyValue, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +121,51 @@ func (mr *MockCDCEtcdClientForAPIMockRecorder) GetAllCDCInfo(ctx interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCDCInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetAllCDCInfo), ctx)
 }
 
+// GetAllNamespaceInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) GetAllNamespaceInfo(ctx context.Context) (map[string]*model.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllNamespaceInfo", ctx)
+	ret0, _ := ret[0].(map[string]*model.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllNamespaceInfo indicates an expected call of GetAllNamespaceInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) GetAllNamespaceInfo(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNamespaceInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetAllNamespaceInfo), ctx)
+}
+
+// GetAllUpstreamInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) GetAllUpstreamInfo(ctx context.Context, namespace string) (map[model.UpstreamID]*model.UpstreamInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUpstreamInfo", ctx, namespace)
+	ret0, _ := ret[0].(map[model.UpstreamID]*model.UpstreamInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUpstreamInfo indicates an expected call of GetAllUpstreamInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) GetAllUpstreamInfo(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUpstreamInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetAllUpstreamInfo), ctx, namespace)
+}
+
+// GetChangeFeedHistory mocks base method.
+func (m *MockCDCEtcdClientForAPI) GetChangeFeedHistory(ctx context.Context, changeFeedID model.ChangeFeedID) (*model.ChangeFeedHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeFeedHistory", ctx, changeFeedID)
+	ret0, _ := ret[0].(*model.ChangeFeedHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeFeedHistory indicates an expected call of GetChangeFeedHistory.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) GetChangeFeedHistory(ctx, changeFeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedHistory", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetChangeFeedHistory), ctx, changeFeedID)
+}
+
 // GetChangeFeedInfo mocks base method.
 func (m *MockCDCEtcdClientForAPI) GetChangeFeedInfo(ctx context.Context, id model.ChangeFeedID) (*model.ChangeFeedInfo, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +181,21 @@ func (mr *MockCDCEtcdClientForAPIMockRecorder) GetChangeFeedInfo(ctx, id interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetChangeFeedInfo), ctx, id)
 }
 
+// GetChangeFeedSnapshotProgress mocks base method.
+func (m *MockCDCEtcdClientForAPI) GetChangeFeedSnapshotProgress(ctx context.Context, changeFeedID model.ChangeFeedID) (map[model.TableID]*model.TableSnapshotProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangeFeedSnapshotProgress", ctx, changeFeedID)
+	ret0, _ := ret[0].(map[model.TableID]*model.TableSnapshotProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeFeedSnapshotProgress indicates an expected call of GetChangeFeedSnapshotProgress.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) GetChangeFeedSnapshotProgress(ctx, changeFeedID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeFeedSnapshotProgress", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetChangeFeedSnapshotProgress), ctx, changeFeedID)
+}
+
 // GetChangeFeedStatus mocks base method.
 func (m *MockCDCEtcdClientForAPI) GetChangeFeedStatus(ctx context.Context, id model.ChangeFeedID) (*model.ChangeFeedStatus, int64, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +240,21 @@ func (mr *MockCDCEtcdClientForAPIMockRecorder) GetGCServiceID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGCServiceID", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetGCServiceID))
 }
 
+// GetNamespaceInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) GetNamespaceInfo(ctx context.Context, namespace string) (*model.NamespaceInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceInfo", ctx, namespace)
+	ret0, _ := ret[0].(*model.NamespaceInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceInfo indicates an expected call of GetNamespaceInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) GetNamespaceInfo(ctx, namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).GetNamespaceInfo), ctx, namespace)
+}
+
 // GetUpstreamInfo mocks base method.
 func (m *MockCDCEtcdClientForAPI) GetUpstreamInfo(ctx context.Context, upstreamID model.UpstreamID, namespace string) (*model.UpstreamInfo, error) {
 	m.ctrl.T.Helper()
@@ -154,15 +285,43 @@ func (mr *MockCDCEtcdClientForAPIMockRecorder) SaveChangeFeedInfo(ctx, info, cha
 }
 
 // UpdateChangefeedAndUpstream mocks base method.
-func (m *MockCDCEtcdClientForAPI) UpdateChangefeedAndUpstream(ctx context.Context, upstreamInfo *model.UpstreamInfo, changeFeedInfo *model.ChangeFeedInfo, changeFeedID model.ChangeFeedID) error {
+func (m *MockCDCEtcdClientForAPI) UpdateChangefeedAndUpstream(ctx context.Context, upstreamInfo *model.UpstreamInfo, changeFeedInfo *model.ChangeFeedInfo, changeFeedID model.ChangeFeedID, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateChangefeedAndUpstream", ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	ret := m.ctrl.Call(m, "UpdateChangefeedAndUpstream", ctx, upstreamInfo, changeFeedInfo, changeFeedID, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateChangefeedAndUpstream indicates an expected call of UpdateChangefeedAndUpstream.
-func (mr *MockCDCEtcdClientForAPIMockRecorder) UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID interface{}) *gomock.Call {
+func (mr *MockCDCEtcdClientForAPIMockRecorder) UpdateChangefeedAndUpstream(ctx, upstreamInfo, changeFeedInfo, changeFeedID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangefeedAndUpstream", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).UpdateChangefeedAndUpstream), ctx, upstreamInfo, changeFeedInfo, changeFeedID, reason)
+}
+
+// UpdateChangefeedInfo mocks base method.
+func (m *MockCDCEtcdClientForAPI) UpdateChangefeedInfo(ctx context.Context, changeFeedInfo *model.ChangeFeedInfo, changeFeedID model.ChangeFeedID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChangefeedInfo", ctx, changeFeedInfo, changeFeedID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChangefeedInfo indicates an expected call of UpdateChangefeedInfo.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) UpdateChangefeedInfo(ctx, changeFeedInfo, changeFeedID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangefeedInfo", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).UpdateChangefeedInfo), ctx, changeFeedInfo, changeFeedID, reason)
+}
+
+// UpdateNamespace mocks base method.
+func (m *MockCDCEtcdClientForAPI) UpdateNamespace(ctx context.Context, info *model.NamespaceInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNamespace", ctx, info)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNamespace indicates an expected call of UpdateNamespace.
+func (mr *MockCDCEtcdClientForAPIMockRecorder) UpdateNamespace(ctx, info interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChangefeedAndUpstream", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).UpdateChangefeedAndUpstream), ctx, upstreamInfo, changeFeedInfo, changeFeedID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNamespace", reflect.TypeOf((*MockCDCEtcdClientForAPI)(nil).UpdateNamespace), ctx, info)
 }
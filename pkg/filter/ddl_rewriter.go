@@ -0,0 +1,365 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/types"
+	tfilter "github.com/pingcap/tidb/util/table-filter"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+// ddlRewriteRule only be used by DDLRewriter.
+type ddlRewriteRule struct {
+	tf     tfilter.Filter
+	action config.DDLRewriteAction
+	// typeMapping maps a lower case column type name to a new column type.
+	typeMapping map[string]*types.FieldType
+	charset     string
+	collation   string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func newDDLRewriteRule(cfg *config.DDLRewriteRule) (*ddlRewriteRule, error) {
+	tf, err := tfilter.Parse(cfg.Matcher)
+	if err != nil {
+		return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, cfg.Matcher)
+	}
+	rule := &ddlRewriteRule{
+		tf:          tf,
+		action:      cfg.Action,
+		charset:     cfg.Charset,
+		collation:   cfg.Collation,
+		replacement: cfg.Replacement,
+	}
+	switch cfg.Action {
+	case config.DDLRewriteMapColumnType:
+		rule.typeMapping = make(map[string]*types.FieldType, len(cfg.TypeMapping))
+		for from, to := range cfg.TypeMapping {
+			tp, err := parseColumnType(to)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, to)
+			}
+			rule.typeMapping[strings.ToLower(from)] = tp
+		}
+	case config.DDLRewriteRegexpReplace:
+		rule.pattern, err = regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, cerror.WrapError(cerror.ErrFilterRuleInvalid, err, cfg.Pattern)
+		}
+	}
+	return rule, nil
+}
+
+// parseColumnType parses a column type like "varchar(255)".
+func parseColumnType(tp string) (*types.FieldType, error) {
+	stmt, err := parser.New().ParseOneStmt(
+		fmt.Sprintf("CREATE TABLE t (c %s)", tp), "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stmt.(*ast.CreateTableStmt).Cols[0].Tp, nil
+}
+
+func (r *ddlRewriteRule) match(schema, table string) bool {
+	if table == "" {
+		return r.tf.MatchSchema(schema)
+	}
+	return r.tf.MatchTable(schema, table)
+}
+
+// apply rewrites the statement in place and returns true if it is changed.
+func (r *ddlRewriteRule) apply(stmt ast.StmtNode) bool {
+	changed := false
+	switch r.action {
+	case config.DDLRewriteStripAutoRandom:
+		forEachColumnDef(stmt, func(col *ast.ColumnDef) {
+			options := col.Options[:0]
+			for _, option := range col.Options {
+				if option.Tp == ast.ColumnOptionAutoRandom {
+					changed = true
+					continue
+				}
+				options = append(options, option)
+			}
+			col.Options = options
+		})
+		changed = stripTableOptions(stmt, ast.TableOptionAutoRandomBase) || changed
+	case config.DDLRewriteStripShardRowIDBits:
+		changed = stripTableOptions(stmt,
+			ast.TableOptionShardRowID, ast.TableOptionPreSplitRegion)
+	case config.DDLRewriteStripPlacementPolicy:
+		changed = stripTableOptions(stmt, ast.TableOptionPlacementPolicy)
+		forEachDatabaseOptions(stmt, func(options []*ast.DatabaseOption) []*ast.DatabaseOption {
+			res := options[:0]
+			for _, option := range options {
+				if option.Tp == ast.DatabaseOptionPlacementPolicy {
+					changed = true
+					continue
+				}
+				res = append(res, option)
+			}
+			return res
+		})
+	case config.DDLRewriteMapColumnType:
+		forEachColumnDef(stmt, func(col *ast.ColumnDef) {
+			if col.Tp == nil {
+				return
+			}
+			name := types.TypeToStr(col.Tp.GetType(), col.Tp.GetCharset())
+			if tp, ok := r.typeMapping[strings.ToLower(name)]; ok {
+				col.Tp = tp.Clone()
+				changed = true
+			}
+		})
+	case config.DDLRewriteCharset:
+		changed = r.rewriteCharset(stmt)
+	}
+	return changed
+}
+
+func (r *ddlRewriteRule) rewriteCharset(stmt ast.StmtNode) bool {
+	changed := false
+	forEachTableOptions(stmt, func(options []*ast.TableOption) []*ast.TableOption {
+		for _, option := range options {
+			if option.Tp == ast.TableOptionCharset && r.charset != "" &&
+				option.StrValue != r.charset {
+				option.StrValue = r.charset
+				changed = true
+			}
+			if option.Tp == ast.TableOptionCollate && r.collation != "" &&
+				option.StrValue != r.collation {
+				option.StrValue = r.collation
+				changed = true
+			}
+		}
+		return options
+	})
+	forEachDatabaseOptions(stmt, func(options []*ast.DatabaseOption) []*ast.DatabaseOption {
+		for _, option := range options {
+			if option.Tp == ast.DatabaseOptionCharset && r.charset != "" &&
+				option.Value != r.charset {
+				option.Value = r.charset
+				changed = true
+			}
+			if option.Tp == ast.DatabaseOptionCollate && r.collation != "" &&
+				option.Value != r.collation {
+				option.Value = r.collation
+				changed = true
+			}
+		}
+		return options
+	})
+	forEachColumnDef(stmt, func(col *ast.ColumnDef) {
+		if col.Tp != nil && col.Tp.GetCharset() != "" && r.charset != "" &&
+			col.Tp.GetCharset() != r.charset {
+			col.Tp.SetCharset(r.charset)
+			changed = true
+		}
+		if col.Tp != nil && col.Tp.GetCollate() != "" && r.collation != "" &&
+			col.Tp.GetCollate() != r.collation {
+			col.Tp.SetCollate(r.collation)
+			changed = true
+		}
+		for _, option := range col.Options {
+			if option.Tp == ast.ColumnOptionCollate && r.collation != "" &&
+				option.StrValue != r.collation {
+				option.StrValue = r.collation
+				changed = true
+			}
+		}
+	})
+	return changed
+}
+
+func forEachColumnDef(stmt ast.StmtNode, fn func(col *ast.ColumnDef)) {
+	switch s := stmt.(type) {
+	case *ast.CreateTableStmt:
+		for _, col := range s.Cols {
+			fn(col)
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range s.Specs {
+			for _, col := range spec.NewColumns {
+				fn(col)
+			}
+		}
+	}
+}
+
+func forEachTableOptions(
+	stmt ast.StmtNode, fn func(options []*ast.TableOption) []*ast.TableOption,
+) {
+	switch s := stmt.(type) {
+	case *ast.CreateTableStmt:
+		s.Options = fn(s.Options)
+	case *ast.AlterTableStmt:
+		specs := s.Specs[:0]
+		for _, spec := range s.Specs {
+			spec.Options = fn(spec.Options)
+			// An ALTER TABLE spec which has no option left is invalid.
+			if spec.Tp == ast.AlterTableOption && len(spec.Options) == 0 {
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		s.Specs = specs
+	}
+}
+
+func forEachDatabaseOptions(
+	stmt ast.StmtNode, fn func(options []*ast.DatabaseOption) []*ast.DatabaseOption,
+) {
+	switch s := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		s.Options = fn(s.Options)
+	case *ast.AlterDatabaseStmt:
+		s.Options = fn(s.Options)
+	}
+}
+
+// stripTableOptions removes the table options of the given types and
+// returns true if any option is removed.
+func stripTableOptions(stmt ast.StmtNode, tps ...ast.TableOptionType) bool {
+	changed := false
+	forEachTableOptions(stmt, func(options []*ast.TableOption) []*ast.TableOption {
+		res := options[:0]
+		for _, option := range options {
+			stripped := false
+			for _, tp := range tps {
+				if option.Tp == tp {
+					stripped = true
+					break
+				}
+			}
+			if stripped {
+				changed = true
+				continue
+			}
+			res = append(res, option)
+		}
+		return res
+	})
+	return changed
+}
+
+// DDLRewriter rewrites the queries of DDL events by the DDL rewrite rules
+// of a changefeed, so that they can be executed by the downstream.
+type DDLRewriter struct {
+	rules []*ddlRewriteRule
+}
+
+// NewDDLRewriter creates a DDLRewriter by the filter config.
+func NewDDLRewriter(cfg *config.FilterConfig) (*DDLRewriter, error) {
+	r := &DDLRewriter{}
+	if cfg == nil {
+		return r, nil
+	}
+	for _, ruleCfg := range cfg.DDLRewriteRules {
+		rule, err := newDDLRewriteRule(ruleCfg)
+		if err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, rule)
+	}
+	return r, nil
+}
+
+// Rewrite returns the rewritten query of the DDL event, the original query
+// is returned if no rule is applied. An empty query is returned if nothing
+// is left to execute, e.g. an ALTER TABLE which only sets SHARD_ROW_ID_BITS.
+// The DDL event is not modified.
+func (r *DDLRewriter) Rewrite(ddl *model.DDLEvent) (string, error) {
+	if r == nil || len(r.rules) == 0 || ddl.TableInfo == nil {
+		return ddl.Query, nil
+	}
+	schema, table := ddl.TableInfo.Schema, ddl.TableInfo.Table
+	var matched []*ddlRewriteRule
+	for _, rule := range r.rules {
+		if rule.match(schema, table) {
+			matched = append(matched, rule)
+		}
+	}
+	if len(matched) == 0 {
+		return ddl.Query, nil
+	}
+
+	stmt, err := parser.New().ParseOneStmt(ddl.Query, "", "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	query := ddl.Query
+	changed := false
+	for _, rule := range matched {
+		switch rule.action {
+		case config.DDLRewriteTruncateToDelete:
+			if _, ok := stmt.(*ast.TruncateTableStmt); ok {
+				query = fmt.Sprintf("DELETE FROM %s", quotes.QuoteSchema(schema, table))
+				stmt, err = parser.New().ParseOneStmt(query, "", "")
+				if err != nil {
+					return "", errors.Trace(err)
+				}
+				changed = false
+			}
+		case config.DDLRewriteRegexpReplace:
+			if changed {
+				if query, err = restoreStmt(stmt); err != nil {
+					return "", err
+				}
+				changed = false
+			}
+			newQuery := rule.pattern.ReplaceAllString(query, rule.replacement)
+			if newQuery != query {
+				query = newQuery
+				stmt, err = parser.New().ParseOneStmt(query, "", "")
+				if err != nil {
+					return "", cerror.WrapError(cerror.ErrFilterRuleInvalid, err,
+						fmt.Sprintf("invalid query rewritten by %s: %s", rule.pattern, query))
+				}
+			}
+		default:
+			changed = rule.apply(stmt) || changed
+		}
+	}
+	if changed {
+		return restoreStmt(stmt)
+	}
+	return query, nil
+}
+
+func restoreStmt(stmt ast.StmtNode) (string, error) {
+	if s, ok := stmt.(*ast.AlterTableStmt); ok && len(s.Specs) == 0 {
+		return "", nil
+	}
+	var sb strings.Builder
+	restoreFlags := format.RestoreTiDBSpecialComment |
+		format.RestoreNameBackQuotes |
+		format.RestoreKeyWordUppercase |
+		format.RestoreStringSingleQuotes
+	if err := stmt.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDDLRewriter(t *testing.T) {
+	t.Parallel()
+	type innerCase struct {
+		schema   string
+		table    string
+		query    string
+		expected string
+	}
+
+	testCases := []struct {
+		rules []*config.DDLRewriteRule
+		cases []innerCase
+	}{
+		{
+			rules: []*config.DDLRewriteRule{
+				{Matcher: []string{"test.*"}, Action: config.DDLRewriteStripAutoRandom},
+				{Matcher: []string{"test.*"}, Action: config.DDLRewriteStripShardRowIDBits},
+				{Matcher: []string{"test.*"}, Action: config.DDLRewriteStripPlacementPolicy},
+			},
+			cases: []innerCase{
+				{
+					schema:   "test",
+					table:    "t1",
+					query:    "CREATE TABLE t1 (id BIGINT PRIMARY KEY AUTO_RANDOM(5), a INT)",
+					expected: "CREATE TABLE `t1` (`id` BIGINT PRIMARY KEY,`a` INT)",
+				},
+				{
+					schema:   "test",
+					table:    "t2",
+					query:    "CREATE TABLE t2 (id INT) SHARD_ROW_ID_BITS = 4 PRE_SPLIT_REGIONS = 2 PLACEMENT POLICY = p1",
+					expected: "CREATE TABLE `t2` (`id` INT)",
+				},
+				{
+					schema:   "test",
+					table:    "t2",
+					query:    "ALTER TABLE t2 SHARD_ROW_ID_BITS = 4",
+					expected: "",
+				},
+				{
+					schema:   "test",
+					table:    "t2",
+					query:    "ALTER TABLE t2 SHARD_ROW_ID_BITS = 4, COMMENT = 'abc'",
+					expected: "ALTER TABLE `t2` COMMENT = 'abc'",
+				},
+				{
+					schema:   "test",
+					query:    "CREATE DATABASE test PLACEMENT POLICY = p1",
+					expected: "CREATE DATABASE `test`",
+				},
+				// nothing to rewrite
+				{
+					schema:   "test",
+					table:    "t3",
+					query:    "create table t3 (id int)",
+					expected: "create table t3 (id int)",
+				},
+				// not matched
+				{
+					schema:   "test1",
+					table:    "t1",
+					query:    "CREATE TABLE t1 (id BIGINT PRIMARY KEY AUTO_RANDOM(5))",
+					expected: "CREATE TABLE t1 (id BIGINT PRIMARY KEY AUTO_RANDOM(5))",
+				},
+			},
+		},
+		{
+			rules: []*config.DDLRewriteRule{
+				{
+					Matcher: []string{"*.*"}, Action: config.DDLRewriteMapColumnType,
+					TypeMapping: map[string]string{"JSON": "longtext", "bit": "tinyint(1)"},
+				},
+				{
+					Matcher: []string{"*.*"}, Action: config.DDLRewriteCharset,
+					Charset: "utf8mb4", Collation: "utf8mb4_general_ci",
+				},
+			},
+			cases: []innerCase{
+				{
+					schema:   "test",
+					table:    "t1",
+					query:    "CREATE TABLE t1 (a JSON, b BIT(1), c VARCHAR(10) CHARACTER SET utf8 COLLATE utf8_bin) CHARSET = utf8 COLLATE = utf8_bin",
+					expected: "CREATE TABLE `t1` (`a` LONGTEXT,`b` TINYINT(1),`c` VARCHAR(10) CHARACTER SET UTF8MB4 COLLATE utf8mb4_general_ci) DEFAULT CHARACTER SET = UTF8MB4 DEFAULT COLLATE = UTF8MB4_GENERAL_CI",
+				},
+				{
+					schema:   "test",
+					table:    "t1",
+					query:    "ALTER TABLE t1 ADD COLUMN d JSON",
+					expected: "ALTER TABLE `t1` ADD COLUMN `d` LONGTEXT",
+				},
+				{
+					schema:   "test",
+					query:    "CREATE DATABASE test CHARACTER SET latin1",
+					expected: "CREATE DATABASE `test` CHARACTER SET = utf8mb4",
+				},
+			},
+		},
+		{
+			rules: []*config.DDLRewriteRule{
+				{Matcher: []string{"test.*"}, Action: config.DDLRewriteTruncateToDelete},
+				{
+					Matcher: []string{"*.*"}, Action: config.DDLRewriteRegexpReplace,
+					Pattern: "(?i)\\s*/\\*T!\\[clustered_index\\] (NON)?CLUSTERED \\*/", Replacement: "",
+				},
+			},
+			cases: []innerCase{
+				{
+					schema:   "test",
+					table:    "t1",
+					query:    "TRUNCATE TABLE t1",
+					expected: "DELETE FROM `test`.`t1`",
+				},
+				{
+					schema:   "test1",
+					table:    "t1",
+					query:    "TRUNCATE TABLE t1",
+					expected: "TRUNCATE TABLE t1",
+				},
+				{
+					schema:   "test1",
+					table:    "t1",
+					query:    "CREATE TABLE t1 (id INT PRIMARY KEY /*T![clustered_index] CLUSTERED */)",
+					expected: "CREATE TABLE t1 (id INT PRIMARY KEY)",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := NewDDLRewriter(&config.FilterConfig{DDLRewriteRules: tc.rules})
+		require.Nil(t, err)
+		for _, c := range tc.cases {
+			ddl := &model.DDLEvent{
+				Query:     c.query,
+				TableInfo: &model.SimpleTableInfo{Schema: c.schema, Table: c.table},
+			}
+			query, err := r.Rewrite(ddl)
+			require.Nil(t, err)
+			require.Equal(t, c.expected, query, c.query)
+			require.Equal(t, c.query, ddl.Query)
+		}
+	}
+
+	// invalid type mapping
+	_, err := NewDDLRewriter(&config.FilterConfig{
+		DDLRewriteRules: []*config.DDLRewriteRule{{
+			Matcher: []string{"*.*"}, Action: config.DDLRewriteMapColumnType,
+			TypeMapping: map[string]string{"json": "not a type"},
+		}},
+	})
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+
+	// a regexp replacement can not produce an invalid query
+	r, err := NewDDLRewriter(&config.FilterConfig{
+		DDLRewriteRules: []*config.DDLRewriteRule{{
+			Matcher: []string{"*.*"}, Action: config.DDLRewriteRegexpReplace,
+			Pattern: "TABLE", Replacement: "TABEL",
+		}},
+	})
+	require.Nil(t, err)
+	_, err = r.Rewrite(&model.DDLEvent{
+		Query:     "CREATE TABLE t1 (id INT)",
+		TableInfo: &model.SimpleTableInfo{Schema: "test", Table: "t1"},
+	})
+	require.Regexp(t, ".*ErrFilterRuleInvalid.*", err)
+}
@@ -148,6 +148,19 @@ func shouldDiscardByBuiltInDDLAllowlist(ddlType timodel.ActionType) bool {
 	return true
 }
 
+// UnsupportedDDLTypes returns the DDL types in the allowlist which are
+// discarded by the built-in DDL allowlist, replicating them is not
+// officially supported and may break the downstream.
+func UnsupportedDDLTypes(allowlist []timodel.ActionType) []timodel.ActionType {
+	var unsupported []timodel.ActionType
+	for _, ddlType := range allowlist {
+		if shouldDiscardByBuiltInDDLAllowlist(ddlType) {
+			unsupported = append(unsupported, ddlType)
+		}
+	}
+	return unsupported
+}
+
 func completeExpression(suffix string) string {
 	if suffix == "" {
 		return suffix
@@ -157,3 +157,16 @@ func TestShouldDiscardByBuiltInDDLAllowlist(t *testing.T) {
 		require.Equal(t, c.discard, shouldDiscardByBuiltInDDLAllowlist(c.jobType))
 	}
 }
+
+func TestUnsupportedDDLTypes(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, UnsupportedDDLTypes(nil))
+	require.Empty(t, UnsupportedDDLTypes([]timodel.ActionType{
+		timodel.ActionCreateTable, timodel.ActionAddColumn,
+	}))
+	require.Equal(t, []timodel.ActionType{
+		timodel.ActionAddForeignKey, timodel.ActionLockTable,
+	}, UnsupportedDDLTypes([]timodel.ActionType{
+		timodel.ActionAddForeignKey, timodel.ActionCreateTable, timodel.ActionLockTable,
+	}))
+}
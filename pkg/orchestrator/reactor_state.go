@@ -32,6 +32,7 @@ type GlobalReactorState struct {
 	Owner          map[string]struct{}
 	Captures       map[model.CaptureID]*model.CaptureInfo
 	Upstreams      map[model.UpstreamID]*model.UpstreamInfo
+	Namespaces     map[string]*model.NamespaceInfo
 	Changefeeds    map[model.ChangeFeedID]*ChangefeedReactorState
 	pendingPatches [][]DataPatch
 
@@ -48,6 +49,7 @@ func NewGlobalState(clusterID string) *GlobalReactorState {
 		Owner:       map[string]struct{}{},
 		Captures:    make(map[model.CaptureID]*model.CaptureInfo),
 		Upstreams:   make(map[model.UpstreamID]*model.UpstreamInfo),
+		Namespaces:  make(map[string]*model.NamespaceInfo),
 		Changefeeds: make(map[model.ChangeFeedID]*ChangefeedReactorState),
 	}
 }
@@ -93,7 +95,9 @@ func (s *GlobalReactorState) Update(key util.EtcdKey, value []byte, _ bool) erro
 		s.Captures[k.CaptureID] = &newCaptureInfo
 	case etcd.CDCKeyTypeChangefeedInfo,
 		etcd.CDCKeyTypeChangeFeedStatus,
-		etcd.CDCKeyTypeTaskPosition:
+		etcd.CDCKeyTypeTaskPosition,
+		etcd.CDCKeyTypeChangefeedHistory,
+		etcd.CDCKeyTypeTableSnapshot:
 		changefeedState, exist := s.Changefeeds[k.ChangefeedID]
 		if !exist {
 			if value == nil {
@@ -126,7 +130,23 @@ func (s *GlobalReactorState) Update(key util.EtcdKey, value []byte, _ bool) erro
 			zap.Uint64("upstream", k.UpstreamID),
 			zap.Any("info", newUpstreamInfo))
 		s.Upstreams[k.UpstreamID] = &newUpstreamInfo
-	case etcd.CDCKeyTypeMetaVersion:
+	case etcd.CDCKeyTypeNamespace:
+		if value == nil {
+			log.Info("namespace is removed",
+				zap.String("namespace", k.Namespace))
+			delete(s.Namespaces, k.Namespace)
+			return nil
+		}
+		var newNamespaceInfo model.NamespaceInfo
+		err := newNamespaceInfo.Unmarshal(value)
+		if err != nil {
+			return cerrors.ErrUnmarshalFailed.Wrap(err).GenWithStackByArgs()
+		}
+		log.Info("namespace is updated",
+			zap.String("namespace", k.Namespace),
+			zap.Any("info", newNamespaceInfo))
+		s.Namespaces[k.Namespace] = &newNamespaceInfo
+	case etcd.CDCKeyTypeMetaVersion, etcd.CDCKeyTypeNotification:
 	default:
 		log.Warn("receive an unexpected etcd event", zap.String("key", key.String()), zap.ByteString("value", value))
 	}
@@ -162,6 +182,13 @@ type ChangefeedReactorState struct {
 	Status        *model.ChangeFeedStatus
 	TaskPositions map[model.CaptureID]*model.TaskPosition
 
+	// hasHistory is true if the info history of the changefeed exists, the
+	// history itself is only used by the open api.
+	hasHistory bool
+	// snapshotTables are the tables whose initial snapshot progress exists,
+	// the progress itself is maintained by processors directly.
+	snapshotTables map[model.TableID]struct{}
+
 	pendingPatches        []DataPatch
 	skipPatchesInThisTick bool
 }
@@ -225,6 +252,25 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 		position := new(model.TaskPosition)
 		s.TaskPositions[key.CaptureID] = position
 		e = position
+	case etcd.CDCKeyTypeChangefeedHistory:
+		if key.ChangefeedID != s.ID {
+			return nil
+		}
+		s.hasHistory = value != nil
+		return nil
+	case etcd.CDCKeyTypeTableSnapshot:
+		if key.ChangefeedID != s.ID {
+			return nil
+		}
+		if value == nil {
+			delete(s.snapshotTables, key.TableID)
+			return nil
+		}
+		if s.snapshotTables == nil {
+			s.snapshotTables = make(map[model.TableID]struct{})
+		}
+		s.snapshotTables[key.TableID] = struct{}{}
+		return nil
 	default:
 		return nil
 	}
@@ -241,7 +287,8 @@ func (s *ChangefeedReactorState) UpdateCDCKey(key *etcd.CDCKey, value []byte) er
 
 // Exist returns false if all keys of this changefeed in ETCD is not exist
 func (s *ChangefeedReactorState) Exist() bool {
-	return s.Info != nil || s.Status != nil || len(s.TaskPositions) != 0
+	return s.Info != nil || s.Status != nil || len(s.TaskPositions) != 0 ||
+		s.hasHistory || len(s.snapshotTables) != 0
 }
 
 // Active return true if the changefeed is ready to be processed
@@ -357,7 +404,75 @@ func (s *ChangefeedReactorState) PatchTaskPosition(captureID model.CaptureID, fn
 	})
 }
 
+// RemoveHistory appends a DataPatch which removes the info history of the changefeed.
+func (s *ChangefeedReactorState) RemoveHistory() {
+	key := &etcd.CDCKey{
+		ClusterID:    s.ClusterID,
+		Tp:           etcd.CDCKeyTypeChangefeedHistory,
+		ChangefeedID: s.ID,
+	}
+	s.pendingPatches = append(s.pendingPatches, &SingleDataPatch{
+		Key: util.NewEtcdKey(key.String()),
+		Func: func(v []byte) ([]byte, bool, error) {
+			return nil, v != nil, nil
+		},
+	})
+}
+
+// removeTableSnapshotsBatchSize is the max number of the initial snapshot
+// progress removed in a tick, since the patches of a changefeed in a tick are
+// committed in one etcd txn, which is limited to 128 operations.
+const removeTableSnapshotsBatchSize = 64
+
+// RemoveTableSnapshots appends DataPatches which remove the initial snapshot
+// progress of the tables of the changefeed. At most
+// removeTableSnapshotsBatchSize tables are removed at a time, the caller is
+// expected to call it again in the next tick until Exist returns false.
+func (s *ChangefeedReactorState) RemoveTableSnapshots() {
+	removed := 0
+	for tableID := range s.snapshotTables {
+		if removed == removeTableSnapshotsBatchSize {
+			return
+		}
+		removed++
+		key := &etcd.CDCKey{
+			ClusterID:    s.ClusterID,
+			Tp:           etcd.CDCKeyTypeTableSnapshot,
+			ChangefeedID: s.ID,
+			TableID:      tableID,
+		}
+		s.pendingPatches = append(s.pendingPatches, &SingleDataPatch{
+			Key: util.NewEtcdKey(key.String()),
+			Func: func(v []byte) ([]byte, bool, error) {
+				return nil, v != nil, nil
+			},
+		})
+	}
+}
+
+// PatchTableSnapshot appends a DataPatch which can modify the initial
+// snapshot progress of the specified table.
+func (s *ChangefeedReactorState) PatchTableSnapshot(
+	tableID model.TableID,
+	fn func(*model.TableSnapshotProgress) (*model.TableSnapshotProgress, bool, error),
+) {
+	key := &etcd.CDCKey{
+		ClusterID:    s.ClusterID,
+		Tp:           etcd.CDCKeyTypeTableSnapshot,
+		ChangefeedID: s.ID,
+		TableID:      tableID,
+	}
+	s.patchAny(key.String(), tableSnapshotTPI, func(e interface{}) (interface{}, bool, error) {
+		// e == nil means that the key is not exist before this patch
+		if e == nil {
+			return fn(nil)
+		}
+		return fn(e.(*model.TableSnapshotProgress))
+	})
+}
+
 var (
+	tableSnapshotTPI    *model.TableSnapshotProgress
 	taskPositionTPI     *model.TaskPosition
 	changefeedStatusTPI *model.ChangeFeedStatus
 	changefeedInfoTPI   *model.ChangeFeedInfo
@@ -127,6 +127,10 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Mounter:          &config.MounterConfig{WorkerNum: 16},
 						Sink:             &config.SinkConfig{Protocol: "open-protocol"},
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
+						Retry: &config.RetryConfig{
+							BackoffInitInterval: config.TomlDuration(10 * time.Second),
+							BackoffMaxInterval:  config.TomlDuration(30 * time.Minute),
+						},
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -176,6 +180,10 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Mounter:          &config.MounterConfig{WorkerNum: 16},
 						Sink:             &config.SinkConfig{Protocol: "open-protocol"},
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
+						Retry: &config.RetryConfig{
+							BackoffInitInterval: config.TomlDuration(10 * time.Second),
+							BackoffMaxInterval:  config.TomlDuration(30 * time.Minute),
+						},
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -231,6 +239,10 @@ func TestChangefeedStateUpdate(t *testing.T) {
 						Mounter:          &config.MounterConfig{WorkerNum: 16},
 						Sink:             &config.SinkConfig{Protocol: "open-protocol"},
 						Consistent:       &config.ConsistentConfig{Level: "normal", Storage: "local"},
+						Retry: &config.RetryConfig{
+							BackoffInitInterval: config.TomlDuration(10 * time.Second),
+							BackoffMaxInterval:  config.TomlDuration(30 * time.Minute),
+						},
 					},
 				},
 				Status: &model.ChangeFeedStatus{CheckpointTs: 421980719742451713, ResolvedTs: 421980720003809281},
@@ -320,6 +332,7 @@ func TestPatchInfo(t *testing.T) {
 			Mounter:    defaultConfig.Mounter,
 			Sink:       defaultConfig.Sink,
 			Consistent: defaultConfig.Consistent,
+			Retry:      defaultConfig.Retry,
 		},
 	})
 	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
@@ -336,6 +349,7 @@ func TestPatchInfo(t *testing.T) {
 			Mounter:    defaultConfig.Mounter,
 			Sink:       defaultConfig.Sink,
 			Consistent: defaultConfig.Consistent,
+			Retry:      defaultConfig.Retry,
 		},
 	})
 	state.PatchInfo(func(info *model.ChangeFeedInfo) (*model.ChangeFeedInfo, bool, error) {
@@ -368,6 +382,33 @@ func TestPatchStatus(t *testing.T) {
 	require.Nil(t, state.Status)
 }
 
+func TestPatchTableSnapshot(t *testing.T) {
+	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test1"))
+	stateTester := NewReactorStateTester(t, state, nil)
+	key := etcd.GetEtcdKeyTableSnapshot(etcd.DefaultCDCClusterID,
+		model.DefaultChangeFeedID("test1"), 1)
+	state.PatchTableSnapshot(1, func(
+		progress *model.TableSnapshotProgress,
+	) (*model.TableSnapshotProgress, bool, error) {
+		require.Nil(t, progress)
+		return &model.TableSnapshotProgress{Resync: true, Policy: model.ResyncPolicyTruncate}, true, nil
+	})
+	stateTester.MustApplyPatches()
+	progress := &model.TableSnapshotProgress{}
+	require.Nil(t, progress.Unmarshal([]byte(stateTester.KVEntries()[key])))
+	require.True(t, progress.ResyncPending())
+
+	state.PatchTableSnapshot(1, func(
+		progress *model.TableSnapshotProgress,
+	) (*model.TableSnapshotProgress, bool, error) {
+		require.Equal(t, model.ResyncPolicyTruncate, progress.Policy)
+		return nil, true, nil
+	})
+	stateTester.MustApplyPatches()
+	require.NotContains(t, stateTester.KVEntries(), key)
+}
+
 func TestPatchTaskPosition(t *testing.T) {
 	state := NewChangefeedReactorState(etcd.DefaultCDCClusterID,
 		model.DefaultChangeFeedID("test1"))
@@ -452,6 +493,8 @@ func TestGlobalStateUpdate(t *testing.T) {
 					"/task/position/6bbc01c8-0605-4f86-a0f9-b3119109b225/test2",
 				fmt.Sprintf("%s", etcd.DefaultClusterAndNamespacePrefix) +
 					"/upstream/12345",
+				fmt.Sprintf("%s", etcd.DefaultClusterAndMetaPrefix) +
+					"/namespace/tenant-a",
 			},
 			updateValue: []string{
 				`6bbc01c8-0605-4f86-a0f9-b3119109b225`,
@@ -462,6 +505,7 @@ func TestGlobalStateUpdate(t *testing.T) {
 				`{"resolved-ts":421980720003809281,"checkpoint-ts":421980719742451713,
 "admin-job-type":0}`,
 				`{}`,
+				`{"name":"tenant-a","changefeed-quota":2}`,
 			},
 			expected: GlobalReactorState{
 				ClusterID: etcd.DefaultCDCClusterID,
@@ -473,6 +517,9 @@ func TestGlobalStateUpdate(t *testing.T) {
 				Upstreams: map[model.UpstreamID]*model.UpstreamInfo{
 					model.UpstreamID(12345): {},
 				},
+				Namespaces: map[string]*model.NamespaceInfo{
+					"tenant-a": {Name: "tenant-a", ChangefeedQuota: 2},
+				},
 				Changefeeds: map[model.ChangeFeedID]*ChangefeedReactorState{
 					model.DefaultChangeFeedID("test1"): {
 						ClusterID: etcd.DefaultCDCClusterID,
@@ -526,10 +573,11 @@ func TestGlobalStateUpdate(t *testing.T) {
 				``,
 			},
 			expected: GlobalReactorState{
-				ClusterID: etcd.DefaultCDCClusterID,
-				Owner:     map[string]struct{}{"22317526c4fc9a38": {}},
-				Captures:  map[model.CaptureID]*model.CaptureInfo{},
-				Upstreams: map[model.UpstreamID]*model.UpstreamInfo{},
+				ClusterID:  etcd.DefaultCDCClusterID,
+				Owner:      map[string]struct{}{"22317526c4fc9a38": {}},
+				Captures:   map[model.CaptureID]*model.CaptureInfo{},
+				Upstreams:  map[model.UpstreamID]*model.UpstreamInfo{},
+				Namespaces: map[string]*model.NamespaceInfo{},
 				Changefeeds: map[model.ChangeFeedID]*ChangefeedReactorState{
 					model.DefaultChangeFeedID("test2"): {
 						ClusterID: etcd.DefaultCDCClusterID,
@@ -630,3 +678,82 @@ func TestCheckChangefeedNormal(t *testing.T) {
 	stateTester.MustApplyPatches()
 	require.Equal(t, state.Status.ResolvedTs, uint64(2))
 }
+
+func TestChangefeedHistoryState(t *testing.T) {
+	state := NewGlobalState(etcd.DefaultCDCClusterID)
+	changefeedID := model.DefaultChangeFeedID("test1")
+	key := etcd.GetEtcdKeyChangeFeedHistory(etcd.DefaultCDCClusterID, changefeedID)
+
+	// the changefeed is kept until its history is removed.
+	err := state.Update(util.NewEtcdKey(key), []byte(`{"revisions":[]}`), false)
+	require.Nil(t, err)
+	changefeedState, ok := state.Changefeeds[changefeedID]
+	require.True(t, ok)
+	require.Nil(t, changefeedState.Info)
+
+	changefeedState.RemoveHistory()
+	patches := state.GetPatches()
+	require.Len(t, patches, 1)
+	require.Len(t, patches[0], 1)
+	patch := patches[0][0].(*SingleDataPatch)
+	require.Equal(t, key, patch.Key.String())
+	value, changed, err := patch.Func([]byte(`{"revisions":[]}`))
+	require.Nil(t, err)
+	require.True(t, changed)
+	require.Nil(t, value)
+
+	err = state.Update(util.NewEtcdKey(key), nil, false)
+	require.Nil(t, err)
+	require.NotContains(t, state.Changefeeds, changefeedID)
+}
+
+func TestChangefeedTableSnapshotState(t *testing.T) {
+	state := NewGlobalState(etcd.DefaultCDCClusterID)
+	changefeedID := model.DefaultChangeFeedID("test1")
+	key1 := etcd.GetEtcdKeyTableSnapshot(etcd.DefaultCDCClusterID, changefeedID, 1)
+	key2 := etcd.GetEtcdKeyTableSnapshot(etcd.DefaultCDCClusterID, changefeedID, 2)
+
+	// the changefeed is kept until the snapshot progress of all tables
+	// are removed.
+	for _, key := range []string{key1, key2} {
+		err := state.Update(util.NewEtcdKey(key), []byte(`{"done":true}`), false)
+		require.Nil(t, err)
+	}
+	changefeedState, ok := state.Changefeeds[changefeedID]
+	require.True(t, ok)
+	require.Nil(t, changefeedState.Info)
+
+	changefeedState.RemoveTableSnapshots()
+	patches := state.GetPatches()
+	require.Len(t, patches, 1)
+	require.Len(t, patches[0], 2)
+	keys := make([]string, 0, len(patches[0]))
+	for _, p := range patches[0] {
+		patch := p.(*SingleDataPatch)
+		keys = append(keys, patch.Key.String())
+		value, changed, err := patch.Func([]byte(`{"done":true}`))
+		require.Nil(t, err)
+		require.True(t, changed)
+		require.Nil(t, value)
+	}
+	require.ElementsMatch(t, []string{key1, key2}, keys)
+
+	err := state.Update(util.NewEtcdKey(key1), nil, false)
+	require.Nil(t, err)
+	require.Contains(t, state.Changefeeds, changefeedID)
+	err = state.Update(util.NewEtcdKey(key2), nil, false)
+	require.Nil(t, err)
+	require.NotContains(t, state.Changefeeds, changefeedID)
+
+	// the progress of many tables is removed in batches.
+	for i := 0; i < removeTableSnapshotsBatchSize+1; i++ {
+		key := etcd.GetEtcdKeyTableSnapshot(etcd.DefaultCDCClusterID,
+			changefeedID, model.TableID(i))
+		err := state.Update(util.NewEtcdKey(key), []byte(`{"done":true}`), false)
+		require.Nil(t, err)
+	}
+	changefeedState = state.Changefeeds[changefeedID]
+	changefeedState.RemoveTableSnapshots()
+	patches = changefeedState.GetPatches()
+	require.Len(t, patches[0], removeTableSnapshotsBatchSize)
+}
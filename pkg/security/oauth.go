@@ -0,0 +1,370 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/mattn/go-shellwords"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuthTokenProviderType defines how the OAUTHBEARER tokens are obtained.
+type OAuthTokenProviderType string
+
+// The token providers we currently support.
+const (
+	// UnknownTokenProvider means the token provider is unknown.
+	UnknownTokenProvider OAuthTokenProviderType = ""
+	// StaticTokenProvider uses a fixed token which never expires.
+	StaticTokenProvider OAuthTokenProviderType = "static"
+	// ClientCredentialsTokenProvider requests tokens from a token endpoint
+	// with the OAuth 2.0 client credentials grant.
+	ClientCredentialsTokenProvider OAuthTokenProviderType = "client-credentials"
+	// CommandTokenProvider runs an external command to get tokens.
+	CommandTokenProvider OAuthTokenProviderType = "command"
+	// AWSMSKIAMTokenProvider signs tokens with the AWS credentials,
+	// which are accepted by the IAM access control of Amazon MSK.
+	AWSMSKIAMTokenProvider OAuthTokenProviderType = "aws-msk-iam"
+)
+
+const (
+	// defaultTokenFetchTimeout is the timeout of fetching a token.
+	defaultTokenFetchTimeout = 10 * time.Second
+	// tokenRefreshRatio is the fraction of a token's lifetime after which
+	// the token is refreshed, so it's never used when it's about to expire.
+	tokenRefreshRatio = 0.8
+	// tokenRefreshRetryInterval is the interval of retrying a failed
+	// background refresh.
+	tokenRefreshRetryInterval = 10 * time.Second
+	// mskIAMTokenLifetime is the lifetime of the tokens signed for Amazon MSK.
+	mskIAMTokenLifetime = 15 * time.Minute
+)
+
+// OAuthTokenProviderTypeFromString converts the string to OAuthTokenProviderType.
+func OAuthTokenProviderTypeFromString(s string) (OAuthTokenProviderType, error) {
+	switch strings.ToLower(s) {
+	case "static":
+		return StaticTokenProvider, nil
+	case "client-credentials":
+		return ClientCredentialsTokenProvider, nil
+	case "command":
+		return CommandTokenProvider, nil
+	case "aws-msk-iam":
+		return AWSMSKIAMTokenProvider, nil
+	default:
+		return UnknownTokenProvider, errors.Errorf("unknown %s token provider", s)
+	}
+}
+
+// OAuth holds necessary parameters to support sasl-oauthbearer.
+type OAuth struct {
+	TokenProvider OAuthTokenProviderType `toml:"sasl-oauth-token-provider" json:"sasl-oauth-token-provider"`
+	// Token is used by the static token provider.
+	Token string `toml:"sasl-oauth-token" json:"sasl-oauth-token"`
+	// ClientID, ClientSecret, TokenURL, Scopes and Audience are used by
+	// the client credentials token provider.
+	ClientID     string   `toml:"sasl-oauth-client-id" json:"sasl-oauth-client-id"`
+	ClientSecret string   `toml:"sasl-oauth-client-secret" json:"sasl-oauth-client-secret"`
+	TokenURL     string   `toml:"sasl-oauth-token-url" json:"sasl-oauth-token-url"`
+	Scopes       []string `toml:"sasl-oauth-scopes" json:"sasl-oauth-scopes"`
+	Audience     string   `toml:"sasl-oauth-audience" json:"sasl-oauth-audience"`
+	// Command is used by the command token provider. The command must print
+	// a JSON object with `access_token` and optional `expires_in` in seconds.
+	// The arguments are split as a shell does, so the ones containing spaces
+	// can be quoted, but the environment variables are not expanded.
+	Command string `toml:"sasl-oauth-command" json:"sasl-oauth-command"`
+	// AWSRegion is used by the Amazon MSK IAM token provider, the region
+	// of the default AWS config is used if it's empty.
+	AWSRegion string `toml:"sasl-oauth-aws-region" json:"sasl-oauth-aws-region"`
+}
+
+// Validate checks whether the parameters required by the token provider are set.
+func (o *OAuth) Validate() error {
+	switch o.TokenProvider {
+	case StaticTokenProvider:
+		if o.Token == "" {
+			return errors.New("sasl-oauth-token is required by the static token provider")
+		}
+	case ClientCredentialsTokenProvider:
+		if o.ClientID == "" || o.ClientSecret == "" || o.TokenURL == "" {
+			return errors.New("sasl-oauth-client-id, sasl-oauth-client-secret and " +
+				"sasl-oauth-token-url are required by the client-credentials token provider")
+		}
+		if _, err := url.Parse(o.TokenURL); err != nil {
+			return errors.Trace(err)
+		}
+	case CommandTokenProvider:
+		args, err := shellwords.Parse(o.Command)
+		if err != nil {
+			return errors.Annotate(err, "invalid sasl-oauth-command")
+		}
+		if len(args) == 0 {
+			return errors.New("sasl-oauth-command is required by the command token provider")
+		}
+	case AWSMSKIAMTokenProvider:
+	default:
+		return errors.Errorf("unknown %s token provider", o.TokenProvider)
+	}
+	return nil
+}
+
+// NewOAuthTokenProvider creates a sarama.AccessTokenProvider according to the OAuth config.
+// The tokens are cached and refreshed in the background before they expire
+// until the context is done, so the provider can be shared by all the
+// connections of a sarama client during its whole lifetime.
+//
+// Note that sarama authenticates a connection only once when it's opened and
+// never re-authenticates it, the refreshed tokens are only used by the new
+// connections. The existing connections keep working after the token expires,
+// unless the broker enforces `connections.max.reauth.ms`, in which case the
+// broker closes them and sarama reconnects with the refreshed token.
+func NewOAuthTokenProvider(ctx context.Context, o *OAuth) (sarama.AccessTokenProvider, error) {
+	if err := o.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var fetch tokenFetcher
+	switch o.TokenProvider {
+	case StaticTokenProvider:
+		token := o.Token
+		fetch = func(_ context.Context) (string, time.Time, error) {
+			return token, time.Time{}, nil
+		}
+	case ClientCredentialsTokenProvider:
+		fetch = newClientCredentialsTokenFetcher(o)
+	case CommandTokenProvider:
+		args, err := shellwords.Parse(o.Command)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		fetch = newCommandTokenFetcher(args, time.Now)
+	case AWSMSKIAMTokenProvider:
+		sess, err := session.NewSession(&aws.Config{Region: aws.String(o.AWSRegion)})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		region := aws.StringValue(sess.Config.Region)
+		if region == "" {
+			return nil, errors.New("the region is required by the aws-msk-iam token provider")
+		}
+		fetch = newMSKIAMTokenFetcher(region, sess.Config.Credentials, time.Now)
+	}
+	provider := newRefreshingTokenProvider(fetch, time.Now)
+	go provider.run(ctx)
+	return provider, nil
+}
+
+// tokenFetcher fetches a new token, a zero expiry means the token never expires.
+type tokenFetcher func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// refreshingTokenProvider implements sarama.AccessTokenProvider.
+type refreshingTokenProvider struct {
+	fetch tokenFetcher
+	now   func() time.Time
+	// refreshedCh notifies the background refresh that a new token is fetched.
+	refreshedCh chan struct{}
+
+	mu        sync.Mutex
+	token     string
+	expiry    time.Time
+	refreshAt time.Time
+}
+
+func newRefreshingTokenProvider(fetch tokenFetcher, now func() time.Time) *refreshingTokenProvider {
+	return &refreshingTokenProvider{
+		fetch:       fetch,
+		now:         now,
+		refreshedCh: make(chan struct{}, 1),
+	}
+}
+
+// Token implements the sarama.AccessTokenProvider interface.
+// It's called by sarama every time a connection to a broker is authenticated.
+func (p *refreshingTokenProvider) Token() (*sarama.AccessToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.token != "" && (p.expiry.IsZero() || now.Before(p.refreshAt)) {
+		return &sarama.AccessToken{Token: p.token}, nil
+	}
+
+	// The token is fetched here if the background refresh is not done in time.
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTokenFetchTimeout)
+	defer cancel()
+	token, expiry, err := p.fetch(ctx)
+	if err != nil {
+		// Keep using the current token until it expires,
+		// the refresh is retried on the next call.
+		if p.token != "" && now.Before(p.expiry) {
+			log.Warn("refresh oauth token failed, use the current token",
+				zap.Time("expiry", p.expiry), zap.Error(err))
+			return &sarama.AccessToken{Token: p.token}, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	if err := p.setTokenLocked(now, token, expiry); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &sarama.AccessToken{Token: p.token}, nil
+}
+
+// setTokenLocked saves the fetched token, it must be called with the lock held.
+func (p *refreshingTokenProvider) setTokenLocked(now time.Time, token string, expiry time.Time) error {
+	if token == "" {
+		return errors.New("the oauth token is empty")
+	}
+	p.token = token
+	p.expiry = expiry
+	if !expiry.IsZero() {
+		lifetime := expiry.Sub(now)
+		p.refreshAt = now.Add(time.Duration(float64(lifetime) * tokenRefreshRatio))
+		log.Info("oauth token refreshed", zap.Time("expiry", expiry))
+	}
+	select {
+	case p.refreshedCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run refreshes the token in the background before it's about to expire, so
+// the new connections don't wait for fetching the token, and a temporary
+// failure of the token endpoint is retried before the token expires.
+// The token is first fetched by Token, and it's never refreshed if it never
+// expires. run returns when the context is done.
+func (p *refreshingTokenProvider) run(ctx context.Context) {
+	var refreshCh <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.refreshedCh:
+		case <-refreshCh:
+			if err := p.refresh(ctx); err != nil {
+				log.Warn("refresh oauth token in the background failed, retry later",
+					zap.Duration("retryInterval", tokenRefreshRetryInterval), zap.Error(err))
+				refreshCh = time.After(tokenRefreshRetryInterval)
+				continue
+			}
+		}
+		p.mu.Lock()
+		if p.token != "" && !p.expiry.IsZero() {
+			refreshCh = time.After(p.refreshAt.Sub(p.now()))
+		}
+		p.mu.Unlock()
+	}
+}
+
+// refresh fetches a new token, the lock is not held when fetching,
+// so Token is not blocked by a slow token endpoint.
+func (p *refreshingTokenProvider) refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTokenFetchTimeout)
+	defer cancel()
+	token, expiry, err := p.fetch(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.setTokenLocked(p.now(), token, expiry)
+}
+
+func newClientCredentialsTokenFetcher(o *OAuth) tokenFetcher {
+	cfg := &clientcredentials.Config{
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		TokenURL:     o.TokenURL,
+		Scopes:       o.Scopes,
+	}
+	if o.Audience != "" {
+		cfg.EndpointParams = url.Values{"audience": {o.Audience}}
+	}
+	return func(ctx context.Context) (string, time.Time, error) {
+		token, err := cfg.Token(ctx)
+		if err != nil {
+			return "", time.Time{}, errors.Trace(err)
+		}
+		return token.AccessToken, token.Expiry, nil
+	}
+}
+
+// commandTokenResponse is the output of the token command,
+// it's the same as the response of an OAuth 2.0 token endpoint.
+type commandTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func newCommandTokenFetcher(args []string, now func() time.Time) tokenFetcher {
+	return func(ctx context.Context) (string, time.Time, error) {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", time.Time{}, errors.Annotatef(err, "run token command %s", args[0])
+		}
+		var resp commandTokenResponse
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return "", time.Time{}, errors.Annotatef(err, "parse the output of token command %s", args[0])
+		}
+		var expiry time.Time
+		if resp.ExpiresIn > 0 {
+			expiry = now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+		}
+		return resp.AccessToken, expiry, nil
+	}
+}
+
+// newMSKIAMTokenFetcher creates a token fetcher for the Amazon MSK IAM access control.
+// The token is a presigned `kafka-cluster:Connect` request encoded in base64url.
+func newMSKIAMTokenFetcher(
+	region string, creds *credentials.Credentials, now func() time.Time,
+) tokenFetcher {
+	signer := v4.NewSigner(creds)
+	endpoint := fmt.Sprintf("https://kafka.%s.amazonaws.com/?Action=%s",
+		region, url.QueryEscape("kafka-cluster:Connect"))
+	return func(_ context.Context) (string, time.Time, error) {
+		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		if err != nil {
+			return "", time.Time{}, errors.Trace(err)
+		}
+		signTime := now()
+		if _, err := signer.Presign(req, nil, "kafka-cluster", region,
+			mskIAMTokenLifetime, signTime); err != nil {
+			return "", time.Time{}, errors.Trace(err)
+		}
+		query := req.URL.Query()
+		query.Set("User-Agent", "ticdc")
+		req.URL.RawQuery = query.Encode()
+		token := base64.RawURLEncoding.EncodeToString([]byte(req.URL.String()))
+		return token, signTime.Add(mskIAMTokenLifetime), nil
+	}
+}
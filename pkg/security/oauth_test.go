@@ -0,0 +1,282 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/mattn/go-shellwords"
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// mockClock is a clock which only moves forward when it's told to.
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// mockTokenServer is a local stand-in for an OAuth 2.0 token endpoint.
+type mockTokenServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests int
+	fail     bool
+}
+
+func newMockTokenServer(t *testing.T, expiresIn int) *mockTokenServer {
+	s := &mockTokenServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "kafka", r.PostForm.Get("scope"))
+		require.Equal(t, "cluster", r.PostForm.Get("audience"))
+		id, secret, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "id", id)
+		require.Equal(t, "secret", secret)
+
+		s.requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`,
+			s.requests, expiresIn)
+	}))
+	return s
+}
+
+func (s *mockTokenServer) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func TestOAuthTokenProviderTypeFromString(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"static", "client-credentials", "Command", "AWS-MSK-IAM"} {
+		tp, err := OAuthTokenProviderTypeFromString(s)
+		require.Nil(t, err)
+		require.Equal(t, OAuthTokenProviderType(strings.ToLower(s)), tp)
+	}
+	_, err := OAuthTokenProviderTypeFromString("random")
+	require.Regexp(t, "unknown random token provider", err)
+}
+
+func TestOAuthValidate(t *testing.T) {
+	t.Parallel()
+
+	require.Regexp(t, "unknown  token provider", (&OAuth{}).Validate())
+	require.Regexp(t, "sasl-oauth-token is required",
+		(&OAuth{TokenProvider: StaticTokenProvider}).Validate())
+	require.Regexp(t, "sasl-oauth-token-url are required", (&OAuth{
+		TokenProvider: ClientCredentialsTokenProvider, ClientID: "id", ClientSecret: "secret",
+	}).Validate())
+	require.Regexp(t, "sasl-oauth-command is required",
+		(&OAuth{TokenProvider: CommandTokenProvider, Command: " "}).Validate())
+	require.Regexp(t, "invalid sasl-oauth-command",
+		(&OAuth{TokenProvider: CommandTokenProvider, Command: `get-token "a b`}).Validate())
+	require.Nil(t, (&OAuth{TokenProvider: AWSMSKIAMTokenProvider}).Validate())
+}
+
+func TestStaticTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	provider, err := NewOAuthTokenProvider(ctx, &OAuth{TokenProvider: StaticTokenProvider, Token: "t"})
+	require.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token()
+		require.Nil(t, err)
+		require.Equal(t, "t", token.Token)
+	}
+}
+
+func TestClientCredentialsTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	server := newMockTokenServer(t, 3600)
+	defer server.Close()
+	start := time.Now()
+	clock := &mockClock{now: start}
+	provider := newRefreshingTokenProvider(newClientCredentialsTokenFetcher(&OAuth{
+		TokenProvider: ClientCredentialsTokenProvider,
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		TokenURL:      server.URL,
+		Scopes:        []string{"kafka"},
+		Audience:      "cluster",
+	}), clock.Now)
+
+	token, err := provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token.Token)
+
+	// The token is reused before it's about to expire.
+	clock.Set(start.Add(1000 * time.Second))
+	token, err = provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token.Token)
+
+	clock.Set(start.Add(2900 * time.Second))
+	token, err = provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-2", token.Token)
+
+	// The current token is used if the refresh fails but it's still valid.
+	server.setFail(true)
+	clock.Set(start.Add(3500 * time.Second))
+	token, err = provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-2", token.Token)
+
+	clock.Set(start.Add(3700 * time.Second))
+	_, err = provider.Token()
+	require.NotNil(t, err)
+
+	server.setFail(false)
+	token, err = provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-3", token.Token)
+}
+
+func TestCommandTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	clock := &mockClock{now: start}
+	// The quoted argument is passed to the command as a whole.
+	args, err := shellwords.Parse(`sh -c 'echo "{\"access_token\":\"t1\",\"expires_in\":100}"'`)
+	require.Nil(t, err)
+	require.Len(t, args, 3)
+	provider := newRefreshingTokenProvider(newCommandTokenFetcher(args, clock.Now), clock.Now)
+	token, err := provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "t1", token.Token)
+	require.Equal(t, start.Add(100*time.Second), provider.expiry)
+	require.Equal(t, start.Add(80*time.Second), provider.refreshAt)
+
+	provider = newRefreshingTokenProvider(
+		newCommandTokenFetcher([]string{"echo", "invalid"}, clock.Now), clock.Now)
+	_, err = provider.Token()
+	require.Regexp(t, "parse the output of token command echo", err)
+}
+
+func TestTokenProviderRefreshInBackground(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+		fail     bool
+	)
+	fetch := func(_ context.Context) (string, time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return "", time.Time{}, errors.New("token endpoint unavailable")
+		}
+		requests++
+		return fmt.Sprintf("token-%d", requests), time.Now().Add(time.Second), nil
+	}
+	getRequests := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+	provider := newRefreshingTokenProvider(fetch, time.Now)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		provider.run(ctx)
+		close(done)
+	}()
+
+	token, err := provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token.Token)
+
+	// The token is refreshed before it expires without calling Token.
+	require.Eventually(t, func() bool {
+		return getRequests() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	token, err = provider.Token()
+	require.Nil(t, err)
+	require.NotEqual(t, "token-1", token.Token)
+
+	// The refresh stops after the context is done.
+	cancel()
+	<-done
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	last := getRequests()
+	time.Sleep(1500 * time.Millisecond)
+	require.Equal(t, last, getRequests())
+}
+
+func TestMSKIAMTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	signTime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := &mockClock{now: signTime}
+	creds := credentials.NewStaticCredentials("access-key", "secret-key", "")
+	provider := newRefreshingTokenProvider(
+		newMSKIAMTokenFetcher("us-west-2", creds, clock.Now), clock.Now)
+	token, err := provider.Token()
+	require.Nil(t, err)
+	require.Equal(t, signTime.Add(15*time.Minute), provider.expiry)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token.Token)
+	require.Nil(t, err)
+	u, err := url.Parse(string(decoded))
+	require.Nil(t, err)
+	require.Equal(t, "kafka.us-west-2.amazonaws.com", u.Host)
+	query := u.Query()
+	require.Equal(t, "kafka-cluster:Connect", query.Get("Action"))
+	require.Equal(t, "900", query.Get("X-Amz-Expires"))
+	require.Equal(t, "20220601T000000Z", query.Get("X-Amz-Date"))
+	require.Regexp(t, "^access-key/20220601/us-west-2/kafka-cluster/aws4_request$",
+		query.Get("X-Amz-Credential"))
+	require.NotEmpty(t, query.Get("X-Amz-Signature"))
+	require.Equal(t, "ticdc", query.Get("User-Agent"))
+}
@@ -35,6 +35,8 @@ const (
 	SCRAM512Mechanism SASLMechanism = sarama.SASLTypeSCRAMSHA512
 	// GSSAPIMechanism means the SASL mechanism is GSSAPI.
 	GSSAPIMechanism SASLMechanism = sarama.SASLTypeGSSAPI
+	// OAuthMechanism means the SASL mechanism is OAUTHBEARER.
+	OAuthMechanism SASLMechanism = sarama.SASLTypeOAuth
 )
 
 // SASLMechanismFromString converts the string to SASL mechanism.
@@ -48,6 +50,8 @@ func SASLMechanismFromString(s string) (SASLMechanism, error) {
 		return SCRAM512Mechanism, nil
 	case "gssapi":
 		return GSSAPIMechanism, nil
+	case "oauthbearer":
+		return OAuthMechanism, nil
 	default:
 		return UnknownMechanism, errors.Errorf("unknown %s SASL mechanism", s)
 	}
@@ -59,6 +63,7 @@ type SASL struct {
 	SASLPassword  string        `toml:"sasl-password" json:"sasl-password"`
 	SASLMechanism SASLMechanism `toml:"sasl-mechanism" json:"sasl-mechanism"`
 	GSSAPI        GSSAPI        `toml:"sasl-gssapi" json:"sasl-gssapi"`
+	OAuth         OAuth         `toml:"sasl-oauth" json:"sasl-oauth"`
 }
 
 // GSSAPIAuthType defines the type of GSSAPI authentication.
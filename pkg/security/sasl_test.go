@@ -74,8 +74,19 @@ func TestSASLMechanismFromString(t *testing.T) {
 			s:                 "GSSAPI",
 			expectedMechanism: "GSSAPI",
 		},
+		{
+			name:              "lower case oauthbearer mechanism",
+			s:                 "oauthbearer",
+			expectedMechanism: "OAUTHBEARER",
+		},
+		{
+			name:              "upper case OAUTHBEARER mechanism",
+			s:                 "OAUTHBEARER",
+			expectedMechanism: "OAUTHBEARER",
+		},
 	}
 	for _, test := range tests {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 			mechanism, err := SASLMechanismFromString(test.s)
@@ -128,6 +139,7 @@ func TestAuthTypeFromString(t *testing.T) {
 	}
 
 	for _, test := range tests {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
@@ -0,0 +1,89 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"strconv"
+	"strings"
+
+	cdcmodel "github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
+)
+
+// Dialect is the SQL dialect of the generated DMLs.
+type Dialect int
+
+// these constants represent the supported dialects.
+const (
+	// DialectMySQL generates DMLs for MySQL and TiDB, it's the default.
+	DialectMySQL Dialect = iota
+	// DialectPostgreSQL generates DMLs for PostgreSQL, the database of the
+	// table is the PostgreSQL schema. The placeholders are $1, $2 and so on,
+	// REPLACE and INSERT ON DUPLICATE KEY UPDATE are generated as INSERT ON
+	// CONFLICT of the columns identifying the row, and UPDATE and DELETE have
+	// no LIMIT clause.
+	DialectPostgreSQL
+)
+
+// String implements fmt.Stringer interface.
+func (d Dialect) String() string {
+	switch d {
+	case DialectMySQL:
+		return "MySQL"
+	case DialectPostgreSQL:
+		return "PostgreSQL"
+	}
+
+	return ""
+}
+
+func (d Dialect) quoteName(name string) string {
+	if d == DialectPostgreSQL {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return quotes.QuoteName(name)
+}
+
+func (d Dialect) quoteTable(table *cdcmodel.TableName) string {
+	if d == DialectPostgreSQL {
+		return d.quoteName(table.Schema) + "." + d.quoteName(table.Table)
+	}
+	return table.QuoteString()
+}
+
+// placeholder returns the placeholder of the n-th argument, n starts from 1.
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgreSQL {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// valuesHolder gens values holder like (?,?,?) or ($1,$2,$3), the first
+// placeholder is the start-th argument.
+func (d Dialect) valuesHolder(start, n int) string {
+	if d != DialectPostgreSQL {
+		return valuesHolder(n)
+	}
+	var builder strings.Builder
+	builder.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(d.placeholder(start + i))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
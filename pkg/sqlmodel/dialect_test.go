@@ -0,0 +1,75 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cdcmodel "github.com/pingcap/tiflow/cdc/model"
+)
+
+func TestPostgreSQLDialect(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb"}
+	sourceTI := mockTableInfo(t, `CREATE TABLE tb (gen INT AS (c+1), c INT PRIMARY KEY, c2 INT, c3 VARCHAR(10))`)
+	newChange := func(pre, post []interface{}) *RowChange {
+		change := NewRowChange(source, nil, pre, post, sourceTI, nil, nil)
+		change.SetDialect(DialectPostgreSQL)
+		return change
+	}
+
+	change := newChange([]interface{}{2, 1, 2, "a"}, []interface{}{3, 2, 3, `"b"`})
+	sql, args := change.GenSQL(DMLUpdate)
+	require.Equal(t, `UPDATE "db"."tb" SET "c" = $1, "c2" = $2, "c3" = $3 WHERE "c" = $4`, sql)
+	require.Equal(t, []interface{}{2, 3, `"b"`, 1}, args)
+
+	sql, args = change.GenSQL(DMLDelete)
+	require.Equal(t, `DELETE FROM "db"."tb" WHERE "c" = $1`, sql)
+	require.Equal(t, []interface{}{1}, args)
+
+	change2 := newChange(nil, []interface{}{4, 3, nil, "c"})
+	sql, args = GenInsertSQL(DMLInsert, change, change2)
+	require.Equal(t, `INSERT INTO "db"."tb" ("c","c2","c3") VALUES ($1,$2,$3),($4,$5,$6)`, sql)
+	require.Equal(t, []interface{}{2, 3, `"b"`, 3, nil, "c"}, args)
+
+	// REPLACE and INSERT ON DUPLICATE KEY UPDATE overwrite the conflicting
+	// rows by the primary key.
+	for _, tp := range []DMLType{DMLReplace, DMLInsertOnDuplicateUpdate} {
+		sql, args = change2.GenSQL(tp)
+		require.Equal(t, `INSERT INTO "db"."tb" ("c","c2","c3") VALUES ($1,$2,$3) `+
+			`ON CONFLICT ("c") DO UPDATE SET "c2" = EXCLUDED."c2","c3" = EXCLUDED."c3"`, sql)
+		require.Equal(t, []interface{}{3, nil, "c"}, args)
+	}
+
+	sql, args = GenDeleteSQL(newChange([]interface{}{2, 1, 2, "a"}, nil),
+		newChange([]interface{}{4, 3, 4, "c"}, nil))
+	require.Equal(t, `DELETE FROM "db"."tb" WHERE ("c") IN (($1),($2))`, sql)
+	require.Equal(t, []interface{}{1, 3}, args)
+
+	// The NULL values are compared by IS NULL without arguments, and the
+	// conflicting rows are skipped if all the columns are keys.
+	sourceTI = mockTableInfo(t, "CREATE TABLE tb (c INT, `c\"2` INT)")
+	change = newChange([]interface{}{nil, 1}, nil)
+	sql, args = change.GenSQL(DMLDelete)
+	require.Equal(t, `DELETE FROM "db"."tb" WHERE "c" IS NULL AND "c""2" = $1`, sql)
+	require.Equal(t, []interface{}{1}, args)
+
+	sourceTI = mockTableInfo(t, `CREATE TABLE tb (c INT PRIMARY KEY)`)
+	sql, args = newChange(nil, []interface{}{1}).GenSQL(DMLReplace)
+	require.Equal(t, `INSERT INTO "db"."tb" ("c") VALUES ($1) ON CONFLICT ("c") DO NOTHING`, sql)
+	require.Equal(t, []interface{}{1}, args)
+}
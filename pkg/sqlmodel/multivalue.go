@@ -19,7 +19,6 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/pingcap/tiflow/dm/pkg/log"
-	"github.com/pingcap/tiflow/pkg/quotes"
 )
 
 // SameTypeTargetAndColumns check whether two row changes have same type, target
@@ -77,30 +76,30 @@ func GenDeleteSQL(changes ...*RowChange) (string, []interface{}) {
 	}
 
 	first := changes[0]
+	dialect := first.dialect
 
 	var buf strings.Builder
 	buf.Grow(1024)
 	buf.WriteString("DELETE FROM ")
-	buf.WriteString(first.targetTable.QuoteString())
+	buf.WriteString(dialect.quoteTable(first.targetTable))
 	buf.WriteString(" WHERE (")
 
 	whereColumns, _ := first.whereColumnsAndValues()
 	for i, column := range whereColumns {
 		if i != len(whereColumns)-1 {
-			buf.WriteString(quotes.QuoteName(column) + ",")
+			buf.WriteString(dialect.quoteName(column) + ",")
 		} else {
-			buf.WriteString(quotes.QuoteName(column) + ")")
+			buf.WriteString(dialect.quoteName(column) + ")")
 		}
 	}
 	buf.WriteString(" IN (")
 	// TODO: can't handle NULL by IS NULL, should use WHERE OR
 	args := make([]interface{}, 0, len(changes)*len(whereColumns))
-	holder := valuesHolder(len(whereColumns))
 	for i, change := range changes {
 		if i > 0 {
 			buf.WriteString(",")
 		}
-		buf.WriteString(holder)
+		buf.WriteString(dialect.valuesHolder(len(args)+1, len(whereColumns)))
 		_, whereValues := change.whereColumnsAndValues()
 		// a simple check about different number of WHERE values, not trying to
 		// cover all cases
@@ -118,6 +117,53 @@ func GenDeleteSQL(changes ...*RowChange) (string, []interface{}) {
 	return buf.String(), args
 }
 
+// genOnConflict generates the ON CONFLICT clause of PostgreSQL, which
+// overwrites the conflicting row like REPLACE and INSERT ON DUPLICATE KEY
+// UPDATE of MySQL. The conflict target is the index identifying the row, the
+// clause is omitted if there is no such index.
+func (r *RowChange) genOnConflict(buf *strings.Builder, skipColIdx []int) {
+	uniqueIndex := r.UniqueNotNullIdx()
+	if uniqueIndex == nil {
+		return
+	}
+
+	isKey := make(map[int]struct{}, len(uniqueIndex.Columns))
+	buf.WriteString(" ON CONFLICT (")
+	for i, col := range uniqueIndex.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		isKey[col.Offset] = struct{}{}
+		buf.WriteString(r.dialect.quoteName(col.Name.O))
+	}
+	buf.WriteString(") DO ")
+
+	i := 0 // used as index of skipColIdx
+	writtenFirstCol := false
+	for j, col := range r.sourceTableInfo.Columns {
+		if i < len(skipColIdx) && skipColIdx[i] == j {
+			i++
+			continue
+		}
+		if _, ok := isKey[j]; ok {
+			continue
+		}
+
+		if writtenFirstCol {
+			buf.WriteByte(',')
+		} else {
+			buf.WriteString("UPDATE SET ")
+		}
+		writtenFirstCol = true
+
+		colName := r.dialect.quoteName(col.Name.O)
+		buf.WriteString(colName + " = EXCLUDED." + colName)
+	}
+	if !writtenFirstCol {
+		buf.WriteString("NOTHING")
+	}
+}
+
 // TODO: support GenUpdateSQL(changes ...*RowChange) using UPDATE SET CASE WHEN
 
 // GenInsertSQL generates the INSERT SQL and its arguments.
@@ -130,15 +176,16 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 	}
 
 	first := changes[0]
+	dialect := first.dialect
 
 	var buf strings.Builder
 	buf.Grow(1024)
-	if tp == DMLReplace {
+	if tp == DMLReplace && dialect == DialectMySQL {
 		buf.WriteString("REPLACE INTO ")
 	} else {
 		buf.WriteString("INSERT INTO ")
 	}
-	buf.WriteString(first.targetTable.QuoteString())
+	buf.WriteString(dialect.quoteTable(first.targetTable))
 	buf.WriteString(" (")
 	columnNum := 0
 	var skipColIdx []int
@@ -152,17 +199,18 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 			buf.WriteByte(',')
 		}
 		columnNum++
-		buf.WriteString(quotes.QuoteName(col.Name.O))
+		buf.WriteString(dialect.quoteName(col.Name.O))
 	}
 	buf.WriteString(") VALUES ")
-	holder := valuesHolder(columnNum)
 	for i := range changes {
 		if i > 0 {
 			buf.WriteString(",")
 		}
-		buf.WriteString(holder)
+		buf.WriteString(dialect.valuesHolder(i*columnNum+1, columnNum))
 	}
-	if tp == DMLInsertOnDuplicateUpdate {
+	if dialect == DialectPostgreSQL && tp != DMLInsert {
+		first.genOnConflict(&buf, skipColIdx)
+	} else if tp == DMLInsertOnDuplicateUpdate {
 		buf.WriteString(" ON DUPLICATE KEY UPDATE ")
 		i := 0 // used as index of skipColIdx
 		writtenFirstCol := false
@@ -178,7 +226,7 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 			}
 			writtenFirstCol = true
 
-			colName := quotes.QuoteName(col.Name.O)
+			colName := dialect.quoteName(col.Name.O)
 			buf.WriteString(colName + "=VALUES(" + colName + ")")
 		}
 	}
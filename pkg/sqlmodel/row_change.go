@@ -25,7 +25,6 @@ import (
 	cdcmodel "github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
-	"github.com/pingcap/tiflow/pkg/quotes"
 )
 
 // RowChangeType is the type of row change.
@@ -70,6 +69,7 @@ type RowChange struct {
 
 	tp          RowChangeType
 	whereHandle *WhereHandle
+	dialect     Dialect
 }
 
 // NewRowChange creates a new RowChange.
@@ -196,6 +196,11 @@ func (r *RowChange) SetWhereHandle(whereHandle *WhereHandle) {
 	r.whereHandle = whereHandle
 }
 
+// SetDialect sets the SQL dialect of the generated DMLs, the default is MySQL.
+func (r *RowChange) SetDialect(dialect Dialect) {
+	r.dialect = dialect
+}
+
 func (r *RowChange) lazyInitWhereHandle() {
 	if r.whereHandle != nil {
 		return
@@ -235,22 +240,31 @@ func (r *RowChange) whereColumnsAndValues() ([]string, []interface{}) {
 }
 
 // genWhere generates WHERE clause for UPDATE and DELETE to identify the row.
-// the SQL part is written to `buf` and the args part is returned.
-func (r *RowChange) genWhere(buf *strings.Builder) []interface{} {
+// the SQL part is written to `buf` and the args part is returned. argStart is
+// the index of the first argument of the WHERE clause, starting from 1.
+func (r *RowChange) genWhere(buf *strings.Builder, argStart int) []interface{} {
 	whereColumns, whereValues := r.whereColumnsAndValues()
 
+	args := make([]interface{}, 0, len(whereValues))
 	for i, col := range whereColumns {
 		if i != 0 {
 			buf.WriteString(" AND ")
 		}
-		buf.WriteString(quotes.QuoteName(col))
-		if whereValues[i] == nil {
-			buf.WriteString(" IS ?")
-		} else {
-			buf.WriteString(" = ?")
+		buf.WriteString(r.dialect.quoteName(col))
+		switch {
+		case whereValues[i] == nil && r.dialect == DialectPostgreSQL:
+			// PostgreSQL doesn't accept a parameter after IS.
+			buf.WriteString(" IS NULL")
+			continue
+		case whereValues[i] == nil:
+			buf.WriteString(" IS ")
+		default:
+			buf.WriteString(" = ")
 		}
+		buf.WriteString(r.dialect.placeholder(argStart + len(args)))
+		args = append(args, whereValues[i])
 	}
-	return whereValues
+	return args
 }
 
 func (r *RowChange) genDeleteSQL() (string, []interface{}) {
@@ -264,10 +278,12 @@ func (r *RowChange) genDeleteSQL() (string, []interface{}) {
 	var buf strings.Builder
 	buf.Grow(1024)
 	buf.WriteString("DELETE FROM ")
-	buf.WriteString(r.targetTable.QuoteString())
+	buf.WriteString(r.dialect.quoteTable(r.targetTable))
 	buf.WriteString(" WHERE ")
-	whereArgs := r.genWhere(&buf)
-	buf.WriteString(" LIMIT 1")
+	whereArgs := r.genWhere(&buf, 1)
+	if r.dialect == DialectMySQL {
+		buf.WriteString(" LIMIT 1")
+	}
 
 	return buf.String(), whereArgs
 }
@@ -283,7 +299,7 @@ func (r *RowChange) genUpdateSQL() (string, []interface{}) {
 	var buf strings.Builder
 	buf.Grow(2048)
 	buf.WriteString("UPDATE ")
-	buf.WriteString(r.targetTable.QuoteString())
+	buf.WriteString(r.dialect.quoteTable(r.targetTable))
 	buf.WriteString(" SET ")
 
 	args := make([]interface{}, 0, len(r.preValues)+len(r.postValues))
@@ -297,13 +313,16 @@ func (r *RowChange) genUpdateSQL() (string, []interface{}) {
 			buf.WriteString(", ")
 		}
 		writtenFirstCol = true
-		fmt.Fprintf(&buf, "%s = ?", quotes.QuoteName(col.Name.O))
 		args = append(args, r.postValues[i])
+		fmt.Fprintf(&buf, "%s = %s",
+			r.dialect.quoteName(col.Name.O), r.dialect.placeholder(len(args)))
 	}
 
 	buf.WriteString(" WHERE ")
-	whereArgs := r.genWhere(&buf)
-	buf.WriteString(" LIMIT 1")
+	whereArgs := r.genWhere(&buf, len(args)+1)
+	if r.dialect == DialectMySQL {
+		buf.WriteString(" LIMIT 1")
+	}
 
 	args = append(args, whereArgs...)
 	return buf.String(), args
@@ -33,6 +33,8 @@ const (
 	EnsureGCServiceResuming = "-resuming-"
 	// EnsureGCServiceInitializing is a tag of GC service id for changefeed initialization
 	EnsureGCServiceInitializing = "-initializing-"
+	// EnsureGCServiceDryRun is a tag of GC service id for changefeed dry run
+	EnsureGCServiceDryRun = "-dry-run-"
 )
 
 // EnsureChangefeedStartTsSafety checks if the startTs less than the minimum of
@@ -56,6 +58,30 @@ func EnsureChangefeedStartTsSafety(
 	return nil
 }
 
+// CheckChangefeedStartTsSafety checks if the startTs less than the minimum of
+// service GC safepoint. Unlike EnsureChangefeedStartTsSafety, it does not hold
+// a service GC safepoint for the changefeed.
+func CheckChangefeedStartTsSafety(
+	ctx context.Context, pdCli pd.Client,
+	gcServiceIDPrefix string,
+	changefeedID model.ChangeFeedID,
+	startTs uint64,
+) error {
+	// A zero TTL makes PD remove the service GC safepoint instead of
+	// updating it, and the minimum service GC safepoint is returned.
+	minServiceGCTs, err := SetServiceGCSafepoint(
+		ctx, pdCli,
+		gcServiceIDPrefix+changefeedID.Namespace+"_"+changefeedID.ID,
+		0, startTs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if startTs < minServiceGCTs {
+		return cerrors.ErrStartTsBeforeGC.GenWithStackByArgs(startTs, minServiceGCTs)
+	}
+	return nil
+}
+
 // UndoEnsureChangefeedStartTsSafety cleans the service GC safepoint of a changefeed
 // if something goes wrong after successfully calling EnsureChangefeedStartTsSafety().
 func UndoEnsureChangefeedStartTsSafety(
@@ -91,6 +91,25 @@ func TestCheckSafetyOfStartTs(t *testing.T) {
 			"because start-ts 50 is earlier than GC safepoint at 60")
 }
 
+func TestCheckChangefeedStartTsSafety(t *testing.T) {
+	t.Parallel()
+
+	pdCli := &mockPdClientForServiceGCSafePoint{serviceSafePoint: make(map[string]uint64)}
+	ctx := context.Background()
+
+	pdCli.UpdateServiceGCSafePoint(ctx, "service1", 10, 60) //nolint:errcheck
+	err := CheckChangefeedStartTsSafety(ctx, pdCli,
+		"ticdc-dry-run-",
+		model.DefaultChangeFeedID("changefeed1"), 50)
+	require.Equal(t,
+		"[CDC:ErrStartTsBeforeGC]fail to create or maintain changefeed "+
+			"because start-ts 50 is earlier than GC safepoint at 60", err.Error())
+	err = CheckChangefeedStartTsSafety(ctx, pdCli,
+		"ticdc-dry-run-",
+		model.DefaultChangeFeedID("changefeed1"), 60)
+	require.Nil(t, err)
+}
+
 type mockPdClientForServiceGCSafePoint struct {
 	pd.Client
 	serviceSafePoint   map[string]uint64
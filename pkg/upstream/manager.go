@@ -15,6 +15,7 @@ package upstream
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -127,6 +128,8 @@ func (m *Manager) add(upstreamID uint64,
 		}
 	}
 	up := newUpstream(pdEndpoints, securityConf)
+	// The cluster ID reported by PD is checked against it during init.
+	up.ID = upstreamID
 	m.ups.Store(upstreamID, up)
 	go func() {
 		err := m.initUpstreamFunc(m.ctx, up, m.gcServiceID)
@@ -161,6 +164,20 @@ func (m *Manager) Get(upstreamID uint64) (*Upstream, bool) {
 	return up, true
 }
 
+// Statuses returns the health of all upstreams managed by the Manager,
+// sorted by upstream ID.
+func (m *Manager) Statuses() []model.UpstreamStatus {
+	var statuses []model.UpstreamStatus
+	m.ups.Range(func(_, v interface{}) bool {
+		statuses = append(statuses, v.(*Upstream).Status())
+		return true
+	})
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ID < statuses[j].ID
+	})
+	return statuses
+}
+
 // Close closes all upstreams.
 // Please make sure it will only be called once when capture exits.
 func (m *Manager) Close() {
@@ -180,3 +180,32 @@ func TestRemoveThenAddAgain(t *testing.T) {
 	_ = m.AddUpstream(uint64(3), &model.UpstreamInfo{})
 	require.True(t, up.idleTime.IsZero())
 }
+
+func TestManagerStatuses(t *testing.T) {
+	pdClient := &gc.MockPDClient{}
+	m := NewManager4Test(pdClient)
+	m.ctx = context.Background()
+	m.initUpstreamFunc = func(ctx context.Context,
+		up *Upstream, gcID string,
+	) error {
+		return errors.New("test")
+	}
+	up := m.AddUpstream(uint64(3), &model.UpstreamInfo{
+		PDEndpoints: "http://127.0.0.1:2379,http://127.0.0.2:2379",
+	})
+	require.Equal(t, uint64(3), up.ID)
+	for up.Error() == nil {
+	}
+
+	statuses := m.Statuses()
+	require.Len(t, statuses, 2)
+	require.Equal(t, testUpstreamID, statuses[0].ID)
+	require.True(t, statuses[0].IsDefault)
+	require.Equal(t, model.UpstreamStateNormal, statuses[0].State)
+	require.Equal(t, uint64(3), statuses[1].ID)
+	require.False(t, statuses[1].IsDefault)
+	require.Equal(t, []string{"http://127.0.0.1:2379", "http://127.0.0.2:2379"},
+		statuses[1].PDEndpoints)
+	require.Equal(t, model.UpstreamStateError, statuses[1].State)
+	require.Equal(t, "test", statuses[1].Error)
+}
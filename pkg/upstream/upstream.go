@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tiflow/cdc/kv"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/etcd"
 	"github.com/pingcap/tiflow/pkg/pdutil"
@@ -255,11 +256,43 @@ func (up *Upstream) IsNormal() bool {
 	return atomic.LoadInt32(&up.status) == normal && up.err.Load() == nil
 }
 
+// IsDefault returns true if the upstream is the one the server started with.
+func (up *Upstream) IsDefault() bool {
+	return up.isDefaultUpstream
+}
+
 // IsClosed returns true if the upstream is closed.
 func (up *Upstream) IsClosed() bool {
 	return atomic.LoadInt32(&up.status) == closed
 }
 
+// Status returns the health of the upstream.
+func (up *Upstream) Status() model.UpstreamStatus {
+	status := model.UpstreamStatus{
+		ID:          up.ID,
+		PDEndpoints: up.PdEndpoints,
+		IsDefault:   up.IsDefault(),
+	}
+	switch atomic.LoadInt32(&up.status) {
+	case uninit:
+		status.State = model.UpstreamStateInitializing
+	case normal:
+		status.State = model.UpstreamStateNormal
+	case closing:
+		status.State = model.UpstreamStateClosing
+	case closed:
+		status.State = model.UpstreamStateClosed
+	}
+	if err := up.Error(); err != nil {
+		if status.State == model.UpstreamStateInitializing ||
+			status.State == model.UpstreamStateNormal {
+			status.State = model.UpstreamStateError
+		}
+		status.Error = err.Error()
+	}
+	return status
+}
+
 // resetIdleTime set the upstream idle time to true
 func (up *Upstream) resetIdleTime() {
 	up.mu.Lock()
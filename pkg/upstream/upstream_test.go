@@ -18,6 +18,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -66,3 +67,25 @@ func TestTrySetIdleTime(t *testing.T) {
 	up.resetIdleTime()
 	require.True(t, up.idleTime.IsZero())
 }
+
+func TestUpstreamStatus(t *testing.T) {
+	up := newUpstream([]string{"http://127.0.0.1:2379"}, nil)
+	up.ID = 1
+	status := up.Status()
+	require.Equal(t, model.UpstreamStatus{
+		ID:          1,
+		PDEndpoints: []string{"http://127.0.0.1:2379"},
+		State:       model.UpstreamStateInitializing,
+	}, status)
+
+	up.status = normal
+	require.Equal(t, model.UpstreamStateNormal, up.Status().State)
+
+	up.err.Store(errors.New("pd unavailable"))
+	status = up.Status()
+	require.Equal(t, model.UpstreamStateError, status.State)
+	require.Equal(t, "pd unavailable", status.Error)
+
+	up.status = closed
+	require.Equal(t, model.UpstreamStateClosed, up.Status().State)
+}
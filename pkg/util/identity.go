@@ -27,6 +27,8 @@ const (
 	RoleRedoLogApplier
 	// RoleKafkaConsumer is the kafka consumer.
 	RoleKafkaConsumer
+	// RolePulsarConsumer is the pulsar consumer.
+	RolePulsarConsumer
 	// RoleTester for test.
 	RoleTester
 	// RoleUnknown is the unknown role.
@@ -43,6 +45,8 @@ func (r Role) String() string {
 		return "cdc-client"
 	case RoleKafkaConsumer:
 		return "kafka-consumer"
+	case RolePulsarConsumer:
+		return "pulsar-consumer"
 	case RoleRedoLogApplier:
 		return "redo-applier"
 	case RoleTester: